@@ -35,6 +35,7 @@ type BeaconStateUpgradable interface {
 	UpgradeToBellatrix() error
 	UpgradeToCapella() error
 	UpgradeToDeneb() error
+	UpgradeToElectra() error
 }
 
 type BeaconStateExtension interface {
@@ -122,6 +123,13 @@ type BeaconStateMutator interface {
 	SetCurrentEpochParticipationFlags(flags []cltypes.ParticipationFlags)
 	SetPreviousEpochParticipationFlags(flags []cltypes.ParticipationFlags)
 	SetPreviousEpochAttestations(attestations *solid.ListSSZ[*solid.PendingAttestation]) // temporarily skip this mock
+	SetDepositRequestsStartIndex(index uint64)
+	SetDepositBalanceToConsume(balance uint64)
+	SetExitBalanceToConsume(balance uint64)
+	SetEarliestExitEpoch(epoch uint64)
+	SetConsolidationBalanceToConsume(balance uint64)
+	SetEarliestConsolidationEpoch(epoch uint64)
+	SetPendingBalanceDeposits(deposits *solid.ListSSZ[*cltypes.PendingBalanceDeposit])
 
 	AddEth1DataVote(vote *cltypes.Eth1Data)
 	AddValidator(validator solid.Validator, balance uint64)
@@ -133,6 +141,9 @@ type BeaconStateMutator interface {
 	AddPreviousEpochParticipationAt(index int, delta byte)
 	AddCurrentEpochAtteastation(attestation *solid.PendingAttestation)
 	AddPreviousEpochAttestation(attestation *solid.PendingAttestation)
+	AppendPendingBalanceDeposit(deposit *cltypes.PendingBalanceDeposit)
+	AppendPendingPartialWithdrawal(withdrawal *cltypes.PendingPartialWithdrawal)
+	AppendPendingConsolidation(consolidation *cltypes.PendingConsolidation)
 
 	AppendValidator(in solid.Validator)
 
@@ -141,6 +152,9 @@ type BeaconStateMutator interface {
 	ResetHistoricalSummaries()
 	ResetCurrentEpochAttestations()
 	ResetPreviousEpochAttestations()
+
+	CutPendingPartialWithdrawals(n int)
+	CutPendingConsolidations(n int)
 }
 
 type BeaconStateExtra interface {
@@ -202,6 +216,15 @@ type BeaconStateMinimal interface {
 	NextWithdrawalIndex() uint64
 	NextWithdrawalValidatorIndex() uint64
 	// HistoricalSummary has no accessor yet.
+	DepositRequestsStartIndex() uint64
+	DepositBalanceToConsume() uint64
+	ExitBalanceToConsume() uint64
+	EarliestExitEpoch() uint64
+	ConsolidationBalanceToConsume() uint64
+	EarliestConsolidationEpoch() uint64
+	PendingBalanceDeposits() *solid.ListSSZ[*cltypes.PendingBalanceDeposit]
+	PendingPartialWithdrawals() *solid.ListSSZ[*cltypes.PendingPartialWithdrawal]
+	PendingConsolidations() *solid.ListSSZ[*cltypes.PendingConsolidation]
 
 	CurrentEpochAttestations() *solid.ListSSZ[*solid.PendingAttestation]
 	CurrentEpochAttestationsLength() int
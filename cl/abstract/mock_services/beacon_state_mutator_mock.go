@@ -402,6 +402,114 @@ func (c *MockBeaconStateMutatorAddValidatorCall) DoAndReturn(f func(solid.Valida
 	return c
 }
 
+// AppendPendingBalanceDeposit mocks base method.
+func (m *MockBeaconStateMutator) AppendPendingBalanceDeposit(arg0 *cltypes.PendingBalanceDeposit) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AppendPendingBalanceDeposit", arg0)
+}
+
+// AppendPendingBalanceDeposit indicates an expected call of AppendPendingBalanceDeposit.
+func (mr *MockBeaconStateMutatorMockRecorder) AppendPendingBalanceDeposit(arg0 any) *MockBeaconStateMutatorAppendPendingBalanceDepositCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendPendingBalanceDeposit", reflect.TypeOf((*MockBeaconStateMutator)(nil).AppendPendingBalanceDeposit), arg0)
+	return &MockBeaconStateMutatorAppendPendingBalanceDepositCall{Call: call}
+}
+
+// MockBeaconStateMutatorAppendPendingBalanceDepositCall wrap *gomock.Call
+type MockBeaconStateMutatorAppendPendingBalanceDepositCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorAppendPendingBalanceDepositCall) Return() *MockBeaconStateMutatorAppendPendingBalanceDepositCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorAppendPendingBalanceDepositCall) Do(f func(*cltypes.PendingBalanceDeposit)) *MockBeaconStateMutatorAppendPendingBalanceDepositCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorAppendPendingBalanceDepositCall) DoAndReturn(f func(*cltypes.PendingBalanceDeposit)) *MockBeaconStateMutatorAppendPendingBalanceDepositCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// AppendPendingConsolidation mocks base method.
+func (m *MockBeaconStateMutator) AppendPendingConsolidation(arg0 *cltypes.PendingConsolidation) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AppendPendingConsolidation", arg0)
+}
+
+// AppendPendingConsolidation indicates an expected call of AppendPendingConsolidation.
+func (mr *MockBeaconStateMutatorMockRecorder) AppendPendingConsolidation(arg0 any) *MockBeaconStateMutatorAppendPendingConsolidationCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendPendingConsolidation", reflect.TypeOf((*MockBeaconStateMutator)(nil).AppendPendingConsolidation), arg0)
+	return &MockBeaconStateMutatorAppendPendingConsolidationCall{Call: call}
+}
+
+// MockBeaconStateMutatorAppendPendingConsolidationCall wrap *gomock.Call
+type MockBeaconStateMutatorAppendPendingConsolidationCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorAppendPendingConsolidationCall) Return() *MockBeaconStateMutatorAppendPendingConsolidationCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorAppendPendingConsolidationCall) Do(f func(*cltypes.PendingConsolidation)) *MockBeaconStateMutatorAppendPendingConsolidationCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorAppendPendingConsolidationCall) DoAndReturn(f func(*cltypes.PendingConsolidation)) *MockBeaconStateMutatorAppendPendingConsolidationCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// AppendPendingPartialWithdrawal mocks base method.
+func (m *MockBeaconStateMutator) AppendPendingPartialWithdrawal(arg0 *cltypes.PendingPartialWithdrawal) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AppendPendingPartialWithdrawal", arg0)
+}
+
+// AppendPendingPartialWithdrawal indicates an expected call of AppendPendingPartialWithdrawal.
+func (mr *MockBeaconStateMutatorMockRecorder) AppendPendingPartialWithdrawal(arg0 any) *MockBeaconStateMutatorAppendPendingPartialWithdrawalCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendPendingPartialWithdrawal", reflect.TypeOf((*MockBeaconStateMutator)(nil).AppendPendingPartialWithdrawal), arg0)
+	return &MockBeaconStateMutatorAppendPendingPartialWithdrawalCall{Call: call}
+}
+
+// MockBeaconStateMutatorAppendPendingPartialWithdrawalCall wrap *gomock.Call
+type MockBeaconStateMutatorAppendPendingPartialWithdrawalCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorAppendPendingPartialWithdrawalCall) Return() *MockBeaconStateMutatorAppendPendingPartialWithdrawalCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorAppendPendingPartialWithdrawalCall) Do(f func(*cltypes.PendingPartialWithdrawal)) *MockBeaconStateMutatorAppendPendingPartialWithdrawalCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorAppendPendingPartialWithdrawalCall) DoAndReturn(f func(*cltypes.PendingPartialWithdrawal)) *MockBeaconStateMutatorAppendPendingPartialWithdrawalCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // AppendValidator mocks base method.
 func (m *MockBeaconStateMutator) AppendValidator(arg0 solid.Validator) {
 	m.ctrl.T.Helper()
@@ -438,6 +546,78 @@ func (c *MockBeaconStateMutatorAppendValidatorCall) DoAndReturn(f func(solid.Val
 	return c
 }
 
+// CutPendingConsolidations mocks base method.
+func (m *MockBeaconStateMutator) CutPendingConsolidations(arg0 int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CutPendingConsolidations", arg0)
+}
+
+// CutPendingConsolidations indicates an expected call of CutPendingConsolidations.
+func (mr *MockBeaconStateMutatorMockRecorder) CutPendingConsolidations(arg0 any) *MockBeaconStateMutatorCutPendingConsolidationsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CutPendingConsolidations", reflect.TypeOf((*MockBeaconStateMutator)(nil).CutPendingConsolidations), arg0)
+	return &MockBeaconStateMutatorCutPendingConsolidationsCall{Call: call}
+}
+
+// MockBeaconStateMutatorCutPendingConsolidationsCall wrap *gomock.Call
+type MockBeaconStateMutatorCutPendingConsolidationsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorCutPendingConsolidationsCall) Return() *MockBeaconStateMutatorCutPendingConsolidationsCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorCutPendingConsolidationsCall) Do(f func(int)) *MockBeaconStateMutatorCutPendingConsolidationsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorCutPendingConsolidationsCall) DoAndReturn(f func(int)) *MockBeaconStateMutatorCutPendingConsolidationsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CutPendingPartialWithdrawals mocks base method.
+func (m *MockBeaconStateMutator) CutPendingPartialWithdrawals(arg0 int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CutPendingPartialWithdrawals", arg0)
+}
+
+// CutPendingPartialWithdrawals indicates an expected call of CutPendingPartialWithdrawals.
+func (mr *MockBeaconStateMutatorMockRecorder) CutPendingPartialWithdrawals(arg0 any) *MockBeaconStateMutatorCutPendingPartialWithdrawalsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CutPendingPartialWithdrawals", reflect.TypeOf((*MockBeaconStateMutator)(nil).CutPendingPartialWithdrawals), arg0)
+	return &MockBeaconStateMutatorCutPendingPartialWithdrawalsCall{Call: call}
+}
+
+// MockBeaconStateMutatorCutPendingPartialWithdrawalsCall wrap *gomock.Call
+type MockBeaconStateMutatorCutPendingPartialWithdrawalsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorCutPendingPartialWithdrawalsCall) Return() *MockBeaconStateMutatorCutPendingPartialWithdrawalsCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorCutPendingPartialWithdrawalsCall) Do(f func(int)) *MockBeaconStateMutatorCutPendingPartialWithdrawalsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorCutPendingPartialWithdrawalsCall) DoAndReturn(f func(int)) *MockBeaconStateMutatorCutPendingPartialWithdrawalsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // ResetCurrentEpochAttestations mocks base method.
 func (m *MockBeaconStateMutator) ResetCurrentEpochAttestations() {
 	m.ctrl.T.Helper()
@@ -726,6 +906,42 @@ func (c *MockBeaconStateMutatorSetBlockRootAtCall) DoAndReturn(f func(int, commo
 	return c
 }
 
+// SetConsolidationBalanceToConsume mocks base method.
+func (m *MockBeaconStateMutator) SetConsolidationBalanceToConsume(arg0 uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetConsolidationBalanceToConsume", arg0)
+}
+
+// SetConsolidationBalanceToConsume indicates an expected call of SetConsolidationBalanceToConsume.
+func (mr *MockBeaconStateMutatorMockRecorder) SetConsolidationBalanceToConsume(arg0 any) *MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetConsolidationBalanceToConsume", reflect.TypeOf((*MockBeaconStateMutator)(nil).SetConsolidationBalanceToConsume), arg0)
+	return &MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall{Call: call}
+}
+
+// MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall wrap *gomock.Call
+type MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall) Return() *MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall) Do(f func(uint64)) *MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall) DoAndReturn(f func(uint64)) *MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // SetCurrentEpochParticipationFlags mocks base method.
 func (m *MockBeaconStateMutator) SetCurrentEpochParticipationFlags(arg0 []cltypes.ParticipationFlags) {
 	m.ctrl.T.Helper()
@@ -834,6 +1050,150 @@ func (c *MockBeaconStateMutatorSetCurrentSyncCommitteeCall) DoAndReturn(f func(*
 	return c
 }
 
+// SetDepositBalanceToConsume mocks base method.
+func (m *MockBeaconStateMutator) SetDepositBalanceToConsume(arg0 uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetDepositBalanceToConsume", arg0)
+}
+
+// SetDepositBalanceToConsume indicates an expected call of SetDepositBalanceToConsume.
+func (mr *MockBeaconStateMutatorMockRecorder) SetDepositBalanceToConsume(arg0 any) *MockBeaconStateMutatorSetDepositBalanceToConsumeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDepositBalanceToConsume", reflect.TypeOf((*MockBeaconStateMutator)(nil).SetDepositBalanceToConsume), arg0)
+	return &MockBeaconStateMutatorSetDepositBalanceToConsumeCall{Call: call}
+}
+
+// MockBeaconStateMutatorSetDepositBalanceToConsumeCall wrap *gomock.Call
+type MockBeaconStateMutatorSetDepositBalanceToConsumeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorSetDepositBalanceToConsumeCall) Return() *MockBeaconStateMutatorSetDepositBalanceToConsumeCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorSetDepositBalanceToConsumeCall) Do(f func(uint64)) *MockBeaconStateMutatorSetDepositBalanceToConsumeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorSetDepositBalanceToConsumeCall) DoAndReturn(f func(uint64)) *MockBeaconStateMutatorSetDepositBalanceToConsumeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetDepositRequestsStartIndex mocks base method.
+func (m *MockBeaconStateMutator) SetDepositRequestsStartIndex(arg0 uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetDepositRequestsStartIndex", arg0)
+}
+
+// SetDepositRequestsStartIndex indicates an expected call of SetDepositRequestsStartIndex.
+func (mr *MockBeaconStateMutatorMockRecorder) SetDepositRequestsStartIndex(arg0 any) *MockBeaconStateMutatorSetDepositRequestsStartIndexCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDepositRequestsStartIndex", reflect.TypeOf((*MockBeaconStateMutator)(nil).SetDepositRequestsStartIndex), arg0)
+	return &MockBeaconStateMutatorSetDepositRequestsStartIndexCall{Call: call}
+}
+
+// MockBeaconStateMutatorSetDepositRequestsStartIndexCall wrap *gomock.Call
+type MockBeaconStateMutatorSetDepositRequestsStartIndexCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorSetDepositRequestsStartIndexCall) Return() *MockBeaconStateMutatorSetDepositRequestsStartIndexCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorSetDepositRequestsStartIndexCall) Do(f func(uint64)) *MockBeaconStateMutatorSetDepositRequestsStartIndexCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorSetDepositRequestsStartIndexCall) DoAndReturn(f func(uint64)) *MockBeaconStateMutatorSetDepositRequestsStartIndexCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetEarliestConsolidationEpoch mocks base method.
+func (m *MockBeaconStateMutator) SetEarliestConsolidationEpoch(arg0 uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetEarliestConsolidationEpoch", arg0)
+}
+
+// SetEarliestConsolidationEpoch indicates an expected call of SetEarliestConsolidationEpoch.
+func (mr *MockBeaconStateMutatorMockRecorder) SetEarliestConsolidationEpoch(arg0 any) *MockBeaconStateMutatorSetEarliestConsolidationEpochCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEarliestConsolidationEpoch", reflect.TypeOf((*MockBeaconStateMutator)(nil).SetEarliestConsolidationEpoch), arg0)
+	return &MockBeaconStateMutatorSetEarliestConsolidationEpochCall{Call: call}
+}
+
+// MockBeaconStateMutatorSetEarliestConsolidationEpochCall wrap *gomock.Call
+type MockBeaconStateMutatorSetEarliestConsolidationEpochCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorSetEarliestConsolidationEpochCall) Return() *MockBeaconStateMutatorSetEarliestConsolidationEpochCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorSetEarliestConsolidationEpochCall) Do(f func(uint64)) *MockBeaconStateMutatorSetEarliestConsolidationEpochCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorSetEarliestConsolidationEpochCall) DoAndReturn(f func(uint64)) *MockBeaconStateMutatorSetEarliestConsolidationEpochCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetEarliestExitEpoch mocks base method.
+func (m *MockBeaconStateMutator) SetEarliestExitEpoch(arg0 uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetEarliestExitEpoch", arg0)
+}
+
+// SetEarliestExitEpoch indicates an expected call of SetEarliestExitEpoch.
+func (mr *MockBeaconStateMutatorMockRecorder) SetEarliestExitEpoch(arg0 any) *MockBeaconStateMutatorSetEarliestExitEpochCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEarliestExitEpoch", reflect.TypeOf((*MockBeaconStateMutator)(nil).SetEarliestExitEpoch), arg0)
+	return &MockBeaconStateMutatorSetEarliestExitEpochCall{Call: call}
+}
+
+// MockBeaconStateMutatorSetEarliestExitEpochCall wrap *gomock.Call
+type MockBeaconStateMutatorSetEarliestExitEpochCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorSetEarliestExitEpochCall) Return() *MockBeaconStateMutatorSetEarliestExitEpochCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorSetEarliestExitEpochCall) Do(f func(uint64)) *MockBeaconStateMutatorSetEarliestExitEpochCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorSetEarliestExitEpochCall) DoAndReturn(f func(uint64)) *MockBeaconStateMutatorSetEarliestExitEpochCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // SetEffectiveBalanceForValidatorAtIndex mocks base method.
 func (m *MockBeaconStateMutator) SetEffectiveBalanceForValidatorAtIndex(arg0 int, arg1 uint64) {
 	m.ctrl.T.Helper()
@@ -978,6 +1338,42 @@ func (c *MockBeaconStateMutatorSetEth1DepositIndexCall) DoAndReturn(f func(uint6
 	return c
 }
 
+// SetExitBalanceToConsume mocks base method.
+func (m *MockBeaconStateMutator) SetExitBalanceToConsume(arg0 uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetExitBalanceToConsume", arg0)
+}
+
+// SetExitBalanceToConsume indicates an expected call of SetExitBalanceToConsume.
+func (mr *MockBeaconStateMutatorMockRecorder) SetExitBalanceToConsume(arg0 any) *MockBeaconStateMutatorSetExitBalanceToConsumeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetExitBalanceToConsume", reflect.TypeOf((*MockBeaconStateMutator)(nil).SetExitBalanceToConsume), arg0)
+	return &MockBeaconStateMutatorSetExitBalanceToConsumeCall{Call: call}
+}
+
+// MockBeaconStateMutatorSetExitBalanceToConsumeCall wrap *gomock.Call
+type MockBeaconStateMutatorSetExitBalanceToConsumeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorSetExitBalanceToConsumeCall) Return() *MockBeaconStateMutatorSetExitBalanceToConsumeCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorSetExitBalanceToConsumeCall) Do(f func(uint64)) *MockBeaconStateMutatorSetExitBalanceToConsumeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorSetExitBalanceToConsumeCall) DoAndReturn(f func(uint64)) *MockBeaconStateMutatorSetExitBalanceToConsumeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // SetExitEpochForValidatorAtIndex mocks base method.
 func (m *MockBeaconStateMutator) SetExitEpochForValidatorAtIndex(arg0 int, arg1 uint64) {
 	m.ctrl.T.Helper()
@@ -2121,3 +2517,7 @@ func (c *MockBeaconStateMutatorSetWithdrawalCredentialForValidatorAtIndexCall) D
 func (c *MockBeaconStateMutator) SetPreviousEpochAttestations(attestations *solid.ListSSZ[*solid.PendingAttestation]) {
 
 }
+
+func (c *MockBeaconStateMutator) SetPendingBalanceDeposits(deposits *solid.ListSSZ[*cltypes.PendingBalanceDeposit]) {
+
+}
@@ -43,6 +43,9 @@ type RouterConfiguration struct {
 	Node       bool
 	Validator  bool
 	Lighthouse bool
+
+	// KeymanagerToken is the bearer token protecting the keymanager API, which is served only if it is set.
+	KeymanagerToken string
 }
 
 func (r *RouterConfiguration) UnwrapEndpointsList(l []string) error {
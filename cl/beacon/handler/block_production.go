@@ -73,6 +73,9 @@ var (
 
 var defaultGraffitiString = "Caplin"
 
+// DefaultGraffiti is the graffiti of the blocks produced without one.
+var DefaultGraffiti = libcommon.HexToHash(hex.EncodeToString([]byte(defaultGraffitiString)))
+
 func (a *ApiHandler) GetEthV1ValidatorAttestationData(
 	w http.ResponseWriter,
 	r *http.Request,
@@ -99,17 +102,22 @@ func (a *ApiHandler) GetEthV1ValidatorAttestationData(
 		)
 	}
 
-	attestationData, err := a.attestationProducer.ProduceAndCacheAttestationData(
-		headState,
-		*slot,
-		*committeeIndex,
-	)
+	attestationData, err := a.ProduceAttestationData(*slot, *committeeIndex)
 	if err != nil {
 		return nil, beaconhttp.NewEndpointError(http.StatusInternalServerError, err)
 	}
 	return newBeaconResponse(attestationData), nil
 }
 
+// ProduceAttestationData returns the data to attest to for the given committee at the given slot.
+func (a *ApiHandler) ProduceAttestationData(slot, committeeIndex uint64) (solid.AttestationData, error) {
+	headState := a.syncedData.HeadState()
+	if headState == nil {
+		return nil, ErrNodeSyncing
+	}
+	return a.attestationProducer.ProduceAndCacheAttestationData(headState, slot, committeeIndex)
+}
+
 func (a *ApiHandler) GetEthV3ValidatorBlock(
 	w http.ResponseWriter,
 	r *http.Request,
@@ -129,15 +137,9 @@ func (a *ApiHandler) GetEthV3ValidatorBlock(
 	}
 	graffiti := libcommon.HexToHash(r.URL.Query().Get("graffiti"))
 	if !r.URL.Query().Has("graffiti") {
-		graffiti = libcommon.HexToHash(hex.EncodeToString([]byte(defaultGraffitiString)))
+		graffiti = DefaultGraffiti
 	}
 
-	tx, err := a.indiciesDB.BeginRo(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer tx.Rollback()
-
 	targetSlotStr := chi.URLParam(r, "slot")
 	targetSlot, err := strconv.ParseUint(targetSlotStr, 10, 64)
 	if err != nil {
@@ -160,12 +162,51 @@ func (a *ApiHandler) GetEthV3ValidatorBlock(
 		}
 	}
 
+	block, err := a.ProduceBlock(ctx, targetSlot, randaoReveal, graffiti, builderBoostFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	// todo: consensusValue
+	rewardsCollector := &eth2.BlockRewardsCollector{}
+	consensusValue := rewardsCollector.Attestations + rewardsCollector.ProposerSlashings + rewardsCollector.AttesterSlashings + rewardsCollector.SyncAggregate
+	a.setupHeaderReponseForBlockProduction(
+		w,
+		block.Version(),
+		block.IsBlinded(),
+		block.GetExecutionValue().Uint64(),
+		consensusValue,
+	)
+
+	var resp *beaconhttp.BeaconResponse
+	if block.IsBlinded() {
+		resp = newBeaconResponse(block.ToBlinded())
+	} else {
+		resp = newBeaconResponse(block.ToExecution())
+	}
+	return resp.WithVersion(block.Version()).With("execution_payload_blinded", block.IsBlinded()).
+		With("execution_payload_value", strconv.FormatUint(block.GetExecutionValue().Uint64(), 10)).
+		With("consensus_block_value", strconv.FormatUint(consensusValue, 10)), nil
+}
+
+// ProduceBlock produces an unsigned block for the given slot on top of the current head. The block is blinded if
+// the payload of the builder was preferred over the local one, as weighted by builderBoostFactor.
+func (a *ApiHandler) ProduceBlock(
+	ctx context.Context,
+	targetSlot uint64,
+	randaoReveal common.Bytes96,
+	graffiti common.Hash,
+	builderBoostFactor uint64,
+) (*cltypes.BlindOrExecutionBeaconBlock, error) {
+	tx, err := a.indiciesDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	s := a.syncedData.HeadState()
 	if s == nil {
-		return nil, beaconhttp.NewEndpointError(
-			http.StatusServiceUnavailable,
-			fmt.Errorf("node is syncing"),
-		)
+		return nil, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, ErrNodeSyncing)
 	}
 
 	baseBlockRoot, err := s.BlockRoot()
@@ -233,27 +274,7 @@ func (a *ApiHandler) GetEthV3ValidatorBlock(
 		"version", block.Version(),
 		"blinded", block.IsBlinded(),
 	)
-
-	// todo: consensusValue
-	rewardsCollector := &eth2.BlockRewardsCollector{}
-	consensusValue := rewardsCollector.Attestations + rewardsCollector.ProposerSlashings + rewardsCollector.AttesterSlashings + rewardsCollector.SyncAggregate
-	a.setupHeaderReponseForBlockProduction(
-		w,
-		block.Version(),
-		block.IsBlinded(),
-		block.GetExecutionValue().Uint64(),
-		consensusValue,
-	)
-
-	var resp *beaconhttp.BeaconResponse
-	if block.IsBlinded() {
-		resp = newBeaconResponse(block.ToBlinded())
-	} else {
-		resp = newBeaconResponse(block.ToExecution())
-	}
-	return resp.WithVersion(block.Version()).With("execution_payload_blinded", block.IsBlinded()).
-		With("execution_payload_value", strconv.FormatUint(block.GetExecutionValue().Uint64(), 10)).
-		With("consensus_block_value", strconv.FormatUint(consensusValue, 10)), nil
+	return block, nil
 }
 
 func (a *ApiHandler) produceBlock(
@@ -417,6 +438,17 @@ func (a *ApiHandler) getBuilderPayload(
 	}
 	if ethHeader := header.Data.Message.Header; ethHeader != nil {
 		ethHeader.SetVersion(baseState.Version())
+		// the builder must move the gas limit towards the target set for the proposer, if any.
+		proposerCfg, err := a.validatorParams.ProposerConfig(ctx, pubKey)
+		if err != nil {
+			return nil, err
+		}
+		if proposerCfg.GasLimit != 0 {
+			parentGasLimit := baseBlock.Body.ExecutionPayload.GasLimit
+			if expected := expectedGasLimit(parentGasLimit, proposerCfg.GasLimit); ethHeader.GasLimit != expected {
+				return nil, fmt.Errorf("builder payload gas limit %d does not match expected gas limit %d", ethHeader.GasLimit, expected)
+			}
+		}
 	}
 	// check kzg commitments
 	if header != nil && baseState.Version() >= clparams.DenebVersion {
@@ -437,6 +469,19 @@ func (a *ApiHandler) getBuilderPayload(
 	return header, nil
 }
 
+// expectedGasLimit computes the gas limit of a block whose proposer targets targetGasLimit, which can move by
+// at most 1/1024 of the parent gas limit per block.
+func expectedGasLimit(parentGasLimit, targetGasLimit uint64) uint64 {
+	maxGasLimitDiff := parentGasLimit / 1024
+	if maxGasLimitDiff > 0 {
+		maxGasLimitDiff--
+	}
+	if targetGasLimit > parentGasLimit {
+		return min(parentGasLimit+maxGasLimitDiff, targetGasLimit)
+	}
+	return max(parentGasLimit-maxGasLimitDiff, targetGasLimit)
+}
+
 func (a *ApiHandler) produceBeaconBody(
 	ctx context.Context,
 	apiVersion int,
@@ -491,7 +536,16 @@ func (a *ApiHandler) produceBeaconBody(
 		timeoutForBlockBuilding := 2 * time.Second // keep asking for 2 seconds for block
 		retryTime := 10 * time.Millisecond
 		secsDiff := (targetSlot - baseBlock.Slot) * a.beaconChainCfg.SecondsPerSlot
-		feeRecipient, _ := a.validatorParams.GetFeeRecipient(proposerIndex)
+		proposerPubkey, err := baseState.ValidatorPublicKey(int(proposerIndex))
+		if err != nil {
+			log.Error("BlockProduction: Failed to get proposer public key", "err", err)
+			return
+		}
+		feeRecipient, _, err := a.validatorParams.ProposerFeeRecipient(ctx, proposerIndex, proposerPubkey)
+		if err != nil {
+			log.Error("BlockProduction: Failed to get proposer fee recipient", "err", err)
+			return
+		}
 		var withdrawals []*types.Withdrawal
 		clWithdrawals := state.ExpectedWithdrawals(
 			baseState,
@@ -782,7 +836,7 @@ func (a *ApiHandler) postBeaconBlocks(w http.ResponseWriter, r *http.Request, ap
 	}
 	_ = validation
 
-	if err := a.broadcastBlock(ctx, block.SignedBlock); err != nil {
+	if err := a.PublishBlock(ctx, block.SignedBlock); err != nil {
 		return nil, beaconhttp.NewEndpointError(http.StatusInternalServerError, err)
 	}
 	return newBeaconResponse(nil), nil
@@ -829,14 +883,22 @@ func (a *ApiHandler) publishBlindedBlocks(w http.ResponseWriter, r *http.Request
 			return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
 		}
 	}
+	if err := a.PublishBlindedBlock(r.Context(), signedBlindedBlock); err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(nil), nil
+}
+
+// PublishBlindedBlock retrieves the payload of a signed blinded block from the builder and publishes the unblinded block.
+func (a *ApiHandler) PublishBlindedBlock(ctx context.Context, signedBlindedBlock *cltypes.SignedBlindedBeaconBlock) error {
 	// submit and unblind the signedBlindedBlock
-	blockPayload, blobsBundle, err := a.builderClient.SubmitBlindedBlocks(r.Context(), signedBlindedBlock)
+	blockPayload, blobsBundle, err := a.builderClient.SubmitBlindedBlocks(ctx, signedBlindedBlock)
 	if err != nil {
-		return nil, beaconhttp.NewEndpointError(http.StatusInternalServerError, err)
+		return beaconhttp.NewEndpointError(http.StatusInternalServerError, err)
 	}
 	signedBlock, err := signedBlindedBlock.Unblind(blockPayload)
 	if err != nil {
-		return nil, beaconhttp.NewEndpointError(http.StatusInternalServerError, err)
+		return beaconhttp.NewEndpointError(http.StatusInternalServerError, err)
 	}
 
 	// check blob bundle
@@ -861,12 +923,12 @@ func (a *ApiHandler) publishBlindedBlocks(w http.ResponseWriter, r *http.Request
 			return nil
 		}(blobsBundle)
 		if err != nil {
-			return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+			return beaconhttp.NewEndpointError(http.StatusBadRequest, err)
 		}
 		// check commitments
 		blockCommitments := signedBlindedBlock.Block.Body.BlobKzgCommitments
 		if len(blobsBundle.Commitments) != blockCommitments.Len() {
-			return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("commitments length mismatch"))
+			return beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("commitments length mismatch"))
 		}
 		for i := range blobsBundle.Commitments {
 			// add the bundle to recently produced blobs
@@ -879,12 +941,12 @@ func (a *ApiHandler) publishBlindedBlocks(w http.ResponseWriter, r *http.Request
 	}
 
 	// broadcast the block
-	if err := a.broadcastBlock(r.Context(), signedBlock); err != nil {
-		return nil, beaconhttp.NewEndpointError(http.StatusInternalServerError, err)
+	if err := a.PublishBlock(ctx, signedBlock); err != nil {
+		return beaconhttp.NewEndpointError(http.StatusInternalServerError, err)
 	}
 
-	log.Info("successfully publish blinded block", "block_num", signedBlock.Block.Body.ExecutionPayload.BlockNumber, "api_version", apiVersion)
-	return newBeaconResponse(nil), nil
+	log.Info("successfully publish blinded block", "block_num", signedBlock.Block.Body.ExecutionPayload.BlockNumber)
+	return nil
 }
 
 func (a *ApiHandler) parseEthConsensusVersion(
@@ -941,7 +1003,8 @@ func (a *ApiHandler) parseRequestBeaconBlock(
 	return nil, fmt.Errorf("invalid content type")
 }
 
-func (a *ApiHandler) broadcastBlock(ctx context.Context, blk *cltypes.SignedBeaconBlock) error {
+// PublishBlock stores a signed block and gossips it along with the blob sidecars of its recently produced blobs.
+func (a *ApiHandler) PublishBlock(ctx context.Context, blk *cltypes.SignedBeaconBlock) error {
 	blkSSZ, err := blk.EncodeSSZ(nil)
 	if err != nil {
 		return err
@@ -1139,7 +1202,7 @@ func computeAttestationReward(
 	if err != nil {
 		return 0, err
 	}
-	attestingIndicies, err := s.GetAttestingIndicies(data, attestation.AggregationBits(), true)
+	attestingIndicies, err := state.GetAttestingIndicesForAttestation(s, attestation, true)
 	if err != nil {
 		return 0, err
 	}
@@ -42,7 +42,7 @@ type getHeadersRequest struct {
 func (a *ApiHandler) rootFromBlockId(ctx context.Context, tx kv.Tx, blockId *beaconhttp.SegmentID) (root libcommon.Hash, err error) {
 	switch {
 	case blockId.Head():
-		root, _, err = a.forkchoiceStore.GetHead()
+		root, err = a.HeadBlockRoot()
 		if err != nil {
 			return libcommon.Hash{}, err
 		}
@@ -75,6 +75,12 @@ func (a *ApiHandler) rootFromBlockId(ctx context.Context, tx kv.Tx, blockId *bea
 	return
 }
 
+// HeadBlockRoot returns the root of the head block chosen by the fork choice.
+func (a *ApiHandler) HeadBlockRoot() (libcommon.Hash, error) {
+	root, _, err := a.forkchoiceStore.GetHead()
+	return root, err
+}
+
 func (a *ApiHandler) GetEthV1BeaconBlock(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
 	ctx := r.Context()
 	tx, err := a.indiciesDB.BeginRo(ctx)
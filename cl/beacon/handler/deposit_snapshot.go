@@ -0,0 +1,43 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	state_accessors "github.com/ledgerwatch/erigon/cl/persistence/state"
+)
+
+// GetEthV1BeaconDepositSnapshot serves the EIP-4881 snapshot of the finalized deposit tree.
+func (a *ApiHandler) GetEthV1BeaconDepositSnapshot(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	tx, err := a.indiciesDB.BeginRo(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	snapshot, err := state_accessors.ReadDepositSnapshot(tx)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("no finalized deposit snapshot available yet"))
+	}
+	return newBeaconResponse(snapshot), nil
+}
@@ -17,6 +17,7 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -28,7 +29,8 @@ import (
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 )
 
-type attesterDutyResponse struct {
+// AttesterDuty is the attestation duty of a validator at a slot.
+type AttesterDuty struct {
 	Pubkey                  libcommon.Bytes48 `json:"pubkey"`
 	ValidatorIndex          uint64            `json:"validator_index,string"`
 	CommitteeIndex          uint64            `json:"committee_index,string"`
@@ -74,7 +76,7 @@ func (a *ApiHandler) getAttesterDuties(w http.ResponseWriter, r *http.Request) (
 	if len(idxsStr) == 0 {
 		return newBeaconResponse([]string{}).WithOptimistic(a.forkchoiceStore.IsHeadOptimistic()).With("dependent_root", dependentRoot), nil
 	}
-	idxSet := map[int]struct{}{}
+	idxs := make([]uint64, 0, len(idxsStr))
 	// convert the request to uint64
 	for _, idxStr := range idxsStr {
 
@@ -82,26 +84,36 @@ func (a *ApiHandler) getAttesterDuties(w http.ResponseWriter, r *http.Request) (
 		if err != nil {
 			return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("could not parse validator index: %w", err))
 		}
-		if _, ok := idxSet[int(idx)]; ok {
-			continue
-		}
+		idxs = append(idxs, idx)
+	}
+	resp, err := a.AttesterDuties(r.Context(), epoch, idxs)
+	if err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(resp).WithOptimistic(a.forkchoiceStore.IsHeadOptimistic()).With("dependent_root", dependentRoot), nil
+}
+
+// AttesterDuties returns the attestation duties of the given validators in the given epoch.
+func (a *ApiHandler) AttesterDuties(ctx context.Context, epoch uint64, idxs []uint64) ([]AttesterDuty, error) {
+	idxSet := map[int]struct{}{}
+	for _, idx := range idxs {
 		idxSet[int(idx)] = struct{}{}
 	}
 
-	tx, err := a.indiciesDB.BeginRo(r.Context())
+	tx, err := a.indiciesDB.BeginRo(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	resp := []attesterDutyResponse{}
+	resp := []AttesterDuty{}
 
 	// get the duties
 	if a.forkchoiceStore.LowestAvaiableSlot() <= epoch*a.beaconChainCfg.SlotsPerEpoch {
 		// non-finality case
-
+		s := a.syncedData.HeadState()
 		if s == nil {
-			return nil, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, fmt.Errorf("node is syncing"))
+			return nil, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, ErrNodeSyncing)
 		}
 
 		if epoch > state.Epoch(s)+3 {
@@ -125,7 +137,7 @@ func (a *ApiHandler) getAttesterDuties(w http.ResponseWriter, r *http.Request) (
 					if err != nil {
 						return nil, err
 					}
-					duty := attesterDutyResponse{
+					duty := AttesterDuty{
 						Pubkey:                  publicKey,
 						ValidatorIndex:          idx,
 						CommitteeIndex:          committeeIndex,
@@ -138,7 +150,7 @@ func (a *ApiHandler) getAttesterDuties(w http.ResponseWriter, r *http.Request) (
 				}
 			}
 		}
-		return newBeaconResponse(resp).WithOptimistic(a.forkchoiceStore.IsHeadOptimistic()).With("dependent_root", dependentRoot), nil
+		return resp, nil
 	}
 
 	stageStateProgress, err := state_accessors.GetStateProcessingProgress(tx)
@@ -184,7 +196,7 @@ func (a *ApiHandler) getAttesterDuties(w http.ResponseWriter, r *http.Request) (
 				if err != nil {
 					return nil, err
 				}
-				duty := attesterDutyResponse{
+				duty := AttesterDuty{
 					Pubkey:                  publicKey,
 					ValidatorIndex:          idx,
 					CommitteeIndex:          committeeIndex,
@@ -197,5 +209,5 @@ func (a *ApiHandler) getAttesterDuties(w http.ResponseWriter, r *http.Request) (
 			}
 		}
 	}
-	return newBeaconResponse(resp).WithOptimistic(a.forkchoiceStore.IsHeadOptimistic()).With("dependent_root", dependentRoot), nil
+	return resp, nil
 }
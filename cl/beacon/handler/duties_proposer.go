@@ -17,6 +17,7 @@
 package handler
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/binary"
 	"fmt"
@@ -32,7 +33,8 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv"
 )
 
-type proposerDuties struct {
+// ProposerDuty is the block proposal duty of a validator at a slot.
+type ProposerDuty struct {
 	Pubkey         libcommon.Bytes48 `json:"pubkey"`
 	ValidatorIndex uint64            `json:"validator_index,string"`
 	Slot           uint64            `json:"slot,string"`
@@ -48,8 +50,25 @@ func (a *ApiHandler) getDutiesProposer(w http.ResponseWriter, r *http.Request) (
 		return nil, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, fmt.Errorf("node is syncing"))
 	}
 	dependentRoot := a.getDependentRoot(s, epoch)
+	finalized := epoch < a.forkchoiceStore.FinalizedCheckpoint().Epoch()
+	duties, err := a.ProposerDuties(r.Context(), epoch)
+	if err != nil {
+		return nil, err
+	}
+	if finalized {
+		return newBeaconResponse(duties).
+			WithOptimistic(a.forkchoiceStore.IsHeadOptimistic()).
+			WithFinalized(true).
+			WithVersion(a.beaconChainCfg.GetCurrentStateVersion(epoch)).
+			With("dependent_root", dependentRoot), nil
+	}
+	return newBeaconResponse(duties).WithFinalized(false).WithVersion(a.beaconChainCfg.GetCurrentStateVersion(epoch)).With("dependent_root", dependentRoot), nil
+}
+
+// ProposerDuties returns the block proposers of every slot of the given epoch.
+func (a *ApiHandler) ProposerDuties(ctx context.Context, epoch uint64) ([]ProposerDuty, error) {
 	if epoch < a.forkchoiceStore.FinalizedCheckpoint().Epoch() {
-		tx, err := a.indiciesDB.BeginRo(r.Context())
+		tx, err := a.indiciesDB.BeginRo(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -62,7 +81,7 @@ func (a *ApiHandler) getDutiesProposer(w http.ResponseWriter, r *http.Request) (
 		if len(indiciesBytes) != int(a.beaconChainCfg.SlotsPerEpoch*4) {
 			return nil, beaconhttp.NewEndpointError(http.StatusInternalServerError, fmt.Errorf("proposer duties is corrupted"))
 		}
-		duties := make([]proposerDuties, a.beaconChainCfg.SlotsPerEpoch)
+		duties := make([]ProposerDuty, a.beaconChainCfg.SlotsPerEpoch)
 		for i := uint64(0); i < a.beaconChainCfg.SlotsPerEpoch; i++ {
 			validatorIndex := binary.BigEndian.Uint32(indiciesBytes[i*4 : i*4+4])
 			var pk libcommon.Bytes48
@@ -70,33 +89,27 @@ func (a *ApiHandler) getDutiesProposer(w http.ResponseWriter, r *http.Request) (
 			if err != nil {
 				return nil, err
 			}
-			duties[i] = proposerDuties{
+			duties[i] = ProposerDuty{
 				Pubkey:         pk,
 				ValidatorIndex: uint64(validatorIndex),
 				Slot:           epoch*a.beaconChainCfg.SlotsPerEpoch + i,
 			}
 		}
-		return newBeaconResponse(duties).
-			WithOptimistic(a.forkchoiceStore.IsHeadOptimistic()).
-			WithFinalized(true).
-			WithVersion(a.beaconChainCfg.GetCurrentStateVersion(epoch)).
-			With("dependent_root", dependentRoot), nil
+		return duties, nil
 	}
 
 	// We need to compute our duties
 	state := a.syncedData.HeadState()
 	if state == nil {
-		return nil, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, fmt.Errorf("beacon node is syncing"))
-
+		return nil, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, ErrNodeSyncing)
 	}
 
 	expectedSlot := epoch * a.beaconChainCfg.SlotsPerEpoch
 
-	duties := make([]proposerDuties, a.beaconChainCfg.SlotsPerEpoch)
+	duties := make([]ProposerDuty, a.beaconChainCfg.SlotsPerEpoch)
 	wg := sync.WaitGroup{}
 
 	for slot := expectedSlot; slot < expectedSlot+a.beaconChainCfg.SlotsPerEpoch; slot++ {
-		var proposerIndex uint64
 		// Lets do proposer index computation
 		mixPosition := (epoch + a.beaconChainCfg.EpochsPerHistoricalVector - a.beaconChainCfg.MinSeedLookahead - 1) %
 			a.beaconChainCfg.EpochsPerHistoricalVector
@@ -124,16 +137,15 @@ func (a *ApiHandler) getDutiesProposer(w http.ResponseWriter, r *http.Request) (
 		// Do it in parallel
 		go func(i, slot uint64, indicies []uint64, seedArray [32]byte) {
 			defer wg.Done()
-			proposerIndex, err = shuffling2.ComputeProposerIndex(state.BeaconState, indices, seedArray)
+			proposerIndex, err := shuffling2.ComputeProposerIndex(state.BeaconState, indices, seedArray)
 			if err != nil {
 				panic(err)
 			}
-			var pk libcommon.Bytes48
-			pk, err = state.ValidatorPublicKey(int(proposerIndex))
+			pk, err := state.ValidatorPublicKey(int(proposerIndex))
 			if err != nil {
 				panic(err)
 			}
-			duties[i] = proposerDuties{
+			duties[i] = ProposerDuty{
 				Pubkey:         pk,
 				ValidatorIndex: proposerIndex,
 				Slot:           slot,
@@ -141,6 +153,5 @@ func (a *ApiHandler) getDutiesProposer(w http.ResponseWriter, r *http.Request) (
 		}(slot-expectedSlot, slot, indices, seedArray)
 	}
 	wg.Wait()
-
-	return newBeaconResponse(duties).WithFinalized(false).WithVersion(a.beaconChainCfg.GetCurrentStateVersion(epoch)).With("dependent_root", dependentRoot), nil
+	return duties, nil
 }
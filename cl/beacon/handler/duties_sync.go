@@ -17,6 +17,7 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -29,7 +30,8 @@ import (
 	state_accessors "github.com/ledgerwatch/erigon/cl/persistence/state"
 )
 
-type syncDutyResponse struct {
+// SyncDuty lists the positions of a validator in the sync committee.
+type SyncDuty struct {
 	Pubkey                         libcommon.Bytes48 `json:"pubkey"`
 	ValidatorIndex                 uint64            `json:"validator_index,string"`
 	ValidatorSyncCommitteeIndicies []string          `json:"validator_sync_committee_indices"`
@@ -41,9 +43,6 @@ func (a *ApiHandler) getSyncDuties(w http.ResponseWriter, r *http.Request) (*bea
 		return nil, err
 	}
 
-	// compute the sync committee period
-	period := epoch / a.beaconChainCfg.EpochsPerSyncCommitteePeriod
-
 	var idxsStr []string
 	if err := json.NewDecoder(r.Body).Decode(&idxsStr); err != nil {
 		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("could not decode request body: %w. request body is required.", err))
@@ -66,8 +65,20 @@ func (a *ApiHandler) getSyncDuties(w http.ResponseWriter, r *http.Request) (*bea
 		idxs = append(idxs, idx)
 		duplicates[int(idx)] = struct{}{}
 	}
+	duties, err := a.SyncDuties(r.Context(), epoch, idxs)
+	if err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(duties).WithOptimistic(a.forkchoiceStore.IsHeadOptimistic()), nil
+}
+
+// SyncDuties returns the sync committee duties of the given validators in the given epoch. Validators which are
+// not part of the sync committee are omitted.
+func (a *ApiHandler) SyncDuties(ctx context.Context, epoch uint64, idxs []uint64) ([]*SyncDuty, error) {
+	// compute the sync committee period
+	period := epoch / a.beaconChainCfg.EpochsPerSyncCommitteePeriod
 
-	tx, err := a.indiciesDB.BeginRo(r.Context())
+	tx, err := a.indiciesDB.BeginRo(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -97,7 +108,7 @@ func (a *ApiHandler) getSyncDuties(w http.ResponseWriter, r *http.Request) (*bea
 		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("could not find sync committee for epoch %d", epoch))
 	}
 	// Now we have the sync committee, we can initialize our response set
-	dutiesSet := map[uint64]*syncDutyResponse{}
+	dutiesSet := map[uint64]*SyncDuty{}
 	for _, idx := range idxs {
 		publicKey, err := state_accessors.ReadPublicKeyByIndex(tx, idx)
 		if err != nil {
@@ -106,7 +117,7 @@ func (a *ApiHandler) getSyncDuties(w http.ResponseWriter, r *http.Request) (*bea
 		if publicKey == (libcommon.Bytes48{}) {
 			return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("could not find validator with index %d", idx))
 		}
-		dutiesSet[idx] = &syncDutyResponse{
+		dutiesSet[idx] = &SyncDuty{
 			Pubkey:         publicKey,
 			ValidatorIndex: idx,
 		}
@@ -128,7 +139,7 @@ func (a *ApiHandler) getSyncDuties(w http.ResponseWriter, r *http.Request) (*bea
 			strconv.FormatUint(uint64(idx), 10))
 	}
 	// Now we can convert the map to a slice
-	duties := make([]*syncDutyResponse, 0, len(dutiesSet))
+	duties := make([]*SyncDuty, 0, len(dutiesSet))
 	for _, duty := range dutiesSet {
 		if len(duty.ValidatorSyncCommitteeIndicies) == 0 {
 			continue
@@ -138,6 +149,5 @@ func (a *ApiHandler) getSyncDuties(w http.ResponseWriter, r *http.Request) (*bea
 	sort.Slice(duties, func(i, j int) bool {
 		return duties[i].ValidatorIndex < duties[j].ValidatorIndex
 	})
-
-	return newBeaconResponse(duties).WithOptimistic(a.forkchoiceStore.IsHeadOptimistic()), nil
+	return duties, nil
 }
@@ -17,6 +17,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"sync"
 
@@ -52,6 +53,9 @@ import (
 
 const maxBlobBundleCacheSize = 48 // 8 blocks worth of blobs
 
+// ErrNodeSyncing is returned when the head state needed to serve a request is not available yet.
+var ErrNodeSyncing = errors.New("beacon node is syncing")
+
 type BlobBundle struct {
 	Commitment common.Bytes48
 	Blob       *cltypes.Blob
@@ -250,6 +254,7 @@ func (a *ApiHandler) init() {
 						r.Get("/{block_id}/root", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconBlockRoot))
 					})
 					r.Get("/genesis", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconGenesis))
+					r.Get("/deposit_snapshot", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconDepositSnapshot))
 					r.Get("/blinded_blocks/{block_id}", beaconhttp.HandleEndpointFunc(a.GetEthV1BlindedBlock))
 					r.Route("/pool", func(r chi.Router) {
 						r.Get("/voluntary_exits", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconPoolVoluntaryExits))
@@ -18,9 +18,12 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	sentinel "github.com/ledgerwatch/erigon-lib/gointerfaces/sentinelproto"
 	"github.com/ledgerwatch/erigon-lib/log/v3"
@@ -81,22 +84,29 @@ func (a *ApiHandler) PostEthV1BeaconPoolAttestations(w http.ResponseWriter, r *h
 		beaconhttp.NewEndpointError(http.StatusBadRequest, err).WriteTo(w)
 		return
 	}
+	if err := a.PublishAttestations(r.Context(), req); err != nil {
+		writePoolingError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
 
+// PublishAttestations adds the given attestations to the pool and gossips them to their subnets. Attestations failing
+// validation are reported in a *poolingError after all the others are published.
+func (a *ApiHandler) PublishAttestations(ctx context.Context, attestations []*solid.Attestation) error {
 	headState := a.syncedData.HeadState()
 	if headState == nil {
-		beaconhttp.NewEndpointError(http.StatusServiceUnavailable, errors.New("head state not available")).WriteTo(w)
-		return
+		return ErrNodeSyncing
 	}
 	failures := []poolingFailure{}
-	for i, attestation := range req {
+	for i, attestation := range attestations {
 		var (
 			slot                  = attestation.AttestantionData().Slot()
 			cIndex                = attestation.AttestantionData().CommitteeIndex()
 			committeeCountPerSlot = headState.CommitteeCount(slot / a.beaconChainCfg.SlotsPerEpoch)
 			subnet                = subnets.ComputeSubnetForAttestation(committeeCountPerSlot, slot, cIndex, a.beaconChainCfg.SlotsPerEpoch, a.netConfig.AttestationSubnetCount)
 		)
-		_ = i
-		if err := a.attestationService.ProcessMessage(r.Context(), &subnet, attestation); err != nil {
+		if err := a.attestationService.ProcessMessage(ctx, &subnet, attestation); err != nil {
 			log.Warn("[Beacon REST] failed to process attestation", "err", err)
 			failures = append(failures, poolingFailure{
 				Index:   i,
@@ -107,32 +117,18 @@ func (a *ApiHandler) PostEthV1BeaconPoolAttestations(w http.ResponseWriter, r *h
 		if a.sentinel != nil {
 			encodedSSZ, err := attestation.EncodeSSZ(nil)
 			if err != nil {
-				beaconhttp.NewEndpointError(http.StatusInternalServerError, err).WriteTo(w)
-				return
+				return err
 			}
-			if _, err := a.sentinel.PublishGossip(r.Context(), &sentinel.GossipData{
+			if _, err := a.sentinel.PublishGossip(ctx, &sentinel.GossipData{
 				Data:     encodedSSZ,
 				Name:     gossip.TopicNamePrefixBeaconAttestation,
 				SubnetId: &subnet,
 			}); err != nil {
-				beaconhttp.NewEndpointError(http.StatusInternalServerError, err).WriteTo(w)
-				return
+				return err
 			}
 		}
 	}
-	if len(failures) > 0 {
-		errResp := poolingError{
-			Code:     http.StatusBadRequest,
-			Message:  "some failures",
-			Failures: failures,
-		}
-		w.WriteHeader(http.StatusBadRequest)
-		if err := json.NewEncoder(w).Encode(errResp); err != nil {
-			log.Warn("failed to encode response", "err", err)
-		}
-		return
-	}
-	w.WriteHeader(http.StatusOK)
+	return newPoolingError(failures)
 }
 
 func (a *ApiHandler) PostEthV1BeaconPoolVoluntaryExits(w http.ResponseWriter, r *http.Request) {
@@ -235,6 +231,38 @@ type poolingError struct {
 	Failures []poolingFailure `json:"failures,omitempty"`
 }
 
+// newPoolingError returns the error reporting the given failures, or nil if there are none.
+func newPoolingError(failures []poolingFailure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	return &poolingError{Code: http.StatusBadRequest, Message: "some failures", Failures: failures}
+}
+
+func (e *poolingError) Error() string {
+	msgs := make([]string, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		msgs = append(msgs, fmt.Sprintf("%d: %s", f.Index, f.Message))
+	}
+	return fmt.Sprintf("%s: %s", e.Message, strings.Join(msgs, ", "))
+}
+
+// writePoolingError writes the response of a pool endpoint which failed with err.
+func writePoolingError(w http.ResponseWriter, err error) {
+	var poolErr *poolingError
+	switch {
+	case errors.As(err, &poolErr):
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(poolErr); err != nil {
+			log.Warn("failed to encode response", "err", err)
+		}
+	case errors.Is(err, ErrNodeSyncing):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func (a *ApiHandler) PostEthV1BeaconPoolBlsToExecutionChanges(w http.ResponseWriter, r *http.Request) {
 	req := []*cltypes.SignedBLSToExecutionChange{}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -280,10 +308,18 @@ func (a *ApiHandler) PostEthV1ValidatorAggregatesAndProof(w http.ResponseWriter,
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := a.PublishAggregateAndProofs(r.Context(), req); err != nil {
+		writePoolingError(w, err)
+		return
+	}
+}
 
+// PublishAggregateAndProofs adds the given aggregates to the pool and gossips them. Aggregates failing validation
+// are reported in a *poolingError after all the others are published.
+func (a *ApiHandler) PublishAggregateAndProofs(ctx context.Context, aggregates []*cltypes.SignedAggregateAndProof) error {
 	failures := []poolingFailure{}
-	for _, v := range req {
-		if err := a.aggregateAndProofsService.ProcessMessage(r.Context(), nil, v); err != nil && !errors.Is(err, services.ErrIgnore) {
+	for _, v := range aggregates {
+		if err := a.aggregateAndProofsService.ProcessMessage(ctx, nil, v); err != nil && !errors.Is(err, services.ErrIgnore) {
 			log.Warn("[Beacon REST] failed to process bls-change", "err", err)
 			failures = append(failures, poolingFailure{Index: len(failures), Message: err.Error()})
 			continue
@@ -292,20 +328,19 @@ func (a *ApiHandler) PostEthV1ValidatorAggregatesAndProof(w http.ResponseWriter,
 		if a.sentinel != nil {
 			encodedSSZ, err := v.EncodeSSZ(nil)
 			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
 				log.Warn("[Beacon REST] failed to encode aggregate and proof", "err", err)
-				return
+				return err
 			}
-			if _, err := a.sentinel.PublishGossip(r.Context(), &sentinel.GossipData{
+			if _, err := a.sentinel.PublishGossip(ctx, &sentinel.GossipData{
 				Data: encodedSSZ,
 				Name: gossip.TopicNameBeaconAggregateAndProof,
 			}); err != nil {
 				log.Warn("[Beacon REST] failed to publish gossip", "err", err)
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
+				return err
 			}
 		}
 	}
+	return newPoolingError(failures)
 }
 
 // PostEthV1BeaconPoolSyncCommittees is a handler for POST /eth/v1/beacon/pool/sync_committees.
@@ -316,10 +351,20 @@ func (a *ApiHandler) PostEthV1BeaconPoolSyncCommittees(w http.ResponseWriter, r
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := a.PublishSyncCommitteeMessages(r.Context(), msgs); err != nil {
+		writePoolingError(w, err)
+		return
+	}
+	// Only write 200
+	w.WriteHeader(http.StatusOK)
+}
+
+// PublishSyncCommitteeMessages adds the given sync committee messages to the pool and gossips them to their subnets.
+// Messages failing validation are reported in a *poolingError after all the others are published.
+func (a *ApiHandler) PublishSyncCommitteeMessages(ctx context.Context, msgs []*cltypes.SyncCommitteeMessage) error {
 	s := a.syncedData.HeadState()
 	if s == nil {
-		http.Error(w, "node is not synced", http.StatusServiceUnavailable)
-		return
+		return ErrNodeSyncing
 	}
 	failures := []poolingFailure{}
 	for idx, v := range msgs {
@@ -329,7 +374,7 @@ func (a *ApiHandler) PostEthV1BeaconPoolSyncCommittees(w http.ResponseWriter, r
 			continue
 		}
 		for _, subnet := range publishingSubnets {
-			if err = a.syncCommitteeMessagesService.ProcessMessage(r.Context(), &subnet, v); err != nil && !errors.Is(err, services.ErrIgnore) {
+			if err = a.syncCommitteeMessagesService.ProcessMessage(ctx, &subnet, v); err != nil && !errors.Is(err, services.ErrIgnore) {
 				log.Warn("[Beacon REST] failed to process attestation", "err", err)
 				failures = append(failures, poolingFailure{Index: idx, Message: err.Error()})
 				break
@@ -338,28 +383,20 @@ func (a *ApiHandler) PostEthV1BeaconPoolSyncCommittees(w http.ResponseWriter, r
 			if a.sentinel != nil {
 				encodedSSZ, err := v.EncodeSSZ(nil)
 				if err != nil {
-					http.Error(w, err.Error(), http.StatusInternalServerError)
-					return
+					return err
 				}
 				subnetId := subnet // this effectively makes a copy
-				if _, err := a.sentinel.PublishGossip(r.Context(), &sentinel.GossipData{
+				if _, err := a.sentinel.PublishGossip(ctx, &sentinel.GossipData{
 					Data:     encodedSSZ,
 					Name:     gossip.TopicNamePrefixSyncCommittee,
 					SubnetId: &subnetId,
 				}); err != nil {
-					http.Error(w, err.Error(), http.StatusInternalServerError)
-					return
+					return err
 				}
 			}
 		}
 	}
-	if len(failures) > 0 {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(poolingError{Code: http.StatusBadRequest, Message: "some failures", Failures: failures})
-		return
-	}
-	// Only write 200
-	w.WriteHeader(http.StatusOK)
+	return newPoolingError(failures)
 }
 
 // PostEthV1ValidatorContributionsAndProofs is a handler for POST /eth/v1/validator/contributions_and_proofs.
@@ -370,18 +407,27 @@ func (a *ApiHandler) PostEthV1ValidatorContributionsAndProofs(w http.ResponseWri
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := a.PublishContributionAndProofs(r.Context(), msgs); err != nil {
+		writePoolingError(w, err)
+		return
+	}
+	// Only write 200
+	w.WriteHeader(http.StatusOK)
+}
+
+// PublishContributionAndProofs adds the given sync committee contributions to the pool and gossips them. Contributions
+// failing validation are reported in a *poolingError after all the others are published.
+func (a *ApiHandler) PublishContributionAndProofs(ctx context.Context, msgs []*cltypes.SignedContributionAndProof) error {
 	s := a.syncedData.HeadState()
 	if s == nil {
-		http.Error(w, "node is not synced", http.StatusServiceUnavailable)
-		return
+		return ErrNodeSyncing
 	}
 	failures := []poolingFailure{}
-	var err error
 	for idx, v := range msgs {
 		if bytes.Equal(v.Message.Contribution.AggregationBits, make([]byte, len(v.Message.Contribution.AggregationBits))) {
 			continue // skip empty contributions
 		}
-		if err = a.syncContributionAndProofsService.ProcessMessage(r.Context(), nil, v); err != nil && !errors.Is(err, services.ErrIgnore) {
+		if err := a.syncContributionAndProofsService.ProcessMessage(ctx, nil, v); err != nil && !errors.Is(err, services.ErrIgnore) {
 			log.Warn("[Beacon REST] failed to process sync contribution", "err", err)
 			failures = append(failures, poolingFailure{Index: idx, Message: err.Error()})
 			continue
@@ -390,26 +436,17 @@ func (a *ApiHandler) PostEthV1ValidatorContributionsAndProofs(w http.ResponseWri
 		if a.sentinel != nil {
 			encodedSSZ, err := v.EncodeSSZ(nil)
 			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
 				log.Warn("[Beacon REST] failed to encode sync contribution", "err", err)
-				return
+				return err
 			}
-			if _, err := a.sentinel.PublishGossip(r.Context(), &sentinel.GossipData{
+			if _, err := a.sentinel.PublishGossip(ctx, &sentinel.GossipData{
 				Data: encodedSSZ,
 				Name: gossip.TopicNameSyncCommitteeContributionAndProof,
 			}); err != nil {
 				log.Warn("[Beacon REST] failed to publish gossip", "err", err)
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
+				return err
 			}
 		}
 	}
-
-	if len(failures) > 0 {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(poolingError{Code: http.StatusBadRequest, Message: "some failures", Failures: failures})
-		return
-	}
-	// Only write 200
-	w.WriteHeader(http.StatusOK)
+	return newPoolingError(failures)
 }
@@ -19,6 +19,7 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -51,10 +52,22 @@ func (a *ApiHandler) PostEthV1ValidatorSyncCommitteeSubscriptions(w http.Respons
 		w.WriteHeader(http.StatusOK)
 		return
 	}
+	if err := a.SubscribeToSyncCommittees(r.Context(), req); err != nil {
+		if errors.Is(err, ErrNodeSyncing) {
+			http.Error(w, "head state not available", http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// SubscribeToSyncCommittees subscribes to the sync committee subnets of the given validators until the requested epochs.
+func (a *ApiHandler) SubscribeToSyncCommittees(ctx context.Context, req []ValidatorSyncCommitteeSubscriptionsRequest) error {
 	headState := a.syncedData.HeadState()
 	if headState == nil {
-		http.Error(w, "head state not available", http.StatusServiceUnavailable)
-		return
+		return ErrNodeSyncing
 	}
 	var err error
 	// process each sub request
@@ -72,23 +85,21 @@ func (a *ApiHandler) PostEthV1ValidatorSyncCommitteeSubscriptions(w http.Respons
 		} else {
 			syncnets, err = subnets.ComputeSubnetsForSyncCommittee(headState, subRequest.ValidatorIndex)
 			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
+				return err
 			}
 		}
 
 		// subscribe to subnets
 		for _, subnet := range syncnets {
-			if _, err := a.sentinel.SetSubscribeExpiry(r.Context(), &sentinel.RequestSubscribeExpiry{
+			if _, err := a.sentinel.SetSubscribeExpiry(ctx, &sentinel.RequestSubscribeExpiry{
 				Topic:          gossip.TopicNameSyncCommittee(int(subnet)),
 				ExpiryUnixSecs: uint64(expiry.Unix()),
 			}); err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
+				return err
 			}
 		}
 	}
-	w.WriteHeader(http.StatusOK)
+	return nil
 }
 
 func (a *ApiHandler) PostEthV1ValidatorBeaconCommitteeSubscription(w http.ResponseWriter, r *http.Request) {
@@ -102,14 +113,22 @@ func (a *ApiHandler) PostEthV1ValidatorBeaconCommitteeSubscription(w http.Respon
 		http.Error(w, "empty request", http.StatusBadRequest)
 		return
 	}
+	if err := a.SubscribeToBeaconCommittees(context.Background(), req); err != nil {
+		log.Error("failed to add attestation subscription", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// SubscribeToBeaconCommittees subscribes to the attestation subnets of the given committees.
+func (a *ApiHandler) SubscribeToBeaconCommittees(ctx context.Context, req []*cltypes.BeaconCommitteeSubscription) error {
 	for _, sub := range req {
-		if err := a.committeeSub.AddAttestationSubscription(context.Background(), sub); err != nil {
-			log.Error("failed to add attestation subscription", "err", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+		if err := a.committeeSub.AddAttestationSubscription(ctx, sub); err != nil {
+			return err
 		}
 	}
-	w.WriteHeader(http.StatusOK)
+	return nil
 }
 
 func parseSyncCommitteeContribution(r *http.Request) (slot, subcommitteeIndex uint64, beaconBlockRoot common.Hash, err error) {
@@ -148,5 +167,11 @@ func (a *ApiHandler) GetEthV1ValidatorSyncCommitteeContribution(w http.ResponseW
 	if err != nil {
 		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
 	}
-	return newBeaconResponse(a.syncMessagePool.GetSyncContribution(slot, subCommitteeIndex, beaconBlockRoot)), nil
+	return newBeaconResponse(a.SyncCommitteeContribution(slot, subCommitteeIndex, beaconBlockRoot)), nil
+}
+
+// SyncCommitteeContribution returns the aggregate of the sync committee messages of a subcommittee for the given
+// slot and block root.
+func (a *ApiHandler) SyncCommitteeContribution(slot, subcommitteeIndex uint64, beaconBlockRoot common.Hash) *cltypes.Contribution {
+	return a.syncMessagePool.GetSyncContribution(slot, subcommitteeIndex, beaconBlockRoot)
 }
@@ -135,7 +135,7 @@ func setupTestingHandler(t *testing.T, v clparams.StateVersion, logger log.Logge
 		return nil
 	}).AnyTimes()
 
-	vp = validator_params.NewValidatorParams()
+	vp = validator_params.NewValidatorParams(db)
 	h = NewApiHandler(
 		logger,
 		&clparams.NetworkConfig{},
@@ -35,9 +35,14 @@ func (a *ApiHandler) PostEthV1ValidatorPrepareBeaconProposal(w http.ResponseWrit
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	a.PrepareBeaconProposers(req)
+	w.WriteHeader(http.StatusOK)
+}
+
+// PrepareBeaconProposers sets the fee recipients of the blocks produced for the given validators.
+func (a *ApiHandler) PrepareBeaconProposers(req []ValidatorPreparationPayload) {
 	for _, v := range req {
 		a.logger.Debug("[Caplin] Registred new validator", "index", v.ValidatorIndex, "fee_recipient", v.FeeRecipient.String())
 		a.validatorParams.SetFeeRecipient(v.ValidatorIndex, v.FeeRecipient)
 	}
-	w.WriteHeader(http.StatusOK)
 }
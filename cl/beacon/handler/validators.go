@@ -698,15 +698,38 @@ func (a *ApiHandler) GetEthV1ValidatorAggregateAttestation(w http.ResponseWriter
 		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, errors.WithMessage(err, "invalid slot"))
 	}
 
-	attDataRootHash := libcommon.HexToHash(attDataRoot)
-	att := a.aggregatePool.GetAggregatationByRoot(attDataRootHash)
+	att, err := a.AggregateAttestation(slotNum, libcommon.HexToHash(attDataRoot))
+	if err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(att), nil
+}
+
+// AggregateAttestation returns the aggregate in the pool of the attestations to the data with the given root.
+func (a *ApiHandler) AggregateAttestation(slot uint64, attestationDataRoot libcommon.Hash) (*solid.Attestation, error) {
+	att := a.aggregatePool.GetAggregatationByRoot(attestationDataRoot)
 	if att == nil {
-		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("attestation %s not found", attDataRoot))
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("attestation %s not found", attestationDataRoot))
 	}
-	if slotNum != att.AttestantionData().Slot() {
-		log.Debug("attestation slot does not match", "attestation_data_root", attDataRoot, "slot_inquire", slot)
+	if slot != att.AttestantionData().Slot() {
+		log.Debug("attestation slot does not match", "attestation_data_root", attestationDataRoot, "slot_inquire", slot)
 		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("attestation slot mismatch"))
 	}
+	return att, nil
+}
 
-	return newBeaconResponse(att), nil
+// ValidatorIndicies returns the indicies of the validators with the given public keys in the head state. Keys of
+// validators which are not part of the state yet are omitted.
+func (a *ApiHandler) ValidatorIndicies(pubkeys []libcommon.Bytes48) (map[libcommon.Bytes48]uint64, error) {
+	s := a.syncedData.HeadState()
+	if s == nil {
+		return nil, ErrNodeSyncing
+	}
+	indicies := make(map[libcommon.Bytes48]uint64, len(pubkeys))
+	for _, pubkey := range pubkeys {
+		if idx, ok := s.ValidatorIndexByPubkey(pubkey); ok {
+			indicies[pubkey] = idx
+		}
+	}
+	return indicies, nil
 }
@@ -0,0 +1,280 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package keymanager
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/validator/validator_client"
+	"github.com/ledgerwatch/erigon/cl/validator/validator_params"
+)
+
+// KeymanagerApi serves the standard Keymanager API for the built-in validator client.
+// Specs at: https://ethereum.github.io/keymanager-APIs/
+type KeymanagerApi struct {
+	mux             *chi.Mux
+	token           string
+	validatorClient *validator_client.ValidatorClient
+	validatorParams *validator_params.ValidatorParams
+}
+
+func NewKeymanagerApi(token string, validatorClient *validator_client.ValidatorClient, validatorParams *validator_params.ValidatorParams) *KeymanagerApi {
+	k := &KeymanagerApi{
+		token:           token,
+		validatorClient: validatorClient,
+		validatorParams: validatorParams,
+	}
+	k.init()
+	return k
+}
+
+func (k *KeymanagerApi) init() {
+	r := chi.NewRouter()
+	k.mux = r
+	r.Use(k.authenticate)
+	r.Route("/eth/v1", func(r chi.Router) {
+		r.Get("/keystores", beaconhttp.HandleEndpointFunc(k.listKeystores))
+		r.Post("/keystores", beaconhttp.HandleEndpointFunc(k.importKeystores))
+		r.Delete("/keystores", beaconhttp.HandleEndpointFunc(k.deleteKeystores))
+		r.Get("/remotekeys", beaconhttp.HandleEndpointFunc(k.listRemoteKeys))
+		r.Post("/remotekeys", beaconhttp.HandleEndpointFunc(k.importRemoteKeys))
+		r.Delete("/remotekeys", beaconhttp.HandleEndpointFunc(k.deleteRemoteKeys))
+		r.Route("/validator/{pubkey}", func(r chi.Router) {
+			r.Get("/feerecipient", beaconhttp.HandleEndpointFunc(k.getFeeRecipient))
+			r.Post("/feerecipient", k.setFeeRecipient)
+			r.Delete("/feerecipient", k.deleteFeeRecipient)
+			r.Get("/gas_limit", beaconhttp.HandleEndpointFunc(k.getGasLimit))
+			r.Post("/gas_limit", k.setGasLimit)
+			r.Delete("/gas_limit", k.deleteGasLimit)
+		})
+	})
+}
+
+func (k *KeymanagerApi) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	k.mux.ServeHTTP(w, r)
+}
+
+// authenticate rejects the requests which do not carry the API bearer token.
+func (k *KeymanagerApi) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			beaconhttp.NewEndpointError(http.StatusUnauthorized, errors.New("missing bearer token")).WriteTo(w)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(k.token)) != 1 {
+			beaconhttp.NewEndpointError(http.StatusForbidden, errors.New("invalid bearer token")).WriteTo(w)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (k *KeymanagerApi) listKeystores(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	return beaconhttp.NewBeaconResponse(k.validatorClient.ListKeystores()), nil
+}
+
+type importKeystoresRequest struct {
+	Keystores          []string `json:"keystores"`
+	Passwords          []string `json:"passwords"`
+	SlashingProtection string   `json:"slashing_protection,omitempty"`
+}
+
+func (k *KeymanagerApi) importKeystores(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	var req importKeystoresRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	keystores := make([][]byte, len(req.Keystores))
+	for i, keystore := range req.Keystores {
+		keystores[i] = []byte(keystore)
+	}
+	var slashingProtection io.Reader
+	if req.SlashingProtection != "" {
+		slashingProtection = strings.NewReader(req.SlashingProtection)
+	}
+	statuses, err := k.validatorClient.ImportKeystores(r.Context(), keystores, req.Passwords, slashingProtection)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	return beaconhttp.NewBeaconResponse(statuses), nil
+}
+
+type deleteKeysRequest struct {
+	Pubkeys []libcommon.Bytes48 `json:"pubkeys"`
+}
+
+func (k *KeymanagerApi) deleteKeystores(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	var req deleteKeysRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	statuses, slashingProtection, err := k.validatorClient.DeleteKeystores(r.Context(), req.Pubkeys)
+	if err != nil {
+		return nil, err
+	}
+	return beaconhttp.NewBeaconResponse(statuses).With("slashing_protection", string(bytes.TrimSpace(slashingProtection))), nil
+}
+
+func (k *KeymanagerApi) listRemoteKeys(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	return beaconhttp.NewBeaconResponse(k.validatorClient.ListRemoteKeys()), nil
+}
+
+type importRemoteKeysRequest struct {
+	RemoteKeys []validator_client.RemoteKeyInfo `json:"remote_keys"`
+}
+
+func (k *KeymanagerApi) importRemoteKeys(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	var req importRemoteKeysRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	return beaconhttp.NewBeaconResponse(k.validatorClient.ImportRemoteKeys(r.Context(), req.RemoteKeys)), nil
+}
+
+func (k *KeymanagerApi) deleteRemoteKeys(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	var req deleteKeysRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	return beaconhttp.NewBeaconResponse(k.validatorClient.DeleteRemoteKeys(r.Context(), req.Pubkeys)), nil
+}
+
+// pubkeyFromRequest parses the pubkey path parameter, which must belong to a validator held by the validator client.
+func (k *KeymanagerApi) pubkeyFromRequest(r *http.Request) (libcommon.Bytes48, error) {
+	var pubkey libcommon.Bytes48
+	if err := pubkey.UnmarshalText([]byte(chi.URLParam(r, "pubkey"))); err != nil {
+		return pubkey, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("invalid pubkey: %w", err))
+	}
+	if !k.validatorClient.HasValidator(pubkey) {
+		return pubkey, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("validator %s not found", pubkey))
+	}
+	return pubkey, nil
+}
+
+type feeRecipientResponse struct {
+	Pubkey     libcommon.Bytes48 `json:"pubkey"`
+	EthAddress libcommon.Address `json:"ethaddress"`
+}
+
+func (k *KeymanagerApi) getFeeRecipient(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	pubkey, err := k.pubkeyFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := k.validatorParams.ProposerConfig(r.Context(), pubkey)
+	if err != nil {
+		return nil, err
+	}
+	feeRecipient := cfg.FeeRecipient
+	if feeRecipient == (libcommon.Address{}) {
+		feeRecipient = k.validatorClient.DefaultFeeRecipient()
+	}
+	return beaconhttp.NewBeaconResponse(feeRecipientResponse{Pubkey: pubkey, EthAddress: feeRecipient}), nil
+}
+
+func (k *KeymanagerApi) setFeeRecipient(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		EthAddress libcommon.Address `json:"ethaddress"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		beaconhttp.NewEndpointError(http.StatusBadRequest, err).WriteTo(w)
+		return
+	}
+	if req.EthAddress == (libcommon.Address{}) {
+		beaconhttp.NewEndpointError(http.StatusBadRequest, errors.New("fee recipient can't be the zero address")).WriteTo(w)
+		return
+	}
+	k.updateProposerConfig(w, r, http.StatusAccepted, func(cfg *validator_params.ProposerConfig) {
+		cfg.FeeRecipient = req.EthAddress
+	})
+}
+
+func (k *KeymanagerApi) deleteFeeRecipient(w http.ResponseWriter, r *http.Request) {
+	k.updateProposerConfig(w, r, http.StatusNoContent, func(cfg *validator_params.ProposerConfig) {
+		cfg.FeeRecipient = libcommon.Address{}
+	})
+}
+
+type gasLimitResponse struct {
+	Pubkey   libcommon.Bytes48 `json:"pubkey"`
+	GasLimit uint64            `json:"gas_limit,string"`
+}
+
+func (k *KeymanagerApi) getGasLimit(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	pubkey, err := k.pubkeyFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := k.validatorParams.ProposerConfig(r.Context(), pubkey)
+	if err != nil {
+		return nil, err
+	}
+	gasLimit := cfg.GasLimit
+	if gasLimit == 0 {
+		gasLimit = validator_params.DefaultGasLimit
+	}
+	return beaconhttp.NewBeaconResponse(gasLimitResponse{Pubkey: pubkey, GasLimit: gasLimit}), nil
+}
+
+func (k *KeymanagerApi) setGasLimit(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		GasLimit uint64 `json:"gas_limit,string"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		beaconhttp.NewEndpointError(http.StatusBadRequest, err).WriteTo(w)
+		return
+	}
+	if req.GasLimit == 0 {
+		beaconhttp.NewEndpointError(http.StatusBadRequest, errors.New("gas limit can't be zero")).WriteTo(w)
+		return
+	}
+	k.updateProposerConfig(w, r, http.StatusAccepted, func(cfg *validator_params.ProposerConfig) {
+		cfg.GasLimit = req.GasLimit
+	})
+}
+
+func (k *KeymanagerApi) deleteGasLimit(w http.ResponseWriter, r *http.Request) {
+	k.updateProposerConfig(w, r, http.StatusNoContent, func(cfg *validator_params.ProposerConfig) {
+		cfg.GasLimit = 0
+	})
+}
+
+// updateProposerConfig applies update to the proposer config of the validator of the request, replying with successCode.
+func (k *KeymanagerApi) updateProposerConfig(w http.ResponseWriter, r *http.Request, successCode int, update func(cfg *validator_params.ProposerConfig)) {
+	pubkey, err := k.pubkeyFromRequest(r)
+	if err != nil {
+		err.(*beaconhttp.EndpointError).WriteTo(w)
+		return
+	}
+	if err := k.validatorParams.UpdateProposerConfig(r.Context(), pubkey, update); err != nil {
+		beaconhttp.WrapEndpointError(err).WriteTo(w)
+		return
+	}
+	w.WriteHeader(successCode)
+}
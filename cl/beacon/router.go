@@ -28,10 +28,12 @@ import (
 	"github.com/ledgerwatch/erigon-lib/log/v3"
 	"github.com/ledgerwatch/erigon/cl/beacon/beacon_router_configuration"
 	"github.com/ledgerwatch/erigon/cl/beacon/handler"
+	"github.com/ledgerwatch/erigon/cl/beacon/keymanager"
 )
 
 type LayeredBeaconHandler struct {
-	ArchiveApi *handler.ApiHandler
+	ArchiveApi    *handler.ApiHandler
+	KeymanagerApi *keymanager.KeymanagerApi // optional
 }
 
 func ListenAndServe(beaconHandler *LayeredBeaconHandler, routerCfg beacon_router_configuration.RouterConfiguration) error {
@@ -50,6 +52,20 @@ func ListenAndServe(beaconHandler *LayeredBeaconHandler, routerCfg beacon_router
 			MaxAge:           4,
 		}))
 
+	if beaconHandler.KeymanagerApi != nil {
+		for _, pattern := range []string{
+			"/eth/v1/keystores",
+			"/eth/v1/remotekeys",
+			"/eth/v1/validator/{pubkey}/feerecipient",
+			"/eth/v1/validator/{pubkey}/gas_limit",
+		} {
+			mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, chi.NewRouteContext()))
+				beaconHandler.KeymanagerApi.ServeHTTP(w, r)
+			})
+		}
+	}
+
 	mux.HandleFunc("/*", func(w http.ResponseWriter, r *http.Request) {
 		nfw := &notFoundNoWriter{ResponseWriter: w, r: r}
 		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, chi.NewRouteContext()))
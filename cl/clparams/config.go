@@ -42,12 +42,24 @@ type CaplinConfig struct {
 	// CaplinMeVRelayUrl is optional and is used to connect to the external builder service.
 	// If it's set, the node will start in builder mode
 	MevRelayUrl string
+	// Built-in validator client, enabled if ValidatorKeystoresDir or ValidatorRemoteSignerUrl is set.
+	ValidatorKeystoresDir    string
+	ValidatorPasswordsPath   string
+	ValidatorRemoteSignerUrl string
+	ValidatorFeeRecipient    string
+	ValidatorGraffiti        string
+	SlashingProtectionImport string
+	SlashingProtectionExport string
 }
 
 func (c CaplinConfig) RelayUrlExist() bool {
 	return c.MevRelayUrl != ""
 }
 
+func (c CaplinConfig) ValidatorClientEnabled() bool {
+	return c.ValidatorKeystoresDir != "" || c.ValidatorRemoteSignerUrl != ""
+}
+
 type NetworkType int
 
 const (
@@ -431,6 +443,8 @@ type BeaconChainConfig struct {
 	CapellaForkEpoch     uint64            `yaml:"CAPELLA_FORK_EPOCH" spec:"true" json:"CAPELLA_FORK_EPOCH,string"`     // CapellaForkEpoch is used to represent the assigned fork epoch for Capella.
 	DenebForkVersion     ConfigForkVersion `yaml:"DENEB_FORK_VERSION" spec:"true" json:"DENEB_FORK_VERSION"`            // DenebForkVersion is used to represent the fork version for Deneb.
 	DenebForkEpoch       uint64            `yaml:"DENEB_FORK_EPOCH" spec:"true" json:"DENEB_FORK_EPOCH,string"`         // DenebForkEpoch is used to represent the assigned fork epoch for Deneb.
+	ElectraForkVersion   ConfigForkVersion `yaml:"ELECTRA_FORK_VERSION" spec:"true" json:"ELECTRA_FORK_VERSION"`        // ElectraForkVersion is used to represent the fork version for Electra.
+	ElectraForkEpoch     uint64            `yaml:"ELECTRA_FORK_EPOCH" spec:"true" json:"ELECTRA_FORK_EPOCH,string"`     // ElectraForkEpoch is used to represent the assigned fork epoch for Electra.
 
 	ForkVersionSchedule map[libcommon.Bytes4]uint64 `json:"-"` // Schedule of fork epochs by version.
 	ForkVersionNames    map[libcommon.Bytes4]string `json:"-"` // Human-readable names of fork versions.
@@ -484,6 +498,25 @@ type BeaconChainConfig struct {
 
 	MaxBlobGasPerBlock uint64 `yaml:"MAX_BLOB_GAS_PER_BLOCK" json:"MAX_BLOB_GAS_PER_BLOCK,string"` // MaxBlobGasPerBlock defines the maximum gas limit for blob sidecar per block.
 	MaxBlobsPerBlock   uint64 `yaml:"MAX_BLOBS_PER_BLOCK" json:"MAX_BLOBS_PER_BLOCK,string"`       // MaxBlobsPerBlock defines the maximum number of blobs per block.
+
+	// Electra, as of consensus-specs v1.5.0-alpha.3: devnet-only, it's not the final Electra and isn't scheduled on any network
+	MinActivationBalance                  uint64     `yaml:"MIN_ACTIVATION_BALANCE" spec:"true" json:"MIN_ACTIVATION_BALANCE,string"`                                         // MinActivationBalance is the minimum balance required to activate a validator.
+	MaxEffectiveBalanceElectra            uint64     `yaml:"MAX_EFFECTIVE_BALANCE_ELECTRA" spec:"true" json:"MAX_EFFECTIVE_BALANCE_ELECTRA,string"`                           // MaxEffectiveBalanceElectra is the maximal effective balance of a validator with compounding credentials.
+	MinSlashingPenaltyQuotientElectra     uint64     `yaml:"MIN_SLASHING_PENALTY_QUOTIENT_ELECTRA" spec:"true" json:"MIN_SLASHING_PENALTY_QUOTIENT_ELECTRA,string"`           // MinSlashingPenaltyQuotientElectra for slashing penalties post Electra hard fork.
+	WhistleBlowerRewardQuotientElectra    uint64     `yaml:"WHISTLEBLOWER_REWARD_QUOTIENT_ELECTRA" spec:"true" json:"WHISTLEBLOWER_REWARD_QUOTIENT_ELECTRA,string"`           // WhistleBlowerRewardQuotientElectra is used to calculate whistle blower reward post Electra hard fork.
+	PendingBalanceDepositsLimit           uint64     `yaml:"PENDING_BALANCE_DEPOSITS_LIMIT" spec:"true" json:"PENDING_BALANCE_DEPOSITS_LIMIT,string"`                         // PendingBalanceDepositsLimit is the maximum number of pending balance deposits in the state.
+	PendingPartialWithdrawalsLimit        uint64     `yaml:"PENDING_PARTIAL_WITHDRAWALS_LIMIT" spec:"true" json:"PENDING_PARTIAL_WITHDRAWALS_LIMIT,string"`                   // PendingPartialWithdrawalsLimit is the maximum number of pending partial withdrawals in the state.
+	PendingConsolidationsLimit            uint64     `yaml:"PENDING_CONSOLIDATIONS_LIMIT" spec:"true" json:"PENDING_CONSOLIDATIONS_LIMIT,string"`                             // PendingConsolidationsLimit is the maximum number of pending consolidations in the state.
+	MaxAttesterSlashingsElectra           uint64     `yaml:"MAX_ATTESTER_SLASHINGS_ELECTRA" spec:"true" json:"MAX_ATTESTER_SLASHINGS_ELECTRA,string"`                         // MaxAttesterSlashingsElectra defines the maximum number of attester slashings in a block post Electra.
+	MaxAttestationsElectra                uint64     `yaml:"MAX_ATTESTATIONS_ELECTRA" spec:"true" json:"MAX_ATTESTATIONS_ELECTRA,string"`                                     // MaxAttestationsElectra defines the maximum number of attestations in a block post Electra.
+	MaxDepositRequestsPerPayload          uint64     `yaml:"MAX_DEPOSIT_REQUESTS_PER_PAYLOAD" spec:"true" json:"MAX_DEPOSIT_REQUESTS_PER_PAYLOAD,string"`                     // MaxDepositRequestsPerPayload defines the maximum number of deposit requests in an execution payload.
+	MaxWithdrawalRequestsPerPayload       uint64     `yaml:"MAX_WITHDRAWAL_REQUESTS_PER_PAYLOAD" spec:"true" json:"MAX_WITHDRAWAL_REQUESTS_PER_PAYLOAD,string"`               // MaxWithdrawalRequestsPerPayload defines the maximum number of withdrawal requests in an execution payload.
+	MaxConsolidationRequestsPerPayload    uint64     `yaml:"MAX_CONSOLIDATION_REQUESTS_PER_PAYLOAD" spec:"true" json:"MAX_CONSOLIDATION_REQUESTS_PER_PAYLOAD,string"`         // MaxConsolidationRequestsPerPayload defines the maximum number of consolidation requests in an execution payload.
+	MaxPendingPartialsPerWithdrawalsSweep uint64     `yaml:"MAX_PENDING_PARTIALS_PER_WITHDRAWALS_SWEEP" spec:"true" json:"MAX_PENDING_PARTIALS_PER_WITHDRAWALS_SWEEP,string"` // MaxPendingPartialsPerWithdrawalsSweep bounds the number of pending partial withdrawals processed per slot.
+	MinPerEpochChurnLimitElectra          uint64     `yaml:"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA" spec:"true" json:"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA,string"`                   // MinPerEpochChurnLimitElectra is the minimum balance churn per epoch post Electra.
+	MaxPerEpochActivationExitChurnLimit   uint64     `yaml:"MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT" spec:"true" json:"MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT,string"`   // MaxPerEpochActivationExitChurnLimit is the maximum balance churn per epoch for activations and exits post Electra.
+	CompoundingWithdrawalPrefix           ConfigByte `yaml:"COMPOUNDING_WITHDRAWAL_PREFIX" spec:"true" json:"COMPOUNDING_WITHDRAWAL_PREFIX"`                                  // CompoundingWithdrawalPrefix is the withdrawal credentials prefix of compounding validators.
+	UnsetDepositRequestsStartIndex        uint64     `yaml:"UNSET_DEPOSIT_REQUESTS_START_INDEX" json:"UNSET_DEPOSIT_REQUESTS_START_INDEX,string"`                             // UnsetDepositRequestsStartIndex marks that no deposit request has been processed yet.
 }
 
 func (b *BeaconChainConfig) RoundSlotToEpoch(slot uint64) uint64 {
@@ -505,7 +538,7 @@ func (b *BeaconChainConfig) RoundSlotToVotePeriod(slot uint64) uint64 {
 }
 
 func (b *BeaconChainConfig) GetCurrentStateVersion(epoch uint64) StateVersion {
-	forkEpochList := []uint64{b.AltairForkEpoch, b.BellatrixForkEpoch, b.CapellaForkEpoch, b.DenebForkEpoch}
+	forkEpochList := []uint64{b.AltairForkEpoch, b.BellatrixForkEpoch, b.CapellaForkEpoch, b.DenebForkEpoch, b.ElectraForkEpoch}
 	stateVersion := Phase0Version
 	for _, forkEpoch := range forkEpochList {
 		if forkEpoch > epoch {
@@ -529,6 +562,7 @@ func configForkSchedule(b *BeaconChainConfig) map[libcommon.Bytes4]uint64 {
 	fvs[utils.Uint32ToBytes4(uint32(b.BellatrixForkVersion))] = b.BellatrixForkEpoch
 	fvs[utils.Uint32ToBytes4(uint32(b.CapellaForkVersion))] = b.CapellaForkEpoch
 	fvs[utils.Uint32ToBytes4(uint32(b.DenebForkVersion))] = b.DenebForkEpoch
+	fvs[utils.Uint32ToBytes4(uint32(b.ElectraForkVersion))] = b.ElectraForkEpoch
 	return fvs
 }
 
@@ -539,6 +573,7 @@ func configForkNames(b *BeaconChainConfig) map[libcommon.Bytes4]string {
 	fvn[utils.Uint32ToBytes4(uint32(b.BellatrixForkVersion))] = "bellatrix"
 	fvn[utils.Uint32ToBytes4(uint32(b.CapellaForkVersion))] = "capella"
 	fvn[utils.Uint32ToBytes4(uint32(b.DenebForkVersion))] = "deneb"
+	fvn[utils.Uint32ToBytes4(uint32(b.ElectraForkVersion))] = "electra"
 	return fvn
 }
 
@@ -676,6 +711,8 @@ var MainnetBeaconConfig BeaconChainConfig = BeaconChainConfig{
 	CapellaForkEpoch:     194048,
 	DenebForkVersion:     0x04000000,
 	DenebForkEpoch:       269568,
+	ElectraForkVersion:   0x05000000,
+	ElectraForkEpoch:     math.MaxUint64, // v1.5.0-alpha.3 Electra, devnet-only
 
 	// New values introduced in Altair hard fork 1.
 	// Participation flag indices.
@@ -724,6 +761,25 @@ var MainnetBeaconConfig BeaconChainConfig = BeaconChainConfig{
 
 	MaxBlobGasPerBlock: 786432,
 	MaxBlobsPerBlock:   6,
+
+	// Electra
+	MinActivationBalance:                  32 * 1e9,
+	MaxEffectiveBalanceElectra:            2048 * 1e9,
+	MinSlashingPenaltyQuotientElectra:     4096,
+	WhistleBlowerRewardQuotientElectra:    4096,
+	PendingBalanceDepositsLimit:           1 << 27,
+	PendingPartialWithdrawalsLimit:        1 << 27,
+	PendingConsolidationsLimit:            1 << 18,
+	MaxAttesterSlashingsElectra:           1,
+	MaxAttestationsElectra:                8,
+	MaxDepositRequestsPerPayload:          8192,
+	MaxWithdrawalRequestsPerPayload:       16,
+	MaxConsolidationRequestsPerPayload:    1,
+	MaxPendingPartialsPerWithdrawalsSweep: 8,
+	MinPerEpochChurnLimitElectra:          128 * 1e9,
+	MaxPerEpochActivationExitChurnLimit:   256 * 1e9,
+	CompoundingWithdrawalPrefix:           ConfigByte(2),
+	UnsetDepositRequestsStartIndex:        math.MaxUint64,
 }
 
 func mainnetConfig() BeaconChainConfig {
@@ -762,6 +818,7 @@ func sepoliaConfig() BeaconChainConfig {
 	cfg.CapellaForkVersion = 0x90000072
 	cfg.DenebForkEpoch = 132608
 	cfg.DenebForkVersion = 0x90000073
+	cfg.ElectraForkVersion = 0x90000074
 	cfg.TerminalTotalDifficulty = "17000000000000000"
 	cfg.DepositContractAddress = "0x7f02C3E3c98b133055B8B348B2Ac625669Ed295D"
 	cfg.InitializeForkSchedule()
@@ -788,6 +845,7 @@ func holeskyConfig() BeaconChainConfig {
 	cfg.CapellaForkVersion = 0x04017000
 	cfg.DenebForkEpoch = 29696
 	cfg.DenebForkVersion = 0x05017000
+	cfg.ElectraForkVersion = 0x06017000
 	cfg.TerminalTotalDifficulty = "0"
 	cfg.TerminalBlockHash = [32]byte{}
 	cfg.TerminalBlockHashActivationEpoch = math.MaxUint64
@@ -833,6 +891,7 @@ func gnosisConfig() BeaconChainConfig {
 	cfg.EpochsPerSyncCommitteePeriod = 512
 	cfg.DenebForkEpoch = 889856
 	cfg.DenebForkVersion = 0x04000064
+	cfg.ElectraForkVersion = 0x05000064
 	cfg.InactivityScoreRecoveryRate = 16
 	cfg.InactivityScoreBias = 4
 	cfg.MaxWithdrawalsPerPayload = 8
@@ -863,6 +922,7 @@ func chiadoConfig() BeaconChainConfig {
 	cfg.CapellaForkVersion = 0x0300006f
 	cfg.DenebForkEpoch = 516608
 	cfg.DenebForkVersion = 0x0400006f
+	cfg.ElectraForkVersion = 0x0500006f
 	cfg.TerminalTotalDifficulty = "231707791542740786049188744689299064356246512"
 	cfg.DepositContractAddress = "0xb97036A26259B7147018913bD58a774cf91acf25"
 	cfg.BaseRewardFactor = 25
@@ -885,6 +945,8 @@ func (b *BeaconChainConfig) GetMinSlashingPenaltyQuotient(version StateVersion)
 		return b.MinSlashingPenaltyQuotientBellatrix
 	case DenebVersion:
 		return b.MinSlashingPenaltyQuotientBellatrix
+	case ElectraVersion:
+		return b.MinSlashingPenaltyQuotientElectra
 	default:
 		panic("not implemented")
 	}
@@ -900,13 +962,28 @@ func (b *BeaconChainConfig) GetPenaltyQuotient(version StateVersion) uint64 {
 		return b.InactivityPenaltyQuotientBellatrix
 	case CapellaVersion:
 		return b.InactivityPenaltyQuotientBellatrix
-	case DenebVersion:
+	case DenebVersion, ElectraVersion:
 		return b.InactivityPenaltyQuotientBellatrix
 	default:
 		panic("not implemented")
 	}
 }
 
+func (b *BeaconChainConfig) GetWhistleBlowerRewardQuotient(version StateVersion) uint64 {
+	if version >= ElectraVersion {
+		return b.WhistleBlowerRewardQuotientElectra
+	}
+	return b.WhistleBlowerRewardQuotient
+}
+
+// GetMaxEffectiveBalance returns the maximum effective balance used for proposer and sync committee selection.
+func (b *BeaconChainConfig) GetMaxEffectiveBalance(version StateVersion) uint64 {
+	if version >= ElectraVersion {
+		return b.MaxEffectiveBalanceElectra
+	}
+	return b.MaxEffectiveBalance
+}
+
 // Beacon configs
 var BeaconConfigs map[NetworkType]BeaconChainConfig = map[NetworkType]BeaconChainConfig{
 	MainnetNetwork: mainnetConfig(),
@@ -948,6 +1025,8 @@ func (b *BeaconChainConfig) GetForkVersionByVersion(v StateVersion) uint32 {
 		return uint32(b.CapellaForkVersion)
 	case DenebVersion:
 		return uint32(b.DenebForkVersion)
+	case ElectraVersion:
+		return uint32(b.ElectraForkVersion)
 	}
 	panic("invalid version")
 }
@@ -964,6 +1043,8 @@ func (b *BeaconChainConfig) GetForkEpochByVersion(v StateVersion) uint64 {
 		return b.CapellaForkEpoch
 	case DenebVersion:
 		return b.DenebForkEpoch
+	case ElectraVersion:
+		return b.ElectraForkEpoch
 	}
 	panic("invalid version")
 }
@@ -46,6 +46,9 @@ const (
 	MaxVoluntaryExits            = 16
 	MaxExecutionChanges          = 16
 	MaxBlobsCommittmentsPerBlock = 4096
+
+	MaxAttesterSlashingsElectra = 1
+	MaxAttestationsElectra      = 8
 )
 
 type SignedBeaconBlock struct {
@@ -176,6 +179,35 @@ func (b *BeaconBlock) SetVersion(version clparams.StateVersion) {
 func (b *BeaconBody) SetVersion(version clparams.StateVersion) {
 	b.Version = version
 	b.ExecutionPayload.SetVersion(version)
+	b.setOperationsLimits()
+}
+
+// setOperationsLimits re-creates the attester slashings and attestations lists with the limits of the body version.
+func (b *BeaconBody) setOperationsLimits() {
+	b.AttesterSlashings, b.Attestations = operationsWithLimits(b.Version, b.AttesterSlashings, b.Attestations)
+}
+
+// operationsWithLimits copies the attester slashings and attestations into lists bounded by the limits of the given version.
+func operationsWithLimits(version clparams.StateVersion, slashings *solid.ListSSZ[*AttesterSlashing], atts *solid.ListSSZ[*solid.Attestation]) (*solid.ListSSZ[*AttesterSlashing], *solid.ListSSZ[*solid.Attestation]) {
+	maxAttesterSlashings, maxAttestations := MaxAttesterSlashings, MaxAttestations
+	if version >= clparams.ElectraVersion {
+		maxAttesterSlashings, maxAttestations = MaxAttesterSlashingsElectra, MaxAttestationsElectra
+	}
+	attesterSlashings := solid.NewDynamicListSSZ[*AttesterSlashing](maxAttesterSlashings)
+	if slashings != nil {
+		slashings.Range(func(_ int, s *AttesterSlashing, _ int) bool {
+			attesterSlashings.Append(s)
+			return true
+		})
+	}
+	attestations := solid.NewDynamicListSSZ[*solid.Attestation](maxAttestations)
+	if atts != nil {
+		atts.Range(func(_ int, a *solid.Attestation, _ int) bool {
+			attestations.Append(a)
+			return true
+		})
+	}
+	return attesterSlashings, attestations
 }
 
 func (b *BeaconBody) EncodeSSZ(dst []byte) ([]byte, error) {
@@ -242,6 +274,7 @@ func (b *BeaconBody) DecodeSSZ(buf []byte, version int) error {
 	}
 
 	b.ExecutionPayload = NewEth1Block(b.Version, b.beaconCfg)
+	b.setOperationsLimits()
 
 	err := ssz2.UnmarshalSSZ(buf, version, b.getSchema(false)...)
 	return err
@@ -396,6 +429,7 @@ func (b *BeaconBody) UnmarshalJSON(buf []byte) error {
 	b.ExecutionPayload = tmp.ExecutionPayload
 	b.ExecutionChanges = tmp.ExecutionChanges
 	b.BlobKzgCommitments = tmp.BlobKzgCommitments
+	b.setOperationsLimits()
 	return nil
 }
 
@@ -163,6 +163,7 @@ func (b *BlindedBeaconBody) SetVersion(version clparams.StateVersion) *BlindedBe
 	} else {
 		b.ExecutionPayload.SetVersion(version)
 	}
+	b.AttesterSlashings, b.Attestations = operationsWithLimits(version, b.AttesterSlashings, b.Attestations)
 	return b
 }
 
@@ -232,6 +233,7 @@ func (b *BlindedBeaconBody) DecodeSSZ(buf []byte, version int) error {
 	}
 
 	b.ExecutionPayload = NewEth1Header(b.Version)
+	b.AttesterSlashings, b.Attestations = operationsWithLimits(b.Version, b.AttesterSlashings, b.Attestations)
 
 	err := ssz2.UnmarshalSSZ(buf, version, b.getSchema(false)...)
 	return err
@@ -0,0 +1,104 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package cltypes
+
+import (
+	"fmt"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/types/clonable"
+
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	ssz2 "github.com/ledgerwatch/erigon/cl/ssz"
+)
+
+// DepositSnapshot is the EIP-4881 snapshot of the finalized part of the deposit tree.
+type DepositSnapshot struct {
+	Finalized            solid.HashListSSZ `json:"finalized"`
+	DepositRoot          libcommon.Hash    `json:"deposit_root"`
+	DepositCount         uint64            `json:"deposit_count,string"`
+	ExecutionBlockHash   libcommon.Hash    `json:"execution_block_hash"`
+	ExecutionBlockHeight uint64            `json:"execution_block_height,string"`
+}
+
+// NewDepositSnapshot takes the snapshot of the finalized deposits of tree, finalized at the given execution block.
+func NewDepositSnapshot(tree *merkle_tree.DepositTree, executionBlockHash libcommon.Hash, executionBlockHeight uint64) (*DepositSnapshot, error) {
+	finalized, depositCount := tree.Finalized()
+	finalizedTree, err := merkle_tree.NewDepositTreeFromSnapshot(finalized, depositCount)
+	if err != nil {
+		return nil, err
+	}
+	d := &DepositSnapshot{
+		Finalized:            solid.NewHashList(merkle_tree.DepositContractDepth),
+		DepositRoot:          finalizedTree.Root(),
+		DepositCount:         depositCount,
+		ExecutionBlockHash:   executionBlockHash,
+		ExecutionBlockHeight: executionBlockHeight,
+	}
+	for _, root := range finalized {
+		d.Finalized.Append(root)
+	}
+	return d, nil
+}
+
+// DepositTree rebuilds the deposit tree from the snapshot, checking it against the snapshot deposit root.
+func (d *DepositSnapshot) DepositTree() (*merkle_tree.DepositTree, error) {
+	finalized := make([]libcommon.Hash, 0, d.Finalized.Length())
+	d.Finalized.Range(func(_ int, root libcommon.Hash, _ int) bool {
+		finalized = append(finalized, root)
+		return true
+	})
+	tree, err := merkle_tree.NewDepositTreeFromSnapshot(finalized, d.DepositCount)
+	if err != nil {
+		return nil, err
+	}
+	if root := tree.Root(); root != d.DepositRoot {
+		return nil, fmt.Errorf("deposit snapshot root mismatch: expected %x, got %x", d.DepositRoot, root)
+	}
+	return tree, nil
+}
+
+func (d *DepositSnapshot) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, d.getSchema()...)
+}
+
+func (d *DepositSnapshot) DecodeSSZ(buf []byte, version int) error {
+	d.Finalized = solid.NewHashList(merkle_tree.DepositContractDepth)
+	return ssz2.UnmarshalSSZ(buf, version, d.getSchema()...)
+}
+
+func (d *DepositSnapshot) EncodingSizeSSZ() int {
+	size := 4 + length.Hash*2 + 8*2
+	if d.Finalized != nil {
+		size += d.Finalized.EncodingSizeSSZ()
+	}
+	return size
+}
+
+func (d *DepositSnapshot) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(d.getSchema()...)
+}
+
+func (*DepositSnapshot) Clone() clonable.Clonable {
+	return &DepositSnapshot{}
+}
+
+func (d *DepositSnapshot) getSchema() []interface{} {
+	return []interface{}{d.Finalized, d.DepositRoot[:], &d.DepositCount, d.ExecutionBlockHash[:], &d.ExecutionBlockHeight}
+}
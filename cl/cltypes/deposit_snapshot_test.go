@@ -0,0 +1,58 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package cltypes_test
+
+import (
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+)
+
+func TestDepositSnapshot(t *testing.T) {
+	tree := merkle_tree.NewDepositTree()
+	for i := 0; i < 11; i++ {
+		require.NoError(t, tree.PushLeaf(libcommon.Hash{byte(i + 1)}))
+	}
+	require.NoError(t, tree.Finalize(7))
+	snapshot, err := cltypes.NewDepositSnapshot(tree, libcommon.HexToHash("0x2"), 100)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), snapshot.DepositCount)
+	require.Equal(t, 3, snapshot.Finalized.Length())
+
+	encoded, err := snapshot.EncodeSSZ(nil)
+	require.NoError(t, err)
+	require.Len(t, encoded, snapshot.EncodingSizeSSZ())
+	decoded := &cltypes.DepositSnapshot{}
+	require.NoError(t, decoded.DecodeSSZ(encoded, 0))
+	require.Equal(t, snapshot, decoded)
+
+	// the rebuilt tree is checked against the deposit root and can keep growing
+	rebuilt, err := decoded.DepositTree()
+	require.NoError(t, err)
+	for i := 7; i < 11; i++ {
+		require.NoError(t, rebuilt.PushLeaf(libcommon.Hash{byte(i + 1)}))
+	}
+	require.Equal(t, tree.Root(), rebuilt.Root())
+
+	decoded.DepositRoot = libcommon.Hash{}
+	_, err = decoded.DepositTree()
+	require.Error(t, err)
+}
@@ -54,6 +54,10 @@ type Eth1Block struct {
 	Withdrawals   *solid.ListSSZ[*Withdrawal] `json:"withdrawals,omitempty"`
 	BlobGasUsed   uint64                      `json:"blob_gas_used,string"`
 	ExcessBlobGas uint64                      `json:"excess_blob_gas,string"`
+	// Electra
+	DepositRequests       *solid.ListSSZ[*DepositRequest]       `json:"deposit_requests,omitempty"`
+	WithdrawalRequests    *solid.ListSSZ[*WithdrawalRequest]    `json:"withdrawal_requests,omitempty"`
+	ConsolidationRequests *solid.ListSSZ[*ConsolidationRequest] `json:"consolidation_requests,omitempty"`
 	// internals
 	version   clparams.StateVersion
 	beaconCfg *clparams.BeaconChainConfig
@@ -94,10 +98,17 @@ func NewEth1BlockFromHeaderAndBody(header *types.Header, body *types.RawBody, be
 		beaconCfg:     beaconCfg,
 	}
 
+	if header.RequestsRoot != nil {
+		block.SetRequests(body.Requests)
+	}
+
 	if header.BlobGasUsed != nil && header.ExcessBlobGas != nil {
 		block.BlobGasUsed = *header.BlobGasUsed
 		block.ExcessBlobGas = *header.ExcessBlobGas
 		block.version = clparams.DenebVersion
+		if header.RequestsRoot != nil {
+			block.version = clparams.ElectraVersion
+		}
 	} else if header.WithdrawalsHash != nil {
 		block.version = clparams.CapellaVersion
 	} else {
@@ -106,6 +117,14 @@ func NewEth1BlockFromHeaderAndBody(header *types.Header, body *types.RawBody, be
 	return block
 }
 
+// SetRequests fills the deposit, withdrawal and consolidation request lists from the execution layer requests.
+func (b *Eth1Block) SetRequests(requests types.Requests) {
+	deposits, withdrawals, consolidations := convertExecutionRequestsToConsensusRequests(requests)
+	b.DepositRequests = solid.NewStaticListSSZFromList(deposits, int(b.beaconCfg.MaxDepositRequestsPerPayload), DepositRequestSize)
+	b.WithdrawalRequests = solid.NewStaticListSSZFromList(withdrawals, int(b.beaconCfg.MaxWithdrawalRequestsPerPayload), WithdrawalRequestSize)
+	b.ConsolidationRequests = solid.NewStaticListSSZFromList(consolidations, int(b.beaconCfg.MaxConsolidationRequestsPerPayload), ConsolidationRequestSize)
+}
+
 func (b *Eth1Block) SetVersion(version clparams.StateVersion) {
 	b.version = version
 }
@@ -133,6 +152,10 @@ func (b *Eth1Block) MarshalJSON() ([]byte, error) {
 		Withdrawals   *solid.ListSSZ[*Withdrawal] `json:"withdrawals,omitempty"`
 		BlobGasUsed   uint64                      `json:"blob_gas_used,string"`
 		ExcessBlobGas uint64                      `json:"excess_blob_gas,string"`
+
+		DepositRequests       *solid.ListSSZ[*DepositRequest]       `json:"deposit_requests,omitempty"`
+		WithdrawalRequests    *solid.ListSSZ[*WithdrawalRequest]    `json:"withdrawal_requests,omitempty"`
+		ConsolidationRequests *solid.ListSSZ[*ConsolidationRequest] `json:"consolidation_requests,omitempty"`
 	}{
 		ParentHash:    b.ParentHash,
 		FeeRecipient:  b.FeeRecipient,
@@ -151,6 +174,10 @@ func (b *Eth1Block) MarshalJSON() ([]byte, error) {
 		Withdrawals:   b.Withdrawals,
 		BlobGasUsed:   b.BlobGasUsed,
 		ExcessBlobGas: b.ExcessBlobGas,
+
+		DepositRequests:       b.DepositRequests,
+		WithdrawalRequests:    b.WithdrawalRequests,
+		ConsolidationRequests: b.ConsolidationRequests,
 	})
 }
 
@@ -173,8 +200,13 @@ func (b *Eth1Block) UnmarshalJSON(data []byte) error {
 		Withdrawals   *solid.ListSSZ[*Withdrawal] `json:"withdrawals"`
 		BlobGasUsed   uint64                      `json:"blob_gas_used,string"`
 		ExcessBlobGas uint64                      `json:"excess_blob_gas,string"`
+
+		DepositRequests       *solid.ListSSZ[*DepositRequest]       `json:"deposit_requests"`
+		WithdrawalRequests    *solid.ListSSZ[*WithdrawalRequest]    `json:"withdrawal_requests"`
+		ConsolidationRequests *solid.ListSSZ[*ConsolidationRequest] `json:"consolidation_requests"`
 	}
 	aux.Withdrawals = solid.NewStaticListSSZ[*Withdrawal](int(b.beaconCfg.MaxWithdrawalsPerPayload), 44)
+	aux.DepositRequests, aux.WithdrawalRequests, aux.ConsolidationRequests = b.newRequestLists()
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
@@ -201,6 +233,11 @@ func (b *Eth1Block) UnmarshalJSON(data []byte) error {
 	b.Withdrawals = aux.Withdrawals
 	b.BlobGasUsed = aux.BlobGasUsed
 	b.ExcessBlobGas = aux.ExcessBlobGas
+	if b.version >= clparams.ElectraVersion {
+		b.DepositRequests = aux.DepositRequests
+		b.WithdrawalRequests = aux.WithdrawalRequests
+		b.ConsolidationRequests = aux.ConsolidationRequests
+	}
 	return nil
 }
 
@@ -227,6 +264,20 @@ func (b *Eth1Block) PayloadHeader() (*Eth1Header, error) {
 		excessBlobGas = b.ExcessBlobGas
 	}
 
+	var depositRequestsRoot, withdrawalRequestsRoot, consolidationRequestsRoot libcommon.Hash
+	if b.version >= clparams.ElectraVersion {
+		b.initRequestLists()
+		if depositRequestsRoot, err = b.DepositRequests.HashSSZ(); err != nil {
+			return nil, err
+		}
+		if withdrawalRequestsRoot, err = b.WithdrawalRequests.HashSSZ(); err != nil {
+			return nil, err
+		}
+		if consolidationRequestsRoot, err = b.ConsolidationRequests.HashSSZ(); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Eth1Header{
 		ParentHash:       b.ParentHash,
 		FeeRecipient:     b.FeeRecipient,
@@ -245,7 +296,11 @@ func (b *Eth1Block) PayloadHeader() (*Eth1Header, error) {
 		WithdrawalsRoot:  withdrawalsRoot,
 		BlobGasUsed:      blobGasUsed,
 		ExcessBlobGas:    excessBlobGas,
-		version:          b.version,
+
+		DepositRequestsRoot:       depositRequestsRoot,
+		WithdrawalRequestsRoot:    withdrawalRequestsRoot,
+		ConsolidationRequestsRoot: consolidationRequestsRoot,
+		version:                   b.version,
 	}, nil
 }
 
@@ -271,14 +326,42 @@ func (b *Eth1Block) EncodingSizeSSZ() (size int) {
 		size += 8 * 2 // BlobGasUsed + ExcessBlobGas
 	}
 
+	if b.version >= clparams.ElectraVersion {
+		b.initRequestLists()
+		size += b.DepositRequests.EncodingSizeSSZ() + 4
+		size += b.WithdrawalRequests.EncodingSizeSSZ() + 4
+		size += b.ConsolidationRequests.EncodingSizeSSZ() + 4
+	}
+
 	return
 }
 
+func (b *Eth1Block) newRequestLists() (*solid.ListSSZ[*DepositRequest], *solid.ListSSZ[*WithdrawalRequest], *solid.ListSSZ[*ConsolidationRequest]) {
+	return solid.NewStaticListSSZ[*DepositRequest](int(b.beaconCfg.MaxDepositRequestsPerPayload), DepositRequestSize),
+		solid.NewStaticListSSZ[*WithdrawalRequest](int(b.beaconCfg.MaxWithdrawalRequestsPerPayload), WithdrawalRequestSize),
+		solid.NewStaticListSSZ[*ConsolidationRequest](int(b.beaconCfg.MaxConsolidationRequestsPerPayload), ConsolidationRequestSize)
+}
+
+// initRequestLists makes sure the Electra request lists are allocated.
+func (b *Eth1Block) initRequestLists() {
+	deposits, withdrawals, consolidations := b.newRequestLists()
+	if b.DepositRequests == nil {
+		b.DepositRequests = deposits
+	}
+	if b.WithdrawalRequests == nil {
+		b.WithdrawalRequests = withdrawals
+	}
+	if b.ConsolidationRequests == nil {
+		b.ConsolidationRequests = consolidations
+	}
+}
+
 // DecodeSSZ decodes the block in SSZ format.
 func (b *Eth1Block) DecodeSSZ(buf []byte, version int) error {
 	b.Extra = solid.NewExtraData()
 	b.Transactions = &solid.TransactionsSSZ{}
 	b.Withdrawals = solid.NewStaticListSSZ[*Withdrawal](int(b.beaconCfg.MaxWithdrawalsPerPayload), 44)
+	b.DepositRequests, b.WithdrawalRequests, b.ConsolidationRequests = b.newRequestLists()
 	b.version = clparams.StateVersion(version)
 	return ssz2.UnmarshalSSZ(buf, version, b.getSchema()...)
 }
@@ -302,6 +385,10 @@ func (b *Eth1Block) getSchema() []interface{} {
 	if b.version >= clparams.DenebVersion {
 		s = append(s, &b.BlobGasUsed, &b.ExcessBlobGas)
 	}
+	if b.version >= clparams.ElectraVersion {
+		b.initRequestLists()
+		s = append(s, b.DepositRequests, b.WithdrawalRequests, b.ConsolidationRequests)
+	}
 	return s
 }
 
@@ -358,6 +445,11 @@ func (b *Eth1Block) RlpHeader(parentRoot *libcommon.Hash) (*types.Header, error)
 		header.ExcessBlobGas = &excessBlobGas
 	}
 
+	if b.version >= clparams.ElectraVersion {
+		requestsRoot := types.DeriveSha(b.Requests())
+		header.RequestsRoot = &requestsRoot
+	}
+
 	// If the header hash does not match the block hash, return an error.
 	if header.Hash() != b.BlockHash {
 		return nil, fmt.Errorf("cannot derive rlp header: mismatching hash: %s != %s", header.Hash(), b.BlockHash)
@@ -377,8 +469,46 @@ func (b *Eth1Block) Body() *types.RawBody {
 		withdrawals[idx] = convertConsensusWithdrawalToExecutionWithdrawal(w)
 		return true
 	})
-	return &types.RawBody{
+	body := &types.RawBody{
 		Transactions: b.Transactions.UnderlyngReference(),
 		Withdrawals:  types.Withdrawals(withdrawals),
 	}
+	if b.version >= clparams.ElectraVersion {
+		body.Requests = b.Requests()
+	}
+	return body
+}
+
+// Requests returns the EIP-7685 execution requests of the payload in their execution layer order:
+// deposits, then withdrawal requests, then consolidation requests.
+func (b *Eth1Block) Requests() types.Requests {
+	b.initRequestLists()
+	requests := make(types.Requests, 0, b.DepositRequests.Len()+b.WithdrawalRequests.Len()+b.ConsolidationRequests.Len())
+	b.DepositRequests.Range(func(_ int, d *DepositRequest, _ int) bool {
+		requests = append(requests, &types.DepositRequest{
+			Pubkey:                d.PubKey,
+			WithdrawalCredentials: d.WithdrawalCredentials,
+			Amount:                d.Amount,
+			Signature:             d.Signature,
+			Index:                 d.Index,
+		})
+		return true
+	})
+	b.WithdrawalRequests.Range(func(_ int, w *WithdrawalRequest, _ int) bool {
+		requests = append(requests, &types.WithdrawalRequest{
+			SourceAddress:   w.SourceAddress,
+			ValidatorPubkey: w.ValidatorPubKey,
+			Amount:          w.Amount,
+		})
+		return true
+	})
+	b.ConsolidationRequests.Range(func(_ int, c *ConsolidationRequest, _ int) bool {
+		requests = append(requests, &types.ConsolidationRequest{
+			SourceAddress: c.SourceAddress,
+			SourcePubKey:  c.SourcePubKey,
+			TargetPubKey:  c.TargetPubKey,
+		})
+		return true
+	})
+	return requests
 }
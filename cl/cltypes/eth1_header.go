@@ -52,6 +52,10 @@ type Eth1Header struct {
 	WithdrawalsRoot  libcommon.Hash `json:"withdrawals_root"`
 	BlobGasUsed      uint64         `json:"blob_gas_used,string"`
 	ExcessBlobGas    uint64         `json:"excess_blob_gas,string"`
+	// Electra
+	DepositRequestsRoot       libcommon.Hash `json:"deposit_requests_root"`
+	WithdrawalRequestsRoot    libcommon.Hash `json:"withdrawal_requests_root"`
+	ConsolidationRequestsRoot libcommon.Hash `json:"consolidation_requests_root"`
 	// internals
 	version clparams.StateVersion
 }
@@ -88,6 +92,14 @@ func (e *Eth1Header) Deneb() {
 	e.ExcessBlobGas = 0
 }
 
+// Electra converts the header to electra version.
+func (e *Eth1Header) Electra() {
+	e.version = clparams.ElectraVersion
+	e.DepositRequestsRoot = libcommon.Hash{}
+	e.WithdrawalRequestsRoot = libcommon.Hash{}
+	e.ConsolidationRequestsRoot = libcommon.Hash{}
+}
+
 func (e *Eth1Header) IsZero() bool {
 	if e.Extra == nil {
 		e.Extra = solid.NewExtraData()
@@ -124,6 +136,10 @@ func (h *Eth1Header) EncodingSizeSSZ() int {
 	if h.version >= clparams.DenebVersion {
 		size += 8 * 2 // BlobGasUsed + ExcessBlobGas
 	}
+
+	if h.version >= clparams.ElectraVersion {
+		size += 32 * 3 // DepositRequestsRoot + WithdrawalRequestsRoot + ConsolidationRequestsRoot
+	}
 	if h.Extra == nil {
 		h.Extra = solid.NewExtraData()
 	}
@@ -147,6 +163,9 @@ func (h *Eth1Header) getSchema() []interface{} {
 	if h.version >= clparams.DenebVersion {
 		s = append(s, &h.BlobGasUsed, &h.ExcessBlobGas)
 	}
+	if h.version >= clparams.ElectraVersion {
+		s = append(s, h.DepositRequestsRoot[:], h.WithdrawalRequestsRoot[:], h.ConsolidationRequestsRoot[:])
+	}
 	return s
 }
 
@@ -173,6 +192,10 @@ func (h *Eth1Header) MarshalJSON() ([]byte, error) {
 		WithdrawalsRoot  libcommon.Hash    `json:"withdrawals_root"`
 		BlobGasUsed      uint64            `json:"blob_gas_used,string"`
 		ExcessBlobGas    uint64            `json:"excess_blob_gas,string"`
+
+		DepositRequestsRoot       *libcommon.Hash `json:"deposit_requests_root,omitempty"`
+		WithdrawalRequestsRoot    *libcommon.Hash `json:"withdrawal_requests_root,omitempty"`
+		ConsolidationRequestsRoot *libcommon.Hash `json:"consolidation_requests_root,omitempty"`
 	}{
 		ParentHash:       h.ParentHash,
 		FeeRecipient:     h.FeeRecipient,
@@ -191,9 +214,21 @@ func (h *Eth1Header) MarshalJSON() ([]byte, error) {
 		WithdrawalsRoot:  h.WithdrawalsRoot,
 		BlobGasUsed:      h.BlobGasUsed,
 		ExcessBlobGas:    h.ExcessBlobGas,
+
+		DepositRequestsRoot:       electraField(h.version, &h.DepositRequestsRoot),
+		WithdrawalRequestsRoot:    electraField(h.version, &h.WithdrawalRequestsRoot),
+		ConsolidationRequestsRoot: electraField(h.version, &h.ConsolidationRequestsRoot),
 	})
 }
 
+// electraField hides Electra-only fields from the JSON of older payloads.
+func electraField[T any](version clparams.StateVersion, v *T) *T {
+	if version < clparams.ElectraVersion {
+		return nil
+	}
+	return v
+}
+
 func (h *Eth1Header) UnmarshalJSON(data []byte) error {
 	var aux struct {
 		ParentHash       libcommon.Hash    `json:"parent_hash"`
@@ -213,6 +248,10 @@ func (h *Eth1Header) UnmarshalJSON(data []byte) error {
 		WithdrawalsRoot  libcommon.Hash    `json:"withdrawals_root"`
 		BlobGasUsed      uint64            `json:"blob_gas_used,string"`
 		ExcessBlobGas    uint64            `json:"excess_blob_gas,string"`
+
+		DepositRequestsRoot       libcommon.Hash `json:"deposit_requests_root"`
+		WithdrawalRequestsRoot    libcommon.Hash `json:"withdrawal_requests_root"`
+		ConsolidationRequestsRoot libcommon.Hash `json:"consolidation_requests_root"`
 	}
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
@@ -241,5 +280,8 @@ func (h *Eth1Header) UnmarshalJSON(data []byte) error {
 	h.WithdrawalsRoot = aux.WithdrawalsRoot
 	h.BlobGasUsed = aux.BlobGasUsed
 	h.ExcessBlobGas = aux.ExcessBlobGas
+	h.DepositRequestsRoot = aux.DepositRequestsRoot
+	h.WithdrawalRequestsRoot = aux.WithdrawalRequestsRoot
+	h.ConsolidationRequestsRoot = aux.ConsolidationRequestsRoot
 	return nil
 }
@@ -0,0 +1,144 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package cltypes
+
+import (
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/types/clonable"
+
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	ssz2 "github.com/ledgerwatch/erigon/cl/ssz"
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+const (
+	DepositRequestSize       = length.Bytes48 + length.Hash + 8 + length.Bytes96 + 8
+	WithdrawalRequestSize    = length.Addr + length.Bytes48 + 8
+	ConsolidationRequestSize = length.Addr + length.Bytes48*2
+)
+
+// DepositRequest is a validator deposit surfaced by the execution layer (EIP-6110).
+type DepositRequest struct {
+	PubKey                libcommon.Bytes48 `json:"pubkey"`
+	WithdrawalCredentials libcommon.Hash    `json:"withdrawal_credentials"`
+	Amount                uint64            `json:"amount,string"`
+	Signature             libcommon.Bytes96 `json:"signature"`
+	Index                 uint64            `json:"index,string"`
+}
+
+func (d *DepositRequest) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, d.PubKey[:], d.WithdrawalCredentials[:], d.Amount, d.Signature[:], d.Index)
+}
+
+func (d *DepositRequest) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, d.PubKey[:], d.WithdrawalCredentials[:], &d.Amount, d.Signature[:], &d.Index)
+}
+
+func (*DepositRequest) EncodingSizeSSZ() int {
+	return DepositRequestSize
+}
+
+func (d *DepositRequest) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(d.PubKey[:], d.WithdrawalCredentials[:], d.Amount, d.Signature[:], d.Index)
+}
+
+func (*DepositRequest) Clone() clonable.Clonable {
+	return &DepositRequest{}
+}
+
+// WithdrawalRequest is an execution layer triggered exit or partial withdrawal (EIP-7002).
+type WithdrawalRequest struct {
+	SourceAddress   libcommon.Address `json:"source_address"`
+	ValidatorPubKey libcommon.Bytes48 `json:"validator_pubkey"`
+	Amount          uint64            `json:"amount,string"`
+}
+
+func (w *WithdrawalRequest) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, w.SourceAddress[:], w.ValidatorPubKey[:], w.Amount)
+}
+
+func (w *WithdrawalRequest) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, w.SourceAddress[:], w.ValidatorPubKey[:], &w.Amount)
+}
+
+func (*WithdrawalRequest) EncodingSizeSSZ() int {
+	return WithdrawalRequestSize
+}
+
+func (w *WithdrawalRequest) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(w.SourceAddress[:], w.ValidatorPubKey[:], w.Amount)
+}
+
+func (*WithdrawalRequest) Clone() clonable.Clonable {
+	return &WithdrawalRequest{}
+}
+
+// ConsolidationRequest is an execution layer triggered consolidation of two validators (EIP-7251).
+type ConsolidationRequest struct {
+	SourceAddress libcommon.Address `json:"source_address"`
+	SourcePubKey  libcommon.Bytes48 `json:"source_pubkey"`
+	TargetPubKey  libcommon.Bytes48 `json:"target_pubkey"`
+}
+
+func (c *ConsolidationRequest) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, c.SourceAddress[:], c.SourcePubKey[:], c.TargetPubKey[:])
+}
+
+func (c *ConsolidationRequest) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, c.SourceAddress[:], c.SourcePubKey[:], c.TargetPubKey[:])
+}
+
+func (*ConsolidationRequest) EncodingSizeSSZ() int {
+	return ConsolidationRequestSize
+}
+
+func (c *ConsolidationRequest) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(c.SourceAddress[:], c.SourcePubKey[:], c.TargetPubKey[:])
+}
+
+func (*ConsolidationRequest) Clone() clonable.Clonable {
+	return &ConsolidationRequest{}
+}
+
+func convertExecutionRequestsToConsensusRequests(requests types.Requests) (deposits []*DepositRequest, withdrawals []*WithdrawalRequest, consolidations []*ConsolidationRequest) {
+	for _, r := range requests {
+		switch req := r.(type) {
+		case *types.DepositRequest:
+			deposits = append(deposits, &DepositRequest{
+				PubKey:                req.Pubkey,
+				WithdrawalCredentials: req.WithdrawalCredentials,
+				Amount:                req.Amount,
+				Signature:             req.Signature,
+				Index:                 req.Index,
+			})
+		case *types.WithdrawalRequest:
+			withdrawals = append(withdrawals, &WithdrawalRequest{
+				SourceAddress:   req.SourceAddress,
+				ValidatorPubKey: req.ValidatorPubkey,
+				Amount:          req.Amount,
+			})
+		case *types.ConsolidationRequest:
+			consolidations = append(consolidations, &ConsolidationRequest{
+				SourceAddress: req.SourceAddress,
+				SourcePubKey:  req.SourcePubKey,
+				TargetPubKey:  req.TargetPubKey,
+			})
+		}
+	}
+	return
+}
@@ -20,11 +20,17 @@ import (
 	"encoding/json"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/merkle_tree"
 	ssz2 "github.com/ledgerwatch/erigon/cl/ssz"
 )
 
+const (
+	maxAttestingIndices        = 2048
+	maxAttestingIndicesElectra = 2048 * 64
+)
+
 /*
  * IndexedAttestation are attestantions sets to prove that someone misbehaved.
  */
@@ -36,11 +42,27 @@ type IndexedAttestation struct {
 
 func NewIndexedAttestation() *IndexedAttestation {
 	return &IndexedAttestation{
-		AttestingIndices: solid.NewRawUint64List(2048, nil),
+		AttestingIndices: solid.NewRawUint64List(maxAttestingIndices, nil),
+		Data:             solid.NewAttestationData(),
+	}
+}
+
+// NewIndexedAttestationWithVersion creates an IndexedAttestation whose attesting indices limit matches the fork.
+func NewIndexedAttestationWithVersion(version clparams.StateVersion) *IndexedAttestation {
+	return &IndexedAttestation{
+		AttestingIndices: solid.NewRawUint64List(AttestingIndicesLimit(version), nil),
 		Data:             solid.NewAttestationData(),
 	}
 }
 
+// AttestingIndicesLimit returns the maximum number of attesting indices of an IndexedAttestation.
+func AttestingIndicesLimit(version clparams.StateVersion) int {
+	if version >= clparams.ElectraVersion {
+		return maxAttestingIndicesElectra
+	}
+	return maxAttestingIndices
+}
+
 func (i *IndexedAttestation) Static() bool {
 	return false
 }
@@ -51,7 +73,7 @@ func (i *IndexedAttestation) UnmarshalJSON(buf []byte) error {
 		Data             solid.AttestationData `json:"data"`
 		Signature        libcommon.Bytes96     `json:"signature"`
 	}
-	tmp.AttestingIndices = solid.NewRawUint64List(2048, nil)
+	tmp.AttestingIndices = solid.NewRawUint64List(maxAttestingIndices, nil)
 	tmp.Data = solid.NewAttestationData()
 	if err := json.Unmarshal(buf, &tmp); err != nil {
 		return err
@@ -69,7 +91,7 @@ func (i *IndexedAttestation) EncodeSSZ(buf []byte) (dst []byte, err error) {
 // DecodeSSZ ssz unmarshals the IndexedAttestation object
 func (i *IndexedAttestation) DecodeSSZ(buf []byte, version int) error {
 	i.Data = solid.NewAttestationData()
-	i.AttestingIndices = solid.NewRawUint64List(2048, nil)
+	i.AttestingIndices = solid.NewRawUint64List(AttestingIndicesLimit(clparams.StateVersion(version)), nil)
 
 	return ssz2.UnmarshalSSZ(buf, version, i.AttestingIndices, i.Data, i.Signature[:])
 }
@@ -0,0 +1,110 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package cltypes
+
+import (
+	"github.com/ledgerwatch/erigon-lib/types/clonable"
+
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	ssz2 "github.com/ledgerwatch/erigon/cl/ssz"
+)
+
+const (
+	PendingBalanceDepositSize    = 16
+	PendingPartialWithdrawalSize = 24
+	PendingConsolidationSize     = 16
+)
+
+// PendingBalanceDeposit is a deposit waiting for activation churn before being credited (EIP-7251).
+type PendingBalanceDeposit struct {
+	Index  uint64 `json:"index,string"`
+	Amount uint64 `json:"amount,string"`
+}
+
+func (p *PendingBalanceDeposit) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, p.Index, p.Amount)
+}
+
+func (p *PendingBalanceDeposit) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, &p.Index, &p.Amount)
+}
+
+func (*PendingBalanceDeposit) EncodingSizeSSZ() int {
+	return PendingBalanceDepositSize
+}
+
+func (p *PendingBalanceDeposit) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(p.Index, p.Amount)
+}
+
+func (*PendingBalanceDeposit) Clone() clonable.Clonable {
+	return &PendingBalanceDeposit{}
+}
+
+// PendingPartialWithdrawal is a partial withdrawal requested from the execution layer
+// which becomes payable at WithdrawableEpoch (EIP-7251).
+type PendingPartialWithdrawal struct {
+	Index             uint64 `json:"index,string"`
+	Amount            uint64 `json:"amount,string"`
+	WithdrawableEpoch uint64 `json:"withdrawable_epoch,string"`
+}
+
+func (p *PendingPartialWithdrawal) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, p.Index, p.Amount, p.WithdrawableEpoch)
+}
+
+func (p *PendingPartialWithdrawal) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, &p.Index, &p.Amount, &p.WithdrawableEpoch)
+}
+
+func (*PendingPartialWithdrawal) EncodingSizeSSZ() int {
+	return PendingPartialWithdrawalSize
+}
+
+func (p *PendingPartialWithdrawal) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(p.Index, p.Amount, p.WithdrawableEpoch)
+}
+
+func (*PendingPartialWithdrawal) Clone() clonable.Clonable {
+	return &PendingPartialWithdrawal{}
+}
+
+// PendingConsolidation moves the balance of SourceIndex into TargetIndex once the source is withdrawable (EIP-7251).
+type PendingConsolidation struct {
+	SourceIndex uint64 `json:"source_index,string"`
+	TargetIndex uint64 `json:"target_index,string"`
+}
+
+func (p *PendingConsolidation) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, p.SourceIndex, p.TargetIndex)
+}
+
+func (p *PendingConsolidation) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, &p.SourceIndex, &p.TargetIndex)
+}
+
+func (*PendingConsolidation) EncodingSizeSSZ() int {
+	return PendingConsolidationSize
+}
+
+func (p *PendingConsolidation) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(p.SourceIndex, p.TargetIndex)
+}
+
+func (*PendingConsolidation) Clone() clonable.Clonable {
+	return &PendingConsolidation{}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common/length"
 	"github.com/ledgerwatch/erigon-lib/types/clonable"
 	"github.com/ledgerwatch/erigon-lib/types/ssz"
+	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/merkle_tree"
 )
 
@@ -36,6 +37,13 @@ const (
 
 	// offset is usually always the same
 	aggregationBitsOffset = 228
+
+	// Electra appends the committee bits (Bitvector[MAX_COMMITTEES_PER_SLOT]) to the static part.
+	committeeBitsSize            = 8
+	aggregationBitsOffsetElectra = aggregationBitsOffset + committeeBitsSize
+
+	aggregationBitsLimit        = 2048
+	aggregationBitsLimitElectra = 2048 * 64
 )
 
 // Attestation type represents a statement or confirmation of some occurrence or phenomenon.
@@ -44,6 +52,8 @@ type Attestation struct {
 	staticBuffer [attestationStaticBufferSize]byte
 	// Dynamic field to store aggregation bits
 	aggregationBitsBuffer []byte
+	// Committee bits, only set for Electra attestations (EIP-7549)
+	committeeBits []byte
 }
 
 // Static returns whether the attestation is static or not. For Attestation, it's always false.
@@ -56,6 +66,7 @@ func (a *Attestation) Copy() *Attestation {
 	copy(new.staticBuffer[:], a.staticBuffer[:])
 	new.aggregationBitsBuffer = make([]byte, len(a.aggregationBitsBuffer))
 	copy(new.aggregationBitsBuffer, a.aggregationBitsBuffer)
+	new.committeeBits = libcommon.Copy(a.committeeBits)
 	return new
 }
 
@@ -73,15 +84,34 @@ func NewAttestionFromParameters(
 	return a
 }
 
+// NewElectraAttestationFromParameters creates a new Electra Attestation, whose aggregation bits
+// span all the committees selected by committeeBits.
+func NewElectraAttestationFromParameters(
+	aggregationBits []byte,
+	attestationData AttestationData,
+	signature [96]byte,
+	committeeBits [committeeBitsSize]byte,
+) *Attestation {
+	a := NewAttestionFromParameters(aggregationBits, attestationData, signature)
+	a.SetCommitteeBits(committeeBits[:])
+	return a
+}
+
 func (a Attestation) MarshalJSON() ([]byte, error) {
+	var committeeBits hexutility.Bytes
+	if a.committeeBits != nil {
+		committeeBits = a.committeeBits
+	}
 	return json.Marshal(struct {
 		AggregationBits hexutility.Bytes  `json:"aggregation_bits"`
 		Signature       libcommon.Bytes96 `json:"signature"`
 		Data            AttestationData   `json:"data"`
+		CommitteeBits   hexutility.Bytes  `json:"committee_bits,omitempty"`
 	}{
 		AggregationBits: a.aggregationBitsBuffer,
 		Signature:       a.Signature(),
 		Data:            a.AttestantionData(),
+		CommitteeBits:   committeeBits,
 	})
 }
 
@@ -90,6 +120,7 @@ func (a *Attestation) UnmarshalJSON(buf []byte) error {
 		AggregationBits hexutility.Bytes  `json:"aggregation_bits"`
 		Signature       libcommon.Bytes96 `json:"signature"`
 		Data            AttestationData   `json:"data"`
+		CommitteeBits   hexutility.Bytes  `json:"committee_bits"`
 	}
 	tmp.Data = NewAttestationData()
 	if err := json.Unmarshal(buf, &tmp); err != nil {
@@ -99,6 +130,10 @@ func (a *Attestation) UnmarshalJSON(buf []byte) error {
 	a.SetAggregationBits(tmp.AggregationBits)
 	a.SetSignature(tmp.Signature)
 	a.SetAttestationData(tmp.Data)
+	a.committeeBits = nil
+	if tmp.CommitteeBits != nil {
+		a.SetCommitteeBits(tmp.CommitteeBits)
+	}
 	return nil
 }
 
@@ -112,6 +147,28 @@ func (a *Attestation) SetAggregationBits(bits []byte) {
 	a.aggregationBitsBuffer = bits
 }
 
+// CommitteeBits returns the committee bits of an Electra attestation, or nil for older attestations.
+func (a *Attestation) CommitteeBits() []byte {
+	return a.committeeBits
+}
+
+// SetCommitteeBits sets the committee bits, turning the attestation into an Electra attestation.
+func (a *Attestation) SetCommitteeBits(bits []byte) {
+	a.committeeBits = make([]byte, committeeBitsSize)
+	copy(a.committeeBits, bits)
+}
+
+// CommitteeIndices returns the indices of the committees set in the committee bits.
+func (a *Attestation) CommitteeIndices() []uint64 {
+	var indices []uint64
+	for i := 0; i < len(a.committeeBits)*8; i++ {
+		if a.committeeBits[i/8]&(1<<(i%8)) != 0 {
+			indices = append(indices, uint64(i))
+		}
+	}
+	return indices
+}
+
 // AttestantionData returns the attestation data of the Attestation instance.
 func (a *Attestation) AttestantionData() AttestationData {
 	return (AttestationData)(a.staticBuffer[4:132])
@@ -139,25 +196,42 @@ func (a *Attestation) EncodingSizeSSZ() (size int) {
 	if a == nil {
 		return
 	}
-	return size + len(a.aggregationBitsBuffer)
+	return size + len(a.committeeBits) + len(a.aggregationBitsBuffer)
 }
 
 // DecodeSSZ decodes the provided buffer into the Attestation instance.
-func (a *Attestation) DecodeSSZ(buf []byte, _ int) error {
-	if len(buf) < attestationStaticBufferSize {
+func (a *Attestation) DecodeSSZ(buf []byte, version int) error {
+	if clparams.StateVersion(version) < clparams.ElectraVersion {
+		if len(buf) < attestationStaticBufferSize {
+			return ssz.ErrLowBufferSize
+		}
+		copy(a.staticBuffer[:], buf)
+		a.aggregationBitsBuffer = libcommon.CopyBytes(buf[aggregationBitsOffset:])
+		a.committeeBits = nil
+		return nil
+	}
+	if len(buf) < aggregationBitsOffsetElectra {
 		return ssz.ErrLowBufferSize
 	}
 	copy(a.staticBuffer[:], buf)
-	a.aggregationBitsBuffer = libcommon.CopyBytes(buf[aggregationBitsOffset:])
+	// keep the static buffer offset consistent with the pre-electra layout.
+	binary.LittleEndian.PutUint32(a.staticBuffer[:4], aggregationBitsOffset)
+	a.committeeBits = libcommon.CopyBytes(buf[attestationStaticBufferSize:aggregationBitsOffsetElectra])
+	a.aggregationBitsBuffer = libcommon.CopyBytes(buf[aggregationBitsOffsetElectra:])
 	return nil
 }
 
 // EncodeSSZ encodes the Attestation instance into the provided buffer.
 func (a *Attestation) EncodeSSZ(dst []byte) ([]byte, error) {
 	buf := dst
-	buf = append(buf, a.staticBuffer[:]...)
-	buf = append(buf, a.aggregationBitsBuffer...)
-	return buf, nil
+	if a.committeeBits == nil {
+		buf = append(buf, a.staticBuffer[:]...)
+		return append(buf, a.aggregationBitsBuffer...), nil
+	}
+	buf = binary.LittleEndian.AppendUint32(buf, aggregationBitsOffsetElectra)
+	buf = append(buf, a.staticBuffer[4:]...)
+	buf = append(buf, a.committeeBits...)
+	return append(buf, a.aggregationBitsBuffer...), nil
 }
 
 // CopyHashBufferTo copies the hash buffer of the Attestation instance to the provided byte slice.
@@ -165,7 +239,11 @@ func (a *Attestation) CopyHashBufferTo(o []byte) error {
 	for i := 0; i < 128; i++ {
 		o[i] = 0
 	}
-	aggBytesRoot, err := merkle_tree.BitlistRootWithLimit(a.AggregationBits(), 2048)
+	limit := uint64(aggregationBitsLimit)
+	if a.committeeBits != nil {
+		limit = aggregationBitsLimitElectra
+	}
+	aggBytesRoot, err := merkle_tree.BitlistRootWithLimit(a.AggregationBits(), limit)
 	if err != nil {
 		return err
 	}
@@ -180,6 +258,10 @@ func (a *Attestation) CopyHashBufferTo(o []byte) error {
 	copy(o[64:], o[:32])
 	copy(o[:32], aggBytesRoot[:])
 	copy(o[32:64], dataRoot[:])
+	for i := 96; i < 128; i++ {
+		o[i] = 0
+	}
+	copy(o[96:], a.committeeBits)
 	return nil
 }
 
@@ -211,5 +293,6 @@ func (a *Attestation) Clone() clonable.Clonable {
 	return &Attestation{
 		aggregationBitsBuffer: bitsBuffer,
 		staticBuffer:          staticBuffer,
+		committeeBits:         libcommon.Copy(a.committeeBits),
 	}
 }
@@ -21,6 +21,8 @@ import (
 
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
 )
 
 func TestAttestationData(t *testing.T) {
@@ -107,3 +109,32 @@ func TestAttestation(t *testing.T) {
 	cloned := attestation.Clone()
 	assert.NotEqual(t, nil, cloned.(*Attestation))
 }
+
+func TestElectraAttestation(t *testing.T) {
+	aggregationBits := []byte{0b10101010, 0b1}
+	var committeeBits [committeeBitsSize]byte
+	committeeBits[0] = 0b00000101
+	committeeBits[7] = 0b10000000
+	attestation := NewElectraAttestationFromParameters(aggregationBits, NewAttestationData(), [96]byte{1}, committeeBits)
+
+	assert.Equal(t, []uint64{0, 2, 63}, attestation.CommitteeIndices())
+	assert.Equal(t, attestationStaticBufferSize+committeeBitsSize+len(aggregationBits), attestation.EncodingSizeSSZ())
+
+	// Test Encoding and Decoding
+	buf, err := attestation.EncodeSSZ(nil)
+	assert.NoError(t, err)
+	assert.Len(t, buf, attestation.EncodingSizeSSZ())
+	newAttestation := &Attestation{}
+	assert.NoError(t, newAttestation.DecodeSSZ(buf, int(clparams.ElectraVersion)))
+	assert.Equal(t, attestation, newAttestation)
+	assert.Equal(t, aggregationBits, newAttestation.AggregationBits())
+	assert.Equal(t, committeeBits[:], newAttestation.CommitteeBits())
+
+	// The committee bits are part of the root.
+	preElectra := NewAttestionFromParameters(aggregationBits, NewAttestationData(), [96]byte{1})
+	electraRoot, err := attestation.HashSSZ()
+	assert.NoError(t, err)
+	preElectraRoot, err := preElectra.HashSSZ()
+	assert.NoError(t, err)
+	assert.NotEqual(t, preElectraRoot, electraRoot)
+}
@@ -172,6 +172,12 @@ func (l *ListSSZ[T]) Truncate(length int) {
 	l.root = libcommon.Hash{}
 }
 
+// Cut removes the first n elements of the list.
+func (l *ListSSZ[T]) Cut(n int) {
+	l.list = l.list[n:]
+	l.root = libcommon.Hash{}
+}
+
 func (l *ListSSZ[T]) ElementProof(i int) [][32]byte {
 	leaves := make([]interface{}, l.limit)
 	for i := range leaves {
@@ -0,0 +1,249 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle_tree
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+// DepositContractDepth is the depth of the merkle tree of the deposit contract.
+const DepositContractDepth = 32
+
+var (
+	ErrDepositTreeFull        = errors.New("deposit tree is full")
+	ErrFinalizeUnknownDeposit = errors.New("cannot finalize deposits which are not in the tree")
+)
+
+// depositNode is a node of the sparse merkle tree of EIP-4881. Finalized subtrees are collapsed
+// into their root, so that only the roots needed to keep appending deposits are retained.
+type depositNode interface {
+	root() libcommon.Hash
+	isFull() bool
+	pushLeaf(leaf libcommon.Hash, level int) (depositNode, error)
+	finalize(depositsToFinalize uint64, level int) depositNode
+	finalized(result *[]libcommon.Hash) uint64
+}
+
+type finalizedNode struct {
+	depositCount uint64
+	hash         libcommon.Hash
+}
+
+func (n *finalizedNode) root() libcommon.Hash { return n.hash }
+func (n *finalizedNode) isFull() bool         { return true }
+func (n *finalizedNode) pushLeaf(libcommon.Hash, int) (depositNode, error) {
+	return nil, ErrDepositTreeFull
+}
+func (n *finalizedNode) finalize(uint64, int) depositNode { return n }
+func (n *finalizedNode) finalized(result *[]libcommon.Hash) uint64 {
+	*result = append(*result, n.hash)
+	return n.depositCount
+}
+
+type leafNode struct {
+	hash libcommon.Hash
+}
+
+func (n *leafNode) root() libcommon.Hash { return n.hash }
+func (n *leafNode) isFull() bool         { return true }
+func (n *leafNode) pushLeaf(libcommon.Hash, int) (depositNode, error) {
+	return nil, ErrDepositTreeFull
+}
+func (n *leafNode) finalize(uint64, int) depositNode {
+	return &finalizedNode{depositCount: 1, hash: n.hash}
+}
+func (n *leafNode) finalized(*[]libcommon.Hash) uint64 { return 0 }
+
+type innerNode struct {
+	left, right depositNode
+}
+
+func (n *innerNode) root() libcommon.Hash {
+	left, right := n.left.root(), n.right.root()
+	return utils.Sha256(left[:], right[:])
+}
+
+func (n *innerNode) isFull() bool { return n.right.isFull() }
+
+func (n *innerNode) pushLeaf(leaf libcommon.Hash, level int) (depositNode, error) {
+	if !n.left.isFull() {
+		left, err := n.left.pushLeaf(leaf, level-1)
+		if err != nil {
+			return nil, err
+		}
+		n.left = left
+		return n, nil
+	}
+	right, err := n.right.pushLeaf(leaf, level-1)
+	if err != nil {
+		return nil, err
+	}
+	n.right = right
+	return n, nil
+}
+
+func (n *innerNode) finalize(depositsToFinalize uint64, level int) depositNode {
+	deposits := uint64(1) << level
+	if deposits <= depositsToFinalize {
+		return &finalizedNode{depositCount: deposits, hash: n.root()}
+	}
+	n.left = n.left.finalize(depositsToFinalize, level-1)
+	if depositsToFinalize > deposits/2 {
+		n.right = n.right.finalize(depositsToFinalize-deposits/2, level-1)
+	}
+	return n
+}
+
+func (n *innerNode) finalized(result *[]libcommon.Hash) uint64 {
+	return n.left.finalized(result) + n.right.finalized(result)
+}
+
+type zeroNode struct {
+	level int
+}
+
+func (n *zeroNode) root() libcommon.Hash { return ZeroHashes[n.level] }
+func (n *zeroNode) isFull() bool         { return false }
+func (n *zeroNode) pushLeaf(leaf libcommon.Hash, level int) (depositNode, error) {
+	return newDepositNode([]libcommon.Hash{leaf}, level), nil
+}
+func (n *zeroNode) finalize(uint64, int) depositNode   { return n }
+func (n *zeroNode) finalized(*[]libcommon.Hash) uint64 { return 0 }
+
+func newDepositNode(leaves []libcommon.Hash, level int) depositNode {
+	if len(leaves) == 0 {
+		return &zeroNode{level: level}
+	}
+	if level == 0 {
+		return &leafNode{hash: leaves[0]}
+	}
+	split := min(1<<(level-1), len(leaves))
+	return &innerNode{
+		left:  newDepositNode(leaves[:split], level-1),
+		right: newDepositNode(leaves[split:], level-1),
+	}
+}
+
+func depositNodeFromSnapshot(finalized []libcommon.Hash, depositCount uint64, level int) depositNode {
+	if len(finalized) == 0 || depositCount == 0 {
+		return &zeroNode{level: level}
+	}
+	if depositCount == uint64(1)<<level {
+		return &finalizedNode{depositCount: depositCount, hash: finalized[0]}
+	}
+	leftSubtree := uint64(1) << (level - 1)
+	if depositCount <= leftSubtree {
+		return &innerNode{
+			left:  depositNodeFromSnapshot(finalized, depositCount, level-1),
+			right: &zeroNode{level: level - 1},
+		}
+	}
+	return &innerNode{
+		left:  &finalizedNode{depositCount: leftSubtree, hash: finalized[0]},
+		right: depositNodeFromSnapshot(finalized[1:], depositCount-leftSubtree, level-1),
+	}
+}
+
+// DepositTree is the incremental merkle tree of the deposit contract, as specified in EIP-4881.
+// ref: https://eips.ethereum.org/EIPS/eip-4881
+type DepositTree struct {
+	tree         depositNode
+	depositCount uint64
+}
+
+func NewDepositTree() *DepositTree {
+	return &DepositTree{tree: &zeroNode{level: DepositContractDepth}}
+}
+
+// NewDepositTreeFromSnapshot rebuilds a tree from the roots of its finalized subtrees, ordered from left to right.
+func NewDepositTreeFromSnapshot(finalized []libcommon.Hash, depositCount uint64) (*DepositTree, error) {
+	if depositCount > uint64(1)<<DepositContractDepth {
+		return nil, fmt.Errorf("deposit count %d exceeds the deposit contract capacity", depositCount)
+	}
+	// every finalized subtree covers one of the bits set in the deposit count.
+	if len(finalized) != bits.OnesCount64(depositCount) {
+		return nil, fmt.Errorf("expected %d finalized roots for %d deposits, got %d", bits.OnesCount64(depositCount), depositCount, len(finalized))
+	}
+	return &DepositTree{
+		tree:         depositNodeFromSnapshot(finalized, depositCount, DepositContractDepth),
+		depositCount: depositCount,
+	}, nil
+}
+
+// NewDepositTreeFromProof initializes a tree in which all the deposits before index are finalized, using the
+// merkle branch of the deposit at index (as found in beacon blocks), whose left siblings are the finalized roots.
+func NewDepositTreeFromProof(proof []libcommon.Hash, index uint64) (*DepositTree, error) {
+	if len(proof) < DepositContractDepth {
+		return nil, fmt.Errorf("deposit proof too short: %d", len(proof))
+	}
+	finalized := make([]libcommon.Hash, 0, bits.OnesCount64(index))
+	for level := DepositContractDepth - 1; level >= 0; level-- {
+		if index&(uint64(1)<<level) != 0 {
+			finalized = append(finalized, proof[level])
+		}
+	}
+	return NewDepositTreeFromSnapshot(finalized, index)
+}
+
+// DepositCount returns the amount of deposits in the tree.
+func (t *DepositTree) DepositCount() uint64 {
+	return t.depositCount
+}
+
+// PushLeaf appends the hash tree root of a deposit data to the tree.
+func (t *DepositTree) PushLeaf(leaf libcommon.Hash) error {
+	tree, err := t.tree.pushLeaf(leaf, DepositContractDepth)
+	if err != nil {
+		return err
+	}
+	t.tree = tree
+	t.depositCount++
+	return nil
+}
+
+// Root returns the deposit root, which is the root of the tree mixed in with the deposit count.
+func (t *DepositTree) Root() libcommon.Hash {
+	var count [32]byte
+	binary.LittleEndian.PutUint64(count[:], t.depositCount)
+	root := t.tree.root()
+	return utils.Sha256(root[:], count[:])
+}
+
+// Finalize prunes the first depositCount deposits from the tree, retaining only the roots of the finalized subtrees.
+func (t *DepositTree) Finalize(depositCount uint64) error {
+	if depositCount > t.depositCount {
+		return ErrFinalizeUnknownDeposit
+	}
+	if depositCount == 0 {
+		return nil
+	}
+	t.tree = t.tree.finalize(depositCount, DepositContractDepth)
+	return nil
+}
+
+// Finalized returns the roots of the finalized subtrees, ordered from left to right, and the amount of deposits they cover.
+func (t *DepositTree) Finalized() ([]libcommon.Hash, uint64) {
+	finalized := []libcommon.Hash{}
+	depositCount := t.tree.finalized(&finalized)
+	return finalized, depositCount
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package merkle_tree_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+// depositRootAndProof computes the deposit root of leaves, and the proof of the leaf at index, the way the deposit contract does.
+func depositRootAndProof(leaves []common.Hash, index uint64) (common.Hash, []common.Hash) {
+	layer := append([]common.Hash{}, leaves...)
+	proof := make([]common.Hash, 0, merkle_tree.DepositContractDepth+1)
+	for level := 0; level < merkle_tree.DepositContractDepth; level++ {
+		sibling := common.Hash(merkle_tree.ZeroHashes[level])
+		if idx := index>>level ^ 1; idx < uint64(len(layer)) {
+			sibling = layer[idx]
+		}
+		proof = append(proof, sibling)
+		next := make([]common.Hash, 0, (len(layer)+1)/2)
+		for i := 0; i < len(layer); i += 2 {
+			right := common.Hash(merkle_tree.ZeroHashes[level])
+			if i+1 < len(layer) {
+				right = layer[i+1]
+			}
+			next = append(next, utils.Sha256(layer[i][:], right[:]))
+		}
+		layer = next
+	}
+	root := common.Hash(merkle_tree.ZeroHashes[merkle_tree.DepositContractDepth])
+	if len(layer) > 0 {
+		root = layer[0]
+	}
+	var count common.Hash
+	binary.LittleEndian.PutUint64(count[:], uint64(len(leaves)))
+	proof = append(proof, count)
+	return utils.Sha256(root[:], count[:]), proof
+}
+
+func testDepositLeaves(n int) []common.Hash {
+	leaves := make([]common.Hash, n)
+	for i := range leaves {
+		leaves[i] = utils.Sha256([]byte{byte(i), byte(i >> 8)})
+	}
+	return leaves
+}
+
+func TestDepositTreeRoot(t *testing.T) {
+	leaves := testDepositLeaves(70)
+	tree := merkle_tree.NewDepositTree()
+	emptyRoot, _ := depositRootAndProof(nil, 0)
+	require.Equal(t, emptyRoot, tree.Root())
+	for i, leaf := range leaves {
+		require.NoError(t, tree.PushLeaf(leaf))
+		expected, _ := depositRootAndProof(leaves[:i+1], 0)
+		require.Equal(t, expected, tree.Root())
+	}
+	require.Equal(t, uint64(len(leaves)), tree.DepositCount())
+}
+
+func TestDepositTreeFinalize(t *testing.T) {
+	leaves := testDepositLeaves(70)
+	tree := merkle_tree.NewDepositTree()
+	for _, leaf := range leaves[:50] {
+		require.NoError(t, tree.PushLeaf(leaf))
+	}
+	require.ErrorIs(t, tree.Finalize(51), merkle_tree.ErrFinalizeUnknownDeposit)
+	for _, depositCount := range []uint64{0, 13, 32, 45} {
+		root := tree.Root()
+		require.NoError(t, tree.Finalize(depositCount))
+		require.Equal(t, root, tree.Root())
+
+		finalized, finalizedCount := tree.Finalized()
+		require.Equal(t, depositCount, finalizedCount)
+		// the snapshot of the finalized deposits has the root of the deposit contract at that deposit count
+		snapshotTree, err := merkle_tree.NewDepositTreeFromSnapshot(finalized, finalizedCount)
+		require.NoError(t, err)
+		expected, _ := depositRootAndProof(leaves[:depositCount], 0)
+		require.Equal(t, expected, snapshotTree.Root())
+	}
+	// the tree keeps growing after finalization, also when rebuilt from a snapshot
+	finalized, finalizedCount := tree.Finalized()
+	snapshotTree, err := merkle_tree.NewDepositTreeFromSnapshot(finalized, finalizedCount)
+	require.NoError(t, err)
+	for _, leaf := range leaves[finalizedCount:] {
+		require.NoError(t, snapshotTree.PushLeaf(leaf))
+	}
+	for _, leaf := range leaves[50:] {
+		require.NoError(t, tree.PushLeaf(leaf))
+	}
+	expected, _ := depositRootAndProof(leaves, 0)
+	require.Equal(t, expected, tree.Root())
+	require.Equal(t, expected, snapshotTree.Root())
+
+	_, err = merkle_tree.NewDepositTreeFromSnapshot(finalized[1:], finalizedCount)
+	require.Error(t, err)
+}
+
+func TestDepositTreeFromProof(t *testing.T) {
+	leaves := testDepositLeaves(70)
+	for _, index := range []uint64{0, 1, 17, 64, 69} {
+		_, proof := depositRootAndProof(leaves, index)
+		tree, err := merkle_tree.NewDepositTreeFromProof(proof, index)
+		require.NoError(t, err)
+		require.Equal(t, index, tree.DepositCount())
+		for _, leaf := range leaves[index:] {
+			require.NoError(t, tree.PushLeaf(leaf))
+		}
+		expected, _ := depositRootAndProof(leaves, 0)
+		require.Equal(t, expected, tree.Root())
+	}
+}
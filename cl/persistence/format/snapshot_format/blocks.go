@@ -27,11 +27,13 @@ import (
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/core/types"
 )
 
 type ExecutionBlockReaderByNumber interface {
 	Transactions(number uint64, hash libcommon.Hash) (*solid.TransactionsSSZ, error)
 	Withdrawals(number uint64, hash libcommon.Hash) (*solid.ListSSZ[*cltypes.Withdrawal], error)
+	Requests(number uint64, hash libcommon.Hash) (types.Requests, error)
 }
 
 var buffersPool = sync.Pool{
@@ -124,7 +126,15 @@ func ReadBlockFromSnapshot(r io.Reader, executionReader ExecutionBlockReaderByNu
 	if err != nil {
 		return nil, err
 	}
-	return blindedBlock.Full(txs, ws), nil
+	block := blindedBlock.Full(txs, ws)
+	if v >= clparams.ElectraVersion {
+		requests, err := executionReader.Requests(blockNumber, blockHash)
+		if err != nil {
+			return nil, err
+		}
+		block.Block.Body.ExecutionPayload.SetRequests(requests)
+	}
+	return block, nil
 }
 
 // ReadBlockHeaderFromSnapshotWithExecutionData reads the beacon block header and the EL block number and block hash.
@@ -121,3 +121,19 @@ func (r *ExecutionSnapshotReader) Withdrawals(number uint64, hash libcommon.Hash
 	}
 	return ret, nil
 }
+
+func (r *ExecutionSnapshotReader) Requests(number uint64, hash libcommon.Hash) (types.Requests, error) {
+	tx, err := r.db.BeginRo(r.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	body, _, err := r.blockReader.Body(r.ctx, tx, hash, number)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, fmt.Errorf("requests not found for block %d", number)
+	}
+	return body.Requests, nil
+}
@@ -20,6 +20,7 @@ import (
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/core/types"
 )
 
 type MockBlockReader struct {
@@ -30,6 +31,10 @@ func (t *MockBlockReader) Withdrawals(number uint64, hash libcommon.Hash) (*soli
 	return t.Block.Withdrawals, nil
 }
 
+func (t *MockBlockReader) Requests(number uint64, hash libcommon.Hash) (types.Requests, error) {
+	return t.Block.Requests(), nil
+}
+
 func (t *MockBlockReader) Transactions(number uint64, hash libcommon.Hash) (*solid.TransactionsSSZ, error) {
 	return t.Block.Transactions, nil
 }
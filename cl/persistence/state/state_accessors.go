@@ -313,3 +313,25 @@ func ReadActiveIndicies(tx kv.Tx, slot uint64) ([]uint64, error) {
 	buf := bytes.NewBuffer(v)
 	return base_encoding.ReadRabbits(nil, buf)
 }
+
+// WriteDepositSnapshot stores the snapshot of the finalized deposit tree, replacing the previous one.
+func WriteDepositSnapshot(tx kv.RwTx, snapshot *cltypes.DepositSnapshot) error {
+	encoded, err := snapshot.EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	return tx.Put(kv.DepositSnapshot, kv.DepositSnapshotKey, encoded)
+}
+
+// ReadDepositSnapshot reads the snapshot of the finalized deposit tree, returns nil if there is none yet.
+func ReadDepositSnapshot(tx kv.Tx) (*cltypes.DepositSnapshot, error) {
+	v, err := tx.GetOne(kv.DepositSnapshot, kv.DepositSnapshotKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(v) == 0 {
+		return nil, nil
+	}
+	snapshot := &cltypes.DepositSnapshot{}
+	return snapshot, snapshot.DecodeSSZ(v, 0)
+}
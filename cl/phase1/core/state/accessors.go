@@ -193,6 +193,10 @@ func GetUnslashedParticipatingIndices(b abstract.BeaconState, flagIndex int, epo
 // Implementation of is_eligible_for_activation_queue.
 // Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/phase0/beacon-chain.md#is_eligible_for_activation_queue
 func IsValidatorEligibleForActivationQueue(b abstract.BeaconState, validator solid.Validator) bool {
+	if b.Version() >= clparams.ElectraVersion {
+		return validator.ActivationEligibilityEpoch() == b.BeaconConfig().FarFutureEpoch &&
+			validator.EffectiveBalance() >= b.BeaconConfig().MinActivationBalance
+	}
 	return validator.ActivationEligibilityEpoch() == b.BeaconConfig().FarFutureEpoch &&
 		validator.EffectiveBalance() == b.BeaconConfig().MaxEffectiveBalance
 }
@@ -217,6 +221,13 @@ func ComputeTimestampAtSlot(b abstract.BeaconState, slot uint64) uint64 {
 
 // ExpectedWithdrawals calculates the expected withdrawals that can be made by validators in the current epoch
 func ExpectedWithdrawals(b abstract.BeaconState, currentEpoch uint64) []*cltypes.Withdrawal {
+	withdrawals, _ := GetExpectedWithdrawals(b, currentEpoch)
+	return withdrawals
+}
+
+// GetExpectedWithdrawals calculates the expected withdrawals together with the number of pending partial withdrawals they consume.
+// Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#updated-get_expected_withdrawals
+func GetExpectedWithdrawals(b abstract.BeaconState, currentEpoch uint64) ([]*cltypes.Withdrawal, uint64) {
 	// Get the current epoch, the next withdrawal index, and the next withdrawal validator index
 	nextWithdrawalIndex := b.NextWithdrawalIndex()
 	nextWithdrawalValidatorIndex := b.NextWithdrawalValidatorIndex()
@@ -227,6 +238,35 @@ func ExpectedWithdrawals(b abstract.BeaconState, currentEpoch uint64) []*cltypes
 	bound := min(maxValidators, maxValidatorsPerWithdrawalsSweep)
 	withdrawals := make([]*cltypes.Withdrawal, 0, bound)
 
+	// Since Electra, the pending partial withdrawals are processed before the sweep.
+	var partialWithdrawalsCount uint64
+	if b.Version() >= clparams.ElectraVersion {
+		b.PendingPartialWithdrawals().Range(func(_ int, withdrawal *cltypes.PendingPartialWithdrawal, _ int) bool {
+			if withdrawal.WithdrawableEpoch > currentEpoch || len(withdrawals) == int(b.BeaconConfig().MaxPendingPartialsPerWithdrawalsSweep) {
+				return false
+			}
+			validator, err := b.ValidatorForValidatorIndex(int(withdrawal.Index))
+			if err != nil {
+				return false
+			}
+			balance, _ := b.ValidatorBalance(int(withdrawal.Index))
+			hasSufficientEffectiveBalance := validator.EffectiveBalance() >= b.BeaconConfig().MinActivationBalance
+			hasExcessBalance := balance > b.BeaconConfig().MinActivationBalance
+			if validator.ExitEpoch() == b.BeaconConfig().FarFutureEpoch && hasSufficientEffectiveBalance && hasExcessBalance {
+				wd := validator.WithdrawalCredentials()
+				withdrawals = append(withdrawals, &cltypes.Withdrawal{
+					Index:     nextWithdrawalIndex,
+					Validator: withdrawal.Index,
+					Address:   libcommon.BytesToAddress(wd[12:]),
+					Amount:    min(balance-b.BeaconConfig().MinActivationBalance, withdrawal.Amount),
+				})
+				nextWithdrawalIndex++
+			}
+			partialWithdrawalsCount++
+			return true
+		})
+	}
+
 	// Loop through the validators to calculate expected withdrawals
 	for validatorCount := uint64(0); validatorCount < bound && len(withdrawals) != int(b.BeaconConfig().MaxWithdrawalsPerPayload); validatorCount++ {
 		// Get the validator and balance for the current validator index
@@ -235,7 +275,7 @@ func ExpectedWithdrawals(b abstract.BeaconState, currentEpoch uint64) []*cltypes
 		currentBalance, _ := b.ValidatorBalance(int(nextWithdrawalValidatorIndex))
 		wd := currentValidator.WithdrawalCredentials()
 		// Check if the validator is fully withdrawable
-		if isFullyWithdrawableValidator(b.BeaconConfig(), currentValidator, currentBalance, currentEpoch) {
+		if isFullyWithdrawableValidator(b.BeaconConfig(), b.Version(), currentValidator, currentBalance, currentEpoch) {
 			// Add a new withdrawal with the validator's withdrawal credentials and balance
 			newWithdrawal := &cltypes.Withdrawal{
 				Index:     nextWithdrawalIndex,
@@ -245,13 +285,17 @@ func ExpectedWithdrawals(b abstract.BeaconState, currentEpoch uint64) []*cltypes
 			}
 			withdrawals = append(withdrawals, newWithdrawal)
 			nextWithdrawalIndex++
-		} else if isPartiallyWithdrawableValidator(b.BeaconConfig(), currentValidator, currentBalance) { // Check if the validator is partially withdrawable
+		} else if isPartiallyWithdrawableValidator(b.BeaconConfig(), b.Version(), currentValidator, currentBalance) { // Check if the validator is partially withdrawable
 			// Add a new withdrawal with the validator's withdrawal credentials and balance minus the maximum effective balance
+			maxEffectiveBalance := b.BeaconConfig().MaxEffectiveBalance
+			if b.Version() >= clparams.ElectraVersion {
+				maxEffectiveBalance = GetValidatorMaxEffectiveBalance(b.BeaconConfig(), currentValidator)
+			}
 			newWithdrawal := &cltypes.Withdrawal{
 				Index:     nextWithdrawalIndex,
 				Validator: nextWithdrawalValidatorIndex,
 				Address:   libcommon.BytesToAddress(wd[12:]),
-				Amount:    currentBalance - b.BeaconConfig().MaxEffectiveBalance,
+				Amount:    currentBalance - maxEffectiveBalance,
 			}
 			withdrawals = append(withdrawals, newWithdrawal)
 			nextWithdrawalIndex++
@@ -262,5 +306,96 @@ func ExpectedWithdrawals(b abstract.BeaconState, currentEpoch uint64) []*cltypes
 	}
 
 	// Return the withdrawals slice
-	return withdrawals
+	return withdrawals, partialWithdrawalsCount
+}
+
+// GetBalanceChurnLimit returns the churn limit for the current epoch, in Gwei.
+// Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-get_balance_churn_limit
+func GetBalanceChurnLimit(b abstract.BeaconState) uint64 {
+	churn := max(b.BeaconConfig().MinPerEpochChurnLimitElectra, b.GetTotalActiveBalance()/b.BeaconConfig().ChurnLimitQuotient)
+	return churn - churn%b.BeaconConfig().EffectiveBalanceIncrement
+}
+
+// GetActivationExitChurnLimit returns the churn limit for activations and exits for the current epoch, in Gwei.
+// Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-get_activation_exit_churn_limit
+func GetActivationExitChurnLimit(b abstract.BeaconState) uint64 {
+	return min(b.BeaconConfig().MaxPerEpochActivationExitChurnLimit, GetBalanceChurnLimit(b))
+}
+
+// GetConsolidationChurnLimit returns the churn limit for consolidations for the current epoch, in Gwei.
+// Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-get_consolidation_churn_limit
+func GetConsolidationChurnLimit(b abstract.BeaconState) uint64 {
+	return GetBalanceChurnLimit(b) - GetActivationExitChurnLimit(b)
+}
+
+// GetActiveBalance returns the balance of the validator capped to its maximum effective balance.
+func GetActiveBalance(b abstract.BeaconState, index uint64) (uint64, error) {
+	validator, err := b.ValidatorForValidatorIndex(int(index))
+	if err != nil {
+		return 0, err
+	}
+	balance, err := b.ValidatorBalance(int(index))
+	if err != nil {
+		return 0, err
+	}
+	return min(balance, GetValidatorMaxEffectiveBalance(b.BeaconConfig(), validator)), nil
+}
+
+// GetPendingBalanceToWithdraw returns the sum of the pending partial withdrawals of the validator.
+func GetPendingBalanceToWithdraw(b abstract.BeaconState, index uint64) uint64 {
+	var total uint64
+	b.PendingPartialWithdrawals().Range(func(_ int, withdrawal *cltypes.PendingPartialWithdrawal, _ int) bool {
+		if withdrawal.Index == index {
+			total += withdrawal.Amount
+		}
+		return true
+	})
+	return total
+}
+
+// GetAttestingIndicesForAttestation returns the attesting indices of an attestation.
+// Since Electra, attestations aggregate over several committees of the same slot, selected by the committee bits,
+// and the aggregation bits are the concatenation of the committees bits.
+// Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#modified-get_attesting_indices
+func GetAttestingIndicesForAttestation(b abstract.BeaconState, attestation *solid.Attestation, checkBitsLength bool) ([]uint64, error) {
+	data := attestation.AttestantionData()
+	if attestation.CommitteeBits() == nil {
+		return b.GetAttestingIndicies(data, attestation.AggregationBits(), checkBitsLength)
+	}
+	aggregationBits := attestation.AggregationBits()
+	aggregationBitsLen := utils.GetBitlistLength(aggregationBits)
+	attestingIndices := []uint64{}
+	committeeOffset := 0
+	for _, committeeIndex := range attestation.CommitteeIndices() {
+		if committeeIndex >= b.CommitteeCount(data.Target().Epoch()) {
+			return nil, fmt.Errorf("GetAttestingIndicesForAttestation: committee index %d out of range", committeeIndex)
+		}
+		committee, err := b.GetBeaconCommitee(data.Slot(), committeeIndex)
+		if err != nil {
+			return nil, err
+		}
+		committeeAttesters := 0
+		for i, member := range committee {
+			bitIndex := committeeOffset + i
+			if bitIndex >= aggregationBitsLen {
+				return nil, fmt.Errorf("GetAttestingIndicesForAttestation: committee is too big")
+			}
+			if utils.IsBitOn(aggregationBits, bitIndex) {
+				attestingIndices = append(attestingIndices, member)
+				committeeAttesters++
+			}
+		}
+		if checkBitsLength && committeeAttesters == 0 {
+			return nil, fmt.Errorf("GetAttestingIndicesForAttestation: committee %d has no attesters", committeeIndex)
+		}
+		committeeOffset += len(committee)
+	}
+	if checkBitsLength && aggregationBitsLen != committeeOffset {
+		return nil, fmt.Errorf(
+			"GetAttestingIndicesForAttestation: invalid aggregation bits. agg bits size: %d, expect: %d",
+			aggregationBitsLen,
+			committeeOffset,
+		)
+	}
+	return attestingIndices, nil
 }
@@ -298,7 +298,7 @@ func (b *CachingBeaconState) ComputeNextSyncCommittee() (*solid.SyncCommittee, e
 		if err != nil {
 			return nil, err
 		}
-		if validator.EffectiveBalance()*math.MaxUint8 >= beaconConfig.MaxEffectiveBalance*randomByte {
+		if validator.EffectiveBalance()*math.MaxUint8 >= beaconConfig.GetMaxEffectiveBalance(b.Version())*randomByte {
 			syncCommitteePubKeys = append(syncCommitteePubKeys, validator.PublicKey())
 		}
 		i++
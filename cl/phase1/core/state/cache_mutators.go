@@ -76,7 +76,7 @@ func (b *CachingBeaconState) SlashValidator(slashedInd uint64, whistleblowerInd
 		whistleblowerInd = new(uint64)
 		*whistleblowerInd = proposerInd
 	}
-	whistleBlowerReward := newEffectiveBalance / b.BeaconConfig().WhistleBlowerRewardQuotient
+	whistleBlowerReward := newEffectiveBalance / b.BeaconConfig().GetWhistleBlowerRewardQuotient(b.Version())
 	proposerReward := b.getSlashingProposerReward(whistleBlowerReward)
 	if err := IncreaseBalance(b, proposerInd, proposerReward); err != nil {
 		return 0, err
@@ -97,6 +97,20 @@ func (b *CachingBeaconState) InitiateValidatorExit(index uint64) error {
 		return nil
 	}
 
+	if b.Version() >= clparams.ElectraVersion {
+		effectiveBalance, err := b.ValidatorEffectiveBalance(int(index))
+		if err != nil {
+			return err
+		}
+		exitQueueEpoch := ComputeExitEpochAndUpdateChurn(b, effectiveBalance)
+		newWithdrawableEpoch, overflow := math.SafeAdd(exitQueueEpoch, b.BeaconConfig().MinValidatorWithdrawabilityDelay)
+		if overflow {
+			return fmt.Errorf("withdrawable epoch is too big")
+		}
+		b.SetExitEpochForValidatorAtIndex(int(index), exitQueueEpoch)
+		return b.SetWithdrawableEpochForValidatorAtIndex(int(index), newWithdrawableEpoch)
+	}
+
 	currentEpoch := Epoch(b)
 	exitQueueEpoch := ComputeActivationExitEpoch(b.BeaconConfig(), currentEpoch)
 	b.ForEachValidator(func(v solid.Validator, idx, total int) bool {
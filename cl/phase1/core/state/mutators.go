@@ -16,7 +16,10 @@
 
 package state
 
-import "github.com/ledgerwatch/erigon/cl/abstract"
+import (
+	"github.com/ledgerwatch/erigon/cl/abstract"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+)
 
 func IncreaseBalance(b abstract.BeaconState, index, delta uint64) error {
 	currentBalance, err := b.ValidatorBalance(int(index))
@@ -37,3 +40,93 @@ func DecreaseBalance(b abstract.BeaconState, index, delta uint64) error {
 	}
 	return b.SetValidatorBalance(int(index), newBalance)
 }
+
+// ComputeExitEpochAndUpdateChurn consumes the exit churn for the given balance and returns the epoch at which the exit happens.
+// Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-compute_exit_epoch_and_update_churn
+func ComputeExitEpochAndUpdateChurn(b abstract.BeaconState, exitBalance uint64) uint64 {
+	earliestExitEpoch := max(b.EarliestExitEpoch(), ComputeActivationExitEpoch(b.BeaconConfig(), Epoch(b)))
+	perEpochChurn := GetActivationExitChurnLimit(b)
+	// New epoch for exits.
+	exitBalanceToConsume := b.ExitBalanceToConsume()
+	if b.EarliestExitEpoch() < earliestExitEpoch {
+		exitBalanceToConsume = perEpochChurn
+	}
+	// Exit doesn't fit in the current earliest epoch.
+	if exitBalance > exitBalanceToConsume {
+		balanceToProcess := exitBalance - exitBalanceToConsume
+		additionalEpochs := (balanceToProcess-1)/perEpochChurn + 1
+		earliestExitEpoch += additionalEpochs
+		exitBalanceToConsume += additionalEpochs * perEpochChurn
+	}
+	b.SetExitBalanceToConsume(exitBalanceToConsume - exitBalance)
+	b.SetEarliestExitEpoch(earliestExitEpoch)
+	return earliestExitEpoch
+}
+
+// ComputeConsolidationEpochAndUpdateChurn consumes the consolidation churn for the given balance and returns the epoch at which the consolidation happens.
+// Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-compute_consolidation_epoch_and_update_churn
+func ComputeConsolidationEpochAndUpdateChurn(b abstract.BeaconState, consolidationBalance uint64) uint64 {
+	earliestConsolidationEpoch := max(b.EarliestConsolidationEpoch(), ComputeActivationExitEpoch(b.BeaconConfig(), Epoch(b)))
+	perEpochConsolidationChurn := GetConsolidationChurnLimit(b)
+	// New epoch for consolidations.
+	consolidationBalanceToConsume := b.ConsolidationBalanceToConsume()
+	if b.EarliestConsolidationEpoch() < earliestConsolidationEpoch {
+		consolidationBalanceToConsume = perEpochConsolidationChurn
+	}
+	// Consolidation doesn't fit in the current earliest epoch.
+	if consolidationBalance > consolidationBalanceToConsume {
+		balanceToProcess := consolidationBalance - consolidationBalanceToConsume
+		additionalEpochs := (balanceToProcess-1)/perEpochConsolidationChurn + 1
+		earliestConsolidationEpoch += additionalEpochs
+		consolidationBalanceToConsume += additionalEpochs * perEpochConsolidationChurn
+	}
+	b.SetConsolidationBalanceToConsume(consolidationBalanceToConsume - consolidationBalance)
+	b.SetEarliestConsolidationEpoch(earliestConsolidationEpoch)
+	return earliestConsolidationEpoch
+}
+
+// SwitchToCompoundingValidator upgrades the 0x01 withdrawal credentials of the validator to 0x02 and queues its excess balance.
+func SwitchToCompoundingValidator(b abstract.BeaconState, index uint64) error {
+	validator, err := b.ValidatorForValidatorIndex(int(index))
+	if err != nil {
+		return err
+	}
+	if !HasEth1WithdrawalCredential(b.BeaconConfig(), validator) {
+		return nil
+	}
+	withdrawalCredentials := validator.WithdrawalCredentials()
+	withdrawalCredentials[0] = byte(b.BeaconConfig().CompoundingWithdrawalPrefix)
+	b.SetWithdrawalCredentialForValidatorAtIndex(int(index), withdrawalCredentials)
+	return QueueExcessActiveBalance(b, index)
+}
+
+// QueueExcessActiveBalance moves the balance above MIN_ACTIVATION_BALANCE to the pending balance deposits.
+func QueueExcessActiveBalance(b abstract.BeaconState, index uint64) error {
+	balance, err := b.ValidatorBalance(int(index))
+	if err != nil {
+		return err
+	}
+	if balance <= b.BeaconConfig().MinActivationBalance {
+		return nil
+	}
+	if err := b.SetValidatorBalance(int(index), b.BeaconConfig().MinActivationBalance); err != nil {
+		return err
+	}
+	b.AppendPendingBalanceDeposit(&cltypes.PendingBalanceDeposit{Index: index, Amount: balance - b.BeaconConfig().MinActivationBalance})
+	return nil
+}
+
+// QueueEntireBalanceAndResetValidator moves the whole balance of the validator to the pending balance deposits.
+func QueueEntireBalanceAndResetValidator(b abstract.BeaconState, index uint64) error {
+	balance, err := b.ValidatorBalance(int(index))
+	if err != nil {
+		return err
+	}
+	if err := b.SetValidatorBalance(int(index), 0); err != nil {
+		return err
+	}
+	b.SetEffectiveBalanceForValidatorAtIndex(int(index), 0)
+	b.SetActivationEligibilityEpochForValidatorAtIndex(int(index), b.BeaconConfig().FarFutureEpoch)
+	b.AppendPendingBalanceDeposit(&cltypes.PendingBalanceDeposit{Index: index, Amount: balance})
+	return nil
+}
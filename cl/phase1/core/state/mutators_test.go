@@ -136,3 +136,46 @@ func TestInitiatieValidatorExit(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeExitEpochAndUpdateChurn(t *testing.T) {
+	s := getTestStateBalances(t)
+	churn := state2.GetActivationExitChurnLimit(s)
+	require.Equal(t, clparams.MainnetBeaconConfig.MinPerEpochChurnLimitElectra, churn)
+	activationExitEpoch := state2.ComputeActivationExitEpoch(s.BeaconConfig(), state2.Epoch(s))
+
+	// The first exit opens a new churn epoch and fits in it.
+	exitBalance := clparams.MainnetBeaconConfig.MinActivationBalance
+	require.Equal(t, activationExitEpoch, state2.ComputeExitEpochAndUpdateChurn(s, exitBalance))
+	require.Equal(t, churn-exitBalance, s.ExitBalanceToConsume())
+
+	// The second exit overflows the remaining churn and is pushed to the next epoch.
+	remaining := s.ExitBalanceToConsume()
+	exitBalance = remaining + churn/2
+	require.Equal(t, activationExitEpoch+1, state2.ComputeExitEpochAndUpdateChurn(s, exitBalance))
+	require.Equal(t, churn/2, s.ExitBalanceToConsume())
+	require.Equal(t, activationExitEpoch+1, s.EarliestExitEpoch())
+}
+
+func TestSwitchToCompoundingValidator(t *testing.T) {
+	s := getTestStateBalances(t)
+	cfg := s.BeaconConfig()
+	excess := uint64(8_000_000_000)
+
+	v := solid.NewValidator()
+	v.SetExitEpoch(cfg.FarFutureEpoch)
+	var credentials [32]byte
+	credentials[0] = byte(cfg.ETH1AddressWithdrawalPrefixByte)
+	v.SetWithdrawalCredentials(credentials)
+	s.AddValidator(v, cfg.MinActivationBalance+excess)
+	index := uint64(s.ValidatorLength() - 1)
+
+	require.NoError(t, state2.SwitchToCompoundingValidator(s, index))
+	validator, err := s.ValidatorForValidatorIndex(int(index))
+	require.NoError(t, err)
+	require.True(t, state2.HasCompoundingWithdrawalCredential(cfg, validator))
+	balance, err := s.ValidatorBalance(int(index))
+	require.NoError(t, err)
+	require.Equal(t, cfg.MinActivationBalance, balance)
+	require.Equal(t, 1, s.PendingBalanceDeposits().Len())
+	require.Equal(t, excess, s.PendingBalanceDeposits().Get(0).Amount)
+}
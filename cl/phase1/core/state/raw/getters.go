@@ -347,3 +347,39 @@ func (b *BeaconState) GetDomain(domainType [4]byte, epoch uint64) ([]byte, error
 func (b *BeaconState) DebugPrint(prefix string) {
 	fmt.Printf("%s: %x\n", prefix, b.currentEpochParticipation)
 }
+
+func (b *BeaconState) DepositRequestsStartIndex() uint64 {
+	return b.depositRequestsStartIndex
+}
+
+func (b *BeaconState) DepositBalanceToConsume() uint64 {
+	return b.depositBalanceToConsume
+}
+
+func (b *BeaconState) ExitBalanceToConsume() uint64 {
+	return b.exitBalanceToConsume
+}
+
+func (b *BeaconState) EarliestExitEpoch() uint64 {
+	return b.earliestExitEpoch
+}
+
+func (b *BeaconState) ConsolidationBalanceToConsume() uint64 {
+	return b.consolidationBalanceToConsume
+}
+
+func (b *BeaconState) EarliestConsolidationEpoch() uint64 {
+	return b.earliestConsolidationEpoch
+}
+
+func (b *BeaconState) PendingBalanceDeposits() *solid.ListSSZ[*cltypes.PendingBalanceDeposit] {
+	return b.pendingBalanceDeposits
+}
+
+func (b *BeaconState) PendingPartialWithdrawals() *solid.ListSSZ[*cltypes.PendingPartialWithdrawal] {
+	return b.pendingPartialWithdrawals
+}
+
+func (b *BeaconState) PendingConsolidations() *solid.ListSSZ[*cltypes.PendingConsolidation] {
+	return b.pendingConsolidations
+}
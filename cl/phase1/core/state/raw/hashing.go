@@ -37,41 +37,54 @@ func (b *BeaconState) HashSSZ() (out [32]byte, err error) {
 	// 	fmt.Println(i/32, libcommon.BytesToHash(b.leaves[i:i+32]))
 	// }
 	// Pad to 32 of length
-	err = merkle_tree.MerkleRootFromFlatLeaves(b.leaves, out[:])
+	err = merkle_tree.MerkleRootFromFlatLeaves(b.versionedLeaves(), out[:])
 	return
 }
 
+// versionedLeaves returns the leaves of the state merkle tree for the current state version.
+func (b *BeaconState) versionedLeaves() []byte {
+	if b.version >= clparams.ElectraVersion {
+		return b.leaves[:stateLeavesCountElectra*32]
+	}
+	return b.leaves[:stateLeavesCount*32]
+}
+
+// stateTreeDepth returns the depth of the state merkle tree, which is used for the light client branches.
+func (b *BeaconState) stateTreeDepth() int {
+	if b.version >= clparams.ElectraVersion {
+		return 6
+	}
+	return 5
+}
+
+func (b *BeaconState) leavesSchema() []interface{} {
+	leaves := b.versionedLeaves()
+	schema := make([]interface{}, 0, len(leaves)/32)
+	for i := 0; i < len(leaves); i += 32 {
+		schema = append(schema, leaves[i:i+32])
+	}
+	return schema
+}
+
 func (b *BeaconState) CurrentSyncCommitteeBranch() ([][32]byte, error) {
 	if err := b.computeDirtyLeaves(); err != nil {
 		return nil, err
 	}
-	schema := []interface{}{}
-	for i := 0; i < len(b.leaves); i += 32 {
-		schema = append(schema, b.leaves[i:i+32])
-	}
-	return merkle_tree.MerkleProof(5, 22, schema...)
+	return merkle_tree.MerkleProof(b.stateTreeDepth(), 22, b.leavesSchema()...)
 }
 
 func (b *BeaconState) NextSyncCommitteeBranch() ([][32]byte, error) {
 	if err := b.computeDirtyLeaves(); err != nil {
 		return nil, err
 	}
-	schema := []interface{}{}
-	for i := 0; i < len(b.leaves); i += 32 {
-		schema = append(schema, b.leaves[i:i+32])
-	}
-	return merkle_tree.MerkleProof(5, 23, schema...)
+	return merkle_tree.MerkleProof(b.stateTreeDepth(), 23, b.leavesSchema()...)
 }
 
 func (b *BeaconState) FinalityRootBranch() ([][32]byte, error) {
 	if err := b.computeDirtyLeaves(); err != nil {
 		return nil, err
 	}
-	schema := []interface{}{}
-	for i := 0; i < len(b.leaves); i += 32 {
-		schema = append(schema, b.leaves[i:i+32])
-	}
-	proof, err := merkle_tree.MerkleProof(5, 20, schema...)
+	proof, err := merkle_tree.MerkleProof(b.stateTreeDepth(), 20, b.leavesSchema()...)
 	if err != nil {
 		return nil, err
 	}
@@ -361,6 +374,67 @@ func (b *BeaconState) computeDirtyLeaves() error {
 	}
 	log.Trace("HistoricalSummaries hashing", "elapsed", time.Since(begin))
 
+	if b.version < clparams.ElectraVersion {
+		return nil
+	}
+
+	// Field(28): DepositRequestsStartIndex
+	if b.isLeafDirty(DepositRequestsStartIndexLeafIndex) {
+		b.updateLeaf(DepositRequestsStartIndexLeafIndex, merkle_tree.Uint64Root(b.depositRequestsStartIndex))
+	}
+
+	// Field(29): DepositBalanceToConsume
+	if b.isLeafDirty(DepositBalanceToConsumeLeafIndex) {
+		b.updateLeaf(DepositBalanceToConsumeLeafIndex, merkle_tree.Uint64Root(b.depositBalanceToConsume))
+	}
+
+	// Field(30): ExitBalanceToConsume
+	if b.isLeafDirty(ExitBalanceToConsumeLeafIndex) {
+		b.updateLeaf(ExitBalanceToConsumeLeafIndex, merkle_tree.Uint64Root(b.exitBalanceToConsume))
+	}
+
+	// Field(31): EarliestExitEpoch
+	if b.isLeafDirty(EarliestExitEpochLeafIndex) {
+		b.updateLeaf(EarliestExitEpochLeafIndex, merkle_tree.Uint64Root(b.earliestExitEpoch))
+	}
+
+	// Field(32): ConsolidationBalanceToConsume
+	if b.isLeafDirty(ConsolidationBalanceToConsumeLeafIndex) {
+		b.updateLeaf(ConsolidationBalanceToConsumeLeafIndex, merkle_tree.Uint64Root(b.consolidationBalanceToConsume))
+	}
+
+	// Field(33): EarliestConsolidationEpoch
+	if b.isLeafDirty(EarliestConsolidationEpochLeafIndex) {
+		b.updateLeaf(EarliestConsolidationEpochLeafIndex, merkle_tree.Uint64Root(b.earliestConsolidationEpoch))
+	}
+
+	// Field(34): PendingBalanceDeposits
+	if b.isLeafDirty(PendingBalanceDepositsLeafIndex) {
+		root, err := b.pendingBalanceDeposits.HashSSZ()
+		if err != nil {
+			return err
+		}
+		b.updateLeaf(PendingBalanceDepositsLeafIndex, root)
+	}
+
+	// Field(35): PendingPartialWithdrawals
+	if b.isLeafDirty(PendingPartialWithdrawalsLeafIndex) {
+		root, err := b.pendingPartialWithdrawals.HashSSZ()
+		if err != nil {
+			return err
+		}
+		b.updateLeaf(PendingPartialWithdrawalsLeafIndex, root)
+	}
+
+	// Field(36): PendingConsolidations
+	if b.isLeafDirty(PendingConsolidationsLeafIndex) {
+		root, err := b.pendingConsolidations.HashSSZ()
+		if err != nil {
+			return err
+		}
+		b.updateLeaf(PendingConsolidationsLeafIndex, root)
+	}
+
 	return nil
 }
 
@@ -513,3 +513,65 @@ func (b *BeaconState) SetSlashings(slashings solid.Uint64VectorSSZ) {
 	b.markLeaf(SlashingsLeafIndex)
 	b.slashings = slashings
 }
+
+func (b *BeaconState) SetDepositRequestsStartIndex(index uint64) {
+	b.depositRequestsStartIndex = index
+	b.markLeaf(DepositRequestsStartIndexLeafIndex)
+}
+
+func (b *BeaconState) SetDepositBalanceToConsume(balance uint64) {
+	b.depositBalanceToConsume = balance
+	b.markLeaf(DepositBalanceToConsumeLeafIndex)
+}
+
+func (b *BeaconState) SetExitBalanceToConsume(balance uint64) {
+	b.exitBalanceToConsume = balance
+	b.markLeaf(ExitBalanceToConsumeLeafIndex)
+}
+
+func (b *BeaconState) SetEarliestExitEpoch(epoch uint64) {
+	b.earliestExitEpoch = epoch
+	b.markLeaf(EarliestExitEpochLeafIndex)
+}
+
+func (b *BeaconState) SetConsolidationBalanceToConsume(balance uint64) {
+	b.consolidationBalanceToConsume = balance
+	b.markLeaf(ConsolidationBalanceToConsumeLeafIndex)
+}
+
+func (b *BeaconState) SetEarliestConsolidationEpoch(epoch uint64) {
+	b.earliestConsolidationEpoch = epoch
+	b.markLeaf(EarliestConsolidationEpochLeafIndex)
+}
+
+func (b *BeaconState) AppendPendingBalanceDeposit(deposit *cltypes.PendingBalanceDeposit) {
+	b.pendingBalanceDeposits.Append(deposit)
+	b.markLeaf(PendingBalanceDepositsLeafIndex)
+}
+
+func (b *BeaconState) SetPendingBalanceDeposits(deposits *solid.ListSSZ[*cltypes.PendingBalanceDeposit]) {
+	b.pendingBalanceDeposits = deposits
+	b.markLeaf(PendingBalanceDepositsLeafIndex)
+}
+
+func (b *BeaconState) AppendPendingPartialWithdrawal(withdrawal *cltypes.PendingPartialWithdrawal) {
+	b.pendingPartialWithdrawals.Append(withdrawal)
+	b.markLeaf(PendingPartialWithdrawalsLeafIndex)
+}
+
+// CutPendingPartialWithdrawals removes the first n pending partial withdrawals.
+func (b *BeaconState) CutPendingPartialWithdrawals(n int) {
+	b.pendingPartialWithdrawals.Cut(n)
+	b.markLeaf(PendingPartialWithdrawalsLeafIndex)
+}
+
+func (b *BeaconState) AppendPendingConsolidation(consolidation *cltypes.PendingConsolidation) {
+	b.pendingConsolidations.Append(consolidation)
+	b.markLeaf(PendingConsolidationsLeafIndex)
+}
+
+// CutPendingConsolidations removes the first n pending consolidations.
+func (b *BeaconState) CutPendingConsolidations(n int) {
+	b.pendingConsolidations.Cut(n)
+	b.markLeaf(PendingConsolidationsLeafIndex)
+}
@@ -55,6 +55,8 @@ func (b *BeaconState) baseOffsetSSZ() uint32 {
 		return 2736653
 	case clparams.DenebVersion:
 		return 2736653
+	case clparams.ElectraVersion:
+		return 2736713
 	default:
 		// ?????
 		panic("tf is that")
@@ -81,6 +83,10 @@ func (b *BeaconState) getSchema() []interface{} {
 	if b.version >= clparams.CapellaVersion {
 		s = append(s, &b.nextWithdrawalIndex, &b.nextWithdrawalValidatorIndex, b.historicalSummaries)
 	}
+	if b.version >= clparams.ElectraVersion {
+		s = append(s, &b.depositRequestsStartIndex, &b.depositBalanceToConsume, &b.exitBalanceToConsume, &b.earliestExitEpoch,
+			&b.consolidationBalanceToConsume, &b.earliestConsolidationEpoch, b.pendingBalanceDeposits, b.pendingPartialWithdrawals, b.pendingConsolidations)
+	}
 	return s
 }
 
@@ -112,6 +118,11 @@ func (b *BeaconState) EncodingSizeSSZ() (size int) {
 
 	size += b.inactivityScores.Length() * 8
 	size += b.historicalSummaries.EncodingSizeSSZ()
+	if b.version >= clparams.ElectraVersion {
+		size += b.pendingBalanceDeposits.EncodingSizeSSZ()
+		size += b.pendingPartialWithdrawals.EncodingSizeSSZ()
+		size += b.pendingConsolidations.EncodingSizeSSZ()
+	}
 	return
 }
 
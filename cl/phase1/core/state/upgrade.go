@@ -17,6 +17,8 @@
 package state
 
 import (
+	"sort"
+
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
@@ -130,3 +132,73 @@ func (b *CachingBeaconState) UpgradeToDeneb() error {
 	b.SetVersion(clparams.DenebVersion)
 	return nil
 }
+
+// UpgradeToElectra - Electra of consensus-specs v1.5.0-alpha.3 (pending balance deposits, requests in execution
+// payload), for devnets only: final Electra containers differ.
+func (b *CachingBeaconState) UpgradeToElectra() error {
+	b.previousStateRoot = libcommon.Hash{}
+	epoch := Epoch(b.BeaconState)
+	// update version
+	fork := b.Fork()
+	fork.Epoch = epoch
+	fork.PreviousVersion = fork.CurrentVersion
+	fork.CurrentVersion = utils.Uint32ToBytes4(uint32(b.BeaconConfig().ElectraForkVersion))
+	b.SetFork(fork)
+	// Update the payload header.
+	header := b.LatestExecutionPayloadHeader()
+	header.Electra()
+	b.SetLatestExecutionPayloadHeader(header)
+	// Update the state root cache
+	b.SetVersion(clparams.ElectraVersion)
+
+	// Set new fields
+	earliestExitEpoch := epoch
+	b.ForEachValidator(func(v solid.Validator, _, _ int) bool {
+		if v.ExitEpoch() != b.BeaconConfig().FarFutureEpoch && v.ExitEpoch() > earliestExitEpoch {
+			earliestExitEpoch = v.ExitEpoch()
+		}
+		return true
+	})
+	b.SetDepositRequestsStartIndex(b.BeaconConfig().UnsetDepositRequestsStartIndex)
+	b.SetDepositBalanceToConsume(0)
+	b.SetEarliestExitEpoch(earliestExitEpoch + 1)
+	b.SetEarliestConsolidationEpoch(ComputeActivationExitEpoch(b.BeaconConfig(), epoch))
+	b.SetPendingBalanceDeposits(solid.NewStaticListSSZ[*cltypes.PendingBalanceDeposit](int(b.BeaconConfig().PendingBalanceDepositsLimit), cltypes.PendingBalanceDepositSize))
+	b.SetExitBalanceToConsume(GetActivationExitChurnLimit(b))
+	b.SetConsolidationBalanceToConsume(GetConsolidationChurnLimit(b))
+
+	// Queue the balances of the validators which are not active yet, ordered by eligibility epoch.
+	preActivation := []uint64{}
+	b.ForEachValidator(func(v solid.Validator, idx, _ int) bool {
+		if v.ActivationEpoch() == b.BeaconConfig().FarFutureEpoch {
+			preActivation = append(preActivation, uint64(idx))
+		}
+		return true
+	})
+	sort.Slice(preActivation, func(i, j int) bool {
+		vi, _ := b.ValidatorForValidatorIndex(int(preActivation[i]))
+		vj, _ := b.ValidatorForValidatorIndex(int(preActivation[j]))
+		if vi.ActivationEligibilityEpoch() != vj.ActivationEligibilityEpoch() {
+			return vi.ActivationEligibilityEpoch() < vj.ActivationEligibilityEpoch()
+		}
+		return preActivation[i] < preActivation[j]
+	})
+	for _, index := range preActivation {
+		if err := QueueEntireBalanceAndResetValidator(b, index); err != nil {
+			return err
+		}
+	}
+	// Queue the excess balance of the compounding validators.
+	for index := 0; index < b.ValidatorLength(); index++ {
+		validator, err := b.ValidatorForValidatorIndex(index)
+		if err != nil {
+			return err
+		}
+		if HasCompoundingWithdrawalCredential(b.BeaconConfig(), validator) {
+			if err := QueueExcessActiveBalance(b, uint64(index)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -42,8 +42,12 @@ func GetIndexedAttestation(attestation *solid.Attestation, attestingIndicies []u
 	sort.Slice(attestingIndicies, func(i, j int) bool {
 		return attestingIndicies[i] < attestingIndicies[j]
 	})
+	version := clparams.Phase0Version
+	if attestation.CommitteeBits() != nil {
+		version = clparams.ElectraVersion
+	}
 	return &cltypes.IndexedAttestation{
-		AttestingIndices: solid.NewRawUint64List(2048, attestingIndicies),
+		AttestingIndices: solid.NewRawUint64List(cltypes.AttestingIndicesLimit(version), attestingIndicies),
 		Data:             attestation.AttestantionData(),
 		Signature:        attestation.Signature(),
 	}
@@ -65,19 +69,52 @@ func ValidatorFromDeposit(conf *clparams.BeaconChainConfig, deposit *cltypes.Dep
 }
 
 // Check whether a validator is fully withdrawable at the given epoch.
-func isFullyWithdrawableValidator(conf *clparams.BeaconChainConfig, validator solid.Validator, balance uint64, epoch uint64) bool {
+func isFullyWithdrawableValidator(conf *clparams.BeaconChainConfig, version clparams.StateVersion, validator solid.Validator, balance uint64, epoch uint64) bool {
+	if version >= clparams.ElectraVersion {
+		return HasExecutionWithdrawalCredential(conf, validator) && validator.WithdrawableEpoch() <= epoch && balance > 0
+	}
 	withdrawalCredentials := validator.WithdrawalCredentials()
 	return withdrawalCredentials[0] == byte(conf.ETH1AddressWithdrawalPrefixByte) &&
 		validator.WithdrawableEpoch() <= epoch && balance > 0
 }
 
 // Check whether a validator is partially withdrawable.
-func isPartiallyWithdrawableValidator(conf *clparams.BeaconChainConfig, validator solid.Validator, balance uint64) bool {
+func isPartiallyWithdrawableValidator(conf *clparams.BeaconChainConfig, version clparams.StateVersion, validator solid.Validator, balance uint64) bool {
+	if version >= clparams.ElectraVersion {
+		maxEffectiveBalance := GetValidatorMaxEffectiveBalance(conf, validator)
+		return HasExecutionWithdrawalCredential(conf, validator) &&
+			validator.EffectiveBalance() == maxEffectiveBalance && balance > maxEffectiveBalance
+	}
 	withdrawalCredentials := validator.WithdrawalCredentials()
 	return withdrawalCredentials[0] == byte(conf.ETH1AddressWithdrawalPrefixByte) &&
 		validator.EffectiveBalance() == conf.MaxEffectiveBalance && balance > conf.MaxEffectiveBalance
 }
 
+// HasEth1WithdrawalCredential checks whether the validator has 0x01 withdrawal credentials.
+func HasEth1WithdrawalCredential(conf *clparams.BeaconChainConfig, validator solid.Validator) bool {
+	withdrawalCredentials := validator.WithdrawalCredentials()
+	return withdrawalCredentials[0] == byte(conf.ETH1AddressWithdrawalPrefixByte)
+}
+
+// HasCompoundingWithdrawalCredential checks whether the validator has 0x02 withdrawal credentials.
+func HasCompoundingWithdrawalCredential(conf *clparams.BeaconChainConfig, validator solid.Validator) bool {
+	withdrawalCredentials := validator.WithdrawalCredentials()
+	return withdrawalCredentials[0] == byte(conf.CompoundingWithdrawalPrefix)
+}
+
+// HasExecutionWithdrawalCredential checks whether the validator has either 0x01 or 0x02 withdrawal credentials.
+func HasExecutionWithdrawalCredential(conf *clparams.BeaconChainConfig, validator solid.Validator) bool {
+	return HasEth1WithdrawalCredential(conf, validator) || HasCompoundingWithdrawalCredential(conf, validator)
+}
+
+// GetValidatorMaxEffectiveBalance returns the maximum effective balance of the validator according to its withdrawal credentials.
+func GetValidatorMaxEffectiveBalance(conf *clparams.BeaconChainConfig, validator solid.Validator) uint64 {
+	if HasCompoundingWithdrawalCredential(conf, validator) {
+		return conf.MaxEffectiveBalanceElectra
+	}
+	return conf.MinActivationBalance
+}
+
 func ComputeActivationExitEpoch(config *clparams.BeaconChainConfig, epoch uint64) uint64 {
 	return epoch + 1 + config.MaxSeedLookahead
 }
@@ -88,6 +88,8 @@ func (cc *ExecutionClientRpc) NewPayload(ctx context.Context, payload *cltypes.E
 		engineMethod = rpc_helper.EngineNewPayloadV2
 	case clparams.DenebVersion:
 		engineMethod = rpc_helper.EngineNewPayloadV3
+	case clparams.ElectraVersion:
+		engineMethod = rpc_helper.EngineNewPayloadV4
 	default:
 		return PayloadStatusNone, fmt.Errorf("invalid payload version")
 	}
@@ -123,6 +125,12 @@ func (cc *ExecutionClientRpc) NewPayload(ctx context.Context, payload *cltypes.E
 		*request.BlobGasUsed = hexutil.Uint64(payload.BlobGasUsed)
 		*request.ExcessBlobGas = hexutil.Uint64(payload.ExcessBlobGas)
 	}
+	// Process Electra
+	if payload.Version() >= clparams.ElectraVersion {
+		request.DepositRequests = payloadBody.Requests.Deposits()
+		request.WithdrawalRequests = payloadBody.Requests.Withdrawals()
+		request.ConsolidationRequests = payloadBody.Requests.Consolidations()
+	}
 
 	payloadStatus := &engine_types.PayloadStatus{} // As it is done in the rpcdaemon
 	log.Debug("[ExecutionClientRpc] Calling EL", "method", engineMethod)
@@ -261,6 +269,21 @@ func (cc *ExecutionClientRpc) HasBlock(ctx context.Context, hash libcommon.Hash)
 	panic("unimplemented")
 }
 
+// HeaderNumber returns the number of the block with given hash, nil if the block is unknown
+func (cc *ExecutionClientRpc) HeaderNumber(ctx context.Context, hash libcommon.Hash) (*uint64, error) {
+	var header *struct {
+		Number hexutil.Uint64 `json:"number"`
+	}
+	if err := cc.client.CallContext(ctx, &header, rpc_helper.GetBlockByHash, hash, false); err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, nil
+	}
+	number := uint64(header.Number)
+	return &number, nil
+}
+
 // Block production
 
 func (cc *ExecutionClientRpc) GetAssembledBlock(ctx context.Context, id []byte) (*cltypes.Eth1Block, *engine_types.BlobsBundleV1, *big.Int, error) {
@@ -19,6 +19,7 @@ package rpc_helper
 const EngineNewPayloadV1 = "engine_newPayloadV1"
 const EngineNewPayloadV2 = "engine_newPayloadV2"
 const EngineNewPayloadV3 = "engine_newPayloadV3"
+const EngineNewPayloadV4 = "engine_newPayloadV4"
 
 const ForkChoiceUpdatedV1 = "engine_forkchoiceUpdatedV1"
 const ForkChoiceUpdatedV2 = "engine_forkchoiceUpdatedV2"
@@ -26,3 +27,5 @@ const ForkChoiceUpdatedV3 = "engine_forkchoiceUpdatedV3"
 
 const GetPayloadBodiesByHashV1 = "engine_getPayloadBodiesByHashV1"
 const GetPayloadBodiesByRangeV1 = "engine_getPayloadBodiesByRangeV1"
+
+const GetBlockByHash = "eth_getBlockByHash"
@@ -0,0 +1,114 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package forkchoice
+
+import (
+	"context"
+	"fmt"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+)
+
+// DepositSnapshot returns the EIP-4881 snapshot of the deposit tree at the last finalized checkpoint, nil if there is none yet.
+func (f *ForkChoiceStore) DepositSnapshot() *cltypes.DepositSnapshot {
+	return f.depositSnapshot.Load()
+}
+
+// LoadDepositSnapshot initializes the deposit tree from a previously taken snapshot.
+func (f *ForkChoiceStore) LoadDepositSnapshot(snapshot *cltypes.DepositSnapshot) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tree, err := snapshot.DepositTree()
+	if err != nil {
+		return err
+	}
+	f.depositTree = tree
+	f.depositSnapshot.Store(snapshot)
+	return nil
+}
+
+// addDeposits appends the deposits included in a block to the deposit tree, depositIndex being the index of the first one.
+// Deposits already in the tree are skipped. If some deposits are missing, the tree is initialized again from the merkle
+// branch of the deposit, as everything before it can be considered finalized.
+func (f *ForkChoiceStore) addDeposits(deposits *solid.ListSSZ[*cltypes.Deposit], depositIndex uint64) error {
+	var err error
+	deposits.Range(func(i int, deposit *cltypes.Deposit, _ int) bool {
+		index := depositIndex + uint64(i)
+		if f.depositTree != nil && index < f.depositTree.DepositCount() {
+			return true
+		}
+		if f.depositTree == nil || index > f.depositTree.DepositCount() {
+			proof := make([]libcommon.Hash, 0, deposit.Proof.Length())
+			deposit.Proof.Range(func(_ int, h libcommon.Hash, _ int) bool {
+				proof = append(proof, h)
+				return true
+			})
+			if f.depositTree, err = merkle_tree.NewDepositTreeFromProof(proof, index); err != nil {
+				return false
+			}
+		}
+		var leaf [32]byte
+		if leaf, err = deposit.Data.HashSSZ(); err != nil {
+			return false
+		}
+		err = f.depositTree.PushLeaf(leaf)
+		return err == nil
+	})
+	if err != nil {
+		f.depositTree = nil
+	}
+	return err
+}
+
+// finalizeDepositTree finalizes the deposits up to the eth1 data of the finalized block and takes a snapshot of the tree.
+// The finalization is postponed if the deposits it covers have not been included in blocks yet.
+func (f *ForkChoiceStore) finalizeDepositTree(ctx context.Context, finalizedRoot libcommon.Hash) error {
+	eth1Data, ok := f.eth1Datas.Get(finalizedRoot)
+	if !ok || f.depositTree == nil || f.engine == nil {
+		return nil
+	}
+	if eth1Data.DepositCount > f.depositTree.DepositCount() {
+		return nil
+	}
+	if _, finalizedCount := f.depositTree.Finalized(); eth1Data.DepositCount < finalizedCount {
+		return nil
+	}
+	blockHeight, err := f.engine.HeaderNumber(ctx, eth1Data.BlockHash)
+	if err != nil {
+		return err
+	}
+	if blockHeight == nil {
+		return fmt.Errorf("execution block %x of finalized eth1 data not found", eth1Data.BlockHash)
+	}
+	if err := f.depositTree.Finalize(eth1Data.DepositCount); err != nil {
+		return err
+	}
+	snapshot, err := cltypes.NewDepositSnapshot(f.depositTree, eth1Data.BlockHash, *blockHeight)
+	if err != nil {
+		return err
+	}
+	if snapshot.DepositRoot != eth1Data.Root {
+		// the tree was fed with deposits which are not part of the canonical chain, start over.
+		f.depositTree = nil
+		return fmt.Errorf("deposit tree root mismatch: expected %x, got %x", eth1Data.Root, snapshot.DepositRoot)
+	}
+	f.depositSnapshot.Store(snapshot)
+	return nil
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
 	"github.com/ledgerwatch/erigon/cl/persistence/blob_storage"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 	state2 "github.com/ledgerwatch/erigon/cl/phase1/core/state"
@@ -121,6 +122,10 @@ type ForkChoiceStore struct {
 	randaoDeltas     *lru.Cache[libcommon.Hash, randaoDelta]       // small entry can be lots of elements.
 	// participation tracking
 	participation *lru.Cache[uint64, *solid.BitList] // epoch -> [partecipation]
+	// EIP-4881 deposit tree, fed with the deposits of the blocks and finalized along with them.
+	eth1Datas       *lru.Cache[libcommon.Hash, *cltypes.Eth1Data]
+	depositTree     *merkle_tree.DepositTree
+	depositSnapshot atomic.Pointer[cltypes.DepositSnapshot]
 
 	mu sync.RWMutex
 
@@ -222,6 +227,12 @@ func NewForkChoiceStore(
 		return nil, err
 	}
 
+	eth1Datas, err := lru.New[libcommon.Hash, *cltypes.Eth1Data](checkpointsPerCache)
+	if err != nil {
+		return nil, err
+	}
+	eth1Datas.Add(anchorRoot, anchorState.Eth1Data().Copy())
+
 	participation.Add(state.Epoch(anchorState.BeaconState), anchorState.CurrentEpochParticipation().Copy())
 
 	totalActiveBalances.Add(anchorRoot, anchorState.GetTotalActiveBalance())
@@ -247,6 +258,7 @@ func NewForkChoiceStore(
 		headSet:               headSet,
 		weights:               make(map[libcommon.Hash]uint64),
 		participation:         participation,
+		eth1Datas:             eth1Datas,
 		emitters:              emitters,
 		genesisTime:           anchorState.GenesisTime(),
 		syncedDataManager:     syncedDataManager,
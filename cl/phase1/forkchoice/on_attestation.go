@@ -133,12 +133,7 @@ func (f *ForkChoiceStore) verifyAttestationWithState(
 	attestation *solid.Attestation,
 	fromBlock bool,
 ) (attestationIndicies []uint64, err error) {
-	data := attestation.AttestantionData()
-	if err != nil {
-		return nil, err
-	}
-
-	attestationIndicies, err = s.GetAttestingIndicies(data, attestation.AggregationBits(), true)
+	attestationIndicies, err = state.GetAttestingIndicesForAttestation(s, attestation, true)
 	if err != nil {
 		return nil, err
 	}
@@ -197,6 +197,12 @@ func (f *ForkChoiceStore) OnBlock(ctx context.Context, block *cltypes.SignedBeac
 	})
 
 	f.totalActiveBalances.Add(blockRoot, lastProcessedState.GetTotalActiveBalance())
+	f.eth1Datas.Add(blockRoot, lastProcessedState.Eth1Data().Copy())
+	if deposits := block.Block.Body.Deposits; deposits.Len() > 0 {
+		if err := f.addDeposits(deposits, lastProcessedState.Eth1DepositIndex()-uint64(deposits.Len())); err != nil {
+			log.Debug("Could not add deposits to the deposit tree", "err", err)
+		}
+	}
 	// Update checkpoints
 	f.updateCheckpoints(lastProcessedState.CurrentJustifiedCheckpoint().Copy(), lastProcessedState.FinalizedCheckpoint().Copy())
 	// First thing save previous values of the checkpoints (avoid memory copy of all states and ensure easy revert)
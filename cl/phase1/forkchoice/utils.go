@@ -17,6 +17,7 @@
 package forkchoice
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/ledgerwatch/erigon/cl/transition"
@@ -64,6 +65,10 @@ func (f *ForkChoiceStore) onNewFinalized(newFinalized solid.Checkpoint) {
 	})
 
 	f.forkGraph.Prune(newFinalized.Epoch() * f.beaconCfg.SlotsPerEpoch)
+
+	if err := f.finalizeDepositTree(context.Background(), newFinalized.BlockRoot()); err != nil {
+		log.Warn("Could not finalize the deposit tree", "err", err)
+	}
 }
 
 // updateCheckpoints updates the justified and finalized checkpoints if new checkpoints have higher epochs.
@@ -146,11 +146,7 @@ func (a *aggregateAndProofServiceImpl) ProcessMessage(
 		log.Warn("receveived aggregate and proof from invalid aggregator")
 		return fmt.Errorf("invalid aggregate and proof")
 	}
-	attestingIndicies, err := headState.GetAttestingIndicies(
-		aggregateAndProof.Message.Aggregate.AttestantionData(),
-		aggregateAndProof.Message.Aggregate.AggregationBits(),
-		true,
-	)
+	attestingIndicies, err := state.GetAttestingIndicesForAttestation(headState, aggregateAndProof.Message.Aggregate, true)
 	if err != nil {
 		return err
 	}
@@ -172,13 +168,8 @@ func verifySignaturesOnAggregate(
 	s *state.CachingBeaconState,
 	aggregateAndProof *cltypes.SignedAggregateAndProof,
 ) error {
-	aggregationBits := aggregateAndProof.Message.Aggregate.AggregationBits()
 	// [REJECT] The aggregate attestation has participants -- that is, len(get_attesting_indices(state, aggregate)) >= 1.
-	attestingIndicies, err := s.GetAttestingIndicies(
-		aggregateAndProof.Message.Aggregate.AttestantionData(),
-		aggregationBits,
-		true,
-	)
+	attestingIndicies, err := state.GetAttestingIndicesForAttestation(s, aggregateAndProof.Message.Aggregate, true)
 	if err != nil {
 		return err
 	}
@@ -676,6 +676,11 @@ func ConsensusClStages(ctx context.Context,
 					if err := state_accessors.IncrementHistoricalRootsTable(tx, headState, preverifiedHistoricalRoots); err != nil {
 						return fmt.Errorf("failed to increment historical roots table: %w", err)
 					}
+					if depositSnapshot := cfg.forkChoice.DepositSnapshot(); depositSnapshot != nil {
+						if err := state_accessors.WriteDepositSnapshot(tx, depositSnapshot); err != nil {
+							return fmt.Errorf("failed to write deposit snapshot: %w", err)
+						}
+					}
 					log.Debug("Incremented state history", "elapsed", time.Since(start), "preverifiedValidators", preverifiedValidators)
 
 					stateRoot, err := headState.HashSSZ()
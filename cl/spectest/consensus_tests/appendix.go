@@ -43,6 +43,8 @@ func init() {
 		With("inactivity_updates", inactivityUpdateTest).
 		With("justification_and_finalization", justificationFinalizationTest).
 		With("participation_flag_updates", participationFlagUpdatesTest).
+		With("pending_balance_deposits", pendingBalanceDepositsTest).
+		With("pending_consolidations", pendingConsolidationsTest).
 		With("randao_mixes_reset", randaoMixesTest).
 		With("registry_updates", registryUpdatesTest).
 		With("rewards_and_penalties", rewardsAndPenaltiesTest).
@@ -76,7 +78,10 @@ func init() {
 		WithFn("voluntary_exit", operationVoluntaryExitHandler).
 		WithFn("sync_aggregate", operationSyncAggregateHandler).
 		WithFn("withdrawals", operationWithdrawalHandler).
-		WithFn("bls_to_execution-change", operationSignedBlsChangeHandler)
+		WithFn("bls_to_execution-change", operationSignedBlsChangeHandler).
+		WithFn("deposit_request", operationDepositRequestHandler).
+		WithFn("withdrawal_request", operationWithdrawalRequestHandler).
+		WithFn("consolidation_request", operationConsolidationRequestHandler)
 	TestFormats.Add("random").
 		With("random", SanityBlocks)
 	TestFormats.Add("rewards").
@@ -112,9 +117,11 @@ func addSszTests() {
 		With("BlobSidecar", getSSZStaticConsensusTest(&cltypes.BlobSidecar{})).
 		With("BLSToExecutionChange", getSSZStaticConsensusTest(&cltypes.BLSToExecutionChange{})).
 		With("Checkpoint", getSSZStaticConsensusTest(solid.Checkpoint{})).
+		With("ConsolidationRequest", getSSZStaticConsensusTest(&cltypes.ConsolidationRequest{})).
 		With("ContributionAndProof", getSSZStaticConsensusTest(&cltypes.ContributionAndProof{})).
 		With("Deposit", getSSZStaticConsensusTest(&cltypes.Deposit{})).
 		With("DepositData", getSSZStaticConsensusTest(&cltypes.DepositData{})).
+		With("DepositRequest", getSSZStaticConsensusTest(&cltypes.DepositRequest{})).
 		//	With("DepositMessage", getSSZStaticConsensusTest(&cltypes.DepositMessage{})).
 		// With("Eth1Block", getSSZStaticConsensusTest(&cltypes.Eth1Block{})).
 		With("Eth1Data", getSSZStaticConsensusTest(&cltypes.Eth1Data{})).
@@ -131,6 +138,9 @@ func addSszTests() {
 		With("LightClientOptimisticUpdate", getSSZStaticConsensusTest(&cltypes.LightClientOptimisticUpdate{})).
 		With("LightClientUpdate", getSSZStaticConsensusTest(&cltypes.LightClientUpdate{})).
 		With("PendingAttestation", getSSZStaticConsensusTest(&solid.PendingAttestation{})).
+		With("PendingBalanceDeposit", getSSZStaticConsensusTest(&cltypes.PendingBalanceDeposit{})).
+		With("PendingConsolidation", getSSZStaticConsensusTest(&cltypes.PendingConsolidation{})).
+		With("PendingPartialWithdrawal", getSSZStaticConsensusTest(&cltypes.PendingPartialWithdrawal{})).
 		//		With("PowBlock", getSSZStaticConsensusTest(&cltypes.PowBlock{})). Unimplemented
 		With("ProposerSlashing", getSSZStaticConsensusTest(&cltypes.ProposerSlashing{})).
 		With("SignedAggregateAndProof", getSSZStaticConsensusTest(&cltypes.SignedAggregateAndProof{})).
@@ -146,7 +156,8 @@ func addSszTests() {
 		With("SyncCommittee", getSSZStaticConsensusTest(&solid.SyncCommittee{})).
 		//	With("SyncCommitteeContribution", getSSZStaticConsensusTest(&cltypes.SyncCommitteeContribution{})).
 		//	With("SyncCommitteeMessage", getSSZStaticConsensusTest(&cltypes.SyncCommitteeMessage{})).
-		With("Validator", getSSZStaticConsensusTest(solid.NewValidator()))
+		With("Validator", getSSZStaticConsensusTest(solid.NewValidator())).
+		With("WithdrawalRequest", getSSZStaticConsensusTest(&cltypes.WithdrawalRequest{}))
 	// With("VoluntaryExit", getSSZStaticConsensusTest(&cltypes.VoluntaryExit{})) TODO
 	// With("Withdrawal", getSSZStaticConsensusTest(&types.Withdrawal{})) TODO
 }
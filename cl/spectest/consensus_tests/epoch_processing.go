@@ -108,6 +108,14 @@ var randaoMixesTest = NewEpochProcessing(func(s abstract.BeaconState) error {
 	return nil
 })
 
+var pendingBalanceDepositsTest = NewEpochProcessing(func(s abstract.BeaconState) error {
+	return statechange.ProcessPendingBalanceDeposits(s)
+})
+
+var pendingConsolidationsTest = NewEpochProcessing(func(s abstract.BeaconState) error {
+	return statechange.ProcessPendingConsolidations(s)
+})
+
 var registryUpdatesTest = NewEpochProcessing(func(s abstract.BeaconState) error {
 	return statechange.ProcessRegistryUpdates(s)
 })
@@ -48,6 +48,8 @@ var ForksFork = spectest.HandlerFunc(func(t *testing.T, root fs.FS, c spectest.T
 		err = preState.UpgradeToCapella()
 	case clparams.CapellaVersion:
 		err = preState.UpgradeToDeneb()
+	case clparams.DenebVersion:
+		err = preState.UpgradeToElectra()
 	default:
 		err = spectest.ErrHandlerNotImplemented(fmt.Sprintf("block state %v", preState.Version()))
 	}
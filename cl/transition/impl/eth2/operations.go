@@ -43,6 +43,9 @@ import (
 	"github.com/ledgerwatch/erigon/cl/utils"
 )
 
+// fullExitRequestAmount is the amount of a withdrawal request asking for a full exit of the validator.
+const fullExitRequestAmount = 0
+
 func (I *impl) ProcessProposerSlashing(
 	s abstract.BeaconState,
 	propSlashing *cltypes.ProposerSlashing,
@@ -191,44 +194,88 @@ func (I *impl) ProcessDeposit(s abstract.BeaconState, deposit *cltypes.Deposit)
 
 	// Increment index
 	s.SetEth1DepositIndex(depositIndex + 1)
-	publicKey := deposit.Data.PubKey
-	amount := deposit.Data.Amount
+	return I.applyDeposit(s, deposit.Data)
+}
+
+// applyDeposit adds a new validator to the registry or tops up an existing one.
+func (I *impl) applyDeposit(s abstract.BeaconState, data *cltypes.DepositData) error {
+	publicKey := data.PubKey
+	amount := data.Amount
 	// Check if pub key is in validator set
 	validatorIndex, has := s.ValidatorIndexByPubkey(publicKey)
 	if !has {
-		// Agnostic domain.
-		domain, err := fork.ComputeDomain(
-			s.BeaconConfig().DomainDeposit[:],
-			utils.Uint32ToBytes4(uint32(s.BeaconConfig().GenesisForkVersion)),
-			[32]byte{},
-		)
+		valid, err := isValidDepositSignature(s, data)
 		if err != nil {
 			return err
 		}
-		depositMessageRoot, err := deposit.Data.MessageHash()
-		if err != nil {
-			return err
-		}
-		signedRoot := utils.Sha256(depositMessageRoot[:], domain)
-		// Perform BLS verification and if successful noice.
-		valid, err := bls.Verify(deposit.Data.Signature[:], signedRoot[:], publicKey[:])
 		// Literally you can input it trash.
-		if !valid || err != nil {
-			log.Debug("Validator BLS verification failed", "valid", valid, "err", err)
+		if !valid {
+			log.Debug("Validator BLS verification failed", "valid", valid)
 			return nil
 		}
-		// Append validator
-		s.AddValidator(state.ValidatorFromDeposit(s.BeaconConfig(), deposit), amount)
+		validator := state.ValidatorFromDeposit(s.BeaconConfig(), &cltypes.Deposit{Data: data})
+		if s.Version() >= clparams.ElectraVersion {
+			// Since Electra, the balance of new validators is processed through the pending balance deposits.
+			validator.SetEffectiveBalance(0)
+			s.AddValidator(validator, 0)
+		} else {
+			s.AddValidator(validator, amount)
+		}
 		// Altair forward
 		if s.Version() >= clparams.AltairVersion {
 			s.AddCurrentEpochParticipationFlags(cltypes.ParticipationFlags(0))
 			s.AddPreviousEpochParticipationFlags(cltypes.ParticipationFlags(0))
 			s.AddInactivityScore(0)
 		}
+		if s.Version() >= clparams.ElectraVersion {
+			s.AppendPendingBalanceDeposit(&cltypes.PendingBalanceDeposit{Index: uint64(s.ValidatorLength() - 1), Amount: amount})
+		}
 		return nil
 	}
-	// Increase the balance if exists already
-	return state.IncreaseBalance(s, validatorIndex, amount)
+	if s.Version() < clparams.ElectraVersion {
+		// Increase the balance if exists already
+		return state.IncreaseBalance(s, validatorIndex, amount)
+	}
+	s.AppendPendingBalanceDeposit(&cltypes.PendingBalanceDeposit{Index: validatorIndex, Amount: amount})
+	validator, err := s.ValidatorForValidatorIndex(int(validatorIndex))
+	if err != nil {
+		return err
+	}
+	// A top-up with compounding credentials switches an eth1 validator to compounding.
+	if data.WithdrawalCredentials[0] != byte(s.BeaconConfig().CompoundingWithdrawalPrefix) ||
+		!state.HasEth1WithdrawalCredential(s.BeaconConfig(), validator) {
+		return nil
+	}
+	valid, err := isValidDepositSignature(s, data)
+	if err != nil || !valid {
+		return nil
+	}
+	return state.SwitchToCompoundingValidator(s, validatorIndex)
+}
+
+// isValidDepositSignature verifies the deposit signature against the fork agnostic deposit domain.
+func isValidDepositSignature(s abstract.BeaconState, data *cltypes.DepositData) (bool, error) {
+	// Agnostic domain.
+	domain, err := fork.ComputeDomain(
+		s.BeaconConfig().DomainDeposit[:],
+		utils.Uint32ToBytes4(uint32(s.BeaconConfig().GenesisForkVersion)),
+		[32]byte{},
+	)
+	if err != nil {
+		return false, err
+	}
+	depositMessageRoot, err := data.MessageHash()
+	if err != nil {
+		return false, err
+	}
+	signedRoot := utils.Sha256(depositMessageRoot[:], domain)
+	// Perform BLS verification and if successful noice.
+	valid, err := bls.Verify(data.Signature[:], signedRoot[:], data.PubKey[:])
+	if err != nil {
+		log.Debug("Validator BLS verification failed", "err", err)
+		return false, nil
+	}
+	return valid, nil
 }
 
 func IsVoluntaryExitApplicable(s abstract.BeaconState, voluntaryExit *cltypes.VoluntaryExit) error {
@@ -251,6 +298,10 @@ func IsVoluntaryExitApplicable(s abstract.BeaconState, voluntaryExit *cltypes.Vo
 	if currentEpoch < validator.ActivationEpoch()+s.BeaconConfig().ShardCommitteePeriod {
 		return errors.New("ProcessVoluntaryExit: exit is happening too fast")
 	}
+	// Only exit validator if it has no pending withdrawals in the queue
+	if s.Version() >= clparams.ElectraVersion && state.GetPendingBalanceToWithdraw(s, voluntaryExit.ValidatorIndex) != 0 {
+		return errors.New("ProcessVoluntaryExit: validator has pending withdrawals")
+	}
 	return nil
 }
 
@@ -309,9 +360,9 @@ func (I *impl) ProcessWithdrawals(
 	beaconConfig := s.BeaconConfig()
 	numValidators := uint64(s.ValidatorLength())
 
+	expectedWithdrawals, partialWithdrawalsCount := state.GetExpectedWithdrawals(s, state.Epoch(s))
 	// Check if full validation is required and verify expected withdrawals.
 	if I.FullValidation {
-		expectedWithdrawals := state.ExpectedWithdrawals(s, state.Epoch(s))
 		if len(expectedWithdrawals) != withdrawals.Len() {
 			return fmt.Errorf(
 				"ProcessWithdrawals: expected %d withdrawals, but got %d",
@@ -337,6 +388,9 @@ func (I *impl) ProcessWithdrawals(
 	}); err != nil {
 		return err
 	}
+	if s.Version() >= clparams.ElectraVersion {
+		s.CutPendingPartialWithdrawals(int(partialWithdrawalsCount))
+	}
 
 	// Update next withdrawal index based on number of withdrawals.
 	if withdrawals.Len() > 0 {
@@ -356,6 +410,141 @@ func (I *impl) ProcessWithdrawals(
 	return nil
 }
 
+// ProcessDepositRequest applies a deposit request from the execution layer.
+// Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-process_deposit_request
+func (I *impl) ProcessDepositRequest(s abstract.BeaconState, depositRequest *cltypes.DepositRequest) error {
+	// Set deposit request start index
+	if s.DepositRequestsStartIndex() == s.BeaconConfig().UnsetDepositRequestsStartIndex {
+		s.SetDepositRequestsStartIndex(depositRequest.Index)
+	}
+	return I.applyDeposit(s, &cltypes.DepositData{
+		PubKey:                depositRequest.PubKey,
+		WithdrawalCredentials: depositRequest.WithdrawalCredentials,
+		Amount:                depositRequest.Amount,
+		Signature:             depositRequest.Signature,
+	})
+}
+
+// ProcessWithdrawalRequest applies a full exit or a partial withdrawal request from the execution layer.
+// Invalid requests are ignored rather than failing the block.
+// Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-process_withdrawal_request
+func (I *impl) ProcessWithdrawalRequest(s abstract.BeaconState, withdrawalRequest *cltypes.WithdrawalRequest) error {
+	beaconConfig := s.BeaconConfig()
+	amount := withdrawalRequest.Amount
+	isFullExitRequest := amount == fullExitRequestAmount
+	// If partial withdrawal queue is full, only full exits are processed
+	if s.PendingPartialWithdrawals().Len() == int(beaconConfig.PendingPartialWithdrawalsLimit) && !isFullExitRequest {
+		return nil
+	}
+	index, has := s.ValidatorIndexByPubkey(withdrawalRequest.ValidatorPubKey)
+	if !has {
+		return nil
+	}
+	validator, err := s.ValidatorForValidatorIndex(int(index))
+	if err != nil {
+		return err
+	}
+	// Verify withdrawal credentials
+	withdrawalCredentials := validator.WithdrawalCredentials()
+	if !state.HasExecutionWithdrawalCredential(beaconConfig, validator) ||
+		!bytes.Equal(withdrawalCredentials[12:], withdrawalRequest.SourceAddress[:]) {
+		return nil
+	}
+	currentEpoch := state.Epoch(s)
+	// Verify the validator is active, has not initiated an exit and has been active long enough
+	if !validator.Active(currentEpoch) || validator.ExitEpoch() != beaconConfig.FarFutureEpoch ||
+		currentEpoch < validator.ActivationEpoch()+beaconConfig.ShardCommitteePeriod {
+		return nil
+	}
+	pendingBalanceToWithdraw := state.GetPendingBalanceToWithdraw(s, index)
+	if isFullExitRequest {
+		// Only exit validator if it has no pending withdrawals in the queue
+		if pendingBalanceToWithdraw == 0 {
+			return s.InitiateValidatorExit(index)
+		}
+		return nil
+	}
+	balance, err := s.ValidatorBalance(int(index))
+	if err != nil {
+		return err
+	}
+	hasSufficientEffectiveBalance := validator.EffectiveBalance() >= beaconConfig.MinActivationBalance
+	hasExcessBalance := balance > beaconConfig.MinActivationBalance+pendingBalanceToWithdraw
+	// Only allow partial withdrawals with compounding withdrawal credentials
+	if !state.HasCompoundingWithdrawalCredential(beaconConfig, validator) || !hasSufficientEffectiveBalance || !hasExcessBalance {
+		return nil
+	}
+	toWithdraw := min(balance-beaconConfig.MinActivationBalance-pendingBalanceToWithdraw, amount)
+	exitQueueEpoch := state.ComputeExitEpochAndUpdateChurn(s, toWithdraw)
+	s.AppendPendingPartialWithdrawal(&cltypes.PendingPartialWithdrawal{
+		Index:             index,
+		Amount:            toWithdraw,
+		WithdrawableEpoch: exitQueueEpoch + beaconConfig.MinValidatorWithdrawabilityDelay,
+	})
+	return nil
+}
+
+// ProcessConsolidationRequest schedules the consolidation of a source validator into a target validator.
+// Invalid requests are ignored rather than failing the block.
+// Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-process_consolidation_request
+func (I *impl) ProcessConsolidationRequest(s abstract.BeaconState, consolidationRequest *cltypes.ConsolidationRequest) error {
+	beaconConfig := s.BeaconConfig()
+	// If the pending consolidations queue is full, consolidation requests are ignored
+	if s.PendingConsolidations().Len() == int(beaconConfig.PendingConsolidationsLimit) {
+		return nil
+	}
+	// If there is too little available consolidation churn limit, consolidation requests are ignored
+	if state.GetConsolidationChurnLimit(s) <= beaconConfig.MinActivationBalance {
+		return nil
+	}
+	sourceIndex, has := s.ValidatorIndexByPubkey(consolidationRequest.SourcePubKey)
+	if !has {
+		return nil
+	}
+	targetIndex, has := s.ValidatorIndexByPubkey(consolidationRequest.TargetPubKey)
+	if !has {
+		return nil
+	}
+	// Verify that source != target, so a consolidation cannot be used as an exit.
+	if sourceIndex == targetIndex {
+		return nil
+	}
+	sourceValidator, err := s.ValidatorForValidatorIndex(int(sourceIndex))
+	if err != nil {
+		return err
+	}
+	targetValidator, err := s.ValidatorForValidatorIndex(int(targetIndex))
+	if err != nil {
+		return err
+	}
+	// Verify source withdrawal credentials
+	sourceWithdrawalCredentials := sourceValidator.WithdrawalCredentials()
+	if !state.HasExecutionWithdrawalCredential(beaconConfig, sourceValidator) ||
+		!bytes.Equal(sourceWithdrawalCredentials[12:], consolidationRequest.SourceAddress[:]) {
+		return nil
+	}
+	// Verify that target has execution withdrawal credentials
+	if !state.HasExecutionWithdrawalCredential(beaconConfig, targetValidator) {
+		return nil
+	}
+	// Verify the source and the target are active and have not initiated an exit
+	currentEpoch := state.Epoch(s)
+	if !sourceValidator.Active(currentEpoch) || !targetValidator.Active(currentEpoch) {
+		return nil
+	}
+	if sourceValidator.ExitEpoch() != beaconConfig.FarFutureEpoch || targetValidator.ExitEpoch() != beaconConfig.FarFutureEpoch {
+		return nil
+	}
+	// Initiate source validator exit and append pending consolidation
+	exitEpoch := state.ComputeConsolidationEpochAndUpdateChurn(s, sourceValidator.EffectiveBalance())
+	s.SetExitEpochForValidatorAtIndex(int(sourceIndex), exitEpoch)
+	if err := s.SetWithdrawableEpochForValidatorAtIndex(int(sourceIndex), exitEpoch+beaconConfig.MinValidatorWithdrawabilityDelay); err != nil {
+		return err
+	}
+	s.AppendPendingConsolidation(&cltypes.PendingConsolidation{SourceIndex: sourceIndex, TargetIndex: targetIndex})
+	return nil
+}
+
 // ProcessExecutionPayload sets the latest payload header accordinly.
 func (I *impl) ProcessExecutionPayload(s abstract.BeaconState, parentHash, prevRandao common.Hash, time uint64, payloadHeader *cltypes.Eth1Header) error {
 	if state.IsMergeTransitionComplete(s) {
@@ -594,7 +783,7 @@ func (I *impl) processAttestationPostAltair(
 
 	c = h.Tag("step", "get_attesting_indices")
 
-	attestingIndicies, err := s.GetAttestingIndicies(data, attestation.AggregationBits(), true)
+	attestingIndicies, err := state.GetAttestingIndicesForAttestation(s, attestation, true)
 	if err != nil {
 		return nil, err
 	}
@@ -787,6 +976,16 @@ func IsAttestationApplicable(s abstract.BeaconState, attestation *solid.Attestat
 		data.Slot()+beaconConfig.MinAttestationInclusionDelay > stateSlot {
 		return errors.New("ProcessAttestation: attestation slot not in range")
 	}
+	if s.Version() >= clparams.ElectraVersion {
+		// The committees are selected by the committee bits, which are checked when computing the attesting indices.
+		if data.CommitteeIndex() != 0 {
+			return errors.New("ProcessAttestation: attestation data index must be 0")
+		}
+		if attestation.CommitteeBits() == nil {
+			return errors.New("ProcessAttestation: attestation without committee bits")
+		}
+		return nil
+	}
 	if data.CommitteeIndex() >= s.CommitteeCount(data.Target().Epoch()) {
 		return errors.New("ProcessAttestation: attester index out of range")
 	}
@@ -1030,6 +1229,11 @@ func (I *impl) ProcessSlots(s abstract.BeaconState, slot uint64) error {
 				return err
 			}
 		}
+		if state.Epoch(s) == beaconConfig.ElectraForkEpoch {
+			if err := s.UpgradeToElectra(); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
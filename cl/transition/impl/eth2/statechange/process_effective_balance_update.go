@@ -18,7 +18,9 @@ package statechange
 
 import (
 	"github.com/ledgerwatch/erigon/cl/abstract"
+	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	state2 "github.com/ledgerwatch/erigon/cl/phase1/core/state"
 )
 
 // ProcessEffectiveBalanceUpdates updates the effective balance of validators. Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/phase0/beacon-chain.md#effective-balances-updates
@@ -39,7 +41,11 @@ func ProcessEffectiveBalanceUpdates(state abstract.BeaconState) error {
 		eb := validator.EffectiveBalance()
 		if balance+downwardThreshold < eb || eb+upwardThreshold < balance {
 			// Set new effective balance
-			effectiveBalance := min(balance-(balance%beaconConfig.EffectiveBalanceIncrement), beaconConfig.MaxEffectiveBalance)
+			maxEffectiveBalance := beaconConfig.MaxEffectiveBalance
+			if state.Version() >= clparams.ElectraVersion {
+				maxEffectiveBalance = state2.GetValidatorMaxEffectiveBalance(beaconConfig, validator)
+			}
+			effectiveBalance := min(balance-(balance%beaconConfig.EffectiveBalanceIncrement), maxEffectiveBalance)
 			state.SetEffectiveBalanceForValidatorAtIndex(index, effectiveBalance)
 		}
 		return true
@@ -0,0 +1,78 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package statechange
+
+import (
+	"github.com/ledgerwatch/erigon/cl/abstract"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+)
+
+// ProcessPendingBalanceDeposits credits the queued deposits within the activation/exit churn.
+// Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-process_pending_balance_deposits
+func ProcessPendingBalanceDeposits(s abstract.BeaconState) error {
+	beaconConfig := s.BeaconConfig()
+	nextEpoch := state.Epoch(s) + 1
+	availableForProcessing := s.DepositBalanceToConsume() + state.GetActivationExitChurnLimit(s)
+	processedAmount := uint64(0)
+	nextDepositIndex := 0
+	var depositsToPostpone []*cltypes.PendingBalanceDeposit
+
+	var err error
+	s.PendingBalanceDeposits().Range(func(_ int, deposit *cltypes.PendingBalanceDeposit, _ int) bool {
+		var validator solid.Validator
+		if validator, err = s.ValidatorForValidatorIndex(int(deposit.Index)); err != nil {
+			return false
+		}
+		if validator.ExitEpoch() < beaconConfig.FarFutureEpoch {
+			// Exiting validators get the deposit after their withdrawable epoch, without consuming churn.
+			if nextEpoch <= validator.WithdrawableEpoch() {
+				depositsToPostpone = append(depositsToPostpone, deposit)
+			} else if err = state.IncreaseBalance(s, deposit.Index, deposit.Amount); err != nil {
+				return false
+			}
+		} else {
+			// The deposit does not fit in the churn, stop processing for this epoch.
+			if processedAmount+deposit.Amount > availableForProcessing {
+				return false
+			}
+			if err = state.IncreaseBalance(s, deposit.Index, deposit.Amount); err != nil {
+				return false
+			}
+			processedAmount += deposit.Amount
+		}
+		nextDepositIndex++
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	pending := s.PendingBalanceDeposits()
+	pending.Cut(nextDepositIndex)
+	if pending.Len() == 0 {
+		s.SetDepositBalanceToConsume(0)
+	} else {
+		s.SetDepositBalanceToConsume(availableForProcessing - processedAmount)
+	}
+	for _, deposit := range depositsToPostpone {
+		pending.Append(deposit)
+	}
+	s.SetPendingBalanceDeposits(pending)
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package statechange
+
+import (
+	"github.com/ledgerwatch/erigon/cl/abstract"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+)
+
+// ProcessPendingConsolidations moves the active balance of every withdrawable consolidation source into its target.
+// Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-process_pending_consolidations
+func ProcessPendingConsolidations(s abstract.BeaconState) error {
+	currentEpoch := state.Epoch(s)
+	nextPendingConsolidation := 0
+
+	var err error
+	s.PendingConsolidations().Range(func(_ int, consolidation *cltypes.PendingConsolidation, _ int) bool {
+		var source solid.Validator
+		if source, err = s.ValidatorForValidatorIndex(int(consolidation.SourceIndex)); err != nil {
+			return false
+		}
+		if source.Slashed() {
+			nextPendingConsolidation++
+			return true
+		}
+		if source.WithdrawableEpoch() > currentEpoch {
+			return false
+		}
+		// Churn any target excess active balance of target and raise its max.
+		if err = state.SwitchToCompoundingValidator(s, consolidation.TargetIndex); err != nil {
+			return false
+		}
+		// Move active balance to target, the excess balance stays withdrawable.
+		var activeBalance uint64
+		if activeBalance, err = state.GetActiveBalance(s, consolidation.SourceIndex); err != nil {
+			return false
+		}
+		if err = state.DecreaseBalance(s, consolidation.SourceIndex, activeBalance); err != nil {
+			return false
+		}
+		if err = state.IncreaseBalance(s, consolidation.TargetIndex, activeBalance); err != nil {
+			return false
+		}
+		nextPendingConsolidation++
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	s.CutPendingConsolidations(nextPendingConsolidation)
+	return nil
+}
@@ -39,6 +39,9 @@ type minimizeQueuedValidator struct {
 
 // ProcessRegistyUpdates updates every epoch the activation status of validators. Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/phase0/beacon-chain.md#registry-updates.
 func ProcessRegistryUpdates(s abstract.BeaconState) error {
+	if s.Version() >= clparams.ElectraVersion {
+		return processRegistryUpdatesElectra(s)
+	}
 	beaconConfig := s.BeaconConfig()
 	currentEpoch := state.Epoch(s)
 	// start also initializing the activation queue.
@@ -88,3 +91,25 @@ func ProcessRegistryUpdates(s abstract.BeaconState) error {
 	}
 	return nil
 }
+
+// processRegistryUpdatesElectra activates all the eligible validators, since the activation churn is now applied to the pending deposits.
+// Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#modified-process_registry_updates
+func processRegistryUpdatesElectra(s abstract.BeaconState) error {
+	beaconConfig := s.BeaconConfig()
+	currentEpoch := state.Epoch(s)
+	activationEpoch := computeActivationExitEpoch(beaconConfig, currentEpoch)
+	var err error
+	s.ForEachValidator(func(validator solid.Validator, validatorIndex, total int) bool {
+		if state.IsValidatorEligibleForActivationQueue(s, validator) {
+			s.SetActivationEligibilityEpochForValidatorAtIndex(validatorIndex, currentEpoch+1)
+		} else if validator.Active(currentEpoch) && validator.EffectiveBalance() <= beaconConfig.EjectionBalance {
+			if err = s.InitiateValidatorExit(uint64(validatorIndex)); err != nil {
+				return false
+			}
+		} else if state.IsValidatorEligibleForActivation(s, validator) {
+			s.SetActivationEpochForValidatorAtIndex(validatorIndex, activationEpoch)
+		}
+		return true
+	})
+	return err
+}
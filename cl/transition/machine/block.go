@@ -98,6 +98,10 @@ func ProcessBlindedBlock(impl BlockProcessor, s abstract.BeaconState, block *clt
 	if block.Version() != version {
 		return fmt.Errorf("processBlindedBlock: wrong state version for block at slot %d", block.Slot)
 	}
+	// Since Electra the execution requests are state-changing operations and only their roots are in the blinded payload.
+	if version >= clparams.ElectraVersion {
+		return fmt.Errorf("processBlindedBlock: cannot process blinded block at slot %d without execution requests", block.Slot)
+	}
 	h := metrics.NewHistTimer("beacon_process_blinded_block")
 	bodyRoot, err := block.Body.HashSSZ()
 	if err != nil {
@@ -212,11 +216,47 @@ func ProcessOperations(impl BlockOperationProcessor, s abstract.BeaconState, blo
 	}); err != nil {
 		return err
 	}
+	if s.Version() < clparams.ElectraVersion {
+		return nil
+	}
+	// Process the execution layer requests, which are part of the execution payload since Electra.
+	if err := solid.RangeErr[*cltypes.DepositRequest](blockBody.ExecutionPayload.DepositRequests, func(index int, depositRequest *cltypes.DepositRequest, length int) error {
+		if err := impl.ProcessDepositRequest(s, depositRequest); err != nil {
+			return fmt.Errorf("ProcessDepositRequest: %s", err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := solid.RangeErr[*cltypes.WithdrawalRequest](blockBody.ExecutionPayload.WithdrawalRequests, func(index int, withdrawalRequest *cltypes.WithdrawalRequest, length int) error {
+		if err := impl.ProcessWithdrawalRequest(s, withdrawalRequest); err != nil {
+			return fmt.Errorf("ProcessWithdrawalRequest: %s", err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := solid.RangeErr[*cltypes.ConsolidationRequest](blockBody.ExecutionPayload.ConsolidationRequests, func(index int, consolidationRequest *cltypes.ConsolidationRequest, length int) error {
+		if err := impl.ProcessConsolidationRequest(s, consolidationRequest); err != nil {
+			return fmt.Errorf("ProcessConsolidationRequest: %s", err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
 func maximumDeposits(s abstract.BeaconState) (maxDeposits uint64) {
-	maxDeposits = s.Eth1Data().DepositCount - s.Eth1DepositIndex()
+	depositIndexLimit := s.Eth1Data().DepositCount
+	// Since Electra, the deposits from the eth1 bridge stop once the deposit requests take over.
+	if s.Version() >= clparams.ElectraVersion {
+		depositIndexLimit = min(depositIndexLimit, s.DepositRequestsStartIndex())
+	}
+	if s.Eth1DepositIndex() >= depositIndexLimit {
+		return 0
+	}
+	maxDeposits = depositIndexLimit - s.Eth1DepositIndex()
 	if maxDeposits > s.BeaconConfig().MaxDeposits {
 		maxDeposits = s.BeaconConfig().MaxDeposits
 	}
@@ -0,0 +1,55 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/beacon/handler"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+)
+
+// BeaconNode is the part of the beacon node the validator client performs its duties through. It is implemented by
+// the beacon API handler of the node the validator client runs in, whose methods return handler.ErrNodeSyncing
+// until the head state is available.
+type BeaconNode interface {
+	ValidatorIndicies(pubkeys []libcommon.Bytes48) (map[libcommon.Bytes48]uint64, error)
+	ProposerDuties(ctx context.Context, epoch uint64) ([]handler.ProposerDuty, error)
+	AttesterDuties(ctx context.Context, epoch uint64, idxs []uint64) ([]handler.AttesterDuty, error)
+	SyncDuties(ctx context.Context, epoch uint64, idxs []uint64) ([]*handler.SyncDuty, error)
+	SubscribeToBeaconCommittees(ctx context.Context, req []*cltypes.BeaconCommitteeSubscription) error
+	SubscribeToSyncCommittees(ctx context.Context, req []handler.ValidatorSyncCommitteeSubscriptionsRequest) error
+	PrepareBeaconProposers(req []handler.ValidatorPreparationPayload)
+
+	ProduceBlock(ctx context.Context, targetSlot uint64, randaoReveal libcommon.Bytes96, graffiti libcommon.Hash, builderBoostFactor uint64) (*cltypes.BlindOrExecutionBeaconBlock, error)
+	PublishBlock(ctx context.Context, blk *cltypes.SignedBeaconBlock) error
+	PublishBlindedBlock(ctx context.Context, blk *cltypes.SignedBlindedBeaconBlock) error
+
+	ProduceAttestationData(slot, committeeIndex uint64) (solid.AttestationData, error)
+	PublishAttestations(ctx context.Context, attestations []*solid.Attestation) error
+	AggregateAttestation(slot uint64, attestationDataRoot libcommon.Hash) (*solid.Attestation, error)
+	PublishAggregateAndProofs(ctx context.Context, aggregates []*cltypes.SignedAggregateAndProof) error
+
+	HeadBlockRoot() (libcommon.Hash, error)
+	PublishSyncCommitteeMessages(ctx context.Context, msgs []*cltypes.SyncCommitteeMessage) error
+	SyncCommitteeContribution(slot, subcommitteeIndex uint64, beaconBlockRoot libcommon.Hash) *cltypes.Contribution
+	PublishContributionAndProofs(ctx context.Context, msgs []*cltypes.SignedContributionAndProof) error
+}
+
+var _ BeaconNode = (*handler.ApiHandler)(nil)
@@ -0,0 +1,148 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/beacon/handler"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/validator/web3signer"
+)
+
+// attesterDuty is an attestation duty along with the aggregator selection of the validator for it.
+type attesterDuty struct {
+	handler.AttesterDuty
+
+	selectionProof libcommon.Bytes96
+	isAggregator   bool
+}
+
+// epochDuties holds all the duties of the local validators for one epoch.
+type epochDuties struct {
+	epoch    uint64
+	attester []*attesterDuty
+	proposer []handler.ProposerDuty
+	sync     []*handler.SyncDuty
+}
+
+// refreshIndicies resolves the validator indicies of the held keys which are already known to the beacon state.
+func (v *ValidatorClient) refreshIndicies() error {
+	pubkeys := v.signer.pubkeys()
+	indicies := make(map[uint64]libcommon.Bytes48, len(pubkeys))
+	if len(pubkeys) > 0 {
+		byPubkey, err := v.node.ValidatorIndicies(pubkeys)
+		if err != nil {
+			return err
+		}
+		for pubkey, idx := range byPubkey {
+			indicies[idx] = pubkey
+		}
+	}
+	v.indicies = indicies
+	return nil
+}
+
+// fetchDuties retrieves the duties of the local validators for the given epoch and subscribes to the relevant subnets.
+func (v *ValidatorClient) fetchDuties(ctx context.Context, epoch uint64) (*epochDuties, error) {
+	duties := &epochDuties{epoch: epoch}
+	if len(v.indicies) == 0 {
+		return duties, nil
+	}
+	indicies := make([]uint64, 0, len(v.indicies))
+	for idx := range v.indicies {
+		indicies = append(indicies, idx)
+	}
+	proposers, err := v.node.ProposerDuties(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	for _, duty := range proposers {
+		if v.signer.hasKey(duty.Pubkey) {
+			duties.proposer = append(duties.proposer, duty)
+		}
+	}
+	attester, err := v.node.AttesterDuties(ctx, epoch, indicies)
+	if err != nil {
+		return nil, err
+	}
+	for _, duty := range attester {
+		duties.attester = append(duties.attester, &attesterDuty{AttesterDuty: duty})
+	}
+	if duties.sync, err = v.node.SyncDuties(ctx, epoch, indicies); err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]*cltypes.BeaconCommitteeSubscription, 0, len(duties.attester))
+	for _, duty := range duties.attester {
+		root, err := v.signer.epochSigningRoot(duty.Slot, v.beaconCfg.DomainSelectionProof, epoch)
+		if err != nil {
+			return nil, err
+		}
+		duty.selectionProof, err = v.signer.sign(ctx, duty.Pubkey, epoch, &web3signer.SignRequest{
+			Type:            web3signer.TypeAggregationSlot,
+			SigningRoot:     root,
+			AggregationSlot: &web3signer.AggregationSlot{Slot: duty.Slot},
+		})
+		if err != nil {
+			return nil, err
+		}
+		duty.isAggregator = isAggregator(duty.selectionProof, duty.CommitteeLength/v.beaconCfg.TargetAggregatorsPerCommittee)
+		subscriptions = append(subscriptions, &cltypes.BeaconCommitteeSubscription{
+			ValidatorIndex:   duty.ValidatorIndex,
+			CommitteeIndex:   duty.CommitteeIndex,
+			CommitteesAtSlot: duty.CommitteesAtSlot,
+			Slot:             duty.Slot,
+			IsAggregator:     duty.isAggregator,
+		})
+	}
+	if len(subscriptions) > 0 {
+		if err := v.node.SubscribeToBeaconCommittees(ctx, subscriptions); err != nil {
+			return nil, err
+		}
+	}
+	syncSubscriptions := make([]handler.ValidatorSyncCommitteeSubscriptionsRequest, 0, len(duties.sync))
+	for _, duty := range duties.sync {
+		syncSubscriptions = append(syncSubscriptions, handler.ValidatorSyncCommitteeSubscriptionsRequest{
+			ValidatorIndex:        duty.ValidatorIndex,
+			SyncCommitteeIndicies: duty.ValidatorSyncCommitteeIndicies,
+			UntilEpoch:            epoch + 1,
+		})
+	}
+	if len(syncSubscriptions) > 0 {
+		if err := v.node.SubscribeToSyncCommittees(ctx, syncSubscriptions); err != nil {
+			return nil, err
+		}
+	}
+	return duties, nil
+}
+
+// prepareBeaconProposer registers the fee recipient of the local validators with the beacon node.
+func (v *ValidatorClient) prepareBeaconProposer() {
+	if len(v.indicies) == 0 {
+		return
+	}
+	req := make([]handler.ValidatorPreparationPayload, 0, len(v.indicies))
+	for idx := range v.indicies {
+		req = append(req, handler.ValidatorPreparationPayload{
+			ValidatorIndex: idx,
+			FeeRecipient:   v.cfg.FeeRecipient,
+		})
+	}
+	v.node.PrepareBeaconProposers(req)
+}
@@ -0,0 +1,194 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+const interchangeFormatVersion = "5"
+
+// Interchange is the EIP-3076 slashing protection interchange format.
+// Specs at: https://eips.ethereum.org/EIPS/eip-3076
+type Interchange struct {
+	Metadata InterchangeMetadata `json:"metadata"`
+	Data     []InterchangeData   `json:"data"`
+}
+
+type InterchangeMetadata struct {
+	InterchangeFormatVersion string         `json:"interchange_format_version"`
+	GenesisValidatorsRoot    libcommon.Hash `json:"genesis_validators_root"`
+}
+
+type InterchangeData struct {
+	Pubkey             libcommon.Bytes48        `json:"pubkey"`
+	SignedBlocks       []InterchangeBlock       `json:"signed_blocks"`
+	SignedAttestations []InterchangeAttestation `json:"signed_attestations"`
+}
+
+type InterchangeBlock struct {
+	Slot        uint64          `json:"slot,string"`
+	SigningRoot *libcommon.Hash `json:"signing_root,omitempty"`
+}
+
+type InterchangeAttestation struct {
+	SourceEpoch uint64          `json:"source_epoch,string"`
+	TargetEpoch uint64          `json:"target_epoch,string"`
+	SigningRoot *libcommon.Hash `json:"signing_root,omitempty"`
+}
+
+// ImportInterchange merges an EIP-3076 interchange file into the database. Records conflicting with the
+// ones already stored lose their signing root, so that neither message can be signed again.
+func (s *SlashingProtection) ImportInterchange(ctx context.Context, r io.Reader, genesisValidatorsRoot libcommon.Hash) error {
+	var interchange Interchange
+	if err := json.NewDecoder(r).Decode(&interchange); err != nil {
+		return err
+	}
+	if interchange.Metadata.InterchangeFormatVersion != interchangeFormatVersion {
+		return fmt.Errorf("unsupported interchange format version %q", interchange.Metadata.InterchangeFormatVersion)
+	}
+	if interchange.Metadata.GenesisValidatorsRoot != genesisValidatorsRoot {
+		return fmt.Errorf("interchange genesis validators root %x does not match %x", interchange.Metadata.GenesisValidatorsRoot, genesisValidatorsRoot)
+	}
+	return s.db.Update(ctx, func(tx kv.RwTx) error {
+		if err := checkGenesisValidatorsRoot(tx, genesisValidatorsRoot); err != nil {
+			return err
+		}
+		for _, data := range interchange.Data {
+			for _, block := range data.SignedBlocks {
+				if err := mergeRecord(tx, kv.SlashingProtectionBlocks, protectionKey(data.Pubkey, block.Slot), signingRootBytes(block.SigningRoot), 0); err != nil {
+					return err
+				}
+			}
+			for _, attestation := range data.SignedAttestations {
+				if attestation.SourceEpoch > attestation.TargetEpoch {
+					return fmt.Errorf("invalid attestation for %x: source epoch %d is after target epoch %d", data.Pubkey, attestation.SourceEpoch, attestation.TargetEpoch)
+				}
+				record := attestationRecord(attestation.SourceEpoch, libcommon.BytesToHash(signingRootBytes(attestation.SigningRoot)))
+				if err := mergeRecord(tx, kv.SlashingProtectionAttestations, protectionKey(data.Pubkey, attestation.TargetEpoch), record, 8); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func signingRootBytes(root *libcommon.Hash) []byte {
+	if root == nil {
+		return make([]byte, 32)
+	}
+	return root[:]
+}
+
+// mergeRecord stores record at key, clearing the signing root (which starts at rootOffset) when the record
+// conflicts with an existing one.
+func mergeRecord(tx kv.RwTx, table string, key, record []byte, rootOffset int) error {
+	existing, err := tx.GetOne(table, key)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 && !bytes.Equal(existing, record) {
+		record = libcommon.Copy(record)
+		if !bytes.Equal(existing[:rootOffset], record[:rootOffset]) {
+			// keep the lowest source epoch, it is the most conservative for the surround checks.
+			if binary.BigEndian.Uint64(existing[:rootOffset]) < binary.BigEndian.Uint64(record[:rootOffset]) {
+				copy(record, existing[:rootOffset])
+			}
+		}
+		clear(record[rootOffset:])
+	}
+	return tx.Put(table, key, record)
+}
+
+// ExportInterchange writes the database in the EIP-3076 interchange format. If pubkeys is not nil, only the
+// records of those keys are exported.
+func (s *SlashingProtection) ExportInterchange(ctx context.Context, w io.Writer, pubkeys []libcommon.Bytes48) error {
+	interchange := Interchange{
+		Metadata: InterchangeMetadata{InterchangeFormatVersion: interchangeFormatVersion},
+	}
+	data := map[libcommon.Bytes48]*InterchangeData{}
+	getData := func(k []byte) *InterchangeData {
+		pubkey := libcommon.Bytes48(k[:48])
+		if _, ok := data[pubkey]; !ok {
+			data[pubkey] = &InterchangeData{Pubkey: pubkey, SignedBlocks: []InterchangeBlock{}, SignedAttestations: []InterchangeAttestation{}}
+		}
+		return data[pubkey]
+	}
+	exported := func(k []byte) bool {
+		return pubkeys == nil || slices.Contains(pubkeys, libcommon.Bytes48(k[:48]))
+	}
+	if err := s.db.View(ctx, func(tx kv.Tx) error {
+		root, err := tx.GetOne(kv.SlashingProtectionMetadata, genesisValidatorsRootKey)
+		if err != nil {
+			return err
+		}
+		interchange.Metadata.GenesisValidatorsRoot = libcommon.BytesToHash(root)
+		if err := tx.ForEach(kv.SlashingProtectionBlocks, nil, func(k, v []byte) error {
+			if !exported(k) {
+				return nil
+			}
+			d := getData(k)
+			d.SignedBlocks = append(d.SignedBlocks, InterchangeBlock{Slot: binary.BigEndian.Uint64(k[48:]), SigningRoot: exportedSigningRoot(v)})
+			return nil
+		}); err != nil {
+			return err
+		}
+		return tx.ForEach(kv.SlashingProtectionAttestations, nil, func(k, v []byte) error {
+			if !exported(k) {
+				return nil
+			}
+			d := getData(k)
+			d.SignedAttestations = append(d.SignedAttestations, InterchangeAttestation{
+				SourceEpoch: binary.BigEndian.Uint64(v[:8]),
+				TargetEpoch: binary.BigEndian.Uint64(k[48:]),
+				SigningRoot: exportedSigningRoot(v[8:]),
+			})
+			return nil
+		})
+	}); err != nil {
+		return err
+	}
+	interchange.Data = make([]InterchangeData, 0, len(data))
+	for _, d := range data {
+		interchange.Data = append(interchange.Data, *d)
+	}
+	sort.Slice(interchange.Data, func(i, j int) bool {
+		return bytes.Compare(interchange.Data[i].Pubkey[:], interchange.Data[j].Pubkey[:]) < 0
+	})
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(interchange)
+}
+
+func exportedSigningRoot(v []byte) *libcommon.Hash {
+	root := libcommon.BytesToHash(v)
+	if root == (libcommon.Hash{}) {
+		return nil
+	}
+	return &root
+}
@@ -0,0 +1,361 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/cl/validator/web3signer"
+)
+
+// Statuses reported by the keymanager API operations.
+const (
+	KeyStatusImported  = "imported"
+	KeyStatusDuplicate = "duplicate"
+	KeyStatusDeleted   = "deleted"
+	KeyStatusNotActive = "not_active"
+	KeyStatusNotFound  = "not_found"
+	KeyStatusError     = "error"
+)
+
+// KeyStatus is the outcome of importing or deleting one key.
+type KeyStatus struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type KeystoreInfo struct {
+	Pubkey         libcommon.Bytes48 `json:"validating_pubkey"`
+	DerivationPath string            `json:"derivation_path,omitempty"`
+	Readonly       bool              `json:"readonly"`
+}
+
+type RemoteKeyInfo struct {
+	Pubkey   libcommon.Bytes48 `json:"pubkey"`
+	Url      string            `json:"url"`
+	Readonly bool              `json:"readonly"`
+}
+
+// importedKeystore is the database record of a keystore imported through the keymanager API. The keystore and
+// its password are kept in files of the imported keys directory, the database only records where.
+type importedKeystore struct {
+	KeystoreFile string `json:"keystore_file"`
+	PasswordFile string `json:"password_file"`
+}
+
+// errKeystoreImportDisabled is returned when importing a keystore without an imported keys directory.
+var errKeystoreImportDisabled = errors.New("keystore import is disabled, no imported keys directory is set")
+
+// writeImportedKeystore writes an imported keystore to <ImportedKeysDir>/keystores and its password to
+// <ImportedKeysDir>/secrets, both readable by the owner only.
+func (v *ValidatorClient) writeImportedKeystore(pubkey libcommon.Bytes48, data []byte, password string) (importedKeystore, error) {
+	if v.cfg.ImportedKeysDir == "" {
+		return importedKeystore{}, errKeystoreImportDisabled
+	}
+	name := fmt.Sprintf("0x%x", pubkey[:])
+	record := importedKeystore{
+		KeystoreFile: filepath.Join(v.cfg.ImportedKeysDir, "keystores", name+".json"),
+		PasswordFile: filepath.Join(v.cfg.ImportedKeysDir, "secrets", name),
+	}
+	for _, file := range []string{record.KeystoreFile, record.PasswordFile} {
+		if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+			return importedKeystore{}, err
+		}
+	}
+	if err := os.WriteFile(record.PasswordFile, []byte(password), 0600); err != nil {
+		return importedKeystore{}, err
+	}
+	if err := os.WriteFile(record.KeystoreFile, data, 0600); err != nil {
+		removeImportedKeystore(record)
+		return importedKeystore{}, err
+	}
+	return record, nil
+}
+
+// removeImportedKeystore deletes the files of an imported keystore, ignoring the ones which are already gone.
+func removeImportedKeystore(record importedKeystore) error {
+	var errs []error
+	for _, file := range []string{record.KeystoreFile, record.PasswordFile} {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// loadImportedKeys loads the keystores and remote keys imported through the keymanager API in previous runs.
+func (v *ValidatorClient) loadImportedKeys(ctx context.Context) error {
+	return v.db.View(ctx, func(tx kv.Tx) error {
+		if err := tx.ForEach(kv.ValidatorKeystores, nil, func(k, val []byte) error {
+			var record importedKeystore
+			if err := json.Unmarshal(val, &record); err != nil {
+				return err
+			}
+			data, err := os.ReadFile(record.KeystoreFile)
+			if err != nil {
+				return fmt.Errorf("imported keystore %x: %w", k, err)
+			}
+			password, err := os.ReadFile(record.PasswordFile)
+			if err != nil {
+				return fmt.Errorf("imported keystore %x: %w", k, err)
+			}
+			keystore, err := ParseKeystore(data)
+			if err != nil {
+				return fmt.Errorf("imported keystore %x: %w", k, err)
+			}
+			secret, err := keystore.Decrypt(string(password))
+			if err != nil {
+				return fmt.Errorf("imported keystore %x: %w", k, err)
+			}
+			v.signer.keys[libcommon.Bytes48(k)] = &localKey{secret: secret, derivationPath: keystore.Path}
+			return nil
+		}); err != nil {
+			return err
+		}
+		return tx.ForEach(kv.ValidatorRemoteKeys, nil, func(k, val []byte) error {
+			if _, ok := v.signer.remoteKeys[libcommon.Bytes48(k)]; ok {
+				// already listed by the remote signer set at startup.
+				return nil
+			}
+			client, err := web3signer.NewClient(string(val))
+			if err != nil {
+				return err
+			}
+			v.signer.remoteKeys[libcommon.Bytes48(k)] = &remoteKey{client: client}
+			return nil
+		})
+	})
+}
+
+// HasValidator reports whether the client holds the key of the given validator, either locally or in a remote signer.
+func (v *ValidatorClient) HasValidator(pubkey libcommon.Bytes48) bool {
+	return v.signer.hasKey(pubkey)
+}
+
+// DefaultFeeRecipient returns the fee recipient of the validators which did not set their own.
+func (v *ValidatorClient) DefaultFeeRecipient() libcommon.Address {
+	return v.cfg.FeeRecipient
+}
+
+func (v *ValidatorClient) ListKeystores() []KeystoreInfo {
+	v.signer.mu.RLock()
+	defer v.signer.mu.RUnlock()
+	keystores := make([]KeystoreInfo, 0, len(v.signer.keys))
+	for pubkey, key := range v.signer.keys {
+		keystores = append(keystores, KeystoreInfo{Pubkey: pubkey, DerivationPath: key.derivationPath, Readonly: key.readonly})
+	}
+	return keystores
+}
+
+// ImportKeystores imports EIP-2335 keystores along with their slashing protection data, if any.
+func (v *ValidatorClient) ImportKeystores(ctx context.Context, keystores [][]byte, passwords []string, slashingProtection io.Reader) ([]KeyStatus, error) {
+	if len(keystores) != len(passwords) {
+		return nil, fmt.Errorf("got %d keystores but %d passwords", len(keystores), len(passwords))
+	}
+	if slashingProtection != nil {
+		if err := v.protection.ImportInterchange(ctx, slashingProtection, v.ethClock.GenesisValidatorsRoot()); err != nil {
+			return nil, fmt.Errorf("invalid slashing protection data: %w", err)
+		}
+	}
+	statuses := make([]KeyStatus, len(keystores))
+	for i := range keystores {
+		statuses[i] = v.importKeystore(ctx, keystores[i], passwords[i])
+	}
+	v.resetDuties()
+	return statuses, nil
+}
+
+func (v *ValidatorClient) importKeystore(ctx context.Context, data []byte, password string) KeyStatus {
+	keystore, err := ParseKeystore(data)
+	if err != nil {
+		return KeyStatus{Status: KeyStatusError, Message: err.Error()}
+	}
+	pubkey, err := keystore.PublicKey()
+	if err != nil {
+		return KeyStatus{Status: KeyStatusError, Message: err.Error()}
+	}
+	if v.HasValidator(pubkey) {
+		return KeyStatus{Status: KeyStatusDuplicate}
+	}
+	secret, err := keystore.Decrypt(password)
+	if err != nil {
+		return KeyStatus{Status: KeyStatusError, Message: err.Error()}
+	}
+	record, err := v.writeImportedKeystore(pubkey, data, password)
+	if err != nil {
+		return KeyStatus{Status: KeyStatusError, Message: err.Error()}
+	}
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		removeImportedKeystore(record)
+		return KeyStatus{Status: KeyStatusError, Message: err.Error()}
+	}
+	if err := v.db.Update(ctx, func(tx kv.RwTx) error {
+		return tx.Put(kv.ValidatorKeystores, pubkey[:], encoded)
+	}); err != nil {
+		removeImportedKeystore(record)
+		return KeyStatus{Status: KeyStatusError, Message: err.Error()}
+	}
+	v.signer.mu.Lock()
+	v.signer.keys[pubkey] = &localKey{secret: secret, derivationPath: keystore.Path}
+	v.signer.mu.Unlock()
+	v.logger.Info("[Validator] Imported keystore", "pubkey", pubkey)
+	return KeyStatus{Status: KeyStatusImported}
+}
+
+// DeleteKeystores stops signing with the given keys and deletes them, returning the slashing protection data of
+// the keys in the EIP-3076 interchange format.
+func (v *ValidatorClient) DeleteKeystores(ctx context.Context, pubkeys []libcommon.Bytes48) ([]KeyStatus, []byte, error) {
+	statuses := make([]KeyStatus, len(pubkeys))
+	for i, pubkey := range pubkeys {
+		v.signer.mu.Lock()
+		key, ok := v.signer.keys[pubkey]
+		if ok && !key.readonly {
+			delete(v.signer.keys, pubkey)
+		}
+		v.signer.mu.Unlock()
+		switch {
+		case ok && key.readonly:
+			statuses[i] = KeyStatus{Status: KeyStatusError, Message: "key was loaded from the keystores directory and is read-only"}
+		case ok:
+			var record importedKeystore
+			if err := v.db.Update(ctx, func(tx kv.RwTx) error {
+				encoded, err := tx.GetOne(kv.ValidatorKeystores, pubkey[:])
+				if err != nil {
+					return err
+				}
+				if err := json.Unmarshal(encoded, &record); err != nil {
+					return err
+				}
+				return tx.Delete(kv.ValidatorKeystores, pubkey[:])
+			}); err != nil {
+				statuses[i] = KeyStatus{Status: KeyStatusError, Message: err.Error()}
+				continue
+			}
+			if err := removeImportedKeystore(record); err != nil {
+				v.logger.Warn("[Validator] Failed to remove the files of a deleted keystore", "pubkey", pubkey, "err", err)
+			}
+			v.logger.Info("[Validator] Deleted keystore", "pubkey", pubkey)
+			statuses[i] = KeyStatus{Status: KeyStatusDeleted}
+		default:
+			hasRecords, err := v.protection.HasRecords(ctx, pubkey)
+			if err != nil {
+				statuses[i] = KeyStatus{Status: KeyStatusError, Message: err.Error()}
+			} else if hasRecords {
+				statuses[i] = KeyStatus{Status: KeyStatusNotActive}
+			} else {
+				statuses[i] = KeyStatus{Status: KeyStatusNotFound}
+			}
+		}
+	}
+	v.resetDuties()
+
+	if pubkeys == nil {
+		pubkeys = []libcommon.Bytes48{}
+	}
+	var exported bytes.Buffer
+	if err := v.protection.ExportInterchange(ctx, &exported, pubkeys); err != nil {
+		return nil, nil, err
+	}
+	return statuses, exported.Bytes(), nil
+}
+
+func (v *ValidatorClient) ListRemoteKeys() []RemoteKeyInfo {
+	v.signer.mu.RLock()
+	defer v.signer.mu.RUnlock()
+	remoteKeys := make([]RemoteKeyInfo, 0, len(v.signer.remoteKeys))
+	for pubkey, key := range v.signer.remoteKeys {
+		remoteKeys = append(remoteKeys, RemoteKeyInfo{Pubkey: pubkey, Url: key.client.Url(), Readonly: key.readonly})
+	}
+	return remoteKeys
+}
+
+// ImportRemoteKeys registers keys held by remote signers.
+func (v *ValidatorClient) ImportRemoteKeys(ctx context.Context, remoteKeys []RemoteKeyInfo) []KeyStatus {
+	statuses := make([]KeyStatus, len(remoteKeys))
+	for i, info := range remoteKeys {
+		if v.HasValidator(info.Pubkey) {
+			statuses[i] = KeyStatus{Status: KeyStatusDuplicate}
+			continue
+		}
+		client, err := web3signer.NewClient(info.Url)
+		if err != nil {
+			statuses[i] = KeyStatus{Status: KeyStatusError, Message: err.Error()}
+			continue
+		}
+		if err := v.db.Update(ctx, func(tx kv.RwTx) error {
+			return tx.Put(kv.ValidatorRemoteKeys, info.Pubkey[:], []byte(info.Url))
+		}); err != nil {
+			statuses[i] = KeyStatus{Status: KeyStatusError, Message: err.Error()}
+			continue
+		}
+		v.signer.mu.Lock()
+		v.signer.remoteKeys[info.Pubkey] = &remoteKey{client: client}
+		v.signer.mu.Unlock()
+		v.logger.Info("[Validator] Imported remote key", "pubkey", info.Pubkey, "url", info.Url)
+		statuses[i] = KeyStatus{Status: KeyStatusImported}
+	}
+	v.resetDuties()
+	return statuses
+}
+
+// DeleteRemoteKeys stops signing with the given remote keys and forgets them.
+func (v *ValidatorClient) DeleteRemoteKeys(ctx context.Context, pubkeys []libcommon.Bytes48) []KeyStatus {
+	statuses := make([]KeyStatus, len(pubkeys))
+	for i, pubkey := range pubkeys {
+		v.signer.mu.Lock()
+		key, ok := v.signer.remoteKeys[pubkey]
+		if ok && !key.readonly {
+			delete(v.signer.remoteKeys, pubkey)
+		}
+		v.signer.mu.Unlock()
+		if !ok {
+			statuses[i] = KeyStatus{Status: KeyStatusNotFound}
+			continue
+		}
+		if key.readonly {
+			statuses[i] = KeyStatus{Status: KeyStatusError, Message: "key is held by the remote signer set at startup and is read-only"}
+			continue
+		}
+		if err := v.db.Update(ctx, func(tx kv.RwTx) error {
+			return tx.Delete(kv.ValidatorRemoteKeys, pubkey[:])
+		}); err != nil {
+			statuses[i] = KeyStatus{Status: KeyStatusError, Message: err.Error()}
+			continue
+		}
+		v.logger.Info("[Validator] Deleted remote key", "pubkey", pubkey)
+		statuses[i] = KeyStatus{Status: KeyStatusDeleted}
+	}
+	v.resetDuties()
+	return statuses
+}
+
+// resetDuties drops the cached duties, so that the ones of the current set of keys are fetched at the next slot.
+func (v *ValidatorClient) resetDuties() {
+	v.dutiesMu.Lock()
+	defer v.dutiesMu.Unlock()
+	v.duties = nil
+}
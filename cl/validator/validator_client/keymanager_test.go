@@ -0,0 +1,138 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Giulio2002/bls"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+)
+
+func TestKeymanagerKeystores(t *testing.T) {
+	ctx := context.Background()
+	db := memdb.NewTestDB(t)
+	readonlySecret, err := bls.GenerateKey()
+	require.NoError(t, err)
+	readonlyPubkey := libcommon.Bytes48(bls.CompressPublicKey(readonlySecret.PublicKey()))
+	keys := map[libcommon.Bytes48]*bls.PrivateKey{readonlyPubkey: readonlySecret}
+	cfg := Config{ImportedKeysDir: t.TempDir()}
+	v, err := NewValidatorClient(ctx, cfg, &clparams.MainnetBeaconConfig, nil, nil, db, keys, log.New())
+	require.NoError(t, err)
+
+	secret, err := bls.GenerateKey()
+	require.NoError(t, err)
+	pubkey := libcommon.Bytes48(bls.CompressPublicKey(secret.PublicKey()))
+	keystore := encryptTestKeystore(t, secret, "password", "pbkdf2")
+	statuses, err := v.ImportKeystores(ctx, [][]byte{keystore, keystore, []byte("{}")}, []string{"password", "password", ""}, nil)
+	require.NoError(t, err)
+	require.Equal(t, KeyStatusImported, statuses[0].Status)
+	require.Equal(t, KeyStatusDuplicate, statuses[1].Status)
+	require.Equal(t, KeyStatusError, statuses[2].Status)
+	require.True(t, v.HasValidator(pubkey))
+	require.Len(t, v.ListKeystores(), 2)
+
+	// the keystore and its password are written to files readable by the owner only, not to the database
+	name := fmt.Sprintf("0x%x", pubkey[:])
+	keystoreFile := filepath.Join(cfg.ImportedKeysDir, "keystores", name+".json")
+	passwordFile := filepath.Join(cfg.ImportedKeysDir, "secrets", name)
+	for _, file := range []string{keystoreFile, passwordFile} {
+		info, err := os.Stat(file)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+	password, err := os.ReadFile(passwordFile)
+	require.NoError(t, err)
+	require.Equal(t, "password", string(password))
+	tx, err := db.BeginRo(ctx)
+	require.NoError(t, err)
+	record, err := tx.GetOne(kv.ValidatorKeystores, pubkey[:])
+	tx.Rollback()
+	require.NoError(t, err)
+	require.JSONEq(t, fmt.Sprintf(`{"keystore_file":%q,"password_file":%q}`, keystoreFile, passwordFile), string(record))
+
+	// imported keys survive restarts
+	v, err = NewValidatorClient(ctx, cfg, &clparams.MainnetBeaconConfig, nil, nil, db, nil, log.New())
+	require.NoError(t, err)
+	require.Equal(t, []KeystoreInfo{{Pubkey: pubkey}}, v.ListKeystores())
+	v, err = NewValidatorClient(ctx, cfg, &clparams.MainnetBeaconConfig, nil, nil, db, keys, log.New())
+	require.NoError(t, err)
+
+	require.NoError(t, v.protection.CheckAndRecordBlock(ctx, pubkey, 10, libcommon.Hash{1}))
+	statuses, slashingProtection, err := v.DeleteKeystores(ctx, []libcommon.Bytes48{pubkey, readonlyPubkey, {1}})
+	require.NoError(t, err)
+	require.Equal(t, KeyStatusDeleted, statuses[0].Status)
+	require.Equal(t, KeyStatusError, statuses[1].Status)
+	require.Equal(t, KeyStatusNotFound, statuses[2].Status)
+	require.False(t, v.HasValidator(pubkey))
+	require.True(t, v.HasValidator(readonlyPubkey))
+	require.NoFileExists(t, keystoreFile)
+	require.NoFileExists(t, passwordFile)
+
+	// the slashing protection data of the deleted keys is exported
+	var interchange Interchange
+	require.NoError(t, json.Unmarshal(slashingProtection, &interchange))
+	require.Len(t, interchange.Data, 1)
+	require.Equal(t, pubkey, interchange.Data[0].Pubkey)
+
+	// deleting again reports the key as no longer active, since it still has slashing protection records
+	statuses, _, err = v.DeleteKeystores(ctx, []libcommon.Bytes48{pubkey})
+	require.NoError(t, err)
+	require.Equal(t, KeyStatusNotActive, statuses[0].Status)
+
+	// without a directory to write them to, keystores can't be imported
+	v, err = NewValidatorClient(ctx, Config{}, &clparams.MainnetBeaconConfig, nil, nil, db, nil, log.New())
+	require.NoError(t, err)
+	statuses, err = v.ImportKeystores(ctx, [][]byte{keystore}, []string{"password"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, KeyStatusError, statuses[0].Status)
+	require.False(t, v.HasValidator(pubkey))
+}
+
+func TestKeymanagerRemoteKeys(t *testing.T) {
+	ctx := context.Background()
+	db := memdb.NewTestDB(t)
+	v, err := NewValidatorClient(ctx, Config{}, &clparams.MainnetBeaconConfig, nil, nil, db, nil, log.New())
+	require.NoError(t, err)
+
+	statuses := v.ImportRemoteKeys(ctx, []RemoteKeyInfo{
+		{Pubkey: libcommon.Bytes48{1}, Url: "http://signer:9000"},
+		{Pubkey: libcommon.Bytes48{1}, Url: "http://signer:9000"},
+		{Pubkey: libcommon.Bytes48{2}, Url: "signer"},
+	})
+	require.Equal(t, []string{KeyStatusImported, KeyStatusDuplicate, KeyStatusError}, []string{statuses[0].Status, statuses[1].Status, statuses[2].Status})
+
+	v, err = NewValidatorClient(ctx, Config{}, &clparams.MainnetBeaconConfig, nil, nil, db, nil, log.New())
+	require.NoError(t, err)
+	require.Equal(t, []RemoteKeyInfo{{Pubkey: libcommon.Bytes48{1}, Url: "http://signer:9000"}}, v.ListRemoteKeys())
+
+	statuses = v.DeleteRemoteKeys(ctx, []libcommon.Bytes48{{1}, {2}})
+	require.Equal(t, KeyStatusDeleted, statuses[0].Status)
+	require.Equal(t, KeyStatusNotFound, statuses[1].Status)
+	require.Empty(t, v.ListRemoteKeys())
+}
@@ -0,0 +1,264 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Giulio2002/bls"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+var (
+	ErrInvalidKeystorePassword = errors.New("invalid keystore password")
+	ErrUnsupportedKeystore     = errors.New("unsupported keystore")
+)
+
+// Keystore is an EIP-2335 BLS12-381 keystore.
+// Specs at: https://eips.ethereum.org/EIPS/eip-2335
+type Keystore struct {
+	Crypto      keystoreCrypto `json:"crypto"`
+	Description string         `json:"description"`
+	Pubkey      string         `json:"pubkey"`
+	Path        string         `json:"path"`
+	UUID        string         `json:"uuid"`
+	Version     int            `json:"version"`
+}
+
+type keystoreCrypto struct {
+	Kdf      keystoreModule `json:"kdf"`
+	Checksum keystoreModule `json:"checksum"`
+	Cipher   keystoreModule `json:"cipher"`
+}
+
+type keystoreModule struct {
+	Function string          `json:"function"`
+	Params   json.RawMessage `json:"params"`
+	Message  string          `json:"message"`
+}
+
+type scryptParams struct {
+	DkLen int    `json:"dklen"`
+	N     int    `json:"n"`
+	P     int    `json:"p"`
+	R     int    `json:"r"`
+	Salt  string `json:"salt"`
+}
+
+type pbkdf2Params struct {
+	DkLen int    `json:"dklen"`
+	C     int    `json:"c"`
+	Prf   string `json:"prf"`
+	Salt  string `json:"salt"`
+}
+
+type aesParams struct {
+	IV string `json:"iv"`
+}
+
+// ParseKeystore decodes an EIP-2335 keystore from its JSON representation.
+func ParseKeystore(data []byte) (*Keystore, error) {
+	k := &Keystore{}
+	if err := json.Unmarshal(data, k); err != nil {
+		return nil, err
+	}
+	if k.Version != 4 {
+		return nil, fmt.Errorf("%w: version %d", ErrUnsupportedKeystore, k.Version)
+	}
+	return k, nil
+}
+
+// PublicKey returns the public key declared by the keystore.
+func (k *Keystore) PublicKey() (libcommon.Bytes48, error) {
+	var pk libcommon.Bytes48
+	b, err := hex.DecodeString(strings.TrimPrefix(k.Pubkey, "0x"))
+	if err != nil {
+		return pk, err
+	}
+	if len(b) != len(pk) {
+		return pk, fmt.Errorf("invalid pubkey length %d", len(b))
+	}
+	copy(pk[:], b)
+	return pk, nil
+}
+
+// Decrypt returns the secret key stored in the keystore, checking that it matches the declared public key.
+func (k *Keystore) Decrypt(password string) (*bls.PrivateKey, error) {
+	decryptionKey, err := k.decryptionKey(normalizePassword(password))
+	if err != nil {
+		return nil, err
+	}
+	cipherMessage, err := hex.DecodeString(k.Crypto.Cipher.Message)
+	if err != nil {
+		return nil, err
+	}
+	if k.Crypto.Checksum.Function != "sha256" {
+		return nil, fmt.Errorf("%w: checksum function %s", ErrUnsupportedKeystore, k.Crypto.Checksum.Function)
+	}
+	checksum, err := hex.DecodeString(k.Crypto.Checksum.Message)
+	if err != nil {
+		return nil, err
+	}
+	expectedChecksum := sha256.Sum256(append(libcommon.Copy(decryptionKey[16:32]), cipherMessage...))
+	if !bytes.Equal(checksum, expectedChecksum[:]) {
+		return nil, ErrInvalidKeystorePassword
+	}
+	if k.Crypto.Cipher.Function != "aes-128-ctr" {
+		return nil, fmt.Errorf("%w: cipher function %s", ErrUnsupportedKeystore, k.Crypto.Cipher.Function)
+	}
+	var params aesParams
+	if err := json.Unmarshal(k.Crypto.Cipher.Params, &params); err != nil {
+		return nil, err
+	}
+	iv, err := hex.DecodeString(params.IV)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(decryptionKey[:16])
+	if err != nil {
+		return nil, err
+	}
+	secret := make([]byte, len(cipherMessage))
+	cipher.NewCTR(block, iv).XORKeyStream(secret, cipherMessage)
+
+	privateKey, err := bls.NewPrivateKeyFromBytes(secret)
+	if err != nil {
+		return nil, err
+	}
+	if k.Pubkey != "" {
+		pk, err := k.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(bls.CompressPublicKey(privateKey.PublicKey()), pk[:]) {
+			return nil, fmt.Errorf("keystore secret does not match pubkey %s", k.Pubkey)
+		}
+	}
+	return privateKey, nil
+}
+
+func (k *Keystore) decryptionKey(password []byte) ([]byte, error) {
+	switch k.Crypto.Kdf.Function {
+	case "scrypt":
+		var params scryptParams
+		if err := json.Unmarshal(k.Crypto.Kdf.Params, &params); err != nil {
+			return nil, err
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, err
+		}
+		if params.DkLen < 32 {
+			return nil, fmt.Errorf("%w: dklen %d", ErrUnsupportedKeystore, params.DkLen)
+		}
+		return scrypt.Key(password, salt, params.N, params.R, params.P, params.DkLen)
+	case "pbkdf2":
+		var params pbkdf2Params
+		if err := json.Unmarshal(k.Crypto.Kdf.Params, &params); err != nil {
+			return nil, err
+		}
+		if params.Prf != "hmac-sha256" {
+			return nil, fmt.Errorf("%w: prf %s", ErrUnsupportedKeystore, params.Prf)
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, err
+		}
+		if params.DkLen < 32 {
+			return nil, fmt.Errorf("%w: dklen %d", ErrUnsupportedKeystore, params.DkLen)
+		}
+		return pbkdf2.Key(password, salt, params.C, params.DkLen, sha256.New), nil
+	default:
+		return nil, fmt.Errorf("%w: kdf function %s", ErrUnsupportedKeystore, k.Crypto.Kdf.Function)
+	}
+}
+
+// normalizePassword applies the EIP-2335 password processing: NFKD normalization and removal of the control codes.
+func normalizePassword(password string) []byte {
+	normalized := norm.NFKD.String(password)
+	out := make([]byte, 0, len(normalized))
+	for _, r := range normalized {
+		if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+			continue
+		}
+		out = append(out, string(r)...)
+	}
+	return out
+}
+
+// LoadKeystores decrypts every keystore (*.json) in keystoresDir. passwordsPath is either a single
+// password file shared by all the keystores, or a directory holding one password file per keystore,
+// named either after the keystore (<name>.txt) or after its public key (0x<pubkey>).
+func LoadKeystores(keystoresDir, passwordsPath string) (map[libcommon.Bytes48]*bls.PrivateKey, error) {
+	entries, err := os.ReadDir(keystoresDir)
+	if err != nil {
+		return nil, err
+	}
+	passwordsInfo, err := os.Stat(passwordsPath)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[libcommon.Bytes48]*bls.PrivateKey)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(keystoresDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		keystore, err := ParseKeystore(data)
+		if err != nil {
+			return nil, fmt.Errorf("keystore %s: %w", entry.Name(), err)
+		}
+		pk, err := keystore.PublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("keystore %s: %w", entry.Name(), err)
+		}
+		passwordFile := passwordsPath
+		if passwordsInfo.IsDir() {
+			passwordFile = filepath.Join(passwordsPath, strings.TrimSuffix(entry.Name(), ".json")+".txt")
+			if _, err := os.Stat(passwordFile); os.IsNotExist(err) {
+				passwordFile = filepath.Join(passwordsPath, fmt.Sprintf("0x%x", pk[:]))
+			}
+		}
+		password, err := os.ReadFile(passwordFile)
+		if err != nil {
+			return nil, fmt.Errorf("keystore %s: %w", entry.Name(), err)
+		}
+		privateKey, err := keystore.Decrypt(strings.TrimRight(string(password), "\r\n"))
+		if err != nil {
+			return nil, fmt.Errorf("keystore %s: %w", entry.Name(), err)
+		}
+		keys[pk] = privateKey
+	}
+	return keys, nil
+}
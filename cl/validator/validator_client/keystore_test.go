@@ -0,0 +1,127 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Giulio2002/bls"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+// encryptTestKeystore builds a keystore for secret, with cheap kdf parameters.
+func encryptTestKeystore(t *testing.T, secret *bls.PrivateKey, password, kdf string) []byte {
+	salt := make([]byte, 32)
+	iv := make([]byte, 16)
+	for i := range salt {
+		salt[i] = byte(i)
+	}
+	keystore := &Keystore{
+		Pubkey:  hex.EncodeToString(bls.CompressPublicKey(secret.PublicKey())),
+		Version: 4,
+	}
+	keystore.Crypto.Kdf.Function = kdf
+	var err error
+	switch kdf {
+	case "scrypt":
+		keystore.Crypto.Kdf.Params, err = json.Marshal(scryptParams{DkLen: 32, N: 16, P: 1, R: 8, Salt: hex.EncodeToString(salt)})
+	case "pbkdf2":
+		keystore.Crypto.Kdf.Params, err = json.Marshal(pbkdf2Params{DkLen: 32, C: 16, Prf: "hmac-sha256", Salt: hex.EncodeToString(salt)})
+	}
+	require.NoError(t, err)
+	decryptionKey, err := keystore.decryptionKey(normalizePassword(password))
+	require.NoError(t, err)
+
+	block, err := aes.NewCipher(decryptionKey[:16])
+	require.NoError(t, err)
+	cipherMessage := make([]byte, 32)
+	cipher.NewCTR(block, iv).XORKeyStream(cipherMessage, secret.Bytes())
+	keystore.Crypto.Cipher.Function = "aes-128-ctr"
+	keystore.Crypto.Cipher.Params, err = json.Marshal(aesParams{IV: hex.EncodeToString(iv)})
+	require.NoError(t, err)
+	keystore.Crypto.Cipher.Message = hex.EncodeToString(cipherMessage)
+
+	checksum := sha256.Sum256(append(libcommon.Copy(decryptionKey[16:32]), cipherMessage...))
+	keystore.Crypto.Checksum.Function = "sha256"
+	keystore.Crypto.Checksum.Params = json.RawMessage("{}")
+	keystore.Crypto.Checksum.Message = hex.EncodeToString(checksum[:])
+
+	encoded, err := json.Marshal(keystore)
+	require.NoError(t, err)
+	return encoded
+}
+
+func TestKeystoreDecrypt(t *testing.T) {
+	secret, err := bls.GenerateKey()
+	require.NoError(t, err)
+	password := "𝔱𝔢𝔰𝔱𝔭𝔞𝔰𝔰𝔴𝔬𝔯𝔡🔑"
+	for _, kdf := range []string{"scrypt", "pbkdf2"} {
+		t.Run(kdf, func(t *testing.T) {
+			keystore, err := ParseKeystore(encryptTestKeystore(t, secret, password, kdf))
+			require.NoError(t, err)
+			decrypted, err := keystore.Decrypt(password)
+			require.NoError(t, err)
+			require.Equal(t, secret.Bytes(), decrypted.Bytes())
+			// control codes are stripped from the password
+			_, err = keystore.Decrypt(password + "\x7f")
+			require.NoError(t, err)
+
+			_, err = keystore.Decrypt("wrong password")
+			require.ErrorIs(t, err, ErrInvalidKeystorePassword)
+		})
+	}
+	_, err = ParseKeystore([]byte(`{"version": 3}`))
+	require.ErrorIs(t, err, ErrUnsupportedKeystore)
+}
+
+func TestLoadKeystores(t *testing.T) {
+	keystoresDir, passwordsDir := t.TempDir(), t.TempDir()
+	secrets := make([]*bls.PrivateKey, 2)
+	for i := range secrets {
+		var err error
+		secrets[i], err = bls.GenerateKey()
+		require.NoError(t, err)
+		password := fmt.Sprintf("password%d", i)
+		name := fmt.Sprintf("keystore-%d", i)
+		require.NoError(t, os.WriteFile(filepath.Join(keystoresDir, name+".json"), encryptTestKeystore(t, secrets[i], password, "pbkdf2"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(passwordsDir, name+".txt"), []byte(password+"\n"), 0600))
+	}
+	keys, err := LoadKeystores(keystoresDir, passwordsDir)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	for _, secret := range secrets {
+		key, ok := keys[libcommon.Bytes48(bls.CompressPublicKey(secret.PublicKey()))]
+		require.True(t, ok)
+		require.Equal(t, secret.Bytes(), key.Bytes())
+	}
+
+	// a single password file is shared by all the keystores
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("password0"), 0600))
+	_, err = LoadKeystores(keystoresDir, passwordFile)
+	require.ErrorIs(t, err, ErrInvalidKeystorePassword)
+}
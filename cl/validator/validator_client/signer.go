@@ -0,0 +1,158 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/Giulio2002/bls"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/types/ssz"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/fork"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
+	"github.com/ledgerwatch/erigon/cl/validator/web3signer"
+)
+
+// signer computes signing roots and signs them with the validator keys held by the client.
+type signer struct {
+	beaconCfg *clparams.BeaconChainConfig
+	ethClock  eth_clock.EthereumClock
+
+	mu   sync.RWMutex
+	keys map[libcommon.Bytes48]*localKey
+	// remoteKeys are the keys held by remote signers.
+	remoteKeys map[libcommon.Bytes48]*remoteKey
+}
+
+func newSigner(beaconCfg *clparams.BeaconChainConfig, ethClock eth_clock.EthereumClock) *signer {
+	return &signer{
+		beaconCfg:  beaconCfg,
+		ethClock:   ethClock,
+		keys:       make(map[libcommon.Bytes48]*localKey),
+		remoteKeys: make(map[libcommon.Bytes48]*remoteKey),
+	}
+}
+
+type localKey struct {
+	secret         *bls.PrivateKey
+	derivationPath string
+	// readonly keys are loaded from the keystores directory and can't be deleted through the keymanager API.
+	readonly bool
+}
+
+type remoteKey struct {
+	client *web3signer.Client
+	// readonly keys are the ones listed by the remote signer set at startup, which can't be deleted through the keymanager API.
+	readonly bool
+}
+
+func (s *signer) pubkeys() []libcommon.Bytes48 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	pubkeys := make([]libcommon.Bytes48, 0, len(s.keys)+len(s.remoteKeys))
+	for pubkey := range s.keys {
+		pubkeys = append(pubkeys, pubkey)
+	}
+	for pubkey := range s.remoteKeys {
+		pubkeys = append(pubkeys, pubkey)
+	}
+	return pubkeys
+}
+
+func (s *signer) hasKey(pubkey libcommon.Bytes48) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, local := s.keys[pubkey]
+	_, remote := s.remoteKeys[pubkey]
+	return local || remote
+}
+
+// forkInfo returns the fork of the given epoch, which remote signers need to compute signing domains.
+func (s *signer) forkInfo(epoch uint64) *web3signer.ForkInfo {
+	version := s.beaconCfg.GetCurrentStateVersion(epoch)
+	previousVersion := version
+	if version > clparams.Phase0Version {
+		previousVersion--
+	}
+	return &web3signer.ForkInfo{
+		Fork: &cltypes.Fork{
+			PreviousVersion: utils.Uint32ToBytes4(s.beaconCfg.GetForkVersionByVersion(previousVersion)),
+			CurrentVersion:  utils.Uint32ToBytes4(s.beaconCfg.GetForkVersionByVersion(version)),
+			Epoch:           s.beaconCfg.GetForkEpochByVersion(version),
+		},
+		GenesisValidatorsRoot: s.ethClock.GenesisValidatorsRoot(),
+	}
+}
+
+func (s *signer) domain(domainType libcommon.Bytes4, epoch uint64) ([]byte, error) {
+	forkVersion := utils.Uint32ToBytes4(s.beaconCfg.GetForkVersionByVersion(s.beaconCfg.GetCurrentStateVersion(epoch)))
+	return fork.ComputeDomain(domainType[:], forkVersion, s.ethClock.GenesisValidatorsRoot())
+}
+
+// signingRoot computes the root that a validator signs for obj in the given domain and epoch.
+func (s *signer) signingRoot(obj ssz.HashableSSZ, domainType libcommon.Bytes4, epoch uint64) (libcommon.Hash, error) {
+	domain, err := s.domain(domainType, epoch)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	return fork.ComputeSigningRoot(obj, domain)
+}
+
+// epochSigningRoot computes the signing root of an epoch or a slot, which are signed as plain uint64 roots.
+func (s *signer) epochSigningRoot(n uint64, domainType libcommon.Bytes4, epoch uint64) (libcommon.Hash, error) {
+	domain, err := s.domain(domainType, epoch)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	root := merkle_tree.Uint64Root(n)
+	return utils.Sha256(root[:], domain), nil
+}
+
+// sign signs req.SigningRoot, whose domain is the one of the given epoch. Local keys sign the root directly, while
+// remote signers get the whole request.
+func (s *signer) sign(ctx context.Context, pubkey libcommon.Bytes48, epoch uint64, req *web3signer.SignRequest) (libcommon.Bytes96, error) {
+	s.mu.RLock()
+	key, local := s.keys[pubkey]
+	remote, isRemote := s.remoteKeys[pubkey]
+	s.mu.RUnlock()
+	switch {
+	case local:
+		return libcommon.Bytes96(key.secret.Sign(req.SigningRoot[:]).Bytes()), nil
+	case isRemote:
+		req.ForkInfo = s.forkInfo(epoch)
+		return remote.client.Sign(ctx, pubkey, req)
+	default:
+		return libcommon.Bytes96{}, fmt.Errorf("no key loaded for validator %x", pubkey)
+	}
+}
+
+// isAggregator checks whether a selection proof elects its validator as aggregator, given the modulo for its committee.
+func isAggregator(selectionProof libcommon.Bytes96, modulo uint64) bool {
+	if modulo == 0 {
+		modulo = 1
+	}
+	hash := utils.Sha256(selectionProof[:])
+	return binary.LittleEndian.Uint64(hash[:8])%modulo == 0
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Giulio2002/bls"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
+	"github.com/ledgerwatch/erigon/cl/validator/web3signer"
+)
+
+// newStubRemoteSigner serves the Web3Signer signing API for secret, recording the requests it signs.
+func newStubRemoteSigner(t *testing.T, secret *bls.PrivateKey) (*httptest.Server, *[]web3signer.SignRequest) {
+	pubkey := libcommon.Bytes48(bls.CompressPublicKey(secret.PublicKey()))
+	requests := &[]web3signer.SignRequest{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/eth2/publicKeys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]libcommon.Bytes48{pubkey})
+	})
+	mux.HandleFunc("/api/v1/eth2/sign/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.TrimPrefix(r.URL.Path, "/api/v1/eth2/sign/") != pubkey.String() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var req web3signer.SignRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*requests = append(*requests, req)
+		json.NewEncoder(w).Encode(map[string]any{"signature": libcommon.Bytes96(secret.Sign(req.SigningRoot[:]).Bytes())})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func TestRemoteSigner(t *testing.T) {
+	ctx := context.Background()
+	secret, err := bls.GenerateKey()
+	require.NoError(t, err)
+	pubkey := libcommon.Bytes48(bls.CompressPublicKey(secret.PublicKey()))
+	server, requests := newStubRemoteSigner(t, secret)
+
+	beaconCfg := clparams.MainnetBeaconConfig
+	genesisValidatorsRoot := libcommon.Hash{1}
+	ethClock := eth_clock.NewEthereumClock(0, genesisValidatorsRoot, &beaconCfg)
+	v, err := NewValidatorClient(ctx, Config{RemoteSignerUrl: server.URL}, &beaconCfg, ethClock, nil, memdb.NewTestDB(t), nil, log.New())
+	require.NoError(t, err)
+	require.True(t, v.HasValidator(pubkey))
+	require.Equal(t, []RemoteKeyInfo{{Pubkey: pubkey, Url: server.URL, Readonly: true}}, v.ListRemoteKeys())
+
+	// the remote signature matches the one of the local key
+	epoch := beaconCfg.CapellaForkEpoch + 1
+	signingRoot, err := v.signer.epochSigningRoot(epoch, beaconCfg.DomainRandao, epoch)
+	require.NoError(t, err)
+	signature, err := v.signer.sign(ctx, pubkey, epoch, &web3signer.SignRequest{
+		Type:         web3signer.TypeRandaoReveal,
+		SigningRoot:  signingRoot,
+		RandaoReveal: &web3signer.RandaoReveal{Epoch: epoch},
+	})
+	require.NoError(t, err)
+	require.Equal(t, libcommon.Bytes96(secret.Sign(signingRoot[:]).Bytes()), signature)
+
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	require.Equal(t, web3signer.TypeRandaoReveal, req.Type)
+	require.Equal(t, epoch, req.RandaoReveal.Epoch)
+	require.Equal(t, genesisValidatorsRoot, req.ForkInfo.GenesisValidatorsRoot)
+	require.Equal(t, beaconCfg.CapellaForkEpoch, req.ForkInfo.Fork.Epoch)
+	require.Equal(t, uint32(beaconCfg.BellatrixForkVersion), utils.Bytes4ToUint32(req.ForkInfo.Fork.PreviousVersion))
+	require.Equal(t, uint32(beaconCfg.CapellaForkVersion), utils.Bytes4ToUint32(req.ForkInfo.Fork.CurrentVersion))
+
+	// keys of the remote signer set at startup can't be deleted
+	statuses := v.DeleteRemoteKeys(ctx, []libcommon.Bytes48{pubkey})
+	require.Equal(t, KeyStatusError, statuses[0].Status)
+	require.True(t, v.HasValidator(pubkey))
+
+	_, err = v.signer.sign(ctx, libcommon.Bytes48{1}, epoch, &web3signer.SignRequest{Type: web3signer.TypeRandaoReveal})
+	require.Error(t, err)
+}
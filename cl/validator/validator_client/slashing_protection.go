@@ -0,0 +1,191 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+var (
+	ErrSlashableBlock       = errors.New("refusing to sign slashable block")
+	ErrSlashableAttestation = errors.New("refusing to sign slashable attestation")
+)
+
+var genesisValidatorsRootKey = []byte("GenesisValidatorsRoot")
+
+// SlashingProtection keeps track of every block and attestation signed by the validator client
+// and refuses to sign anything that could get the validators slashed, following the conditions of EIP-3076.
+type SlashingProtection struct {
+	db kv.RwDB
+}
+
+func NewSlashingProtection(db kv.RwDB) *SlashingProtection {
+	return &SlashingProtection{db: db}
+}
+
+// CheckGenesisValidatorsRoot binds the database to a chain, returning an error if it was used with another one.
+func (s *SlashingProtection) CheckGenesisValidatorsRoot(ctx context.Context, root libcommon.Hash) error {
+	return s.db.Update(ctx, func(tx kv.RwTx) error {
+		return checkGenesisValidatorsRoot(tx, root)
+	})
+}
+
+func checkGenesisValidatorsRoot(tx kv.RwTx, root libcommon.Hash) error {
+	stored, err := tx.GetOne(kv.SlashingProtectionMetadata, genesisValidatorsRootKey)
+	if err != nil {
+		return err
+	}
+	if len(stored) == 0 {
+		return tx.Put(kv.SlashingProtectionMetadata, genesisValidatorsRootKey, root[:])
+	}
+	if !bytes.Equal(stored, root[:]) {
+		return fmt.Errorf("slashing protection database belongs to genesis validators root %x, not %x", stored, root)
+	}
+	return nil
+}
+
+func protectionKey(pubkey libcommon.Bytes48, n uint64) []byte {
+	key := make([]byte, 56)
+	copy(key, pubkey[:])
+	binary.BigEndian.PutUint64(key[48:], n)
+	return key
+}
+
+// CheckAndRecordBlock records a block proposal for pubkey, or returns ErrSlashableBlock if signing it is unsafe.
+func (s *SlashingProtection) CheckAndRecordBlock(ctx context.Context, pubkey libcommon.Bytes48, slot uint64, signingRoot libcommon.Hash) error {
+	return s.db.Update(ctx, func(tx kv.RwTx) error {
+		key := protectionKey(pubkey, slot)
+		existing, err := tx.GetOne(kv.SlashingProtectionBlocks, key)
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			// Signing the very same block again is safe, anything else at this slot is a double proposal.
+			if signingRoot != (libcommon.Hash{}) && bytes.Equal(existing, signingRoot[:]) {
+				return nil
+			}
+			return fmt.Errorf("%w: double proposal at slot %d", ErrSlashableBlock, slot)
+		}
+		c, err := tx.Cursor(kv.SlashingProtectionBlocks)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		k, _, err := c.Seek(pubkey[:])
+		if err != nil {
+			return err
+		}
+		if k != nil && bytes.HasPrefix(k, pubkey[:]) {
+			if minSlot := binary.BigEndian.Uint64(k[48:]); slot < minSlot {
+				return fmt.Errorf("%w: slot %d is lower than the lowest signed slot %d", ErrSlashableBlock, slot, minSlot)
+			}
+		}
+		return tx.Put(kv.SlashingProtectionBlocks, key, signingRoot[:])
+	})
+}
+
+// CheckAndRecordAttestation records an attestation for pubkey, or returns ErrSlashableAttestation if it is
+// a double vote, a surround vote or older than the attestations already signed.
+func (s *SlashingProtection) CheckAndRecordAttestation(ctx context.Context, pubkey libcommon.Bytes48, sourceEpoch, targetEpoch uint64, signingRoot libcommon.Hash) error {
+	if sourceEpoch > targetEpoch {
+		return fmt.Errorf("%w: source epoch %d is after target epoch %d", ErrSlashableAttestation, sourceEpoch, targetEpoch)
+	}
+	return s.db.Update(ctx, func(tx kv.RwTx) error {
+		key := protectionKey(pubkey, targetEpoch)
+		existing, err := tx.GetOne(kv.SlashingProtectionAttestations, key)
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			if signingRoot != (libcommon.Hash{}) && bytes.Equal(existing[8:], signingRoot[:]) {
+				return nil
+			}
+			return fmt.Errorf("%w: double vote for target epoch %d", ErrSlashableAttestation, targetEpoch)
+		}
+
+		c, err := tx.Cursor(kv.SlashingProtectionAttestations)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		found := false
+		var minSource, minTarget uint64
+		for k, v, err := c.Seek(pubkey[:]); k != nil && bytes.HasPrefix(k, pubkey[:]); k, v, err = c.Next() {
+			if err != nil {
+				return err
+			}
+			source, target := binary.BigEndian.Uint64(v[:8]), binary.BigEndian.Uint64(k[48:])
+			if source < sourceEpoch && targetEpoch < target {
+				return fmt.Errorf("%w: surrounded by attestation %d->%d", ErrSlashableAttestation, source, target)
+			}
+			if sourceEpoch < source && target < targetEpoch {
+				return fmt.Errorf("%w: surrounds attestation %d->%d", ErrSlashableAttestation, source, target)
+			}
+			if !found || source < minSource {
+				minSource = source
+			}
+			if !found || target < minTarget {
+				minTarget = target
+			}
+			found = true
+		}
+		if found && sourceEpoch < minSource {
+			return fmt.Errorf("%w: source epoch %d is lower than the lowest signed source epoch %d", ErrSlashableAttestation, sourceEpoch, minSource)
+		}
+		if found && targetEpoch <= minTarget {
+			return fmt.Errorf("%w: target epoch %d is not higher than the lowest signed target epoch %d", ErrSlashableAttestation, targetEpoch, minTarget)
+		}
+		return tx.Put(kv.SlashingProtectionAttestations, key, attestationRecord(sourceEpoch, signingRoot))
+	})
+}
+
+// HasRecords reports whether anything signed by pubkey is recorded in the database.
+func (s *SlashingProtection) HasRecords(ctx context.Context, pubkey libcommon.Bytes48) (found bool, err error) {
+	err = s.db.View(ctx, func(tx kv.Tx) error {
+		for _, table := range []string{kv.SlashingProtectionBlocks, kv.SlashingProtectionAttestations} {
+			c, err := tx.Cursor(table)
+			if err != nil {
+				return err
+			}
+			k, _, err := c.Seek(pubkey[:])
+			c.Close()
+			if err != nil {
+				return err
+			}
+			if k != nil && bytes.HasPrefix(k, pubkey[:]) {
+				found = true
+				return nil
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func attestationRecord(sourceEpoch uint64, signingRoot libcommon.Hash) []byte {
+	v := make([]byte, 40)
+	binary.BigEndian.PutUint64(v, sourceEpoch)
+	copy(v[8:], signingRoot[:])
+	return v
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSlashingProtection(t *testing.T) *SlashingProtection {
+	db := memdb.NewTestDB(t)
+	return NewSlashingProtection(db)
+}
+
+func TestSlashingProtectionBlocks(t *testing.T) {
+	ctx := context.Background()
+	s := setupSlashingProtection(t)
+	pubkey := libcommon.Bytes48{1}
+
+	require.NoError(t, s.CheckAndRecordBlock(ctx, pubkey, 10, libcommon.Hash{1}))
+	// same block again is fine
+	require.NoError(t, s.CheckAndRecordBlock(ctx, pubkey, 10, libcommon.Hash{1}))
+	// double proposal
+	require.ErrorIs(t, s.CheckAndRecordBlock(ctx, pubkey, 10, libcommon.Hash{2}), ErrSlashableBlock)
+	// lower than the lowest signed slot
+	require.ErrorIs(t, s.CheckAndRecordBlock(ctx, pubkey, 9, libcommon.Hash{3}), ErrSlashableBlock)
+	require.NoError(t, s.CheckAndRecordBlock(ctx, pubkey, 11, libcommon.Hash{4}))
+	// other validators are not affected
+	require.NoError(t, s.CheckAndRecordBlock(ctx, libcommon.Bytes48{2}, 9, libcommon.Hash{2}))
+}
+
+func TestSlashingProtectionAttestations(t *testing.T) {
+	ctx := context.Background()
+	s := setupSlashingProtection(t)
+	pubkey := libcommon.Bytes48{1}
+
+	require.NoError(t, s.CheckAndRecordAttestation(ctx, pubkey, 2, 3, libcommon.Hash{1}))
+	require.NoError(t, s.CheckAndRecordAttestation(ctx, pubkey, 2, 3, libcommon.Hash{1}))
+	require.NoError(t, s.CheckAndRecordAttestation(ctx, pubkey, 3, 6, libcommon.Hash{2}))
+
+	tests := []struct {
+		name           string
+		source, target uint64
+	}{
+		{"double vote", 2, 3},
+		{"surrounding vote", 2, 7},
+		{"surrounded vote", 4, 5},
+		{"source after target", 5, 4},
+		{"source lower than lowest source", 1, 8},
+		{"target not higher than lowest target", 2, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, s.CheckAndRecordAttestation(ctx, pubkey, tt.source, tt.target, libcommon.Hash{9}), ErrSlashableAttestation)
+		})
+	}
+	require.NoError(t, s.CheckAndRecordAttestation(ctx, pubkey, 6, 7, libcommon.Hash{3}))
+}
+
+func TestSlashingProtectionGenesisValidatorsRoot(t *testing.T) {
+	ctx := context.Background()
+	s := setupSlashingProtection(t)
+	require.NoError(t, s.CheckGenesisValidatorsRoot(ctx, libcommon.Hash{1}))
+	require.NoError(t, s.CheckGenesisValidatorsRoot(ctx, libcommon.Hash{1}))
+	require.Error(t, s.CheckGenesisValidatorsRoot(ctx, libcommon.Hash{2}))
+}
+
+func TestInterchangeRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	genesisValidatorsRoot := libcommon.Hash{0xaa}
+	root := libcommon.Hash{1}
+	interchange := Interchange{
+		Metadata: InterchangeMetadata{
+			InterchangeFormatVersion: interchangeFormatVersion,
+			GenesisValidatorsRoot:    genesisValidatorsRoot,
+		},
+		Data: []InterchangeData{
+			{
+				Pubkey:       libcommon.Bytes48{1},
+				SignedBlocks: []InterchangeBlock{{Slot: 10, SigningRoot: &root}, {Slot: 12}},
+				SignedAttestations: []InterchangeAttestation{
+					{SourceEpoch: 1, TargetEpoch: 2, SigningRoot: &root},
+					{SourceEpoch: 2, TargetEpoch: 3},
+				},
+			},
+		},
+	}
+	encoded, err := json.Marshal(interchange)
+	require.NoError(t, err)
+
+	s := setupSlashingProtection(t)
+	require.NoError(t, s.ImportInterchange(ctx, bytes.NewReader(encoded), genesisValidatorsRoot))
+	// imported records are enforced
+	require.ErrorIs(t, s.CheckAndRecordBlock(ctx, libcommon.Bytes48{1}, 12, libcommon.Hash{2}), ErrSlashableBlock)
+	require.ErrorIs(t, s.CheckAndRecordAttestation(ctx, libcommon.Bytes48{1}, 2, 3, libcommon.Hash{2}), ErrSlashableAttestation)
+	// a different chain is refused
+	require.Error(t, s.ImportInterchange(ctx, bytes.NewReader(encoded), libcommon.Hash{0xbb}))
+
+	var out bytes.Buffer
+	require.NoError(t, s.ExportInterchange(ctx, &out, nil))
+	var exported Interchange
+	require.NoError(t, json.Unmarshal(out.Bytes(), &exported))
+	require.Equal(t, interchange, exported)
+
+	// importing the export into a fresh database gives the same result
+	s2 := setupSlashingProtection(t)
+	require.NoError(t, s2.ImportInterchange(ctx, bytes.NewReader(out.Bytes()), genesisValidatorsRoot))
+	var out2 bytes.Buffer
+	require.NoError(t, s2.ExportInterchange(ctx, &out2, nil))
+	require.Equal(t, out.String(), out2.String())
+}
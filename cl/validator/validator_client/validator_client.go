@@ -0,0 +1,531 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Giulio2002/bls"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+	"github.com/ledgerwatch/erigon-lib/types/ssz"
+	"github.com/ledgerwatch/erigon/cl/beacon/handler"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
+	"github.com/ledgerwatch/erigon/cl/validator/web3signer"
+)
+
+// Config holds the settings of the built-in validator client.
+type Config struct {
+	FeeRecipient libcommon.Address
+	Graffiti     string
+	// RemoteSignerUrl is the url of a Web3Signer-compatible remote signer, whose keys are used along the local ones.
+	RemoteSignerUrl string
+	// ImportedKeysDir is where the keystores imported through the keymanager API are written, under keystores/,
+	// along with their passwords, under secrets/. Importing keystores is disabled if it is empty.
+	ImportedKeysDir string
+}
+
+// ValidatorClient performs the duties of the validators whose keys it holds, through the beacon node it runs in.
+// Every block and attestation goes through the slashing protection database before being signed.
+type ValidatorClient struct {
+	cfg        Config
+	beaconCfg  *clparams.BeaconChainConfig
+	ethClock   eth_clock.EthereumClock
+	node       BeaconNode
+	signer     *signer
+	protection *SlashingProtection
+	db         kv.RwDB
+	logger     log.Logger
+
+	// indicies maps the validator indicies of the loaded keys to their public keys.
+	indicies map[uint64]libcommon.Bytes48
+
+	dutiesMu sync.Mutex
+	duties   *epochDuties
+}
+
+// NewValidatorClient creates a validator client holding the given keys, loaded from the keystores directory, the keys
+// of the remote signer in cfg, if any, and the keys previously imported through the keymanager API. db stores the
+// slashing protection data and the imported keys.
+func NewValidatorClient(
+	ctx context.Context,
+	cfg Config,
+	beaconCfg *clparams.BeaconChainConfig,
+	ethClock eth_clock.EthereumClock,
+	node BeaconNode,
+	db kv.RwDB,
+	keys map[libcommon.Bytes48]*bls.PrivateKey,
+	logger log.Logger,
+) (*ValidatorClient, error) {
+	v := &ValidatorClient{
+		cfg:        cfg,
+		beaconCfg:  beaconCfg,
+		ethClock:   ethClock,
+		node:       node,
+		signer:     newSigner(beaconCfg, ethClock),
+		protection: NewSlashingProtection(db),
+		db:         db,
+		logger:     logger,
+		indicies:   make(map[uint64]libcommon.Bytes48),
+	}
+	for pubkey, secret := range keys {
+		v.signer.keys[pubkey] = &localKey{secret: secret, readonly: true}
+	}
+	if cfg.RemoteSignerUrl != "" {
+		client, err := web3signer.NewClient(cfg.RemoteSignerUrl)
+		if err != nil {
+			return nil, err
+		}
+		pubkeys, err := client.PublicKeys(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the keys of remote signer %s: %w", cfg.RemoteSignerUrl, err)
+		}
+		for _, pubkey := range pubkeys {
+			v.signer.remoteKeys[pubkey] = &remoteKey{client: client, readonly: true}
+		}
+	}
+	if err := v.loadImportedKeys(ctx); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// SlashingProtection returns the slashing protection database of the validator client.
+func (v *ValidatorClient) SlashingProtection() *SlashingProtection {
+	return v.protection
+}
+
+// Run performs the validator duties slot by slot until the context is cancelled.
+func (v *ValidatorClient) Run(ctx context.Context) error {
+	if err := v.protection.CheckGenesisValidatorsRoot(ctx, v.ethClock.GenesisValidatorsRoot()); err != nil {
+		return err
+	}
+	v.logger.Info("[Validator] Started validator client", "keys", len(v.signer.pubkeys()))
+	for {
+		nextSlot := v.ethClock.GetCurrentSlot() + 1
+		if v.ethClock.GenesisTime() > uint64(time.Now().Unix()) {
+			nextSlot = 0
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Until(v.ethClock.GetSlotTime(nextSlot))):
+		}
+		go v.processSlot(ctx, nextSlot)
+	}
+}
+
+// dutiesForEpoch returns the duties of the given epoch, fetching them from the beacon node if needed.
+func (v *ValidatorClient) dutiesForEpoch(ctx context.Context, epoch uint64) (*epochDuties, error) {
+	v.dutiesMu.Lock()
+	defer v.dutiesMu.Unlock()
+	if v.duties != nil && v.duties.epoch == epoch {
+		return v.duties, nil
+	}
+	if err := v.refreshIndicies(); err != nil {
+		return nil, err
+	}
+	if v.cfg.FeeRecipient != (libcommon.Address{}) {
+		v.prepareBeaconProposer()
+	}
+	duties, err := v.fetchDuties(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	v.duties = duties
+	return duties, nil
+}
+
+func (v *ValidatorClient) processSlot(ctx context.Context, slot uint64) {
+	epoch := slot / v.beaconCfg.SlotsPerEpoch
+	duties, err := v.dutiesForEpoch(ctx, epoch)
+	if err != nil {
+		v.logDutyError("fetch duties", slot, err)
+		return
+	}
+
+	for i := range duties.proposer {
+		if duties.proposer[i].Slot != slot {
+			continue
+		}
+		if err := v.proposeBlock(ctx, &duties.proposer[i]); err != nil {
+			v.logDutyError("propose block", slot, err)
+		}
+	}
+
+	// attestations and sync committee messages are produced one third into the slot, or as soon as
+	// the slot is processed if that is already past.
+	if !v.waitUntil(ctx, slot, 1) {
+		return
+	}
+	attestationDataRoots, err := v.attest(ctx, duties, slot)
+	if err != nil {
+		v.logDutyError("attest", slot, err)
+	}
+	blockRoot, err := v.produceSyncCommitteeMessages(ctx, duties, slot)
+	if err != nil {
+		v.logDutyError("produce sync committee messages", slot, err)
+	}
+
+	// aggregation happens two thirds into the slot.
+	if !v.waitUntil(ctx, slot, 2) {
+		return
+	}
+	if err := v.aggregate(ctx, duties, slot, attestationDataRoots); err != nil {
+		v.logDutyError("aggregate attestations", slot, err)
+	}
+	if blockRoot != (libcommon.Hash{}) {
+		if err := v.produceSyncContributions(ctx, duties, slot, blockRoot); err != nil {
+			v.logDutyError("produce sync committee contributions", slot, err)
+		}
+	}
+}
+
+// waitUntil waits until the given third of the slot, returning false if the context was cancelled in the meantime.
+func (v *ValidatorClient) waitUntil(ctx context.Context, slot uint64, thirds uint64) bool {
+	deadline := v.ethClock.GetSlotTime(slot).Add(time.Duration(v.beaconCfg.SecondsPerSlot*thirds) * time.Second / 3)
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(time.Until(deadline)):
+		return true
+	}
+}
+
+func (v *ValidatorClient) logDutyError(duty string, slot uint64, err error) {
+	if errors.Is(err, handler.ErrNodeSyncing) {
+		v.logger.Debug("[Validator] Skipping duty, node is syncing", "duty", duty, "slot", slot)
+		return
+	}
+	if errors.Is(err, ErrSlashableBlock) || errors.Is(err, ErrSlashableAttestation) || errors.Is(err, web3signer.ErrSlashable) {
+		v.logger.Error("[Validator] Refused to sign slashable message", "duty", duty, "slot", slot, "err", err)
+		return
+	}
+	v.logger.Warn("[Validator] Failed to perform duty", "duty", duty, "slot", slot, "err", err)
+}
+
+func (v *ValidatorClient) proposeBlock(ctx context.Context, duty *handler.ProposerDuty) error {
+	epoch := duty.Slot / v.beaconCfg.SlotsPerEpoch
+	randaoRoot, err := v.signer.epochSigningRoot(epoch, v.beaconCfg.DomainRandao, epoch)
+	if err != nil {
+		return err
+	}
+	randaoReveal, err := v.signer.sign(ctx, duty.Pubkey, epoch, &web3signer.SignRequest{
+		Type:         web3signer.TypeRandaoReveal,
+		SigningRoot:  randaoRoot,
+		RandaoReveal: &web3signer.RandaoReveal{Epoch: epoch},
+	})
+	if err != nil {
+		return err
+	}
+	graffiti := handler.DefaultGraffiti
+	if v.cfg.Graffiti != "" {
+		graffiti = libcommon.Hash{}
+		copy(graffiti[:], v.cfg.Graffiti)
+	}
+	block, err := v.node.ProduceBlock(ctx, duty.Slot, randaoReveal, graffiti, 0)
+	if err != nil {
+		return err
+	}
+	version := block.Version()
+
+	if block.IsBlinded() {
+		blinded := block.ToBlinded()
+		header, err := blockHeader(blinded.Slot, blinded.ProposerIndex, blinded.ParentRoot, blinded.StateRoot, blinded.Body)
+		if err != nil {
+			return err
+		}
+		signature, err := v.signBlock(ctx, duty, version, header)
+		if err != nil {
+			return err
+		}
+		if err := v.node.PublishBlindedBlock(ctx, &cltypes.SignedBlindedBeaconBlock{Block: blinded, Signature: signature}); err != nil {
+			return err
+		}
+	} else {
+		unsigned := block.ToExecution().Block
+		header, err := blockHeader(unsigned.Slot, unsigned.ProposerIndex, unsigned.ParentRoot, unsigned.StateRoot, unsigned.Body)
+		if err != nil {
+			return err
+		}
+		signature, err := v.signBlock(ctx, duty, version, header)
+		if err != nil {
+			return err
+		}
+		if err := v.node.PublishBlock(ctx, &cltypes.SignedBeaconBlock{Block: unsigned, Signature: signature}); err != nil {
+			return err
+		}
+	}
+	v.logger.Info("[Validator] Proposed block", "slot", duty.Slot, "validator", duty.ValidatorIndex, "blinded", block.IsBlinded())
+	return nil
+}
+
+// blockHeader builds the header of a block, which has the same root as the block itself.
+func blockHeader(slot, proposerIndex uint64, parentRoot, stateRoot libcommon.Hash, body ssz.HashableSSZ) (*cltypes.BeaconBlockHeader, error) {
+	bodyRoot, err := body.HashSSZ()
+	if err != nil {
+		return nil, err
+	}
+	return &cltypes.BeaconBlockHeader{
+		Slot:          slot,
+		ProposerIndex: proposerIndex,
+		ParentRoot:    parentRoot,
+		Root:          stateRoot,
+		BodyRoot:      bodyRoot,
+	}, nil
+}
+
+// signBlock signs a block, given its header, for the given proposer duty, after recording it in the slashing
+// protection database.
+func (v *ValidatorClient) signBlock(ctx context.Context, duty *handler.ProposerDuty, version clparams.StateVersion, header *cltypes.BeaconBlockHeader) (libcommon.Bytes96, error) {
+	epoch := duty.Slot / v.beaconCfg.SlotsPerEpoch
+	signingRoot, err := v.signer.signingRoot(header, v.beaconCfg.DomainBeaconProposer, epoch)
+	if err != nil {
+		return libcommon.Bytes96{}, err
+	}
+	if err := v.protection.CheckAndRecordBlock(ctx, duty.Pubkey, duty.Slot, signingRoot); err != nil {
+		return libcommon.Bytes96{}, err
+	}
+	return v.signer.sign(ctx, duty.Pubkey, epoch, &web3signer.SignRequest{
+		Type:        web3signer.TypeBlockV2,
+		SigningRoot: signingRoot,
+		BeaconBlock: web3signer.NewBeaconBlock(version, header),
+	})
+}
+
+// attest produces and publishes the attestations of the local validators for the given slot. It returns the
+// attestation data roots by committee index, which are needed for aggregation.
+func (v *ValidatorClient) attest(ctx context.Context, duties *epochDuties, slot uint64) (map[uint64]libcommon.Hash, error) {
+	dataByCommittee := make(map[uint64]solid.AttestationData)
+	dataRoots := make(map[uint64]libcommon.Hash)
+	attestations := []*solid.Attestation{}
+	for _, duty := range duties.attester {
+		if duty.Slot != slot {
+			continue
+		}
+		data, ok := dataByCommittee[duty.CommitteeIndex]
+		if !ok {
+			var err error
+			if data, err = v.node.ProduceAttestationData(slot, duty.CommitteeIndex); err != nil {
+				return dataRoots, err
+			}
+			root, err := data.HashSSZ()
+			if err != nil {
+				return dataRoots, err
+			}
+			dataByCommittee[duty.CommitteeIndex] = data
+			dataRoots[duty.CommitteeIndex] = root
+		}
+		targetEpoch := data.Target().Epoch()
+		signingRoot, err := v.signer.signingRoot(data, v.beaconCfg.DomainBeaconAttester, targetEpoch)
+		if err != nil {
+			return dataRoots, err
+		}
+		if err := v.protection.CheckAndRecordAttestation(ctx, duty.Pubkey, data.Source().Epoch(), targetEpoch, signingRoot); err != nil {
+			v.logDutyError("attest", slot, fmt.Errorf("validator %d: %w", duty.ValidatorIndex, err))
+			continue
+		}
+		signature, err := v.signer.sign(ctx, duty.Pubkey, targetEpoch, &web3signer.SignRequest{
+			Type:        web3signer.TypeAttestation,
+			SigningRoot: signingRoot,
+			Attestation: data,
+		})
+		if err != nil {
+			return dataRoots, err
+		}
+		// the aggregation bits are a bitlist, so the bit right after the committee marks its length.
+		aggregationBits := make([]byte, duty.CommitteeLength/8+1)
+		aggregationBits[duty.ValidatorCommitteeIndex/8] |= 1 << (duty.ValidatorCommitteeIndex % 8)
+		aggregationBits[duty.CommitteeLength/8] |= 1 << (duty.CommitteeLength % 8)
+		attestations = append(attestations, solid.NewAttestionFromParameters(aggregationBits, data, signature))
+	}
+	if len(attestations) == 0 {
+		return dataRoots, nil
+	}
+	if err := v.node.PublishAttestations(ctx, attestations); err != nil {
+		return dataRoots, err
+	}
+	v.logger.Debug("[Validator] Published attestations", "slot", slot, "count", len(attestations))
+	return dataRoots, nil
+}
+
+// aggregate publishes the aggregate attestations of the local validators elected as aggregators for the given slot.
+func (v *ValidatorClient) aggregate(ctx context.Context, duties *epochDuties, slot uint64, dataRoots map[uint64]libcommon.Hash) error {
+	epoch := slot / v.beaconCfg.SlotsPerEpoch
+	aggregates := []*cltypes.SignedAggregateAndProof{}
+	for _, duty := range duties.attester {
+		if duty.Slot != slot || !duty.isAggregator {
+			continue
+		}
+		dataRoot, ok := dataRoots[duty.CommitteeIndex]
+		if !ok {
+			continue
+		}
+		aggregate, err := v.node.AggregateAttestation(slot, dataRoot)
+		if err != nil {
+			return err
+		}
+		aggregateAndProof := &cltypes.AggregateAndProof{
+			AggregatorIndex: duty.ValidatorIndex,
+			Aggregate:       aggregate,
+			SelectionProof:  duty.selectionProof,
+		}
+		signingRoot, err := v.signer.signingRoot(aggregateAndProof, v.beaconCfg.DomainAggregateAndProof, epoch)
+		if err != nil {
+			return err
+		}
+		signature, err := v.signer.sign(ctx, duty.Pubkey, epoch, &web3signer.SignRequest{
+			Type:              web3signer.TypeAggregateAndProof,
+			SigningRoot:       signingRoot,
+			AggregateAndProof: aggregateAndProof,
+		})
+		if err != nil {
+			return err
+		}
+		aggregates = append(aggregates, &cltypes.SignedAggregateAndProof{Message: aggregateAndProof, Signature: signature})
+	}
+	if len(aggregates) == 0 {
+		return nil
+	}
+	return v.node.PublishAggregateAndProofs(ctx, aggregates)
+}
+
+// produceSyncCommitteeMessages publishes the sync committee messages of the local validators for the given slot
+// and returns the head block root they signed.
+func (v *ValidatorClient) produceSyncCommitteeMessages(ctx context.Context, duties *epochDuties, slot uint64) (libcommon.Hash, error) {
+	if len(duties.sync) == 0 {
+		return libcommon.Hash{}, nil
+	}
+	headRoot, err := v.node.HeadBlockRoot()
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	epoch := slot / v.beaconCfg.SlotsPerEpoch
+	domain, err := v.signer.domain(v.beaconCfg.DomainSyncCommittee, epoch)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	signingRoot := utils.Sha256(headRoot[:], domain)
+	messages := make([]*cltypes.SyncCommitteeMessage, 0, len(duties.sync))
+	for _, duty := range duties.sync {
+		signature, err := v.signer.sign(ctx, duty.Pubkey, epoch, &web3signer.SignRequest{
+			Type:                 web3signer.TypeSyncCommitteeMessage,
+			SigningRoot:          signingRoot,
+			SyncCommitteeMessage: &web3signer.SyncCommitteeMessage{BeaconBlockRoot: headRoot, Slot: slot},
+		})
+		if err != nil {
+			return libcommon.Hash{}, err
+		}
+		messages = append(messages, &cltypes.SyncCommitteeMessage{
+			Slot:            slot,
+			BeaconBlockRoot: headRoot,
+			ValidatorIndex:  duty.ValidatorIndex,
+			Signature:       signature,
+		})
+	}
+	if err := v.node.PublishSyncCommitteeMessages(ctx, messages); err != nil {
+		return libcommon.Hash{}, err
+	}
+	return headRoot, nil
+}
+
+// produceSyncContributions publishes the sync committee contributions of the local validators elected as
+// sync subcommittee aggregators for the given slot.
+func (v *ValidatorClient) produceSyncContributions(ctx context.Context, duties *epochDuties, slot uint64, blockRoot libcommon.Hash) error {
+	epoch := slot / v.beaconCfg.SlotsPerEpoch
+	subcommitteeSize := v.beaconCfg.SyncCommitteeSize / v.beaconCfg.SyncCommitteeSubnetCount
+	modulo := subcommitteeSize / v.beaconCfg.TargetAggregatorsPerSyncSubcommittee
+	contributions := []*cltypes.SignedContributionAndProof{}
+	for _, duty := range duties.sync {
+		seen := make(map[uint64]struct{})
+		for _, indexStr := range duty.ValidatorSyncCommitteeIndicies {
+			index, err := strconv.ParseUint(indexStr, 10, 64)
+			if err != nil {
+				return err
+			}
+			subcommitteeIndex := index / subcommitteeSize
+			if _, ok := seen[subcommitteeIndex]; ok {
+				continue
+			}
+			seen[subcommitteeIndex] = struct{}{}
+
+			selectionData := &cltypes.SyncAggregatorSelectionData{Slot: slot, SubcommitteeIndex: subcommitteeIndex}
+			selectionRoot, err := v.signer.signingRoot(selectionData, v.beaconCfg.DomainSyncCommitteeSelectionProof, epoch)
+			if err != nil {
+				return err
+			}
+			selectionProof, err := v.signer.sign(ctx, duty.Pubkey, epoch, &web3signer.SignRequest{
+				Type:                        web3signer.TypeSyncCommitteeSelectionProof,
+				SigningRoot:                 selectionRoot,
+				SyncAggregatorSelectionData: selectionData,
+			})
+			if err != nil {
+				return err
+			}
+			if !isAggregator(selectionProof, modulo) {
+				continue
+			}
+			contribution := v.node.SyncCommitteeContribution(slot, subcommitteeIndex, blockRoot)
+			if contribution == nil || isEmptyBitfield(contribution.AggregationBits) {
+				continue
+			}
+			contributionAndProof := &cltypes.ContributionAndProof{
+				AggregatorIndex: duty.ValidatorIndex,
+				Contribution:    contribution,
+				SelectionProof:  selectionProof,
+			}
+			signingRoot, err := v.signer.signingRoot(contributionAndProof, v.beaconCfg.DomainContributionAndProof, epoch)
+			if err != nil {
+				return err
+			}
+			signature, err := v.signer.sign(ctx, duty.Pubkey, epoch, &web3signer.SignRequest{
+				Type:                 web3signer.TypeSyncCommitteeContributionAndProof,
+				SigningRoot:          signingRoot,
+				ContributionAndProof: contributionAndProof,
+			})
+			if err != nil {
+				return err
+			}
+			contributions = append(contributions, &cltypes.SignedContributionAndProof{Message: contributionAndProof, Signature: signature})
+		}
+	}
+	if len(contributions) == 0 {
+		return nil
+	}
+	return v.node.PublishContributionAndProofs(ctx, contributions)
+}
+
+func isEmptyBitfield(bits hexutility.Bytes) bool {
+	for _, b := range bits {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,300 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Giulio2002/bls"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+	"github.com/ledgerwatch/erigon/cl/beacon/handler"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
+)
+
+// mockBeaconNode - gives all the duties of slot to a single validator and records what it publishes
+type mockBeaconNode struct {
+	beaconCfg *clparams.BeaconChainConfig
+	pubkey    libcommon.Bytes48
+	index     uint64
+	slot      uint64
+	headRoot  libcommon.Hash
+	syncing   bool
+
+	mu                sync.Mutex
+	produced          byte // number of blocks and attestation data produced, so that no two are the same
+	subscriptions     []*cltypes.BeaconCommitteeSubscription
+	syncSubscriptions []handler.ValidatorSyncCommitteeSubscriptionsRequest
+	feeRecipients     []handler.ValidatorPreparationPayload
+	graffiti          libcommon.Hash
+	blocks            []*cltypes.SignedBeaconBlock
+	attestations      []*solid.Attestation
+	aggregates        []*cltypes.SignedAggregateAndProof
+	syncMessages      []*cltypes.SyncCommitteeMessage
+	contributions     []*cltypes.SignedContributionAndProof
+}
+
+func (n *mockBeaconNode) ValidatorIndicies(pubkeys []libcommon.Bytes48) (map[libcommon.Bytes48]uint64, error) {
+	if n.syncing {
+		return nil, handler.ErrNodeSyncing
+	}
+	indicies := map[libcommon.Bytes48]uint64{}
+	for _, pubkey := range pubkeys {
+		if pubkey == n.pubkey {
+			indicies[pubkey] = n.index
+		}
+	}
+	return indicies, nil
+}
+
+func (n *mockBeaconNode) ProposerDuties(_ context.Context, epoch uint64) ([]handler.ProposerDuty, error) {
+	duties := make([]handler.ProposerDuty, 0, n.beaconCfg.SlotsPerEpoch)
+	for slot := epoch * n.beaconCfg.SlotsPerEpoch; slot < (epoch+1)*n.beaconCfg.SlotsPerEpoch; slot++ {
+		duty := handler.ProposerDuty{Pubkey: libcommon.Bytes48{1}, ValidatorIndex: n.index + 1, Slot: slot}
+		if slot == n.slot {
+			duty = handler.ProposerDuty{Pubkey: n.pubkey, ValidatorIndex: n.index, Slot: slot}
+		}
+		duties = append(duties, duty)
+	}
+	return duties, nil
+}
+
+func (n *mockBeaconNode) AttesterDuties(_ context.Context, _ uint64, idxs []uint64) ([]handler.AttesterDuty, error) {
+	if len(idxs) != 1 || idxs[0] != n.index {
+		return nil, nil
+	}
+	return []handler.AttesterDuty{{
+		Pubkey:                  n.pubkey,
+		ValidatorIndex:          n.index,
+		CommitteeIndex:          2,
+		CommitteeLength:         3,
+		ValidatorCommitteeIndex: 1,
+		CommitteesAtSlot:        4,
+		Slot:                    n.slot,
+	}}, nil
+}
+
+func (n *mockBeaconNode) SyncDuties(_ context.Context, _ uint64, idxs []uint64) ([]*handler.SyncDuty, error) {
+	if len(idxs) != 1 || idxs[0] != n.index {
+		return nil, nil
+	}
+	return []*handler.SyncDuty{{Pubkey: n.pubkey, ValidatorIndex: n.index, ValidatorSyncCommitteeIndicies: []string{"5"}}}, nil
+}
+
+func (n *mockBeaconNode) SubscribeToBeaconCommittees(_ context.Context, req []*cltypes.BeaconCommitteeSubscription) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.subscriptions = append(n.subscriptions, req...)
+	return nil
+}
+
+func (n *mockBeaconNode) SubscribeToSyncCommittees(_ context.Context, req []handler.ValidatorSyncCommitteeSubscriptionsRequest) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.syncSubscriptions = append(n.syncSubscriptions, req...)
+	return nil
+}
+
+func (n *mockBeaconNode) PrepareBeaconProposers(req []handler.ValidatorPreparationPayload) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.feeRecipients = append(n.feeRecipients, req...)
+}
+
+func (n *mockBeaconNode) ProduceBlock(_ context.Context, targetSlot uint64, randaoReveal libcommon.Bytes96, graffiti libcommon.Hash, _ uint64) (*cltypes.BlindOrExecutionBeaconBlock, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.graffiti = graffiti
+	n.produced++
+	body := cltypes.NewBeaconBody(n.beaconCfg)
+	body.RandaoReveal = randaoReveal
+	body.Graffiti = graffiti
+	return &cltypes.BlindOrExecutionBeaconBlock{
+		Slot:          targetSlot,
+		ProposerIndex: n.index,
+		ParentRoot:    n.headRoot,
+		StateRoot:     libcommon.Hash{n.produced},
+		BeaconBody:    body,
+		Cfg:           n.beaconCfg,
+	}, nil
+}
+
+func (n *mockBeaconNode) PublishBlock(_ context.Context, blk *cltypes.SignedBeaconBlock) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.blocks = append(n.blocks, blk)
+	return nil
+}
+
+func (n *mockBeaconNode) PublishBlindedBlock(context.Context, *cltypes.SignedBlindedBeaconBlock) error {
+	panic("blocks of the mock node are never blinded")
+}
+
+func (n *mockBeaconNode) ProduceAttestationData(slot, committeeIndex uint64) (solid.AttestationData, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.produced++
+	return solid.NewAttestionDataFromParameters(slot, committeeIndex, libcommon.Hash{n.produced},
+		solid.NewCheckpointFromParameters(libcommon.Hash{3}, slot/n.beaconCfg.SlotsPerEpoch-1),
+		solid.NewCheckpointFromParameters(libcommon.Hash{4}, slot/n.beaconCfg.SlotsPerEpoch)), nil
+}
+
+func (n *mockBeaconNode) PublishAttestations(_ context.Context, attestations []*solid.Attestation) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.attestations = append(n.attestations, attestations...)
+	return nil
+}
+
+func (n *mockBeaconNode) AggregateAttestation(slot uint64, attestationDataRoot libcommon.Hash) (*solid.Attestation, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, attestation := range n.attestations {
+		root, err := attestation.AttestantionData().HashSSZ()
+		if err != nil {
+			return nil, err
+		}
+		if root == attestationDataRoot && attestation.AttestantionData().Slot() == slot {
+			return attestation, nil
+		}
+	}
+	return nil, errors.New("aggregate not found")
+}
+
+func (n *mockBeaconNode) PublishAggregateAndProofs(_ context.Context, aggregates []*cltypes.SignedAggregateAndProof) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.aggregates = append(n.aggregates, aggregates...)
+	return nil
+}
+
+func (n *mockBeaconNode) HeadBlockRoot() (libcommon.Hash, error) {
+	return n.headRoot, nil
+}
+
+func (n *mockBeaconNode) PublishSyncCommitteeMessages(_ context.Context, msgs []*cltypes.SyncCommitteeMessage) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.syncMessages = append(n.syncMessages, msgs...)
+	return nil
+}
+
+func (n *mockBeaconNode) SyncCommitteeContribution(slot, subcommitteeIndex uint64, beaconBlockRoot libcommon.Hash) *cltypes.Contribution {
+	aggregationBits := make([]byte, cltypes.SyncCommitteeAggregationBitsSize)
+	aggregationBits[0] = 1 << 5
+	return &cltypes.Contribution{
+		Slot:              slot,
+		BeaconBlockRoot:   beaconBlockRoot,
+		SubcommitteeIndex: subcommitteeIndex,
+		AggregationBits:   aggregationBits,
+	}
+}
+
+func (n *mockBeaconNode) PublishContributionAndProofs(_ context.Context, msgs []*cltypes.SignedContributionAndProof) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.contributions = append(n.contributions, msgs...)
+	return nil
+}
+
+func TestValidatorClientDuties(t *testing.T) {
+	ctx := context.Background()
+	beaconCfg := clparams.MainnetBeaconConfig
+	// every validator is an aggregator of its committees and sync subcommittees
+	beaconCfg.TargetAggregatorsPerSyncSubcommittee = beaconCfg.SyncCommitteeSize / beaconCfg.SyncCommitteeSubnetCount
+	// a genesis time of 0 puts every slot in the past, so the duties of a slot are performed right away
+	ethClock := eth_clock.NewEthereumClock(0, libcommon.Hash{1}, &beaconCfg)
+	secret, err := bls.GenerateKey()
+	require.NoError(t, err)
+	pubkey := libcommon.Bytes48(bls.CompressPublicKey(secret.PublicKey()))
+	slot := 100*beaconCfg.SlotsPerEpoch + 3
+	epoch := slot / beaconCfg.SlotsPerEpoch
+	node := &mockBeaconNode{beaconCfg: &beaconCfg, pubkey: pubkey, index: 42, slot: slot, headRoot: libcommon.Hash{9}}
+	cfg := Config{FeeRecipient: libcommon.Address{7}, Graffiti: "erigon"}
+	v, err := NewValidatorClient(ctx, cfg, &beaconCfg, ethClock, node, memdb.NewTestDB(t), map[libcommon.Bytes48]*bls.PrivateKey{pubkey: secret}, log.New())
+	require.NoError(t, err)
+
+	verify := func(signature libcommon.Bytes96, obj interface{ HashSSZ() ([32]byte, error) }, domain [4]byte) {
+		t.Helper()
+		signingRoot, err := v.signer.signingRoot(obj, domain, epoch)
+		require.NoError(t, err)
+		valid, err := bls.Verify(signature[:], signingRoot[:], pubkey[:])
+		require.NoError(t, err)
+		require.True(t, valid)
+	}
+
+	v.processSlot(ctx, slot)
+
+	require.Equal(t, []handler.ValidatorPreparationPayload{{ValidatorIndex: 42, FeeRecipient: cfg.FeeRecipient}}, node.feeRecipients)
+	require.Equal(t, []*cltypes.BeaconCommitteeSubscription{{ValidatorIndex: 42, CommitteeIndex: 2, CommitteesAtSlot: 4, Slot: slot, IsAggregator: true}}, node.subscriptions)
+	require.Equal(t, []handler.ValidatorSyncCommitteeSubscriptionsRequest{{ValidatorIndex: 42, SyncCommitteeIndicies: []string{"5"}, UntilEpoch: epoch + 1}}, node.syncSubscriptions)
+
+	// the block is signed over its header, whose root is the one of the block
+	require.Len(t, node.blocks, 1)
+	block := node.blocks[0].Block
+	require.Equal(t, slot, block.Slot)
+	require.Equal(t, "erigon", string(node.graffiti[:len("erigon")]))
+	verify(node.blocks[0].Signature, block, beaconCfg.DomainBeaconProposer)
+	randaoRoot, err := v.signer.epochSigningRoot(epoch, beaconCfg.DomainRandao, epoch)
+	require.NoError(t, err)
+	valid, err := bls.Verify(block.Body.RandaoReveal[:], randaoRoot[:], pubkey[:])
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	// the attestation sets the bit of the validator in a committee of 3
+	require.Len(t, node.attestations, 1)
+	attestation := node.attestations[0]
+	require.Equal(t, []byte{0b1010}, []byte(attestation.AggregationBits()))
+	verify(attestation.Signature(), attestation.AttestantionData(), beaconCfg.DomainBeaconAttester)
+	require.Len(t, node.aggregates, 1)
+	require.Equal(t, attestation, node.aggregates[0].Message.Aggregate)
+	verify(node.aggregates[0].Signature, node.aggregates[0].Message, beaconCfg.DomainAggregateAndProof)
+
+	require.Len(t, node.syncMessages, 1)
+	require.Equal(t, node.headRoot, node.syncMessages[0].BeaconBlockRoot)
+	syncDomain, err := v.signer.domain(beaconCfg.DomainSyncCommittee, epoch)
+	require.NoError(t, err)
+	syncRoot := utils.Sha256(node.headRoot[:], syncDomain)
+	valid, err = bls.Verify(node.syncMessages[0].Signature[:], syncRoot[:], pubkey[:])
+	require.NoError(t, err)
+	require.True(t, valid)
+	require.Len(t, node.contributions, 1)
+	require.Equal(t, uint64(0), node.contributions[0].Message.Contribution.SubcommitteeIndex)
+	verify(node.contributions[0].Signature, node.contributions[0].Message, beaconCfg.DomainContributionAndProof)
+
+	// the slashing protection refuses to sign other blocks and attestations for the same duties
+	v.processSlot(ctx, slot)
+	require.Len(t, node.blocks, 1)
+	require.Len(t, node.attestations, 1)
+
+	// nothing is published while the node is syncing
+	node.syncing = true
+	v.duties = nil
+	v.processSlot(ctx, slot+1)
+	require.Len(t, node.blocks, 1)
+	require.Len(t, node.syncMessages, 2)
+}
@@ -17,17 +17,33 @@
 package validator_params
 
 import (
+	"context"
+	"encoding/binary"
 	"sync"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/kv"
 )
 
+// DefaultGasLimit is the gas limit target of validators which did not set one.
+const DefaultGasLimit = 30_000_000
+
+// ProposerConfig holds the block proposal settings of a validator. Zero values mean unset.
+type ProposerConfig struct {
+	FeeRecipient libcommon.Address
+	GasLimit     uint64
+}
+
 type ValidatorParams struct {
+	// fee recipients registered by validator index through prepare_beacon_proposer, kept in memory only.
 	feeRecipients sync.Map
+	// per-pubkey proposer settings, persisted in db.
+	db kv.RwDB
 }
 
-func NewValidatorParams() *ValidatorParams {
-	return &ValidatorParams{}
+func NewValidatorParams(db kv.RwDB) *ValidatorParams {
+	return &ValidatorParams{db: db}
 }
 
 func (vp *ValidatorParams) SetFeeRecipient(validatorIndex uint64, feeRecipient libcommon.Address) {
@@ -41,3 +57,61 @@ func (vp *ValidatorParams) GetFeeRecipient(validatorIndex uint64) (libcommon.Add
 	}
 	return val.(libcommon.Address), true
 }
+
+// ProposerConfig returns the proposer settings stored for pubkey.
+func (vp *ValidatorParams) ProposerConfig(ctx context.Context, pubkey libcommon.Bytes48) (cfg ProposerConfig, err error) {
+	err = vp.db.View(ctx, func(tx kv.Tx) error {
+		v, err := tx.GetOne(kv.ValidatorProposerConfig, pubkey[:])
+		if err != nil {
+			return err
+		}
+		cfg = decodeProposerConfig(v)
+		return nil
+	})
+	return
+}
+
+// ProposerFeeRecipient returns the fee recipient to use for a block proposed by the given validator. The fee recipient
+// set for its pubkey takes precedence over the one registered through prepare_beacon_proposer.
+func (vp *ValidatorParams) ProposerFeeRecipient(ctx context.Context, validatorIndex uint64, pubkey libcommon.Bytes48) (libcommon.Address, bool, error) {
+	cfg, err := vp.ProposerConfig(ctx, pubkey)
+	if err != nil {
+		return libcommon.Address{}, false, err
+	}
+	if cfg.FeeRecipient != (libcommon.Address{}) {
+		return cfg.FeeRecipient, true, nil
+	}
+	feeRecipient, ok := vp.GetFeeRecipient(validatorIndex)
+	return feeRecipient, ok, nil
+}
+
+// UpdateProposerConfig applies update to the proposer settings of pubkey and persists the result.
+func (vp *ValidatorParams) UpdateProposerConfig(ctx context.Context, pubkey libcommon.Bytes48, update func(cfg *ProposerConfig)) error {
+	return vp.db.Update(ctx, func(tx kv.RwTx) error {
+		v, err := tx.GetOne(kv.ValidatorProposerConfig, pubkey[:])
+		if err != nil {
+			return err
+		}
+		cfg := decodeProposerConfig(v)
+		update(&cfg)
+		if cfg == (ProposerConfig{}) {
+			return tx.Delete(kv.ValidatorProposerConfig, pubkey[:])
+		}
+		return tx.Put(kv.ValidatorProposerConfig, pubkey[:], cfg.encode())
+	})
+}
+
+func (cfg ProposerConfig) encode() []byte {
+	v := make([]byte, length.Addr+8)
+	copy(v, cfg.FeeRecipient[:])
+	binary.BigEndian.PutUint64(v[length.Addr:], cfg.GasLimit)
+	return v
+}
+
+func decodeProposerConfig(v []byte) (cfg ProposerConfig) {
+	if len(v) == length.Addr+8 {
+		cfg.FeeRecipient = libcommon.BytesToAddress(v[:length.Addr])
+		cfg.GasLimit = binary.BigEndian.Uint64(v[length.Addr:])
+	}
+	return
+}
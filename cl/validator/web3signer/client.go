@@ -0,0 +1,134 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package web3signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+var (
+	ErrKeyNotFound = errors.New("key not found in remote signer")
+	// ErrSlashable is returned when the remote signer refuses to sign a message because of its own slashing protection.
+	ErrSlashable = errors.New("remote signer refused to sign slashable message")
+)
+
+// Client signs messages with the keys held by a Web3Signer-compatible remote signer.
+// ref: https://consensys.github.io/web3signer/web3signer-eth2.html
+type Client struct {
+	httpClient *http.Client
+	url        *url.URL
+}
+
+func NewClient(baseUrl string) (*Client, error) {
+	u, err := url.Parse(baseUrl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid remote signer url %q", baseUrl)
+	}
+	return &Client{httpClient: &http.Client{}, url: u}, nil
+}
+
+// Url returns the base url of the remote signer.
+func (c *Client) Url() string {
+	return c.url.String()
+}
+
+// PublicKeys lists the keys held by the remote signer.
+func (c *Client) PublicKeys(ctx context.Context) ([]libcommon.Bytes48, error) {
+	body, err := c.call(ctx, http.MethodGet, c.url.JoinPath("/api/v1/eth2/publicKeys").String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var pubkeys []libcommon.Bytes48
+	if err := json.Unmarshal(body, &pubkeys); err != nil {
+		return nil, err
+	}
+	return pubkeys, nil
+}
+
+type signResponse struct {
+	Signature libcommon.Bytes96 `json:"signature"`
+}
+
+// Sign asks the remote signer to sign req with the key of pubkey.
+func (c *Client) Sign(ctx context.Context, pubkey libcommon.Bytes48, req *SignRequest) (libcommon.Bytes96, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return libcommon.Bytes96{}, err
+	}
+	body, err := c.call(ctx, http.MethodPost, c.url.JoinPath("/api/v1/eth2/sign", pubkey.String()).String(), payload)
+	if err != nil {
+		return libcommon.Bytes96{}, err
+	}
+	// the signature comes either as json or as plain hex text, depending on the signer.
+	var signature libcommon.Bytes96
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		var resp signResponse
+		if err := json.Unmarshal(trimmed, &resp); err != nil {
+			return libcommon.Bytes96{}, err
+		}
+		signature = resp.Signature
+	} else if err := signature.UnmarshalText(trimmed); err != nil {
+		return libcommon.Bytes96{}, fmt.Errorf("invalid signature from remote signer: %w", err)
+	}
+	return signature, nil
+}
+
+func (c *Client) call(ctx context.Context, method, url string, payload []byte) ([]byte, error) {
+	var payloadReader io.Reader
+	if payload != nil {
+		payloadReader = bytes.NewReader(payload)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, url, payloadReader)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/json")
+	if payload != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch response.StatusCode {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusNotFound:
+		return nil, ErrKeyNotFound
+	case http.StatusPreconditionFailed:
+		return nil, ErrSlashable
+	default:
+		return nil, fmt.Errorf("remote signer status code: %d, body: %s", response.StatusCode, body)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package web3signer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+)
+
+func TestClientSign(t *testing.T) {
+	ctx := context.Background()
+	pubkey := libcommon.Bytes48{1}
+	signature := libcommon.Bytes96{2}
+	var status int
+	var plainText bool
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/eth2/sign/"+pubkey.String(), r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+		if plainText {
+			w.Write([]byte(signature.String()))
+		} else {
+			json.NewEncoder(w).Encode(signResponse{Signature: signature})
+		}
+	}))
+	defer server.Close()
+	client, err := NewClient(server.URL)
+	require.NoError(t, err)
+
+	req := &SignRequest{
+		Type:        TypeBlockV2,
+		SigningRoot: libcommon.Hash{3},
+		BeaconBlock: NewBeaconBlock(clparams.DenebVersion, &cltypes.BeaconBlockHeader{Slot: 4}),
+	}
+	for _, plainText = range []bool{false, true} {
+		status = http.StatusOK
+		signed, err := client.Sign(ctx, pubkey, req)
+		require.NoError(t, err)
+		require.Equal(t, signature, signed)
+	}
+	require.Equal(t, TypeBlockV2, received["type"])
+	require.Equal(t, libcommon.Hash{3}.String(), received["signingRoot"])
+	require.Equal(t, "DENEB", received["beacon_block"].(map[string]any)["version"])
+	require.NotContains(t, received, "attestation")
+
+	status = http.StatusPreconditionFailed
+	_, err = client.Sign(ctx, pubkey, req)
+	require.ErrorIs(t, err, ErrSlashable)
+	status = http.StatusNotFound
+	_, err = client.Sign(ctx, pubkey, req)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = NewClient("signer")
+	require.Error(t, err)
+}
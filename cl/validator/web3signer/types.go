@@ -0,0 +1,83 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package web3signer
+
+import (
+	"strings"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+)
+
+// Types of the messages which can be signed.
+const (
+	TypeAggregationSlot                   = "AGGREGATION_SLOT"
+	TypeAggregateAndProof                 = "AGGREGATE_AND_PROOF"
+	TypeAttestation                       = "ATTESTATION"
+	TypeBlockV2                           = "BLOCK_V2"
+	TypeRandaoReveal                      = "RANDAO_REVEAL"
+	TypeSyncCommitteeMessage              = "SYNC_COMMITTEE_MESSAGE"
+	TypeSyncCommitteeSelectionProof       = "SYNC_COMMITTEE_SELECTION_PROOF"
+	TypeSyncCommitteeContributionAndProof = "SYNC_COMMITTEE_CONTRIBUTION_AND_PROOF"
+)
+
+// SignRequest is the body of a signing request. Besides the signing root, it carries the message itself, so that
+// the remote signer can check it against its own slashing protection. Only the field matching Type is set.
+type SignRequest struct {
+	Type        string         `json:"type"`
+	ForkInfo    *ForkInfo      `json:"fork_info,omitempty"`
+	SigningRoot libcommon.Hash `json:"signingRoot"`
+
+	AggregationSlot             *AggregationSlot                     `json:"aggregation_slot,omitempty"`
+	AggregateAndProof           *cltypes.AggregateAndProof           `json:"aggregate_and_proof,omitempty"`
+	Attestation                 solid.AttestationData                `json:"attestation,omitempty"`
+	BeaconBlock                 *BeaconBlock                         `json:"beacon_block,omitempty"`
+	RandaoReveal                *RandaoReveal                        `json:"randao_reveal,omitempty"`
+	SyncCommitteeMessage        *SyncCommitteeMessage                `json:"sync_committee_message,omitempty"`
+	SyncAggregatorSelectionData *cltypes.SyncAggregatorSelectionData `json:"sync_aggregator_selection_data,omitempty"`
+	ContributionAndProof        *cltypes.ContributionAndProof        `json:"contribution_and_proof,omitempty"`
+}
+
+type ForkInfo struct {
+	Fork                  *cltypes.Fork  `json:"fork"`
+	GenesisValidatorsRoot libcommon.Hash `json:"genesis_validators_root"`
+}
+
+type AggregationSlot struct {
+	Slot uint64 `json:"slot,string"`
+}
+
+// BeaconBlock holds the header of the block to sign, which has the same signing root as the block.
+type BeaconBlock struct {
+	Version     string                     `json:"version"`
+	BlockHeader *cltypes.BeaconBlockHeader `json:"block_header"`
+}
+
+func NewBeaconBlock(version clparams.StateVersion, header *cltypes.BeaconBlockHeader) *BeaconBlock {
+	return &BeaconBlock{Version: strings.ToUpper(version.String()), BlockHeader: header}
+}
+
+type RandaoReveal struct {
+	Epoch uint64 `json:"epoch,string"`
+}
+
+type SyncCommitteeMessage struct {
+	BeaconBlockRoot libcommon.Hash `json:"beacon_block_root"`
+	Slot            uint64         `json:"slot,string"`
+}
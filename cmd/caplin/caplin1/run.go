@@ -33,8 +33,10 @@ import (
 	"github.com/ledgerwatch/erigon/cl/aggregation"
 	"github.com/ledgerwatch/erigon/cl/antiquary"
 	"github.com/ledgerwatch/erigon/cl/beacon"
+	"github.com/ledgerwatch/erigon/cl/beacon/beacon_router_configuration"
 	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
 	"github.com/ledgerwatch/erigon/cl/beacon/handler"
+	"github.com/ledgerwatch/erigon/cl/beacon/keymanager"
 	"github.com/ledgerwatch/erigon/cl/beacon/synced_data"
 	"github.com/ledgerwatch/erigon/cl/clparams/initial_state"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
@@ -46,6 +48,7 @@ import (
 	"github.com/ledgerwatch/erigon/cl/validator/attestation_producer"
 	"github.com/ledgerwatch/erigon/cl/validator/committee_subscription"
 	"github.com/ledgerwatch/erigon/cl/validator/sync_contribution_pool"
+	"github.com/ledgerwatch/erigon/cl/validator/validator_client"
 	"github.com/ledgerwatch/erigon/cl/validator/validator_params"
 	"github.com/ledgerwatch/erigon/eth/ethconfig"
 	"github.com/ledgerwatch/erigon/params"
@@ -256,6 +259,15 @@ func RunCaplinPhase1(ctx context.Context, engine execution_client.ExecutionEngin
 	if err := beacon_indicies.WriteHighestFinalized(tx, 0); err != nil {
 		return err
 	}
+	depositSnapshot, err := state_accessors.ReadDepositSnapshot(tx)
+	if err != nil {
+		return err
+	}
+	if depositSnapshot != nil {
+		if err := forkChoice.LoadDepositSnapshot(depositSnapshot); err != nil {
+			logger.Warn("Could not load the deposit snapshot", "err", err)
+		}
+	}
 
 	vTables := state_accessors.NewStaticValidatorTable()
 	// Read the current table
@@ -282,9 +294,9 @@ func RunCaplinPhase1(ctx context.Context, engine execution_client.ExecutionEngin
 	}
 
 	statesReader := historical_states_reader.NewHistoricalStatesReader(beaconConfig, rcsn, vTables, genesisState)
-	validatorParameters := validator_params.NewValidatorParams()
-	if config.BeaconRouter.Active {
-		apiHandler := handler.NewApiHandler(
+	validatorParameters := validator_params.NewValidatorParams(indexDB)
+	newApiHandler := func(routerCfg *beacon_router_configuration.RouterConfiguration) *handler.ApiHandler {
+		return handler.NewApiHandler(
 			logger,
 			networkConfig,
 			ethClock,
@@ -297,7 +309,7 @@ func RunCaplinPhase1(ctx context.Context, engine execution_client.ExecutionEngin
 			statesReader,
 			sentinel,
 			params.GitTag,
-			&config.BeaconRouter,
+			routerCfg,
 			emitters,
 			blobStorage,
 			csn,
@@ -316,9 +328,24 @@ func RunCaplinPhase1(ctx context.Context, engine execution_client.ExecutionEngin
 			proposerSlashingService,
 			option.builderClient,
 		)
-		go beacon.ListenAndServe(&beacon.LayeredBeaconHandler{
+	}
+	var validatorClient *validator_client.ValidatorClient
+	if config.CaplinConfig.ValidatorClientEnabled() {
+		// the validator client performs its duties by calling into its own API handler, regardless of the enabled routes.
+		validatorClient, err = startValidatorClient(ctx, config.CaplinConfig, dirs, beaconConfig, ethClock, newApiHandler(&config.BeaconRouter), logger)
+		if err != nil {
+			return err
+		}
+	}
+	if config.BeaconRouter.Active {
+		apiHandler := newApiHandler(&config.BeaconRouter)
+		layeredHandler := &beacon.LayeredBeaconHandler{
 			ArchiveApi: apiHandler,
-		}, config.BeaconRouter)
+		}
+		if validatorClient != nil && config.BeaconRouter.KeymanagerToken != "" {
+			layeredHandler.KeymanagerApi = keymanager.NewKeymanagerApi(config.BeaconRouter.KeymanagerToken, validatorClient, validatorParameters)
+		}
+		go beacon.ListenAndServe(layeredHandler, config.BeaconRouter)
 		log.Info("Beacon API started", "addr", config.BeaconRouter.Address)
 	}
 
@@ -0,0 +1,116 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package caplin1
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/Giulio2002/bls"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
+	"github.com/ledgerwatch/erigon/cl/validator/validator_client"
+)
+
+// startValidatorClient loads the validator keys and the slashing protection database and runs the built-in
+// validator client in the background until ctx is cancelled.
+func startValidatorClient(
+	ctx context.Context,
+	cfg clparams.CaplinConfig,
+	dirs datadir.Dirs,
+	beaconConfig *clparams.BeaconChainConfig,
+	ethClock eth_clock.EthereumClock,
+	node validator_client.BeaconNode,
+	logger log.Logger,
+) (*validator_client.ValidatorClient, error) {
+	vcCfg := validator_client.Config{Graffiti: cfg.ValidatorGraffiti, RemoteSignerUrl: cfg.ValidatorRemoteSignerUrl}
+	if cfg.ValidatorFeeRecipient != "" {
+		if !libcommon.IsHexAddress(cfg.ValidatorFeeRecipient) {
+			return nil, fmt.Errorf("invalid validator fee recipient %q", cfg.ValidatorFeeRecipient)
+		}
+		vcCfg.FeeRecipient = libcommon.HexToAddress(cfg.ValidatorFeeRecipient)
+	}
+	var keys map[libcommon.Bytes48]*bls.PrivateKey
+	if cfg.ValidatorKeystoresDir != "" {
+		var err error
+		if keys, err = validator_client.LoadKeystores(cfg.ValidatorKeystoresDir, cfg.ValidatorPasswordsPath); err != nil {
+			return nil, err
+		}
+	}
+
+	dbPath := path.Join(dirs.DataDir, "caplin", "validator")
+	if err := os.MkdirAll(dbPath, 0700); err != nil {
+		return nil, err
+	}
+	vcCfg.ImportedKeysDir = path.Join(dbPath, "imported")
+	db := mdbx.MustOpen(dbPath)
+	vc, err := validator_client.NewValidatorClient(ctx, vcCfg, beaconConfig, ethClock, node, db, keys, logger)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	protection := vc.SlashingProtection()
+	if cfg.SlashingProtectionImport != "" {
+		if err := importSlashingProtection(ctx, protection, cfg.SlashingProtectionImport, ethClock.GenesisValidatorsRoot()); err != nil {
+			db.Close()
+			return nil, err
+		}
+		logger.Info("[Validator] Imported slashing protection data", "file", cfg.SlashingProtectionImport)
+	}
+
+	go func() {
+		defer db.Close()
+		if err := vc.Run(ctx); err != nil {
+			logger.Error("[Validator] Validator client stopped", "err", err)
+		}
+		if cfg.SlashingProtectionExport != "" {
+			if err := exportSlashingProtection(protection, cfg.SlashingProtectionExport); err != nil {
+				logger.Error("[Validator] Failed to export slashing protection data", "file", cfg.SlashingProtectionExport, "err", err)
+				return
+			}
+			logger.Info("[Validator] Exported slashing protection data", "file", cfg.SlashingProtectionExport)
+		}
+	}()
+	return vc, nil
+}
+
+func importSlashingProtection(ctx context.Context, protection *validator_client.SlashingProtection, file string, genesisValidatorsRoot libcommon.Hash) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return protection.ImportInterchange(ctx, f, genesisValidatorsRoot)
+}
+
+func exportSlashingProtection(protection *validator_client.SlashingProtection, file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// the node context is already cancelled at this point.
+	return protection.ExportInterchange(context.Background(), f, nil)
+}
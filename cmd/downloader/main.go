@@ -80,6 +80,8 @@ func main() {
 
 var (
 	webseeds                       string
+	webseedServeAddr               string
+	scrubRateStr                   string
 	datadirCli, chain              string
 	filePath                       string
 	forceRebuild                   bool
@@ -110,6 +112,8 @@ func init() {
 	withChainFlag(rootCmd)
 
 	rootCmd.Flags().StringVar(&webseeds, utils.WebSeedsFlag.Name, utils.WebSeedsFlag.Value, utils.WebSeedsFlag.Usage)
+	rootCmd.Flags().StringVar(&webseedServeAddr, utils.WebSeedServeAddrFlag.Name, utils.WebSeedServeAddrFlag.Value, utils.WebSeedServeAddrFlag.Usage)
+	rootCmd.Flags().StringVar(&scrubRateStr, utils.DownloaderScrubRateFlag.Name, utils.DownloaderScrubRateFlag.Value, utils.DownloaderScrubRateFlag.Usage)
 	rootCmd.Flags().StringVar(&natSetting, "nat", utils.NATFlag.Value, utils.NATFlag.Usage)
 	rootCmd.Flags().StringVar(&downloaderApiAddr, "downloader.api.addr", "127.0.0.1:9093", "external downloader api network address, for example: 127.0.0.1:9093 serves remote downloader interface")
 	rootCmd.Flags().StringVar(&downloadRateStr, "torrent.download.rate", utils.TorrentDownloadRateFlag.Value, utils.TorrentDownloadRateFlag.Usage)
@@ -241,6 +245,10 @@ func Downloader(ctx context.Context, logger log.Logger) error {
 	}
 
 	cfg.ClientConfig.PieceHashersPerTorrent = dbg.EnvInt("DL_HASHERS", 32)
+	cfg.WebSeedServeAddr = webseedServeAddr
+	if err := cfg.ScrubRate.UnmarshalText([]byte(scrubRateStr)); err != nil {
+		return err
+	}
 	cfg.ClientConfig.DisableIPv6 = disableIPV6
 	cfg.ClientConfig.DisableIPv4 = disableIPV4
 
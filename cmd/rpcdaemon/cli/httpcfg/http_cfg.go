@@ -19,6 +19,8 @@ package httpcfg
 import (
 	"time"
 
+	"github.com/c2h5oh/datasize"
+
 	"github.com/ledgerwatch/erigon/turbo/rpchelper"
 
 	"github.com/ledgerwatch/erigon-lib/common/datadir"
@@ -67,6 +69,8 @@ type HttpCfg struct {
 	WebsocketCompression              bool
 	WebsocketSubscribeLogsChannelSize int
 	RpcAllowListFilePath              string
+	RpcRateLimitFilePath              string
+	RpcResultCacheSize                datasize.ByteSize // 0 - disabled
 	RpcBatchConcurrency               uint
 	RpcStreamingDisable               bool
 	RpcFiltersConfig                  rpchelper.FiltersConfig
@@ -0,0 +1,231 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	remote "github.com/ledgerwatch/erigon-lib/gointerfaces/remoteproto"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/services"
+)
+
+// resultBlockFunc - number of the block the result of the call belongs to, false if it's unknown (tags like "latest",
+// pending txs, etc.) and the result must not be cached
+type resultBlockFunc func(ctx context.Context, c *ResultCache, params []json.RawMessage, result json.RawMessage) (uint64, bool, error)
+
+// resultCachePolicies - methods which results never change once their block is finalized
+var resultCachePolicies = map[string]resultBlockFunc{
+	"eth_getBlockByNumber":                 blockOfParam,
+	"eth_getBlockByHash":                   blockOfParam,
+	"eth_getBlockTransactionCountByNumber": blockOfParam,
+	"eth_getBlockReceipts":                 blockOfParam,
+	"eth_getTransactionByHash":             blockOfResult,
+	"eth_getTransactionReceipt":            blockOfResult,
+	"trace_block":                          blockOfParam,
+	"trace_transaction":                    blockOfTxnParam,
+	"debug_traceBlockByNumber":             blockOfParam,
+	"debug_traceBlockByHash":               blockOfParam,
+	"debug_traceTransaction":               blockOfTxnParam,
+}
+
+// resultCacheMaxEntries - bound of number of entries, the real bound is the size
+const resultCacheMaxEntries = 1 << 20
+
+type resultCacheEntry struct {
+	blockNum uint64
+	result   json.RawMessage
+}
+
+// ResultCache - size-bounded rpc.ResultCache of results of calls about finalized blocks. Finalized block and
+// reorgs are learned from the state-change stream: entries of unwound blocks are dropped.
+type ResultCache struct {
+	db          kv.RoDB
+	blockReader services.FullBlockReader
+	logger      log.Logger
+
+	lock      sync.Mutex
+	entries   *simplelru.LRU[string, resultCacheEntry] // by method+params
+	size      datasize.ByteSize
+	limit     datasize.ByteSize
+	finalized uint64
+}
+
+// NewResultCache - returns nil if limit is 0: caching is disabled
+func NewResultCache(limit datasize.ByteSize, db kv.RoDB, blockReader services.FullBlockReader, logger log.Logger) *ResultCache {
+	if limit == 0 {
+		return nil
+	}
+	c := &ResultCache{db: db, blockReader: blockReader, logger: logger, limit: limit}
+	c.entries, _ = simplelru.NewLRU[string, resultCacheEntry](resultCacheMaxEntries, func(key string, e resultCacheEntry) {
+		c.size -= datasize.ByteSize(len(key) + len(e.result))
+	})
+	return c
+}
+
+func resultCacheKey(method string, params []byte) string {
+	return method + string(params)
+}
+
+func (c *ResultCache) Cacheable(method string) bool {
+	_, ok := resultCachePolicies[method]
+	return ok
+}
+
+func (c *ResultCache) Get(method string, params []byte) (json.RawMessage, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e, ok := c.entries.Get(resultCacheKey(method, params))
+	if !ok {
+		return nil, false
+	}
+	return e.result, true
+}
+
+func (c *ResultCache) Put(ctx context.Context, method string, params []byte, result json.RawMessage) {
+	policy, ok := resultCachePolicies[method]
+	if !ok || bytes.Equal(result, []byte("null")) {
+		return
+	}
+	key := resultCacheKey(method, params)
+	size := datasize.ByteSize(len(key) + len(result))
+	if size > c.limit {
+		return
+	}
+	var args []json.RawMessage
+	if err := json.Unmarshal(params, &args); err != nil {
+		return
+	}
+	blockNum, ok, err := policy(ctx, c, args, result)
+	if err != nil {
+		c.logger.Debug("[rpc] result cache", "method", method, "err", err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if blockNum > c.finalized {
+		return
+	}
+	if c.entries.Contains(key) {
+		return
+	}
+	c.entries.Add(key, resultCacheEntry{blockNum: blockNum, result: result})
+	c.size += size
+	for c.size > c.limit {
+		c.entries.RemoveOldest()
+	}
+}
+
+// OnNewBlock - consumes the state-change stream: forgets results of unwound blocks and moves the finalized block.
+// Only results of blocks up to the finalized one are cached, so unwinds above it don't touch the cache
+func (c *ResultCache) OnNewBlock(batch *remote.StateChangeBatch) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	unwound := false
+	for _, change := range batch.ChangeBatch {
+		if change.Direction != remote.Direction_UNWIND || change.BlockHeight >= c.finalized {
+			continue
+		}
+		for _, key := range c.entries.Keys() {
+			if e, ok := c.entries.Peek(key); ok && e.blockNum > change.BlockHeight {
+				c.entries.Remove(key)
+			}
+		}
+		c.finalized = change.BlockHeight
+		unwound = true
+	}
+	// finalized block of the batch must not move it back above the unwind of the same batch
+	if batch.FinalizedBlock > 0 && (!unwound || batch.FinalizedBlock < c.finalized) {
+		c.finalized = batch.FinalizedBlock
+	}
+}
+
+// blockOfParam - block referenced by the first param: number (but not tag) or hash
+func blockOfParam(ctx context.Context, c *ResultCache, params []json.RawMessage, _ json.RawMessage) (uint64, bool, error) {
+	if len(params) == 0 {
+		return 0, false, nil
+	}
+	var blockNumOrHash rpc.BlockNumberOrHash
+	if err := json.Unmarshal(params[0], &blockNumOrHash); err != nil {
+		return 0, false, err
+	}
+	if blockNum, ok := blockNumOrHash.Number(); ok {
+		return uint64(blockNum), blockNum >= 0, nil
+	}
+	hash, _ := blockNumOrHash.Hash()
+	var blockNum *uint64
+	if err := c.db.View(ctx, func(tx kv.Tx) error {
+		blockNum = rawdb.ReadHeaderNumber(tx, hash)
+		return nil
+	}); err != nil {
+		return 0, false, err
+	}
+	if blockNum == nil {
+		return 0, false, nil
+	}
+	return *blockNum, true, nil
+}
+
+// blockOfTxnParam - block of the txn which hash is the first param
+func blockOfTxnParam(ctx context.Context, c *ResultCache, params []json.RawMessage, _ json.RawMessage) (uint64, bool, error) {
+	if len(params) == 0 {
+		return 0, false, nil
+	}
+	var txnHash libcommon.Hash
+	if err := json.Unmarshal(params[0], &txnHash); err != nil {
+		return 0, false, err
+	}
+	var blockNum uint64
+	var ok bool
+	if err := c.db.View(ctx, func(tx kv.Tx) (err error) {
+		blockNum, ok, err = c.blockReader.TxnLookup(ctx, tx, txnHash)
+		return err
+	}); err != nil {
+		return 0, false, err
+	}
+	return blockNum, ok, nil
+}
+
+// blockOfResult - "blockNumber" field of the result, it's null for pending txns
+func blockOfResult(_ context.Context, _ *ResultCache, _ []json.RawMessage, result json.RawMessage) (uint64, bool, error) {
+	var res struct {
+		BlockNumber *hexutil.Uint64 `json:"blockNumber"`
+	}
+	if err := json.Unmarshal(result, &res); err != nil {
+		return 0, false, err
+	}
+	if res.BlockNumber == nil {
+		return 0, false, nil
+	}
+	return uint64(*res.BlockNumber), true, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/stretchr/testify/require"
+
+	remote "github.com/ledgerwatch/erigon-lib/gointerfaces/remoteproto"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+)
+
+func TestResultCacheFinalizedAndUnwind(t *testing.T) {
+	ctx := context.Background()
+	c := NewResultCache(datasize.MB, nil, nil, log.New())
+	receipt := func(blockNum uint64) json.RawMessage {
+		return json.RawMessage(fmt.Sprintf(`{"blockNumber":"0x%x","status":"0x1"}`, blockNum))
+	}
+	put := func(method, params string, result json.RawMessage) bool {
+		c.Put(ctx, method, []byte(params), result)
+		_, ok := c.Get(method, []byte(params))
+		return ok
+	}
+
+	require.False(t, put("eth_getTransactionReceipt", `["0x01"]`, receipt(5)), "finalized block is unknown yet")
+	c.OnNewBlock(&remote.StateChangeBatch{FinalizedBlock: 10})
+	require.True(t, put("eth_getTransactionReceipt", `["0x01"]`, receipt(5)))
+	require.True(t, put("eth_getTransactionReceipt", `["0x02"]`, receipt(10)))
+	require.False(t, put("eth_getTransactionReceipt", `["0x03"]`, receipt(11)), "not finalized")
+	require.False(t, put("eth_getTransactionReceipt", `["0x04"]`, json.RawMessage(`{"blockNumber":null}`)), "pending")
+	require.False(t, put("eth_getTransactionReceipt", `["0x05"]`, json.RawMessage(`null`)), "not found")
+	require.True(t, put("eth_getBlockByNumber", `["0x7",false]`, json.RawMessage(`{"number":"0x7"}`)))
+	require.False(t, put("eth_getBlockByNumber", `["finalized",false]`, json.RawMessage(`{"number":"0xa"}`)), "tag")
+	require.False(t, put("eth_call", `[{},"0x7"]`, json.RawMessage(`"0x"`)), "no policy")
+
+	c.OnNewBlock(&remote.StateChangeBatch{ChangeBatch: []*remote.StateChange{{Direction: remote.Direction_UNWIND, BlockHeight: 6}}})
+	_, ok := c.Get("eth_getTransactionReceipt", []byte(`["0x01"]`))
+	require.True(t, ok)
+	_, ok = c.Get("eth_getTransactionReceipt", []byte(`["0x02"]`))
+	require.False(t, ok, "unwound")
+	_, ok = c.Get("eth_getBlockByNumber", []byte(`["0x7",false]`))
+	require.False(t, ok, "unwound")
+	require.False(t, put("eth_getTransactionReceipt", `["0x02"]`, receipt(10)), "finalized block moved back by unwind")
+
+	c.OnNewBlock(&remote.StateChangeBatch{ChangeBatch: []*remote.StateChange{{Direction: remote.Direction_UNWIND, BlockHeight: 8}}})
+	_, ok = c.Get("eth_getTransactionReceipt", []byte(`["0x01"]`))
+	require.True(t, ok, "unwind above the finalized block")
+	require.False(t, put("eth_getTransactionReceipt", `["0x06"]`, receipt(7)), "finalized block is not moved forward by unwind")
+
+	c.OnNewBlock(&remote.StateChangeBatch{
+		ChangeBatch:    []*remote.StateChange{{Direction: remote.Direction_UNWIND, BlockHeight: 4}},
+		FinalizedBlock: 10,
+	})
+	_, ok = c.Get("eth_getTransactionReceipt", []byte(`["0x01"]`))
+	require.False(t, ok, "unwound")
+	require.False(t, put("eth_getTransactionReceipt", `["0x01"]`, receipt(5)), "finalized block of the batch is above its unwind")
+	require.True(t, put("eth_getTransactionReceipt", `["0x07"]`, receipt(4)))
+
+	c.OnNewBlock(&remote.StateChangeBatch{FinalizedBlock: 10})
+	require.True(t, put("eth_getTransactionReceipt", `["0x01"]`, receipt(5)))
+}
+
+func TestResultCacheSizeLimit(t *testing.T) {
+	ctx := context.Background()
+	c := NewResultCache(datasize.KB, nil, nil, log.New())
+	c.OnNewBlock(&remote.StateChangeBatch{FinalizedBlock: 100})
+	for i := 0; i < 100; i++ {
+		c.Put(ctx, "eth_getBlockByNumber", []byte(fmt.Sprintf(`["0x%x",false]`, i)), json.RawMessage(fmt.Sprintf(`{"number":"0x%x"}`, i)))
+		require.LessOrEqual(t, c.size, c.limit)
+	}
+	_, ok := c.Get("eth_getBlockByNumber", []byte(`["0x63",false]`))
+	require.True(t, ok)
+	_, ok = c.Get("eth_getBlockByNumber", []byte(`["0x0",false]`))
+	require.False(t, ok, "evicted")
+
+	require.Nil(t, NewResultCache(0, nil, nil, log.New()), "disabled")
+}
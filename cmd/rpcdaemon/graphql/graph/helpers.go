@@ -1,10 +1,12 @@
 package graph
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 
 	hexutil2 "github.com/ledgerwatch/erigon-lib/common/hexutil"
 
@@ -13,6 +15,7 @@ import (
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/graphql/graph/model"
 	"github.com/ledgerwatch/erigon/core/types"
 )
 
@@ -135,3 +138,155 @@ func convertStrHexToDec(hexString *string) *string {
 
 	return &result
 }
+
+func convertLog(l *types.Log) *model.Log {
+	tlog := &model.Log{
+		Index:   int(l.Index),
+		Account: &model.Account{Address: strings.ToLower(l.Address.String())},
+		Topics:  make([]string, 0, len(l.Topics)),
+		Data:    hexutility.Encode(l.Data),
+	}
+	for _, topic := range l.Topics {
+		tlog.Topics = append(tlog.Topics, topic.String())
+	}
+	txIndex := int(l.TxIndex)
+	tlog.Transaction = &model.Transaction{
+		Hash:  l.TxHash.String(),
+		Index: &txIndex,
+		Block: &model.Block{Number: l.BlockNumber, Hash: l.BlockHash.String()},
+	}
+	return tlog
+}
+
+// convertPendingTransaction - fields of not yet mined txn, sender is recovered by signer
+func convertPendingTransaction(txn types.Transaction, signer *types.Signer) (*model.Transaction, error) {
+	sender, err := txn.Sender(*signer)
+	if err != nil {
+		return nil, err
+	}
+	trans := &model.Transaction{
+		Hash:      txn.Hash().String(),
+		Nonce:     hexutil2.EncodeUint64(txn.GetNonce()),
+		From:      &model.Account{Address: strings.ToLower(sender.String())},
+		Value:     hexutil2.EncodeBig(txn.GetValue().ToBig()),
+		GasPrice:  hexutil2.EncodeBig(txn.GetPrice().ToBig()),
+		Gas:       txn.GetGas(),
+		InputData: hexutility.Encode(txn.GetData()),
+	}
+	if to := txn.GetTo(); to != nil {
+		trans.To = &model.Account{Address: strings.ToLower(to.String())}
+	}
+	txType := int(txn.Type())
+	trans.Type = &txType
+	if txn.Type() >= types.DynamicFeeTxType {
+		feeCap, tip := hexutil2.EncodeBig(txn.GetFeeCap().ToBig()), hexutil2.EncodeBig(txn.GetTip().ToBig())
+		trans.MaxFeePerGas, trans.MaxPriorityFeePerGas = &feeCap, &tip
+	}
+	v, r, s := txn.RawSignatureValues()
+	trans.V, trans.R, trans.S = hexutil2.EncodeBig(v.ToBig()), hexutil2.EncodeBig(r.ToBig()), hexutil2.EncodeBig(s.ToBig())
+	var raw bytes.Buffer
+	if err = txn.MarshalBinary(&raw); err != nil {
+		return nil, err
+	}
+	trans.Raw = hexutility.Encode(raw.Bytes())
+	return trans, nil
+}
+
+// callTracerFrame - call frame as returned by the native callTracer
+type callTracerFrame struct {
+	Type         string             `json:"type"`
+	From         libcommon.Address  `json:"from"`
+	To           *libcommon.Address `json:"to"`
+	Value        *hexutil2.Big      `json:"value"`
+	Gas          hexutil2.Uint64    `json:"gas"`
+	GasUsed      hexutil2.Uint64    `json:"gasUsed"`
+	Input        hexutility.Bytes   `json:"input"`
+	Output       hexutility.Bytes   `json:"output"`
+	Error        string             `json:"error"`
+	RevertReason string             `json:"revertReason"`
+	Calls        []callTracerFrame  `json:"calls"`
+}
+
+// flattenCallFrames - appends frame and its subcalls to frames in execution order
+func flattenCallFrames(frame *callTracerFrame, traceAddress []int, frames []*model.CallFrame) []*model.CallFrame {
+	cf := &model.CallFrame{
+		Type:         frame.Type,
+		TraceAddress: traceAddress,
+		From:         strings.ToLower(frame.From.String()),
+		Gas:          uint64(frame.Gas),
+		GasUsed:      uint64(frame.GasUsed),
+		Input:        frame.Input.String(),
+	}
+	if frame.To != nil {
+		to := strings.ToLower(frame.To.String())
+		cf.To = &to
+	}
+	if frame.Value != nil {
+		value := frame.Value.String()
+		cf.Value = &value
+	}
+	if len(frame.Output) > 0 {
+		output := frame.Output.String()
+		cf.Output = &output
+	}
+	if frame.Error != "" {
+		cf.Error = &frame.Error
+	}
+	if frame.RevertReason != "" {
+		cf.RevertReason = &frame.RevertReason
+	}
+	frames = append(frames, cf)
+	for i := range frame.Calls {
+		subTraceAddress := make([]int, len(traceAddress), len(traceAddress)+1)
+		copy(subTraceAddress, traceAddress)
+		frames = flattenCallFrames(&frame.Calls[i], append(subTraceAddress, i), frames)
+	}
+	return frames
+}
+
+// convertMinedTransaction - txn fields merged with its receipt fields, as returned by GraphQLAPI.GetAccountTransactions
+func convertMinedTransaction(transaction map[string]interface{}) *model.Transaction {
+	trans := &model.Transaction{
+		Hash:      *convertDataToStringP(transaction, "hash"),
+		Nonce:     *convertDataToStringP(transaction, "nonce"),
+		From:      &model.Account{Address: strings.ToLower(*convertDataToStringP(transaction, "from"))},
+		Gas:       *convertDataToUint64P(transaction, "gas"),
+		InputData: *convertDataToStringP(transaction, "input"),
+		Type:      convertDataToIntP(transaction, "type"),
+	}
+	if _, ok := transaction["v"]; ok {
+		trans.V = *convertDataToStringP(transaction, "v")
+		trans.R = *convertDataToStringP(transaction, "r")
+		trans.S = *convertDataToStringP(transaction, "s")
+	}
+	if _, ok := transaction["to"]; ok {
+		trans.To = &model.Account{Address: strings.ToLower(*convertDataToStringP(transaction, "to"))}
+	}
+	if _, ok := transaction["value"]; ok {
+		trans.Value = *convertDataToStringP(transaction, "value")
+	}
+	if _, ok := transaction["gasPrice"]; ok {
+		trans.GasPrice = *convertDataToStringP(transaction, "gasPrice")
+	}
+	if _, ok := transaction["maxFeePerGas"]; ok {
+		trans.MaxFeePerGas = convertDataToStringP(transaction, "maxFeePerGas")
+	}
+	if _, ok := transaction["maxPriorityFeePerGas"]; ok {
+		trans.MaxPriorityFeePerGas = convertDataToStringP(transaction, "maxPriorityFeePerGas")
+	}
+	if _, ok := transaction["transactionIndex"]; ok {
+		trans.Index = convertDataToIntP(transaction, "transactionIndex")
+	}
+	_, hasNumber := transaction["blockNumber"]
+	_, hasHash := transaction["blockHash"]
+	if hasNumber && hasHash {
+		trans.Block = &model.Block{Number: *convertDataToUint64P(transaction, "blockNumber"), Hash: *convertDataToStringP(transaction, "blockHash")}
+	}
+	if _, ok := transaction["status"]; ok {
+		trans.Status = convertDataToUint64P(transaction, "status")
+		trans.GasUsed = convertDataToUint64P(transaction, "gasUsed")
+		trans.CumulativeGasUsed = convertDataToUint64P(transaction, "cumulativeGasUsed")
+		trans.EffectiveGasPrice = convertDataToStringP(transaction, "effectiveGasPrice")
+	}
+	return trans
+}
@@ -8,46 +8,53 @@ type AccessTuple struct {
 }
 
 type Account struct {
-	Address          string `json:"address"`
-	Balance          string `json:"balance"`
-	TransactionCount uint64 `json:"transactionCount"`
-	Code             string `json:"code"`
-	Storage          string `json:"storage"`
+	Address          string                 `json:"address"`
+	Balance          string                 `json:"balance"`
+	TransactionCount uint64                 `json:"transactionCount"`
+	Code             string                 `json:"code"`
+	Storage          string                 `json:"storage"`
+	Transactions     *TransactionConnection `json:"transactions"`
+}
+
+type BalanceChange struct {
+	Address string `json:"address"`
+	Balance string `json:"balance"`
 }
 
 type Block struct {
-	Number            uint64         `json:"number"`
-	Hash              string         `json:"hash"`
-	Parent            *Block         `json:"parent,omitempty"`
-	Nonce             string         `json:"nonce"`
-	TransactionsRoot  string         `json:"transactionsRoot"`
-	TransactionCount  *int           `json:"transactionCount,omitempty"`
-	StateRoot         string         `json:"stateRoot"`
-	ReceiptsRoot      string         `json:"receiptsRoot"`
-	Miner             *Account       `json:"miner"`
-	ExtraData         string         `json:"extraData"`
-	GasLimit          uint64         `json:"gasLimit"`
-	GasUsed           uint64         `json:"gasUsed"`
-	BaseFeePerGas     *string        `json:"baseFeePerGas,omitempty"`
-	NextBaseFeePerGas *string        `json:"nextBaseFeePerGas,omitempty"`
-	Timestamp         string         `json:"timestamp"`
-	LogsBloom         string         `json:"logsBloom"`
-	MixHash           string         `json:"mixHash"`
-	Difficulty        string         `json:"difficulty"`
-	TotalDifficulty   string         `json:"totalDifficulty"`
-	OmmerCount        *int           `json:"ommerCount,omitempty"`
-	Ommers            []*Block       `json:"ommers,omitempty"`
-	OmmerAt           *Block         `json:"ommerAt,omitempty"`
-	OmmerHash         string         `json:"ommerHash"`
-	Transactions      []*Transaction `json:"transactions,omitempty"`
-	TransactionAt     *Transaction   `json:"transactionAt,omitempty"`
-	Logs              []*Log         `json:"logs"`
-	Account           *Account       `json:"account"`
-	Call              *CallResult    `json:"call,omitempty"`
-	EstimateGas       uint64         `json:"estimateGas"`
-	RawHeader         string         `json:"rawHeader"`
-	Raw               string         `json:"raw"`
-	Withdrawals       []*Withdrawal  `json:"withdrawals,omitempty"`
+	Number            uint64           `json:"number"`
+	Hash              string           `json:"hash"`
+	Parent            *Block           `json:"parent,omitempty"`
+	Nonce             string           `json:"nonce"`
+	TransactionsRoot  string           `json:"transactionsRoot"`
+	TransactionCount  *int             `json:"transactionCount,omitempty"`
+	StateRoot         string           `json:"stateRoot"`
+	ReceiptsRoot      string           `json:"receiptsRoot"`
+	Miner             *Account         `json:"miner"`
+	ExtraData         string           `json:"extraData"`
+	GasLimit          uint64           `json:"gasLimit"`
+	GasUsed           uint64           `json:"gasUsed"`
+	BaseFeePerGas     *string          `json:"baseFeePerGas,omitempty"`
+	NextBaseFeePerGas *string          `json:"nextBaseFeePerGas,omitempty"`
+	Timestamp         string           `json:"timestamp"`
+	LogsBloom         string           `json:"logsBloom"`
+	MixHash           string           `json:"mixHash"`
+	Difficulty        string           `json:"difficulty"`
+	TotalDifficulty   string           `json:"totalDifficulty"`
+	OmmerCount        *int             `json:"ommerCount,omitempty"`
+	Ommers            []*Block         `json:"ommers,omitempty"`
+	OmmerAt           *Block           `json:"ommerAt,omitempty"`
+	OmmerHash         string           `json:"ommerHash"`
+	Transactions      []*Transaction   `json:"transactions,omitempty"`
+	TransactionAt     *Transaction     `json:"transactionAt,omitempty"`
+	Logs              []*Log           `json:"logs"`
+	Account           *Account         `json:"account"`
+	Call              *CallResult      `json:"call,omitempty"`
+	EstimateGas       uint64           `json:"estimateGas"`
+	RawHeader         string           `json:"rawHeader"`
+	Raw               string           `json:"raw"`
+	Withdrawals       []*Withdrawal    `json:"withdrawals,omitempty"`
+	BalanceChanges    []*BalanceChange `json:"balanceChanges"`
 }
 
 type BlockFilterCriteria struct {
@@ -66,6 +73,20 @@ type CallData struct {
 	Data                 *string `json:"data,omitempty"`
 }
 
+type CallFrame struct {
+	Type         string  `json:"type"`
+	TraceAddress []int   `json:"traceAddress"`
+	From         string  `json:"from"`
+	To           *string `json:"to,omitempty"`
+	Value        *string `json:"value,omitempty"`
+	Gas          uint64  `json:"gas"`
+	GasUsed      uint64  `json:"gasUsed"`
+	Input        string  `json:"input"`
+	Output       *string `json:"output,omitempty"`
+	Error        *string `json:"error,omitempty"`
+	RevertReason *string `json:"revertReason,omitempty"`
+}
+
 type CallResult struct {
 	Data    string `json:"data"`
 	GasUsed uint64 `json:"gasUsed"`
@@ -101,6 +122,9 @@ type Pending struct {
 type Query struct {
 }
 
+type Subscription struct {
+}
+
 type SyncState struct {
 	StartingBlock uint64 `json:"startingBlock"`
 	CurrentBlock  uint64 `json:"currentBlock"`
@@ -134,6 +158,12 @@ type Transaction struct {
 	AccessList           []*AccessTuple `json:"accessList,omitempty"`
 	Raw                  string         `json:"raw"`
 	RawReceipt           string         `json:"rawReceipt"`
+	Trace                []*CallFrame   `json:"trace,omitempty"`
+}
+
+type TransactionConnection struct {
+	Transactions []*Transaction `json:"transactions"`
+	EndCursor    *uint64        `json:"endCursor,omitempty"`
 }
 
 type Withdrawal struct {
@@ -7,17 +7,80 @@ package graph
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	log "github.com/ledgerwatch/erigon-lib/log/v3"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/graphql/graph/model"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/filters"
 	"github.com/ledgerwatch/erigon/rpc"
 )
 
+// Transactions is the resolver for the transactions field.
+func (r *accountResolver) Transactions(ctx context.Context, obj *model.Account, first *int, after *uint64) (*model.TransactionConnection, error) {
+	if !common.IsHexAddress(obj.Address) {
+		return nil, fmt.Errorf("invalid address: %s", obj.Address)
+	}
+	pageSize := 25
+	if first != nil {
+		pageSize = *first
+	}
+	if pageSize <= 0 || pageSize > math.MaxUint16 {
+		return nil, fmt.Errorf("invalid page size: %d", pageSize)
+	}
+	var blockNum uint64 // 0 - from the latest block
+	if after != nil {
+		if *after == 0 {
+			return &model.TransactionConnection{Transactions: []*model.Transaction{}}, nil
+		}
+		blockNum = *after
+	}
+
+	res, err := r.GraphQLAPI.GetAccountTransactions(ctx, common.HexToAddress(obj.Address), blockNum, uint16(pageSize))
+	if err != nil {
+		return nil, err
+	}
+	txs, _ := res["transactions"].([]map[string]interface{})
+	conn := &model.TransactionConnection{Transactions: make([]*model.Transaction, 0, len(txs))}
+	for _, txn := range txs {
+		conn.Transactions = append(conn.Transactions, convertMinedTransaction(txn))
+	}
+	if lastPage, _ := res["lastPage"].(bool); !lastPage && len(conn.Transactions) > 0 && conn.Transactions[len(conn.Transactions)-1].Block != nil {
+		endCursor := conn.Transactions[len(conn.Transactions)-1].Block.Number
+		conn.EndCursor = &endCursor
+	}
+	return conn, ctx.Err()
+}
+
+// BalanceChanges is the resolver for the balanceChanges field.
+func (r *blockResolver) BalanceChanges(ctx context.Context, obj *model.Block) ([]*model.BalanceChange, error) {
+	blockNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(obj.Number))
+	if obj.Hash != "" {
+		blockNrOrHash = rpc.BlockNumberOrHashWithHash(common.HexToHash(obj.Hash), false)
+	}
+	balances, err := r.GraphQLAPI.GetBalanceChangesInBlock(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*model.BalanceChange, 0, len(balances))
+	for address, balance := range balances {
+		changes = append(changes, &model.BalanceChange{
+			Address: strings.ToLower(address.String()),
+			Balance: balance.String(),
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Address < changes[j].Address })
+	return changes, ctx.Err()
+}
+
 // SendRawTransaction is the resolver for the sendRawTransaction field.
 func (r *mutationResolver) SendRawTransaction(ctx context.Context, data string) (string, error) {
 	panic(fmt.Errorf("not implemented: SendRawTransaction - sendRawTransaction"))
@@ -243,11 +306,146 @@ func (r *queryResolver) ChainID(ctx context.Context) (string, error) {
 	return "0x" + strconv.FormatUint(chainID.Uint64(), 16), err
 }
 
+// NewBlocks is the resolver for the newBlocks field.
+func (r *subscriptionResolver) NewBlocks(ctx context.Context) (<-chan *model.Block, error) {
+	headers, err := r.GraphQLAPI.SubscribeNewHeads(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blocks := make(chan *model.Block, 1)
+	go func() {
+		defer close(blocks)
+		for header := range headers {
+			if header == nil {
+				continue
+			}
+			blockNumberStr := strconv.FormatUint(header.Number.Uint64(), 10)
+			block, err := (&queryResolver{r.Resolver}).Block(ctx, &blockNumberStr, nil)
+			if err != nil {
+				log.Debug("[graphql] newBlocks subscription", "block", blockNumberStr, "err", err)
+				continue
+			}
+			select {
+			case blocks <- block:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return blocks, nil
+}
+
+// Logs is the resolver for the logs field.
+func (r *subscriptionResolver) Logs(ctx context.Context, filter model.FilterCriteria) (<-chan *model.Log, error) {
+	var crit filters.FilterCriteria
+	for _, address := range filter.Addresses {
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid address: %s", address)
+		}
+		crit.Addresses = append(crit.Addresses, common.HexToAddress(address))
+	}
+	for _, topics := range filter.Topics {
+		alternatives := make([]common.Hash, 0, len(topics))
+		for _, topic := range topics {
+			alternatives = append(alternatives, common.HexToHash(topic))
+		}
+		crit.Topics = append(crit.Topics, alternatives)
+	}
+
+	logs, err := r.GraphQLAPI.SubscribeLogs(ctx, crit)
+	if err != nil {
+		return nil, err
+	}
+	result := make(chan *model.Log, 1)
+	go func() {
+		defer close(result)
+		for l := range logs {
+			if l == nil {
+				continue
+			}
+			select {
+			case result <- convertLog(l):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return result, nil
+}
+
+// PendingTransactions is the resolver for the pendingTransactions field.
+func (r *subscriptionResolver) PendingTransactions(ctx context.Context) (<-chan *model.Transaction, error) {
+	chainID, err := r.GraphQLAPI.GetChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	signer := types.LatestSignerForChainID(chainID)
+
+	txsCh, err := r.GraphQLAPI.SubscribePendingTxs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make(chan *model.Transaction, 1)
+	go func() {
+		defer close(result)
+		for txs := range txsCh {
+			for _, txn := range txs {
+				if txn == nil {
+					continue
+				}
+				trans, err := convertPendingTransaction(txn, signer)
+				if err != nil {
+					log.Debug("[graphql] pendingTransactions subscription", "hash", txn.Hash(), "err", err)
+					continue
+				}
+				select {
+				case result <- trans:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return result, nil
+}
+
+// Trace is the resolver for the trace field.
+func (r *transactionResolver) Trace(ctx context.Context, obj *model.Transaction) ([]*model.CallFrame, error) {
+	res, err := r.GraphQLAPI.GetTransactionTrace(ctx, common.HexToHash(obj.Hash))
+	if err != nil {
+		return nil, err
+	}
+	var frame *callTracerFrame
+	if err = json.Unmarshal(res, &frame); err != nil {
+		return nil, fmt.Errorf("unexpected callTracer result: %w", err)
+	}
+	if frame == nil {
+		return nil, ctx.Err()
+	}
+	return flattenCallFrames(frame, []int{}, nil), ctx.Err()
+}
+
+// Account returns AccountResolver implementation.
+func (r *Resolver) Account() AccountResolver { return &accountResolver{r} }
+
+// Block returns BlockResolver implementation.
+func (r *Resolver) Block() BlockResolver { return &blockResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+// Transaction returns TransactionResolver implementation.
+func (r *Resolver) Transaction() TransactionResolver { return &transactionResolver{r} }
+
+type accountResolver struct{ *Resolver }
+type blockResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
+type transactionResolver struct{ *Resolver }
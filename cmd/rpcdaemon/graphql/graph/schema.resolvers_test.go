@@ -0,0 +1,175 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/graphql/graph/model"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/jsonrpc"
+)
+
+// mockGraphQLAPI - serves account txs from blocks the way otterscan search does: txs of blocks before blockNum,
+// with the last block completed even if the page overflows. Serves balance changes, other calls are not implemented
+type mockGraphQLAPI struct {
+	jsonrpc.GraphQLAPI
+
+	addr     common.Address
+	blocks   []uint64 // block numbers of the account txs, newest first
+	balances map[common.Address]*hexutil.Big
+
+	pageRequests  []uint64
+	balanceBlocks []rpc.BlockNumberOrHash
+}
+
+func (api *mockGraphQLAPI) GetAccountTransactions(_ context.Context, addr common.Address, blockNum uint64, pageSize uint16) (map[string]interface{}, error) {
+	api.pageRequests = append(api.pageRequests, blockNum)
+	txs := make([]map[string]interface{}, 0, pageSize)
+	i := 0
+	for i < len(api.blocks) && blockNum != 0 && api.blocks[i] >= blockNum {
+		i++
+	}
+	for ; i < len(api.blocks) && (len(txs) < int(pageSize) || api.blocks[i] == api.blocks[i-1]); i++ {
+		if addr != api.addr {
+			break
+		}
+		txs = append(txs, map[string]interface{}{
+			"hash":              common.BigToHash(new(big.Int).SetUint64(api.blocks[i])),
+			"nonce":             hexutil.Uint64(i),
+			"from":              addr,
+			"to":                common.Address{1},
+			"gas":               hexutil.Uint64(21000),
+			"input":             hexutility.Bytes{},
+			"type":              hexutil.Uint64(2),
+			"value":             (*hexutil.Big)(big.NewInt(1)),
+			"maxFeePerGas":      (*hexutil.Big)(big.NewInt(2)),
+			"transactionIndex":  hexutil.Uint64(0),
+			"blockNumber":       (*hexutil.Big)(new(big.Int).SetUint64(api.blocks[i])),
+			"blockHash":         common.Hash{byte(api.blocks[i])},
+			"status":            hexutil.Uint64(1),
+			"gasUsed":           hexutil.Uint64(21000),
+			"cumulativeGasUsed": hexutil.Uint64(42000),
+			"effectiveGasPrice": (*hexutil.Big)(big.NewInt(2)),
+		})
+	}
+	return map[string]interface{}{"transactions": txs, "lastPage": i == len(api.blocks)}, nil
+}
+
+func (api *mockGraphQLAPI) GetBalanceChangesInBlock(_ context.Context, blockNrOrHash rpc.BlockNumberOrHash) (map[common.Address]*hexutil.Big, error) {
+	api.balanceBlocks = append(api.balanceBlocks, blockNrOrHash)
+	return api.balances, nil
+}
+
+func TestAccountTransactionsPagination(t *testing.T) {
+	addr := common.HexToAddress("0xAbC0000000000000000000000000000000000001")
+	api := &mockGraphQLAPI{addr: addr, blocks: []uint64{9, 7, 7, 5, 3}}
+	resolver := (&Resolver{GraphQLAPI: api}).Account()
+	account := &model.Account{Address: addr.Hex()}
+
+	var blocks []uint64
+	var after *uint64
+	first := 2
+	for page := 0; ; page++ {
+		require.Less(t, page, 10, "pagination does not terminate")
+		conn, err := resolver.Transactions(context.Background(), account, &first, after)
+		require.NoError(t, err)
+		for _, txn := range conn.Transactions {
+			blocks = append(blocks, txn.Block.Number)
+		}
+		if conn.EndCursor == nil {
+			break
+		}
+		require.Equal(t, blocks[len(blocks)-1], *conn.EndCursor)
+		after = conn.EndCursor
+	}
+	require.Equal(t, []uint64{9, 7, 7, 5, 3}, blocks)
+	require.Equal(t, []uint64{0, 7}, api.pageRequests)
+
+	conn, err := resolver.Transactions(context.Background(), account, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, conn.Transactions, 5)
+	require.Nil(t, conn.EndCursor)
+
+	txn := conn.Transactions[0]
+	require.Equal(t, common.Hash{9}.Hex(), txn.Block.Hash)
+	require.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000009", txn.Hash)
+	require.Equal(t, "0xabc0000000000000000000000000000000000001", txn.From.Address)
+	require.Equal(t, "0x0100000000000000000000000000000000000000", txn.To.Address)
+	require.Equal(t, "0x1", txn.Value)
+	require.Equal(t, "0x2", *txn.MaxFeePerGas)
+	require.Nil(t, txn.MaxPriorityFeePerGas)
+	require.Equal(t, uint64(21000), txn.Gas)
+	require.Equal(t, 2, *txn.Type)
+	require.Equal(t, 0, *txn.Index)
+	require.Equal(t, uint64(1), *txn.Status)
+	require.Equal(t, uint64(21000), *txn.GasUsed)
+	require.Equal(t, uint64(42000), *txn.CumulativeGasUsed)
+	require.Equal(t, "0x2", *txn.EffectiveGasPrice)
+
+	api.pageRequests = nil
+	zero := uint64(0)
+	conn, err = resolver.Transactions(context.Background(), account, nil, &zero)
+	require.NoError(t, err)
+	require.Empty(t, conn.Transactions)
+	require.Nil(t, conn.EndCursor)
+	require.Empty(t, api.pageRequests, "there is nothing before the genesis block")
+
+	for _, invalid := range []int{0, -1, 1 << 16} {
+		_, err = resolver.Transactions(context.Background(), account, &invalid, nil)
+		require.ErrorContains(t, err, "invalid page size")
+	}
+	_, err = resolver.Transactions(context.Background(), &model.Account{Address: "0x01"}, nil, nil)
+	require.ErrorContains(t, err, "invalid address")
+}
+
+func TestBlockBalanceChanges(t *testing.T) {
+	api := &mockGraphQLAPI{balances: map[common.Address]*hexutil.Big{
+		common.HexToAddress("0xB0"): (*hexutil.Big)(big.NewInt(0)),
+		common.HexToAddress("0x0A"): (*hexutil.Big)(big.NewInt(255)),
+		common.HexToAddress("0xC0"): (*hexutil.Big)(big.NewInt(1)),
+	}}
+	resolver := (&Resolver{GraphQLAPI: api}).Block()
+
+	changes, err := resolver.BalanceChanges(context.Background(), &model.Block{Number: 5})
+	require.NoError(t, err)
+	require.Equal(t, []*model.BalanceChange{
+		{Address: "0x000000000000000000000000000000000000000a", Balance: "0xff"},
+		{Address: "0x00000000000000000000000000000000000000b0", Balance: "0x0"},
+		{Address: "0x00000000000000000000000000000000000000c0", Balance: "0x1"},
+	}, changes)
+
+	hash := common.Hash{5}
+	_, err = resolver.BalanceChanges(context.Background(), &model.Block{Number: 5, Hash: hash.Hex()})
+	require.NoError(t, err)
+
+	require.Len(t, api.balanceBlocks, 2)
+	number, ok := api.balanceBlocks[0].Number()
+	require.True(t, ok)
+	require.Equal(t, rpc.BlockNumber(5), number)
+	byHash, ok := api.balanceBlocks[1].Hash()
+	require.True(t, ok, "block known by hash is looked up by hash")
+	require.Equal(t, hash, byHash)
+}
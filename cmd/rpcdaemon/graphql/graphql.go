@@ -19,9 +19,16 @@ package graphql
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/gorilla/websocket"
+
+	"github.com/ledgerwatch/erigon-lib/log/v3"
 
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/graphql/graph"
 	"github.com/ledgerwatch/erigon/rpc"
@@ -30,9 +37,13 @@ import (
 
 const (
 	urlPath = "/graphql"
+
+	wsKeepAlivePingInterval = 10 * time.Second
 )
 
-func CreateHandler(api []rpc.API) *handler.Server {
+// CreateHandler builds the GraphQL handler. Subscriptions over websocket are accepted only from allowedOrigins,
+// or from localhost when none are given.
+func CreateHandler(api []rpc.API, allowedOrigins []string, logger log.Logger) *handler.Server {
 
 	var graphqlAPI jsonrpc.GraphQLAPI
 
@@ -49,7 +60,28 @@ func CreateHandler(api []rpc.API) *handler.Server {
 	resolver := graph.Resolver{}
 	resolver.GraphQLAPI = graphqlAPI
 
-	return handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: &resolver})) // TODO : init resolver.DB here !!!
+	// same transports and extensions as handler.NewDefaultServer, but websocket (subscriptions) checks the origin
+	// against the configured CORS domains, the same way JSON-RPC websocket does
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &resolver})) // TODO : init resolver.DB here !!!
+	srv.AddTransport(transport.Websocket{
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: rpc.WsHandshakeValidator(allowedOrigins, logger),
+		},
+		KeepAlivePingInterval: wsKeepAlivePingInterval,
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+
+	srv.SetQueryCache(lru.New(1000))
+
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{
+		Cache: lru.New(100),
+	})
+
+	return srv
 }
 
 func ProcessGraphQLcheckIfNeeded(
@@ -19,9 +19,15 @@ package graphql
 import (
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"regexp"
 	"strings"
 	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/log/v3"
 )
 
 func TestGraphQLQueryBlock(t *testing.T) {
@@ -159,3 +165,21 @@ func TestGraphQLQueryBlock(t *testing.T) {
 		}
 	}
 }
+
+func TestGraphQLWebsocketOrigin(t *testing.T) {
+	srv := httptest.NewServer(CreateHandler(nil, []string{"http://allowed.example"}, log.New()))
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-transport-ws"}}
+
+	conn, resp, err := dialer.Dial(url, http.Header{"Origin": []string{"http://evil.example"}})
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+
+	conn, resp, err = dialer.Dial(url, http.Header{"Origin": []string{"http://allowed.example"}})
+	require.NoError(t, err)
+	resp.Body.Close()
+	conn.Close()
+}
@@ -109,13 +109,15 @@ func (back *RemoteBackend) BlockByHash(ctx context.Context, db kv.Tx, hash commo
 	block, _, err := back.BlockWithSenders(ctx, db, hash, *number)
 	return block, err
 }
-func (back *RemoteBackend) TxsV3Enabled() bool                    { panic("not implemented") }
-func (back *RemoteBackend) Snapshots() services.BlockSnapshots    { panic("not implemented") }
-func (back *RemoteBackend) BorSnapshots() services.BlockSnapshots { panic("not implemented") }
-func (back *RemoteBackend) AllTypes() []snaptype.Type             { panic("not implemented") }
-func (back *RemoteBackend) FrozenBlocks() uint64                  { return back.blockReader.FrozenBlocks() }
-func (back *RemoteBackend) FrozenBorBlocks() uint64               { return back.blockReader.FrozenBorBlocks() }
-func (back *RemoteBackend) FrozenFiles() (list []string)          { return back.blockReader.FrozenFiles() }
+func (back *RemoteBackend) TxsV3Enabled() bool                      { panic("not implemented") }
+func (back *RemoteBackend) Snapshots() services.BlockSnapshots      { panic("not implemented") }
+func (back *RemoteBackend) BorSnapshots() services.BlockSnapshots   { panic("not implemented") }
+func (back *RemoteBackend) TraceSnapshots() services.BlockSnapshots { panic("not implemented") }
+func (back *RemoteBackend) AllTypes() []snaptype.Type               { panic("not implemented") }
+func (back *RemoteBackend) FrozenBlocks() uint64                    { return back.blockReader.FrozenBlocks() }
+func (back *RemoteBackend) FrozenBorBlocks() uint64                 { return back.blockReader.FrozenBorBlocks() }
+func (back *RemoteBackend) FrozenTraceResults() uint64              { return back.blockReader.FrozenTraceResults() }
+func (back *RemoteBackend) FrozenFiles() (list []string)            { return back.blockReader.FrozenFiles() }
 func (back *RemoteBackend) FreezingCfg() ethconfig.BlocksFreezing {
 	return back.blockReader.FreezingCfg()
 }
@@ -322,6 +324,10 @@ func (back *RemoteBackend) LastFrozenEventId() uint64 {
 	panic("not implemented")
 }
 
+func (back *RemoteBackend) TraceResults(ctx context.Context, tx kv.Getter, blockNum uint64) ([]byte, bool, error) {
+	return back.blockReader.TraceResults(ctx, tx, blockNum)
+}
+
 func (back *RemoteBackend) Span(ctx context.Context, tx kv.Getter, spanId uint64) ([]byte, error) {
 	return back.blockReader.Span(ctx, tx, spanId)
 }
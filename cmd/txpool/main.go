@@ -77,6 +77,11 @@ var (
 	noTxGossip bool
 
 	commitEvery time.Duration
+
+	privateTxLifetime uint64
+
+	journal   string
+	rejournal time.Duration
 )
 
 func init() {
@@ -102,6 +107,9 @@ func init() {
 	rootCmd.PersistentFlags().Uint64Var(&priceBump, "txpool.pricebump", txpoolcfg.DefaultConfig.PriceBump, "Price bump percentage to replace an already existing transaction")
 	rootCmd.PersistentFlags().Uint64Var(&blobPriceBump, "txpool.blobpricebump", txpoolcfg.DefaultConfig.BlobPriceBump, "Price bump percentage to replace an existing blob (type-3) transaction")
 	rootCmd.PersistentFlags().DurationVar(&commitEvery, utils.TxPoolCommitEveryFlag.Name, utils.TxPoolCommitEveryFlag.Value, utils.TxPoolCommitEveryFlag.Usage)
+	rootCmd.PersistentFlags().Uint64Var(&privateTxLifetime, utils.TxPoolPrivateTxLifetimeFlag.Name, utils.TxPoolPrivateTxLifetimeFlag.Value, utils.TxPoolPrivateTxLifetimeFlag.Usage)
+	rootCmd.PersistentFlags().StringVar(&journal, utils.TxPoolJournalFlag.Name, utils.TxPoolJournalFlag.Value, utils.TxPoolJournalFlag.Usage)
+	rootCmd.PersistentFlags().DurationVar(&rejournal, utils.TxPoolRejournalFlag.Name, utils.TxPoolRejournalFlag.Value, utils.TxPoolRejournalFlag.Usage)
 	rootCmd.PersistentFlags().BoolVar(&noTxGossip, utils.TxPoolGossipDisableFlag.Name, utils.TxPoolGossipDisableFlag.Value, utils.TxPoolGossipDisableFlag.Usage)
 	rootCmd.Flags().StringSliceVar(&traceSenders, utils.TxPoolTraceSendersFlag.Name, []string{}, utils.TxPoolTraceSendersFlag.Usage)
 }
@@ -171,6 +179,9 @@ func doTxpool(ctx context.Context, logger log.Logger) error {
 	cfg.PriceBump = priceBump
 	cfg.BlobPriceBump = blobPriceBump
 	cfg.NoGossip = noTxGossip
+	cfg.PrivateTxLifetime = privateTxLifetime
+	cfg.Journal = journal
+	cfg.Rejournal = rejournal
 
 	cacheConfig := kvcache.DefaultCoherentConfig
 	cacheConfig.MetricsLabel = "txpool"
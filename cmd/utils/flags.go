@@ -24,6 +24,7 @@ import (
 	"crypto/ecdsa"
 	"fmt"
 	"math/big"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
@@ -45,6 +46,7 @@ import (
 	libkzg "github.com/ledgerwatch/erigon-lib/crypto/kzg"
 	"github.com/ledgerwatch/erigon-lib/direct"
 	downloadercfg2 "github.com/ledgerwatch/erigon-lib/downloader/downloadercfg"
+	"github.com/ledgerwatch/erigon-lib/downloader/snaptype"
 	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
 
 	"github.com/ledgerwatch/erigon/cl/clparams"
@@ -228,6 +230,21 @@ var (
 		Usage: "How often transactions should be committed to the storage",
 		Value: txpoolcfg.DefaultConfig.CommitEvery,
 	}
+	TxPoolPrivateTxLifetimeFlag = cli.Uint64Flag{
+		Name:  "txpool.private.lifetime",
+		Usage: "Number of blocks a transaction sent by eth_sendPrivateRawTransaction stays in the pool if not mined",
+		Value: txpoolcfg.DefaultConfig.PrivateTxLifetime,
+	}
+	TxPoolJournalFlag = cli.StringFlag{
+		Name:  "txpool.journal",
+		Usage: "Disk journal for local transactions to survive loss of the txpool db (relative to the txpool dir, empty - disabled)",
+		Value: "transactions.rlp",
+	}
+	TxPoolRejournalFlag = cli.DurationFlag{
+		Name:  "txpool.rejournal",
+		Usage: "Time interval to regenerate the local transaction journal",
+		Value: txpoolcfg.DefaultConfig.Rejournal,
+	}
 	// Miner settings
 	MiningEnabledFlag = cli.BoolFlag{
 		Name:  "mine",
@@ -417,6 +434,15 @@ var (
 		Name:  "rpc.accessList",
 		Usage: "Specify granular (method-by-method) API allowlist",
 	}
+	RpcRateLimitFlag = cli.StringFlag{
+		Name:  "rpc.ratelimit",
+		Usage: "Path to JSON file with per-method and per-client rate limits of RPC calls",
+	}
+	RpcResultCacheFlag = cli.StringFlag{
+		Name:  "rpc.resultcache",
+		Value: "0MB",
+		Usage: "Amount of memory for results of calls about finalized blocks (eth_getBlockByNumber, debug_traceTransaction, etc.). Set 0 to disable",
+	}
 
 	RpcGasCapFlag = cli.UintFlag{
 		Name:  "rpc.gascap",
@@ -509,13 +535,11 @@ var (
 		Name:  "rpc.allow-unprotected-txs",
 		Usage: "Allow for unprotected (non-EIP155 signed) transactions to be submitted via RPC",
 	}
-	// Careful! Because we must rewind the hash state
-	// and re-compute the state trie, the further back in time the request, the more
-	// computationally intensive the operation becomes.
-	// The current default has been chosen arbitrarily as 'useful' without likely being overly computationally intense.
+	// Proofs are built from commitment history, which is kept only with --prune.include-commitment-history,
+	// for blocks executed by the node and not pruned by --prune.h.older.
 	RpcMaxGetProofRewindBlockCount = cli.IntFlag{
 		Name:  "rpc.maxgetproofrewindblockcount.limit",
-		Usage: "Max GetProof rewind block count",
+		Usage: "Max GetProof rewind block count (0 - no limit, as far as history is not pruned)",
 		Value: 100_000,
 	}
 	StateCacheFlag = cli.StringFlag{
@@ -690,6 +714,14 @@ var (
 		Name:  ethconfig.FlagSnapStop,
 		Usage: "Workaround to stop producing new snapshots, if you meet some snapshots-related critical bug. It will stop move historical data from DB to new immutable snapshots. DB will grow and may slightly slow-down - and removing this flag in future will not fix this effect (db size will not greatly reduce).",
 	}
+	SnapCodecFlag = cli.StringFlag{
+		Name:  "snap.codec",
+		Usage: "Compression codec of new snapshot files per type, comma-separated `<type>=<codec>`. Codecs: patterns (default), zstd. Example: transactions=zstd,bodies=zstd",
+	}
+	SnapHistoryWindowFlag = cli.Uint64Flag{
+		Name:  ethconfig.FlagSnapHistoryWindow,
+		Usage: "Partial history node: keep transactions only of this amount of recent blocks (min 100_000). Older transactions files are not downloaded and get deleted as new files are produced, RPC returns 'history pruned' error for them. Headers and bodies are kept. 0 - keep all history",
+	}
 	SnapStateStopFlag = cli.BoolFlag{
 		Name:  ethconfig.FlagSnapStateStop,
 		Usage: "Workaround to stop producing new state files, if you meet some state-related critical bug. It will stop aggregate DB history in a state files. DB will grow and may slightly slow-down - and removing this flag in future will not fix this effect (db size will not greatly reduce).",
@@ -779,6 +811,16 @@ var (
 		Usage: "Comma-separated URL's, holding metadata about network-support infrastructure (like S3 buckets with snapshots, bootnodes, etc...)",
 		Value: "",
 	}
+	DownloaderScrubRateFlag = cli.StringFlag{
+		Name:  "downloader.scrub.rate",
+		Usage: "Periodically re-hash pieces of completed snapshot files in background, reading at most this many bytes per second. Corrupted files are quarantined and re-downloaded. Example: 16mb. 0 - disabled",
+		Value: "0",
+	}
+	WebSeedServeAddrFlag = cli.StringFlag{
+		Name:  "webseed.serve.addr",
+		Usage: "Serve completed snapshot files and their .torrent files over HTTP on this address (for example 0.0.0.0:8081): other nodes can use it as `--webseed=http://<host>:8081`. Disabled by default",
+		Value: "",
+	}
 
 	HeimdallURLFlag = cli.StringFlag{
 		Name:  "bor.heimdall",
@@ -1000,6 +1042,46 @@ var (
 		Usage: "enables archival node in caplin",
 		Value: false,
 	}
+	CaplinValidatorKeystoresFlag = cli.StringFlag{
+		Name:  "caplin.validator.keystores",
+		Usage: "directory of EIP-2335 keystores to run caplin's built-in validator client with",
+		Value: "",
+	}
+	CaplinValidatorPasswordsFlag = cli.StringFlag{
+		Name:  "caplin.validator.passwords",
+		Usage: "password file for all the keystores, or directory with one password file per keystore",
+		Value: "",
+	}
+	CaplinValidatorRemoteSignerFlag = cli.StringFlag{
+		Name:  "caplin.validator.remote-signer",
+		Usage: "url of a Web3Signer-compatible remote signer whose keys caplin's built-in validator client signs with",
+		Value: "",
+	}
+	CaplinValidatorFeeRecipientFlag = cli.StringFlag{
+		Name:  "caplin.validator.fee-recipient",
+		Usage: "fee recipient of the blocks proposed by caplin's built-in validator client",
+		Value: "",
+	}
+	CaplinValidatorGraffitiFlag = cli.StringFlag{
+		Name:  "caplin.validator.graffiti",
+		Usage: "graffiti of the blocks proposed by caplin's built-in validator client",
+		Value: "",
+	}
+	CaplinSlashingProtectionImportFlag = cli.StringFlag{
+		Name:  "caplin.validator.slashing-protection.import",
+		Usage: "EIP-3076 interchange file to import into the slashing protection database at startup",
+		Value: "",
+	}
+	CaplinSlashingProtectionExportFlag = cli.StringFlag{
+		Name:  "caplin.validator.slashing-protection.export",
+		Usage: "file to export the slashing protection database to, in EIP-3076 interchange format, at shutdown",
+		Value: "",
+	}
+	BeaconApiKeymanagerTokenFileFlag = cli.StringFlag{
+		Name:  "beacon.api.keymanager.token-file",
+		Usage: "file holding the bearer token of the keymanager api, which is served along the beacon api when caplin's built-in validator client is enabled",
+		Value: "",
+	}
 	BeaconApiAllowCredentialsFlag = cli.BoolFlag{
 		Name:  "beacon.api.cors.allow-credentials",
 		Usage: "set the cors' allow credentials",
@@ -1518,6 +1600,9 @@ func setTxPool(ctx *cli.Context, fullCfg *ethconfig.Config) {
 		fullCfg.TxPool.BlobPriceBump = ctx.Uint64(TxPoolBlobPriceBumpFlag.Name)
 	}
 	cfg.CommitEvery = common2.RandomizeDuration(ctx.Duration(TxPoolCommitEveryFlag.Name))
+	fullCfg.TxPool.PrivateTxLifetime = ctx.Uint64(TxPoolPrivateTxLifetimeFlag.Name)
+	fullCfg.TxPool.Journal = ctx.String(TxPoolJournalFlag.Name)
+	fullCfg.TxPool.Rejournal = ctx.Duration(TxPoolRejournalFlag.Name)
 }
 
 func setEthash(ctx *cli.Context, datadir string, cfg *ethconfig.Config) {
@@ -1676,6 +1761,16 @@ func setBeaconAPI(ctx *cli.Context, cfg *ethconfig.Config) error {
 	cfg.BeaconRouter.AllowedMethods = ctx.StringSlice(BeaconApiAllowMethodsFlag.Name)
 	cfg.BeaconRouter.AllowedOrigins = ctx.StringSlice(BeaconApiAllowOriginsFlag.Name)
 	cfg.BeaconRouter.AllowCredentials = ctx.Bool(BeaconApiAllowCredentialsFlag.Name)
+	if tokenFile := ctx.String(BeaconApiKeymanagerTokenFileFlag.Name); tokenFile != "" {
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return fmt.Errorf("failed to read keymanager api token: %w", err)
+		}
+		cfg.BeaconRouter.KeymanagerToken = strings.TrimSpace(string(token))
+		if cfg.BeaconRouter.KeymanagerToken == "" {
+			return fmt.Errorf("keymanager api token file %s is empty", tokenFile)
+		}
+	}
 	return nil
 }
 
@@ -1687,6 +1782,13 @@ func setCaplin(ctx *cli.Context, cfg *ethconfig.Config) {
 	cfg.CaplinConfig.BlobPruningDisabled = ctx.Bool(CaplinDisableBlobPruningFlag.Name)
 	cfg.CaplinConfig.Archive = ctx.Bool(CaplinArchiveFlag.Name)
 	cfg.CaplinConfig.MevRelayUrl = ctx.String(CaplinMevRelayUrl.Name)
+	cfg.CaplinConfig.ValidatorKeystoresDir = ctx.String(CaplinValidatorKeystoresFlag.Name)
+	cfg.CaplinConfig.ValidatorPasswordsPath = ctx.String(CaplinValidatorPasswordsFlag.Name)
+	cfg.CaplinConfig.ValidatorRemoteSignerUrl = ctx.String(CaplinValidatorRemoteSignerFlag.Name)
+	cfg.CaplinConfig.ValidatorFeeRecipient = ctx.String(CaplinValidatorFeeRecipientFlag.Name)
+	cfg.CaplinConfig.ValidatorGraffiti = ctx.String(CaplinValidatorGraffitiFlag.Name)
+	cfg.CaplinConfig.SlashingProtectionImport = ctx.String(CaplinSlashingProtectionImportFlag.Name)
+	cfg.CaplinConfig.SlashingProtectionExport = ctx.String(CaplinSlashingProtectionExportFlag.Name)
 	if checkpointUrls := ctx.StringSlice(CaplinCheckpointSyncUrlFlag.Name); len(checkpointUrls) > 0 {
 		clparams.ConfigurableCheckpointsURLs = checkpointUrls
 	}
@@ -1770,6 +1872,10 @@ func SetEthConfig(ctx *cli.Context, nodeConfig *nodecfg.Config, cfg *ethconfig.C
 	cfg.Snapshot.KeepBlocks = ctx.Bool(SnapKeepBlocksFlag.Name)
 	cfg.Snapshot.ProduceE2 = !ctx.Bool(SnapStopFlag.Name)
 	cfg.Snapshot.ProduceE3 = !ctx.Bool(SnapStateStopFlag.Name)
+	if err := snaptype.SetCodecs(ctx.String(SnapCodecFlag.Name)); err != nil {
+		Fatalf("Option %s: %v", SnapCodecFlag.Name, err)
+	}
+	cfg.Snapshot.HistoryWindow = ctx.Uint64(SnapHistoryWindowFlag.Name)
 	cfg.Snapshot.NoDownloader = ctx.Bool(NoDownloaderFlag.Name)
 	cfg.Snapshot.Verify = ctx.Bool(DownloaderVerifyFlag.Name)
 	cfg.Snapshot.DownloaderAddr = strings.TrimSpace(ctx.String(DownloaderAddrFlag.Name))
@@ -1801,6 +1907,10 @@ func SetEthConfig(ctx *cli.Context, nodeConfig *nodecfg.Config, cfg *ethconfig.C
 		if err != nil {
 			panic(err)
 		}
+		cfg.Downloader.WebSeedServeAddr = ctx.String(WebSeedServeAddrFlag.Name)
+		if err := cfg.Downloader.ScrubRate.UnmarshalText([]byte(ctx.String(DownloaderScrubRateFlag.Name))); err != nil {
+			panic(err)
+		}
 		downloadernat.DoNat(nodeConfig.P2P.NAT, cfg.Downloader.ClientConfig, logger)
 	}
 
@@ -1124,6 +1124,82 @@ func WriteTotalBurnt(db kv.Putter, number uint64, totalBurnt *big.Int) error {
 	return db.Put(kv.Issuance, append([]byte("burnt"), hexutility.EncodeTs(number)...), totalBurnt.Bytes())
 }
 
+// ReadSupplyDelta retrieves the supply delta of the given block, nil if it wasn't recorded.
+func ReadSupplyDelta(db kv.Getter, number uint64) (*types.SupplyDelta, error) {
+	data, err := db.GetOne(kv.SupplyDelta, hexutility.EncodeTs(number))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	delta := new(types.SupplyDelta)
+	if err := rlp.DecodeBytes(data, delta); err != nil {
+		return nil, fmt.Errorf("invalid supply delta RLP: %w", err)
+	}
+	return delta, nil
+}
+
+func WriteSupplyDelta(db kv.Putter, number uint64, delta *types.SupplyDelta) error {
+	data, err := rlp.EncodeToBytes(delta)
+	if err != nil {
+		return err
+	}
+	return db.Put(kv.SupplyDelta, hexutility.EncodeTs(number), data)
+}
+
+// TruncateSupplyDeltas deletes the supply deltas of blocks starting from blockFrom.
+func TruncateSupplyDeltas(tx kv.RwTx, blockFrom uint64) error {
+	if err := tx.ForEach(kv.SupplyDelta, hexutility.EncodeTs(blockFrom), func(k, _ []byte) error {
+		return tx.Delete(kv.SupplyDelta, k)
+	}); err != nil {
+		return fmt.Errorf("TruncateSupplyDeltas: %w", err)
+	}
+	return nil
+}
+
+// ReadTraceResults retrieves the compressed trace results of the given block, nil if they weren't recorded
+// or were already moved to files.
+func ReadTraceResults(db kv.Getter, number uint64) ([]byte, error) {
+	return db.GetOne(kv.TraceResults, hexutility.EncodeTs(number))
+}
+
+func WriteTraceResults(db kv.Putter, number uint64, data []byte) error {
+	return db.Put(kv.TraceResults, hexutility.EncodeTs(number), data)
+}
+
+// TruncateTraceResults deletes the trace results of blocks starting from blockFrom.
+func TruncateTraceResults(tx kv.RwTx, blockFrom uint64) error {
+	if err := tx.ForEach(kv.TraceResults, hexutility.EncodeTs(blockFrom), func(k, _ []byte) error {
+		return tx.Delete(kv.TraceResults, k)
+	}); err != nil {
+		return fmt.Errorf("TruncateTraceResults: %w", err)
+	}
+	return nil
+}
+
+// PruneTraceResults deletes at most `limit` trace results of blocks below blockTo.
+func PruneTraceResults(tx kv.RwTx, blockTo uint64, limit int) (deleted int, err error) {
+	c, err := tx.RwCursor(kv.TraceResults)
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	for k, _, err := c.First(); k != nil; k, _, err = c.Next() {
+		if err != nil {
+			return deleted, err
+		}
+		if binary.BigEndian.Uint64(k) >= blockTo || deleted >= limit {
+			break
+		}
+		if err = c.DeleteCurrent(); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
 func ReadHeaderByNumber(db kv.Getter, number uint64) *types.Header {
 	hash, err := ReadCanonicalHash(db, number)
 	if err != nil {
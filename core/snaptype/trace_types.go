@@ -0,0 +1,116 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package snaptype
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon-lib/common/background"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/downloader/snaptype"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+	"github.com/ledgerwatch/erigon-lib/recsplit"
+)
+
+// Trace results files are produced locally by the optional TraceResults stage. They are not part of
+// BlockSnapshotTypes: nodes without the stage have no such files, and block files don't wait for them.
+
+var TraceEnums = struct {
+	snaptype.Enums
+	TraceResults snaptype.Enum
+}{
+	Enums:        snaptype.Enums{},
+	TraceResults: snaptype.MinTraceEnum,
+}
+
+var TraceIndexes = struct {
+	TraceResultsBlockNum snaptype.Index
+}{
+	TraceResultsBlockNum: snaptype.Index{Name: "traceresults"},
+}
+
+var (
+	// TraceResults - one word per block: the compressed trace_block result, as stored in kv.TraceResults
+	TraceResults = snaptype.RegisterType(
+		TraceEnums.TraceResults,
+		"traceresults",
+		snaptype.Versions{
+			Current:      1,
+			MinSupported: 1,
+		},
+		snaptype.RangeExtractorFunc(
+			func(ctx context.Context, blockFrom, blockTo uint64, _ snaptype.FirstKeyGetter, db kv.RoDB, _ *chain.Config, collect func([]byte) error, workers int, lvl log.Lvl, logger log.Logger) (uint64, error) {
+				logEvery := time.NewTicker(20 * time.Second)
+				defer logEvery.Stop()
+
+				// every block of the range must be present: files are read by ordinal
+				expected := blockFrom
+				if err := kv.BigChunks(db, kv.TraceResults, hexutility.EncodeTs(blockFrom), func(tx kv.Tx, k, v []byte) (bool, error) {
+					blockNum := binary.BigEndian.Uint64(k)
+					if blockNum >= blockTo {
+						return false, nil
+					}
+					if blockNum != expected {
+						return false, fmt.Errorf("trace results of block %d not found", expected)
+					}
+					expected++
+					if err := collect(v); err != nil {
+						return false, err
+					}
+					select {
+					case <-ctx.Done():
+						return false, ctx.Err()
+					case <-logEvery.C:
+						logger.Log(lvl, "[snapshots] Dumping trace results", "block", blockNum)
+					default:
+					}
+					return true, nil
+				}); err != nil {
+					return 0, err
+				}
+				if expected != blockTo {
+					return 0, fmt.Errorf("trace results of block %d not found", expected)
+				}
+				return blockTo, nil
+			}),
+		[]snaptype.Index{TraceIndexes.TraceResultsBlockNum},
+		snaptype.IndexBuilderFunc(
+			func(ctx context.Context, info snaptype.FileInfo, salt uint32, _ *chain.Config, tmpDir string, p *background.Progress, lvl log.Lvl, logger log.Logger) (err error) {
+				num := make([]byte, binary.MaxVarintLen64)
+
+				if err := snaptype.BuildIndex(ctx, info, salt, info.From, tmpDir, log.LvlDebug, p, func(idx *recsplit.RecSplit, i, offset uint64, _ []byte) error {
+					if p != nil {
+						p.Processed.Add(1)
+					}
+					n := binary.PutUvarint(num, i)
+					return idx.AddKey(num[:n], offset)
+				}, logger); err != nil {
+					return fmt.Errorf("can't index %s: %w", info.Name(), err)
+				}
+				return nil
+			}),
+	)
+)
+
+func TraceSnapshotTypes() []snaptype.Type {
+	return []snaptype.Type{TraceResults}
+}
@@ -95,6 +95,7 @@ type IntraBlockState struct {
 	nextRevisionID int
 	trace          bool
 	balanceInc     map[libcommon.Address]*BalanceIncrease // Map of balance increases (without first reading the account)
+	tracingHooks   *tracing.Hooks
 }
 
 // Create a new state from a given trie
@@ -117,6 +118,11 @@ func (sdb *IntraBlockState) SetTrace(trace bool) {
 	sdb.trace = trace
 }
 
+// SetHooks installs the state event hooks (balance changes etc.) of a tracer.
+func (sdb *IntraBlockState) SetHooks(hooks *tracing.Hooks) {
+	sdb.tracingHooks = hooks
+}
+
 // setErrorUnsafe sets error but should be called in medhods that already have locks
 func (sdb *IntraBlockState) setErrorUnsafe(err error) {
 	if sdb.savedErr == nil {
@@ -238,6 +244,11 @@ func (sdb *IntraBlockState) TxIndex() int {
 	return sdb.txIndex
 }
 
+// BlockHash returns the current block hash set by SetTxContext.
+func (sdb *IntraBlockState) BlockHash() libcommon.Hash {
+	return sdb.bhash
+}
+
 // DESCRIBED: docs/programmers_guide/guide.md#address---identifier-of-an-account
 func (sdb *IntraBlockState) GetCode(addr libcommon.Address) []byte {
 	stateObject := sdb.getStateObject(addr)
@@ -329,6 +340,10 @@ func (sdb *IntraBlockState) AddBalance(addr libcommon.Address, amount *uint256.I
 	if !needAccount && addr == ripemd && amount.IsZero() {
 		needAccount = true
 	}
+	// Balance change hooks need to know the previous balance
+	if !needAccount && sdb.tracingHooks != nil && sdb.tracingHooks.OnBalanceChange != nil {
+		needAccount = true
+	}
 	if !needAccount {
 		sdb.journal.append(balanceIncrease{
 			account:  &addr,
@@ -434,6 +449,9 @@ func (sdb *IntraBlockState) Selfdestruct(addr libcommon.Address) bool {
 		prev:        stateObject.selfdestructed,
 		prevbalance: *stateObject.Balance(),
 	})
+	if sdb.tracingHooks != nil && sdb.tracingHooks.OnBalanceChange != nil && !stateObject.Balance().IsZero() {
+		sdb.tracingHooks.OnBalanceChange(addr, stateObject.Balance(), new(uint256.Int), tracing.BalanceDecreaseSelfdestruct)
+	}
 	stateObject.markSelfdestructed()
 	stateObject.createdContract = false
 	stateObject.data.Balance.Clear()
@@ -697,6 +715,11 @@ func (sdb *IntraBlockState) FinalizeTx(chainRules *chain.Rules, stateWriter Stat
 			continue
 		}
 
+		// Ether sent to an account after it self-destructed within the same transaction is burnt
+		if so.selfdestructed && !so.deleted && !so.data.Balance.IsZero() && sdb.tracingHooks != nil && sdb.tracingHooks.OnBalanceChange != nil {
+			sdb.tracingHooks.OnBalanceChange(addr, &so.data.Balance, new(uint256.Int), tracing.BalanceDecreaseSelfdestructBurn)
+		}
+
 		//fmt.Printf("FinalizeTx: %x, balance=%d %T\n", addr, so.data.Balance.Uint64(), stateWriter)
 		if err := updateAccount(chainRules.IsSpuriousDragon, chainRules.IsAura, stateWriter, addr, so, true); err != nil {
 			return err
@@ -294,6 +294,9 @@ func (so *stateObject) SubBalance(amount *uint256.Int, reason tracing.BalanceCha
 }
 
 func (so *stateObject) SetBalance(amount *uint256.Int, reason tracing.BalanceChangeReason) {
+	if so.db.tracingHooks != nil && so.db.tracingHooks.OnBalanceChange != nil {
+		so.db.tracingHooks.OnBalanceChange(so.address, so.Balance(), amount, reason)
+	}
 	so.db.journal.append(balanceChange{
 		account: &so.address,
 		prev:    so.data.Balance,
@@ -0,0 +1,269 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package stateless
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/holiman/uint256"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/turbo/trie"
+)
+
+// ErrMissingNode is returned when execution reaches a part of the trie which is not covered by the witness
+var ErrMissingNode = errors.New("trie node is missing in the witness")
+
+// witnessState serves block execution out of the witness: reads come from tries built of witness
+// nodes, writes are applied to the same tries, so the post-state root can be recomputed afterwards.
+type witnessState struct {
+	nodes    map[libcommon.Hash][]byte
+	codes    map[libcommon.Hash][]byte
+	accounts *trie.Trie
+	storage  map[libcommon.Address]*trie.Trie
+
+	// account updates are applied to the account trie on Root, after storage roots are known
+	updated      map[libcommon.Address]*accounts.Account // nil for deleted accounts
+	storageDirty map[libcommon.Address]struct{}
+	err          error // first error, execution does not always propagate reader errors
+}
+
+var _ state.StateReader = (*witnessState)(nil)
+var _ state.WriterWithChangeSets = (*witnessState)(nil)
+
+func newWitnessState(root libcommon.Hash, witness *Witness) (*witnessState, error) {
+	ws := &witnessState{
+		nodes:        make(map[libcommon.Hash][]byte, len(witness.State)),
+		codes:        make(map[libcommon.Hash][]byte, len(witness.Codes)),
+		storage:      make(map[libcommon.Address]*trie.Trie),
+		updated:      make(map[libcommon.Address]*accounts.Account),
+		storageDirty: make(map[libcommon.Address]struct{}),
+	}
+	for _, node := range witness.State {
+		ws.nodes[crypto.Keccak256Hash(node)] = node
+	}
+	for _, code := range witness.Codes {
+		ws.codes[crypto.Keccak256Hash(code)] = code
+	}
+	if _, ok := ws.nodes[root]; !ok && root != trie.EmptyRoot {
+		return nil, fmt.Errorf("%w: state root %x", ErrMissingNode, root)
+	}
+	var err error
+	if ws.accounts, err = trie.NewFromWitness(root, ws.nodes); err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
+
+// wrapMissingNode makes trie errors about nodes which are not in the witness match ErrMissingNode
+func wrapMissingNode(err error) error {
+	var missing *trie.MissingNodeError
+	if errors.As(err, &missing) {
+		return fmt.Errorf("%w: %w", ErrMissingNode, err)
+	}
+	return err
+}
+
+func (ws *witnessState) fail(err error) error {
+	if ws.err == nil {
+		ws.err = err
+	}
+	return err
+}
+
+// readAccount reads account as of the beginning of the block
+func (ws *witnessState) readAccount(address libcommon.Address) (*accounts.Account, error) {
+	enc, err := ws.accounts.TryGet(crypto.Keccak256(address[:]))
+	if err != nil {
+		return nil, ws.fail(fmt.Errorf("account %x: %w", address, wrapMissingNode(err)))
+	}
+	if enc == nil {
+		return nil, nil
+	}
+	acc := new(accounts.Account)
+	if err := acc.DecodeForHashing(enc); err != nil {
+		return nil, ws.fail(fmt.Errorf("account %x: %w", address, err))
+	}
+	return acc, nil
+}
+
+func (ws *witnessState) storageTrie(address libcommon.Address) (*trie.Trie, error) {
+	if t, ok := ws.storage[address]; ok {
+		return t, nil
+	}
+	acc, err := ws.readAccount(address)
+	if err != nil {
+		return nil, err
+	}
+	root := types.EmptyRootHash
+	if acc != nil {
+		root = acc.Root
+	}
+	t, err := trie.NewFromWitness(root, ws.nodes)
+	if err != nil {
+		return nil, ws.fail(fmt.Errorf("storage of %x: %w", address, err))
+	}
+	ws.storage[address] = t
+	return t, nil
+}
+
+func (ws *witnessState) ReadAccountData(address libcommon.Address) (*accounts.Account, error) {
+	return ws.readAccount(address)
+}
+
+func (ws *witnessState) ReadAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash) ([]byte, error) {
+	t, err := ws.storageTrie(address)
+	if err != nil {
+		return nil, err
+	}
+	enc, err := t.TryGet(crypto.Keccak256(key[:]))
+	if err != nil {
+		return nil, ws.fail(fmt.Errorf("storage %x %x: %w", address, *key, wrapMissingNode(err)))
+	}
+	if enc == nil {
+		return nil, nil
+	}
+	value, _, err := rlp.SplitString(enc)
+	if err != nil {
+		return nil, ws.fail(fmt.Errorf("storage %x %x: %w", address, *key, err))
+	}
+	return value, nil
+}
+
+func (ws *witnessState) ReadAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) ([]byte, error) {
+	if accounts.IsEmptyCodeHash(codeHash) {
+		return nil, nil
+	}
+	code, ok := ws.codes[codeHash]
+	if !ok {
+		return nil, ws.fail(fmt.Errorf("code %x of %x is missing in the witness", codeHash, address))
+	}
+	return code, nil
+}
+
+func (ws *witnessState) ReadAccountCodeSize(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (int, error) {
+	code, err := ws.ReadAccountCode(address, incarnation, codeHash)
+	return len(code), err
+}
+
+// ReadAccountIncarnation - incarnations are not a part of the trie, execution does not depend on them
+func (ws *witnessState) ReadAccountIncarnation(address libcommon.Address) (uint64, error) {
+	return 0, nil
+}
+
+func (ws *witnessState) UpdateAccountData(address libcommon.Address, original, account *accounts.Account) error {
+	ws.updated[address] = account.SelfCopy()
+	return nil
+}
+
+func (ws *witnessState) UpdateAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash, code []byte) error {
+	ws.codes[codeHash] = code
+	return nil
+}
+
+func (ws *witnessState) DeleteAccount(address libcommon.Address, original *accounts.Account) error {
+	ws.updated[address] = nil
+	ws.storage[address] = trie.NewTestRLPTrie(types.EmptyRootHash)
+	return nil
+}
+
+func (ws *witnessState) WriteAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash, original, value *uint256.Int) error {
+	t, err := ws.storageTrie(address)
+	if err != nil {
+		return err
+	}
+	hashedKey := crypto.Keccak256(key[:])
+	if value.IsZero() {
+		err = t.TryDelete(hashedKey)
+	} else {
+		var enc []byte
+		if enc, err = rlp.EncodeToBytes(value.Bytes()); err == nil {
+			err = t.TryUpdate(hashedKey, enc)
+		}
+	}
+	if err != nil {
+		return ws.fail(fmt.Errorf("storage %x %x: %w", address, *key, wrapMissingNode(err)))
+	}
+	// storage root changes even if account fields stay the same
+	ws.storageDirty[address] = struct{}{}
+	return nil
+}
+
+func (ws *witnessState) CreateContract(address libcommon.Address) error {
+	ws.storage[address] = trie.NewTestRLPTrie(types.EmptyRootHash)
+	return nil
+}
+
+func (ws *witnessState) WriteChangeSets() error { return nil }
+func (ws *witnessState) WriteHistory() error    { return nil }
+
+// Root applies account updates and returns the resulting state root
+func (ws *witnessState) Root() (libcommon.Hash, error) {
+	if ws.err != nil {
+		return libcommon.Hash{}, ws.err
+	}
+	for address := range ws.storageDirty {
+		if _, ok := ws.updated[address]; ok {
+			continue
+		}
+		acc, err := ws.readAccount(address)
+		if err != nil {
+			return libcommon.Hash{}, err
+		}
+		if acc != nil {
+			ws.updated[address] = acc
+		}
+	}
+	addresses := make([]libcommon.Address, 0, len(ws.updated))
+	for address := range ws.updated {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool { return bytes.Compare(addresses[i][:], addresses[j][:]) < 0 })
+
+	for _, address := range addresses {
+		hashedKey := crypto.Keccak256(address[:])
+		acc := ws.updated[address]
+		if acc == nil {
+			if err := ws.accounts.TryDelete(hashedKey); err != nil {
+				return libcommon.Hash{}, fmt.Errorf("account %x: %w", address, wrapMissingNode(err))
+			}
+			continue
+		}
+		t, err := ws.storageTrie(address)
+		if err != nil {
+			return libcommon.Hash{}, err
+		}
+		acc.Root = t.Hash()
+		enc := make([]byte, acc.EncodingLengthForHashing())
+		acc.EncodeForHashing(enc)
+		if err := ws.accounts.TryUpdate(hashedKey, enc); err != nil {
+			return libcommon.Hash{}, fmt.Errorf("account %x: %w", address, wrapMissingNode(err))
+		}
+	}
+	ws.updated = make(map[libcommon.Address]*accounts.Account)
+	ws.storageDirty = make(map[libcommon.Address]struct{})
+	return ws.accounts.Hash(), nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package stateless
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+// ExecuteBlock executes the block having nothing but the witness: pre-state comes from witness trie nodes checked
+// against the parent stateRoot, bytecodes and ancestor headers come from the witness as well.
+// Receipts, gas and bloom are checked by execution itself, the post-state root is checked against the block header.
+// Returns the post-state root.
+func ExecuteBlock(chainConfig *chain.Config, engine consensus.Engine, block *types.Block, witness *Witness, logger log.Logger) (libcommon.Hash, error) {
+	chainReader := newWitnessChain(chainConfig, witness.Headers)
+	parent := chainReader.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return libcommon.Hash{}, fmt.Errorf("parent header %x is missing in the witness", block.ParentHash())
+	}
+
+	ws, err := newWitnessState(parent.Root, witness)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	blockHashFunc := core.GetHashFn(block.HeaderNoCopy(), chainReader.GetHeader)
+	vmConfig := vm.Config{}
+	if _, err := core.ExecuteBlockEphemerally(chainConfig, &vmConfig, blockHashFunc, engine, block, ws, ws, chainReader, nil, logger); err != nil {
+		if ws.err != nil {
+			return libcommon.Hash{}, ws.err
+		}
+		return libcommon.Hash{}, err
+	}
+	root, err := ws.Root()
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	if root != block.Root() {
+		return root, fmt.Errorf("state root after execution %x, in header %x", root, block.Root())
+	}
+	return root, nil
+}
+
+// witnessChain is a consensus.ChainReader over the witness headers only
+type witnessChain struct {
+	config  *chain.Config
+	headers map[libcommon.Hash]*types.Header
+}
+
+var _ consensus.ChainReader = (*witnessChain)(nil)
+
+func newWitnessChain(config *chain.Config, headers []*types.Header) *witnessChain {
+	wc := &witnessChain{config: config, headers: make(map[libcommon.Hash]*types.Header, len(headers))}
+	for _, h := range headers {
+		wc.headers[h.Hash()] = h
+	}
+	return wc
+}
+
+func (wc *witnessChain) Config() *chain.Config                 { return wc.config }
+func (wc *witnessChain) CurrentHeader() *types.Header          { return nil }
+func (wc *witnessChain) CurrentFinalizedHeader() *types.Header { return nil }
+func (wc *witnessChain) CurrentSafeHeader() *types.Header      { return nil }
+
+func (wc *witnessChain) GetHeader(hash libcommon.Hash, number uint64) *types.Header {
+	h := wc.headers[hash]
+	if h == nil || h.Number.Uint64() != number {
+		return nil
+	}
+	return h
+}
+
+func (wc *witnessChain) GetHeaderByNumber(number uint64) *types.Header {
+	for _, h := range wc.headers {
+		if h.Number.Uint64() == number {
+			return h
+		}
+	}
+	return nil
+}
+
+func (wc *witnessChain) GetHeaderByHash(hash libcommon.Hash) *types.Header { return wc.headers[hash] }
+func (wc *witnessChain) GetTd(hash libcommon.Hash, number uint64) *big.Int { return nil }
+func (wc *witnessChain) FrozenBlocks() uint64                              { return 0 }
+func (wc *witnessChain) FrozenBorBlocks() uint64                           { return 0 }
+func (wc *witnessChain) BorSpan(spanId uint64) []byte                      { return nil }
+
+func (wc *witnessChain) GetBlock(hash libcommon.Hash, number uint64) *types.Block { return nil }
+func (wc *witnessChain) HasBlock(hash libcommon.Hash, number uint64) bool         { return false }
+
+func (wc *witnessChain) BorEventsByBlock(hash libcommon.Hash, number uint64) []rlp.RawValue {
+	return nil
+}
+func (wc *witnessChain) BorStartEventID(hash libcommon.Hash, number uint64) uint64 { return 0 }
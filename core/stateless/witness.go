@@ -0,0 +1,132 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+// Package stateless provides block execution witnesses: the subset of state, bytecodes and headers
+// which is enough to execute a block without the database, and execution of blocks out of them.
+package stateless
+
+import (
+	"bytes"
+	"sort"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+)
+
+// Witness holds everything needed to execute a block without the database
+type Witness struct {
+	// Headers are the parent header and ancestors accessed by BLOCKHASH
+	Headers []*types.Header `json:"headers"`
+	// Codes are bytecodes of the contracts executed by the block
+	Codes []hexutility.Bytes `json:"codes"`
+	// State is a set of RLP encoded pre-state trie nodes (account and storage tries) for all touched keys
+	State []hexutility.Bytes `json:"state"`
+	// Keys are touched addresses and address+location storage keys
+	Keys []hexutility.Bytes `json:"keys"`
+}
+
+// Recorder is a state.StateReader which records all accessed accounts, storage slots and bytecodes
+// of the underlying reader, as a base for the witness.
+type Recorder struct {
+	reader   state.StateReader
+	accounts map[libcommon.Address]map[libcommon.Hash]struct{}
+	codes    map[libcommon.Hash][]byte
+}
+
+var _ state.StateReader = (*Recorder)(nil)
+
+func NewRecorder(reader state.StateReader) *Recorder {
+	return &Recorder{
+		reader:   reader,
+		accounts: make(map[libcommon.Address]map[libcommon.Hash]struct{}),
+		codes:    make(map[libcommon.Hash][]byte),
+	}
+}
+
+func (r *Recorder) touch(address libcommon.Address) map[libcommon.Hash]struct{} {
+	slots, ok := r.accounts[address]
+	if !ok {
+		slots = make(map[libcommon.Hash]struct{})
+		r.accounts[address] = slots
+	}
+	return slots
+}
+
+func (r *Recorder) ReadAccountData(address libcommon.Address) (*accounts.Account, error) {
+	r.touch(address)
+	return r.reader.ReadAccountData(address)
+}
+
+func (r *Recorder) ReadAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash) ([]byte, error) {
+	r.touch(address)[*key] = struct{}{}
+	return r.reader.ReadAccountStorage(address, incarnation, key)
+}
+
+func (r *Recorder) ReadAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) ([]byte, error) {
+	r.touch(address)
+	code, err := r.reader.ReadAccountCode(address, incarnation, codeHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) > 0 {
+		r.codes[codeHash] = code
+	}
+	return code, nil
+}
+
+// ReadAccountCodeSize reads the whole code, since witness consumer has to compute the size out of it
+func (r *Recorder) ReadAccountCodeSize(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (int, error) {
+	code, err := r.ReadAccountCode(address, incarnation, codeHash)
+	return len(code), err
+}
+
+func (r *Recorder) ReadAccountIncarnation(address libcommon.Address) (uint64, error) {
+	r.touch(address)
+	return r.reader.ReadAccountIncarnation(address)
+}
+
+// PlainKeys returns sorted accessed addresses and address+location storage keys
+func (r *Recorder) PlainKeys() [][]byte {
+	var keys [][]byte
+	for address, slots := range r.accounts {
+		keys = append(keys, libcommon.Copy(address[:]))
+		for slot := range slots {
+			key := make([]byte, 0, length.Addr+length.Hash)
+			keys = append(keys, append(append(key, address[:]...), slot[:]...))
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+	return keys
+}
+
+// Codes returns accessed bytecodes sorted by code hash
+func (r *Recorder) Codes() [][]byte {
+	hashes := make([]libcommon.Hash, 0, len(r.codes))
+	for h := range r.codes {
+		hashes = append(hashes, h)
+	}
+	sort.Slice(hashes, func(i, j int) bool { return bytes.Compare(hashes[i][:], hashes[j][:]) < 0 })
+	codes := make([][]byte, len(hashes))
+	for i, h := range hashes {
+		codes[i] = r.codes[h]
+	}
+	return codes
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"math/big"
+
+	"github.com/holiman/uint256"
+)
+
+// SupplyDelta is the change of the ether supply caused by a single block,
+// split by the source of issuance and burn.
+type SupplyDelta struct {
+	GenesisAlloc uint256.Int // ether allocated by the genesis block
+	Reward       uint256.Int // block and uncle rewards
+	Withdrawals  uint256.Int // ether withdrawn from the beacon chain
+	EIP1559Burn  uint256.Int // base fee burnt as per EIP-1559
+	BlobBurn     uint256.Int // blob base fee burnt as per EIP-4844
+	MiscBurn     uint256.Int // ether destroyed by self-destructs
+}
+
+// Issuance returns the total amount of ether created by the block.
+func (d *SupplyDelta) Issuance() *uint256.Int {
+	issuance := new(uint256.Int).Add(&d.GenesisAlloc, &d.Reward)
+	return issuance.Add(issuance, &d.Withdrawals)
+}
+
+// Burn returns the total amount of ether destroyed by the block.
+func (d *SupplyDelta) Burn() *uint256.Int {
+	burn := new(uint256.Int).Add(&d.EIP1559Burn, &d.BlobBurn)
+	return burn.Add(burn, &d.MiscBurn)
+}
+
+// Delta returns the net supply change of the block, which may be negative.
+func (d *SupplyDelta) Delta() *big.Int {
+	return new(big.Int).Sub(d.Issuance().ToBig(), d.Burn().ToBig())
+}
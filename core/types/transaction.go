@@ -490,6 +490,9 @@ func (m Message) MaxFeePerBlobGas() *uint256.Int {
 }
 
 func (m Message) BlobHashes() []libcommon.Hash { return m.blobHashes }
+func (m *Message) SetBlobHashes(blobHashes []libcommon.Hash) {
+	m.blobHashes = blobHashes
+}
 
 func DecodeSSZ(data []byte, dest codec.Deserializable) error {
 	err := dest.Deserialize(codec.NewDecodingReader(bytes.NewReader(data), uint64(len(data))))
@@ -207,6 +207,27 @@ func ActivePrecompiles(rules *chain.Rules) []libcommon.Address {
 	}
 }
 
+// ActivePrecompiledContracts returns the precompiled contracts enabled with the current configuration.
+// The returned map is shared and must not be modified.
+func ActivePrecompiledContracts(rules *chain.Rules) map[libcommon.Address]PrecompiledContract {
+	switch {
+	case rules.IsPrague:
+		return PrecompiledContractsPrague
+	case rules.IsNapoli:
+		return PrecompiledContractsNapoli
+	case rules.IsCancun:
+		return PrecompiledContractsCancun
+	case rules.IsBerlin:
+		return PrecompiledContractsBerlin
+	case rules.IsIstanbul:
+		return PrecompiledContractsIstanbul
+	case rules.IsByzantium:
+		return PrecompiledContractsByzantium
+	default:
+		return PrecompiledContractsHomestead
+	}
+}
+
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
 // It returns
 // - the returned bytes,
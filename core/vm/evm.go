@@ -39,22 +39,9 @@ import (
 var emptyCodeHash = crypto.Keccak256Hash(nil)
 
 func (evm *EVM) precompile(addr libcommon.Address) (PrecompiledContract, bool) {
-	var precompiles map[libcommon.Address]PrecompiledContract
-	switch {
-	case evm.chainRules.IsPrague:
-		precompiles = PrecompiledContractsPrague
-	case evm.chainRules.IsNapoli:
-		precompiles = PrecompiledContractsNapoli
-	case evm.chainRules.IsCancun:
-		precompiles = PrecompiledContractsCancun
-	case evm.chainRules.IsBerlin:
-		precompiles = PrecompiledContractsBerlin
-	case evm.chainRules.IsIstanbul:
-		precompiles = PrecompiledContractsIstanbul
-	case evm.chainRules.IsByzantium:
-		precompiles = PrecompiledContractsByzantium
-	default:
-		precompiles = PrecompiledContractsHomestead
+	precompiles := evm.precompiles
+	if precompiles == nil {
+		precompiles = ActivePrecompiledContracts(evm.chainRules)
 	}
 	p, ok := precompiles[addr]
 	return p, ok
@@ -98,6 +85,9 @@ type EVM struct {
 	// available gas is calculated in gasCall* according to the 63/64 rule and later
 	// applied in opCall*.
 	callGasTemp uint64
+	// precompiles, when set, replaces the fork-defined set of precompiled contracts
+	// (used by call simulation to move precompiles to other addresses)
+	precompiles map[libcommon.Address]PrecompiledContract
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -123,6 +113,12 @@ func NewEVM(blockCtx evmtypes.BlockContext, txCtx evmtypes.TxContext, state evmt
 	return evm
 }
 
+// SetPrecompiles overrides the set of precompiled contracts available to the EVM.
+// Passing nil restores the set defined by the chain rules.
+func (evm *EVM) SetPrecompiles(precompiles map[libcommon.Address]PrecompiledContract) {
+	evm.precompiles = precompiles
+}
+
 // Reset resets the EVM with a new transaction context.Reset
 // This is not threadsafe and should only be done very cautiously.
 func (evm *EVM) Reset(txCtx evmtypes.TxContext, ibs evmtypes.IntraBlockState) {
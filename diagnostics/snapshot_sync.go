@@ -57,6 +57,12 @@ func SetupStagesAccess(metricsMux *http.ServeMux, diag *diaglib.DiagnosticClient
 		writeNetworkSpeed(w, diag)
 	})
 
+	metricsMux.HandleFunc("/snapshot-scrub", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		writeSnapshotScrub(w, diag)
+	})
+
 	metricsMux.HandleFunc("/sync-stages", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Content-Type", "application/json")
@@ -87,3 +93,7 @@ func writeHardwareInfo(w http.ResponseWriter, diag *diaglib.DiagnosticClient) {
 func writeSyncStages(w http.ResponseWriter, diag *diaglib.DiagnosticClient) {
 	diag.SyncStagesJson(w)
 }
+
+func writeSnapshotScrub(w http.ResponseWriter, diag *diaglib.DiagnosticClient) {
+	diag.SnapshotScrubJson(w)
+}
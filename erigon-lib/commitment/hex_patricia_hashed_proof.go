@@ -0,0 +1,408 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package commitment
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/rlp"
+)
+
+// ProofNodes is a list of RLP encoded trie nodes on the path from the trie root towards a key.
+// Nodes embedded into their parent (shorter than 32 bytes) are not listed, the root node always is.
+type ProofNodes [][]byte
+
+// StateProof is a Merkle proof of an account and some of its storage slots, as served by eth_getProof.
+type StateProof struct {
+	RootHash      []byte
+	AccountProof  ProofNodes
+	StorageRoot   []byte       // storage trie root of the account, EmptyRootHash if account has no storage
+	StorageProofs []ProofNodes // proofs for requested storage keys, in the same order
+}
+
+// GenerateProof builds Merkle proofs for the account and storage keys out of the branches available
+// via PatriciaContext. Trie state is expected to be set up with SetState beforehand, processing state (grid)
+// is not used nor modified. Every node of the proof is checked against the reference kept by its parent, so
+// inconsistent branch data results in error rather than invalid proof.
+// Storage plain keys are expected to be prefixed with the account plain key, as the rest of the trie expects.
+func (hph *HexPatriciaHashed) GenerateProof(accountPlainKey []byte, storagePlainKeys [][]byte) (*StateProof, error) {
+	return hph.generateProof(accountPlainKey, storagePlainKeys, false)
+}
+
+// GenerateWitness collects trie nodes needed to read, update or delete given plain keys (accounts and storage)
+// without the rest of the trie and to recompute the root afterwards. Along with proof nodes it includes
+// siblings of two-children branches on the paths, since deletion collapses such branch into the remaining child.
+// Returned nodes are unique, the order is not specified.
+func (hph *HexPatriciaHashed) GenerateWitness(plainKeys [][]byte) (rootHash []byte, nodes ProofNodes, err error) {
+	var accountKeys []string
+	storageKeys := make(map[string][][]byte)
+	for _, plainKey := range plainKeys {
+		if len(plainKey) < hph.accountKeyLen {
+			return nil, nil, fmt.Errorf("GenerateWitness: unexpected key %x", plainKey)
+		}
+		account := string(plainKey[:hph.accountKeyLen])
+		if _, ok := storageKeys[account]; !ok {
+			accountKeys = append(accountKeys, account)
+			storageKeys[account] = nil
+		}
+		if len(plainKey) > hph.accountKeyLen {
+			storageKeys[account] = append(storageKeys[account], plainKey)
+		}
+	}
+
+	seen := make(map[string]struct{})
+	add := func(proof ProofNodes) {
+		for _, node := range proof {
+			if _, ok := seen[string(node)]; !ok {
+				seen[string(node)] = struct{}{}
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	for _, account := range accountKeys {
+		proof, err := hph.generateProof([]byte(account), storageKeys[account], true)
+		if err != nil {
+			return nil, nil, err
+		}
+		rootHash = proof.RootHash
+		add(proof.AccountProof)
+		for _, storageProof := range proof.StorageProofs {
+			add(storageProof)
+		}
+	}
+	if rootHash == nil {
+		root := hph.root
+		rootRef, err := hph.computeCellHash(&root, 0, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		rootHash = common.Copy(rootRef[1:])
+	}
+	return rootHash, nodes, nil
+}
+
+func (hph *HexPatriciaHashed) generateProof(accountPlainKey []byte, storagePlainKeys [][]byte, siblings bool) (*StateProof, error) {
+	if len(accountPlainKey) != hph.accountKeyLen {
+		return nil, fmt.Errorf("GenerateProof: account key %x length %d, expected %d", accountPlainKey, len(accountPlainKey), hph.accountKeyLen)
+	}
+	root := hph.root
+	rootRef, err := hph.computeCellHash(&root, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	proof := &StateProof{
+		RootHash:      common.Copy(rootRef[1:]),
+		StorageRoot:   common.Copy(EmptyRootHash),
+		StorageProofs: make([]ProofNodes, len(storagePlainKeys)),
+	}
+
+	key := make([]byte, 128)
+	if err := hashKey(hph.keccak, accountPlainKey, key, 0); err != nil {
+		return nil, err
+	}
+	root = hph.root
+	var account *Cell
+	if root.apl > 0 || root.extLen > 0 || root.hl > 0 {
+		proof.AccountProof, account, err = hph.proofPath(&root, 0, key[:64], common.Copy(rootRef), false, siblings)
+		if err != nil {
+			return nil, fmt.Errorf("account %x proof: %w", accountPlainKey, err)
+		}
+	}
+	if account == nil {
+		return proof, nil
+	}
+
+	storageRoot, err := hph.cellStorageRoot(account)
+	if err != nil {
+		return nil, err
+	}
+	proof.StorageRoot = common.Copy(storageRoot[:])
+	if bytes.Equal(storageRoot[:], EmptyRootHash) {
+		return proof, nil
+	}
+	for i, storagePlainKey := range storagePlainKeys {
+		if len(storagePlainKey) <= hph.accountKeyLen || !bytes.HasPrefix(storagePlainKey, accountPlainKey) {
+			return nil, fmt.Errorf("GenerateProof: storage key %x does not belong to account %x", storagePlainKey, accountPlainKey)
+		}
+		if err := hashKey(hph.keccak, storagePlainKey[hph.accountKeyLen:], key[64:], 0); err != nil {
+			return nil, err
+		}
+		// storage trie hangs off the account cell, walk it as it would be a cell at depth 64 holding storage root
+		storage := *account
+		storage.apl = 0
+		ref := append([]byte{0x80 + length.Hash}, storageRoot[:]...)
+		if proof.StorageProofs[i], _, err = hph.proofPath(&storage, 64, key, ref, true, siblings); err != nil {
+			return nil, fmt.Errorf("storage %x proof: %w", storagePlainKey, err)
+		}
+	}
+	return proof, nil
+}
+
+// proofPath walks down from the cell at given depth following hashedKey and collects nodes on the way.
+// ref is the reference (hash with prefix, or embedded node) which parent node keeps for the cell.
+// Returns the leaf cell if key is present in the trie, nil otherwise (proof of absence).
+// With siblings set, the other child of every two-children branch on the path is collected as well.
+func (hph *HexPatriciaHashed) proofPath(cell *Cell, depth int, hashedKey []byte, ref []byte, storage, siblings bool) (ProofNodes, *Cell, error) {
+	var nodes ProofNodes
+	appendNode := func(node []byte) error {
+		// first node on the path is a trie root, it is always referenced by hash
+		if !bytes.Equal(hph.nodeRef(node, len(nodes) == 0), ref) {
+			return fmt.Errorf("node %x at depth %d does not match parent reference %x", node, depth, ref)
+		}
+		if len(nodes) == 0 || len(node) >= length.Hash {
+			nodes = append(nodes, node)
+		}
+		return nil
+	}
+	for {
+		if (storage && cell.spl > 0) || (!storage && cell.apl > 0) {
+			node, leafKey, err := hph.proofLeafNode(cell, depth, hashedKey, storage)
+			if err != nil {
+				return nil, nil, err
+			}
+			if err = appendNode(node); err != nil {
+				return nil, nil, err
+			}
+			if !bytes.Equal(leafKey, hashedKey) {
+				return nodes, nil, nil
+			}
+			return nodes, cell, nil
+		}
+		if cell.hl == 0 {
+			return nodes, nil, nil
+		}
+		if cell.extLen > 0 {
+			if err := appendNode(proofExtensionNode(cell.extension[:cell.extLen], cell.h[:cell.hl])); err != nil {
+				return nil, nil, err
+			}
+			if !bytes.HasPrefix(hashedKey[depth:], cell.extension[:cell.extLen]) {
+				return nodes, nil, nil
+			}
+			depth += cell.extLen
+			ref = append([]byte{0x80 + length.Hash}, cell.h[:cell.hl]...)
+		}
+		node, children, refs, err := hph.proofBranchNode(hashedKey[:depth])
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = appendNode(node); err != nil {
+			return nil, nil, err
+		}
+		if depth == len(hashedKey) {
+			return nil, nil, fmt.Errorf("branch node at the end of key %x", hashedKey)
+		}
+		nibble := hashedKey[depth]
+		if refs[nibble] == nil {
+			return nodes, nil, nil
+		}
+		if siblings {
+			if nodes, err = hph.proofSibling(nodes, children, refs, hashedKey, depth, storage); err != nil {
+				return nil, nil, err
+			}
+		}
+		cell, ref = &children[nibble], refs[nibble]
+		depth++
+	}
+}
+
+// proofSibling appends the node of the only sibling of hashedKey[depth] child, if the branch has two children
+// and the sibling is referenced by hash. Embedded siblings are already a part of the branch node.
+func (hph *HexPatriciaHashed) proofSibling(nodes ProofNodes, children *[16]Cell, refs [16][]byte, hashedKey []byte, depth int, storage bool) (ProofNodes, error) {
+	sibling := -1
+	for i := range refs {
+		if refs[i] == nil || byte(i) == hashedKey[depth] {
+			continue
+		}
+		if sibling >= 0 {
+			return nodes, nil
+		}
+		sibling = i
+	}
+	if sibling < 0 || len(refs[sibling]) != 1+length.Hash {
+		return nodes, nil
+	}
+	cell := &children[sibling]
+	var node []byte
+	var err error
+	switch {
+	case (storage && cell.spl > 0) || (!storage && cell.apl > 0):
+		node, _, err = hph.proofLeafNode(cell, depth+1, hashedKey, storage)
+	case cell.extLen > 0:
+		node = proofExtensionNode(cell.extension[:cell.extLen], cell.h[:cell.hl])
+	default:
+		prefix := append(common.Copy(hashedKey[:depth]), byte(sibling))
+		node, _, _, err = hph.proofBranchNode(prefix)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(hph.nodeRef(node, false), refs[sibling]) {
+		return nil, fmt.Errorf("sibling node %x at depth %d does not match reference %x", node, depth+1, refs[sibling])
+	}
+	return append(nodes, node), nil
+}
+
+// proofBranchNode reads branch at given prefix and encodes it as a trie node.
+// Returns filled child cells and their references as well.
+func (hph *HexPatriciaHashed) proofBranchNode(prefix []byte) ([]byte, *[16]Cell, [16][]byte, error) {
+	var refs [16][]byte
+	key := hexToCompact(prefix)
+	if len(key) == 0 {
+		key = temporalReplacementForEmpty
+	}
+	branchData, _, err := hph.ctx.GetBranch(key)
+	if err != nil {
+		return nil, nil, refs, err
+	}
+	if len(branchData) < 4 {
+		return nil, nil, refs, fmt.Errorf("branch not found for prefix %x", prefix)
+	}
+	branchData = branchData[2:] // skip touch map
+	bitmap := binary.BigEndian.Uint16(branchData[0:])
+	pos := 2
+
+	cells := new([16]Cell)
+	depth := len(prefix) + 1
+	totalLen := 17 - bits.OnesCount16(bitmap)
+	for bitset := bitmap; bitset != 0; {
+		bit := bitset & -bitset
+		nibble := bits.TrailingZeros16(bit)
+		cell := &cells[nibble]
+		cell.reset()
+		fieldBits := branchData[pos]
+		pos++
+		if pos, err = cell.fillFromFields(branchData, pos, PartFlags(fieldBits)); err != nil {
+			return nil, nil, refs, fmt.Errorf("prefix [%x], branchData[%x]: %w", prefix, branchData, err)
+		}
+		if cell.apl > 0 {
+			if err = hph.ctx.GetAccount(cell.apk[:cell.apl], cell); err != nil {
+				return nil, nil, refs, fmt.Errorf("proofBranchNode GetAccount: %w", err)
+			}
+		}
+		if cell.spl > 0 {
+			if err = hph.ctx.GetStorage(cell.spk[:cell.spl], cell); err != nil {
+				return nil, nil, refs, fmt.Errorf("proofBranchNode GetStorage: %w", err)
+			}
+		}
+		ref, err := hph.computeCellHash(cell, depth, nil)
+		if err != nil {
+			return nil, nil, refs, err
+		}
+		refs[nibble] = common.Copy(ref)
+		totalLen += len(ref)
+		bitset ^= bit
+	}
+
+	node := make([]byte, rlp.ListPrefixLen(totalLen)+totalLen, rlp.ListPrefixLen(totalLen)+totalLen+9)
+	pos = rlp.EncodeListPrefix(totalLen, node[:cap(node)])
+	for i := 0; i < 16; i++ {
+		if refs[i] == nil {
+			node[pos] = 0x80
+			pos++
+			continue
+		}
+		pos += copy(node[pos:], refs[i])
+	}
+	node[pos] = 0x80 // value
+	return node, cells, refs, nil
+}
+
+// proofLeafNode encodes account or storage leaf and returns it along with the full hashed key of the leaf,
+// which may differ from the requested one.
+func (hph *HexPatriciaHashed) proofLeafNode(cell *Cell, depth int, hashedKey []byte, storage bool) ([]byte, []byte, error) {
+	leafKey := make([]byte, len(hashedKey), len(hashedKey)+1)
+	var value []byte
+	if storage {
+		copy(leafKey, hashedKey[:64])
+		if err := hashKey(hph.keccak, cell.spk[hph.accountKeyLen:cell.spl], leafKey[64:], 0); err != nil {
+			return nil, nil, err
+		}
+		value = make([]byte, rlp.StringLen(cell.Storage[:cell.StorageLen]))
+		rlp.EncodeString(cell.Storage[:cell.StorageLen], value)
+	} else {
+		if err := hashKey(hph.keccak, cell.apk[:cell.apl], leafKey, 0); err != nil {
+			return nil, nil, err
+		}
+		storageRoot, err := hph.cellStorageRoot(cell)
+		if err != nil {
+			return nil, nil, err
+		}
+		var valBuf [128]byte
+		value = common.Copy(valBuf[:cell.accountForHashing(valBuf[:], storageRoot)])
+	}
+	compactKey := hexToCompact(append(common.Copy(leafKey[depth:]), 16))
+
+	totalLen := rlp.StringLen(compactKey) + rlp.StringLen(value)
+	node := make([]byte, rlp.ListPrefixLen(totalLen)+totalLen+9)
+	pos := rlp.EncodeListPrefix(totalLen, node)
+	pos += rlp.EncodeString(compactKey, node[pos:])
+	pos += rlp.EncodeString(value, node[pos:])
+	return node[:pos], leafKey, nil
+}
+
+func proofExtensionNode(extension, hash []byte) []byte {
+	compactKey := hexToCompact(extension)
+	totalLen := rlp.StringLen(compactKey) + 1 + len(hash)
+	node := make([]byte, rlp.ListPrefixLen(totalLen)+totalLen+9)
+	pos := rlp.EncodeListPrefix(totalLen, node)
+	pos += rlp.EncodeString(compactKey, node[pos:])
+	pos += rlp.EncodeString(hash, node[pos:])
+	return node[:pos]
+}
+
+// cellStorageRoot computes storage root of the account cell the same way computeCellHash does
+func (hph *HexPatriciaHashed) cellStorageRoot(cell *Cell) (storageRoot [length.Hash]byte, err error) {
+	switch {
+	case cell.spl > 0:
+		var hashedKey [65]byte
+		if err = hashKey(hph.keccak, cell.spk[hph.accountKeyLen:cell.spl], hashedKey[:], 0); err != nil {
+			return storageRoot, err
+		}
+		hashedKey[64] = 16
+		aux, err := hph.leafHashWithKeyVal(make([]byte, 0, 33), hashedKey[:], cell.Storage[:cell.StorageLen], true)
+		if err != nil {
+			return storageRoot, err
+		}
+		return *(*[length.Hash]byte)(aux[1:]), nil
+	case cell.extLen > 0:
+		if cell.hl == 0 {
+			return storageRoot, fmt.Errorf("cellStorageRoot extension without hash")
+		}
+		return hph.extensionHash(cell.extension[:cell.extLen], cell.h[:cell.hl])
+	case cell.hl > 0:
+		return cell.h, nil
+	}
+	return *(*[length.Hash]byte)(EmptyRootHash), nil
+}
+
+// nodeRef returns how the node is referenced from its parent: hash of the node or the node itself if it is short
+func (hph *HexPatriciaHashed) nodeRef(node []byte, forceHash bool) []byte {
+	if !forceHash && len(node) < length.Hash {
+		return node
+	}
+	ref := make([]byte, 1+length.Hash)
+	ref[0] = 0x80 + length.Hash
+	hph.keccak.Reset()
+	hph.keccak.Write(node)
+	hph.keccak.Read(ref[1:])
+	return ref
+}
@@ -0,0 +1,285 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package commitment
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/rlp"
+)
+
+// verifyProofNodes walks the proof from the root following hashedKey and returns the leaf value, nil if key is absent
+func verifyProofNodes(rootHash, hashedKey []byte, nodes ProofNodes) ([]byte, error) {
+	keccak := sha3.NewLegacyKeccak256()
+	ref := append([]byte{0x80 + length.Hash}, rootHash...)
+	used := 0
+	for {
+		var node []byte
+		if len(ref) == 1+length.Hash && ref[0] == 0x80+length.Hash {
+			if used == len(nodes) {
+				return nil, fmt.Errorf("missing node %x", ref[1:])
+			}
+			node = nodes[used]
+			used++
+			keccak.Reset()
+			keccak.Write(node)
+			if h := keccak.Sum(nil); !bytes.Equal(h, ref[1:]) {
+				return nil, fmt.Errorf("node %d hash %x, expected %x", used-1, h, ref[1:])
+			}
+		} else {
+			node = ref
+		}
+		listPos, listLen, err := rlp.List(node, 0)
+		if err != nil {
+			return nil, err
+		}
+		var items [][]byte
+		for pos := listPos; pos < listPos+listLen; {
+			dataPos, dataLen, _, err := rlp.Prefix(node, pos)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, node[pos:dataPos+dataLen])
+			pos = dataPos + dataLen
+		}
+		switch len(items) {
+		case 17:
+			if len(hashedKey) == 0 {
+				return nil, fmt.Errorf("branch at the end of key")
+			}
+			ref, hashedKey = items[hashedKey[0]], hashedKey[1:]
+			if len(ref) == 1 && ref[0] == 0x80 {
+				return nil, checkAllUsed(used, nodes)
+			}
+		case 2:
+			dataPos, dataLen, err := rlp.String(items[0], 0)
+			if err != nil {
+				return nil, err
+			}
+			compact := items[0][dataPos : dataPos+dataLen]
+			var path []byte
+			if compact[0]&0x10 != 0 {
+				path = append(path, compact[0]&0xf)
+			}
+			for _, b := range compact[1:] {
+				path = append(path, b>>4, b&0xf)
+			}
+			if !bytes.HasPrefix(hashedKey, path) {
+				return nil, checkAllUsed(used, nodes)
+			}
+			hashedKey = hashedKey[len(path):]
+			if compact[0]&0x20 == 0 {
+				ref = items[1]
+				continue
+			}
+			if len(hashedKey) != 0 {
+				return nil, checkAllUsed(used, nodes)
+			}
+			dataPos, dataLen, err = rlp.String(items[1], 0)
+			if err != nil {
+				return nil, err
+			}
+			return items[1][dataPos : dataPos+dataLen], checkAllUsed(used, nodes)
+		default:
+			return nil, fmt.Errorf("unexpected node %x", node)
+		}
+	}
+}
+
+func checkAllUsed(used int, nodes ProofNodes) error {
+	if used != len(nodes) {
+		return fmt.Errorf("%d of %d proof nodes used", used, len(nodes))
+	}
+	return nil
+}
+
+func Test_HexPatriciaHashed_GenerateProof(t *testing.T) {
+	ctx := context.Background()
+	ms := NewMockState(t)
+	hph := NewHexPatriciaHashed(length.Addr, ms, ms.TempDir())
+
+	rnd := rand.New(rand.NewSource(42))
+	randomHex := func(n int) string {
+		b := make([]byte, n)
+		rnd.Read(b)
+		return hex.EncodeToString(b)
+	}
+	builder := NewUpdateBuilder()
+	var addrs []string
+	for i := 0; i < 300; i++ {
+		addr := randomHex(length.Addr)
+		addrs = append(addrs, addr)
+		builder.Balance(addr, rnd.Uint64()).Nonce(addr, uint64(i))
+	}
+	// account with big storage, account with single slot (singleton storage leaf) and one with short values
+	var slots []string
+	for i := 0; i < 200; i++ {
+		slot := randomHex(length.Hash)
+		slots = append(slots, slot)
+		builder.Storage(addrs[0], slot, randomHex(1+rnd.Intn(length.Hash)))
+	}
+	builder.Storage(addrs[1], slots[0], "05")
+	for i := 0; i < 3; i++ {
+		builder.Storage(addrs[2], fmt.Sprintf("%064x", i), "01")
+	}
+	plainKeys, updates := builder.Build()
+	require.NoError(t, ms.applyPlainUpdates(plainKeys, updates))
+	rootHash, err := hph.ProcessUpdates(ctx, plainKeys, updates)
+	require.NoError(t, err)
+
+	// proofs are generated from the restored trie state, as it happens for historical blocks
+	state, err := hph.EncodeCurrentState(nil)
+	require.NoError(t, err)
+	restored := NewHexPatriciaHashed(length.Addr, ms, ms.TempDir())
+	require.NoError(t, restored.SetState(state))
+
+	checkAccount := func(t *testing.T, addr string, slots []string) *StateProof {
+		t.Helper()
+		plainKey := decodeHex(addr)
+		storageKeys := make([][]byte, len(slots))
+		for i, slot := range slots {
+			storageKeys[i] = append(decodeHex(addr), decodeHex(slot)...)
+		}
+		proof, err := restored.GenerateProof(plainKey, storageKeys)
+		require.NoError(t, err)
+		require.EqualValues(t, rootHash, proof.RootHash)
+
+		hashedKey := make([]byte, 128)
+		require.NoError(t, hashKey(restored.keccak, plainKey, hashedKey, 0))
+		value, err := verifyProofNodes(proof.RootHash, hashedKey[:64], proof.AccountProof)
+		require.NoError(t, err)
+		if _, ok := ms.sm[string(plainKey)]; !ok {
+			require.Nil(t, value)
+			return proof
+		}
+		var account Cell
+		account.reset()
+		require.NoError(t, ms.GetAccount(plainKey, &account))
+		var valBuf [128]byte
+		require.EqualValues(t, valBuf[:account.accountForHashing(valBuf[:], [length.Hash]byte(proof.StorageRoot))], value)
+
+		for i, storageKey := range storageKeys {
+			require.NoError(t, hashKey(restored.keccak, storageKey[length.Addr:], hashedKey, 0))
+			if bytes.Equal(proof.StorageRoot, EmptyRootHash) {
+				require.Empty(t, proof.StorageProofs[i])
+				continue
+			}
+			value, err := verifyProofNodes(proof.StorageRoot, hashedKey[:64], proof.StorageProofs[i])
+			require.NoError(t, err)
+			stored, ok := ms.sm[string(storageKey)]
+			if !ok {
+				require.Nil(t, value)
+				continue
+			}
+			var upd Update
+			_, err = upd.Decode(stored, 0)
+			require.NoError(t, err)
+			expected := make([]byte, rlp.StringLen(upd.CodeHashOrStorage[:upd.ValLength]))
+			rlp.EncodeString(upd.CodeHashOrStorage[:upd.ValLength], expected)
+			require.EqualValues(t, expected, value)
+		}
+		return proof
+	}
+
+	t.Run("accounts", func(t *testing.T) {
+		for _, addr := range addrs {
+			checkAccount(t, addr, nil)
+		}
+	})
+	t.Run("missing account", func(t *testing.T) {
+		proof := checkAccount(t, randomHex(length.Addr), []string{slots[0]})
+		require.EqualValues(t, EmptyRootHash, proof.StorageRoot)
+	})
+	t.Run("storage", func(t *testing.T) {
+		checkAccount(t, addrs[0], append(slots, randomHex(length.Hash), randomHex(length.Hash)))
+	})
+	t.Run("singleton storage", func(t *testing.T) {
+		checkAccount(t, addrs[1], []string{slots[0], slots[1]})
+	})
+	t.Run("embedded storage leaves", func(t *testing.T) {
+		checkAccount(t, addrs[2], []string{fmt.Sprintf("%064x", 0), fmt.Sprintf("%064x", 2), fmt.Sprintf("%064x", 7)})
+	})
+	t.Run("no storage", func(t *testing.T) {
+		proof := checkAccount(t, addrs[3], []string{slots[0]})
+		require.EqualValues(t, EmptyRootHash, proof.StorageRoot)
+	})
+	t.Run("foreign storage key", func(t *testing.T) {
+		_, err := restored.GenerateProof(decodeHex(addrs[0]), [][]byte{append(decodeHex(addrs[1]), decodeHex(slots[0])...)})
+		require.Error(t, err)
+	})
+}
+
+func Test_HexPatriciaHashed_GenerateWitness(t *testing.T) {
+	ctx := context.Background()
+	ms := NewMockState(t)
+	hph := NewHexPatriciaHashed(length.Addr, ms, ms.TempDir())
+
+	rnd := rand.New(rand.NewSource(7))
+	randomHex := func(n int) string {
+		b := make([]byte, n)
+		rnd.Read(b)
+		return hex.EncodeToString(b)
+	}
+	builder := NewUpdateBuilder()
+	var addrs []string
+	for i := 0; i < 100; i++ {
+		addr := randomHex(length.Addr)
+		addrs = append(addrs, addr)
+		builder.Balance(addr, rnd.Uint64())
+	}
+	var slots []string
+	for i := 0; i < 50; i++ {
+		slot := randomHex(length.Hash)
+		slots = append(slots, slot)
+		builder.Storage(addrs[0], slot, randomHex(length.Hash))
+	}
+	plainKeys, updates := builder.Build()
+	require.NoError(t, ms.applyPlainUpdates(plainKeys, updates))
+	rootHash, err := hph.ProcessUpdates(ctx, plainKeys, updates)
+	require.NoError(t, err)
+
+	witnessKeys := [][]byte{decodeHex(addrs[1]), decodeHex(randomHex(length.Addr))}
+	for _, slot := range slots[:5] {
+		witnessKeys = append(witnessKeys, append(decodeHex(addrs[0]), decodeHex(slot)...))
+	}
+	witnessRoot, nodes, err := hph.GenerateWitness(witnessKeys)
+	require.NoError(t, err)
+	require.EqualValues(t, rootHash, witnessRoot)
+
+	witness := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		_, dup := witness[string(node)]
+		require.False(t, dup, "duplicate node %x", node)
+		witness[string(node)] = struct{}{}
+	}
+	proof, err := hph.GenerateProof(decodeHex(addrs[0]), witnessKeys[2:])
+	require.NoError(t, err)
+	for _, proofNodes := range append(proof.StorageProofs, proof.AccountProof) {
+		for _, node := range proofNodes {
+			require.Contains(t, witness, string(node))
+		}
+	}
+}
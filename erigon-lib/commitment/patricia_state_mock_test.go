@@ -142,8 +142,8 @@ func (ms *MockState) GetStorage(plainKey []byte, cell *Cell) error {
 		return nil
 	}
 	if ex.Flags&StorageUpdate != 0 {
-		copy(cell.Storage[:], ex.CodeHashOrStorage[:])
-		cell.StorageLen = len(ex.CodeHashOrStorage)
+		copy(cell.Storage[:], ex.CodeHashOrStorage[:ex.ValLength])
+		cell.StorageLen = ex.ValLength
 	} else {
 		cell.StorageLen = 0
 		cell.Storage = [length.Hash]byte{}
@@ -54,6 +54,8 @@ type DiagnosticClient struct {
 	resourcesUsageMutex sync.Mutex
 	networkSpeed        NetworkSpeedTestResult
 	networkSpeedMutex   sync.Mutex
+	snapshotScrub       SnapshotScrubStatistics
+	snapshotScrubMutex  sync.Mutex
 }
 
 func NewDiagnosticClient(ctx context.Context, metricsMux *http.ServeMux, dataDirPath string, speedTest bool) (*DiagnosticClient, error) {
@@ -83,6 +85,9 @@ func NewDiagnosticClient(ctx context.Context, metricsMux *http.ServeMux, dataDir
 		resourcesUsage: ResourcesUsage{
 			MemoryUsage: []MemoryStats{},
 		},
+		snapshotScrub: SnapshotScrubStatistics{
+			Files: map[string]SegmentScrubStatistics{},
+		},
 		peersStats: NewPeerStats(1000), // 1000 is the limit of peers; TODO: make it configurable through a flag
 	}, nil
 }
@@ -117,6 +122,7 @@ func (d *DiagnosticClient) Setup() {
 	d.setupBodiesDiagnostics(rootCtx)
 	d.setupResourcesUsageDiagnostics(rootCtx)
 	d.setupSpeedtestDiagnostics(rootCtx)
+	d.setupSnapshotScrubDiagnostics(rootCtx)
 	d.runSaveProcess(rootCtx)
 
 	//d.logDiagMsgs()
@@ -276,6 +276,28 @@ type MemoryStats struct {
 	StageIndex  CurrentSyncStagesIdxs `json:"stageIndex"`
 }
 
+type SnapshotScrubStatistics struct {
+	Passes       uint64                            `json:"passes"`
+	BytesChecked uint64                            `json:"bytesChecked"`
+	Corrupted    uint64                            `json:"corrupted"`
+	LastPassTook time.Duration                     `json:"lastPassTook"`
+	Files        map[string]SegmentScrubStatistics `json:"files"`
+}
+
+type SegmentScrubStatistics struct {
+	Name      string    `json:"name"`
+	Bytes     uint64    `json:"bytes"`
+	Checked   time.Time `json:"checked"`
+	Corrupted bool      `json:"corrupted"`
+	Requeued  bool      `json:"requeued"`
+	Err       string    `json:"err,omitempty"`
+}
+
+type SnapshotScrubPassUpdate struct {
+	Files    int           `json:"files"`
+	TimeTook time.Duration `json:"timeTook"`
+}
+
 type NetworkSpeedTestResult struct {
 	Latency       time.Duration `json:"latency"`
 	DownloadSpeed float64       `json:"downloadSpeed"`
@@ -354,3 +376,11 @@ func (ti HeadersProcessedUpdate) Type() Type {
 func (ti SnapshotFillDBStageUpdate) Type() Type {
 	return TypeOf(ti)
 }
+
+func (ti SegmentScrubStatistics) Type() Type {
+	return TypeOf(ti)
+}
+
+func (ti SnapshotScrubPassUpdate) Type() Type {
+	return TypeOf(ti)
+}
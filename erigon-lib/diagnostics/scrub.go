@@ -0,0 +1,90 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+)
+
+func (d *DiagnosticClient) setupSnapshotScrubDiagnostics(rootCtx context.Context) {
+	d.runSegmentScrubListener(rootCtx)
+	d.runScrubPassListener(rootCtx)
+}
+
+func (d *DiagnosticClient) runSegmentScrubListener(rootCtx context.Context) {
+	go func() {
+		ctx, ch, closeChannel := Context[SegmentScrubStatistics](rootCtx, 1)
+		defer closeChannel()
+
+		StartProviders(ctx, TypeOf(SegmentScrubStatistics{}), log.Root())
+		for {
+			select {
+			case <-rootCtx.Done():
+				return
+			case info := <-ch:
+				d.UpdateSegmentScrubStatistics(info)
+			}
+		}
+	}()
+}
+
+func (d *DiagnosticClient) runScrubPassListener(rootCtx context.Context) {
+	go func() {
+		ctx, ch, closeChannel := Context[SnapshotScrubPassUpdate](rootCtx, 1)
+		defer closeChannel()
+
+		StartProviders(ctx, TypeOf(SnapshotScrubPassUpdate{}), log.Root())
+		for {
+			select {
+			case <-rootCtx.Done():
+				return
+			case info := <-ch:
+				d.snapshotScrubMutex.Lock()
+				d.snapshotScrub.Passes++
+				d.snapshotScrub.LastPassTook = info.TimeTook
+				d.snapshotScrubMutex.Unlock()
+			}
+		}
+	}()
+}
+
+func (d *DiagnosticClient) UpdateSegmentScrubStatistics(info SegmentScrubStatistics) {
+	d.snapshotScrubMutex.Lock()
+	defer d.snapshotScrubMutex.Unlock()
+
+	if d.snapshotScrub.Files == nil {
+		d.snapshotScrub.Files = map[string]SegmentScrubStatistics{}
+	}
+
+	d.snapshotScrub.BytesChecked += info.Bytes
+	if info.Corrupted {
+		d.snapshotScrub.Corrupted++
+	}
+	d.snapshotScrub.Files[info.Name] = info
+}
+
+func (d *DiagnosticClient) SnapshotScrubJson(w io.Writer) {
+	d.snapshotScrubMutex.Lock()
+	defer d.snapshotScrubMutex.Unlock()
+	if err := json.NewEncoder(w).Encode(d.snapshotScrub); err != nil {
+		log.Debug("[diagnostics] SnapshotScrubJson", "err", err)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package diagnostics_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/diagnostics"
+)
+
+func TestUpdateSegmentScrubStatistics(t *testing.T) {
+	d, err := NewTestDiagnosticClient()
+	require.NoError(t, err)
+
+	d.UpdateSegmentScrubStatistics(diagnostics.SegmentScrubStatistics{Name: "a.seg", Bytes: 10})
+	d.UpdateSegmentScrubStatistics(diagnostics.SegmentScrubStatistics{Name: "b.seg", Bytes: 5, Corrupted: true, Requeued: true})
+	d.UpdateSegmentScrubStatistics(diagnostics.SegmentScrubStatistics{Name: "a.seg", Bytes: 10})
+
+	var buf bytes.Buffer
+	d.SnapshotScrubJson(&buf)
+
+	var stats diagnostics.SnapshotScrubStatistics
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &stats))
+	require.Equal(t, uint64(25), stats.BytesChecked)
+	require.Equal(t, uint64(1), stats.Corrupted)
+	require.Len(t, stats.Files, 2)
+	require.True(t, stats.Files["b.seg"].Requeued)
+}
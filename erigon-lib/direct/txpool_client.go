@@ -112,6 +112,58 @@ func (c *TxPoolOnAddC) Context() context.Context { return c.ctx }
 
 // -- end OnAdd
 
+// -- start OnEvent
+
+func (s *TxPoolClient) OnEvent(ctx context.Context, in *txpool_proto.OnEventRequest, opts ...grpc.CallOption) (txpool_proto.Txpool_OnEventClient, error) {
+	ch := make(chan *onEventReply, 16384)
+	streamServer := &TxPoolOnEventS{ch: ch, ctx: ctx}
+	go func() {
+		defer close(ch)
+		streamServer.Err(s.server.OnEvent(in, streamServer))
+	}()
+	return &TxPoolOnEventC{ch: ch, ctx: ctx}, nil
+}
+
+type onEventReply struct {
+	r   *txpool_proto.OnEventReply
+	err error
+}
+
+type TxPoolOnEventS struct {
+	ch  chan *onEventReply
+	ctx context.Context
+	grpc.ServerStream
+}
+
+func (s *TxPoolOnEventS) Send(m *txpool_proto.OnEventReply) error {
+	s.ch <- &onEventReply{r: m}
+	return nil
+}
+func (s *TxPoolOnEventS) Context() context.Context { return s.ctx }
+func (s *TxPoolOnEventS) Err(err error) {
+	if err == nil {
+		return
+	}
+	s.ch <- &onEventReply{err: err}
+}
+
+type TxPoolOnEventC struct {
+	ch  chan *onEventReply
+	ctx context.Context
+	grpc.ClientStream
+}
+
+func (c *TxPoolOnEventC) Recv() (*txpool_proto.OnEventReply, error) {
+	m, ok := <-c.ch
+	if !ok || m == nil {
+		return nil, io.EOF
+	}
+	return m.r, m.err
+}
+func (c *TxPoolOnEventC) Context() context.Context { return c.ctx }
+
+// -- end OnEvent
+
 func (s *TxPoolClient) Status(ctx context.Context, in *txpool_proto.StatusRequest, opts ...grpc.CallOption) (*txpool_proto.StatusReply, error) {
 	return s.server.Status(ctx, in)
 }
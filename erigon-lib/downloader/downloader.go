@@ -94,6 +94,7 @@ type Downloader struct {
 	webDownloadInfo map[string]webDownloadInfo
 	downloading     map[string]*downloadInfo
 	downloadLimit   *rate.Limit
+	requeued        map[string]struct{} // files found corrupted by scrubber, mainLoop must download them again
 
 	stuckFileDetailedLogs bool
 }
@@ -337,6 +338,7 @@ func New(ctx context.Context, cfg *downloadercfg.Cfg, logger log.Logger, verbosi
 		webDownloadInfo:     map[string]webDownloadInfo{},
 		webDownloadSessions: map[string]*RCloneSession{},
 		downloading:         map[string]*downloadInfo{},
+		requeued:            map[string]struct{}{},
 		webseedsDiscover:    discover,
 	}
 	d.webseeds.SetTorrent(d.torrentFS, snapLock.Downloads, cfg.DownloadTorrentFilesFromWebseed)
@@ -394,6 +396,12 @@ func New(ctx context.Context, cfg *downloadercfg.Cfg, logger log.Logger, verbosi
 		}
 	}
 
+	if cfg.WebSeedServeAddr != "" {
+		if err := d.ServeWebSeed(cfg.WebSeedServeAddr); err != nil {
+			return nil, err
+		}
+	}
+
 	return d, nil
 }
 
@@ -797,6 +805,14 @@ func (d *Downloader) MainLoopInBackground(silent bool) {
 			}
 		}
 	}()
+
+	if d.cfg.ScrubRate > 0 {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.scrubLoop(int(d.cfg.ScrubRate.Bytes()))
+		}()
+	}
 }
 
 type downloadStatus struct {
@@ -887,6 +903,11 @@ func (d *Downloader) mainLoop(silent bool) error {
 		lastIntMult := time.Now()
 
 		for {
+			for name := range d.takeRequeued() {
+				delete(complete, name)
+				delete(failed, name)
+			}
+
 			torrents := d.torrentClient.Torrents()
 
 			var pending []*torrent.Torrent
@@ -58,6 +58,8 @@ type Cfg struct {
 	AddTorrentsFromDisk             bool
 	SnapshotLock                    bool
 	ChainName                       string
+	WebSeedServeAddr                string            // serve completed files over HTTP - as webseed for other nodes. empty - disabled
+	ScrubRate                       datasize.ByteSize // bytes/sec budget of background re-hashing of completed files. 0 - disabled
 
 	Dirs datadir.Dirs
 
@@ -0,0 +1,387 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/anacrolix/torrent"
+	"github.com/anacrolix/torrent/metainfo"
+	"golang.org/x/time/rate"
+
+	"github.com/ledgerwatch/erigon-lib/diagnostics"
+	"github.com/ledgerwatch/erigon-lib/downloader/downloadercfg"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+const (
+	// scrubEvery - how often same file is re-hashed
+	scrubEvery = 7 * 24 * time.Hour
+	// scrubInterval - how often scrubber looks for files which are due
+	scrubInterval = time.Hour
+	// QuarantineDir - sub-dir of snapshots dir, where corrupted files are moved to. Safe to remove.
+	QuarantineDir = "quarantine"
+)
+
+// files which have no .torrent - scrubber remembers their hash at first check and compares with it later
+var scrubIndexExtensions = map[string]struct{}{
+	".idx":  {},
+	".efi":  {},
+	".vi":   {},
+	".kvi":  {},
+	".kvei": {},
+	".bt":   {},
+}
+
+// scrubInfo - result of last background integrity check of file. Stored in kv.BittorrentScrub
+type scrubInfo struct {
+	Checked   time.Time `json:"checked"`
+	Corrupted bool      `json:"corrupted,omitempty"`
+	Err       string    `json:"err,omitempty"`
+
+	// baseline of files without .torrent
+	Size    int64     `json:"size,omitempty"`
+	ModTime time.Time `json:"modTime,omitempty"`
+	Hash    []byte    `json:"hash,omitempty"`
+}
+
+type scrubCandidate struct {
+	name    string
+	torrent *torrent.Torrent // nil for index files
+	info    scrubInfo
+}
+
+// scrubLoop - background integrity daemon: periodically re-hashes pieces of completed files
+// with I/O budget of `rateLimit` bytes/sec. Corrupted files are moved to QuarantineDir and
+// re-queued for download (by torrent and webseeds). Indices are just moved - Erigon re-builds missing indices.
+func (d *Downloader) scrubLoop(rateLimit int) {
+	limiter := rate.NewLimiter(rate.Limit(rateLimit), max(rateLimit, downloadercfg.DefaultPieceSize))
+
+	// don't compete with initial download/verification
+	timer := time.NewTimer(scrubInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		if err := d.scrub(d.ctx, limiter, scrubEvery); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			d.logger.Warn("[snapshots] scrub", "err", err)
+		}
+		timer.Reset(scrubInterval)
+	}
+}
+
+// scrub - checks all files which were not checked during `every`
+func (d *Downloader) scrub(ctx context.Context, limiter *rate.Limiter, every time.Duration) error {
+	candidates, err := d.scrubCandidates(ctx, every)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	var corrupted int
+	for _, c := range candidates {
+		res, n, err := d.scrubFile(ctx, limiter, c)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return err
+			}
+			res.Err = err.Error()
+			d.logger.Debug("[snapshots] scrub", "file", c.name, "err", err)
+		}
+		res.Checked = time.Now()
+		if res.Corrupted {
+			corrupted++
+		}
+
+		if err := d.db.Update(ctx, scrubInfoUpdater(c.name, res)); err != nil {
+			return fmt.Errorf("scrub: %s: %w", c.name, err)
+		}
+
+		diagnostics.Send(diagnostics.SegmentScrubStatistics{
+			Name:      c.name,
+			Bytes:     uint64(n),
+			Checked:   res.Checked,
+			Corrupted: res.Corrupted,
+			Requeued:  res.Corrupted && c.torrent != nil,
+			Err:       res.Err,
+		})
+	}
+
+	diagnostics.Send(diagnostics.SnapshotScrubPassUpdate{
+		Files:    len(candidates),
+		TimeTook: time.Since(start),
+	})
+	d.logger.Info("[snapshots] scrub done", "files", len(candidates), "corrupted", corrupted, "took", time.Since(start))
+	return nil
+}
+
+// scrubCandidates - completed torrents and indices, which were not checked during `every`, oldest first
+func (d *Downloader) scrubCandidates(ctx context.Context, every time.Duration) ([]scrubCandidate, error) {
+	var candidates []scrubCandidate
+
+	for _, t := range d.torrentClient.Torrents() {
+		if t.Info() == nil || !t.Complete.Bool() {
+			continue
+		}
+		d.lock.RLock()
+		_, downloading := d.downloading[t.Name()]
+		d.lock.RUnlock()
+		if downloading {
+			continue
+		}
+		candidates = append(candidates, scrubCandidate{name: t.Name(), torrent: t})
+	}
+
+	err := filepath.WalkDir(d.SnapDir(), func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if e.IsDir() {
+			if e.Name() == QuarantineDir || e.Name() == "tmp" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if _, ok := scrubIndexExtensions[filepath.Ext(e.Name())]; !ok {
+			return nil
+		}
+		name, err := filepath.Rel(d.SnapDir(), path)
+		if err != nil {
+			return err
+		}
+		candidates = append(candidates, scrubCandidate{name: filepath.ToSlash(name)})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.db.View(ctx, func(tx kv.Tx) error {
+		for i := range candidates {
+			v, err := tx.GetOne(kv.BittorrentScrub, []byte(candidates[i].name))
+			if err != nil {
+				return err
+			}
+			if len(v) == 0 {
+				continue
+			}
+			if err := json.Unmarshal(v, &candidates[i].info); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	due := candidates[:0]
+	for _, c := range candidates {
+		if now.Sub(c.info.Checked) >= every {
+			due = append(due, c)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].info.Checked.Before(due[j].info.Checked) })
+	return due, nil
+}
+
+// scrubFile - returns new check result and amount of read bytes
+func (d *Downloader) scrubFile(ctx context.Context, limiter *rate.Limiter, c scrubCandidate) (res scrubInfo, n int64, err error) {
+	fPath := filepath.Join(d.SnapDir(), filepath.FromSlash(c.name))
+
+	if c.torrent == nil {
+		return d.scrubIndex(ctx, limiter, fPath, c)
+	}
+
+	piece, n, err := scrubPieces(ctx, limiter, c.torrent.Info(), fPath)
+	if err != nil {
+		return res, n, err
+	}
+	if piece < 0 {
+		return res, n, nil
+	}
+
+	d.logger.Warn("[snapshots] scrub: file corrupted, re-downloading", "file", c.name, "piece", piece)
+	res.Corrupted = true
+	if err := d.requeueCorrupted(ctx, c.torrent); err != nil {
+		return res, n, fmt.Errorf("requeue: %w", err)
+	}
+	return res, n, nil
+}
+
+// scrubIndex - file has no .torrent: stores its hash as baseline at first check (or if file was re-built)
+// and compares with it later
+func (d *Downloader) scrubIndex(ctx context.Context, limiter *rate.Limiter, fPath string, c scrubCandidate) (res scrubInfo, n int64, err error) {
+	st, err := os.Stat(fPath)
+	if err != nil {
+		return res, 0, err
+	}
+	f, err := os.Open(fPath)
+	if err != nil {
+		return res, 0, err
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	n, err = io.Copy(hasher, &rateLimitedReader{ctx: ctx, r: f, l: limiter})
+	if err != nil {
+		return res, n, err
+	}
+	res = scrubInfo{Size: st.Size(), ModTime: st.ModTime(), Hash: hasher.Sum(nil)}
+
+	prev := c.info
+	if len(prev.Hash) == 0 || prev.Size != st.Size() || !prev.ModTime.Equal(st.ModTime()) {
+		return res, n, nil
+	}
+	if bytes.Equal(prev.Hash, res.Hash) {
+		return res, n, nil
+	}
+
+	d.logger.Warn("[snapshots] scrub: index corrupted, moving to quarantine", "file", c.name)
+	// baseline must be taken again from re-built file
+	res = scrubInfo{Corrupted: true}
+	if err := d.quarantine(c.name); err != nil {
+		return res, n, err
+	}
+	return res, n, nil
+}
+
+// scrubPieces - returns index of first corrupted piece or -1
+func scrubPieces(ctx context.Context, limiter *rate.Limiter, info *metainfo.Info, fPath string) (piece int, n int64, err error) {
+	f, err := os.Open(fPath)
+	if err != nil {
+		return -1, 0, err
+	}
+	defer f.Close()
+
+	hasher := sha1.New()
+	for i := 0; i < info.NumPieces(); i++ {
+		p := info.Piece(i)
+		hasher.Reset()
+		read, err := io.Copy(hasher, &rateLimitedReader{ctx: ctx, r: io.NewSectionReader(f, p.Offset(), p.Length()), l: limiter})
+		n += read
+		if err != nil {
+			return -1, n, err
+		}
+		if read != p.Length() || !bytes.Equal(hasher.Sum(nil), p.Hash().Bytes()) {
+			return i, n, nil
+		}
+	}
+	return -1, n, nil
+}
+
+// requeueCorrupted - moves file to quarantine, forgets its pieces and adds it back to download
+func (d *Downloader) requeueCorrupted(ctx context.Context, t *torrent.Torrent) error {
+	name, infoHash, numPieces := t.Name(), t.InfoHash(), t.NumPieces()
+
+	t.Drop()
+	if err := d.quarantine(name); err != nil {
+		return err
+	}
+	for i := 0; i < numPieces; i++ {
+		if err := d.pieceCompletionDB.Set(metainfo.PieceKey{InfoHash: infoHash, Index: i}, false); err != nil {
+			return err
+		}
+	}
+	if err := d.db.Update(ctx, torrentInfoReset(name, infoHash.Bytes(), 0)); err != nil {
+		return err
+	}
+
+	d.lock.Lock()
+	d.requeued[name] = struct{}{}
+	d.lock.Unlock()
+
+	ts, err := d.torrentFS.LoadByName(name)
+	if err != nil {
+		return err
+	}
+	_, _, err = addTorrentFile(ctx, ts, d.torrentClient, d.db, d.webseeds)
+	return err
+}
+
+// takeRequeued - files which mainLoop must forget as `complete` and download again
+func (d *Downloader) takeRequeued() map[string]struct{} {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if len(d.requeued) == 0 {
+		return nil
+	}
+	requeued := d.requeued
+	d.requeued = map[string]struct{}{}
+	return requeued
+}
+
+func (d *Downloader) quarantine(name string) error {
+	from := filepath.Join(d.SnapDir(), filepath.FromSlash(name))
+	to := filepath.Join(d.SnapDir(), QuarantineDir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	return os.Rename(from, to)
+}
+
+func scrubInfoUpdater(name string, info scrubInfo) func(tx kv.RwTx) error {
+	return func(tx kv.RwTx) error {
+		v, err := json.Marshal(info)
+		if err != nil {
+			return err
+		}
+		return tx.Put(kv.BittorrentScrub, []byte(name), v)
+	}
+}
+
+type rateLimitedReader struct {
+	ctx context.Context
+	r   io.Reader
+	l   *rate.Limiter
+}
+
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	if r.l.Limit() != rate.Inf && len(p) > r.l.Burst() {
+		p = p[:r.l.Burst()]
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if err := r.l.WaitN(r.ctx, n); err != nil {
+			return n, err
+		}
+	}
+	return n, err
+}
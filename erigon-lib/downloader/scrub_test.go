@@ -0,0 +1,112 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	lg "github.com/anacrolix/log"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	downloadercfg2 "github.com/ledgerwatch/erigon-lib/downloader/downloadercfg"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+)
+
+func TestScrub(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	dirs := datadir.New(t.TempDir())
+	cfg, err := downloadercfg2.New(dirs, "", lg.Info, 0, 0, 0, 0, 0, nil, nil, "testnet", false, false)
+	require.NoError(err)
+	d, err := New(ctx, cfg, log.New(), log.LvlInfo, true)
+	require.NoError(err)
+	defer d.Close()
+
+	const segName, idxName = "v1-000000-000100-headers.seg", "v1-000000-000100-headers.idx"
+	data := make([]byte, 4096)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	require.NoError(os.WriteFile(filepath.Join(dirs.Snap, segName), data, 0644))
+	require.NoError(os.WriteFile(filepath.Join(dirs.Snap, idxName), data, 0644))
+	require.NoError(d.AddNewSeedableFile(ctx, segName))
+	for _, tt := range d.TorrentClient().Torrents() {
+		tt.VerifyData()
+	}
+	require.Eventually(func() bool {
+		tt := d.TorrentClient().Torrents()
+		return len(tt) == 1 && tt[0].Complete.Bool()
+	}, 10*time.Second, 50*time.Millisecond)
+	infoHash := d.TorrentClient().Torrents()[0].InfoHash()
+
+	limiter := rate.NewLimiter(rate.Inf, downloadercfg2.DefaultPieceSize)
+	readInfo := func(name string) (info scrubInfo) {
+		require.NoError(d.db.View(ctx, func(tx kv.Tx) error {
+			v, err := tx.GetOne(kv.BittorrentScrub, []byte(name))
+			if err != nil {
+				return err
+			}
+			return json.Unmarshal(v, &info)
+		}))
+		return info
+	}
+
+	// healthy files: checked once per period, index baseline remembered
+	require.NoError(d.scrub(ctx, limiter, time.Hour))
+	require.False(readInfo(segName).Corrupted)
+	require.NotEmpty(readInfo(idxName).Hash)
+	checked := readInfo(segName).Checked
+	require.NoError(d.scrub(ctx, limiter, time.Hour))
+	require.Equal(checked, readInfo(segName).Checked)
+
+	// silent corruption: same size and modtime
+	corrupt := func(name string) {
+		fPath := filepath.Join(dirs.Snap, name)
+		st, err := os.Stat(fPath)
+		require.NoError(err)
+		bad := append([]byte{}, data...)
+		bad[100]++
+		require.NoError(os.WriteFile(fPath, bad, 0644))
+		require.NoError(os.Chtimes(fPath, st.ModTime(), st.ModTime()))
+	}
+	corrupt(segName)
+	corrupt(idxName)
+	require.NoError(d.scrub(ctx, limiter, 0))
+
+	require.True(readInfo(segName).Corrupted)
+	require.True(readInfo(idxName).Corrupted)
+	require.NoFileExists(filepath.Join(dirs.Snap, idxName))
+	for _, name := range []string{segName, idxName} {
+		require.FileExists(filepath.Join(dirs.Snap, QuarantineDir, name))
+	}
+
+	// re-queued for download
+	tt, ok := d.TorrentClient().Torrent(infoHash)
+	require.True(ok)
+	require.False(tt.Complete.Bool())
+	complete, _, _ := d.checkComplete(segName)
+	require.False(complete)
+	require.Contains(d.takeRequeued(), segName)
+}
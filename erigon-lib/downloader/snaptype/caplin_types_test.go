@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/ledgerwatch/erigon-lib/downloader/snaptype"
+	"github.com/ledgerwatch/erigon-lib/seg"
 )
 
 func TestEnumeration(t *testing.T) {
@@ -44,3 +45,23 @@ func TestNames(t *testing.T) {
 	}
 
 }
+
+func TestSetCodecs(t *testing.T) {
+	if err := snaptype.SetCodecs("BeaconBlocks=zstd, blobsidecars=patterns"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = snaptype.SetCodecs("beaconblocks=patterns") }()
+
+	if snaptype.BeaconBlocks.Codec() != seg.CodecZstd {
+		t.Fatal("codec mismatch", snaptype.BeaconBlocks, snaptype.BeaconBlocks.Codec())
+	}
+	if snaptype.BlobSidecars.Codec() != seg.CodecPatterns {
+		t.Fatal("codec mismatch", snaptype.BlobSidecars, snaptype.BlobSidecars.Codec())
+	}
+
+	for _, spec := range []string{"beaconblocks", "nosuchtype=zstd", "beaconblocks=lz4"} {
+		if err := snaptype.SetCodecs(spec); err == nil {
+			t.Fatal("expected error", spec)
+		}
+	}
+}
@@ -181,6 +181,8 @@ type Type interface {
 	HasIndexFiles(info FileInfo, logger log.Logger) bool
 	BuildIndexes(ctx context.Context, info FileInfo, chainConfig *chain.Config, tmpDir string, p *background.Progress, lvl log.Lvl, logger log.Logger) error
 	ExtractRange(ctx context.Context, info FileInfo, firstKeyGetter FirstKeyGetter, db kv.RoDB, chainConfig *chain.Config, tmpDir string, workers int, lvl log.Lvl, logger log.Logger) (uint64, error)
+	// Codec - compression codec for new files of this type. Existing files are read with codec recorded in their header.
+	Codec() seg.Codec
 }
 
 type snapType struct {
@@ -197,6 +199,7 @@ type snapType struct {
 // and them be readonly
 var registeredTypes = map[Enum]Type{}
 var namedTypes = map[string]Type{}
+var typeCodecs = map[Enum]seg.Codec{}
 
 func RegisterType(enum Enum, name string, versions Versions, rangeExtractor RangeExtractor, indexes []Index, indexBuilder IndexBuilder) Type {
 	t := snapType{
@@ -238,6 +241,10 @@ func (s snapType) FileInfo(dir string, from uint64, to uint64) FileInfo {
 	return f
 }
 
+func (s snapType) Codec() seg.Codec {
+	return typeCodecs[s.enum]
+}
+
 func (s snapType) ExtractRange(ctx context.Context, info FileInfo, firstKeyGetter FirstKeyGetter, db kv.RoDB, chainConfig *chain.Config, tmpDir string, workers int, lvl log.Lvl, logger log.Logger) (uint64, error) {
 	return ExtractRange(ctx, info, s.rangeExtractor, firstKeyGetter, db, chainConfig, tmpDir, workers, lvl, logger)
 }
@@ -301,6 +308,37 @@ func (s snapType) IdxFileName(version Version, from uint64, to uint64, index ...
 	return IdxFileName(version, from, to, index[0].Name)
 }
 
+// SetCodecs - sets compression codec of new files per type. Format: `transactions=zstd,headers=patterns`.
+// Types not mentioned keep their codec. Must be called during program initialization.
+func SetCodecs(spec string) error {
+	for _, kv := range strings.Split(spec, ",") {
+		kv = strings.TrimSpace(kv)
+		if kv == "" {
+			continue
+		}
+		name, codecName, ok := strings.Cut(kv, "=")
+		if !ok {
+			return fmt.Errorf("invalid snapshot codec %q, expected <type>=<codec>", kv)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		t, ok := namedTypes[name]
+		for _, ct := range CaplinSnapshotTypes {
+			if ct.Name() == name {
+				t, ok = ct, true
+			}
+		}
+		if !ok {
+			return fmt.Errorf("unknown snapshot type: %q", name)
+		}
+		codec, err := seg.ParseCodec(strings.TrimSpace(codecName))
+		if err != nil {
+			return err
+		}
+		typeCodecs[t.Enum()] = codec
+	}
+	return nil
+}
+
 func ParseFileType(s string) (Type, bool) {
 	enum, ok := ParseEnum(s)
 
@@ -322,6 +360,7 @@ type Enums struct {
 const MinCoreEnum = 1
 const MinBorEnum = 4
 const MinCaplinEnum = 8
+const MinTraceEnum = 10
 
 var CaplinEnums = struct {
 	Enums
@@ -472,6 +511,7 @@ func ExtractRange(ctx context.Context, f FileInfo, extractor RangeExtractor, fir
 		return lastKeyValue, err
 	}
 	defer sn.Close()
+	sn.SetCodec(f.Type.Codec())
 
 	lastKeyValue, err = extractor.Extract(ctx, f.From, f.To, firstKey, chainDB, chainConfig, func(v []byte) error {
 		return sn.AddWord(v)
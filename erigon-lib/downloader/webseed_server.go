@@ -0,0 +1,127 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// webSeedManifest - list of files served by webseed, format is same as WebSeeds.retrieveManifest expects:
+// one file name (relative to snapshots dir) per line, data files and their .torrent files
+const webSeedManifest = "manifest.txt"
+
+// webSeedHandler - serves completed files of Downloader over plain HTTP (range requests supported).
+// Other nodes can use it as webseed: `--webseed=http://<addr>` and bootstrap without BitTorrent traffic.
+type webSeedHandler struct {
+	d *Downloader
+}
+
+func (d *Downloader) WebSeedHandler() http.Handler { return &webSeedHandler{d: d} }
+
+// ServeWebSeed - starts HTTP server of WebSeedHandler on addr. Server stops on Downloader.Close
+func (d *Downloader) ServeWebSeed(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("webseed server: %w", err)
+	}
+	srv := &http.Server{Handler: d.WebSeedHandler(), ReadHeaderTimeout: 10 * time.Second}
+	d.wg.Add(2)
+	go func() {
+		defer d.wg.Done()
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			d.logger.Warn("[snapshots] webseed server", "err", err)
+		}
+	}()
+	go func() {
+		defer d.wg.Done()
+		<-d.ctx.Done()
+		_ = srv.Close()
+	}()
+	d.logger.Info("[snapshots] webseed server started", "addr", ln.Addr().String())
+	return nil
+}
+
+// completedFiles - data files which are fully downloaded (or produced) and verified
+func (d *Downloader) completedFiles() map[string]struct{} {
+	res := map[string]struct{}{}
+	for _, t := range d.torrentClient.Torrents() {
+		if t.Info() == nil || !t.Complete.Bool() {
+			continue
+		}
+		res[t.Name()] = struct{}{}
+	}
+	return res
+}
+
+func (h *webSeedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	completed := h.d.completedFiles()
+	if name == webSeedManifest {
+		http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(h.manifest(completed)))
+		return
+	}
+	// serve only files known to Downloader: it protects from serving half-downloaded files and from path traversal
+	if _, ok := completed[strings.TrimSuffix(name, ".torrent")]; !ok {
+		http.NotFound(w, r)
+		return
+	}
+	f, err := os.Open(filepath.Join(h.d.SnapDir(), filepath.FromSlash(name)))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// WebSeeds requires Etag (see retrieveFileEtag), md5 of big files is too expensive - use same Etag as nginx does
+	w.Header().Set("Etag", fmt.Sprintf(`"%x-%x"`, fi.ModTime().Unix(), fi.Size()))
+	http.ServeContent(w, r, name, fi.ModTime(), f)
+}
+
+func (h *webSeedHandler) manifest(completed map[string]struct{}) []byte {
+	names := make([]string, 0, len(completed)*2)
+	for name := range completed {
+		names = append(names, name)
+		if exists, _ := h.d.torrentFS.Exists(name); exists {
+			names = append(names, name+".torrent")
+		}
+	}
+	sort.Strings(names)
+	var b bytes.Buffer
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	lg "github.com/anacrolix/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	downloadercfg2 "github.com/ledgerwatch/erigon-lib/downloader/downloadercfg"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+)
+
+func TestWebSeedServer(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	dirs := datadir.New(t.TempDir())
+	cfg, err := downloadercfg2.New(dirs, "", lg.Info, 0, 0, 0, 0, 0, nil, nil, "testnet", false, false)
+	require.NoError(err)
+	d, err := New(ctx, cfg, log.New(), log.LvlInfo, true)
+	require.NoError(err)
+	defer d.Close()
+
+	data := make([]byte, 4096)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	require.NoError(os.WriteFile(filepath.Join(dirs.Snap, "v1-000000-000100-headers.seg"), data, 0644))
+	require.NoError(os.WriteFile(filepath.Join(dirs.Snap, "v1-000100-000200-headers.seg"), data, 0644)) // not added to downloader
+	require.NoError(d.AddNewSeedableFile(ctx, "v1-000000-000100-headers.seg"))
+
+	srv := httptest.NewServer(d.WebSeedHandler())
+	defer srv.Close()
+	get := func(path string, header http.Header) (*http.Response, []byte) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+path, nil)
+		require.NoError(err)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(err)
+		return resp, body
+	}
+
+	resp, body := get("/v1-000000-000100-headers.seg", nil)
+	require.Equal(http.StatusNotFound, resp.StatusCode) // not verified yet
+	_, body = get("/manifest.txt", nil)
+	require.Empty(body)
+
+	for _, tt := range d.TorrentClient().Torrents() {
+		tt.VerifyData()
+	}
+	require.Eventually(func() bool {
+		_, body := get("/manifest.txt", nil)
+		return string(body) == "v1-000000-000100-headers.seg\nv1-000000-000100-headers.seg.torrent\n"
+	}, 10*time.Second, 50*time.Millisecond)
+
+	resp, body = get("/v1-000000-000100-headers.seg", http.Header{"Range": {"bytes=10-19"}})
+	require.Equal(http.StatusPartialContent, resp.StatusCode)
+	require.Equal(data[10:20], body)
+	require.NotEmpty(resp.Header.Get("Etag"))
+
+	resp, _ = get("/v1-000000-000100-headers.seg.torrent", nil)
+	require.Equal(http.StatusOK, resp.StatusCode)
+
+	resp, _ = get("/v1-000100-000200-headers.seg", nil)
+	require.Equal(http.StatusNotFound, resp.StatusCode)
+	resp, _ = get("/../v1-000000-000100-headers.seg.torrent", nil)
+	require.Equal(http.StatusOK, resp.StatusCode) // cleaned to the root
+	resp, _ = get("/%2e%2e/downloader/mdbx.dat", nil)
+	require.Equal(http.StatusNotFound, resp.StatusCode)
+
+	// manifest is compatible with webseed client
+	u, err := url.Parse(srv.URL)
+	require.NoError(err)
+	manifest, err := NewWebSeeds(nil, log.LvlInfo, log.New()).retrieveManifest(ctx, u)
+	require.NoError(err)
+	require.Equal(srv.URL+"/v1-000000-000100-headers.seg", manifest["v1-000000-000100-headers.seg"])
+	require.Equal(srv.URL+"/v1-000000-000100-headers.seg.torrent", manifest["v1-000000-000100-headers.seg.torrent"])
+	require.Len(manifest, 2)
+}
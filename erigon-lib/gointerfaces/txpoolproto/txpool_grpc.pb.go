@@ -30,6 +30,7 @@ const (
 	Txpool_OnAdd_FullMethodName        = "/txpool.Txpool/OnAdd"
 	Txpool_Status_FullMethodName       = "/txpool.Txpool/Status"
 	Txpool_Nonce_FullMethodName        = "/txpool.Txpool/Nonce"
+	Txpool_OnEvent_FullMethodName      = "/txpool.Txpool/OnEvent"
 )
 
 // TxpoolClient is the client API for Txpool service.
@@ -55,6 +56,8 @@ type TxpoolClient interface {
 	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusReply, error)
 	// returns nonce for given account
 	Nonce(ctx context.Context, in *NonceRequest, opts ...grpc.CallOption) (*NonceReply, error)
+	// subscribe to transaction add, promote, replace and discard events
+	OnEvent(ctx context.Context, in *OnEventRequest, opts ...grpc.CallOption) (Txpool_OnEventClient, error)
 }
 
 type txpoolClient struct {
@@ -178,6 +181,39 @@ func (c *txpoolClient) Nonce(ctx context.Context, in *NonceRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *txpoolClient) OnEvent(ctx context.Context, in *OnEventRequest, opts ...grpc.CallOption) (Txpool_OnEventClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Txpool_ServiceDesc.Streams[1], Txpool_OnEvent_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &txpoolOnEventClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Txpool_OnEventClient interface {
+	Recv() (*OnEventReply, error)
+	grpc.ClientStream
+}
+
+type txpoolOnEventClient struct {
+	grpc.ClientStream
+}
+
+func (x *txpoolOnEventClient) Recv() (*OnEventReply, error) {
+	m := new(OnEventReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // TxpoolServer is the server API for Txpool service.
 // All implementations must embed UnimplementedTxpoolServer
 // for forward compatibility
@@ -201,6 +237,8 @@ type TxpoolServer interface {
 	Status(context.Context, *StatusRequest) (*StatusReply, error)
 	// returns nonce for given account
 	Nonce(context.Context, *NonceRequest) (*NonceReply, error)
+	// subscribe to transaction add, promote, replace and discard events
+	OnEvent(*OnEventRequest, Txpool_OnEventServer) error
 	mustEmbedUnimplementedTxpoolServer()
 }
 
@@ -235,6 +273,9 @@ func (UnimplementedTxpoolServer) Status(context.Context, *StatusRequest) (*Statu
 func (UnimplementedTxpoolServer) Nonce(context.Context, *NonceRequest) (*NonceReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Nonce not implemented")
 }
+func (UnimplementedTxpoolServer) OnEvent(*OnEventRequest, Txpool_OnEventServer) error {
+	return status.Errorf(codes.Unimplemented, "method OnEvent not implemented")
+}
 func (UnimplementedTxpoolServer) mustEmbedUnimplementedTxpoolServer() {}
 
 // UnsafeTxpoolServer may be embedded to opt out of forward compatibility for this service.
@@ -413,6 +454,27 @@ func _Txpool_Nonce_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Txpool_OnEvent_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(OnEventRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TxpoolServer).OnEvent(m, &txpoolOnEventServer{ServerStream: stream})
+}
+
+type Txpool_OnEventServer interface {
+	Send(*OnEventReply) error
+	grpc.ServerStream
+}
+
+type txpoolOnEventServer struct {
+	grpc.ServerStream
+}
+
+func (x *txpoolOnEventServer) Send(m *OnEventReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // Txpool_ServiceDesc is the grpc.ServiceDesc for Txpool service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -459,6 +521,11 @@ var Txpool_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Txpool_OnAdd_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "OnEvent",
+			Handler:       _Txpool_OnEvent_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "txpool/txpool.proto",
 }
@@ -39,14 +39,19 @@ import (
 	"github.com/ledgerwatch/erigon-lib/log/v3"
 )
 
-func OpenPair(from, to string, label kv.Label, targetPageSize datasize.ByteSize, logger log.Logger) (kv.RoDB, kv.RwDB) {
+// OpenSource - opens db for reading, also if it's already opened by running Erigon
+func OpenSource(from string, label kv.Label, logger log.Logger) kv.RoDB {
 	const ThreadsHardLimit = 9_000
-	src := mdbx2.NewMDBX(logger).Path(from).
+	return mdbx2.NewMDBX(logger).Path(from).
 		Label(label).
 		RoTxsLimiter(semaphore.NewWeighted(ThreadsHardLimit)).
 		WithTableCfg(func(_ kv.TableCfg) kv.TableCfg { return kv.TablesCfgByLabel(label) }).
 		Flags(func(flags uint) uint { return flags | mdbx.Accede }).
 		MustOpen()
+}
+
+func OpenPair(from, to string, label kv.Label, targetPageSize datasize.ByteSize, logger log.Logger) (kv.RoDB, kv.RwDB) {
+	src := OpenSource(from, label, logger)
 	if targetPageSize <= 0 {
 		targetPageSize = datasize.ByteSize(src.PageSize())
 	}
@@ -0,0 +1,549 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/erigontech/mdbx-go/mdbx"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+)
+
+// Incremental backups of MDBX database, taken in one read transaction while the node runs.
+//
+// Backups of one database form a chain in one directory, every backup is a sub-directory:
+//
+//	<backups dir>/000001/manifest.json
+//	<backups dir>/000001/tables/<table>.kv
+//	<backups dir>/000002/manifest.json
+//	<backups dir>/000002/tables/<table>.kv - only tables modified after 000001
+//
+// Table is backed up again only if MDBX reports its modification after the read transaction of previous backup.
+// Manifest lists every table of the database with directory of the backup holding its content and checksum of it,
+// so restore needs only the last manifest. Immutable snapshot files are not copied, manifest only lists them.
+
+const (
+	ManifestFileName = "manifest.json"
+	tablesDirName    = "tables"
+	tableFileExt     = ".kv"
+)
+
+var ErrBackupChainBroken = errors.New("backup: chain of incremental backups is broken")
+
+type Manifest struct {
+	Label     string    `json:"label"`
+	Name      string    `json:"name"`             // directory of this backup
+	Parent    string    `json:"parent,omitempty"` // directory of previous backup in chain, empty for full backup
+	TxID      uint64    `json:"txID"`             // id of read transaction backup was taken in
+	PageSize  uint64    `json:"pageSize"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	Tables        []TableManifest `json:"tables"`
+	SnapshotFiles []SnapshotFile  `json:"snapshotFiles"`
+}
+
+type TableManifest struct {
+	Name    string `json:"name"`
+	ModTxID uint64 `json:"modTxID"` // id of transaction which modified table last time
+	Entries uint64 `json:"entries"`
+	Backup  string `json:"backup"` // directory of backup holding table content
+	Size    int64  `json:"size"`
+	SHA256  string `json:"sha256"`
+}
+
+// SnapshotFile - immutable file of datadir/snapshots, which database relies on, but backup doesn't copy
+type SnapshotFile struct {
+	Path string `json:"path"` // relative to snapshots dir
+	Size int64  `json:"size"`
+}
+
+func (m *Manifest) table(name string) (TableManifest, bool) {
+	for _, t := range m.Tables {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return TableManifest{}, false
+}
+
+// IncrementalOpts - full=true starts new chain even if previous backup exists
+type IncrementalOpts struct {
+	Label   kv.Label
+	SnapDir string // listed into manifest, if not empty
+	Full    bool
+}
+
+// Incremental - backups db into new sub-directory of dir, copying only tables modified since the last backup there
+func Incremental(ctx context.Context, db kv.RoDB, dir string, opts IncrementalOpts, logger log.Logger) (_ *Manifest, err error) {
+	tx, err := db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	statTx, ok := tx.(interface {
+		BucketStat(name string) (*mdbx.Stat, error)
+		ExistsBucket(name string) (bool, error)
+		ViewID() uint64
+	})
+	if !ok {
+		return nil, fmt.Errorf("backup: incremental backup supports only mdbx, got %T", tx)
+	}
+
+	var prev *Manifest
+	if !opts.Full {
+		if prev, err = LatestManifest(dir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	if prev != nil && (prev.Label != string(opts.Label) || prev.TxID > statTx.ViewID()) {
+		// txID goes back when db was re-created (for example restored) - its txIDs can't be compared with previous ones
+		logger.Info("[backup] previous backup is of other db, start new chain", "previous", prev.Name)
+		prev = nil
+	}
+
+	m := &Manifest{Label: string(opts.Label), TxID: statTx.ViewID(), PageSize: db.PageSize(), CreatedAt: time.Now().UTC()}
+	if m.Name, err = nextBackupName(dir); err != nil {
+		return nil, err
+	}
+	if prev != nil {
+		m.Parent = prev.Name
+	}
+	tablesDir := filepath.Join(dir, m.Name, tablesDirName)
+	if err := os.MkdirAll(tablesDir, 0740); err != nil {
+		return nil, err
+	}
+	defer func() { // don't leave half-written backup, next one would use it as previous
+		if err != nil {
+			os.RemoveAll(filepath.Join(dir, m.Name))
+		}
+	}()
+
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+
+	tables := make([]string, 0, len(db.AllTables()))
+	for name, cfg := range db.AllTables() {
+		if !cfg.IsDeprecated {
+			tables = append(tables, name)
+		}
+	}
+	sort.Strings(tables)
+	var copied int
+	for _, name := range tables {
+		exists, err := statTx.ExistsBucket(name)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		st, err := statTx.BucketStat(name)
+		if err != nil {
+			return nil, err
+		}
+		if prev != nil {
+			if t, ok := prev.table(name); ok && t.ModTxID == st.LastTxId && t.Entries == st.Entries {
+				m.Tables = append(m.Tables, t)
+				continue
+			}
+		}
+		t := TableManifest{Name: name, ModTxID: st.LastTxId, Entries: st.Entries, Backup: m.Name}
+		if t.Size, t.SHA256, err = writeTable(ctx, tx, name, filepath.Join(tablesDir, name+tableFileExt), logEvery, logger); err != nil {
+			return nil, fmt.Errorf("backup: table %s: %w", name, err)
+		}
+		m.Tables = append(m.Tables, t)
+		copied++
+	}
+	tx.Rollback() // don't hold read transaction while listing files
+
+	if opts.SnapDir != "" {
+		if m.SnapshotFiles, err = listSnapshotFiles(opts.SnapDir); err != nil {
+			return nil, err
+		}
+	}
+	if err = writeManifest(filepath.Join(dir, m.Name), m); err != nil {
+		return nil, err
+	}
+	logger.Info("[backup] done", "backup", m.Name, "parent", m.Parent, "tables copied", copied, "tables total", len(m.Tables))
+	return m, nil
+}
+
+// writeTable - stream of records: uvarint(len(k)) k uvarint(len(v)) v, sorted like in table
+func writeTable(ctx context.Context, tx kv.Tx, table, path string, logEvery *time.Ticker, logger log.Logger) (size int64, checksum string, err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	w := bufio.NewWriterSize(io.MultiWriter(f, h), 1024*1024)
+
+	c, err := tx.Cursor(table)
+	if err != nil {
+		return 0, "", err
+	}
+	defer c.Close()
+	var lenBuf [binary.MaxVarintLen64]byte
+	var i uint64
+	for k, v, err := c.First(); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return 0, "", err
+		}
+		for _, b := range [][]byte{k, v} {
+			n := binary.PutUvarint(lenBuf[:], uint64(len(b)))
+			if _, err := w.Write(lenBuf[:n]); err != nil {
+				return 0, "", err
+			}
+			if _, err := w.Write(b); err != nil {
+				return 0, "", err
+			}
+		}
+		i++
+		if i%100_000 == 0 {
+			select {
+			case <-ctx.Done():
+				return 0, "", ctx.Err()
+			case <-logEvery.C:
+				logger.Info("[backup] Progress", "table", table, "entries", i)
+			default:
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return 0, "", err
+	}
+	if err := f.Sync(); err != nil {
+		return 0, "", err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		return 0, "", err
+	}
+	return st.Size(), hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func listSnapshotFiles(snapDir string) (files []SnapshotFile, err error) {
+	err = filepath.WalkDir(snapDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(snapDir, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if rel == "db" || rel == "tmp" { // downloader db and temporary files are not immutable
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(rel, ".tmp") || strings.HasSuffix(rel, ".torrent") || strings.HasSuffix(rel, ".lock") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, SnapshotFile{Path: filepath.ToSlash(rel), Size: info.Size()})
+		return nil
+	})
+	return files, err
+}
+
+func writeManifest(backupDir string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(backupDir, ManifestFileName+".tmp")
+	if err := os.WriteFile(tmp, data, 0640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(backupDir, ManifestFileName))
+}
+
+func ReadManifest(backupDir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(backupDir, ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("backup: manifest of %s: %w", backupDir, err)
+	}
+	return m, nil
+}
+
+// Backups - names of complete backups in dir, oldest first
+func Backups(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, e.Name(), ManifestFileName)); err == nil {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LatestManifest - manifest of the last backup in dir, fs.ErrNotExist if there are no backups
+func LatestManifest(dir string) (*Manifest, error) {
+	names, err := Backups(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fs.ErrNotExist
+	}
+	return ReadManifest(filepath.Join(dir, names[len(names)-1]))
+}
+
+func nextBackupName(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0740); err != nil {
+		return "", err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	var last uint64
+	for _, e := range entries {
+		var n uint64
+		if _, err := fmt.Sscanf(e.Name(), "%d", &n); err == nil && n > last {
+			last = n
+		}
+	}
+	return fmt.Sprintf("%06d", last+1), nil
+}
+
+// Verify - checks that files of all tables of backup m exist in dir and match their checksums
+func Verify(dir string, m *Manifest) error {
+	for _, t := range m.Tables {
+		size, checksum, err := checksumFile(filepath.Join(dir, t.Backup, tablesDirName, t.Name+tableFileExt))
+		if err != nil {
+			return fmt.Errorf("%w: table %s of %s: %w", ErrBackupChainBroken, t.Name, t.Backup, err)
+		}
+		if size != t.Size || checksum != t.SHA256 {
+			return fmt.Errorf("%w: table %s of %s has checksum %s, expected %s", ErrBackupChainBroken, t.Name, t.Backup, checksum, t.SHA256)
+		}
+	}
+	return nil
+}
+
+func checksumFile(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Restore - fills empty dst with content of backup name (the latest if empty) of dir.
+// Tables are committed periodically, so on error dst is left partially restored.
+func Restore(ctx context.Context, dir, name string, dst kv.RwDB, logger log.Logger) (*Manifest, error) {
+	var m *Manifest
+	var err error
+	if name == "" {
+		m, err = LatestManifest(dir)
+	} else {
+		m, err = ReadManifest(filepath.Join(dir, name))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := Verify(dir, m); err != nil {
+		return nil, err
+	}
+
+	if err := dst.View(ctx, func(tx kv.Tx) error {
+		for _, t := range m.Tables {
+			if _, ok := dst.AllTables()[t.Name]; !ok {
+				return fmt.Errorf("backup: table %s is unknown to target db", t.Name)
+			}
+			empty, err := isTableEmpty(tx, t.Name)
+			if err != nil {
+				return err
+			}
+			if !empty {
+				return fmt.Errorf("backup: table %s is not empty in target db", t.Name)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	commitEvery := time.NewTicker(5 * time.Minute)
+	defer commitEvery.Stop()
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+	for _, t := range m.Tables {
+		logger.Info("[backup] restore", "table", t.Name, "from", t.Backup)
+		if err := restoreTable(ctx, filepath.Join(dir, t.Backup, tablesDirName, t.Name+tableFileExt), t, dst, commitEvery, logEvery, logger); err != nil {
+			return nil, fmt.Errorf("backup: restore table %s: %w", t.Name, err)
+		}
+	}
+	return m, nil
+}
+
+// restoreTable - appends entries of the table file to the empty table, committing every commitEvery
+func restoreTable(ctx context.Context, path string, t TableManifest, dst kv.RwDB, commitEvery, logEvery *time.Ticker, logger log.Logger) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	r := bufio.NewReaderSize(io.TeeReader(f, h), 1024*1024)
+
+	tx, err := dst.BeginRw(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		tx.Rollback() // tx and c are replaced on commits
+	}()
+	c, err := tx.RwCursor(t.Name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		c.Close()
+	}()
+	dupC, isDupsort := c.(kv.RwCursorDupSort)
+
+	var i uint64
+	for {
+		k, err := readRecord(r)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		v, err := readRecord(r)
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		if isDupsort {
+			err = dupC.AppendDup(k, v)
+		} else {
+			err = c.Append(k, v)
+		}
+		if err != nil {
+			return err
+		}
+		i++
+		if i%100_000 == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-logEvery.C:
+				logger.Info("[backup] Progress", "table", t.Name, "entries", fmt.Sprintf("%d/%d", i, t.Entries))
+			case <-commitEvery.C:
+				c.Close()
+				if err := tx.Commit(); err != nil {
+					return err
+				}
+				if tx, err = dst.BeginRw(ctx); err != nil {
+					return err
+				}
+				if c, err = tx.RwCursor(t.Name); err != nil {
+					return err
+				}
+				dupC, _ = c.(kv.RwCursorDupSort)
+			default:
+			}
+		}
+	}
+	if i != t.Entries {
+		return fmt.Errorf("%d entries, expected %d", i, t.Entries)
+	}
+	if checksum := hex.EncodeToString(h.Sum(nil)); checksum != t.SHA256 {
+		return fmt.Errorf("%w: checksum %s, expected %s", ErrBackupChainBroken, checksum, t.SHA256)
+	}
+	return tx.Commit()
+}
+
+func isTableEmpty(tx kv.Tx, table string) (bool, error) {
+	c, err := tx.Cursor(table)
+	if err != nil {
+		return false, err
+	}
+	defer c.Close()
+	k, _, err := c.First()
+	return k == nil, err
+}
+
+func readRecord(r *bufio.Reader) ([]byte, error) {
+	l, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, l)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return b, nil
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// MissingSnapshotFiles - files of m.SnapshotFiles absent in snapDir or of other size
+func MissingSnapshotFiles(m *Manifest, snapDir string) (missing []string) {
+	for _, f := range m.SnapshotFiles {
+		st, err := os.Stat(filepath.Join(snapDir, filepath.FromSlash(f.Path)))
+		if err != nil || st.Size() != f.Size {
+			missing = append(missing, f.Path)
+		}
+	}
+	return missing
+}
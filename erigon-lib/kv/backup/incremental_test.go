@@ -0,0 +1,149 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+)
+
+func tableContent(t *testing.T, db kv.RoDB, table string) (res [][2]string) {
+	t.Helper()
+	require.NoError(t, db.View(context.Background(), func(tx kv.Tx) error {
+		return tx.ForEach(table, nil, func(k, v []byte) error {
+			res = append(res, [2]string{string(k), string(v)})
+			return nil
+		})
+	}))
+	return res
+}
+
+func TestIncremental(t *testing.T) {
+	ctx, logger := context.Background(), log.New()
+	db := memdb.NewTestDB(t)
+	dir, snapDir := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(snapDir, "v1-000000-000500-headers.seg"), []byte("seg"), 0644))
+
+	require.NoError(t, db.Update(ctx, func(tx kv.RwTx) error {
+		for _, kv2 := range [][2]string{{"a", "1"}, {"b", "2"}} {
+			if err := tx.Put(kv.Headers, []byte(kv2[0]), []byte(kv2[1])); err != nil {
+				return err
+			}
+		}
+		// dupsort table: many values of one key
+		for _, v := range []string{"x", "y", "z"} {
+			if err := tx.Put(kv.PlainState, []byte("k"), []byte(v)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	full, err := Incremental(ctx, db, dir, IncrementalOpts{Label: kv.ChainDB, SnapDir: snapDir}, logger)
+	require.NoError(t, err)
+	require.Equal(t, "000001", full.Name)
+	require.Empty(t, full.Parent)
+	require.Equal(t, []SnapshotFile{{Path: "v1-000000-000500-headers.seg", Size: 3}}, full.SnapshotFiles)
+	headers, ok := full.table(kv.Headers)
+	require.True(t, ok)
+	require.Equal(t, "000001", headers.Backup)
+
+	require.NoError(t, db.Update(ctx, func(tx kv.RwTx) error {
+		return tx.Put(kv.Headers, []byte("c"), []byte("3"))
+	}))
+	incr, err := Incremental(ctx, db, dir, IncrementalOpts{Label: kv.ChainDB, SnapDir: snapDir}, logger)
+	require.NoError(t, err)
+	require.Equal(t, "000002", incr.Name)
+	require.Equal(t, "000001", incr.Parent)
+	for _, tbl := range incr.Tables {
+		switch tbl.Name {
+		case kv.Headers:
+			require.Equal(t, "000002", tbl.Backup)
+		default:
+			require.Equal(t, "000001", tbl.Backup, tbl.Name)
+		}
+	}
+	files, err := os.ReadDir(filepath.Join(dir, "000002", tablesDirName))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	restored := memdb.NewTestDB(t)
+	m, err := Restore(ctx, dir, "", restored, logger)
+	require.NoError(t, err)
+	require.Equal(t, "000002", m.Name)
+	require.Equal(t, tableContent(t, db, kv.Headers), tableContent(t, restored, kv.Headers))
+	require.Equal(t, [][2]string{{"k", "x"}, {"k", "y"}, {"k", "z"}}, tableContent(t, restored, kv.PlainState))
+
+	// restore doesn't overwrite existing data
+	_, err = Restore(ctx, dir, "", restored, logger)
+	require.ErrorContains(t, err, "is not empty in target db")
+
+	// restore of older backup of chain
+	restored = memdb.NewTestDB(t)
+	_, err = Restore(ctx, dir, "000001", restored, logger)
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{{"a", "1"}, {"b", "2"}}, tableContent(t, restored, kv.Headers))
+
+	require.Empty(t, MissingSnapshotFiles(m, snapDir))
+	require.Equal(t, []string{"v1-000000-000500-headers.seg"}, MissingSnapshotFiles(m, t.TempDir()))
+}
+
+func TestRestoreBrokenChain(t *testing.T) {
+	ctx, logger := context.Background(), log.New()
+	db := memdb.NewTestDB(t)
+	dir := t.TempDir()
+	require.NoError(t, db.Update(ctx, func(tx kv.RwTx) error {
+		return tx.Put(kv.Headers, []byte("a"), []byte("1"))
+	}))
+	_, err := Incremental(ctx, db, dir, IncrementalOpts{Label: kv.ChainDB}, logger)
+	require.NoError(t, err)
+	require.NoError(t, db.Update(ctx, func(tx kv.RwTx) error {
+		return tx.Put(kv.HeaderCanonical, []byte("a"), []byte("1"))
+	}))
+	_, err = Incremental(ctx, db, dir, IncrementalOpts{Label: kv.ChainDB}, logger)
+	require.NoError(t, err)
+
+	// table not modified in 000002 is taken from 000001
+	path := filepath.Join(dir, "000001", tablesDirName, kv.Headers+tableFileExt)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	data[len(data)-1] ^= 1
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	_, err = Restore(ctx, dir, "", memdb.NewTestDB(t), logger)
+	require.ErrorIs(t, err, ErrBackupChainBroken)
+
+	require.NoError(t, os.Remove(path))
+	_, err = Restore(ctx, dir, "", memdb.NewTestDB(t), logger)
+	require.ErrorIs(t, err, ErrBackupChainBroken)
+
+	// full backup starts new chain
+	full, err := Incremental(ctx, db, dir, IncrementalOpts{Label: kv.ChainDB, Full: true}, logger)
+	require.NoError(t, err)
+	require.Equal(t, "000003", full.Name)
+	require.Empty(t, full.Parent)
+	_, err = Restore(ctx, dir, "", memdb.NewTestDB(t), logger)
+	require.NoError(t, err)
+}
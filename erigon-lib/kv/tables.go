@@ -346,6 +346,10 @@ const (
 
 	Issuance = "Issuance" // block_num_u64->RLP(issuance+burnt[0 if < london])
 
+	SupplyDelta = "SupplyDelta" // block_num_u64->RLP(types.SupplyDelta), filled by the optional Supply stage
+
+	TraceResults = "TraceResults" // block_num_u64->snappy(json(trace_block result)), filled by the optional TraceResults stage, moved to files by BlockRetire
+
 	StateAccounts   = "StateAccounts"
 	StateStorage    = "StateStorage"
 	StateCode       = "StateCode"
@@ -367,6 +371,7 @@ const (
 	// Downloader
 	BittorrentCompletion = "BittorrentCompletion"
 	BittorrentInfo       = "BittorrentInfo"
+	BittorrentScrub      = "BittorrentScrub" // file_name -> last background integrity check result (in JSON encoding)
 
 	// Domains/History/InvertedIndices
 	// Contants have "Tbl" prefix, to avoid collision with actual Domain names
@@ -501,6 +506,25 @@ const (
 
 	StatesProcessingProgress = "StatesProcessingProgress"
 
+	// EIP-4881 snapshot of the finalized deposit tree
+	DepositSnapshot = "DepositSnapshot"
+
+	// Validator client slashing protection (EIP-3076)
+	// [pubkey + slot] => [signing root]
+	SlashingProtectionBlocks = "SlashingProtectionBlocks"
+	// [pubkey + target epoch] => [source epoch + signing root]
+	SlashingProtectionAttestations = "SlashingProtectionAttestations"
+	// key => value (genesis validators root)
+	SlashingProtectionMetadata = "SlashingProtectionMetadata"
+	// Keys imported through the keymanager API
+	// [pubkey] => [keystore file + password file]
+	ValidatorKeystores = "ValidatorKeystores"
+	// [pubkey] => [remote signer url]
+	ValidatorRemoteKeys = "ValidatorRemoteKeys"
+	// Proposer settings set through the keymanager API
+	// [pubkey] => [fee recipient + gas limit]
+	ValidatorProposerConfig = "ValidatorProposerConfig"
+
 	//Diagnostics tables
 	DiagSystemInfo = "DiagSystemInfo"
 	DiagSyncStages = "DiagSyncStages"
@@ -525,6 +549,8 @@ var (
 	PruneBlocks         = []byte("pruneBlocks")
 	PruneBlocksType     = []byte("pruneBlocksType")
 
+	PruneCommitmentHistory = []byte("pruneCommitmentHistory")
+
 	DBSchemaVersionKey = []byte("dbVersion")
 	GenesisKey         = []byte("genesis")
 
@@ -542,6 +568,7 @@ var (
 	LastNewBlockSeen            = []byte("LastNewBlockSeen") // last seen block hash
 
 	StatesProcessingKey          = []byte("StatesProcessing")
+	DepositSnapshotKey           = []byte("DepositSnapshot")
 	MinimumPrunableStepDomainKey = []byte("MinimumPrunableStepDomainKey")
 )
 
@@ -595,6 +622,8 @@ var ChaindataTables = []string{
 	Epoch,
 	PendingEpoch,
 	Issuance,
+	SupplyDelta,
+	TraceResults,
 	StateAccounts,
 	StateStorage,
 	StateCode,
@@ -704,6 +733,14 @@ var ChaindataTables = []string{
 	ActiveValidatorIndicies,
 	EffectiveBalancesDump,
 	BalancesDump,
+	DepositSnapshot,
+	// Validator client
+	SlashingProtectionBlocks,
+	SlashingProtectionAttestations,
+	SlashingProtectionMetadata,
+	ValidatorKeystores,
+	ValidatorRemoteKeys,
+	ValidatorProposerConfig,
 }
 
 const (
@@ -721,6 +758,7 @@ var SentryTables = []string{}
 var DownloaderTables = []string{
 	BittorrentCompletion,
 	BittorrentInfo,
+	BittorrentScrub,
 }
 var ReconTables = []string{
 	PlainStateR,
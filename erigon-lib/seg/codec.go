@@ -0,0 +1,75 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package seg
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Codec identifies how the words of a compressed file are encoded.
+//
+// CodecPatterns is the original pattern-dictionary + Huffman format. Files written with it
+// carry no codec header - that keeps all existing files readable. Files written with any
+// other codec start with codecMagic followed by a byte with the codec id. The first 8 bytes
+// of a CodecPatterns file are the big-endian words count, which can't start with 0xff.
+type Codec uint8
+
+const (
+	CodecPatterns Codec = iota
+	CodecZstd           // zstd frame per word, with dictionary trained on a sample of the words
+)
+
+var codecMagic = [7]byte{0xff, 'e', 'r', 'i', 's', 'e', 'g'}
+
+// codecHeaderSize - magic + codec id
+const codecHeaderSize = len(codecMagic) + 1
+
+func (c Codec) String() string {
+	switch c {
+	case CodecPatterns:
+		return "patterns"
+	case CodecZstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(c))
+	}
+}
+
+func ParseCodec(s string) (Codec, error) {
+	switch strings.ToLower(s) {
+	case "", "patterns", "default":
+		return CodecPatterns, nil
+	case "zstd":
+		return CodecZstd, nil
+	default:
+		return 0, fmt.Errorf("unknown seg codec: %q, expected one of: patterns, zstd", s)
+	}
+}
+
+// readCodec - returns codec recorded in file header. `ok=false` means file has no codec header (CodecPatterns)
+func readCodec(data []byte) (c Codec, ok bool) {
+	if len(data) < codecHeaderSize || !bytes.Equal(data[:len(codecMagic)], codecMagic[:]) {
+		return CodecPatterns, false
+	}
+	return Codec(data[len(codecMagic)]), true
+}
+
+func codecHeader(c Codec) []byte {
+	return append(codecMagic[:len(codecMagic):len(codecMagic)], byte(c))
+}
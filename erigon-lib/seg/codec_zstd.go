@@ -0,0 +1,356 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package seg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/klauspost/compress/dict"
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/etl"
+)
+
+// CodecZstd file layout (after codec header):
+//
+//	8 bytes - words count (big-endian)
+//	8 bytes - empty words count
+//	8 bytes - dictionary size, then dictionary itself (zstd format, may be empty)
+//	words: uvarint(2*len(payload)+raw) + payload.
+//
+// payload is a zstd frame if lowest bit of length prefix is zero, and the word itself otherwise.
+// Words added by AddUncompressedWord, empty words and words which zstd can't shrink are stored as-is.
+const (
+	zstdLevel        = zstd.SpeedBetterCompression
+	zstdDictMaxSize  = 110 * 1024 // same as default of `zstd --train`
+	zstdDictMinInput = 64 * 1024  // too few samples - no use of dictionary
+	zstdSamplesLimit = 16 * 1024 * 1024
+	// fixed id: keeps files reproducible and costs just 1 byte in each frame header
+	zstdDictID    = 1
+	zstdBatchSize = 64 * 1024 // words encoded in parallel at once
+)
+
+// zstdSampler - keeps evenly spread sample of words for dictionary training. When sample is over zstdSamplesLimit
+// every second sample is dropped and sampling step is doubled, so memory stays bounded for any amount of words.
+type zstdSampler struct {
+	samples [][]byte
+	size    int
+	step    uint64
+	seen    uint64
+}
+
+func (s *zstdSampler) add(word []byte) {
+	if s.step == 0 {
+		s.step = 1
+	}
+	s.seen++
+	if (s.seen-1)%s.step != 0 || len(word) == 0 {
+		return
+	}
+	s.samples = append(s.samples, common.Copy(word))
+	s.size += len(word)
+	for s.size > zstdSamplesLimit {
+		j := 0
+		s.size = 0
+		for i := 0; i < len(s.samples); i += 2 {
+			s.samples[j] = s.samples[i]
+			s.size += len(s.samples[j])
+			j++
+		}
+		clear(s.samples[j:])
+		s.samples = s.samples[:j]
+		s.step *= 2
+	}
+}
+
+func trainZstdDict(s *zstdSampler) ([]byte, error) {
+	if s.size < zstdDictMinInput {
+		return nil, nil
+	}
+	return dict.BuildZstdDict(s.samples, dict.Options{
+		MaxDictSize: zstdDictMaxSize,
+		HashBytes:   6,
+		ZstdDictID:  zstdDictID,
+		ZstdLevel:   zstdLevel,
+	})
+}
+
+type zstdBatchWord struct {
+	word, frame []byte
+	compressed  bool
+}
+
+func (w *zstdBatchWord) encode(enc *zstd.Encoder) {
+	w.frame = w.frame[:0]
+	if !w.compressed || len(w.word) == 0 {
+		return
+	}
+	w.frame = enc.EncodeAll(w.word, w.frame)
+}
+
+func (w *zstdBatchWord) write(bw *bufio.Writer, numBuf []byte) error {
+	payload, raw := w.frame, uint64(0)
+	if len(w.frame) == 0 || len(w.frame) >= len(w.word) {
+		payload, raw = w.word, 1
+	}
+	n := binary.PutUvarint(numBuf, 2*uint64(len(payload))+raw)
+	if _, err := bw.Write(numBuf[:n]); err != nil {
+		return err
+	}
+	_, err := bw.Write(payload)
+	return err
+}
+
+func (c *Compressor) compressZstd() error {
+	t := time.Now()
+	dictionary, err := trainZstdDict(&c.zstdSampler)
+	if err != nil {
+		c.logger.Debug(fmt.Sprintf("[%s] zstd dictionary training failed, compressing without dictionary", c.logPrefix), "err", err)
+		dictionary = nil
+	}
+	c.zstdSampler = zstdSampler{}
+
+	opts := []zstd.EOption{zstd.WithEncoderLevel(zstdLevel), zstd.WithEncoderCRC(false), zstd.WithEncoderConcurrency(c.workers)}
+	if len(dictionary) > 0 {
+		opts = append(opts, zstd.WithEncoderDict(dictionary))
+	}
+	enc, err := zstd.NewWriter(nil, opts...)
+	if err != nil {
+		return err
+	}
+	defer enc.Close()
+
+	defer os.Remove(c.tmpOutFilePath)
+	cf, err := os.Create(c.tmpOutFilePath)
+	if err != nil {
+		return err
+	}
+	defer cf.Close()
+	bw := bufio.NewWriterSize(cf, 2*etl.BufIOSize)
+
+	header := make([]byte, codecHeaderSize+24)
+	copy(header, codecHeader(CodecZstd))
+	binary.BigEndian.PutUint64(header[codecHeaderSize:], c.wordsCount)
+	// empty words count is patched after all words are written
+	binary.BigEndian.PutUint64(header[codecHeaderSize+16:], uint64(len(dictionary)))
+	if _, err = bw.Write(header); err != nil {
+		return err
+	}
+	if _, err = bw.Write(dictionary); err != nil {
+		return err
+	}
+
+	var emptyWords, inBatch int
+	batch := make([]zstdBatchWord, zstdBatchSize)
+	numBuf := make([]byte, binary.MaxVarintLen64)
+	flush := func() error {
+		words := batch[:inBatch]
+		inBatch = 0
+		g, ctx := errgroup.WithContext(c.ctx)
+		chunk := (len(words) + c.workers - 1) / c.workers
+		for from := 0; from < len(words); from += chunk {
+			part := words[from:min(from+chunk, len(words))]
+			g.Go(func() error {
+				for i := range part {
+					part[i].encode(enc)
+				}
+				return ctx.Err()
+			})
+		}
+		if err := g.Wait(); err != nil {
+			return err
+		}
+		for i := range words {
+			if err := words[i].write(bw, numBuf); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err = c.uncompressedFile.ForEach(func(v []byte, compressed bool) error {
+		if len(v) == 0 {
+			emptyWords++
+		}
+		w := &batch[inBatch]
+		w.word, w.compressed = append(w.word[:0], v...), compressed
+		inBatch++
+		if inBatch < len(batch) {
+			return nil
+		}
+		return flush()
+	}); err != nil {
+		return err
+	}
+	if err = flush(); err != nil {
+		return err
+	}
+	if err = bw.Flush(); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint64(numBuf[:8], uint64(emptyWords))
+	if _, err = cf.WriteAt(numBuf[:8], int64(codecHeaderSize+8)); err != nil {
+		return err
+	}
+	return c.finish(cf, t)
+}
+
+func (d *Decompressor) openZstd() error {
+	pos := uint64(codecHeaderSize + 24)
+	if uint64(d.size) < pos {
+		return &ErrCompressedFileCorrupted{FileName: d.FileName1, Reason: "zstd header is truncated"}
+	}
+	d.wordsCount = binary.BigEndian.Uint64(d.data[codecHeaderSize:])
+	d.emptyWordsCount = binary.BigEndian.Uint64(d.data[codecHeaderSize+8:])
+	dictSize := binary.BigEndian.Uint64(d.data[codecHeaderSize+16:])
+	if pos+dictSize > uint64(d.size) {
+		return &ErrCompressedFileCorrupted{FileName: d.FileName1, Reason: fmt.Sprintf("zstd dictSize=%d overflows file size of %d", dictSize, d.size)}
+	}
+	opts := []zstd.DOption{zstd.WithDecoderConcurrency(0)}
+	if dictSize > 0 {
+		opts = append(opts, zstd.WithDecoderDicts(d.data[pos:pos+dictSize]))
+	}
+	var err error
+	if d.zstd, err = zstd.NewReader(nil, opts...); err != nil {
+		return &ErrCompressedFileCorrupted{FileName: d.FileName1, Reason: err.Error()}
+	}
+	d.wordsStart = pos + dictSize
+	return nil
+}
+
+// zstdWord - returns stored payload of the word at current offset and offset of the next word
+func (g *Getter) zstdWord() (payload []byte, frame bool, next uint64) {
+	l, n := binary.Uvarint(g.data[g.dataP:])
+	if n <= 0 {
+		panic(fmt.Sprintf("file: %s, invalid word header at offset %d", g.fName, g.dataP))
+	}
+	start := g.dataP + uint64(n)
+	next = start + l>>1
+	return g.data[start:next], l&1 == 0, next
+}
+
+func (g *Getter) zstdDecode(dst, payload []byte, frame bool) []byte {
+	if !frame {
+		return append(dst, payload...)
+	}
+	out, err := g.zstd.DecodeAll(payload, dst)
+	if err != nil {
+		panic(fmt.Sprintf("file: %s, offset: %d, %s", g.fName, g.dataP, err))
+	}
+	return out
+}
+
+// zstdPeek - word at current offset, without moving. Result is valid until next call.
+func (g *Getter) zstdPeek() (word []byte, next uint64) {
+	payload, frame, next := g.zstdWord()
+	if !frame {
+		return payload, next
+	}
+	g.zbuf = g.zstdDecode(g.zbuf[:0], payload, frame)
+	return g.zbuf, next
+}
+
+func (g *Getter) nextZstd(buf []byte) ([]byte, uint64) {
+	payload, frame, next := g.zstdWord()
+	if buf == nil { // nil - is the marker of "something not found"
+		buf = []byte{}
+	}
+	buf = g.zstdDecode(buf, payload, frame)
+	g.dataP = next
+	return buf, next
+}
+
+func (g *Getter) nextUncompressedZstd() ([]byte, uint64) {
+	payload, frame, next := g.zstdWord()
+	if frame {
+		payload = g.zstdDecode(nil, payload, frame)
+	}
+	g.dataP = next
+	return payload, next
+}
+
+func (g *Getter) skipZstd() (uint64, int) {
+	payload, frame, next := g.zstdWord()
+	wordLen := len(payload)
+	if frame {
+		var h zstd.Header
+		if err := h.Decode(payload); err == nil && h.HasFCS {
+			wordLen = int(h.FrameContentSize)
+		} else {
+			g.zbuf = g.zstdDecode(g.zbuf[:0], payload, frame)
+			wordLen = len(g.zbuf)
+		}
+	}
+	g.dataP = next
+	return next, wordLen
+}
+
+func (g *Getter) fastNextZstd(buf []byte) ([]byte, uint64) {
+	payload, frame, next := g.zstdWord()
+	buf = g.zstdDecode(buf[:0], payload, frame)
+	g.dataP = next
+	return buf, next
+}
+
+func (g *Getter) matchZstd(buf []byte) int {
+	word, next := g.zstdPeek()
+	if len(buf) != len(word) {
+		if len(buf) < len(word) {
+			return -1
+		}
+		return 1
+	}
+	cmp := bytes.Compare(buf, word)
+	if cmp == 0 {
+		g.dataP = next
+	}
+	return cmp
+}
+
+func (g *Getter) matchCmpZstd(buf []byte) int {
+	word, next := g.zstdPeek()
+	cmp := bytes.Compare(buf, word)
+	if cmp == 0 {
+		g.dataP = next
+	}
+	return cmp
+}
+
+func (g *Getter) matchPrefixZstd(prefix []byte) bool {
+	word, _ := g.zstdPeek()
+	return bytes.HasPrefix(word, prefix)
+}
+
+func (g *Getter) matchPrefixCmpZstd(prefix []byte) int {
+	if len(prefix) == 0 {
+		return 0
+	}
+	word, _ := g.zstdPeek()
+	if len(word) == 0 {
+		return 1
+	}
+	if len(prefix) > len(word) {
+		return bytes.Compare(prefix, word)
+	}
+	return bytes.Compare(prefix, word[:len(prefix)])
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package seg
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+)
+
+// prepareZstdDict - lorem words + enough repetitive words to train dictionary. Every 3rd word is uncompressed, every 7th is empty
+func prepareZstdDict(t testing.TB, words int) (*Decompressor, [][]byte) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "compressed")
+	c, err := NewCompressor(context.Background(), t.Name(), file, tmpDir, 1, 2, log.LvlDebug, log.New())
+	require.NoError(t, err)
+	defer c.Close()
+	c.SetCodec(CodecZstd)
+	c.DisableFsync()
+
+	var expect [][]byte
+	for i := 0; i < words; i++ {
+		var w []byte
+		if i%7 != 0 {
+			w = []byte(fmt.Sprintf("%s %d %s", loremStrings[i%len(loremStrings)], i, loremStrings[(i*13)%len(loremStrings)]))
+		}
+		expect = append(expect, w)
+		if i%3 == 0 {
+			err = c.AddUncompressedWord(w)
+		} else {
+			err = c.AddWord(w)
+		}
+		require.NoError(t, err)
+	}
+	require.NoError(t, c.Compress())
+
+	d, err := NewDecompressor(file)
+	require.NoError(t, err)
+	return d, expect
+}
+
+func TestZstdCodec(t *testing.T) {
+	d, expect := prepareZstdDict(t, 10_000)
+	defer d.Close()
+	require.Equal(t, CodecZstd, d.Codec())
+	require.Equal(t, len(expect), d.Count())
+	require.Equal(t, (len(expect)+6)/7, d.EmptyWordsCount())
+	dictSize := binary.BigEndian.Uint64(d.data[codecHeaderSize+16:])
+	require.NotZero(t, dictSize)
+
+	g := d.MakeGetter()
+	var offsets []uint64
+	for i := 0; g.HasNext(); i++ {
+		offsets = append(offsets, g.dataP)
+		var w []byte
+		if i%3 == 0 {
+			w, _ = g.NextUncompressed()
+		} else {
+			w, _ = g.Next(nil)
+		}
+		require.Equal(t, string(expect[i]), string(w), i)
+	}
+	require.Equal(t, len(expect), len(offsets))
+
+	// random access by offset, as .idx files do
+	buf := make([]byte, 0, 128)
+	for _, i := range []int{9_999, 0, 5_001, 7, 42} {
+		g.Reset(offsets[i])
+		w, _ := g.FastNext(buf)
+		require.Equal(t, string(expect[i]), string(w))
+
+		g.Reset(offsets[i])
+		next, l := g.Skip()
+		require.Equal(t, len(expect[i]), l)
+		if i+1 < len(offsets) {
+			require.Equal(t, offsets[i+1], next)
+		}
+	}
+
+	g.Reset(offsets[1])
+	require.True(t, g.MatchPrefix(expect[1][:5]))
+	require.False(t, g.MatchPrefix([]byte("nope")))
+	require.Equal(t, 0, g.MatchPrefixCmp(expect[1][:5]))
+	require.Equal(t, offsets[1], g.dataP)
+	require.Equal(t, -1, g.MatchCmp([]byte("a")))
+	require.Equal(t, offsets[1], g.dataP)
+	require.Equal(t, 0, g.Match(expect[1]))
+	require.Equal(t, offsets[2], g.dataP)
+	require.Equal(t, 0, g.MatchCmp(expect[2]))
+	require.Equal(t, offsets[3], g.dataP)
+}
+
+func TestZstdCodecFewWords(t *testing.T) {
+	// not enough data to train dictionary - still must work
+	d, expect := prepareZstdDict(t, 5)
+	defer d.Close()
+	require.Equal(t, uint64(0), binary.BigEndian.Uint64(d.data[codecHeaderSize+16:]))
+	g := d.MakeGetter()
+	for i := 0; g.HasNext(); i++ {
+		w, _ := g.Next(nil)
+		require.Equal(t, string(expect[i]), string(w))
+	}
+
+	d, _ = prepareZstdDict(t, 0)
+	defer d.Close()
+	require.Equal(t, 0, d.Count())
+	require.False(t, d.MakeGetter().HasNext())
+}
+
+func TestCodecHeader(t *testing.T) {
+	// files without codec header keep working
+	d := prepareLoremDict(t)
+	defer d.Close()
+	require.Equal(t, CodecPatterns, d.Codec())
+	_, ok := readCodec(d.data)
+	require.False(t, ok)
+
+	c, ok := readCodec(append(codecHeader(CodecZstd), make([]byte, 24)...))
+	require.True(t, ok)
+	require.Equal(t, CodecZstd, c)
+
+	for _, name := range []string{"", "patterns", "zstd"} {
+		c, err := ParseCodec(name)
+		require.NoError(t, err)
+		if name != "" {
+			require.Equal(t, name, c.String())
+		}
+	}
+	_, err := ParseCodec("lz4")
+	require.Error(t, err)
+}
+
+func TestZstdSampler(t *testing.T) {
+	var s zstdSampler
+	w := make([]byte, 1024)
+	for i := 0; i < 2*zstdSamplesLimit/len(w); i++ {
+		s.add(w)
+	}
+	require.LessOrEqual(t, s.size, zstdSamplesLimit)
+	require.Equal(t, uint64(2), s.step)
+	require.Equal(t, s.size, len(s.samples)*len(w))
+}
@@ -73,6 +73,8 @@ type Compressor struct {
 	trace            bool
 	logger           log.Logger
 	noFsync          bool // fsync is enabled by default, but tests can manually disable
+	codec            Codec
+	zstdSampler      zstdSampler // words sample for CodecZstd dictionary training
 }
 
 func NewCompressor(ctx context.Context, logPrefix, outputFile, tmpDir string, minPatternScore uint64, workers int, lvl log.Lvl, logger log.Logger) (*Compressor, error) {
@@ -129,6 +131,10 @@ func (c *Compressor) Close() {
 func (c *Compressor) SetTrace(trace bool) { c.trace = trace }
 func (c *Compressor) Workers() int        { return c.workers }
 
+// SetCodec - must be called before adding first word
+func (c *Compressor) SetCodec(codec Codec) { c.codec = codec }
+func (c *Compressor) Codec() Codec         { return c.codec }
+
 func (c *Compressor) Count() int { return int(c.wordsCount) }
 
 func (c *Compressor) AddWord(word []byte) error {
@@ -139,6 +145,10 @@ func (c *Compressor) AddWord(word []byte) error {
 	}
 
 	c.wordsCount++
+	if c.codec == CodecZstd {
+		c.zstdSampler.add(word)
+		return c.uncompressedFile.Append(word)
+	}
 	l := 2*len(word) + 2
 	if c.superstringLen+l > superstringLimit {
 		if c.superstringCount%samplingFactor == 0 {
@@ -184,6 +194,14 @@ func (c *Compressor) Compress() error {
 	close(c.superstrings)
 	c.wg.Wait()
 
+	switch c.codec {
+	case CodecPatterns:
+	case CodecZstd:
+		return c.compressZstd()
+	default:
+		return fmt.Errorf("unsupported codec: %s", c.codec)
+	}
+
 	if c.lvl < log.LvlTrace {
 		c.logger.Log(c.lvl, fmt.Sprintf("[%s] BuildDict start", c.logPrefix), "workers", c.workers)
 	}
@@ -213,6 +231,11 @@ func (c *Compressor) Compress() error {
 	if err := compressWithPatternCandidates(c.ctx, c.trace, c.logPrefix, c.tmpOutFilePath, cf, c.uncompressedFile, c.workers, db, c.lvl, c.logger); err != nil {
 		return err
 	}
+	return c.finish(cf, t)
+}
+
+// finish - makes compressed file visible under its final name
+func (c *Compressor) finish(cf *os.File, t time.Time) (err error) {
 	if err = c.fsync(cf); err != nil {
 		return err
 	}
@@ -230,7 +253,7 @@ func (c *Compressor) Compress() error {
 
 	_, fName := filepath.Split(c.outputFile)
 	if c.lvl < log.LvlTrace {
-		c.logger.Log(c.lvl, fmt.Sprintf("[%s] Compress", c.logPrefix), "took", time.Since(t), "ratio", c.Ratio, "file", fName)
+		c.logger.Log(c.lvl, fmt.Sprintf("[%s] Compress", c.logPrefix), "took", time.Since(t), "ratio", c.Ratio, "codec", c.codec, "file", fName)
 	}
 	return nil
 }
@@ -33,6 +33,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/log/v3"
 
 	"github.com/c2h5oh/datasize"
+	"github.com/klauspost/compress/zstd"
 
 	"github.com/ledgerwatch/erigon-lib/common/dbg"
 	"github.com/ledgerwatch/erigon-lib/mmap"
@@ -132,6 +133,8 @@ type Decompressor struct {
 	modTime         time.Time
 	wordsCount      uint64
 	emptyWordsCount uint64
+	codec           Codec
+	zstd            *zstd.Decoder // CodecZstd only, safe for concurrent use
 
 	filePath, FileName1 string
 
@@ -219,6 +222,21 @@ func NewDecompressor(compressedFilePath string) (*Decompressor, error) {
 	d.data = d.mmapHandle1[:d.size]
 	defer d.EnableReadAhead().DisableReadAhead() //speedup opening on slow drives
 
+	if codec, ok := readCodec(d.data); ok {
+		d.codec = codec
+		switch codec {
+		case CodecZstd:
+			err = d.openZstd()
+		default:
+			err = &ErrCompressedFileCorrupted{FileName: fName, Reason: fmt.Sprintf("unsupported codec %s", codec)}
+		}
+		if err != nil {
+			return nil, err
+		}
+		closeDecompressor = false
+		return d, nil
+	}
+
 	d.wordsCount = binary.BigEndian.Uint64(d.data[:8])
 	d.emptyWordsCount = binary.BigEndian.Uint64(d.data[8:16])
 
@@ -450,6 +468,10 @@ func (d *Decompressor) IsOpen() bool {
 
 func (d *Decompressor) Close() {
 	if d.f != nil {
+		if d.zstd != nil {
+			d.zstd.Close()
+			d.zstd = nil
+		}
 		if err := mmap.Munmap(d.mmapHandle1, d.mmapHandle2); err != nil {
 			log.Log(dbg.FileCloseLogLevel, "unmap", "err", err, "file", d.FileName(), "stack", dbg.Stack())
 		}
@@ -464,6 +486,7 @@ func (d *Decompressor) Close() {
 }
 
 func (d *Decompressor) FilePath() string { return d.filePath }
+func (d *Decompressor) Codec() Codec     { return d.codec }
 func (d *Decompressor) FileName() string { return d.FileName1 }
 
 // WithReadAhead - Expect read in sequential order. (Hence, pages in the given range can be aggressively read ahead, and may be freed soon after they are accessed.)
@@ -545,6 +568,9 @@ type Getter struct {
 	dataP       uint64
 	dataBit     int // Value 0..7 - position of the bit
 	trace       bool
+
+	zstd *zstd.Decoder // not nil for CodecZstd files
+	zbuf []byte        // CodecZstd: buffer for words which are decoded only to compare
 }
 
 func (g *Getter) Trace(t bool)     { g.trace = t }
@@ -649,6 +675,7 @@ func (d *Decompressor) MakeGetter() *Getter {
 		data:        d.data[d.wordsStart:],
 		patternDict: d.dict,
 		fName:       d.FileName1,
+		zstd:        d.zstd,
 	}
 }
 
@@ -670,6 +697,9 @@ func (g *Getter) Next(buf []byte) ([]byte, uint64) {
 			panic(fmt.Sprintf("file: %s, %s, %s", g.fName, rec, dbg.Stack()))
 		}
 	}()
+	if g.zstd != nil {
+		return g.nextZstd(buf)
+	}
 	savePos := g.dataP
 	wordLen := g.nextPos(true)
 	wordLen-- // because when create huffman tree we do ++ , because 0 is terminator
@@ -741,6 +771,9 @@ func (g *Getter) NextUncompressed() ([]byte, uint64) {
 			panic(fmt.Sprintf("file: %s, %s, %s", g.fName, rec, dbg.Stack()))
 		}
 	}()
+	if g.zstd != nil {
+		return g.nextUncompressedZstd()
+	}
 	wordLen := g.nextPos(true)
 	wordLen-- // because when create huffman tree we do ++ , because 0 is terminator
 	if wordLen == 0 {
@@ -762,6 +795,9 @@ func (g *Getter) NextUncompressed() ([]byte, uint64) {
 
 // Skip moves offset to the next word and returns the new offset and the length of the word.
 func (g *Getter) Skip() (uint64, int) {
+	if g.zstd != nil {
+		return g.skipZstd()
+	}
 	l := g.nextPos(true)
 	l-- // because when create huffman tree we do ++ , because 0 is terminator
 	if l == 0 {
@@ -799,6 +835,9 @@ func (g *Getter) Skip() (uint64, int) {
 }
 
 func (g *Getter) SkipUncompressed() (uint64, int) {
+	if g.zstd != nil {
+		return g.skipZstd()
+	}
 	wordLen := g.nextPos(true)
 	wordLen-- // because when create huffman tree we do ++ , because 0 is terminator
 	if wordLen == 0 {
@@ -825,6 +864,9 @@ func (g *Getter) SkipUncompressed() (uint64, int) {
 //
 //	0 if they are equal.
 func (g *Getter) Match(buf []byte) int {
+	if g.zstd != nil {
+		return g.matchZstd(buf)
+	}
 	savePos := g.dataP
 	wordLen := g.nextPos(true)
 	wordLen-- // because when create huffman tree we do ++ , because 0 is terminator
@@ -918,6 +960,9 @@ func (g *Getter) Match(buf []byte) int {
 
 // MatchPrefix only checks if the word at the current offset has a buf prefix. Does not move offset to the next word.
 func (g *Getter) MatchPrefix(prefix []byte) bool {
+	if g.zstd != nil {
+		return g.matchPrefixZstd(prefix)
+	}
 	savePos := g.dataP
 	defer func() {
 		g.dataP, g.dataBit = savePos, 0
@@ -1001,6 +1046,9 @@ func (g *Getter) MatchPrefix(prefix []byte) bool {
 // MatchCmp lexicographically compares given buf with the word at the current offset in the file.
 // returns 0 if buf == word, -1 if buf < word, 1 if buf > word
 func (g *Getter) MatchCmp(buf []byte) int {
+	if g.zstd != nil {
+		return g.matchCmpZstd(buf)
+	}
 	savePos := g.dataP
 	wordLen := g.nextPos(true)
 	wordLen-- // because when create huffman tree we do ++ , because 0 is terminator
@@ -1063,6 +1111,9 @@ func (g *Getter) MatchCmp(buf []byte) int {
 // MatchPrefixCmp lexicographically compares given prefix with the word at the current offset in the file.
 // returns 0 if buf == word, -1 if buf < word, 1 if buf > word
 func (g *Getter) MatchPrefixCmp(prefix []byte) int {
+	if g.zstd != nil {
+		return g.matchPrefixCmpZstd(prefix)
+	}
 	savePos := g.dataP
 	defer func() {
 		g.dataP, g.dataBit = savePos, 0
@@ -1129,6 +1180,9 @@ func (g *Getter) MatchPrefixCmp(prefix []byte) int {
 }
 
 func (g *Getter) MatchPrefixUncompressed(prefix []byte) int {
+	if g.zstd != nil {
+		return g.matchPrefixCmpZstd(prefix)
+	}
 	savePos := g.dataP
 	defer func() {
 		g.dataP, g.dataBit = savePos, 0
@@ -1165,6 +1219,9 @@ func (g *Getter) FastNext(buf []byte) ([]byte, uint64) {
 			panic(fmt.Sprintf("file: %s, %s, %s", g.fName, rec, dbg.Stack()))
 		}
 	}()
+	if g.zstd != nil {
+		return g.fastNextZstd(buf)
+	}
 
 	savePos := g.dataP
 	wordLen := g.nextPos(true)
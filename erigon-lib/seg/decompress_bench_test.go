@@ -145,3 +145,30 @@ func BenchmarkDecompressTorrent(t *testing.B) {
 		}
 	})
 }
+
+func BenchmarkDecompressNextZstd(b *testing.B) {
+	d, _ := prepareZstdDict(b, 10_000)
+	defer d.Close()
+	g := d.MakeGetter()
+	buf := make([]byte, 0, 128)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf, _ = g.Next(buf[:0])
+		if !g.HasNext() {
+			g.Reset(0)
+		}
+	}
+}
+
+func BenchmarkDecompressSkipZstd(b *testing.B) {
+	d, _ := prepareZstdDict(b, 10_000)
+	defer d.Close()
+	g := d.MakeGetter()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = g.Skip()
+		if !g.HasNext() {
+			g.Reset(0)
+		}
+	}
+}
@@ -71,6 +71,7 @@ type Aggregator struct {
 	mergeWorkers           int // usually 1
 
 	commitmentValuesTransform bool // enables squeezing commitment values in CommitmentDomain
+	keepCommitmentHistory     bool // enables history of CommitmentDomain, needed for proofs of past blocks
 
 	// To keep DB small - need move data to small files ASAP.
 	// It means goroutine which creating small files - can't be locked by merge or indexing.
@@ -386,6 +387,18 @@ func (a *Aggregator) EnableHistory(name kv.Domain) *Aggregator {
 	return a
 }
 
+// KeepCommitmentHistory makes SharedDomains write history of CommitmentDomain, which is required to build
+// proofs and witnesses for past blocks. Disabled by default: every block then also writes all trie branches it
+// updated into history, which grows DB by roughly the size of CommitmentDomain itself. This history has no files,
+// it stays in DB: regular prune doesn't touch it, only PruneCommitHistory below the window given by caller.
+func (a *Aggregator) KeepCommitmentHistory(keep bool) *Aggregator {
+	a.keepCommitmentHistory = keep
+	if keep {
+		a.d[kv.CommitmentDomain].History.keepRecentTxnInDB = math.MaxUint64
+	}
+	return a
+}
+
 func (a *Aggregator) HasBackgroundFilesBuild() bool { return a.ps.Has() }
 func (a *Aggregator) BackgroundProgress() string    { return a.ps.String() }
 
@@ -1166,17 +1179,12 @@ func (as *AggregatorPruneStat) Accumulate(other *AggregatorPruneStat) {
 	}
 }
 
-// temporal function to prune history straight after commitment is done - reduce history size in db until we build
-// pruning in background. This helps on chain-tip performance (while full pruning is not available we can prune at least commit)
-func (ac *AggregatorRoTx) PruneCommitHistory(ctx context.Context, tx kv.RwTx, logEvery *time.Ticker) error {
+// PruneCommitHistory prunes history of CommitmentDomain before untilTx. It's written only if kept for proofs and
+// witnesses of past blocks (see KeepCommitmentHistory) and never goes to files, so caller prunes it straight after
+// commitment is done, below the window of blocks it must be kept for.
+func (ac *AggregatorRoTx) PruneCommitHistory(ctx context.Context, tx kv.RwTx, untilTx uint64, logEvery *time.Ticker) error {
 	cd := ac.d[kv.CommitmentDomain]
-	if cd.ht.h.historyDisabled {
-		return nil
-	}
-
-	txFrom := uint64(0)
-	canHist, txTo := cd.ht.canPruneUntil(tx, math.MaxUint64)
-	if dbg.NoPrune() || !canHist {
+	if cd.ht.h.historyDisabled || untilTx == 0 || dbg.NoPrune() {
 		return nil
 	}
 
@@ -1186,12 +1194,12 @@ func (ac *AggregatorRoTx) PruneCommitHistory(ctx context.Context, tx kv.RwTx, lo
 	}
 	defer mxPruneTookAgg.ObserveDuration(time.Now())
 
-	stat, err := cd.ht.Prune(ctx, tx, txFrom, txTo, math.MaxUint64, true, logEvery)
+	stat, err := cd.ht.Prune(ctx, tx, 0, untilTx, math.MaxUint64, true, logEvery)
 	if err != nil {
 		return err
 	}
 
-	ac.a.logger.Info("commitment history backpressure pruning", "pruned", stat.String())
+	ac.a.logger.Debug("commitment history pruning", "until_txnum", untilTx, "pruned", stat.String())
 	return nil
 }
 
@@ -1272,6 +1272,127 @@ func TestAggregatorV3_SharedDomains(t *testing.T) {
 	}
 }
 
+func TestAggregatorV3_ProofAsOf(t *testing.T) {
+	db, agg := testDbAndAggregatorv3(t, 20)
+	agg.KeepCommitmentHistory(true)
+	ctx := context.Background()
+
+	ac := agg.BeginFilesRo()
+	defer ac.Close()
+
+	rwTx, err := db.BeginRw(ctx)
+	require.NoError(t, err)
+	defer rwTx.Rollback()
+
+	domains, err := NewSharedDomains(WrapTxWithCtx(rwTx, ac), log.New())
+	require.NoError(t, err)
+	defer domains.Close()
+
+	keys, _ := generateInputData(t, length.Addr, 16, 30)
+	slot := make([]byte, length.Hash)
+	slot[length.Hash-1] = 1
+
+	const blocks = 10
+	roots := make([][]byte, blocks+1)
+	for i := uint64(1); i <= blocks; i++ {
+		domains.SetTxNum(i)
+		for j := range keys {
+			buf := types.EncodeAccountBytesV3(i, uint256.NewInt(i*100_000+uint64(j)), nil, 0)
+			prev, step, err := domains.DomainGet(kv.AccountsDomain, keys[j], nil)
+			require.NoError(t, err)
+			require.NoError(t, domains.DomainPut(kv.AccountsDomain, keys[j], nil, buf, prev, step))
+		}
+		prev, step, err := domains.DomainGet(kv.StorageDomain, keys[0], slot)
+		require.NoError(t, err)
+		require.NoError(t, domains.DomainPut(kv.StorageDomain, keys[0], slot, []byte{byte(i)}, prev, step))
+
+		roots[i], err = domains.ComputeCommitment(ctx, true, i, "")
+		require.NoError(t, err)
+	}
+	require.NoError(t, domains.Flush(ctx, rwTx))
+	domains.Close()
+	ac.Close()
+
+	ac = agg.BeginFilesRo()
+	defer ac.Close()
+	for i := uint64(1); i <= blocks; i++ {
+		for _, key := range keys[:3] {
+			proof, err := ac.ProofAsOf(rwTx, i+1, key, [][]byte{slot})
+			require.NoError(t, err)
+			require.EqualValues(t, roots[i], proof.RootHash, "block %d", i)
+			require.NotEmpty(t, proof.AccountProof)
+			require.Len(t, proof.StorageProofs, 1)
+		}
+	}
+
+	_, err = ac.ProofAsOf(rwTx, 1, keys[0], nil)
+	require.Error(t, err)
+
+	agg.KeepCommitmentHistory(false)
+	_, err = ac.ProofAsOf(rwTx, blocks+1, keys[0], nil)
+	require.ErrorIs(t, err, ErrCommitmentHistoryDisabled)
+}
+
+func TestAggregatorV3_CommitmentHistoryRetention(t *testing.T) {
+	const step = 100_000
+	db, agg := testDbAndAggregatorv3(t, step)
+	agg.KeepCommitmentHistory(true)
+	ctx := context.Background()
+
+	rwTx, err := db.BeginRw(ctx)
+	require.NoError(t, err)
+	defer rwTx.Rollback()
+	ac := agg.BeginFilesRo()
+	domains, err := NewSharedDomains(WrapTxWithCtx(rwTx, ac), log.New())
+	require.NoError(t, err)
+
+	keys, _ := generateInputData(t, length.Addr, 16, 10)
+	// blocks are far more than 100_000 txns apart, so block 1 is older than the recent txns kept by histories without files
+	txNums := []uint64{1, 3*step + 1, 6*step + 1}
+	roots := make([][]byte, len(txNums))
+	for i, txNum := range txNums {
+		domains.SetTxNum(txNum)
+		for j := range keys {
+			buf := types.EncodeAccountBytesV3(uint64(i), uint256.NewInt(uint64(i*1000+j)), nil, 0)
+			prev, step, err := domains.DomainGet(kv.AccountsDomain, keys[j], nil)
+			require.NoError(t, err)
+			require.NoError(t, domains.DomainPut(kv.AccountsDomain, keys[j], nil, buf, prev, step))
+		}
+		roots[i], err = domains.ComputeCommitment(ctx, true, uint64(i+1), "")
+		require.NoError(t, err)
+	}
+	require.NoError(t, domains.Flush(ctx, rwTx))
+	domains.Close()
+	ac.Close()
+	require.NoError(t, rwTx.Commit())
+
+	require.NoError(t, agg.BuildFiles(txNums[len(txNums)-1]+1))
+	rwTx, err = db.BeginRw(ctx)
+	require.NoError(t, err)
+	defer rwTx.Rollback()
+	ac = agg.BeginFilesRo()
+	defer ac.Close()
+	for i := 0; i < 10; i++ {
+		_, err = ac.PruneSmallBatches(ctx, time.Minute, rwTx)
+		require.NoError(t, err)
+	}
+
+	proofsAsOf := func() {
+		t.Helper()
+		for i, txNum := range txNums {
+			proof, err := ac.ProofAsOf(rwTx, txNum+1, keys[0], nil)
+			require.NoError(t, err)
+			require.EqualValues(t, roots[i], proof.RootHash, "block %d", i+1)
+		}
+	}
+	proofsAsOf() // regular prune keeps commitment history
+
+	// window of blocks starts after block 1: its history is pruned, the rest still serves all blocks
+	require.NoError(t, ac.PruneCommitHistory(ctx, rwTx, txNums[1], nil))
+	require.GreaterOrEqual(t, ac.d[kv.CommitmentDomain].ht.iit.ii.minTxNumInDB(rwTx), txNums[1])
+	proofsAsOf()
+}
+
 // also useful to decode given input into v3 account
 func Test_helper_decodeAccountv3Bytes(t *testing.T) {
 	input, err := hex.DecodeString("000114000101")
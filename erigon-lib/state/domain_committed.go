@@ -19,16 +19,27 @@ package state
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"strings"
 
+	"golang.org/x/crypto/sha3"
+
 	"github.com/ledgerwatch/erigon-lib/commitment"
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/cryptozerocopy"
 	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/recsplit"
+	"github.com/ledgerwatch/erigon-lib/types"
 )
 
 type ValueMerger func(prev, current []byte) (merged []byte, err error)
 
+// ErrCommitmentHistoryDisabled is returned when proofs for past states are requested, but history of
+// CommitmentDomain is not written (see Aggregator.KeepCommitmentHistory).
+var ErrCommitmentHistoryDisabled = errors.New("commitment history disabled")
+
 type commitmentState struct {
 	txNum     uint64
 	blockNum  uint64
@@ -68,6 +79,216 @@ func (cs *commitmentState) Encode() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// replaceShortenedKeysInBranch replaces shortened keys in the branch with full keys
+func (ac *AggregatorRoTx) replaceShortenedKeysInBranch(prefix []byte, branch commitment.BranchData, fStartTxNum uint64, fEndTxNum uint64) (commitment.BranchData, error) {
+	if !ac.d[kv.CommitmentDomain].d.replaceKeysInValues && ac.a.commitmentValuesTransform {
+		panic("domain.replaceKeysInValues is disabled, but agg.commitmentValuesTransform is enabled")
+	}
+
+	if !ac.a.commitmentValuesTransform ||
+		len(branch) == 0 ||
+		ac.minimaxTxNumInDomainFiles() == 0 ||
+		bytes.Equal(prefix, keyCommitmentState) || ((fEndTxNum-fStartTxNum)/ac.a.StepSize())%2 != 0 {
+
+		return branch, nil // do not transform, return as is
+	}
+
+	sto := ac.d[kv.StorageDomain]
+	acc := ac.d[kv.AccountsDomain]
+	storageItem := sto.lookupFileByItsRange(fStartTxNum, fEndTxNum)
+	if storageItem == nil {
+		ac.a.logger.Crit(fmt.Sprintf("storage file of steps %d-%d not found\n", fStartTxNum/ac.a.aggregationStep, fEndTxNum/ac.a.aggregationStep))
+		return nil, fmt.Errorf("storage file not found")
+	}
+	accountItem := acc.lookupFileByItsRange(fStartTxNum, fEndTxNum)
+	if accountItem == nil {
+		ac.a.logger.Crit(fmt.Sprintf("storage file of steps %d-%d not found\n", fStartTxNum/ac.a.aggregationStep, fEndTxNum/ac.a.aggregationStep))
+		return nil, fmt.Errorf("account file not found")
+	}
+	storageGetter := NewArchiveGetter(storageItem.decompressor.MakeGetter(), sto.d.compression)
+	accountGetter := NewArchiveGetter(accountItem.decompressor.MakeGetter(), acc.d.compression)
+
+	aux := make([]byte, 0, 256)
+	return branch.ReplacePlainKeys(aux, func(key []byte, isStorage bool) ([]byte, error) {
+		if isStorage {
+			if len(key) == length.Addr+length.Hash {
+				return nil, nil // save storage key as is
+			}
+			// Optimised key referencing a state file record (file number and offset within the file)
+			storagePlainKey, found := sto.lookupByShortenedKey(key, storageGetter)
+			if !found {
+				s0, s1 := fStartTxNum/ac.a.StepSize(), fEndTxNum/ac.a.StepSize()
+				ac.a.logger.Crit("replace back lost storage full key", "shortened", fmt.Sprintf("%x", key),
+					"decoded", fmt.Sprintf("step %d-%d; offt %d", s0, s1, decodeShorterKey(key)))
+				return nil, fmt.Errorf("replace back lost storage full key: %x", key)
+			}
+			return storagePlainKey, nil
+		}
+
+		if len(key) == length.Addr {
+			return nil, nil // save account key as is
+		}
+
+		apkBuf, found := acc.lookupByShortenedKey(key, accountGetter)
+		if !found {
+			s0, s1 := fStartTxNum/ac.a.StepSize(), fEndTxNum/ac.a.StepSize()
+			ac.a.logger.Crit("replace back lost account full key", "shortened", fmt.Sprintf("%x", key),
+				"decoded", fmt.Sprintf("step %d-%d; offt %d", s0, s1, decodeShorterKey(key)))
+			return nil, fmt.Errorf("replace back lost account full key: %x", key)
+		}
+		return apkBuf, nil
+	})
+}
+
+// historicalCommitmentContext is a read-only commitment.PatriciaContext which serves branches
+// and state values as they were at txNum (before txNum applied).
+type historicalCommitmentContext struct {
+	ac     *AggregatorRoTx
+	roTx   kv.Tx
+	txNum  uint64
+	keccak cryptozerocopy.KeccakState
+}
+
+func (hc *historicalCommitmentContext) GetBranch(prefix []byte) ([]byte, uint64, error) {
+	cd := hc.ac.d[kv.CommitmentDomain]
+	v, ok, err := cd.ht.HistorySeek(prefix, hc.txNum, hc.roTx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("commitment prefix %x history read error: %w", prefix, err)
+	}
+	if ok {
+		// history values are stored in db as is (without transformation), empty value means key was not set yet
+		return v, 0, nil
+	}
+
+	// key has not been changed since txNum, so latest value is the historical one
+	v, step, found, err := cd.getLatestFromDb(prefix, hc.roTx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("commitment prefix %x read error: %w", prefix, err)
+	}
+	if found {
+		return v, step, nil
+	}
+	v, _, startTx, endTx, err := cd.getFromFiles(prefix)
+	if err != nil {
+		return nil, 0, fmt.Errorf("commitment prefix %x read error: %w", prefix, err)
+	}
+	if !hc.ac.a.commitmentValuesTransform || bytes.Equal(prefix, keyCommitmentState) {
+		return v, endTx / hc.ac.a.StepSize(), nil
+	}
+	rv, err := hc.ac.replaceShortenedKeysInBranch(prefix, commitment.BranchData(v), startTx, endTx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return rv, endTx / hc.ac.a.StepSize(), nil
+}
+
+func (hc *historicalCommitmentContext) PutBranch(prefix []byte, data []byte, prevData []byte, prevStep uint64) error {
+	return fmt.Errorf("PutBranch: historical commitment context is read-only")
+}
+
+func (hc *historicalCommitmentContext) GetAccount(plainKey []byte, cell *commitment.Cell) error {
+	encAccount, err := hc.ac.d[kv.AccountsDomain].GetAsOf(plainKey, hc.txNum, hc.roTx)
+	if err != nil {
+		return fmt.Errorf("GetAccount failed: %w", err)
+	}
+	cell.Nonce = 0
+	cell.Balance.Clear()
+	if len(encAccount) > 0 {
+		nonce, balance, chash := types.DecodeAccountBytesV3(encAccount)
+		cell.Nonce = nonce
+		cell.Balance.Set(balance)
+		if len(chash) > 0 {
+			copy(cell.CodeHash[:], chash)
+		}
+	}
+	if bytes.Equal(cell.CodeHash[:], commitment.EmptyCodeHash) {
+		cell.Delete = len(encAccount) == 0
+		return nil
+	}
+
+	code, err := hc.ac.d[kv.CodeDomain].GetAsOf(plainKey, hc.txNum, hc.roTx)
+	if err != nil {
+		return fmt.Errorf("GetAccount: failed to read code: %w", err)
+	}
+	if len(code) > 0 {
+		hc.keccak.Reset()
+		hc.keccak.Write(code)
+		hc.keccak.Read(cell.CodeHash[:])
+	} else {
+		cell.CodeHash = commitment.EmptyCodeHashArray
+	}
+	cell.Delete = len(encAccount) == 0 && len(code) == 0
+	return nil
+}
+
+func (hc *historicalCommitmentContext) GetStorage(plainKey []byte, cell *commitment.Cell) error {
+	enc, err := hc.ac.d[kv.StorageDomain].GetAsOf(plainKey, hc.txNum, hc.roTx)
+	if err != nil {
+		return err
+	}
+	cell.StorageLen = len(enc)
+	copy(cell.Storage[:], enc)
+	cell.Delete = cell.StorageLen == 0
+	return nil
+}
+
+// ProofAsOf builds Merkle proof of the account and its storage slots against the state trie as it was at txNum
+// (before txNum applied). Trie branches are read from commitment history, which is kept in DB only and pruned,
+// so proofs are available for recent blocks only. Returned RootHash must be checked against block stateRoot by caller:
+// missing or partially pruned history results in a different root.
+func (ac *AggregatorRoTx) ProofAsOf(tx kv.Tx, txNum uint64, address []byte, storageKeys [][]byte) (*commitment.StateProof, error) {
+	hph, err := ac.commitmentAsOf(tx, txNum)
+	if err != nil {
+		return nil, err
+	}
+	storagePlainKeys := make([][]byte, len(storageKeys))
+	for i, key := range storageKeys {
+		storagePlainKeys[i] = append(common.Copy(address), key...)
+	}
+	return hph.GenerateProof(address, storagePlainKeys)
+}
+
+// WitnessAsOf collects state trie nodes as of txNum (before txNum applied) required to access and modify given
+// plain keys: addresses and address+location storage keys. Same as for ProofAsOf, returned root must be checked
+// against block stateRoot by caller.
+func (ac *AggregatorRoTx) WitnessAsOf(tx kv.Tx, txNum uint64, plainKeys [][]byte) (rootHash []byte, nodes commitment.ProofNodes, err error) {
+	hph, err := ac.commitmentAsOf(tx, txNum)
+	if err != nil {
+		return nil, nil, err
+	}
+	return hph.GenerateWitness(plainKeys)
+}
+
+// commitmentAsOf restores trie state saved at the latest commitment before txNum, with branches read as of txNum
+func (ac *AggregatorRoTx) commitmentAsOf(tx kv.Tx, txNum uint64) (*commitment.HexPatriciaHashed, error) {
+	if !ac.a.keepCommitmentHistory {
+		return nil, ErrCommitmentHistoryDisabled
+	}
+	hc := &historicalCommitmentContext{
+		ac:     ac,
+		roTx:   tx,
+		txNum:  txNum,
+		keccak: sha3.NewLegacyKeccak256().(cryptozerocopy.KeccakState),
+	}
+	encState, _, err := hc.GetBranch(keyCommitmentState)
+	if err != nil {
+		return nil, err
+	}
+	if len(encState) == 0 {
+		return nil, fmt.Errorf("commitment state is not available as of txNum %d", txNum)
+	}
+	cs := new(commitmentState)
+	if err := cs.Decode(encState); err != nil {
+		return nil, fmt.Errorf("commitment state as of txNum %d: %w", txNum, err)
+	}
+
+	hph := commitment.NewHexPatriciaHashed(length.Addr, hc, ac.a.tmpdir)
+	if err := hph.SetState(cs.trieState); err != nil {
+		return nil, fmt.Errorf("commitment state as of txNum %d: %w", txNum, err)
+	}
+	return hph, nil
+}
+
 func decodeShorterKey(from []byte) uint64 {
 	of, n := binary.Uvarint(from)
 	if n == 0 {
@@ -0,0 +1,151 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
+	"github.com/ledgerwatch/erigon-lib/types"
+)
+
+// eventsBufferSize - amount of events a subscriber may fall behind before it gets unsubscribed
+const eventsBufferSize = 4096
+
+type EventType uint8
+
+const (
+	AddEvent     EventType = iota // transaction entered the pool (always to the queued sub-pool)
+	PromoteEvent                  // transaction moved between sub-pools, in either direction
+	ReplaceEvent                  // transaction was replaced by another one with the same sender and nonce
+	DiscardEvent                  // transaction was rejected or removed from the pool, other than by replacement
+)
+
+func (t EventType) String() string {
+	switch t {
+	case AddEvent:
+		return "add"
+	case PromoteEvent:
+		return "promote"
+	case ReplaceEvent:
+		return "replace"
+	case DiscardEvent:
+		return "discard"
+	default:
+		return "unknown"
+	}
+}
+
+// Event - change of the pool content. Fields which are not relevant for the event type are left zero.
+type Event struct {
+	Type       EventType
+	IDHash     common.Hash
+	Sender     common.Address
+	From       SubPoolType             // PromoteEvent
+	To         SubPoolType             // AddEvent, PromoteEvent
+	ReplacedBy common.Hash             // ReplaceEvent
+	Reason     txpoolcfg.DiscardReason // DiscardEvent
+}
+
+// eventFeed - fan-out of the pool events. It's called under the pool lock, so it never blocks:
+// subscriber which doesn't keep up gets its channel closed.
+type eventFeed struct {
+	mu     sync.Mutex
+	subs   map[uint]chan Event
+	id     uint
+	active atomic.Bool // fast path for the pool when nobody listens
+}
+
+func (f *eventFeed) subscribe() (<-chan Event, func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.subs == nil {
+		f.subs = make(map[uint]chan Event)
+	}
+	f.id++
+	id := f.id
+	ch := make(chan Event, eventsBufferSize)
+	f.subs[id] = ch
+	f.active.Store(true)
+	return ch, func() { f.unsubscribe(id) }
+}
+
+func (f *eventFeed) unsubscribe(id uint) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ch, ok := f.subs[id]
+	if !ok { // double-unsubscribe support
+		return
+	}
+	delete(f.subs, id)
+	close(ch)
+	f.active.Store(len(f.subs) > 0)
+}
+
+func (f *eventFeed) send(ev Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for id, ch := range f.subs {
+		select {
+		case ch <- ev:
+		default:
+			delete(f.subs, id)
+			close(ch)
+		}
+	}
+	f.active.Store(len(f.subs) > 0)
+}
+
+// SubscribeEvents - returns channel with events of the pool. Channel is closed by unsubscribe
+// or when the subscriber falls more than eventsBufferSize events behind.
+func (p *TxPool) SubscribeEvents() (events <-chan Event, unsubscribe func()) {
+	return p.events.subscribe()
+}
+
+func (p *TxPool) senderAddr(senderID uint64) common.Address {
+	return p.senders.senderID2Addr[senderID]
+}
+
+func (p *TxPool) onAdd(mt *metaTx) {
+	if !p.events.active.Load() {
+		return
+	}
+	p.events.send(Event{Type: AddEvent, IDHash: common.Hash(mt.Tx.IDHash), Sender: p.senderAddr(mt.Tx.SenderID), To: mt.currentSubPool})
+}
+
+func (p *TxPool) onMove(mt *metaTx, from SubPoolType) {
+	if !p.events.active.Load() || mt.currentSubPool == from {
+		return
+	}
+	p.events.send(Event{Type: PromoteEvent, IDHash: common.Hash(mt.Tx.IDHash), Sender: p.senderAddr(mt.Tx.SenderID), From: from, To: mt.currentSubPool})
+}
+
+func (p *TxPool) onReplace(replaced, mt *metaTx) {
+	if !p.events.active.Load() {
+		return
+	}
+	p.events.send(Event{Type: ReplaceEvent, IDHash: common.Hash(replaced.Tx.IDHash), Sender: p.senderAddr(mt.Tx.SenderID), ReplacedBy: common.Hash(mt.Tx.IDHash)})
+}
+
+func (p *TxPool) onDiscard(txn *types.TxSlot, reason txpoolcfg.DiscardReason) {
+	if !p.events.active.Load() {
+		return
+	}
+	p.events.send(Event{Type: DiscardEvent, IDHash: common.Hash(txn.IDHash), Sender: p.senderAddr(txn.SenderID), Reason: reason})
+}
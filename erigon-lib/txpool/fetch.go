@@ -298,6 +298,9 @@ func (f *Fetch) handleInboundMessage(ctx context.Context, req *sentry.InboundMes
 			}
 
 			txnHash := hashes[i:min(i+hashSize, len(hashes))]
+			if f.pool.IsPrivate(txnHash) { // reply as if it's unknown
+				continue
+			}
 			txn, err := f.pool.GetRlp(tx, txnHash)
 			if err != nil {
 				return err
@@ -0,0 +1,237 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/rlp"
+	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
+	"github.com/ledgerwatch/erigon-lib/types"
+)
+
+// txJournal - append-only file with local transactions, so they survive loss of the pool db (same as geth's --txpool.journal).
+// File is a stream of RLP items in geth's format: legacy txn is a list, typed txn is a string with its binary encoding.
+// Blob txs are not journaled - pool doesn't keep their blobs.
+type txJournal struct {
+	path   string
+	writer *os.File // nil while journal is loading - to not journal replayed txs twice
+}
+
+func newTxJournal(path string) *txJournal {
+	return &txJournal{path: path}
+}
+
+func journalPath(cfg txpoolcfg.Config) string {
+	if cfg.Journal == "" || filepath.IsAbs(cfg.Journal) {
+		return cfg.Journal
+	}
+	return filepath.Join(cfg.DBDir, cfg.Journal)
+}
+
+// load - returns binary encodings of journaled txs. On corrupted tail returns txs read before it together with error.
+func (j *txJournal) load() ([][]byte, error) {
+	data, err := os.ReadFile(j.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var txRlps [][]byte
+	for pos := 0; pos < len(data); {
+		dataPos, dataLen, isList, err := rlp.Prefix(data, pos)
+		if err != nil {
+			return txRlps, fmt.Errorf("journal item at %d: %w", pos, err)
+		}
+		end := dataPos + dataLen
+		if end > len(data) {
+			return txRlps, fmt.Errorf("journal item at %d: unexpected end of file", pos)
+		}
+		if isList {
+			txRlps = append(txRlps, data[pos:end])
+		} else {
+			txRlps = append(txRlps, data[dataPos:end])
+		}
+		pos = end
+	}
+	return txRlps, nil
+}
+
+func encodeJournalItem(txRlp []byte) []byte {
+	if len(txRlp) > 0 && txRlp[0] >= 0xc0 { // legacy txn - already a list
+		return txRlp
+	}
+	buf := make([]byte, rlp.StringLen(txRlp))
+	rlp.EncodeString(txRlp, buf)
+	return buf
+}
+
+func (j *txJournal) insert(txRlp []byte) error {
+	if j.writer == nil {
+		return nil
+	}
+	_, err := j.writer.Write(encodeJournalItem(txRlp))
+	return err
+}
+
+// rotate - replaces journal content by given txs and re-opens it for appending
+func (j *txJournal) rotate(txRlps [][]byte) error {
+	if j.writer != nil {
+		if err := j.writer.Close(); err != nil {
+			return err
+		}
+		j.writer = nil
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return err
+	}
+	tmpPath := j.path + ".new"
+	replacement, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	for _, txRlp := range txRlps {
+		if _, err = replacement.Write(encodeJournalItem(txRlp)); err != nil {
+			replacement.Close()
+			return err
+		}
+	}
+	if err = replacement.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpPath, j.path); err != nil {
+		return err
+	}
+	j.writer, err = os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0644)
+	return err
+}
+
+func (j *txJournal) close() error {
+	if j.writer == nil {
+		return nil
+	}
+	err := j.writer.Close()
+	j.writer = nil
+	return err
+}
+
+// loadJournal - adds journaled txs as local and rewrites journal by all local txs of the pool
+func (p *TxPool) loadJournal(ctx context.Context, db kv.RoDB) error {
+	txRlps, loadErr := p.journal.load()
+	if loadErr != nil {
+		p.logger.Warn("[txpool] journal: corrupted, using readable part", "path", p.journal.path, "err", loadErr)
+	}
+
+	if len(txRlps) > 0 {
+		var slots types.TxSlots
+		parseCtx := types.NewTxParseContext(p.chainID).ChainIDRequired()
+		parseCtx.ValidateRLP(p.ValidateSerializedTxn)
+		if err := db.View(ctx, func(tx kv.Tx) error {
+			for _, txRlp := range txRlps {
+				j := len(slots.Txs)
+				slots.Resize(uint(j + 1))
+				slots.Txs[j] = &types.TxSlot{}
+				slots.IsLocal[j] = true
+				if _, err := parseCtx.ParseTransaction(txRlp, 0, slots.Txs[j], slots.Senders.At(j), false /* hasEnvelope */, false /* wrappedWithBlobs */, func(hash []byte) error {
+					if known, _ := p.IdHashKnown(tx, hash); known {
+						return types.ErrAlreadyKnown
+					}
+					return nil
+				}); err != nil {
+					slots.Resize(uint(j))
+					if !errors.Is(err, types.ErrAlreadyKnown) {
+						p.logger.Debug("[txpool] journal: skip txn", "err", err)
+					}
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		var added int
+		if len(slots.Txs) > 0 {
+			reasons, err := p.AddLocalTxs(ctx, slots, nil)
+			if err != nil {
+				return err
+			}
+			for _, reason := range reasons {
+				if reason == txpoolcfg.Success {
+					added++
+				}
+			}
+		}
+		p.logger.Info("[txpool] Loaded local transactions journal", "path", p.journal.path, "transactions", len(txRlps), "added", added)
+	}
+
+	return p.rotateJournal(ctx, db)
+}
+
+func (p *TxPool) rotateJournal(ctx context.Context, db kv.RoDB) error {
+	return db.View(ctx, func(tx kv.Tx) error {
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		var txRlps [][]byte
+		p.all.ascendAll(func(mt *metaTx) bool {
+			if mt.subPool&IsLocal == 0 || mt.Tx.Type == types.BlobTxType || mt.privateUntil > 0 {
+				return true
+			}
+			txRlp, _, _, err := p.getRlpLocked(tx, mt.Tx.IDHash[:])
+			if err != nil {
+				p.logger.Warn("[txpool] journal: get txn", "err", err)
+				return true
+			}
+			if txRlp != nil {
+				txRlps = append(txRlps, txRlp)
+			}
+			return true
+		})
+		if err := p.journal.rotate(txRlps); err != nil {
+			return fmt.Errorf("rotating txpool journal: %w", err)
+		}
+		p.logger.Debug("[txpool] Regenerated local transactions journal", "transactions", len(txRlps))
+		return nil
+	})
+}
+
+// journalLocked - appends newly added local txs to the journal, must be called under the pool lock
+func (p *TxPool) journalLocked(txn *types.TxSlot) {
+	if p.journal == nil || txn.Type == types.BlobTxType {
+		return
+	}
+	if err := p.journal.insert(txn.Rlp); err != nil {
+		p.logger.Warn("[txpool] journal: append txn", "err", err)
+	}
+}
+
+func (p *TxPool) closeJournal() {
+	if p.journal == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if err := p.journal.close(); err != nil {
+		p.logger.Warn("[txpool] journal: close", "err", err)
+	}
+}
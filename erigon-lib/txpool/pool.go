@@ -94,6 +94,8 @@ type Pool interface {
 	FilterKnownIdHashes(tx kv.Tx, hashes types.Hashes) (unknownHashes types.Hashes, err error)
 	Started() bool
 	GetRlp(tx kv.Tx, hash []byte) ([]byte, error)
+	// IsPrivate check whether transaction with given Id hash must not be sent to peers
+	IsPrivate(idHash []byte) bool
 
 	AddNewGoodPeer(peerID types.PeerID)
 }
@@ -131,6 +133,7 @@ type metaTx struct {
 	subPool                   SubPoolMarker
 	currentSubPool            SubPoolType
 	minedBlockNum             uint64
+	privateUntil              uint64 // block number at which not mined private txn is dropped, 0 - not private
 }
 
 func newMetaTx(slot *types.TxSlot, isLocal bool, timestamp uint64) *metaTx {
@@ -211,6 +214,9 @@ type TxPool struct {
 	all                     *BySenderAndNonce                // senderID => (sorted map of txn nonce => *metaTx)
 	deletedTxs              []*metaTx                        // list of discarded txs since last db commit
 	promoted                types.Announcements
+	events                  eventFeed          // add/promote/replace/discard notifications, see SubscribeEvents
+	journal                 *txJournal         // nil if disabled
+	privateTxs              map[string]*metaTx // idHash => private txn, see AddPrivateTxs
 	cfg                     txpoolcfg.Config
 	chainID                 uint256.Int
 	lastSeenBlock           atomic.Uint64
@@ -283,11 +289,20 @@ func New(newTxs chan types.Announcements, coreDB kv.RoDB, cfg txpoolcfg.Config,
 		unprocessedRemoteByHash: map[string]int{},
 		minedBlobTxsByBlock:     map[uint64][]*metaTx{},
 		minedBlobTxsByHash:      map[string]*metaTx{},
+		privateTxs:              map[string]*metaTx{},
 		maxBlobsPerBlock:        maxBlobsPerBlock,
 		feeCalculator:           feeCalculator,
 		logger:                  logger,
 	}
 
+	if path := journalPath(cfg); path != "" {
+		if cfg.Rejournal < time.Second {
+			logger.Warn("[txpool] Sanitizing invalid txpool journal time", "provided", cfg.Rejournal, "updated", time.Second)
+			res.cfg.Rejournal = time.Second
+		}
+		res.journal = newTxJournal(path)
+	}
+
 	if shanghaiTime != nil {
 		if !shanghaiTime.IsUint64() {
 			return nil, errors.New("shanghaiTime overflow")
@@ -318,7 +333,7 @@ func (p *TxPool) Start(ctx context.Context, db kv.RwDB) error {
 		return nil
 	}
 
-	return db.View(ctx, func(tx kv.Tx) error {
+	if err := db.View(ctx, func(tx kv.Tx) error {
 		coreDb, _ := p.coreDBWithCache()
 		coreTx, err := coreDb.BeginRo(ctx)
 
@@ -332,12 +347,22 @@ func (p *TxPool) Start(ctx context.Context, db kv.RwDB) error {
 			return fmt.Errorf("loading pool from DB: %w", err)
 		}
 
-		if p.started.CompareAndSwap(false, true) {
-			p.logger.Info("[txpool] Started")
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if p.journal != nil {
+		if err := p.loadJournal(ctx, db); err != nil {
+			return fmt.Errorf("loading txpool journal: %w", err)
 		}
+	}
 
-		return nil
-	})
+	if p.started.CompareAndSwap(false, true) {
+		p.logger.Info("[txpool] Started")
+	}
+
+	return nil
 }
 
 func (p *TxPool) OnNewBlock(ctx context.Context, stateChanges *remote.StateChangeBatch, unwindTxs, unwindBlobTxs, minedTxs types.TxSlots, tx kv.Tx) error {
@@ -475,6 +500,7 @@ func (p *TxPool) OnNewBlock(ctx context.Context, stateChanges *remote.StateChang
 	if err = p.removeMined(p.all, minedTxs.Txs); err != nil {
 		return err
 	}
+	p.discardExpiredPrivateLocked(block)
 
 	var announcements types.Announcements
 
@@ -586,7 +612,7 @@ func (p *TxPool) AppendLocalAnnouncements(types []byte, sizes []uint32, hashes [
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	for hash, txn := range p.byHash {
-		if txn.subPool&IsLocal == 0 {
+		if txn.subPool&IsLocal == 0 || txn.privateUntil > 0 {
 			continue
 		}
 		types = append(types, txn.Tx.Type)
@@ -1106,6 +1132,7 @@ func (p *TxPool) validateTxs(txs *types.TxSlots, stateCache kvcache.CacheView) (
 			p.punishSpammer(txn.SenderID)
 		}
 		reasons[i] = reason
+		p.onDiscard(txn, reason)
 	}
 
 	goodTxs.Resize(uint(goodCount))
@@ -1166,6 +1193,10 @@ func fillDiscardReasons(reasons []txpoolcfg.DiscardReason, newTxs types.TxSlots,
 }
 
 func (p *TxPool) AddLocalTxs(ctx context.Context, newTransactions types.TxSlots, tx kv.Tx) ([]txpoolcfg.DiscardReason, error) {
+	return p.addLocalTxs(ctx, newTransactions, false)
+}
+
+func (p *TxPool) addLocalTxs(ctx context.Context, newTransactions types.TxSlots, private bool) ([]txpoolcfg.DiscardReason, error) {
 	coreDb, cache := p.coreDBWithCache()
 	coreTx, err := coreDb.BeginRo(ctx)
 	if err != nil {
@@ -1201,16 +1232,32 @@ func (p *TxPool) AddLocalTxs(ctx context.Context, newTransactions types.TxSlots,
 	} else {
 		return nil, err
 	}
-	p.promoted.Reset()
-	p.promoted.AppendOther(announcements)
-
 	reasons = fillDiscardReasons(reasons, newTxs, p.discardReasonsLRU)
+	if private {
+		for i, reason := range reasons {
+			if reason == txpoolcfg.Success {
+				p.markPrivateLocked(newTxs.Txs[i])
+			}
+		}
+	}
+
+	p.promoted.Reset()
+	for i := 0; i < announcements.Len(); i++ {
+		t, size, hash := announcements.At(i)
+		if !p.isPrivateLocked(hash) {
+			p.promoted.Append(t, size, hash)
+		}
+	}
 	for i, reason := range reasons {
 		if reason == txpoolcfg.Success {
 			txn := newTxs.Txs[i]
 			if txn.Traced {
-				p.logger.Info(fmt.Sprintf("TX TRACING: AddLocalTxs promotes idHash=%x, senderId=%d", txn.IDHash, txn.SenderID))
+				p.logger.Info(fmt.Sprintf("TX TRACING: AddLocalTxs promotes idHash=%x, senderId=%d, private=%t", txn.IDHash, txn.SenderID, private))
+			}
+			if private {
+				continue
 			}
+			p.journalLocked(txn)
 			p.promoted.Append(txn.Type, txn.Size, txn.IDHash[:])
 		}
 	}
@@ -1262,6 +1309,7 @@ func (p *TxPool) addTxs(blockNum uint64, cacheView kvcache.CacheView, senders *s
 		mt := newMetaTx(txn, newTxs.IsLocal[i], blockNum)
 		if reason := p.addLocked(mt, &announcements); reason != txpoolcfg.NotSet {
 			discardReasons[i] = reason
+			p.onDiscard(txn, reason)
 			continue
 		}
 		discardReasons[i] = txpoolcfg.NotSet // unnecessary
@@ -1364,6 +1412,7 @@ func (p *TxPool) setBlobFee(blobFee uint64) {
 func (p *TxPool) addLocked(mt *metaTx, announcements *types.Announcements) txpoolcfg.DiscardReason {
 	// Insert to pending pool, if pool doesn't have txn with same Nonce and bigger Tip
 	found := p.all.get(mt.Tx.SenderID, mt.Tx.Nonce)
+	var replaced *metaTx
 	if found != nil {
 		if found.Tx.Type == types.BlobTxType && mt.Tx.Type != types.BlobTxType {
 			return txpoolcfg.BlobTxReplace
@@ -1417,6 +1466,7 @@ func (p *TxPool) addLocked(mt *metaTx, announcements *types.Announcements) txpoo
 		}
 
 		p.discardLocked(found, txpoolcfg.ReplacedByHigherTip)
+		replaced = found
 	}
 
 	// Don't add blob txn to queued if it's less than current pending blob base fee
@@ -1438,6 +1488,10 @@ func (p *TxPool) addLocked(mt *metaTx, announcements *types.Announcements) txpoo
 	}
 	// All transactions are first added to the queued pool and then immediately promoted from there if required
 	p.queued.Add(mt, "addLocked", p.logger)
+	p.onAdd(mt)
+	if replaced != nil {
+		p.onReplace(replaced, mt)
+	}
 	if mt.Tx.Type == types.BlobTxType {
 		t := p.totalBlobsInPool.Load()
 		p.totalBlobsInPool.Store(t + (uint64(len(mt.Tx.BlobHashes))))
@@ -1460,6 +1514,9 @@ func (p *TxPool) discardLocked(mt *metaTx, reason txpoolcfg.DiscardReason) {
 		t := p.totalBlobsInPool.Load()
 		p.totalBlobsInPool.Store(t - uint64(len(mt.Tx.BlobHashes)))
 	}
+	if reason != txpoolcfg.ReplacedByHigherTip { // replacement is reported by onReplace, once the new txn is added
+		p.onDiscard(mt.Tx, reason)
+	}
 }
 
 // Cache recently mined blobs in anticipation of reorg, delete finalized ones
@@ -1703,8 +1760,11 @@ func (p *TxPool) promote(pendingBaseFee uint64, pendingBlobFee uint64, announcem
 			tx := p.pending.PopWorst()
 			announcements.Append(tx.Tx.Type, tx.Tx.Size, tx.Tx.IDHash[:])
 			p.baseFee.Add(tx, "demote-pending", logger)
+			p.onMove(tx, PendingSubPool)
 		} else {
-			p.queued.Add(p.pending.PopWorst(), "demote-pending", logger)
+			tx := p.pending.PopWorst()
+			p.queued.Add(tx, "demote-pending", logger)
+			p.onMove(tx, PendingSubPool)
 		}
 	}
 
@@ -1713,11 +1773,14 @@ func (p *TxPool) promote(pendingBaseFee uint64, pendingBlobFee uint64, announcem
 		tx := p.baseFee.PopBest()
 		announcements.Append(tx.Tx.Type, tx.Tx.Size, tx.Tx.IDHash[:])
 		p.pending.Add(tx, logger)
+		p.onMove(tx, BaseFeeSubPool)
 	}
 
 	// Demote worst transactions that do not qualify for base fee pool anymore, to queued sub pool, or discard
 	for worst := p.baseFee.Worst(); p.baseFee.Len() > 0 && worst.subPool < BaseFeePoolBits; worst = p.baseFee.Worst() {
-		p.queued.Add(p.baseFee.PopWorst(), "demote-base", logger)
+		tx := p.baseFee.PopWorst()
+		p.queued.Add(tx, "demote-base", logger)
+		p.onMove(tx, BaseFeeSubPool)
 	}
 
 	// Promote best transactions from the queued pool to either pending or base fee pool, while they qualify
@@ -1726,8 +1789,11 @@ func (p *TxPool) promote(pendingBaseFee uint64, pendingBlobFee uint64, announcem
 			tx := p.queued.PopBest()
 			announcements.Append(tx.Tx.Type, tx.Tx.Size, tx.Tx.IDHash[:])
 			p.pending.Add(tx, logger)
+			p.onMove(tx, QueuedSubPool)
 		} else {
-			p.baseFee.Add(p.queued.PopBest(), "promote-queued", logger)
+			tx := p.queued.PopBest()
+			p.baseFee.Add(tx, "promote-queued", logger)
+			p.onMove(tx, QueuedSubPool)
 		}
 	}
 
@@ -1772,6 +1838,12 @@ func MainLoop(ctx context.Context, db kv.RwDB, p *TxPool, newTxs chan types.Anno
 	defer commitEvery.Stop()
 	logEvery := time.NewTicker(p.cfg.LogEvery)
 	defer logEvery.Stop()
+	var rejournalEvery <-chan time.Time // nil if journal is disabled
+	if p.journal != nil {
+		rejournalTicker := time.NewTicker(p.cfg.Rejournal)
+		defer rejournalTicker.Stop()
+		rejournalEvery = rejournalTicker.C
+	}
 
 	err := p.Start(ctx, db)
 
@@ -1784,9 +1856,16 @@ func MainLoop(ctx context.Context, db kv.RwDB, p *TxPool, newTxs chan types.Anno
 		select {
 		case <-ctx.Done():
 			_, _ = p.flush(ctx, db)
+			p.closeJournal()
 			return
 		case <-logEvery.C:
 			p.logStats()
+		case <-rejournalEvery:
+			if p.Started() {
+				if err := p.rotateJournal(ctx, db); err != nil {
+					p.logger.Warn("[txpool] Failed to rotate local txs journal", "err", err)
+				}
+			}
 		case <-processRemoteTxsEvery.C:
 			if !p.Started() {
 				continue
@@ -1853,6 +1932,9 @@ func MainLoop(ctx context.Context, db kv.RwDB, p *TxPool, newTxs chan types.Anno
 				if err := db.View(ctx, func(tx kv.Tx) error {
 					for i := 0; i < announcements.Len(); i++ {
 						t, size, hash := announcements.At(i)
+						if p.IsPrivate(hash) { // promoted by a new block, but must not reach peers
+							continue
+						}
 						slotRlp, err := p.GetRlp(tx, hash)
 						if err != nil {
 							return err
@@ -2010,7 +2092,7 @@ func (p *TxPool) flushLocked(tx kv.RwTx) (err error) {
 
 	v := make([]byte, 0, 1024)
 	for txHash, metaTx := range p.byHash {
-		if metaTx.Tx.Rlp == nil {
+		if metaTx.Tx.Rlp == nil || metaTx.privateUntil > 0 { // private txs are not persisted, see AddPrivateTxs
 			continue
 		}
 		v = common.EnsureEnoughSize(v, 20+len(metaTx.Tx.Rlp))
@@ -2299,7 +2381,7 @@ func (p *TxPool) logStats() {
 }
 
 // Deprecated need switch to streaming-like
-func (p *TxPool) deprecatedForEach(_ context.Context, f func(rlp []byte, sender common.Address, t SubPoolType), tx kv.Tx) {
+func (p *TxPool) deprecatedForEach(_ context.Context, f func(rlp []byte, sender common.Address, t SubPoolType, isLocal bool), tx kv.Tx) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	p.all.ascendAll(func(mt *metaTx) bool {
@@ -2318,7 +2400,7 @@ func (p *TxPool) deprecatedForEach(_ context.Context, f func(rlp []byte, sender
 			slotRlp = v[20:]
 		}
 		if sender, found := p.senders.senderID2Addr[slot.SenderID]; found {
-			f(slotRlp, sender, mt.currentSubPool)
+			f(slotRlp, sender, mt.currentSubPool, mt.subPool&IsLocal > 0)
 		}
 		return true
 	})
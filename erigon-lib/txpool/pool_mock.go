@@ -271,6 +271,44 @@ func (c *MockPoolIdHashKnownCall) DoAndReturn(f func(kv.Tx, []byte) (bool, error
 	return c
 }
 
+// IsPrivate mocks base method.
+func (m *MockPool) IsPrivate(arg0 []byte) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsPrivate", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsPrivate indicates an expected call of IsPrivate.
+func (mr *MockPoolMockRecorder) IsPrivate(arg0 any) *MockPoolIsPrivateCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPrivate", reflect.TypeOf((*MockPool)(nil).IsPrivate), arg0)
+	return &MockPoolIsPrivateCall{Call: call}
+}
+
+// MockPoolIsPrivateCall wrap *gomock.Call
+type MockPoolIsPrivateCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockPoolIsPrivateCall) Return(arg0 bool) *MockPoolIsPrivateCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockPoolIsPrivateCall) Do(f func([]byte) bool) *MockPoolIsPrivateCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockPoolIsPrivateCall) DoAndReturn(f func([]byte) bool) *MockPoolIsPrivateCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // OnNewBlock mocks base method.
 func (m *MockPool) OnNewBlock(arg0 context.Context, arg1 *remoteproto.StateChangeBatch, arg2, arg3, arg4 types.TxSlots, arg5 kv.Tx) error {
 	m.ctrl.T.Helper()
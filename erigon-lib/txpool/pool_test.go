@@ -22,6 +22,8 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 
 	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
@@ -1228,3 +1230,296 @@ func TestGasLimitChanged(t *testing.T) {
 
 	assert.Zero(mtx.subPool&NotTooMuchGas, "Should now have block space (again) for the tx")
 }
+
+func TestEvents(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 100)
+
+	coreDB, _ := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	db := memdb.NewTestPoolDB(t)
+
+	cfg := txpoolcfg.DefaultConfig
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	assert.NoError(err)
+	require.True(pool != nil)
+	ctx := context.Background()
+	pendingBaseFee := uint64(200000)
+	h1 := gointerfaces.ConvertHashToH256([32]byte{})
+	change := &remote.StateChangeBatch{
+		PendingBlockBaseFee: pendingBaseFee,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 0, BlockHash: h1},
+		},
+	}
+	var addr [20]byte
+	addr[0] = 1
+	v := types.EncodeAccountBytesV3(2, uint256.NewInt(1*common.Ether), make([]byte, 32), 1)
+	change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+		Action:  remote.Action_UPSERT,
+		Address: gointerfaces.ConvertAddressToH160(addr),
+		Data:    v,
+	})
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	err = pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, types.TxSlots{}, tx)
+	assert.NoError(err)
+
+	events, unsubscribe := pool.SubscribeEvents()
+	defer unsubscribe()
+	readEvents := func() (res []Event) {
+		for len(events) > 0 {
+			res = append(res, <-events)
+		}
+		return res
+	}
+	add := func(idHash byte, nonce uint64, fee uint64) txpoolcfg.DiscardReason {
+		var txSlots types.TxSlots
+		txSlot := &types.TxSlot{
+			Tip:    *uint256.NewInt(fee),
+			FeeCap: *uint256.NewInt(fee),
+			Gas:    100000,
+			Nonce:  nonce,
+		}
+		txSlot.IDHash[0] = idHash
+		txSlots.Append(txSlot, addr[:], true)
+		reasons, err := pool.AddLocalTxs(ctx, txSlots, tx)
+		require.NoError(err)
+		return reasons[0]
+	}
+	hash := func(idHash byte) (h common.Hash) {
+		h[0] = idHash
+		return h
+	}
+
+	require.Equal(txpoolcfg.Success, add(1, 2, 300000))
+	assert.Equal([]Event{
+		{Type: AddEvent, IDHash: hash(1), Sender: addr, To: QueuedSubPool},
+		{Type: PromoteEvent, IDHash: hash(1), Sender: addr, From: QueuedSubPool, To: PendingSubPool},
+	}, readEvents())
+
+	// not enough price bump
+	require.Equal(txpoolcfg.NotReplaced, add(2, 2, 310000))
+	assert.Equal([]Event{
+		{Type: DiscardEvent, IDHash: hash(2), Sender: addr, Reason: txpoolcfg.NotReplaced},
+	}, readEvents())
+
+	require.Equal(txpoolcfg.Success, add(3, 2, 330000))
+	assert.Equal([]Event{
+		{Type: AddEvent, IDHash: hash(3), Sender: addr, To: QueuedSubPool},
+		{Type: ReplaceEvent, IDHash: hash(1), Sender: addr, ReplacedBy: hash(3)},
+		{Type: PromoteEvent, IDHash: hash(3), Sender: addr, From: QueuedSubPool, To: PendingSubPool},
+	}, readEvents())
+
+	require.Equal(txpoolcfg.NonceTooLow, add(4, 1, 300000))
+	assert.Equal([]Event{
+		{Type: DiscardEvent, IDHash: hash(4), Sender: addr, Reason: txpoolcfg.NonceTooLow},
+	}, readEvents())
+
+	// base fee goes up, the transaction is demoted
+	change.StateVersionId++
+	change.PendingBlockBaseFee = 400000
+	change.ChangeBatch[0].BlockHeight = 1
+	change.ChangeBatch[0].Changes = nil
+	require.NoError(pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, types.TxSlots{}, tx))
+	assert.Equal([]Event{
+		{Type: PromoteEvent, IDHash: hash(3), Sender: addr, From: PendingSubPool, To: BaseFeeSubPool},
+	}, readEvents())
+
+	unsubscribe()
+	_, ok := <-events
+	require.False(ok)
+	unsubscribe() // double-unsubscribe is fine
+}
+
+func TestEventsSlowSubscriber(t *testing.T) {
+	var feed eventFeed
+	slow, _ := feed.subscribe()
+	fast, unsubscribe := feed.subscribe()
+	defer unsubscribe()
+	for i := 0; i < eventsBufferSize+1; i++ {
+		feed.send(Event{Type: AddEvent})
+		<-fast
+	}
+	require.Len(t, slow, eventsBufferSize)
+	for range slow { // channel of the subscriber which didn't keep up is closed
+	}
+	require.True(t, feed.active.Load())
+	unsubscribe()
+	require.False(t, feed.active.Load())
+}
+
+func TestJournal(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 100)
+
+	coreDB, _ := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	db := memdb.NewTestPoolDB(t)
+
+	cfg := txpoolcfg.DefaultConfig
+	cfg.DBDir = t.TempDir()
+	cfg.Journal = "transactions.rlp"
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	assert.NoError(err)
+	require.NotNil(pool.journal)
+	require.Equal(filepath.Join(cfg.DBDir, "transactions.rlp"), pool.journal.path)
+	ctx := context.Background()
+	h1 := gointerfaces.ConvertHashToH256([32]byte{})
+	change := &remote.StateChangeBatch{
+		PendingBlockBaseFee: 200000,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 0, BlockHash: h1},
+		},
+	}
+	var addr [20]byte
+	addr[0] = 1
+	v := types.EncodeAccountBytesV3(0, uint256.NewInt(1*common.Ether), make([]byte, 32), 1)
+	change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+		Action:  remote.Action_UPSERT,
+		Address: gointerfaces.ConvertAddressToH160(addr),
+		Data:    v,
+	})
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	err = pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, types.TxSlots{}, tx)
+	assert.NoError(err)
+	tx.Rollback()
+
+	// journal is not writable until first rotation
+	require.NoError(pool.rotateJournal(ctx, db))
+	txRlps, err := pool.journal.load()
+	require.NoError(err)
+	require.Empty(txRlps)
+
+	legacyRlp := []byte{0xc2, 0x01, 0x02} // legacy txn is a list
+	typedRlp := []byte{0x02, 0xc1, 0x03}  // typed txn is type byte followed by a list
+	for i, txRlp := range [][]byte{legacyRlp, typedRlp} {
+		var txSlots types.TxSlots
+		txSlot := &types.TxSlot{
+			Tip:    *uint256.NewInt(300000),
+			FeeCap: *uint256.NewInt(300000),
+			Gas:    100000,
+			Nonce:  uint64(i),
+			Rlp:    txRlp,
+		}
+		txSlot.IDHash[0] = byte(i + 1)
+		txSlots.Append(txSlot, addr[:], true)
+		reasons, err := pool.AddLocalTxs(ctx, txSlots, nil)
+		require.NoError(err)
+		require.Equal(txpoolcfg.Success, reasons[0])
+	}
+
+	// geth-compatible encoding: typed txn is wrapped into rlp string
+	data, err := os.ReadFile(pool.journal.path)
+	require.NoError(err)
+	assert.Equal(hexutility.MustDecodeHex("0xc2010283"+"02c103"), data)
+
+	txRlps, err = pool.journal.load()
+	require.NoError(err)
+	assert.Equal([][]byte{legacyRlp, typedRlp}, txRlps)
+
+	// rotation rewrites the journal by local txs of the pool
+	require.NoError(pool.journal.insert([]byte{0xc1, 0x05}))
+	require.NoError(pool.rotateJournal(ctx, db))
+	txRlps, err = pool.journal.load()
+	require.NoError(err)
+	assert.Equal([][]byte{legacyRlp, typedRlp}, txRlps)
+
+	// corrupted tail doesn't affect txs before it
+	require.NoError(pool.journal.insert([]byte{0xc5, 0x01}))
+	txRlps, err = pool.journal.load()
+	require.Error(err)
+	assert.Equal([][]byte{legacyRlp, typedRlp}, txRlps)
+
+	pool.closeJournal()
+	require.Nil(pool.journal.writer)
+}
+
+func TestPrivateTxs(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 100)
+
+	coreDB, _ := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	db := memdb.NewTestPoolDB(t)
+
+	cfg := txpoolcfg.DefaultConfig
+	cfg.PrivateTxLifetime = 2
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	assert.NoError(err)
+	require.True(pool != nil)
+	ctx := context.Background()
+	var addr [20]byte
+	addr[0] = 1
+	newBlock := func(blockNum uint64) {
+		h1 := gointerfaces.ConvertHashToH256([32]byte{})
+		change := &remote.StateChangeBatch{
+			PendingBlockBaseFee: 200000,
+			BlockGasLimit:       1000000,
+			ChangeBatch: []*remote.StateChange{
+				{BlockHeight: blockNum, BlockHash: h1},
+			},
+		}
+		v := types.EncodeAccountBytesV3(0, uint256.NewInt(1*common.Ether), make([]byte, 32), 1)
+		change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+			Action:  remote.Action_UPSERT,
+			Address: gointerfaces.ConvertAddressToH160(addr),
+			Data:    v,
+		})
+		tx, err := db.BeginRw(ctx)
+		require.NoError(err)
+		defer tx.Rollback()
+		require.NoError(pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, types.TxSlots{}, tx))
+	}
+	newBlock(1)
+	for len(ch) > 0 {
+		<-ch
+	}
+
+	var txSlots types.TxSlots
+	txSlot := &types.TxSlot{
+		Tip:    *uint256.NewInt(300000),
+		FeeCap: *uint256.NewInt(300000),
+		Gas:    100000,
+		Nonce:  0,
+		Rlp:    []byte{0xc1, 0x01},
+	}
+	txSlot.IDHash[0] = 1
+	txSlots.Append(txSlot, addr[:], true)
+	reasons, err := pool.AddPrivateTxs(ctx, txSlots, nil)
+	require.NoError(err)
+	require.Equal(txpoolcfg.Success, reasons[0])
+
+	// available for block building, but not for peers
+	require.True(pool.IsPrivate(txSlot.IDHash[:]))
+	require.Equal(1, pool.pending.Len())
+	assert.Empty(ch)
+	_, _, hashes := pool.AppendAllAnnouncements(nil, nil, nil)
+	assert.Empty(hashes)
+
+	// not persisted
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	require.NoError(pool.flushLocked(tx))
+	has, err := tx.Has(kv.PoolTransaction, txSlot.IDHash[:])
+	require.NoError(err)
+	assert.False(has)
+	assert.NotNil(txSlot.Rlp)
+	tx.Rollback()
+
+	newBlock(2)
+	require.True(pool.IsPrivate(txSlot.IDHash[:]))
+	newBlock(3)
+	require.False(pool.IsPrivate(txSlot.IDHash[:]))
+	assert.Zero(pool.pending.Len())
+	reason, ok := pool.discardReasonsLRU.Get(string(txSlot.IDHash[:]))
+	require.True(ok)
+	assert.Equal(txpoolcfg.PrivateTxnExpired, reason)
+	assert.Empty(pool.privateTxs)
+}
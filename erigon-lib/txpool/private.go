@@ -0,0 +1,87 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"context"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
+	"github.com/ledgerwatch/erigon-lib/types"
+)
+
+// Private txs - local txs which must not reach peers before they are mined (protection from front-running).
+// They are available for block building (YieldBest), but:
+//   - not announced/broadcast to peers and not served to peers by GetPooledTransactions
+//   - not sent to OnAdd subscribers
+//   - not persisted to the pool db and not journaled: after restart they would become usual local txs
+//   - dropped if not mined within cfg.PrivateTxLifetime blocks
+
+// AddPrivateTxs - same as AddLocalTxs, but added txs are private
+func (p *TxPool) AddPrivateTxs(ctx context.Context, newTransactions types.TxSlots, tx kv.Tx) ([]txpoolcfg.DiscardReason, error) {
+	return p.addLocalTxs(ctx, newTransactions, true)
+}
+
+// IsPrivate - whether txn with given hash is in the pool and is private
+func (p *TxPool) IsPrivate(idHash []byte) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.isPrivateLocked(idHash)
+}
+
+func (p *TxPool) isPrivateLocked(idHash []byte) bool {
+	mt, ok := p.byHash[string(idHash)]
+	return ok && mt.privateUntil > 0
+}
+
+func (p *TxPool) markPrivateLocked(txn *types.TxSlot) {
+	mt, ok := p.byHash[string(txn.IDHash[:])]
+	if !ok {
+		return
+	}
+	mt.privateUntil = p.lastSeenBlock.Load() + p.cfg.PrivateTxLifetime
+	if mt.privateUntil == 0 { // lifetime 0 means "until next block", but 0 is reserved for public txs
+		mt.privateUntil = 1
+	}
+	p.privateTxs[string(txn.IDHash[:])] = mt
+}
+
+// discardExpiredPrivateLocked - drops private txs which were not mined in time. Mined, replaced and discarded
+// txs are forgotten here lazily.
+func (p *TxPool) discardExpiredPrivateLocked(blockNum uint64) {
+	for hash, mt := range p.privateTxs {
+		if p.byHash[hash] != mt {
+			delete(p.privateTxs, hash)
+			continue
+		}
+		if blockNum < mt.privateUntil {
+			continue
+		}
+		switch mt.currentSubPool {
+		case PendingSubPool:
+			p.pending.Remove(mt, "private-expired", p.logger)
+		case BaseFeeSubPool:
+			p.baseFee.Remove(mt, "private-expired", p.logger)
+		case QueuedSubPool:
+			p.queued.Remove(mt, "private-expired", p.logger)
+		default:
+			//already removed
+		}
+		delete(p.privateTxs, hash)
+		p.discardLocked(mt, txpoolcfg.PrivateTxnExpired)
+	}
+}
@@ -48,7 +48,7 @@ import (
 )
 
 // TxPoolAPIVersion
-var TxPoolAPIVersion = &types2.VersionReply{Major: 1, Minor: 0, Patch: 0}
+var TxPoolAPIVersion = &types2.VersionReply{Major: 1, Minor: 2, Patch: 0}
 
 type txPool interface {
 	ValidateSerializedTxn(serializedTxn []byte) error
@@ -56,10 +56,12 @@ type txPool interface {
 	PeekBest(n uint16, txs *types.TxsRlp, tx kv.Tx, onTopOf, availableGas, availableBlobGas uint64) (bool, error)
 	GetRlp(tx kv.Tx, hash []byte) ([]byte, error)
 	AddLocalTxs(ctx context.Context, newTxs types.TxSlots, tx kv.Tx) ([]txpoolcfg.DiscardReason, error)
-	deprecatedForEach(_ context.Context, f func(rlp []byte, sender common.Address, t SubPoolType), tx kv.Tx)
+	AddPrivateTxs(ctx context.Context, newTxs types.TxSlots, tx kv.Tx) ([]txpoolcfg.DiscardReason, error)
+	deprecatedForEach(_ context.Context, f func(rlp []byte, sender common.Address, t SubPoolType, isLocal bool), tx kv.Tx)
 	CountContent() (int, int, int)
 	IdHashKnown(tx kv.Tx, hash []byte) (bool, error)
 	NonceFromAddress(addr [20]byte) (nonce uint64, inPool bool)
+	SubscribeEvents() (events <-chan Event, unsubscribe func())
 }
 
 var _ txpool_proto.TxpoolServer = (*GrpcServer)(nil)   // compile-time interface check
@@ -98,6 +100,9 @@ func (*GrpcDisabled) Status(ctx context.Context, request *txpool_proto.StatusReq
 func (*GrpcDisabled) Nonce(ctx context.Context, request *txpool_proto.NonceRequest) (*txpool_proto.NonceReply, error) {
 	return nil, ErrPoolDisabled
 }
+func (*GrpcDisabled) OnEvent(request *txpool_proto.OnEventRequest, server txpool_proto.Txpool_OnEventServer) error {
+	return ErrPoolDisabled
+}
 
 type GrpcServer struct {
 	txpool_proto.UnimplementedTxpoolServer
@@ -137,11 +142,12 @@ func (s *GrpcServer) All(ctx context.Context, _ *txpool_proto.AllRequest) (*txpo
 	defer tx.Rollback()
 	reply := &txpool_proto.AllReply{}
 	reply.Txs = make([]*txpool_proto.AllReply_Tx, 0, 32)
-	s.txPool.deprecatedForEach(ctx, func(rlp []byte, sender common.Address, t SubPoolType) {
+	s.txPool.deprecatedForEach(ctx, func(rlp []byte, sender common.Address, t SubPoolType, isLocal bool) {
 		reply.Txs = append(reply.Txs, &txpool_proto.AllReply_Tx{
 			Sender:  gointerfaces.ConvertAddressToH160(sender),
 			TxnType: convertSubPoolType(t),
 			RlpTx:   common.Copy(rlp),
+			IsLocal: isLocal,
 		})
 	}, tx)
 	return reply, nil
@@ -213,7 +219,11 @@ func (s *GrpcServer) Add(ctx context.Context, in *txpool_proto.AddRequest) (*txp
 		}
 	}
 
-	discardReasons, err := s.txPool.AddLocalTxs(ctx, slots, tx)
+	addTxs := s.txPool.AddLocalTxs
+	if in.Private {
+		addTxs = s.txPool.AddPrivateTxs
+	}
+	discardReasons, err := addTxs(ctx, slots, tx)
 	if err != nil {
 		return nil, err
 	}
@@ -261,6 +271,64 @@ func (s *GrpcServer) OnAdd(req *txpool_proto.OnAddRequest, stream txpool_proto.T
 	}
 }
 
+// maxEventsPerReply - limit of events batched into one OnEventReply
+const maxEventsPerReply = 1024
+
+var ErrEventsSubscriberTooSlow = errors.New("txpool events subscriber is too slow, events were dropped")
+
+func (s *GrpcServer) OnEvent(req *txpool_proto.OnEventRequest, stream txpool_proto.Txpool_OnEventServer) error {
+	s.logger.Info("New txpool events subscriber joined")
+	events, unsubscribe := s.txPool.SubscribeEvents()
+	defer unsubscribe()
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return ErrEventsSubscriberTooSlow
+			}
+			reply := &txpool_proto.OnEventReply{Events: []*txpool_proto.TxpoolEvent{convertEvent(ev)}}
+			// batch everything which is already buffered
+			for n := len(events); n > 0 && len(reply.Events) < maxEventsPerReply; n-- {
+				if ev, ok = <-events; !ok {
+					return ErrEventsSubscriberTooSlow
+				}
+				reply.Events = append(reply.Events, convertEvent(ev))
+			}
+			if err := stream.Send(reply); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		}
+	}
+}
+
+func convertEvent(ev Event) *txpool_proto.TxpoolEvent {
+	reply := &txpool_proto.TxpoolEvent{
+		Hash:   gointerfaces.ConvertHashToH256(ev.IDHash),
+		Sender: gointerfaces.ConvertAddressToH160(ev.Sender),
+	}
+	switch ev.Type {
+	case AddEvent:
+		reply.Type = txpool_proto.TxpoolEventType_ADD
+		reply.To = convertSubPoolType(ev.To)
+	case PromoteEvent:
+		reply.Type = txpool_proto.TxpoolEventType_PROMOTE
+		reply.From = convertSubPoolType(ev.From)
+		reply.To = convertSubPoolType(ev.To)
+	case ReplaceEvent:
+		reply.Type = txpool_proto.TxpoolEventType_REPLACE
+		reply.ReplacedBy = gointerfaces.ConvertHashToH256(ev.ReplacedBy)
+	case DiscardEvent:
+		reply.Type = txpool_proto.TxpoolEventType_DISCARD
+		reply.DiscardReason = uint32(ev.Reason)
+		reply.DiscardMessage = ev.Reason.String()
+	}
+	return reply
+}
+
 func (s *GrpcServer) Transactions(ctx context.Context, in *txpool_proto.TransactionsRequest) (*txpool_proto.TransactionsReply, error) {
 	tx, err := s.db.BeginRo(ctx)
 	if err != nil {
@@ -57,6 +57,12 @@ type Config struct {
 	MdbxGrowthStep  datasize.ByteSize
 
 	NoGossip bool // this mode doesn't broadcast any txs, and if receive remote-txn - skip it
+
+	PrivateTxLifetime uint64 // Number of blocks a private txn stays in the pool if not mined, it's never sent to peers
+
+	// local txs journal: replayed on start, rewritten every Rejournal. Relative path is resolved against DBDir, empty - disabled
+	Journal   string
+	Rejournal time.Duration
 }
 
 var DefaultConfig = Config{
@@ -77,6 +83,10 @@ var DefaultConfig = Config{
 	BlobPriceBump:      100,
 
 	NoGossip: false,
+
+	PrivateTxLifetime: 25,
+
+	Rejournal: time.Hour,
 }
 
 type DiscardReason uint8
@@ -114,6 +124,7 @@ const (
 	UnmatchedBlobTxExt  DiscardReason = 29 // KZGcommitments must match the corresponding blobs and proofs
 	BlobTxReplace       DiscardReason = 30 // Cannot replace type-3 blob txn with another type of txn
 	BlobPoolOverflow    DiscardReason = 31 // The total number of blobs (through blob txs) in the pool has reached its limit
+	PrivateTxnExpired   DiscardReason = 32 // Private txn was not mined within PrivateTxLifetime blocks
 
 )
 
@@ -177,6 +188,8 @@ func (r DiscardReason) String() string {
 		return "can't replace blob-txn with a non-blob-txn"
 	case BlobPoolOverflow:
 		return "blobs limit in txpool is full"
+	case PrivateTxnExpired:
+		return "private transaction expired"
 	default:
 		panic(fmt.Sprintf("discard reason: %d", r))
 	}
@@ -21,6 +21,7 @@
 package ethconfig
 
 import (
+	"fmt"
 	"math/big"
 	"os"
 	"os/user"
@@ -35,6 +36,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/datadir"
 	"github.com/ledgerwatch/erigon-lib/downloader/downloadercfg"
+	"github.com/ledgerwatch/erigon-lib/downloader/snaptype"
 	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
 	"github.com/ledgerwatch/erigon/cl/beacon/beacon_router_configuration"
 	"github.com/ledgerwatch/erigon/cl/clparams"
@@ -145,6 +147,7 @@ type BlocksFreezing struct {
 	NoDownloader   bool // possible to use snapshots without calling Downloader
 	Verify         bool // verify snapshots on startup
 	DownloaderAddr string
+	HistoryWindow  uint64 // keep transactions files only of this amount of recent blocks. 0 - keep all history
 }
 
 func (s BlocksFreezing) String() string {
@@ -158,13 +161,31 @@ func (s BlocksFreezing) String() string {
 	if !s.ProduceE2 {
 		out = append(out, "--"+FlagSnapStop+"=true")
 	}
+	if s.HistoryWindow > 0 {
+		out = append(out, fmt.Sprintf("--%s=%d", FlagSnapHistoryWindow, s.HistoryWindow))
+	}
 	return strings.Join(out, " ")
 }
 
+// HistoryPrunedTo - transactions files of blocks below returned number are out of HistoryWindow:
+// Downloader doesn't download them and node deletes them. 0 - nothing to prune.
+func (s BlocksFreezing) HistoryPrunedTo(frozenBlocks uint64) uint64 {
+	if s.HistoryWindow == 0 {
+		return 0
+	}
+	window := max(s.HistoryWindow, snaptype.Erigon2MergeLimit)
+	if frozenBlocks <= window {
+		return 0
+	}
+	return frozenBlocks - window
+}
+
 var (
 	FlagSnapKeepBlocks = "snap.keepblocks"
 	FlagSnapStop       = "snap.stop"
 	FlagSnapStateStop  = "snap.state.stop"
+
+	FlagSnapHistoryWindow = "snap.history.window"
 )
 
 func NewSnapCfg(enabled, keepBlocks, produceE2, produceE3 bool) BlocksFreezing {
@@ -286,6 +307,8 @@ type Sync struct {
 	PruneLimit                 int //the maximum records to delete from the DB during pruning
 	BreakAfterStage            string
 	LoopBlockLimit             uint
+	TraceSupply                bool // run the optional Supply stage, recording the issuance and burn of every block
+	TraceResults               bool // run the optional TraceResults stage, persisting trace_block results of every block
 
 	UploadLocation   string
 	UploadFrom       rpc.BlockNumber
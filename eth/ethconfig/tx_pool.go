@@ -79,6 +79,9 @@ var DefaultTxPool2Config = func(fullCfg *Config) txpoolcfg.Config {
 	cfg.CommitEvery = 5 * time.Minute
 	cfg.TracedSenders = pool1Cfg.TracedSenders
 	cfg.CommitEvery = pool1Cfg.CommitEvery
+	cfg.PrivateTxLifetime = fullCfg.TxPool.PrivateTxLifetime
+	cfg.Journal = fullCfg.TxPool.Journal
+	cfg.Rejournal = fullCfg.TxPool.Rejournal
 
 	return cfg
 }
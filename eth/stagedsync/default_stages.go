@@ -35,6 +35,7 @@ func DefaultStages(ctx context.Context,
 	bodies BodiesCfg,
 	senders SendersCfg,
 	exec ExecuteBlockCfg,
+	traceResults TraceResultsCfg,
 	txLookup TxLookupCfg,
 	finish FinishCfg,
 	test bool) []*Stage {
@@ -140,6 +141,34 @@ func DefaultStages(ctx context.Context,
 				return PruneExecutionStage(p, tx, exec, ctx)
 			},
 		},
+		{
+			ID:          stages.Supply,
+			Description: "Trace ether issuance and burn",
+			Disabled:    dbg.StagesOnlyBlocks || !exec.syncCfg.TraceSupply,
+			Forward: func(badBlockUnwind bool, s *StageState, u Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+				cfg := StageSupplyCfg(exec.db, exec.chainConfig, exec.engine, exec.blockReader, exec.genesis)
+				return SpawnSupplyStage(s, txc, cfg, ctx, logger)
+			},
+			Unwind: func(u *UnwindState, s *StageState, txc wrap.TxContainer, logger log.Logger) error {
+				cfg := StageSupplyCfg(exec.db, exec.chainConfig, exec.engine, exec.blockReader, exec.genesis)
+				return UnwindSupplyStage(u, s, txc.Tx, cfg, ctx)
+			},
+			// no Prune: supply deltas are kept for every block, erigon_getSupplyDelta serves the whole history
+		},
+		{
+			ID:          stages.TraceResults,
+			Description: "Persist trace_block results",
+			Disabled:    dbg.StagesOnlyBlocks || traceResults.traceBlock == nil,
+			Forward: func(badBlockUnwind bool, s *StageState, u Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+				return SpawnTraceResultsStage(s, txc, traceResults, ctx, logger)
+			},
+			Unwind: func(u *UnwindState, s *StageState, txc wrap.TxContainer, logger log.Logger) error {
+				return UnwindTraceResultsStage(u, s, txc.Tx, traceResults, ctx)
+			},
+			Prune: func(p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneTraceResultsStage(p, tx, traceResults, ctx, logger)
+			},
+		},
 		//{
 		//	ID:          stages.CustomTrace,
 		//	Description: "Re-Execute blocks on history state - with custom tracer",
@@ -553,6 +582,8 @@ var DefaultForwardOrder = UnwindOrder{
 	stages.Senders,
 	stages.Execution,
 	//stages.CustomTrace,
+	stages.Supply,
+	stages.TraceResults,
 	stages.TxLookup,
 	stages.Finish,
 }
@@ -568,6 +599,8 @@ var DefaultUnwindOrder = UnwindOrder{
 	stages.Finish,
 	stages.TxLookup,
 
+	stages.TraceResults,
+	stages.Supply,
 	//stages.CustomTrace,
 	stages.Execution,
 	stages.Senders,
@@ -608,6 +641,8 @@ var DefaultPruneOrder = PruneOrder{
 	stages.Finish,
 	stages.TxLookup,
 
+	stages.TraceResults,
+	stages.Supply,
 	stages.Execution,
 	stages.Senders,
 
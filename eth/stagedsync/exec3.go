@@ -62,6 +62,7 @@ import (
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/core/types/accounts"
 	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/ethdb/prune"
 	"github.com/ledgerwatch/erigon/turbo/services"
 	"github.com/ledgerwatch/erigon/turbo/shards"
 )
@@ -1048,6 +1049,23 @@ func dumpPlainStateDebug(tx kv.RwTx, doms *state2.SharedDomains) {
 }
 
 // flushAndCheckCommitmentV3 - does write state to db and then check commitment
+// pruneCommitHistory - commitment history (written only with --prune.include-commitment-history) has no files,
+// it's kept in db for blocks of the state history window: all blocks, unless --prune.h.older is set
+func pruneCommitHistory(ctx context.Context, tx kv.RwTx, blockNum uint64, pm prune.Mode) error {
+	if pm.History == nil || !pm.History.Enabled() {
+		return nil
+	}
+	pruneTo := pm.History.PruneTo(blockNum)
+	if pruneTo == 0 {
+		return nil
+	}
+	untilTx, err := rawdbv3.TxNums.Min(tx, pruneTo)
+	if err != nil {
+		return err
+	}
+	return tx.(state2.HasAggTx).AggTx().(*state2.AggregatorRoTx).PruneCommitHistory(ctx, tx, untilTx, nil)
+}
+
 func flushAndCheckCommitmentV3(ctx context.Context, header *types.Header, applyTx kv.RwTx, doms *state2.SharedDomains, cfg ExecuteBlockCfg, e *StageState, maxBlockNum uint64, parallel bool, logger log.Logger, u Unwinder, inMemExec bool) (bool, error) {
 
 	// E2 state root check was in another stage - means we did flush state even if state root will not match
@@ -1079,7 +1097,7 @@ func flushAndCheckCommitmentV3(ctx context.Context, header *types.Header, applyT
 			if err := doms.Flush(ctx, applyTx); err != nil {
 				return false, err
 			}
-			if err = applyTx.(state2.HasAggTx).AggTx().(*state2.AggregatorRoTx).PruneCommitHistory(ctx, applyTx, nil); err != nil {
+			if err := pruneCommitHistory(ctx, applyTx, header.Number.Uint64(), cfg.prune); err != nil {
 				return false, err
 			}
 		}
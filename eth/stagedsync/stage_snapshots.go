@@ -627,7 +627,9 @@ func pruneBlockSnapshots(ctx context.Context, cfg SnapshotsCfg, logger log.Logge
 
 	// Keep at least 2 block snapshots as we do not want FrozenBlocks to be 0
 	pruneAmount, _ := computeBlocksToPrune(cfg)
-	if pruneAmount == 0 {
+	// `--snap.history.window`: cutoff moves forward with every new frozen file
+	historyPrunedTo := cfg.blockReader.FreezingCfg().HistoryPrunedTo(headNumber)
+	if pruneAmount == 0 && historyPrunedTo == 0 {
 		return false, nil
 	}
 
@@ -640,7 +642,7 @@ func pruneBlockSnapshots(ctx context.Context, cfg SnapshotsCfg, logger log.Logge
 	filesDeleted := false
 	// Prune blocks snapshots if necessary
 	for _, file := range snapshotFileNames {
-		if !cfg.prune.Blocks.Enabled() || headNumber == 0 || !strings.Contains(file, "transactions") {
+		if headNumber == 0 || !strings.Contains(file, "transactions") {
 			continue
 		}
 
@@ -649,11 +651,17 @@ func pruneBlockSnapshots(ctx context.Context, cfg SnapshotsCfg, logger log.Logge
 		if !ok {
 			continue
 		}
-		if info.To >= minBlockNumberToKeep {
-			continue
-		}
-		if info.To-info.From != snaptype.Erigon2MergeLimit {
-			continue
+		outOfHistoryWindow := historyPrunedTo > 0 && info.To <= historyPrunedTo
+		if !outOfHistoryWindow {
+			if !cfg.prune.Blocks.Enabled() {
+				continue
+			}
+			if info.To >= minBlockNumberToKeep {
+				continue
+			}
+			if info.To-info.From != snaptype.Erigon2MergeLimit {
+				continue
+			}
 		}
 		if cfg.snapshotDownloader != nil {
 			if _, err := cfg.snapshotDownloader.Delete(ctx, &protodownloader.DeleteRequest{Paths: []string{file}}); err != nil {
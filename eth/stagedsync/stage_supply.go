@@ -0,0 +1,230 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package stagedsync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+	"github.com/ledgerwatch/erigon-lib/wrap"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/tracing"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/eth/consensuschain"
+	"github.com/ledgerwatch/erigon/eth/tracers/supply"
+	"github.com/ledgerwatch/erigon/turbo/services"
+)
+
+type SupplyCfg struct {
+	db          kv.RwDB
+	chainConfig *chain.Config
+	engine      consensus.Engine
+	blockReader services.FullBlockReader
+	genesis     *types.Genesis
+}
+
+func StageSupplyCfg(db kv.RwDB, chainConfig *chain.Config, engine consensus.Engine, blockReader services.FullBlockReader, genesis *types.Genesis) SupplyCfg {
+	return SupplyCfg{
+		db:          db,
+		chainConfig: chainConfig,
+		engine:      engine,
+		blockReader: blockReader,
+		genesis:     genesis,
+	}
+}
+
+// SpawnSupplyStage re-executes the executed blocks with the supply tracer and
+// records the supply delta of every block into kv.SupplyDelta.
+func SpawnSupplyStage(s *StageState, txc wrap.TxContainer, cfg SupplyCfg, ctx context.Context, logger log.Logger) (err error) {
+	if txc.Doms != nil {
+		// In-memory execution (fork validation) doesn't flush the state history the
+		// blocks are replayed on, the stage catches up once the blocks are committed
+		return nil
+	}
+	tx := txc.Tx
+	useExternalTx := tx != nil
+	if !useExternalTx {
+		tx, err = cfg.db.BeginRw(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+	}
+	ttx, ok := tx.(kv.TemporalTx)
+	if !ok {
+		return fmt.Errorf("supply stage requires a temporal db, got %T", tx)
+	}
+
+	logPrefix := s.LogPrefix()
+	endBlock, err := s.ExecutionAt(tx)
+	if err != nil {
+		return err
+	}
+	if endBlock <= s.BlockNumber {
+		return nil
+	}
+
+	tracer := supply.NewTracer()
+	hooks := tracer.Hooks()
+	hooks.OnBlockchainInit(cfg.chainConfig)
+
+	startBlock := s.BlockNumber + 1
+	if s.BlockNumber == 0 {
+		// The genesis block isn't executed, its issuance is the genesis allocation
+		genesisDelta, err := rawdb.ReadSupplyDelta(tx, 0)
+		if err != nil {
+			return err
+		}
+		if genesisDelta == nil && cfg.genesis != nil {
+			hooks.OnGenesisBlock(nil, cfg.genesis.Alloc)
+			if genesisDelta, err = tracer.Delta(); err != nil {
+				return err
+			}
+			if err = rawdb.WriteSupplyDelta(tx, 0, genesisDelta); err != nil {
+				return err
+			}
+		}
+	}
+	if endBlock-startBlock > 16 {
+		logger.Info(fmt.Sprintf("[%s] Tracing supply", logPrefix), "from", startBlock, "to", endBlock)
+	}
+
+	logEvery := time.NewTicker(logInterval)
+	defer logEvery.Stop()
+	for blockNum := startBlock; blockNum <= endBlock; blockNum++ {
+		delta, err := traceBlockSupply(ctx, ttx, cfg, tracer, blockNum, logger)
+		if err != nil {
+			return fmt.Errorf("[%s] tracing supply of block %d: %w", logPrefix, blockNum, err)
+		}
+		if err = rawdb.WriteSupplyDelta(tx, blockNum, delta); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return libcommon.ErrStopped
+		case <-logEvery.C:
+			logger.Info(fmt.Sprintf("[%s] Progress", logPrefix), "block", blockNum)
+		default:
+		}
+	}
+
+	if err = s.Update(tx, endBlock); err != nil {
+		return err
+	}
+	if !useExternalTx {
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// traceBlockSupply replays the block on top of the historical state of its parent.
+func traceBlockSupply(ctx context.Context, tx kv.TemporalTx, cfg SupplyCfg, tracer *supply.Tracer, blockNum uint64, logger log.Logger) (*types.SupplyDelta, error) {
+	blockHash, err := cfg.blockReader.CanonicalHash(ctx, tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	block, _, err := cfg.blockReader.BlockWithSenders(ctx, tx, blockHash, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block not found")
+	}
+	header := block.HeaderNoCopy()
+
+	txNum, err := rawdbv3.TxNums.Min(tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	stateReader := state.NewHistoryReaderV3()
+	stateReader.SetTx(tx)
+	stateReader.SetTxNum(txNum)
+	ibs := state.New(stateReader)
+	hooks := tracer.Hooks()
+	ibs.SetHooks(hooks)
+	hooks.OnBlockStart(tracing.BlockEvent{Block: block})
+
+	chainReader := consensuschain.NewReader(cfg.chainConfig, tx, cfg.blockReader, logger)
+	if err = core.InitializeBlockExecution(cfg.engine, chainReader, header, cfg.chainConfig, ibs, logger, hooks); err != nil {
+		return nil, err
+	}
+
+	getHashFn := core.GetHashFn(header, func(hash libcommon.Hash, number uint64) *types.Header {
+		h, _ := cfg.blockReader.Header(ctx, tx, hash, number)
+		return h
+	})
+	vmConfig := vm.Config{Debug: true, Tracer: tracer}
+	gp := new(core.GasPool).AddGas(header.GasLimit).AddBlobGas(cfg.chainConfig.GetMaxBlobGasPerBlock())
+	noop := state.NewNoopWriter()
+	var usedGas, usedBlobGas uint64
+	receipts := make(types.Receipts, 0, len(block.Transactions()))
+	for i, txn := range block.Transactions() {
+		ibs.SetTxContext(txn.Hash(), blockHash, i)
+		receipt, _, err := core.ApplyTransaction(cfg.chainConfig, getHashFn, cfg.engine, nil, gp, ibs, noop, header, txn, &usedGas, &usedBlobGas, vmConfig)
+		if err != nil {
+			return nil, fmt.Errorf("could not apply txn %d [%x]: %w", i, txn.Hash(), err)
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	syscall := func(contract libcommon.Address, data []byte) ([]byte, error) {
+		return core.SysCallContract(contract, data, cfg.chainConfig, ibs, header, cfg.engine, false /* constCall */)
+	}
+	if _, _, _, err = cfg.engine.Finalize(cfg.chainConfig, types.CopyHeader(header), ibs, block.Transactions(), block.Uncles(), receipts, block.Withdrawals(), block.Requests(), chainReader, syscall, logger); err != nil {
+		return nil, err
+	}
+	return tracer.Delta()
+}
+
+func UnwindSupplyStage(u *UnwindState, s *StageState, tx kv.RwTx, cfg SupplyCfg, ctx context.Context) (err error) {
+	useExternalTx := tx != nil
+	if !useExternalTx {
+		tx, err = cfg.db.BeginRw(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+	}
+
+	if err = rawdb.TruncateSupplyDeltas(tx, u.UnwindPoint+1); err != nil {
+		return err
+	}
+	if err = u.Done(tx); err != nil {
+		return err
+	}
+
+	if !useExternalTx {
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
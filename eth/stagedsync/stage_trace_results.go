@@ -0,0 +1,174 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package stagedsync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+	"github.com/ledgerwatch/erigon-lib/wrap"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/turbo/services"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/freezeblocks"
+)
+
+// TraceBlockFunc produces the compressed trace_block result of a canonical block, see jsonrpc.NewTraceResultsProducer
+type TraceBlockFunc func(ctx context.Context, tx kv.TemporalTx, blockNum uint64) ([]byte, error)
+
+type TraceResultsCfg struct {
+	db          kv.RwDB
+	blockReader services.FullBlockReader
+	traceBlock  TraceBlockFunc
+}
+
+func StageTraceResultsCfg(db kv.RwDB, blockReader services.FullBlockReader, traceBlock TraceBlockFunc) TraceResultsCfg {
+	return TraceResultsCfg{
+		db:          db,
+		blockReader: blockReader,
+		traceBlock:  traceBlock,
+	}
+}
+
+// SpawnTraceResultsStage re-executes the executed blocks with the Parity-style tracer and records their
+// trace_block results into kv.TraceResults. BlockRetire moves them to files later.
+func SpawnTraceResultsStage(s *StageState, txc wrap.TxContainer, cfg TraceResultsCfg, ctx context.Context, logger log.Logger) (err error) {
+	if txc.Doms != nil {
+		// In-memory execution (fork validation) doesn't flush the state history the
+		// blocks are replayed on, the stage catches up once the blocks are committed
+		return nil
+	}
+	tx := txc.Tx
+	useExternalTx := tx != nil
+	if !useExternalTx {
+		tx, err = cfg.db.BeginRw(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+	}
+	ttx, ok := tx.(kv.TemporalTx)
+	if !ok {
+		return fmt.Errorf("trace results stage requires a temporal db, got %T", tx)
+	}
+
+	logPrefix := s.LogPrefix()
+	endBlock, err := s.ExecutionAt(tx)
+	if err != nil {
+		return err
+	}
+	if endBlock <= s.BlockNumber {
+		return nil
+	}
+
+	startBlock := s.BlockNumber + 1
+	if s.BlockNumber == 0 {
+		// files are read by ordinal - genesis needs a record too
+		startBlock = 0
+	}
+	if endBlock-startBlock > 16 {
+		logger.Info(fmt.Sprintf("[%s] Tracing blocks", logPrefix), "from", startBlock, "to", endBlock)
+	}
+
+	logEvery := time.NewTicker(logInterval)
+	defer logEvery.Stop()
+	for blockNum := startBlock; blockNum <= endBlock; blockNum++ {
+		data, err := cfg.traceBlock(ctx, ttx, blockNum)
+		if err != nil {
+			return fmt.Errorf("[%s] tracing block %d: %w", logPrefix, blockNum, err)
+		}
+		if err = rawdb.WriteTraceResults(tx, blockNum, data); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return libcommon.ErrStopped
+		case <-logEvery.C:
+			logger.Info(fmt.Sprintf("[%s] Progress", logPrefix), "block", blockNum)
+		default:
+		}
+	}
+
+	if err = s.Update(tx, endBlock); err != nil {
+		return err
+	}
+	if !useExternalTx {
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func UnwindTraceResultsStage(u *UnwindState, s *StageState, tx kv.RwTx, cfg TraceResultsCfg, ctx context.Context) (err error) {
+	useExternalTx := tx != nil
+	if !useExternalTx {
+		tx, err = cfg.db.BeginRw(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+	}
+
+	if err = rawdb.TruncateTraceResults(tx, u.UnwindPoint+1); err != nil {
+		return err
+	}
+	if err = u.Done(tx); err != nil {
+		return err
+	}
+
+	if !useExternalTx {
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PruneTraceResultsStage deletes from db the trace results which are already in files
+func PruneTraceResultsStage(p *PruneState, tx kv.RwTx, cfg TraceResultsCfg, ctx context.Context, logger log.Logger) (err error) {
+	useExternalTx := tx != nil
+	if !useExternalTx {
+		tx, err = cfg.db.BeginRw(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+	}
+
+	if canDeleteTo := freezeblocks.CanDeleteTo(p.ForwardProgress, cfg.blockReader.FrozenTraceResults()); canDeleteTo > 0 {
+		deleted, err := rawdb.PruneTraceResults(tx, canDeleteTo, 1_000)
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			logger.Debug(fmt.Sprintf("[%s] Pruned trace results", p.LogPrefix()), "to", canDeleteTo, "deleted", deleted)
+		}
+	}
+
+	if !useExternalTx {
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -39,6 +39,8 @@ var (
 	Senders             SyncStage = "Senders"         // "From" recovered from signatures, bodies re-written
 	Execution           SyncStage = "Execution"       // Executing each block w/o buildinf a trie
 	CustomTrace         SyncStage = "CustomTrace"     // Executing each block w/o buildinf a trie
+	Supply              SyncStage = "Supply"          // Tracing ether issuance and burn of each block
+	TraceResults        SyncStage = "TraceResults"    // Persisting trace_block results of each block
 	Translation         SyncStage = "Translation"     // Translation each marked for translation contract (from EVM to TEVM)
 	VerkleTrie          SyncStage = "VerkleTrie"
 	IntermediateHashes  SyncStage = "IntermediateHashes"  // Generate intermediate hashes, calculate the state root hash
@@ -72,6 +74,8 @@ var AllStages = []SyncStage{
 	Senders,
 	Execution,
 	CustomTrace,
+	Supply,
+	TraceResults,
 	Translation,
 	HashState,
 	IntermediateHashes,
@@ -0,0 +1,166 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package tracetest
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/dir"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+	"github.com/ledgerwatch/erigon/tests"
+	"github.com/ledgerwatch/erigon/turbo/stages/mock"
+)
+
+// flatCallTracerTest defines a single test to check the flat call tracer against,
+// result is in the format of the trace_ namespace (Parity/OpenEthereum).
+type flatCallTracerTest struct {
+	Genesis      *types.Genesis  `json:"genesis"`
+	Context      *callContext    `json:"context"`
+	Input        string          `json:"input"`
+	TracerConfig json.RawMessage `json:"tracerConfig"`
+	Result       json.RawMessage `json:"result"`
+}
+
+// flatCallTrace is a frame of flatCallTracer result, fields needed by the tests only
+type flatCallTrace struct {
+	Action struct {
+		To    libcommon.Address `json:"to"`
+		Value *hexutil.Big      `json:"value"`
+	} `json:"action"`
+	BlockHash           *libcommon.Hash `json:"blockHash"`
+	BlockNumber         *uint64         `json:"blockNumber"`
+	TransactionHash     *libcommon.Hash `json:"transactionHash"`
+	TransactionPosition *uint64         `json:"transactionPosition"`
+	Type                string          `json:"type"`
+}
+
+// runFlatCallTracer executes the transaction of the test on top of its pre-state and returns the trace
+func runFlatCallTracer(t *testing.T, test *flatCallTracerTest, tracerCtx *tracers.Context) (*chain.Rules, json.RawMessage) {
+	t.Helper()
+	tx, err := types.UnmarshalTransactionFromBinary(common.FromHex(test.Input), false /* blobTxnsAreWrappedWithBlobs */)
+	require.NoError(t, err)
+	signer := types.MakeSigner(test.Genesis.Config, uint64(test.Context.Number), uint64(test.Context.Time))
+	context := evmtypes.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    consensus.Transfer,
+		Coinbase:    test.Context.Miner,
+		BlockNumber: uint64(test.Context.Number),
+		Time:        uint64(test.Context.Time),
+		Difficulty:  (*big.Int)(test.Context.Difficulty),
+		GasLimit:    uint64(test.Context.GasLimit),
+	}
+	if test.Context.BaseFee != nil {
+		context.BaseFee, _ = uint256.FromBig((*big.Int)(test.Context.BaseFee))
+	}
+	rules := test.Genesis.Config.Rules(context.BlockNumber, context.Time)
+
+	m := mock.Mock(t)
+	dbTx, err := m.DB.BeginRw(m.Ctx)
+	require.NoError(t, err)
+	defer dbTx.Rollback()
+	statedb, err := tests.MakePreState(rules, dbTx, test.Genesis.Alloc, uint64(test.Context.Number), m.HistoryV3)
+	require.NoError(t, err)
+
+	tracer, err := tracers.New("flatCallTracer", tracerCtx, test.TracerConfig)
+	require.NoError(t, err)
+	msg, err := tx.AsMessage(*signer, (*big.Int)(test.Context.BaseFee), rules)
+	require.NoError(t, err)
+	evm := vm.NewEVM(context, core.NewEVMTxContext(msg), statedb, test.Genesis.Config, vm.Config{Debug: true, Tracer: tracer})
+	_, err = core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(tx.GetGas()).AddBlobGas(tx.GetBlobGas()), true /* refunds */, false /* gasBailout */)
+	require.NoError(t, err)
+
+	res, err := tracer.GetResult()
+	require.NoError(t, err)
+	return rules, res
+}
+
+func readFlatCallTracerTest(t *testing.T, name string) *flatCallTracerTest {
+	t.Helper()
+	blob, err := os.ReadFile(filepath.Join("testdata", "call_tracer_flat", name))
+	require.NoError(t, err)
+	test := new(flatCallTracerTest)
+	require.NoError(t, json.Unmarshal(blob, test))
+	return test
+}
+
+func TestFlatCallTracerNative(t *testing.T) {
+	files, err := dir.ReadDir(filepath.Join("testdata", "call_tracer_flat"))
+	require.NoError(t, err)
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		file := file // capture range variable
+		t.Run(camel(strings.TrimSuffix(file.Name(), ".json")), func(t *testing.T) {
+			t.Parallel()
+
+			test := readFlatCallTracerTest(t, file.Name())
+			_, res := runFlatCallTracer(t, test, new(tracers.Context))
+			require.JSONEq(t, string(test.Result), string(res))
+		})
+	}
+}
+
+func TestFlatCallTracerSkipsPrecompiles(t *testing.T) {
+	test := readFlatCallTracerTest(t, "skip_precompiles_0x536434786ace02697118c44abf2835f188bf79902807c61a523ca3a6200bc350.json")
+	rules, res := runFlatCallTracer(t, test, new(tracers.Context))
+	var have []flatCallTrace
+	require.NoError(t, json.Unmarshal(res, &have))
+	precompiles := vm.ActivePrecompiledContracts(rules)
+	for _, frame := range have {
+		if _, ok := precompiles[frame.Action.To]; ok && frame.Type == "call" {
+			require.NotZero(t, frame.Action.Value.ToInt().Sign(), "value-less precompile call must be skipped")
+		}
+	}
+}
+
+func TestFlatCallTracerContext(t *testing.T) {
+	test := readFlatCallTracerTest(t, "deep_calls.json")
+	tracerCtx := &tracers.Context{
+		BlockHash:   libcommon.HexToHash("0x01"),
+		BlockNumber: uint64(test.Context.Number),
+		TxIndex:     3,
+		TxHash:      libcommon.HexToHash("0x02"),
+	}
+	_, res := runFlatCallTracer(t, test, tracerCtx)
+	var have []flatCallTrace
+	require.NoError(t, json.Unmarshal(res, &have))
+	require.NotEmpty(t, have)
+	for i, frame := range have {
+		require.Equal(t, tracerCtx.BlockHash, *frame.BlockHash, "frame %d", i)
+		require.Equal(t, tracerCtx.BlockNumber, *frame.BlockNumber, "frame %d", i)
+		require.Equal(t, tracerCtx.TxHash, *frame.TransactionHash, "frame %d", i)
+		require.Equal(t, uint64(tracerCtx.TxIndex), *frame.TransactionPosition, "frame %d", i)
+	}
+}
@@ -0,0 +1,347 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/holiman/uint256"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+)
+
+func init() {
+	register("flatCallTracer", newFlatCallTracer)
+}
+
+// flatCallFrame is a Parity-style flat trace, in the same shape as produced by the trace_ namespace.
+type flatCallFrame struct {
+	// Do not change the ordering of these fields -- allows for easier comparison with trace_ output
+	Action              interface{}     `json:"action"`
+	BlockHash           *libcommon.Hash `json:"blockHash,omitempty"`
+	BlockNumber         *uint64         `json:"blockNumber,omitempty"`
+	Error               string          `json:"error,omitempty"`
+	Result              interface{}     `json:"result"`
+	Subtraces           int             `json:"subtraces"`
+	TraceAddress        []int           `json:"traceAddress"`
+	TransactionHash     *libcommon.Hash `json:"transactionHash,omitempty"`
+	TransactionPosition *uint64         `json:"transactionPosition,omitempty"`
+	Type                string          `json:"type"`
+}
+
+type flatCallAction struct {
+	From     libcommon.Address `json:"from"`
+	CallType string            `json:"callType"`
+	Gas      hexutil.Uint64    `json:"gas"`
+	Input    hexutility.Bytes  `json:"input"`
+	To       libcommon.Address `json:"to"`
+	Value    hexutil.Big       `json:"value"`
+}
+
+type flatCreateAction struct {
+	From  libcommon.Address `json:"from"`
+	Gas   hexutil.Uint64    `json:"gas"`
+	Init  hexutility.Bytes  `json:"init"`
+	Value hexutil.Big       `json:"value"`
+}
+
+type flatSuicideAction struct {
+	Address       libcommon.Address `json:"address"`
+	RefundAddress libcommon.Address `json:"refundAddress"`
+	Balance       hexutil.Big       `json:"balance"`
+}
+
+type flatCallResult struct {
+	GasUsed hexutil.Uint64   `json:"gasUsed"`
+	Output  hexutility.Bytes `json:"output"`
+}
+
+type flatCreateResult struct {
+	Address *libcommon.Address `json:"address,omitempty"`
+	Code    hexutility.Bytes   `json:"code"`
+	GasUsed hexutil.Uint64     `json:"gasUsed"`
+}
+
+// parityErrorMapping maps the EVM error messages to the ones reported by Parity/OpenEthereum.
+var parityErrorMapping = map[string]string{
+	"contract creation code storage out of gas": "Out of gas",
+	"out of gas":                      "Out of gas",
+	"gas uint64 overflow":             "Out of gas",
+	"max code size exceeded":          "Out of gas",
+	"invalid jump destination":        "Bad jump destination",
+	"execution reverted":              "Reverted",
+	"return data out of bounds":       "Out of bounds",
+	"stack limit reached 1024 (1023)": "Out of stack",
+	"precompiled failed":              "Built-in failed",
+	"invalid input length":            "Built-in failed",
+}
+
+var parityErrorMappingStartingWith = map[string]string{
+	"invalid opcode:": "Bad instruction",
+	"stack underflow": "Stack underflow",
+}
+
+type flatCallTracerConfig struct {
+	ConvertParityErrors bool `json:"convertParityErrors"` // If true, errors are reported the way Parity/OpenEthereum does
+	IncludePrecompiles  bool `json:"includePrecompiles"`  // If true, value-less calls to precompiles are reported
+}
+
+// flatCallTracer reports the call frames of a transaction as a flat list of
+// Parity-style traces. It reuses the callTracer to build the call tree and
+// flattens it once the transaction is done.
+type flatCallTracer struct {
+	tracer    *callTracer
+	config    flatCallTracerConfig
+	ctx       *tracers.Context // Holds tracer context data
+	gas       uint64           // Gas available to the top call, after intrinsic gas
+	gasUsed   uint64           // Gas used by the top call, excluding intrinsic gas
+	skipped   []bool           // Whether the frames on the call stack are dropped from the output
+	interrupt uint32           // Atomic flag to signal execution interruption
+	reason    error            // Textual reason for the interruption
+}
+
+// newFlatCallTracer returns a native go tracer which produces trace_-compatible
+// flat call frames, and implements vm.EVMLogger.
+func newFlatCallTracer(ctx *tracers.Context, cfg json.RawMessage) (tracers.Tracer, error) {
+	var config flatCallTracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	// Precompiles are filtered out here, as Parity only skips the value-less ones
+	tracer, err := newCallTracer(ctx, json.RawMessage(`{"includePrecompiles":true}`))
+	if err != nil {
+		return nil, err
+	}
+	return &flatCallTracer{tracer: tracer.(*callTracer), config: config, ctx: ctx}, nil
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *flatCallTracer) CaptureStart(env *vm.EVM, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	t.gas = gas
+	t.tracer.CaptureStart(env, from, to, precompile, create, input, gas, value, code)
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *flatCallTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	t.gasUsed = gasUsed
+	if len(t.tracer.callstack) == 0 {
+		return
+	}
+	to := t.tracer.callstack[0].To
+	t.tracer.CaptureEnd(output, gasUsed, err)
+	// callTracer drops the address of failed creation, Parity reports it for reverted ones
+	t.tracer.callstack[0].To = to
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *flatCallTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+// CaptureFault implements the EVMLogger interface to trace an execution fault.
+func (t *flatCallTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, _ *vm.ScopeContext, depth int, err error) {
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *flatCallTracer) CaptureEnter(typ vm.OpCode, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	// Skip if tracing was interrupted
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		return
+	}
+	t.tracer.CaptureEnter(typ, from, to, precompile, create, input, gas, value, code)
+	// Parity traces don't include value-less calls to precompiles
+	skip := precompile && !t.config.IncludePrecompiles && (value == nil || value.IsZero())
+	t.skipped = append(t.skipped, skip)
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *flatCallTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		return
+	}
+	size := len(t.tracer.callstack)
+	var to libcommon.Address
+	if size > 1 {
+		to = t.tracer.callstack[size-1].To
+	}
+	t.tracer.CaptureExit(output, gasUsed, err)
+	if size > 1 && len(t.tracer.callstack) == size-1 {
+		// callTracer drops the address of failed creation, Parity reports it for reverted ones
+		parent := &t.tracer.callstack[size-2]
+		parent.Calls[len(parent.Calls)-1].To = to
+	}
+	if len(t.skipped) == 0 {
+		return
+	}
+	skip := t.skipped[len(t.skipped)-1]
+	t.skipped = t.skipped[:len(t.skipped)-1]
+	if !skip {
+		return
+	}
+	// the call has been nested into its parent by the callTracer, remove it
+	parent := &t.tracer.callstack[len(t.tracer.callstack)-1]
+	if len(parent.Calls) > 0 {
+		parent.Calls = parent.Calls[:len(parent.Calls)-1]
+	}
+}
+
+func (t *flatCallTracer) CaptureTxStart(gasLimit uint64) {
+	t.tracer.CaptureTxStart(gasLimit)
+}
+
+func (t *flatCallTracer) CaptureTxEnd(restGas uint64) {
+	t.tracer.CaptureTxEnd(restGas)
+}
+
+// GetResult returns the json-encoded list of flat call traces, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *flatCallTracer) GetResult() (json.RawMessage, error) {
+	if len(t.tracer.callstack) != 1 {
+		return nil, errors.New("incorrect number of top-level calls")
+	}
+	top := t.tracer.callstack[0]
+	// Parity reports the gas of the top call without the intrinsic gas
+	top.Gas, top.GasUsed = t.gas, t.gasUsed
+	flat, err := t.flatten(&top, []int{})
+	if err != nil {
+		return nil, err
+	}
+	res, err := json.Marshal(flat)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *flatCallTracer) Stop(err error) {
+	t.tracer.Stop(err)
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+// flatten converts the nested call frame and its children into flat frames, in depth-first order.
+func (t *flatCallTracer) flatten(call *callFrame, traceAddress []int) ([]flatCallFrame, error) {
+	frame := flatCallFrame{
+		Error:        call.Error,
+		Subtraces:    len(call.Calls),
+		TraceAddress: traceAddress,
+	}
+	value := new(hexutil.Big)
+	if call.Value != nil {
+		value = (*hexutil.Big)(call.Value)
+	}
+
+	switch call.Type {
+	case vm.CREATE, vm.CREATE2:
+		frame.Type = "create"
+		frame.Action = &flatCreateAction{From: call.From, Gas: hexutil.Uint64(call.Gas), Init: call.Input, Value: *value}
+		result := &flatCreateResult{Code: call.Output, GasUsed: hexutil.Uint64(call.GasUsed)}
+		if call.To != (libcommon.Address{}) {
+			to := call.To
+			result.Address = &to
+		}
+		frame.Result = result
+	case vm.SELFDESTRUCT:
+		frame.Type = "suicide"
+		frame.Action = &flatSuicideAction{Address: call.From, RefundAddress: call.To, Balance: *value}
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		frame.Type = "call"
+		if call.Type == vm.STATICCALL {
+			value = new(hexutil.Big)
+		}
+		frame.Action = &flatCallAction{
+			From:     call.From,
+			CallType: strings.ToLower(call.Type.String()),
+			Gas:      hexutil.Uint64(call.Gas),
+			Input:    call.Input,
+			To:       call.To,
+			Value:    *value,
+		}
+		frame.Result = &flatCallResult{GasUsed: hexutil.Uint64(call.GasUsed), Output: call.Output}
+	default:
+		return nil, fmt.Errorf("unrecognized call frame type: %s", call.Type)
+	}
+
+	if frame.Error != "" {
+		// Revert output contains useful information (revert reason), otherwise the result is discarded
+		if frame.Error != vm.ErrExecutionReverted.Error() {
+			frame.Result = nil
+		}
+		frame.Error = t.convertError(frame.Error)
+	}
+	t.fillContext(&frame)
+
+	output := []flatCallFrame{frame}
+	for i := range call.Calls {
+		childAddress := make([]int, len(traceAddress)+1)
+		copy(childAddress, traceAddress)
+		childAddress[len(traceAddress)] = i
+		children, err := t.flatten(&call.Calls[i], childAddress)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, children...)
+	}
+	return output, nil
+}
+
+// convertError reports reverts the way the trace_ namespace does, and optionally
+// every other error the way Parity does.
+func (t *flatCallTracer) convertError(err string) string {
+	if !t.config.ConvertParityErrors {
+		if err == vm.ErrExecutionReverted.Error() {
+			return "Reverted"
+		}
+		return err
+	}
+	if parityError, ok := parityErrorMapping[err]; ok {
+		return parityError
+	}
+	for gethError, parityError := range parityErrorMappingStartingWith {
+		if strings.HasPrefix(err, gethError) {
+			return parityError
+		}
+	}
+	return err
+}
+
+func (t *flatCallTracer) fillContext(frame *flatCallFrame) {
+	if t.ctx == nil {
+		return
+	}
+	if t.ctx.BlockHash != (libcommon.Hash{}) {
+		blockHash, blockNumber := t.ctx.BlockHash, t.ctx.BlockNumber
+		frame.BlockHash = &blockHash
+		frame.BlockNumber = &blockNumber
+	}
+	if t.ctx.TxHash != (libcommon.Hash{}) {
+		txHash, txIndex := t.ctx.TxHash, uint64(t.ctx.TxIndex)
+		frame.TransactionHash = &txHash
+		frame.TransactionPosition = &txIndex
+	}
+}
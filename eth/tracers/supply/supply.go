@@ -0,0 +1,195 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package supply
+
+import (
+	"math/big"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/consensus/misc"
+	"github.com/ledgerwatch/erigon/core/tracing"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+)
+
+// Tracer accumulates the ether supply changes of a block: issuance by block
+// rewards and withdrawals, EIP-1559 and EIP-4844 fee burns and ether destroyed
+// by self-destructs.
+//
+// The state events are received through Hooks, which must be installed into
+// the IntraBlockState executing the block. The tracer must also be set as the
+// EVM tracer, so that self-destructs of reverted call frames are discarded.
+type Tracer struct {
+	chainConfig *chain.Config
+	delta       types.SupplyDelta
+	err         error
+
+	// Net balance change caused by self-destructs in every open call frame.
+	// It is negative when ether is burnt.
+	callstack []*big.Int
+}
+
+var _ vm.EVMLogger = (*Tracer)(nil)
+
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+// Hooks returns the chain and state hooks of the tracer.
+func (t *Tracer) Hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnBlockchainInit: t.OnBlockchainInit,
+		OnBlockStart:     t.OnBlockStart,
+		OnGenesisBlock:   t.OnGenesisBlock,
+		OnBalanceChange:  t.OnBalanceChange,
+	}
+}
+
+// Delta returns the supply delta of the current block.
+func (t *Tracer) Delta() (*types.SupplyDelta, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	delta := t.delta
+	return &delta, nil
+}
+
+func (t *Tracer) OnBlockchainInit(chainConfig *chain.Config) {
+	t.chainConfig = chainConfig
+}
+
+func (t *Tracer) OnGenesisBlock(genesis *types.Block, alloc types.GenesisAlloc) {
+	t.reset()
+	for _, account := range alloc {
+		if account.Balance == nil {
+			continue
+		}
+		balance, overflow := uint256.FromBig(account.Balance)
+		if overflow {
+			continue
+		}
+		t.delta.GenesisAlloc.Add(&t.delta.GenesisAlloc, balance)
+	}
+}
+
+func (t *Tracer) OnBlockStart(event tracing.BlockEvent) {
+	t.reset()
+	header := event.Block.HeaderNoCopy()
+	number := header.Number.Uint64()
+
+	// Base fee is sent to a contract instead of being burnt on some chains
+	if header.BaseFee != nil && t.chainConfig.GetBurntContract(number) == nil {
+		baseFee, _ := uint256.FromBig(header.BaseFee)
+		t.delta.EIP1559Burn.Mul(baseFee, uint256.NewInt(header.GasUsed))
+	}
+	if header.BlobGasUsed != nil && header.ExcessBlobGas != nil {
+		blobGasPrice, err := misc.GetBlobGasPrice(t.chainConfig, *header.ExcessBlobGas)
+		if err != nil {
+			t.err = err
+			return
+		}
+		t.delta.BlobBurn.Mul(blobGasPrice, uint256.NewInt(*header.BlobGasUsed))
+	}
+}
+
+// OnBalanceChange must not retain prev and next, they are mutated by the state afterwards.
+func (t *Tracer) OnBalanceChange(addr libcommon.Address, prev, next *uint256.Int, reason tracing.BalanceChangeReason) {
+	switch reason {
+	case tracing.BalanceIncreaseRewardMineBlock, tracing.BalanceIncreaseRewardMineUncle:
+		t.delta.Reward.Add(&t.delta.Reward, new256(next).Sub(next, prev))
+	case tracing.BalanceIncreaseWithdrawal:
+		t.delta.Withdrawals.Add(&t.delta.Withdrawals, new256(next).Sub(next, prev))
+	case tracing.BalanceDecreaseSelfdestructBurn:
+		// Happens at the end of the transaction, so it can't be reverted
+		t.delta.MiscBurn.Add(&t.delta.MiscBurn, new256(prev).Sub(prev, next))
+	case tracing.BalanceIncreaseSelfdestruct, tracing.BalanceDecreaseSelfdestruct:
+		// A self-destruct moves the balance to the beneficiary, which only
+		// burns ether if the beneficiary is the self-destructing contract itself
+		if len(t.callstack) == 0 {
+			return
+		}
+		frame := t.callstack[len(t.callstack)-1]
+		frame.Add(frame, next.ToBig())
+		frame.Sub(frame, prev.ToBig())
+	}
+}
+
+func new256(x *uint256.Int) *uint256.Int {
+	return new(uint256.Int).Set(x)
+}
+
+func (t *Tracer) reset() {
+	t.delta = types.SupplyDelta{}
+	t.err = nil
+	t.callstack = t.callstack[:0]
+}
+
+func (t *Tracer) enter() {
+	t.callstack = append(t.callstack, new(big.Int))
+}
+
+func (t *Tracer) exit(err error) {
+	if len(t.callstack) == 0 {
+		return
+	}
+	frame := t.callstack[len(t.callstack)-1]
+	t.callstack = t.callstack[:len(t.callstack)-1]
+	if err != nil {
+		// The state changes of a failed frame are reverted
+		return
+	}
+	if len(t.callstack) > 0 {
+		parent := t.callstack[len(t.callstack)-1]
+		parent.Add(parent, frame)
+		return
+	}
+	if frame.Sign() < 0 {
+		burnt, _ := uint256.FromBig(frame.Neg(frame))
+		t.delta.MiscBurn.Add(&t.delta.MiscBurn, burnt)
+	}
+}
+
+func (t *Tracer) CaptureTxStart(gasLimit uint64) {}
+
+func (t *Tracer) CaptureTxEnd(restGas uint64) {}
+
+func (t *Tracer) CaptureStart(env *vm.EVM, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	t.callstack = t.callstack[:0]
+	t.enter()
+}
+
+func (t *Tracer) CaptureEnd(output []byte, usedGas uint64, err error) {
+	t.exit(err)
+}
+
+func (t *Tracer) CaptureEnter(typ vm.OpCode, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	t.enter()
+}
+
+func (t *Tracer) CaptureExit(output []byte, usedGas uint64, err error) {
+	t.exit(err)
+}
+
+func (t *Tracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *Tracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
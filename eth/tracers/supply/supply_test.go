@@ -0,0 +1,98 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package supply
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core/tracing"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+func TestSupplyTracer(t *testing.T) {
+	var (
+		contract = libcommon.HexToAddress("0x1000")
+		other    = libcommon.HexToAddress("0x2000")
+		zero     = new(uint256.Int)
+	)
+	tracer := NewTracer()
+	hooks := tracer.Hooks()
+	hooks.OnBlockchainInit(params.TestChainConfig)
+
+	header := &types.Header{Number: big.NewInt(1), GasUsed: 100, BaseFee: big.NewInt(7)}
+	hooks.OnBlockStart(tracing.BlockEvent{Block: types.NewBlockWithHeader(header)})
+
+	// self-destruct to itself burns the balance
+	tracer.CaptureStart(nil, other, contract, false, false, nil, 0, zero, nil)
+	hooks.OnBalanceChange(contract, uint256.NewInt(10), uint256.NewInt(20), tracing.BalanceIncreaseSelfdestruct)
+	hooks.OnBalanceChange(contract, uint256.NewInt(20), zero, tracing.BalanceDecreaseSelfdestruct)
+	tracer.CaptureEnd(nil, 0, nil)
+
+	// self-destruct of a reverted frame doesn't burn anything
+	tracer.CaptureStart(nil, other, contract, false, false, nil, 0, zero, nil)
+	tracer.CaptureEnter(vm.CALL, other, contract, false, false, nil, 0, zero, nil)
+	hooks.OnBalanceChange(contract, uint256.NewInt(5), uint256.NewInt(10), tracing.BalanceIncreaseSelfdestruct)
+	hooks.OnBalanceChange(contract, uint256.NewInt(10), zero, tracing.BalanceDecreaseSelfdestruct)
+	tracer.CaptureExit(nil, 0, vm.ErrExecutionReverted)
+	tracer.CaptureEnd(nil, 0, nil)
+
+	// self-destruct to another account only moves the balance
+	tracer.CaptureStart(nil, other, contract, false, false, nil, 0, zero, nil)
+	hooks.OnBalanceChange(other, zero, uint256.NewInt(3), tracing.BalanceIncreaseSelfdestruct)
+	hooks.OnBalanceChange(contract, uint256.NewInt(3), zero, tracing.BalanceDecreaseSelfdestruct)
+	tracer.CaptureEnd(nil, 0, nil)
+
+	hooks.OnBalanceChange(contract, uint256.NewInt(4), zero, tracing.BalanceDecreaseSelfdestructBurn)
+	hooks.OnBalanceChange(other, uint256.NewInt(3), uint256.NewInt(5), tracing.BalanceIncreaseRewardMineBlock)
+	hooks.OnBalanceChange(other, uint256.NewInt(5), uint256.NewInt(11), tracing.BalanceIncreaseWithdrawal)
+	hooks.OnBalanceChange(other, uint256.NewInt(11), uint256.NewInt(1), tracing.BalanceChangeTransfer)
+
+	delta, err := tracer.Delta()
+	require.NoError(t, err)
+	require.Equal(t, uint64(700), delta.EIP1559Burn.Uint64())
+	require.Equal(t, uint64(14), delta.MiscBurn.Uint64())
+	require.Equal(t, uint64(2), delta.Reward.Uint64())
+	require.Equal(t, uint64(6), delta.Withdrawals.Uint64())
+	require.Equal(t, big.NewInt(2+6-700-14), delta.Delta())
+
+	// the next block starts from scratch
+	hooks.OnBlockStart(tracing.BlockEvent{Block: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2)})})
+	delta, err = tracer.Delta()
+	require.NoError(t, err)
+	require.Zero(t, delta.Delta().Sign())
+}
+
+func TestSupplyTracerGenesis(t *testing.T) {
+	tracer := NewTracer()
+	hooks := tracer.Hooks()
+	hooks.OnGenesisBlock(nil, types.GenesisAlloc{
+		libcommon.HexToAddress("0x1000"): {Balance: big.NewInt(1000)},
+		libcommon.HexToAddress("0x2000"): {Balance: big.NewInt(24)},
+	})
+	delta, err := tracer.Delta()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1024), delta.GenesisAlloc.Uint64())
+	require.Equal(t, big.NewInt(1024), delta.Delta())
+}
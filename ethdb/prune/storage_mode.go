@@ -153,6 +153,12 @@ func Get(db kv.Getter) (Mode, error) {
 		prune.Blocks = blockAmount
 	}
 
+	v, err := db.GetOne(kv.DatabaseInfo, kv.PruneCommitmentHistory)
+	if err != nil {
+		return prune, err
+	}
+	prune.CommitmentHistory = len(v) == 1 && v[0] == 1
+
 	return prune, nil
 }
 
@@ -164,6 +170,8 @@ type Mode struct {
 	CallTraces  BlockAmount
 	Blocks      BlockAmount
 	Experiments Experiments
+
+	CommitmentHistory bool // keep history of commitment domain, needed for proofs and witnesses of past blocks
 }
 
 type BlockAmount interface {
@@ -256,6 +264,9 @@ func (m Mode) String() string {
 			long += fmt.Sprintf(" --prune.c.%s=%d", m.CallTraces.dbType(), m.CallTraces.toValue())
 		}
 	}
+	if m.CommitmentHistory {
+		long += " --prune.include-commitment-history"
+	}
 
 	return strings.TrimLeft(short+long, " ")
 }
@@ -290,6 +301,11 @@ func Override(db kv.RwTx, sm Mode) error {
 		return err
 	}
 
+	err = setMode(db, kv.PruneCommitmentHistory, sm.CommitmentHistory)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -346,6 +362,11 @@ func setIfNotExist(db kv.GetPut, pm Mode) error {
 		}
 	}
 
+	err = setModeOnEmpty(db, kv.PruneCommitmentHistory, pm.CommitmentHistory)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
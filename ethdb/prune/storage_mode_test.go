@@ -31,16 +31,16 @@ func TestSetStorageModeIfNotExist(t *testing.T) {
 	prune, err := Get(tx)
 	assert.NoError(t, err)
 	assert.Equal(t, Mode{true, Distance(math.MaxUint64), Distance(math.MaxUint64),
-		Distance(math.MaxUint64), Distance(math.MaxUint64), Distance(math.MaxUint64), Experiments{}}, prune)
+		Distance(math.MaxUint64), Distance(math.MaxUint64), Distance(math.MaxUint64), Experiments{}, false}, prune)
 
 	err = setIfNotExist(tx, Mode{true, Distance(1), Distance(2),
-		Before(3), Before(4), Before(100), Experiments{}})
+		Before(3), Before(4), Before(100), Experiments{}, true})
 	assert.NoError(t, err)
 
 	prune, err = Get(tx)
 	assert.NoError(t, err)
 	assert.Equal(t, Mode{true, Distance(1), Distance(2),
-		Before(3), Before(4), Before(100), Experiments{}}, prune)
+		Before(3), Before(4), Before(100), Experiments{}, true}, prune)
 }
 
 var distanceTests = []struct {
@@ -33,6 +33,7 @@ import (
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/core/vm"
 	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+	"github.com/ledgerwatch/erigon/eth/tracers"
 	tracersConfig "github.com/ledgerwatch/erigon/eth/tracers/config"
 	"github.com/ledgerwatch/erigon/polygon/bor/borcfg"
 	bortypes "github.com/ledgerwatch/erigon/polygon/bor/types"
@@ -62,7 +63,12 @@ func TraceBorStateSyncTxnDebugAPI(
 	}
 
 	txCtx := initStateSyncTxContext(blockNum, blockHash)
-	tracer, streaming, cancel, err := transactions.AssembleTracer(ctx, traceConfig, txCtx.TxHash, stream, callTimeout)
+	tracer, streaming, cancel, err := transactions.AssembleTracer(ctx, traceConfig, &tracers.Context{
+		BlockHash:   blockHash,
+		BlockNumber: blockNum,
+		TxIndex:     ibs.TxIndex(),
+		TxHash:      txCtx.TxHash,
+	}, stream, callTimeout)
 	if err != nil {
 		stream.WriteNil()
 		return err
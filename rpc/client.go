@@ -81,6 +81,8 @@ type Client struct {
 	isHTTP          bool
 	services        *serviceRegistry
 	methodAllowList AllowList
+	rateLimiter     *RateLimiter // of calls served to the remote side
+	resultCache     ResultCache
 
 	idCounter uint32
 
@@ -117,6 +119,8 @@ type clientConn struct {
 func (c *Client) newClientConn(conn ServerCodec) *clientConn {
 	ctx := context.WithValue(context.Background(), clientContextKey{}, c)
 	handler := newHandler(ctx, conn, c.idgen, c.services, c.methodAllowList, 50, false /* traceRequests */, c.logger, 0)
+	handler.rateLimiter = c.rateLimiter
+	handler.resultCache = c.resultCache
 	return &clientConn{conn, handler}
 }
 
@@ -201,17 +205,19 @@ func newClient(initctx context.Context, connect reconnectFunc, logger log.Logger
 	if err != nil {
 		return nil, err
 	}
-	c := initClient(conn, randomIDGenerator(), &serviceRegistry{logger: logger}, logger)
+	c := initClient(conn, randomIDGenerator(), &serviceRegistry{logger: logger}, nil, nil, logger)
 	c.reconnectFunc = connect
 	return c, nil
 }
 
-func initClient(conn ServerCodec, idgen func() ID, services *serviceRegistry, logger log.Logger) *Client {
+func initClient(conn ServerCodec, idgen func() ID, services *serviceRegistry, rateLimiter *RateLimiter, resultCache ResultCache, logger log.Logger) *Client {
 	_, isHTTP := conn.(*httpConn)
 	c := &Client{
 		idgen:       idgen,
 		isHTTP:      isHTTP,
 		services:    services,
+		rateLimiter: rateLimiter,
+		resultCache: resultCache,
 		writeConn:   conn,
 		close:       make(chan struct{}),
 		closing:     make(chan struct{}),
@@ -29,6 +29,7 @@ var (
 	_ Error = new(invalidMessageError)
 	_ Error = new(InvalidParamsError)
 	_ Error = new(CustomError)
+	_ Error = new(rateLimitedError)
 )
 
 const defaultErrorCode = -32000
@@ -92,3 +93,18 @@ type CustomError struct {
 func (e *CustomError) ErrorCode() int { return e.Code }
 
 func (e *CustomError) Error() string { return e.Message }
+
+// call was rejected by RateLimiter. Code is "limit exceeded" of EIP-1474
+type rateLimitedError struct {
+	method string
+	client bool // limit of the client, not of the method, is exceeded
+}
+
+func (e *rateLimitedError) ErrorCode() int { return -32005 }
+
+func (e *rateLimitedError) Error() string {
+	if e.client {
+		return fmt.Sprintf("rate limit exceeded: too many requests from the client, method %s", e.method)
+	}
+	return fmt.Sprintf("rate limit exceeded: too many requests of method %s", e.method)
+}
@@ -71,6 +71,8 @@ type handler struct {
 
 	allowList     AllowList // a list of explicitly allowed methods, if empty -- everything is allowed
 	forbiddenList ForbiddenList
+	rateLimiter   *RateLimiter // nil - no limits
+	resultCache   ResultCache  // nil - no caching
 
 	subLock             sync.Mutex
 	serverSubs          map[ID]*Subscription
@@ -87,8 +89,11 @@ type callProc struct {
 	notifiers []*Notifier
 }
 
+// HandleError - writes err as the "error" field of the object being streamed. The stream remembers it in its
+// Attachment: the call may still return nil error, but its result isn't a successful one (see runCacheableMethod)
 func HandleError(err error, stream *jsoniter.Stream) {
 	if err != nil {
+		stream.Attachment = err
 		stream.WriteObjectField("error")
 		stream.WriteObjectStart()
 		stream.WriteObjectField("code")
@@ -478,12 +483,22 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage, stream *jsoniter
 	if callb == nil {
 		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
 	}
+	if callb != h.unsubscribeCb {
+		if err := h.checkRateLimit(msg.Method); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
 	args, err := parsePositionalArguments(msg.Params, callb.argTypes)
 	if err != nil {
 		return msg.errorResponse(&InvalidParamsError{err.Error()})
 	}
 	start := time.Now()
-	answer := h.runMethod(cp.ctx, msg, callb, args, stream)
+	var answer *jsonrpcMessage
+	if params, ok := h.cacheableParams(msg.Method, callb, args); ok {
+		answer = h.runCacheableMethod(cp.ctx, msg, callb, args, params)
+	} else {
+		answer = h.runMethod(cp.ctx, msg, callb, args, stream)
+	}
 
 	// Collect the statistics for RPC calls if metrics is enabled.
 	// We only care about pure rpc call. Filter out subscription.
@@ -513,6 +528,9 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage, stream *jso
 	if callb == nil {
 		return msg.errorResponse(&subscriptionNotFoundError{namespace, name})
 	}
+	if err := h.checkRateLimit(msg.Method); err != nil {
+		return msg.errorResponse(err)
+	}
 
 	// Parse subscription name arg too, but remove it before calling the callback.
 	argTypes := append([]reflect.Type{stringType}, callb.argTypes...)
@@ -530,6 +548,14 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage, stream *jso
 	return h.runMethod(ctx, msg, callb, args, stream)
 }
 
+// checkRateLimit takes tokens of the call from the rate limiter, if any.
+func (h *handler) checkRateLimit(method string) error {
+	if h.rateLimiter == nil {
+		return nil
+	}
+	return h.rateLimiter.allow(method, rateLimitClientID(h.conn))
+}
+
 // runMethod runs the Go callback for an RPC method.
 func (h *handler) runMethod(ctx context.Context, msg *jsonrpcMessage, callb *callback, args []reflect.Value, stream *jsoniter.Stream) *jsonrpcMessage {
 	if !callb.streamable {
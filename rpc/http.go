@@ -225,6 +225,11 @@ func (t *httpServerConn) RemoteAddr() string {
 	return t.r.RemoteAddr
 }
 
+// jwtSubject returns subject of the JWT the request was authenticated with, if any.
+func (t *httpServerConn) jwtSubject() string {
+	return jwtSubjectFromContext(t.r.Context())
+}
+
 // SetWriteDeadline does nothing and always returns nil.
 func (t *httpServerConn) SetWriteDeadline(time.Time) error { return nil }
 
@@ -306,6 +311,31 @@ func validateRequest(r *http.Request) (int, error) {
 }
 
 func CheckJwtSecret(w http.ResponseWriter, r *http.Request, jwtSecret []byte) bool {
+	_, ok := checkJwtSecret(w, r, jwtSecret)
+	return ok
+}
+
+type jwtSubjectKey struct{}
+
+// CheckJwtSecretAndSubject - same as CheckJwtSecret, but also returns the request carrying subject of the token,
+// so RateLimiter applies client limits per subject instead of per remote IP
+func CheckJwtSecretAndSubject(w http.ResponseWriter, r *http.Request, jwtSecret []byte) (*http.Request, bool) {
+	subject, ok := checkJwtSecret(w, r, jwtSecret)
+	if !ok {
+		return r, false
+	}
+	if subject == "" {
+		return r, true
+	}
+	return r.WithContext(context.WithValue(r.Context(), jwtSubjectKey{}, subject)), true
+}
+
+func jwtSubjectFromContext(ctx context.Context) string {
+	subject, _ := ctx.Value(jwtSubjectKey{}).(string)
+	return subject
+}
+
+func checkJwtSecret(w http.ResponseWriter, r *http.Request, jwtSecret []byte) (subject string, ok bool) {
 	var tokenStr string
 	// Check if JWT signature is correct
 	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
@@ -314,7 +344,7 @@ func CheckJwtSecret(w http.ResponseWriter, r *http.Request, jwtSecret []byte) bo
 
 	if len(tokenStr) == 0 {
 		http.Error(w, "missing token", http.StatusForbidden)
-		return false
+		return "", false
 	}
 
 	keyFunc := func(token *jwt.Token) (interface{}, error) {
@@ -342,8 +372,8 @@ func CheckJwtSecret(w http.ResponseWriter, r *http.Request, jwtSecret []byte) bo
 	case time.Until(claims.IssuedAt.Time) > jwtTokenExpiry:
 		http.Error(w, "future token", http.StatusForbidden)
 	default:
-		return true
+		return claims.Subject, true
 	}
 
-	return false
+	return "", false
 }
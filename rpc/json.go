@@ -114,8 +114,8 @@ func errorMessage(err error) *jsonrpcMessage {
 		Code:    defaultErrorCode,
 		Message: err.Error(),
 	}}
-	ec, ok := err.(Error)
-	if ok {
+	var ec Error
+	if errors.As(err, &ec) { // error code survives wrapping: `fmt.Errorf("...: %w", err)`
 		msg.Error.Code = ec.ErrorCode()
 	}
 	de, ok := err.(DataError)
@@ -168,6 +168,7 @@ type ConnRemoteAddr interface {
 // support for parsing arguments and serializing (result) objects.
 type jsonCodec struct {
 	remote  string
+	subject string                    // of JWT the connection was authenticated with
 	closer  sync.Once                 // close closed channel once
 	closeCh chan interface{}          // closed on Close
 	decode  func(v interface{}) error // decoder to allow multiple transports
@@ -189,6 +190,9 @@ func NewFuncCodec(conn deadlineCloser, encode, decode func(v interface{}) error)
 	if ra, ok := conn.(ConnRemoteAddr); ok {
 		codec.remote = ra.RemoteAddr()
 	}
+	if js, ok := conn.(interface{ jwtSubject() string }); ok {
+		codec.subject = js.jwtSubject()
+	}
 	return codec
 }
 
@@ -205,6 +209,10 @@ func (c *jsonCodec) remoteAddr() string {
 	return c.remote
 }
 
+func (c *jsonCodec) jwtSubject() string {
+	return c.subject
+}
+
 func (c *jsonCodec) ReadBatch() (messages []*jsonrpcMessage, batch bool, err error) {
 	// Decode the next JSON object in the input stream.
 	// This verifies basic syntax, etc.
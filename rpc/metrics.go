@@ -31,6 +31,8 @@ var (
 	rpcMetricsLabels   = map[bool]map[string]string{}
 	rpcRequestGauge    = metrics.GetOrCreateCounter("rpc_total")
 	failedReqeustGauge = metrics.GetOrCreateCounter("rpc_failure")
+	resultCacheHits    = metrics.GetOrCreateCounter(`rpc_result_cache{result="hit"}`)
+	resultCacheMisses  = metrics.GetOrCreateCounter(`rpc_result_cache{result="miss"}`)
 )
 
 // PreAllocateRPCMetricLabels pre-allocates labels for all rpc methods inside API List
@@ -96,3 +98,8 @@ func newRPCServingTimerMS(method string, valid bool) metrics.Summary {
 
 	return metrics.GetOrCreateSummary(label)
 }
+
+// rateLimitedCounter - calls rejected by RateLimiter, limit is "method" or "client"
+func rateLimitedCounter(method, limit string) metrics.Counter {
+	return metrics.GetOrCreateCounter(fmt.Sprintf(`rpc_rate_limited_total{method="%s",limit="%s"}`, method, limit))
+}
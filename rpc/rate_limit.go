@@ -0,0 +1,167 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/time/rate"
+)
+
+// maxRateLimitedClients - buckets of clients not seen for a while are evicted, so such clients start with full bucket
+const maxRateLimitedClients = 16_384
+
+// RateLimitBucket - token bucket refilled by Rate tokens per second, holding at most Burst tokens
+type RateLimitBucket struct {
+	Rate  float64 `json:"rate"`  // 0 - unlimited
+	Burst int     `json:"burst"` // Rate (rounded up) if not set
+}
+
+func (b RateLimitBucket) burst() int {
+	if b.Burst > 0 {
+		return b.Burst
+	}
+	return int(b.Rate + 0.999)
+}
+
+// RateLimitConfig - every call takes cost of its method from the bucket of the method (shared by all clients)
+// and from the bucket of the client. Client is identified by JWT subject if connection is authenticated
+// by JWT, otherwise by remote IP. Calls over in-process and IPC connections have no client bucket.
+//
+// Keys of Methods and Costs are method names or namespace wildcards like "debug_*". Subscriptions are
+// limited as calls of "<namespace>_subscribe"; "<namespace>_unsubscribe" is never limited.
+type RateLimitConfig struct {
+	Client  RateLimitBucket            `json:"client"`
+	Methods map[string]RateLimitBucket `json:"methods"`
+	Costs   map[string]int             `json:"costs"` // 1 if not set
+}
+
+// RateLimiter - limits calls by RateLimitConfig. Safe for concurrent use.
+type RateLimiter struct {
+	cfg     RateLimitConfig
+	methods map[string]*rate.Limiter // by key of cfg.Methods
+	clients *lru.Cache[string, *rate.Limiter]
+}
+
+func NewRateLimiter(cfg RateLimitConfig) (*RateLimiter, error) {
+	maxCost := 1
+	for method, cost := range cfg.Costs {
+		if cost <= 0 {
+			return nil, fmt.Errorf("rate limit: cost of %s must be positive, got %d", method, cost)
+		}
+		maxCost = max(maxCost, cost)
+	}
+	if cfg.Client.Rate < 0 {
+		return nil, fmt.Errorf("rate limit: negative client rate %f", cfg.Client.Rate)
+	}
+	if cfg.Client.Rate > 0 && cfg.Client.burst() < maxCost {
+		return nil, fmt.Errorf("rate limit: client burst %d is less than max cost %d, such calls would be always rejected", cfg.Client.burst(), maxCost)
+	}
+
+	l := &RateLimiter{cfg: cfg, methods: make(map[string]*rate.Limiter, len(cfg.Methods))}
+	for method, bucket := range cfg.Methods {
+		if bucket.Rate < 0 {
+			return nil, fmt.Errorf("rate limit: negative rate %f of %s", bucket.Rate, method)
+		}
+		if bucket.Rate == 0 {
+			continue
+		}
+		if cost := l.cost(method); bucket.burst() < cost {
+			return nil, fmt.Errorf("rate limit: burst %d of %s is less than its cost %d, calls would be always rejected", bucket.burst(), method, cost)
+		}
+		l.methods[method] = rate.NewLimiter(rate.Limit(bucket.Rate), bucket.burst())
+	}
+	var err error
+	if l.clients, err = lru.New[string, *rate.Limiter](maxRateLimitedClients); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// lookup - value of method, or of its namespace wildcard
+func lookup[V any](m map[string]V, method string) (v V, ok bool) {
+	if v, ok = m[method]; ok {
+		return v, true
+	}
+	if i := strings.IndexByte(method, '_'); i >= 0 {
+		v, ok = m[method[:i+1]+"*"]
+	}
+	return v, ok
+}
+
+func (l *RateLimiter) cost(method string) int {
+	if cost, ok := lookup(l.cfg.Costs, method); ok {
+		return cost
+	}
+	return 1
+}
+
+func (l *RateLimiter) clientBucket(client string) *rate.Limiter {
+	if bucket, ok := l.clients.Get(client); ok {
+		return bucket
+	}
+	bucket := rate.NewLimiter(rate.Limit(l.cfg.Client.Rate), l.cfg.Client.burst())
+	if prev, ok, _ := l.clients.PeekOrAdd(client, bucket); ok {
+		return prev
+	}
+	return bucket
+}
+
+// allow - takes tokens of the call from the buckets of method and client. Returns error to reply with if
+// any of them has not enough tokens, then nothing is taken.
+func (l *RateLimiter) allow(method, client string) error {
+	now := time.Now()
+	cost := l.cost(method)
+
+	var methodReservation *rate.Reservation
+	if bucket, ok := lookup(l.methods, method); ok {
+		methodReservation = bucket.ReserveN(now, cost)
+		if methodReservation.DelayFrom(now) > 0 {
+			methodReservation.CancelAt(now)
+			rateLimitedCounter(method, "method").Inc()
+			return &rateLimitedError{method: method}
+		}
+	}
+	if client != "" && l.cfg.Client.Rate > 0 {
+		clientReservation := l.clientBucket(client).ReserveN(now, cost)
+		if clientReservation.DelayFrom(now) > 0 {
+			clientReservation.CancelAt(now)
+			if methodReservation != nil {
+				methodReservation.CancelAt(now)
+			}
+			rateLimitedCounter(method, "client").Inc()
+			return &rateLimitedError{method: method, client: true}
+		}
+	}
+	return nil
+}
+
+// rateLimitClientID - JWT subject of authenticated connection, otherwise remote IP
+func rateLimitClientID(conn jsonWriter) string {
+	if c, ok := conn.(interface{ jwtSubject() string }); ok && c.jwtSubject() != "" {
+		return "jwt:" + c.jwtSubject()
+	}
+	remote := conn.remoteAddr()
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		return host
+	}
+	return remote
+}
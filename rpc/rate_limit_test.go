@@ -0,0 +1,161 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+)
+
+func newRateLimitedTestServer(t *testing.T, cfg RateLimitConfig) *Server {
+	t.Helper()
+	limiter, err := NewRateLimiter(cfg)
+	require.NoError(t, err)
+	srv := newTestServer(log.New())
+	srv.SetRateLimiter(limiter)
+	t.Cleanup(srv.Stop)
+	return srv
+}
+
+func requireRateLimited(t *testing.T, err error) {
+	t.Helper()
+	var rpcErr Error
+	require.True(t, errors.As(err, &rpcErr), "unexpected error %v", err)
+	require.Equal(t, -32005, rpcErr.ErrorCode())
+}
+
+func TestNewRateLimiterValidation(t *testing.T) {
+	_, err := NewRateLimiter(RateLimitConfig{Costs: map[string]int{"test_echo": 0}})
+	require.Error(t, err)
+	_, err = NewRateLimiter(RateLimitConfig{Client: RateLimitBucket{Rate: 1, Burst: 2}, Costs: map[string]int{"test_echo": 3}})
+	require.Error(t, err)
+	_, err = NewRateLimiter(RateLimitConfig{Methods: map[string]RateLimitBucket{"test_*": {Rate: 1}}, Costs: map[string]int{"test_echo": 2}})
+	require.NoError(t, err, "burst of test_* is enough for cost of other test_ methods")
+	_, err = NewRateLimiter(RateLimitConfig{Methods: map[string]RateLimitBucket{"test_echo": {Rate: 1}}, Costs: map[string]int{"test_*": 2}})
+	require.Error(t, err)
+}
+
+func TestRateLimitMethodInBatch(t *testing.T) {
+	srv := newRateLimitedTestServer(t, RateLimitConfig{
+		Methods: map[string]RateLimitBucket{"test_echo": {Rate: 0.001, Burst: 2}},
+	})
+	client := DialInProc(srv, log.New())
+	defer client.Close()
+
+	batch := make([]BatchElem, 4)
+	for i := range batch[:3] {
+		batch[i] = BatchElem{Method: "test_echo", Args: []interface{}{"hello", i, &echoArgs{"world"}}, Result: new(echoResult)}
+	}
+	batch[3] = BatchElem{Method: "test_rets", Result: new(string)}
+	require.NoError(t, client.BatchCall(batch))
+	require.NoError(t, batch[0].Error)
+	require.NoError(t, batch[1].Error)
+	requireRateLimited(t, batch[2].Error)
+	require.NoError(t, batch[3].Error, "other methods are not limited")
+
+	// the bucket is shared by batched and single calls
+	requireRateLimited(t, client.Call(new(echoResult), "test_echo", "hello", 1, &echoArgs{"world"}))
+}
+
+func TestRateLimitClientCost(t *testing.T) {
+	srv := newRateLimitedTestServer(t, RateLimitConfig{
+		Client: RateLimitBucket{Rate: 0.001, Burst: 3},
+		Costs:  map[string]int{"test_*": 2, "test_rets": 1},
+	})
+	httpsrv := httptest.NewServer(srv)
+	defer httpsrv.Close()
+
+	client, err := DialHTTP(httpsrv.URL, log.New())
+	require.NoError(t, err)
+	defer client.Close()
+
+	var res echoResult
+	require.NoError(t, client.Call(&res, "test_echo", "hello", 1, &echoArgs{"world"}))
+	requireRateLimited(t, client.Call(&res, "test_echo", "hello", 1, &echoArgs{"world"}))
+	var s string
+	require.NoError(t, client.Call(&s, "test_rets"))
+	requireRateLimited(t, client.Call(&s, "test_rets"))
+
+	// in-process calls have no client bucket
+	inproc := DialInProc(srv, log.New())
+	defer inproc.Close()
+	require.NoError(t, inproc.Call(&res, "test_echo", "hello", 1, &echoArgs{"world"}))
+}
+
+func TestRateLimitClientByJwtSubject(t *testing.T) {
+	srv := newRateLimitedTestServer(t, RateLimitConfig{Client: RateLimitBucket{Rate: 0.001, Burst: 1}})
+	secret := []byte("secret")
+	httpsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ok bool
+		if r, ok = CheckJwtSecretAndSubject(w, r, secret); ok {
+			srv.ServeHTTP(w, r)
+		}
+	}))
+	defer httpsrv.Close()
+
+	dial := func(subject string) *Client {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			Subject:  subject,
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+		}).SignedString(secret)
+		require.NoError(t, err)
+		client, err := DialHTTP(httpsrv.URL, log.New())
+		require.NoError(t, err)
+		client.SetHeader("Authorization", "Bearer "+token)
+		t.Cleanup(client.Close)
+		return client
+	}
+	alice, bob := dial("alice"), dial("bob")
+
+	var s string
+	require.NoError(t, alice.Call(&s, "test_rets"))
+	requireRateLimited(t, alice.Call(&s, "test_rets"))
+	require.NoError(t, bob.Call(&s, "test_rets"), "same IP, but other subject")
+}
+
+func TestRateLimitWebsocketSubscription(t *testing.T) {
+	srv := newRateLimitedTestServer(t, RateLimitConfig{
+		Methods: map[string]RateLimitBucket{"nftest_subscribe": {Rate: 0.001, Burst: 1}},
+	})
+	httpsrv := httptest.NewServer(srv.WebsocketHandler([]string{"*"}, nil, false, log.New()))
+	defer httpsrv.Close()
+
+	client, err := DialWebsocket(context.Background(), "ws:"+strings.TrimPrefix(httpsrv.URL, "http:"), "", log.New())
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	sub, err := client.Subscribe(ctx, "nftest", make(chan int), "someSubscription", 1, 1)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	_, err = client.Subscribe(ctx, "nftest", make(chan int), "someSubscription", 1, 1)
+	requireRateLimited(t, err)
+	var res int
+	require.NoError(t, client.Call(&res, "nftest_echo", 1), "calls are not limited")
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// ResultCache - cache of results which don't change anymore (for example, of finalized blocks). Handler asks it
+// before running a method and offers it results of successful calls. Params are canonical: parsed arguments of
+// the method encoded back to JSON, so that equivalent requests ("0x0a" vs "0xa", omitted vs null optional
+// argument, etc.) share an entry.
+type ResultCache interface {
+	// Cacheable - whether results of the method may be cached at all. Results of streaming methods are
+	// buffered in memory instead of streamed, so it must be false for methods never cached.
+	Cacheable(method string) bool
+	Get(method string, params []byte) (json.RawMessage, bool)
+	// Put - offers result of successful call, the cache decides whether it's immutable and worth keeping
+	Put(ctx context.Context, method string, params []byte, result json.RawMessage)
+}
+
+// cacheableParams - canonical params of the call if its result may be cached: parsed arguments encoded back to JSON
+func (h *handler) cacheableParams(method string, callb *callback, args []reflect.Value) ([]byte, bool) {
+	if h.resultCache == nil || callb == h.unsubscribeCb || !h.resultCache.Cacheable(method) {
+		return nil, false
+	}
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = arg.Interface()
+	}
+	params, err := json.Marshal(values)
+	if err != nil {
+		return nil, false
+	}
+	return params, true
+}
+
+// runCacheableMethod - same as runMethod, but serves the result from ResultCache if it's there and offers
+// the result to ResultCache otherwise. Results of streaming methods are buffered, so they can be cached. Results
+// with errors written inline (see HandleError) or cut short by cancellation/timeout of the call are not offered.
+func (h *handler) runCacheableMethod(ctx context.Context, msg *jsonrpcMessage, callb *callback, args []reflect.Value, params []byte) *jsonrpcMessage {
+	if result, ok := h.resultCache.Get(msg.Method, params); ok {
+		resultCacheHits.Inc()
+		return &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: result}
+	}
+	resultCacheMisses.Inc()
+
+	var result json.RawMessage
+	if callb.streamable {
+		stream := jsoniter.NewStream(jsoniter.ConfigDefault, nil, 4096)
+		if _, err := callb.call(ctx, msg.Method, args, stream); err != nil {
+			return msg.errorResponse(err)
+		}
+		result = stream.Buffer()
+		if stream.Attachment != nil {
+			return &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: result}
+		}
+	} else {
+		res, err := callb.call(ctx, msg.Method, args, nil)
+		if err != nil {
+			return msg.errorResponse(err)
+		}
+		if result, err = json.Marshal(res); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
+	if ctx.Err() == nil {
+		h.resultCache.Put(ctx, msg.Method, params, result)
+	}
+	return &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: result}
+}
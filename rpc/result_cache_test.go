@@ -0,0 +1,147 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+)
+
+type mapResultCache struct {
+	lock    sync.Mutex
+	results map[string]json.RawMessage
+}
+
+func (c *mapResultCache) Cacheable(method string) bool { return method != "cached_uncached" }
+
+func (c *mapResultCache) Get(method string, params []byte) (json.RawMessage, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	res, ok := c.results[method+string(params)]
+	return res, ok
+}
+
+func (c *mapResultCache) Put(_ context.Context, method string, params []byte, result json.RawMessage) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.results[method+string(params)] = result
+}
+
+type cachedTestService struct{ calls atomic.Int32 }
+
+func (s *cachedTestService) Block(num BlockNumber, fullTx *bool) (map[string]interface{}, error) {
+	s.calls.Add(1)
+	if num < 0 {
+		return nil, errors.New("tags are not supported")
+	}
+	return map[string]interface{}{"number": num, "fullTx": fullTx != nil && *fullTx}, nil
+}
+
+func (s *cachedTestService) Trace(num BlockNumber, stream *jsoniter.Stream) error {
+	s.calls.Add(1)
+	stream.WriteObjectStart()
+	stream.WriteObjectField("number")
+	stream.WriteInt64(num.Int64())
+	stream.WriteObjectEnd()
+	return nil
+}
+
+// TraceSlow - traces the way debug_traceBlockByNumber does: error of a tracer (here - its timeout) is written
+// inline in place of the result and the call succeeds
+func (s *cachedTestService) TraceSlow(ctx context.Context, num BlockNumber, stream *jsoniter.Stream) error {
+	s.calls.Add(1)
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	stream.WriteArrayStart()
+	stream.WriteObjectStart()
+	stream.WriteObjectField("number")
+	stream.WriteInt64(num.Int64())
+	<-ctx.Done()
+	stream.WriteMore()
+	HandleError(errors.New("execution timeout"), stream)
+	stream.WriteObjectEnd()
+	stream.WriteArrayEnd()
+	return nil
+}
+
+func (s *cachedTestService) Uncached(num BlockNumber) BlockNumber {
+	s.calls.Add(1)
+	return num
+}
+
+func TestResultCache(t *testing.T) {
+	logger := log.New()
+	srv := newTestServer(logger)
+	defer srv.Stop()
+	service := new(cachedTestService)
+	require.NoError(t, srv.RegisterName("cached", service))
+	cache := &mapResultCache{results: map[string]json.RawMessage{}}
+	srv.SetResultCache(cache)
+	client := DialInProc(srv, logger)
+	defer client.Close()
+
+	var block map[string]interface{}
+	require.NoError(t, client.Call(&block, "cached_block", "0xa"))
+	require.Equal(t, map[string]interface{}{"number": "0xa", "fullTx": false}, block)
+	// same canonical params
+	require.NoError(t, client.Call(&block, "cached_block", "0xa", nil))
+	require.NoError(t, client.Call(&block, "cached_block", 10))
+	require.Equal(t, int32(1), service.calls.Load())
+	require.Equal(t, map[string]interface{}{"number": "0xa", "fullTx": false}, block)
+
+	require.NoError(t, client.Call(&block, "cached_block", "0xa", true))
+	require.Equal(t, int32(2), service.calls.Load())
+
+	// errors are not cached
+	require.Error(t, client.Call(&block, "cached_block", "latest"))
+	require.Error(t, client.Call(&block, "cached_block", "latest"))
+	require.Equal(t, int32(4), service.calls.Load())
+
+	// results of streaming methods are buffered
+	var trace map[string]int
+	for i := 0; i < 2; i++ {
+		require.NoError(t, client.Call(&trace, "cached_trace", "0xa"))
+		require.Equal(t, map[string]int{"number": 10}, trace)
+	}
+	require.Equal(t, int32(5), service.calls.Load())
+	require.Equal(t, json.RawMessage(`{"number":10}`), cache.results[`cached_trace["0xa"]`])
+
+	var num BlockNumber
+	require.NoError(t, client.Call(&num, "cached_uncached", "0xa"))
+	require.NoError(t, client.Call(&num, "cached_uncached", "0xa"))
+	require.Equal(t, int32(7), service.calls.Load())
+
+	// streamed results with inline errors are not cached
+	var traces []map[string]interface{}
+	for i := 0; i < 2; i++ {
+		require.NoError(t, client.Call(&traces, "cached_traceSlow", "0xa"))
+		require.Len(t, traces, 1)
+		require.Equal(t, map[string]interface{}{"code": float64(defaultErrorCode), "message": "execution timeout"}, traces[0]["error"])
+	}
+	require.Equal(t, int32(9), service.calls.Load())
+	require.NotContains(t, cache.results, `cached_traceSlow["0xa"]`)
+}
@@ -60,6 +60,8 @@ type Server struct {
 	traceRequests       bool // Whether to print requests at INFO level
 	debugSingleRequest  bool // Whether to print requests at INFO level
 	batchLimit          int  // Maximum number of requests in a batch
+	rateLimiter         *RateLimiter
+	resultCache         ResultCache
 	logger              log.Logger
 	rpcSlowLogThreshold time.Duration
 }
@@ -85,6 +87,16 @@ func (s *Server) SetBatchLimit(limit int) {
 	s.batchLimit = limit
 }
 
+// SetRateLimiter sets limiter of calls and subscriptions handled by this server, nil disables limiting
+func (s *Server) SetRateLimiter(rateLimiter *RateLimiter) {
+	s.rateLimiter = rateLimiter
+}
+
+// SetResultCache sets cache of immutable results of calls handled by this server, nil disables caching
+func (s *Server) SetResultCache(resultCache ResultCache) {
+	s.resultCache = resultCache
+}
+
 // RegisterName creates a service for the given receiver type under the given name. When no
 // methods on the given receiver match the criteria to be either a RPC method or a
 // subscription an error is returned. Otherwise a new service is created and added to the
@@ -110,7 +122,7 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 	s.codecs.Add(codec)
 	defer s.codecs.Remove(codec)
 
-	c := initClient(codec, s.idgen, &s.services, s.logger)
+	c := initClient(codec, s.idgen, &s.services, s.rateLimiter, s.resultCache, s.logger)
 	<-codec.closed()
 	c.Close()
 }
@@ -126,6 +138,8 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec, stre
 
 	h := newHandler(ctx, codec, s.idgen, &s.services, s.methodAllowList, s.batchConcurrency, s.traceRequests, s.logger, s.rpcSlowLogThreshold)
 	h.allowSubscribe = false
+	h.rateLimiter = s.rateLimiter
+	h.resultCache = s.resultCache
 	defer h.close(io.EOF, nil)
 
 	reqs, batch, err := codec.ReadBatch()
@@ -56,26 +56,31 @@ func (s *Server) WebsocketHandler(allowedOrigins []string, jwtSecret []byte, com
 		ReadBufferSize:    wsReadBuffer,
 		WriteBufferSize:   wsWriteBuffer,
 		WriteBufferPool:   wsBufferPool,
-		CheckOrigin:       wsHandshakeValidator(allowedOrigins, logger),
+		CheckOrigin:       WsHandshakeValidator(allowedOrigins, logger),
 	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if jwtSecret != nil && !CheckJwtSecret(w, r, jwtSecret) {
-			return
+		if jwtSecret != nil {
+			var ok bool
+			if r, ok = CheckJwtSecretAndSubject(w, r, jwtSecret); !ok {
+				return
+			}
 		}
 		conn, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
 			logger.Warn("WebSocket upgrade failed", "err", err)
 			return
 		}
-		codec := NewWebsocketCodec(conn)
+		codec := NewWebsocketCodec(conn).(*websocketCodec)
+		codec.remote = r.RemoteAddr
+		codec.subject = jwtSubjectFromContext(r.Context())
 		s.ServeCodec(codec, 0)
 	})
 }
 
-// wsHandshakeValidator returns a handler that verifies the origin during the
+// WsHandshakeValidator returns a handler that verifies the origin during the
 // websocket upgrade process. When a '*' is specified as an allowed origins all
 // connections are accepted.
-func wsHandshakeValidator(allowedOrigins []string, logger log.Logger) func(*http.Request) bool {
+func WsHandshakeValidator(allowedOrigins []string, logger log.Logger) func(*http.Request) bool {
 	origins := mapset.NewSet[string]()
 	allowAllOrigins := false
 
@@ -54,6 +54,7 @@ type CallArgs struct {
 	Input                *hexutility.Bytes  `json:"input"`
 	AccessList           *types2.AccessList `json:"accessList"`
 	ChainID              *hexutil.Big       `json:"chainId,omitempty"`
+	BlobVersionedHashes  []libcommon.Hash   `json:"blobVersionedHashes"`
 }
 
 // from retrieves the transaction sender address.
@@ -161,7 +162,13 @@ func (args *CallArgs) ToMessage(globalGasCap uint64, baseFee *uint256.Int) (type
 		accessList = *args.AccessList
 	}
 
-	msg := types.NewMessage(addr, args.To, 0, value, gas, gasPrice, gasFeeCap, gasTipCap, data, accessList, false /* checkNonce */, false /* isFree */, maxFeePerBlobGas)
+	var nonce uint64
+	if args.Nonce != nil {
+		nonce = uint64(*args.Nonce)
+	}
+
+	msg := types.NewMessage(addr, args.To, nonce, value, gas, gasPrice, gasFeeCap, gasTipCap, data, accessList, false /* checkNonce */, false /* isFree */, maxFeePerBlobGas)
+	msg.SetBlobHashes(args.BlobVersionedHashes)
 	return msg, nil
 }
 
@@ -177,6 +184,10 @@ type Account struct {
 	Balance   **hexutil.Big                   `json:"balance"`
 	State     *map[libcommon.Hash]uint256.Int `json:"state"`
 	StateDiff *map[libcommon.Hash]uint256.Int `json:"stateDiff"`
+
+	// MovePrecompileToAddress relocates the precompiled contract living at this
+	// account's address. Only honoured by eth_simulateV1.
+	MovePrecompileToAddress *libcommon.Address `json:"movePrecompileToAddress"`
 }
 
 func NewRevertError(result *evmtypes.ExecutionResult) *RevertError {
@@ -21,10 +21,12 @@ import (
 	"math/big"
 
 	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/chain"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 
 	"github.com/ledgerwatch/erigon/core/state"
 	"github.com/ledgerwatch/erigon/core/tracing"
+	"github.com/ledgerwatch/erigon/core/vm"
 )
 
 type StateOverrides map[libcommon.Address]Account
@@ -66,3 +68,40 @@ func (overrides *StateOverrides) Override(state *state.IntraBlockState) error {
 
 	return nil
 }
+
+// OverridePrecompiles returns the set of precompiled contracts active under rules
+// with the requested movePrecompileToAddress relocations applied. It returns nil
+// when no precompile is moved, meaning the default set should be used.
+func (overrides *StateOverrides) OverridePrecompiles(rules *chain.Rules) (map[libcommon.Address]vm.PrecompiledContract, error) {
+	active := vm.ActivePrecompiledContracts(rules)
+	var precompiles map[libcommon.Address]vm.PrecompiledContract
+	for addr, account := range *overrides {
+		if account.MovePrecompileToAddress == nil {
+			continue
+		}
+		if _, ok := active[addr]; !ok {
+			return nil, fmt.Errorf("account %s is not a precompile", addr.Hex())
+		}
+		if precompiles == nil {
+			precompiles = make(map[libcommon.Address]vm.PrecompiledContract, len(active))
+			for a, p := range active {
+				precompiles[a] = p
+			}
+		}
+		delete(precompiles, addr)
+	}
+	if precompiles == nil {
+		return nil, nil
+	}
+	for addr, account := range *overrides {
+		if account.MovePrecompileToAddress == nil {
+			continue
+		}
+		dst := *account.MovePrecompileToAddress
+		if _, ok := precompiles[dst]; ok {
+			return nil, fmt.Errorf("account %s is already overridden by a precompile", dst.Hex())
+		}
+		precompiles[dst] = active[addr]
+	}
+	return precompiles, nil
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common/datadir"
 	"github.com/ledgerwatch/erigon-lib/common/dir"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
 	"github.com/ledgerwatch/erigon/cmd/utils"
 	"github.com/ledgerwatch/erigon/cmd/utils/flags"
 	"github.com/ledgerwatch/erigon/turbo/debug"
@@ -35,7 +36,7 @@ import (
 )
 
 // nolint
-var backupCommand = cli.Command{
+var alphaBackupCommand = cli.Command{
 	Name: "alpha_backup",
 	Description: `Alpha verison of command. Backup all databases without stopping of Erigon.
 While this command has Alpha prefix - we recommend to stop Erigon for backup. 
@@ -64,7 +65,51 @@ TODO:
 	}),
 }
 
+var backupCommand = cli.Command{
+	Name:  "backup",
+	Usage: "Incremental backups of chaindata",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "create",
+			Action: doBackupCreate,
+			Usage:  "Backup chaindata into new sub-directory of --backup.dir, copying only tables modified since the previous backup there. Erigon may keep running",
+			Description: `Backup is taken in one read transaction, so it's consistent, but it prevents db from re-using pages
+freed during the backup - db may grow if Erigon is running. Immutable files of datadir/snapshots are not copied,
+manifest of backup only lists them: backup them separately (they never change, so rsync is enough) or let
+Erigon download them after restore.`,
+			Flags: joinFlags([]cli.Flag{
+				&utils.DataDirFlag,
+				&BackupDirFlag,
+				&BackupFullFlag,
+			}),
+		},
+		{
+			Name:   "restore",
+			Action: doBackupRestore,
+			Usage:  "Rebuild chaindata of --datadir from chain of incremental backups of --backup.dir",
+			Flags: joinFlags([]cli.Flag{
+				&utils.DataDirFlag,
+				&BackupDirFlag,
+				&BackupNameFlag,
+			}),
+		},
+	},
+}
+
 var (
+	BackupDirFlag = flags.DirectoryFlag{
+		Name:     "backup.dir",
+		Usage:    "Directory of chain of incremental backups",
+		Required: true,
+	}
+	BackupFullFlag = cli.BoolFlag{
+		Name:  "full",
+		Usage: "Copy all tables and start new chain of incremental backups",
+	}
+	BackupNameFlag = cli.StringFlag{
+		Name:  "backup.name",
+		Usage: "Name of backup to restore (sub-directory of --backup.dir). The latest if not set",
+	}
 	ToDatadirFlag = flags.DirectoryFlag{
 		Name:     "to.datadir",
 		Usage:    "Target datadir",
@@ -167,3 +212,70 @@ func doBackup(cliCtx *cli.Context) error {
 
 	return nil
 }
+
+func doBackupCreate(cliCtx *cli.Context) error {
+	logger, _, _, err := debug.Setup(cliCtx, true /* rootLogger */)
+	if err != nil {
+		return err
+	}
+
+	// no datadir lock: backup is taken while Erigon is running
+	dirs := datadir.New(cliCtx.String(utils.DataDirFlag.Name))
+	db := backup.OpenSource(dirs.Chaindata, kv.ChainDB, logger)
+	defer db.Close()
+
+	_, err = backup.Incremental(cliCtx.Context, db, cliCtx.String(BackupDirFlag.Name), backup.IncrementalOpts{
+		Label:   kv.ChainDB,
+		SnapDir: dirs.Snap,
+		Full:    cliCtx.Bool(BackupFullFlag.Name),
+	}, logger)
+	return err
+}
+
+func doBackupRestore(cliCtx *cli.Context) error {
+	logger, _, _, err := debug.Setup(cliCtx, true /* rootLogger */)
+	if err != nil {
+		return err
+	}
+
+	dirs, l, err := datadir.New(cliCtx.String(utils.DataDirFlag.Name)).MustFlock()
+	if err != nil {
+		return err
+	}
+	defer l.Unlock()
+
+	if exists, err := dir.Exist(filepath.Join(dirs.Chaindata, "mdbx.dat")); err != nil {
+		return err
+	} else if exists {
+		return fmt.Errorf("chaindata already exists: %s", dirs.Chaindata)
+	}
+
+	backupDir := cliCtx.String(BackupDirFlag.Name)
+	var m *backup.Manifest
+	if name := cliCtx.String(BackupNameFlag.Name); name != "" {
+		m, err = backup.ReadManifest(filepath.Join(backupDir, name))
+	} else {
+		m, err = backup.LatestManifest(backupDir)
+	}
+	if err != nil {
+		return err
+	}
+	if m.Label != string(kv.ChainDB) {
+		return fmt.Errorf("backup %s is of %s, not of %s", m.Name, m.Label, kv.ChainDB)
+	}
+
+	db := mdbx.NewMDBX(logger).Path(dirs.Chaindata).Label(kv.ChainDB).PageSize(m.PageSize).WriteMap().
+		WithTableCfg(func(_ kv.TableCfg) kv.TableCfg { return kv.ChaindataTablesCfg }).
+		MustOpen()
+	defer db.Close()
+	if _, err := backup.Restore(cliCtx.Context, backupDir, m.Name, db, logger); err != nil {
+		return fmt.Errorf("%w, remove partially restored %s before retrying", err, dirs.Chaindata)
+	}
+
+	if missing := backup.MissingSnapshotFiles(m, dirs.Snap); len(missing) > 0 {
+		logger.Warn("[backup] snapshot files are missing, Erigon will download them on start, or copy them from source datadir",
+			"amount", len(missing), "dir", dirs.Snap, "first", missing[0])
+	}
+	logger.Info("[backup] restored", "backup", m.Name, "taken at", m.CreatedAt)
+	return nil
+}
@@ -0,0 +1,296 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/temporal"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+
+	"github.com/ledgerwatch/erigon/cmd/hack/tool/fromdb"
+	"github.com/ledgerwatch/erigon/cmd/utils"
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/eth/ethconfig/estimate"
+	"github.com/ledgerwatch/erigon/eth/ethconsensusconfig"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/turbo/debug"
+	"github.com/ledgerwatch/erigon/turbo/services"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/freezeblocks"
+	"github.com/ledgerwatch/erigon/turbo/transactions"
+)
+
+var (
+	EraDirFlag = cli.StringFlag{
+		Name:     "era.dir",
+		Usage:    "Directory to write era1 files into",
+		Required: true,
+	}
+	EraFromFlag = cli.Uint64Flag{
+		Name:  "era.from",
+		Usage: "First epoch (of 8192 blocks) to export",
+	}
+	EraToFlag = cli.Uint64Flag{
+		Name:  "era.to",
+		Usage: "Epoch to stop export before (0 - until the merge)",
+	}
+	EraTrustFileRootsFlag = cli.BoolFlag{
+		Name:  "era.trust-file-roots",
+		Usage: "Import files of a network without a list of known accumulator roots, verifying them only against their own accumulator",
+	}
+)
+
+var exportEraCommand = cli.Command{
+	Name:  "export-era",
+	Usage: "Export pre-merge history into era1 files",
+	Action: func(c *cli.Context) error {
+		dirs, l, err := datadir.New(c.String(utils.DataDirFlag.Name)).MustFlock()
+		if err != nil {
+			return err
+		}
+		defer l.Unlock()
+
+		return doExportEra(c, dirs)
+	},
+	Flags: joinFlags([]cli.Flag{
+		&utils.DataDirFlag,
+		&EraDirFlag,
+		&EraFromFlag,
+		&EraToFlag,
+	}),
+	Description: `
+Writes blocks, receipts and total difficulty of every pre-merge epoch into <network>-<epoch>-<root>.era1
+files, which other execution clients can read. Receipts not stored in the db are re-executed, it requires
+history of state for exported blocks. Blocks before Byzantium with transactions can't be exported: their
+receipts commit to intermediate state roots, which are not kept.`,
+}
+
+var importEraCommand = cli.Command{
+	Name:      "import-era",
+	Usage:     "Import pre-merge history from era1 files into block snapshots",
+	ArgsUsage: "<file or dir> (<file or dir 2> ... <file or dir N>)",
+	Action: func(c *cli.Context) error {
+		dirs, l, err := datadir.New(c.String(utils.DataDirFlag.Name)).MustFlock()
+		if err != nil {
+			return err
+		}
+		defer l.Unlock()
+
+		return doImportEra(c, dirs)
+	},
+	Flags: joinFlags([]cli.Flag{
+		&utils.DataDirFlag,
+		&utils.ChainFlag,
+		&EraTrustFileRootsFlag,
+	}),
+	Description: `
+Builds headers, bodies and transactions snapshots from era1 files, continuing the snapshots of datadir.
+Accumulator root of every file is verified against its blocks, its name and the list of known roots of
+the network, and transactions, uncles and receipts of every block against its header. Files of a network
+without the list are refused, unless --era.trust-file-roots is given. Receipts are used only for verification. Blocks not filling
+a whole snapshot segment are left for the regular sync. Datadir must not have synced blocks after its snapshots.`,
+}
+
+func doImportEra(cliCtx *cli.Context, dirs datadir.Dirs) error {
+	if cliCtx.NArg() < 1 {
+		return fmt.Errorf("this command requires era1 files or directories as arguments")
+	}
+	logger, _, _, err := debug.Setup(cliCtx, true /* rootLogger */)
+	if err != nil {
+		return err
+	}
+	defer logger.Info("Done")
+	ctx := cliCtx.Context
+
+	var files []string
+	for _, arg := range cliCtx.Args().Slice() {
+		st, err := os.Stat(arg)
+		if err != nil {
+			return err
+		}
+		if !st.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		inDir, err := filepath.Glob(filepath.Join(arg, "*.era1"))
+		if err != nil {
+			return err
+		}
+		files = append(files, inDir...)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no .era1 files in %s", strings.Join(cliCtx.Args().Slice(), ", "))
+	}
+
+	db := dbCfg(kv.ChainDB, dirs.Chaindata).MustOpen()
+	defer db.Close()
+	if fromdb.ChainConfig(db) == nil {
+		genesis := core.GenesisBlockByChainName(cliCtx.String(utils.ChainFlag.Name))
+		if genesis == nil {
+			return fmt.Errorf("unknown chain %s", cliCtx.String(utils.ChainFlag.Name))
+		}
+		if _, _, err := core.CommitGenesisBlock(db, genesis, dirs.Tmp, logger); err != nil {
+			return err
+		}
+	}
+	chainConfig := fromdb.ChainConfig(db)
+
+	cfg := ethconfig.NewSnapCfg(true, false, true, true)
+	blockSnaps, _, _, br, agg, clean, err := openSnaps(ctx, cfg, dirs, db, logger)
+	if err != nil {
+		return err
+	}
+	defer clean()
+	blockReader, _ := br.IO()
+
+	var parent *types.Header
+	var parentTD *big.Int
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		progress, err := stages.GetStageProgress(tx, stages.Headers)
+		if err != nil {
+			return err
+		}
+		if progress > blockReader.FrozenBlocks() {
+			return fmt.Errorf("datadir has synced blocks up to %d after snapshots, which end at %d", progress, blockReader.FrozenBlocks())
+		}
+		if len(blockSnaps.Files()) == 0 {
+			return nil
+		}
+		if parent, err = blockReader.HeaderByNumber(ctx, tx, blockReader.FrozenBlocks()); err != nil {
+			return err
+		}
+		if parent == nil {
+			return fmt.Errorf("last block %d of snapshots not found", blockReader.FrozenBlocks())
+		}
+		parentTD, err = rawdb.ReadTd(tx, parent.Hash(), parent.Number.Uint64())
+		return err
+	}); err != nil {
+		return err
+	}
+
+	blockTo, _, err := freezeblocks.ImportEra1(ctx, files, parent, parentTD, blockReader.FirstTxnNumNotInSnapshots(), chainConfig, cliCtx.Bool(EraTrustFileRootsFlag.Name), dirs.Snap, dirs.Tmp, estimate.CompressSnapshot.Workers(), log.LvlInfo, logger)
+	if err != nil {
+		return err
+	}
+
+	if err := blockSnaps.ReopenFolder(); err != nil {
+		return err
+	}
+	if err := db.Update(ctx, func(tx kv.RwTx) error {
+		ac := agg.BeginFilesRo()
+		defer ac.Close()
+		return rawdb.WriteSnapshots(tx, blockReader.FrozenFiles(), ac.Files())
+	}); err != nil {
+		return err
+	}
+	logger.Info("Imported era1 files", "blocks in snapshots", blockTo)
+	return nil
+}
+
+func doExportEra(cliCtx *cli.Context, dirs datadir.Dirs) error {
+	logger, _, _, err := debug.Setup(cliCtx, true /* rootLogger */)
+	if err != nil {
+		return err
+	}
+	defer logger.Info("Done")
+	ctx := cliCtx.Context
+
+	db := dbCfg(kv.ChainDB, dirs.Chaindata).MustOpen()
+	defer db.Close()
+	chainConfig := fromdb.ChainConfig(db)
+	if chainConfig == nil {
+		return fmt.Errorf("datadir %s has no chain config", dirs.DataDir)
+	}
+
+	cfg := ethconfig.NewSnapCfg(true, false, true, true)
+	_, _, _, br, agg, clean, err := openSnaps(ctx, cfg, dirs, db, logger)
+	if err != nil {
+		return err
+	}
+	defer clean()
+	blockReader, _ := br.IO()
+
+	tdb, err := temporal.New(db, agg)
+	if err != nil {
+		return err
+	}
+	engine := ethconsensusconfig.CreateConsensusEngineBareBones(ctx, chainConfig, logger)
+
+	toEpoch := cliCtx.Uint64(EraToFlag.Name)
+	if toEpoch == 0 {
+		toEpoch = math.MaxUint64
+	}
+	files, err := freezeblocks.ExportEra1(ctx, tdb, blockReader, eraReceipts(chainConfig, engine, blockReader), chainConfig, cliCtx.String(EraDirFlag.Name),
+		cliCtx.Uint64(EraFromFlag.Name), toEpoch, log.LvlInfo, logger)
+	if err != nil {
+		return err
+	}
+	logger.Info("Exported era1 files", "amount", len(files))
+	return nil
+}
+
+// eraReceipts - receipts from db, or re-executed on historical state if not stored
+func eraReceipts(chainConfig *chain.Config, engine consensus.Engine, blockReader services.FullBlockReader) freezeblocks.Era1ReceiptsGetter {
+	return func(ctx context.Context, tx kv.Tx, block *types.Block, senders []common.Address) (types.Receipts, error) {
+		if receipts := rawdb.ReadReceipts(tx, block, senders); receipts != nil {
+			return receipts, nil
+		}
+		if len(block.Transactions()) == 0 {
+			return types.Receipts{}, nil
+		}
+
+		_, _, _, ibs, _, err := transactions.ComputeTxEnv(ctx, engine, block, chainConfig, blockReader, tx, 0)
+		if err != nil {
+			return nil, err
+		}
+		usedGas, usedBlobGas := new(uint64), new(uint64)
+		gp := new(core.GasPool).AddGas(block.GasLimit()).AddBlobGas(chainConfig.GetMaxBlobGasPerBlock())
+		getHeader := func(hash common.Hash, number uint64) *types.Header {
+			h, _ := blockReader.Header(ctx, tx, hash, number)
+			return h
+		}
+		header := block.Header()
+		receipts := make(types.Receipts, len(block.Transactions()))
+		for i, txn := range block.Transactions() {
+			ibs.SetTxContext(txn.Hash(), block.Hash(), i)
+			receipt, _, err := core.ApplyTransaction(chainConfig, core.GetHashFn(header, getHeader), engine, nil, gp, ibs, state.NewNoopWriter(), header, txn, usedGas, usedBlobGas, vm.Config{})
+			if err != nil {
+				return nil, err
+			}
+			receipts[i] = receipt
+		}
+		return receipts, nil
+	}
+}
@@ -80,9 +80,12 @@ func MakeApp(name string, action cli.ActionFunc, cliFlags []cli.Flag) *cli.App {
 	app.Commands = []*cli.Command{
 		&initCommand,
 		&importCommand,
+		&importEraCommand,
+		&exportEraCommand,
 		&snapshotCommand,
 		&supportCommand,
-		//&backupCommand,
+		&backupCommand,
+		//&alphaBackupCommand,
 	}
 	return app
 }
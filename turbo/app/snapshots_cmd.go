@@ -184,12 +184,13 @@ var snapshotCommand = cli.Command{
 		{
 			Name:   "compress",
 			Action: doCompress,
-			Flags:  joinFlags([]cli.Flag{&utils.DataDirFlag}),
+			Flags:  joinFlags([]cli.Flag{&utils.DataDirFlag, &SnapshotCodecFlag}),
 		},
 		{
 			Name:   "decompress-speed",
 			Action: doDecompressSpeed,
-			Flags:  joinFlags([]cli.Flag{&utils.DataDirFlag}),
+			Usage:  "erigon snapshots decompress-speed a.seg [--codec=zstd] - with --codec also re-compresses file by given codec and measures it too",
+			Flags:  joinFlags([]cli.Flag{&utils.DataDirFlag, &SnapshotCodecFlag}),
 		},
 		{
 			Name:   "bt-search",
@@ -366,6 +367,10 @@ var (
 		Name:  "rebuild",
 		Usage: "Force rebuild",
 	}
+	SnapshotCodecFlag = cli.StringFlag{
+		Name:  "codec",
+		Usage: "Compression codec: patterns, zstd",
+	}
 )
 
 func doBtSearch(cliCtx *cli.Context) error {
@@ -589,21 +594,50 @@ func doDecompressSpeed(cliCtx *cli.Context) error {
 	}
 	f := args.First()
 
+	if err := decompressSpeed(f, logger); err != nil {
+		return err
+	}
+	if !cliCtx.IsSet(SnapshotCodecFlag.Name) {
+		return nil
+	}
+
+	// same words compressed by another codec - to compare with the original file
+	codec, err := seg.ParseCodec(cliCtx.String(SnapshotCodecFlag.Name))
+	if err != nil {
+		return err
+	}
+	dirs := datadir.New(cliCtx.String(utils.DataDirFlag.Name))
+	recompressed := filepath.Join(dirs.Tmp, filepath.Base(f)+"."+codec.String())
+	defer os.Remove(recompressed)
+	if err := recompress(cliCtx.Context, f, recompressed, codec, dirs.Tmp, logger); err != nil {
+		return err
+	}
+	return decompressSpeed(recompressed, logger)
+}
+
+func decompressSpeed(f string, logger log.Logger) error {
 	decompressor, err := seg.NewDecompressor(f)
 	if err != nil {
 		return err
 	}
 	defer decompressor.Close()
+	speed := func(n int, took time.Duration) string {
+		return datasize.ByteSize(float64(n)/took.Seconds()).HR() + "/s"
+	}
+	_, fName := filepath.Split(f)
+	logger.Info("file", "name", fName, "codec", decompressor.Codec(), "size", datasize.ByteSize(decompressor.Size()).HR(), "words", decompressor.Count())
 	func() {
 		defer decompressor.EnableReadAhead().DisableReadAhead()
 
 		t := time.Now()
 		g := decompressor.MakeGetter()
 		buf := make([]byte, 0, 16*etl.BufIOSize)
+		var total int
 		for g.HasNext() {
 			buf, _ = g.Next(buf[:0])
+			total += len(buf)
 		}
-		logger.Info("decompress speed", "took", time.Since(t))
+		logger.Info("decompress speed", "took", time.Since(t), "speed", speed(total, time.Since(t)), "uncompressed", datasize.ByteSize(total).HR())
 	}()
 	func() {
 		defer decompressor.EnableReadAhead().DisableReadAhead()
@@ -613,11 +647,39 @@ func doDecompressSpeed(cliCtx *cli.Context) error {
 		for g.HasNext() {
 			_, _ = g.Skip()
 		}
-		log.Info("decompress skip speed", "took", time.Since(t))
+		logger.Info("decompress skip speed", "took", time.Since(t))
 	}()
 	return nil
 }
 
+// recompress - all words of `from` are treated as compressed words (as in block snapshots)
+func recompress(ctx context.Context, from, to string, codec seg.Codec, tmpDir string, logger log.Logger) error {
+	decompressor, err := seg.NewDecompressor(from)
+	if err != nil {
+		return err
+	}
+	defer decompressor.Close()
+	defer decompressor.EnableReadAhead().DisableReadAhead()
+
+	c, err := seg.NewCompressor(ctx, "recompress", to, tmpDir, seg.MinPatternScore, estimate.CompressSnapshot.Workers(), log.LvlInfo, logger)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	c.SetCodec(codec)
+	c.DisableFsync()
+
+	g := decompressor.MakeGetter()
+	buf := make([]byte, 0, 16*etl.BufIOSize)
+	for g.HasNext() {
+		buf, _ = g.Next(buf[:0])
+		if err := c.AddWord(buf); err != nil {
+			return err
+		}
+	}
+	return c.Compress()
+}
+
 func doIndicesCommand(cliCtx *cli.Context, dirs datadir.Dirs) error {
 	logger, _, _, err := debug.Setup(cliCtx, true /* rootLogger */)
 	if err != nil {
@@ -816,11 +878,16 @@ func doCompress(cliCtx *cli.Context) error {
 	f := args.First()
 	dirs := datadir.New(cliCtx.String(utils.DataDirFlag.Name))
 	logger.Info("file", "datadir", dirs.DataDir, "f", f)
+	codec, err := seg.ParseCodec(cliCtx.String(SnapshotCodecFlag.Name))
+	if err != nil {
+		return err
+	}
 	c, err := seg.NewCompressor(ctx, "compress", f, dirs.Tmp, seg.MinPatternScore, estimate.CompressSnapshot.Workers(), log.LvlInfo, logger)
 	if err != nil {
 		return err
 	}
 	defer c.Close()
+	c.SetCodec(codec)
 	r := bufio.NewReaderSize(os.Stdin, int(128*datasize.MB))
 	buf := make([]byte, 0, int(1*datasize.MB))
 	var l uint64
@@ -43,6 +43,9 @@ var DefaultFlags = []cli.Flag{
 	&utils.TxPoolLifetimeFlag,
 	&utils.TxPoolTraceSendersFlag,
 	&utils.TxPoolCommitEveryFlag,
+	&utils.TxPoolPrivateTxLifetimeFlag,
+	&utils.TxPoolJournalFlag,
+	&utils.TxPoolRejournalFlag,
 	&PruneFlag,
 	&PruneBlocksFlag,
 	&PruneHistoryFlag,
@@ -54,6 +57,7 @@ var DefaultFlags = []cli.Flag{
 	&PruneReceiptBeforeFlag,
 	&PruneTxIndexBeforeFlag,
 	&PruneCallTracesBeforeFlag,
+	&PruneIncludeCommitmentHistoryFlag,
 	&BatchSizeFlag,
 	&BodyCacheLimitFlag,
 	&DatabaseVerbosityFlag,
@@ -91,6 +95,8 @@ var DefaultFlags = []cli.Flag{
 	&utils.RpcStreamingDisableFlag,
 	&utils.DBReadConcurrencyFlag,
 	&utils.RpcAccessListFlag,
+	&utils.RpcRateLimitFlag,
+	&utils.RpcResultCacheFlag,
 	&utils.RpcTraceCompatFlag,
 	&utils.RpcGasCapFlag,
 	&utils.RpcBatchLimit,
@@ -119,6 +125,8 @@ var DefaultFlags = []cli.Flag{
 	&utils.SnapKeepBlocksFlag,
 	&utils.SnapStopFlag,
 	&utils.SnapStateStopFlag,
+	&utils.SnapCodecFlag,
+	&utils.SnapHistoryWindowFlag,
 	&utils.DbPageSizeFlag,
 	&utils.DbSizeLimitFlag,
 	&utils.DbWriteMapFlag,
@@ -176,6 +184,8 @@ var DefaultFlags = []cli.Flag{
 	&HealthCheckFlag,
 	&utils.HeimdallURLFlag,
 	&utils.WebSeedsFlag,
+	&utils.WebSeedServeAddrFlag,
+	&utils.DownloaderScrubRateFlag,
 	&utils.WithoutHeimdallFlag,
 	&utils.BorBlockPeriodFlag,
 	&utils.BorBlockSizeFlag,
@@ -217,12 +227,20 @@ var DefaultFlags = []cli.Flag{
 	&utils.BeaconApiWriteTimeoutFlag,
 	&utils.BeaconApiProtocolFlag,
 	&utils.BeaconApiIdleTimeoutFlag,
+	&utils.BeaconApiKeymanagerTokenFileFlag,
 
 	&utils.CaplinBackfillingFlag,
 	&utils.CaplinBlobBackfillingFlag,
 	&utils.CaplinDisableBlobPruningFlag,
 	&utils.CaplinArchiveFlag,
 	&utils.CaplinMevRelayUrl,
+	&utils.CaplinValidatorKeystoresFlag,
+	&utils.CaplinValidatorPasswordsFlag,
+	&utils.CaplinValidatorRemoteSignerFlag,
+	&utils.CaplinValidatorFeeRecipientFlag,
+	&utils.CaplinValidatorGraffitiFlag,
+	&utils.CaplinSlashingProtectionImportFlag,
+	&utils.CaplinSlashingProtectionExportFlag,
 
 	&utils.TrustedSetupFile,
 	&utils.RPCSlowFlag,
@@ -231,4 +249,6 @@ var DefaultFlags = []cli.Flag{
 	&SyncLoopBlockLimitFlag,
 	&SyncLoopBreakAfterFlag,
 	&SyncLoopPruneLimitFlag,
+	&SyncSupplyFlag,
+	&SyncTraceResultsFlag,
 }
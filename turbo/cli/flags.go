@@ -137,6 +137,12 @@ var (
 		Name:  "prune.b.before",
 		Usage: `Prune data before this block`,
 	}
+	PruneIncludeCommitmentHistoryFlag = cli.BoolFlag{
+		Name: "prune.include-commitment-history",
+		Usage: `Keep history of the state commitment (trie branches), required by eth_getProof and debug_executionWitness for past blocks.
+	It's kept in DB for blocks executed by the node (not in state files), pruned with --prune.h.older.
+	Costs roughly as much additional disk space as the commitment domain itself. Can't be changed after the first start`,
+	}
 
 	ExperimentsFlag = cli.StringFlag{
 		Name: "experiments",
@@ -195,6 +201,15 @@ var (
 		Value: 5_000,
 	}
 
+	SyncSupplyFlag = cli.BoolFlag{
+		Name:  "sync.supply",
+		Usage: "Enables the Supply stage, which records the ether issuance and burn of every block (see erigon_getSupplyDelta)",
+	}
+	SyncTraceResultsFlag = cli.BoolFlag{
+		Name:  "sync.trace-results",
+		Usage: "Enables the TraceResults stage, which persists trace_block results of every block, so trace_block/trace_transaction/trace_filter don't re-execute",
+	}
+
 	UploadLocationFlag = cli.StringFlag{
 		Name:  "upload.location",
 		Usage: "Location to upload snapshot segments to",
@@ -339,6 +354,7 @@ func ApplyFlagsForEthConfig(ctx *cli.Context, cfg *ethconfig.Config, logger log.
 	if err != nil {
 		utils.Fatalf(fmt.Sprintf("error while parsing mode: %v", err))
 	}
+	mode.CommitmentHistory = ctx.Bool(PruneIncludeCommitmentHistoryFlag.Name)
 	cfg.Prune = mode
 	if ctx.String(BatchSizeFlag.Name) != "" {
 		err := cfg.BatchSize.UnmarshalText([]byte(ctx.String(BatchSizeFlag.Name)))
@@ -394,6 +410,9 @@ func ApplyFlagsForEthConfig(ctx *cli.Context, cfg *ethconfig.Config, logger log.
 		cfg.Sync.LoopBlockLimit = limit
 	}
 
+	cfg.Sync.TraceSupply = ctx.Bool(SyncSupplyFlag.Name)
+	cfg.Sync.TraceResults = ctx.Bool(SyncTraceResultsFlag.Name)
+
 	if location := ctx.String(UploadLocationFlag.Name); len(location) > 0 {
 		cfg.Sync.UploadLocation = location
 	}
@@ -481,6 +500,9 @@ func ApplyFlagsForEthConfigCobra(f *pflag.FlagSet, cfg *ethconfig.Config) {
 		if err != nil {
 			utils.Fatalf(fmt.Sprintf("error while parsing mode: %v", err))
 		}
+		if v := f.Bool(PruneIncludeCommitmentHistoryFlag.Name, PruneIncludeCommitmentHistoryFlag.Value, PruneIncludeCommitmentHistoryFlag.Usage); v != nil {
+			mode.CommitmentHistory = *v
+		}
 		cfg.Prune = mode
 	}
 	if v := f.String(BatchSizeFlag.Name, BatchSizeFlag.Value, BatchSizeFlag.Usage); v != nil {
@@ -566,6 +588,7 @@ func setEmbeddedRpcDaemon(ctx *cli.Context, cfg *nodecfg.Config, logger log.Logg
 		RpcStreamingDisable:               ctx.Bool(utils.RpcStreamingDisableFlag.Name),
 		DBReadConcurrency:                 ctx.Int(utils.DBReadConcurrencyFlag.Name),
 		RpcAllowListFilePath:              ctx.String(utils.RpcAccessListFlag.Name),
+		RpcRateLimitFilePath:              ctx.String(utils.RpcRateLimitFlag.Name),
 		RpcFiltersConfig: rpchelper.FiltersConfig{
 			RpcSubscriptionFiltersMaxLogs:      ctx.Int(RpcSubscriptionFiltersMaxLogsFlag.Name),
 			RpcSubscriptionFiltersMaxHeaders:   ctx.Int(RpcSubscriptionFiltersMaxHeadersFlag.Name),
@@ -615,6 +638,11 @@ func setEmbeddedRpcDaemon(ctx *cli.Context, cfg *nodecfg.Config, logger log.Logg
 		utils.Fatalf("Invalid state.cache value provided")
 	}
 
+	err = c.RpcResultCacheSize.UnmarshalText([]byte(ctx.String(utils.RpcResultCacheFlag.Name)))
+	if err != nil {
+		utils.Fatalf("Invalid rpc.resultcache value provided")
+	}
+
 	/*
 		rootCmd.PersistentFlags().BoolVar(&cfg.GRPCServerEnabled, "grpc", false, "Enable GRPC server")
 		rootCmd.PersistentFlags().StringVar(&cfg.GRPCListenAddress, "grpc.addr", node.DefaultGRPCHost, "GRPC server listening interface")
@@ -21,9 +21,13 @@ import (
 	"errors"
 	"fmt"
 
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 	remote "github.com/ledgerwatch/erigon-lib/gointerfaces/remoteproto"
+	proto_txpool "github.com/ledgerwatch/erigon-lib/gointerfaces/txpoolproto"
 	"github.com/ledgerwatch/erigon/p2p"
 
+	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/turbo/rpchelper"
 )
 
@@ -38,17 +42,25 @@ type AdminAPI interface {
 
 	// AddPeer requests connecting to a remote node.
 	AddPeer(ctx context.Context, url string) (bool, error)
+
+	// ExportLocalTransactions returns signed local transactions of the txpool.
+	ExportLocalTransactions(ctx context.Context) ([]hexutility.Bytes, error)
+
+	// ImportLocalTransactions adds signed transactions to the txpool as local ones.
+	ImportLocalTransactions(ctx context.Context, txs []hexutility.Bytes) ([]*TxPoolImportResult, error)
 }
 
 // AdminAPIImpl data structure to store things needed for admin_* commands.
 type AdminAPIImpl struct {
 	ethBackend rpchelper.ApiBackend
+	txPool     proto_txpool.TxpoolClient
 }
 
 // NewAdminAPI returns AdminAPIImpl instance.
-func NewAdminAPI(eth rpchelper.ApiBackend) *AdminAPIImpl {
+func NewAdminAPI(eth rpchelper.ApiBackend, txPool proto_txpool.TxpoolClient) *AdminAPIImpl {
 	return &AdminAPIImpl{
 		ethBackend: eth,
+		txPool:     txPool,
 	}
 }
 
@@ -79,3 +91,58 @@ func (api *AdminAPIImpl) AddPeer(ctx context.Context, url string) (bool, error)
 	}
 	return result.Success, nil
 }
+
+// ExportLocalTransactions implements admin_exportLocalTransactions. Returns signed local transactions of the pool
+// (the ones sent via this node), in the format accepted by admin_importLocalTransactions and eth_sendRawTransaction.
+func (api *AdminAPIImpl) ExportLocalTransactions(ctx context.Context) ([]hexutility.Bytes, error) {
+	reply, err := api.txPool.All(ctx, &proto_txpool.AllRequest{})
+	if err != nil {
+		return nil, err
+	}
+	txs := make([]hexutility.Bytes, 0, 8)
+	for i := range reply.Txs {
+		if reply.Txs[i].IsLocal {
+			txs = append(txs, reply.Txs[i].RlpTx)
+		}
+	}
+	return txs, nil
+}
+
+// TxPoolImportResult - outcome of admin_importLocalTransactions for one transaction
+type TxPoolImportResult struct {
+	Hash  libcommon.Hash `json:"hash"`
+	Error string         `json:"error,omitempty"`
+}
+
+// ImportLocalTransactions implements admin_importLocalTransactions. Adds signed transactions to the pool as local ones
+// and returns per-transaction results, in the order of input.
+func (api *AdminAPIImpl) ImportLocalTransactions(ctx context.Context, txs []hexutility.Bytes) ([]*TxPoolImportResult, error) {
+	results := make([]*TxPoolImportResult, len(txs))
+	rlpTxs := make([][]byte, 0, len(txs))
+	idx := make([]int, 0, len(txs)) // position of rlpTxs[i] in txs
+	for i, encodedTx := range txs {
+		results[i] = &TxPoolImportResult{}
+		txn, err := types.DecodeWrappedTransaction(encodedTx)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].Hash = txn.Hash()
+		rlpTxs = append(rlpTxs, encodedTx)
+		idx = append(idx, i)
+	}
+	if len(rlpTxs) == 0 {
+		return results, nil
+	}
+
+	reply, err := api.txPool.Add(ctx, &proto_txpool.AddRequest{RlpTxs: rlpTxs})
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range idx {
+		if reply.Imported[j] != proto_txpool.ImportResult_SUCCESS {
+			results[i].Error = fmt.Sprintf("%s: %s", proto_txpool.ImportResult_name[int32(reply.Imported[j])], reply.Errors[j])
+		}
+	}
+	return results, nil
+}
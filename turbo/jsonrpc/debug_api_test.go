@@ -35,6 +35,8 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
 	"github.com/ledgerwatch/erigon-lib/kv/stream"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/stateless"
 	"github.com/ledgerwatch/erigon/core/types"
 	tracersConfig "github.com/ledgerwatch/erigon/eth/tracers/config"
 	"github.com/ledgerwatch/erigon/rpc"
@@ -522,3 +524,42 @@ func TestAccountAt(t *testing.T) {
 		require.Equal(0, int(results.Nonce))
 	})
 }
+
+func TestExecutionWitness(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+
+	var blocks []*types.Block
+	err := m.DB.View(m.Ctx, func(tx kv.Tx) error {
+		head := rawdb.ReadCurrentHeader(tx)
+		for n := uint64(1); n <= head.Number.Uint64(); n++ {
+			block, err := m.BlockReader.BlockByNumber(m.Ctx, tx, n)
+			if err != nil {
+				return err
+			}
+			blocks = append(blocks, block)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, blocks)
+
+	for _, block := range blocks {
+		witness, err := api.ExecutionWitness(m.Ctx, rpc.BlockNumber(block.NumberU64()))
+		require.NoError(t, err, "block %d", block.NumberU64())
+		require.Equal(t, block.ParentHash(), witness.Headers[0].Hash())
+		require.NotEmpty(t, witness.State)
+
+		root, err := stateless.ExecuteBlock(m.ChainConfig, m.Engine, block, witness, log.New())
+		require.NoError(t, err, "block %d", block.NumberU64())
+		require.Equal(t, block.Root(), root)
+	}
+
+	// witness without one of the nodes is not enough for execution
+	block := blocks[len(blocks)-1]
+	witness, err := api.ExecutionWitness(m.Ctx, rpc.BlockNumber(block.NumberU64()))
+	require.NoError(t, err)
+	witness.State = witness.State[1:]
+	_, err = stateless.ExecuteBlock(m.ChainConfig, m.Engine, block, witness, log.New())
+	require.ErrorIs(t, err, stateless.ErrMissingNode)
+}
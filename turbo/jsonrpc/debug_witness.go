@@ -0,0 +1,151 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+	libstate "github.com/ledgerwatch/erigon-lib/state"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/stateless"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/eth/consensuschain"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+// ExecutionWitness implements debug_executionWitness. Re-executes the block on top of the historical state and
+// returns the witness: pre-state trie nodes of every touched account and storage slot, executed bytecodes and
+// headers accessed by BLOCKHASH, which is enough to execute the block without the database (see stateless.ExecuteBlock).
+// Trie nodes come from the commitment history, so witnesses are available only for blocks executed by the node
+// with --prune.include-commitment-history and not pruned by --prune.h.older.
+func (api *PrivateDebugAPIImpl) ExecutionWitness(ctx context.Context, blockNr rpc.BlockNumber) (*stateless.Witness, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blockNumber, hash, _, err := rpchelper.GetCanonicalBlockNumber(rpc.BlockNumberOrHashWithNumber(blockNr), tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	if blockNumber == 0 {
+		return nil, fmt.Errorf("witness of genesis block is not supported")
+	}
+	block, err := api.blockWithSenders(ctx, tx, hash, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", blockNumber)
+	}
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	engine, ok := api.engine().(consensus.Engine)
+	if !ok {
+		return nil, fmt.Errorf("execution witness requires consensus engine")
+	}
+	aggTx, err := aggregatorRoTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// state as of the beginning of the block, before its system txn
+	fromTxNum, err := rawdbv3.TxNums.Min(tx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	reader := state.NewHistoryReaderV3()
+	reader.SetTx(tx)
+	reader.SetTxNum(fromTxNum)
+	recorder := stateless.NewRecorder(reader)
+
+	headers := make(map[common.Hash]*types.Header)
+	getHeader := func(hash common.Hash, number uint64) *types.Header {
+		h, e := api._blockReader.Header(ctx, tx, hash, number)
+		if e != nil {
+			log.Error("getHeader error", "number", number, "hash", hash, "err", e)
+		}
+		if h != nil {
+			headers[hash] = h
+		}
+		return h
+	}
+	parent := getHeader(block.ParentHash(), blockNumber-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent of block %d not found", blockNumber)
+	}
+
+	vmConfig := vm.Config{}
+	chainReader := consensuschain.NewReader(chainConfig, tx, api._blockReader, log.New())
+	if _, err = core.ExecuteBlockEphemerally(chainConfig, &vmConfig, core.GetHashFn(block.HeaderNoCopy(), getHeader), engine, block,
+		recorder, state.NewNoopWriter(), chainReader, nil, log.New()); err != nil {
+		return nil, fmt.Errorf("execute block %d: %w", blockNumber, err)
+	}
+
+	keys := recorder.PlainKeys()
+	root, nodes, err := aggTx.WitnessAsOf(tx, fromTxNum, keys)
+	if errors.Is(err, libstate.ErrCommitmentHistoryDisabled) {
+		return nil, fmt.Errorf("%w: node must be started with --prune.include-commitment-history to serve witnesses", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("commitment history not available for block %d: %w", blockNumber-1, err)
+	}
+	if !bytes.Equal(root, parent.Root.Bytes()) {
+		return nil, fmt.Errorf("commitment history not available for block %d: computed root %x, expected %x", blockNumber-1, root, parent.Root)
+	}
+
+	codes := recorder.Codes()
+	witness := &stateless.Witness{
+		Headers: make([]*types.Header, 0, len(headers)),
+		Codes:   make([]hexutility.Bytes, len(codes)),
+		State:   make([]hexutility.Bytes, len(nodes)),
+		Keys:    make([]hexutility.Bytes, len(keys)),
+	}
+	for _, h := range headers {
+		witness.Headers = append(witness.Headers, h)
+	}
+	// parent goes first, then older ancestors
+	sort.Slice(witness.Headers, func(i, j int) bool {
+		return witness.Headers[i].Number.Uint64() > witness.Headers[j].Number.Uint64()
+	})
+	for i, code := range codes {
+		witness.Codes[i] = code
+	}
+	for i, node := range nodes {
+		witness.State[i] = node
+	}
+	for i, key := range keys {
+		witness.Keys[i] = key
+	}
+	return witness, nil
+}
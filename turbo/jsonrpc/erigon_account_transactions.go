@@ -0,0 +1,327 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/order"
+	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
+	"github.com/ledgerwatch/erigon-lib/kv/stream"
+
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+const (
+	// defaultAccountTransactionsLimit is the page size of erigon_getAccountTransactions when no limit is given.
+	defaultAccountTransactionsLimit = 25
+	// maxAccountTransactionsLimit is the maximum page size of erigon_getAccountTransactions.
+	maxAccountTransactionsLimit = 1_000
+)
+
+// Roles an address can play in a transaction returned by erigon_getAccountTransactions.
+const (
+	AccountTxRoleSender       = "sender"       // signer of the transaction
+	AccountTxRoleRecipient    = "recipient"    // `to` of the transaction, or the contract it deploys
+	AccountTxRoleLogTopic     = "logTopic"     // one of the topics of a log emitted by the transaction
+	AccountTxRoleInternalCall = "internalCall" // caller or callee of a nested call, create or selfdestruct
+)
+
+// AccountTransaction is a transaction touching the address requested by erigon_getAccountTransactions.
+type AccountTransaction struct {
+	Hash             common.Hash    `json:"hash"`
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	TransactionIndex hexutil.Uint64 `json:"transactionIndex"`
+	Roles            []string       `json:"roles"`
+}
+
+// AccountTransactions is a page of erigon_getAccountTransactions. Cursor continues the search
+// in the same direction and is nil on the last page.
+type AccountTransactions struct {
+	Transactions []*AccountTransaction `json:"transactions"`
+	Cursor       *string               `json:"cursor"`
+}
+
+// GetAccountTransactions implements erigon_getAccountTransactions. Returns the transactions of the inclusive
+// block range which touch the address, ordered by direction ("asc" or "desc", "desc" by default). Transactions are
+// found by the call traces and log topics inverted indexes, so no receipt is re-computed.
+// Pass the returned cursor with the same address, range and direction to get the next page.
+func (api *ErigonImpl) GetAccountTransactions(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber, direction string, cursor *string, limit *hexutil.Uint64) (*AccountTransactions, error) {
+	var asc order.By
+	switch direction {
+	case "asc":
+		asc = order.Asc
+	case "desc", "":
+		asc = order.Desc
+	default:
+		return nil, fmt.Errorf("invalid direction %q, expected \"asc\" or \"desc\"", direction)
+	}
+	pageSize := uint64(defaultAccountTransactionsLimit)
+	if limit != nil {
+		pageSize = uint64(*limit)
+	}
+	if pageSize == 0 || pageSize > maxAccountTransactionsLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxAccountTransactionsLimit)
+	}
+
+	dbtx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer dbtx.Rollback()
+	tx := dbtx.(kv.TemporalTx)
+
+	fromBlockNum, _, _, err := rpchelper.GetCanonicalBlockNumber(rpc.BlockNumberOrHashWithNumber(fromBlock), tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	toBlockNum, _, _, err := rpchelper.GetCanonicalBlockNumber(rpc.BlockNumberOrHashWithNumber(toBlock), tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	if fromBlockNum > toBlockNum {
+		return nil, fmt.Errorf("fromBlock %d is greater than toBlock %d", fromBlockNum, toBlockNum)
+	}
+
+	// [fromTxNum, toTxNum) in the order of the search
+	minTxNum, err := rawdbv3.TxNums.Min(tx, fromBlockNum)
+	if err != nil {
+		return nil, err
+	}
+	maxTxNum, err := rawdbv3.TxNums.Max(tx, toBlockNum)
+	if err != nil {
+		return nil, err
+	}
+	fromTxNum, toTxNum := int(minTxNum), int(maxTxNum)+1
+	if !asc {
+		fromTxNum, toTxNum = int(maxTxNum), int(minTxNum)-1
+	}
+	if cursor != nil {
+		cursorTxNum, err := decodeAccountTxCursor(*cursor, asc)
+		if err != nil {
+			return nil, err
+		}
+		if cursorTxNum < minTxNum || cursorTxNum > maxTxNum {
+			return nil, errors.New("cursor is out of the requested block range")
+		}
+		fromTxNum = int(cursorTxNum)
+	}
+
+	txNums, err := newAccountTxNumsStream(tx, address, fromTxNum, toTxNum, asc)
+	if err != nil {
+		return nil, err
+	}
+	defer txNums.Close()
+	it := rawdbv3.TxNums2BlockNums(tx, txNums, asc)
+
+	result := &AccountTransactions{Transactions: make([]*AccountTransaction, 0, pageSize)}
+	var block *types.Block
+	for it.HasNext() {
+		txNum, blockNum, txIndex, isFinalTxn, blockNumChanged, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		// system txs (block begin/end) have no hash and are not reported
+		if txIndex < 0 || isFinalTxn {
+			continue
+		}
+		if uint64(len(result.Transactions)) == pageSize {
+			next := encodeAccountTxCursor(txNum, asc)
+			result.Cursor = &next
+			break
+		}
+
+		if blockNumChanged || block == nil {
+			if block, err = api.blockByNumberWithSenders(ctx, tx, blockNum); err != nil {
+				return nil, err
+			}
+			if block == nil {
+				return nil, fmt.Errorf("block %d not found", blockNum)
+			}
+		}
+		if txIndex >= len(block.Transactions()) {
+			return nil, fmt.Errorf("transaction %d of block %d not found", txIndex, blockNum)
+		}
+		txn := block.Transactions()[txIndex]
+		result.Transactions = append(result.Transactions, &AccountTransaction{
+			Hash:             txn.Hash(),
+			BlockNumber:      hexutil.Uint64(blockNum),
+			TransactionIndex: hexutil.Uint64(txIndex),
+			Roles:            accountTxRoles(address, txn, txNums.lastHits),
+		})
+	}
+	return result, nil
+}
+
+// accountTxRoles derives the roles of the address from the transaction itself and the indexes it was found in.
+// An address which is also called internally by its own transaction is reported only as sender or recipient.
+func accountTxRoles(address common.Address, txn types.Transaction, hits accountTxHits) []string {
+	roles := make([]string, 0, 2)
+	sender, _ := txn.GetSender()
+	isSender := sender == address
+	isRecipient := false
+	if to := txn.GetTo(); to != nil {
+		isRecipient = *to == address
+	} else {
+		isRecipient = crypto.CreateAddress(sender, txn.GetNonce()) == address
+	}
+
+	if isSender {
+		roles = append(roles, AccountTxRoleSender)
+	}
+	if isRecipient {
+		roles = append(roles, AccountTxRoleRecipient)
+	}
+	if hits&accountTxHitLogTopic != 0 {
+		roles = append(roles, AccountTxRoleLogTopic)
+	}
+	if (hits&accountTxHitTraceFrom != 0 && !isSender) || (hits&accountTxHitTraceTo != 0 && !isRecipient) {
+		roles = append(roles, AccountTxRoleInternalCall)
+	}
+	return roles
+}
+
+// encodeAccountTxCursor - cursor is the first txNum of the next page and the direction it was issued for
+func encodeAccountTxCursor(txNum uint64, asc order.By) string {
+	var buf [9]byte
+	binary.BigEndian.PutUint64(buf[:8], txNum)
+	if asc {
+		buf[8] = 1
+	}
+	return base64.RawURLEncoding.EncodeToString(buf[:])
+}
+
+func decodeAccountTxCursor(cursor string, asc order.By) (uint64, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(buf) != 9 {
+		return 0, errors.New("invalid cursor")
+	}
+	if (buf[8] == 1) != bool(asc) {
+		return 0, errors.New("cursor was issued for the opposite direction")
+	}
+	return binary.BigEndian.Uint64(buf[:8]), nil
+}
+
+type accountTxHits uint8
+
+const (
+	accountTxHitTraceFrom accountTxHits = 1 << iota
+	accountTxHitTraceTo
+	accountTxHitLogTopic
+)
+
+// accountTxNumsStream - union of the inverted indexes an address can be found in, remembering which of
+// them matched the last returned txNum
+type accountTxNumsStream struct {
+	its      []stream.U64
+	hits     []accountTxHits
+	heads    []uint64
+	has      []bool
+	asc      order.By
+	err      error
+	lastHits accountTxHits
+}
+
+func newAccountTxNumsStream(tx kv.TemporalTx, address common.Address, fromTxNum, toTxNum int, asc order.By) (*accountTxNumsStream, error) {
+	topic := common.BytesToHash(address[:])
+	s := &accountTxNumsStream{asc: asc}
+	for _, idx := range []struct {
+		name kv.InvertedIdx
+		key  []byte
+		hit  accountTxHits
+	}{
+		{kv.TracesFromIdx, address[:], accountTxHitTraceFrom},
+		{kv.TracesToIdx, address[:], accountTxHitTraceTo},
+		{kv.LogTopicIdx, topic[:], accountTxHitLogTopic},
+	} {
+		it, err := tx.IndexRange(idx.name, idx.key, fromTxNum, toTxNum, asc, kv.Unlim)
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+		s.its = append(s.its, it)
+		s.hits = append(s.hits, idx.hit)
+	}
+	s.heads = make([]uint64, len(s.its))
+	s.has = make([]bool, len(s.its))
+	for i := range s.its {
+		s.advance(i)
+	}
+	return s, nil
+}
+
+func (s *accountTxNumsStream) advance(i int) {
+	if s.err != nil {
+		return
+	}
+	s.has[i] = s.its[i].HasNext()
+	if s.has[i] {
+		s.heads[i], s.err = s.its[i].Next()
+	}
+}
+
+func (s *accountTxNumsStream) HasNext() bool {
+	if s.err != nil {
+		return true
+	}
+	for _, has := range s.has {
+		if has {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *accountTxNumsStream) Next() (uint64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	var next uint64
+	found := false
+	for i, has := range s.has {
+		if has && (!found || (bool(s.asc) && s.heads[i] < next) || (!bool(s.asc) && s.heads[i] > next)) {
+			next, found = s.heads[i], true
+		}
+	}
+	if !found {
+		return 0, errors.New("accountTxNumsStream: no more elements")
+	}
+	s.lastHits = 0
+	for i, has := range s.has {
+		if has && s.heads[i] == next {
+			s.lastHits |= s.hits[i]
+			s.advance(i)
+		}
+	}
+	return next, s.err
+}
+
+func (s *accountTxNumsStream) Close() {
+	for _, it := range s.its {
+		it.Close()
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/stages/mock"
+)
+
+func TestGetAccountTransactions(t *testing.T) {
+	var (
+		signer         = types.LatestSignerForChainID(nil)
+		bankKey, _     = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		bankAddress    = crypto.PubkeyToAddress(bankKey.PublicKey)
+		userKey, _     = crypto.HexToECDSA("49a7b37aa6f6645917e7b807e9d1c00d4fa71f18343b0d4122a4d2df64dd6fee")
+		userAddress    = crypto.PubkeyToAddress(userKey.PublicKey)
+		emitterAddress = libcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+		relayAddress   = libcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+		// emits a log with the calldata as the only topic
+		emitterCode = []byte{byte(vm.PUSH1), 0, byte(vm.CALLDATALOAD), byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.LOG1), byte(vm.STOP)}
+		// calls the user
+		relayCode = append(append([]byte{
+			byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH20)},
+			userAddress[:]...),
+			byte(vm.GAS), byte(vm.CALL), byte(vm.STOP))
+		gspec = &types.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				bankAddress:    {Balance: big.NewInt(1e18)},
+				userAddress:    {Balance: big.NewInt(1e18)},
+				emitterAddress: {Balance: new(big.Int), Code: emitterCode},
+				relayAddress:   {Balance: new(big.Int), Code: relayCode},
+			},
+		}
+	)
+	m := mock.MockWithGenesis(t, gspec, bankKey, false)
+	send := func(block *core.BlockGen, key *ecdsa.PrivateKey, to libcommon.Address, data []byte) {
+		nonce := block.TxNonce(crypto.PubkeyToAddress(key.PublicKey))
+		txn, err := types.SignTx(types.NewTransaction(nonce, to, uint256.NewInt(1), 100_000, uint256.NewInt(1e9), data), *signer, key)
+		require.NoError(t, err)
+		block.AddTx(txn)
+	}
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 5, func(i int, block *core.BlockGen) {
+		switch i {
+		case 0:
+			send(block, bankKey, userAddress, nil)
+		case 1:
+			send(block, bankKey, emitterAddress, libcommon.BytesToHash(userAddress[:]).Bytes())
+		case 2:
+			send(block, bankKey, relayAddress, nil)
+		case 3:
+			send(block, userKey, bankAddress, nil)
+		case 4:
+			send(block, bankKey, bankAddress, nil)
+			send(block, userKey, relayAddress, nil)
+			send(block, bankKey, userAddress, nil)
+		}
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.InsertChain(chain))
+
+	type want struct {
+		block, txIndex uint64
+		roles          []string
+	}
+	wants := []want{
+		{5, 2, []string{AccountTxRoleRecipient}},
+		{5, 1, []string{AccountTxRoleSender, AccountTxRoleInternalCall}},
+		{4, 0, []string{AccountTxRoleSender}},
+		{3, 0, []string{AccountTxRoleInternalCall}},
+		{2, 0, []string{AccountTxRoleLogTopic}},
+		{1, 0, []string{AccountTxRoleRecipient}},
+	}
+
+	api := NewErigonAPI(newBaseApiForTest(m), m.DB, nil)
+	ctx := context.Background()
+	desc, err := api.GetAccountTransactions(ctx, userAddress, 0, rpc.LatestBlockNumber, "desc", nil, nil)
+	require.NoError(t, err)
+	require.Nil(t, desc.Cursor)
+	require.Len(t, desc.Transactions, len(wants))
+	for i, w := range wants {
+		txn := desc.Transactions[i]
+		require.Equal(t, hexutil.Uint64(w.block), txn.BlockNumber)
+		require.Equal(t, hexutil.Uint64(w.txIndex), txn.TransactionIndex)
+		require.Equal(t, chain.Blocks[w.block-1].Transactions()[w.txIndex].Hash(), txn.Hash)
+		require.Equal(t, w.roles, txn.Roles, "block %d tx %d", w.block, w.txIndex)
+	}
+
+	asc, err := api.GetAccountTransactions(ctx, userAddress, 0, rpc.LatestBlockNumber, "asc", nil, nil)
+	require.NoError(t, err)
+	slices.Reverse(asc.Transactions)
+	require.Equal(t, desc.Transactions, asc.Transactions)
+
+	// block range is inclusive
+	ranged, err := api.GetAccountTransactions(ctx, userAddress, 2, 4, "asc", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, ranged.Transactions, 3)
+	require.Equal(t, hexutil.Uint64(2), ranged.Transactions[0].BlockNumber)
+	require.Equal(t, hexutil.Uint64(4), ranged.Transactions[2].BlockNumber)
+
+	// pages, also splitting a block, concatenate to the full result
+	for _, direction := range []string{"asc", "desc"} {
+		var paged []*AccountTransaction
+		var cursor *string
+		limit := hexutil.Uint64(2)
+		for {
+			page, err := api.GetAccountTransactions(ctx, userAddress, 0, rpc.LatestBlockNumber, direction, cursor, &limit)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(page.Transactions), int(limit))
+			paged = append(paged, page.Transactions...)
+			if page.Cursor == nil {
+				break
+			}
+			cursor = page.Cursor
+		}
+		if direction == "asc" {
+			slices.Reverse(paged)
+		}
+		require.Equal(t, desc.Transactions, paged, direction)
+	}
+
+	limit := hexutil.Uint64(1)
+	page, err := api.GetAccountTransactions(ctx, userAddress, 0, rpc.LatestBlockNumber, "desc", nil, &limit)
+	require.NoError(t, err)
+	_, err = api.GetAccountTransactions(ctx, userAddress, 0, rpc.LatestBlockNumber, "asc", page.Cursor, &limit)
+	require.ErrorContains(t, err, "opposite direction")
+	_, err = api.GetAccountTransactions(ctx, userAddress, 0, 1, "desc", page.Cursor, &limit)
+	require.ErrorContains(t, err, "out of the requested block range")
+	_, err = api.GetAccountTransactions(ctx, userAddress, 0, rpc.LatestBlockNumber, "desc", nil, new(hexutil.Uint64))
+	require.Error(t, err)
+}
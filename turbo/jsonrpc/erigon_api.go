@@ -53,6 +53,12 @@ type ErigonAPI interface {
 	// Gets cannonical block receipt through hash. If the block is not cannonical returns error
 	GetBlockReceiptsByBlockHash(ctx context.Context, cannonicalBlockHash common.Hash) ([]map[string]interface{}, error)
 
+	// Supply related (see ./erigon_supply.go)
+	GetSupplyDelta(ctx context.Context, blockRange SupplyDeltaRange) ([]*SupplyDelta, error)
+
+	// Account related (see ./erigon_account_transactions.go)
+	GetAccountTransactions(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber, direction string, cursor *string, limit *hexutil.Uint64) (*AccountTransactions, error)
+
 	// NodeInfo returns a collection of metadata known about the host.
 	NodeInfo(ctx context.Context) ([]p2p.NodeInfo, error)
 }
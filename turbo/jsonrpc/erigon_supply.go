@@ -0,0 +1,124 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+// maxSupplyDeltaBlocks is the maximum number of blocks a single erigon_getSupplyDelta call may cover.
+const maxSupplyDeltaBlocks = 10_000
+
+// SupplyDeltaRange is the inclusive block range of erigon_getSupplyDelta.
+type SupplyDeltaRange struct {
+	FromBlock rpc.BlockNumber `json:"fromBlock"`
+	ToBlock   rpc.BlockNumber `json:"toBlock"`
+}
+
+// SupplyDelta is the ether supply change of a single block.
+type SupplyDelta struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	Hash        common.Hash    `json:"hash"`
+	Delta       *hexutil.Big   `json:"delta"` // issuance minus burn, may be negative
+	Issuance    SupplyIssuance `json:"issuance"`
+	Burn        SupplyBurn     `json:"burn"`
+}
+
+type SupplyIssuance struct {
+	GenesisAlloc *hexutil.Big `json:"genesisAlloc"`
+	Reward       *hexutil.Big `json:"reward"`
+	Withdrawals  *hexutil.Big `json:"withdrawals"`
+}
+
+type SupplyBurn struct {
+	EIP1559 *hexutil.Big `json:"1559"`
+	Blob    *hexutil.Big `json:"blob"`
+	Misc    *hexutil.Big `json:"misc"`
+}
+
+// GetSupplyDelta implements erigon_getSupplyDelta. Returns the ether issuance and burn of every block in the range.
+// Requires the Supply stage to be enabled with --sync.supply.
+func (api *ErigonImpl) GetSupplyDelta(ctx context.Context, blockRange SupplyDeltaRange) ([]*SupplyDelta, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	fromBlock, _, _, err := rpchelper.GetCanonicalBlockNumber(rpc.BlockNumberOrHashWithNumber(blockRange.FromBlock), tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	toBlock, _, _, err := rpchelper.GetCanonicalBlockNumber(rpc.BlockNumberOrHashWithNumber(blockRange.ToBlock), tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	if fromBlock > toBlock {
+		return nil, fmt.Errorf("fromBlock %d is greater than toBlock %d", fromBlock, toBlock)
+	}
+	if toBlock-fromBlock >= maxSupplyDeltaBlocks {
+		return nil, fmt.Errorf("block range is too wide, at most %d blocks are allowed", maxSupplyDeltaBlocks)
+	}
+
+	progress, err := stages.GetStageProgress(tx, stages.Supply)
+	if err != nil {
+		return nil, err
+	}
+	if toBlock > progress {
+		return nil, fmt.Errorf("supply is only tracked up to block %d, make sure the node runs with --sync.supply", progress)
+	}
+
+	result := make([]*SupplyDelta, 0, toBlock-fromBlock+1)
+	for blockNum := fromBlock; blockNum <= toBlock; blockNum++ {
+		delta, err := rawdb.ReadSupplyDelta(tx, blockNum)
+		if err != nil {
+			return nil, err
+		}
+		if delta == nil {
+			return nil, fmt.Errorf("supply delta of block %d not found", blockNum)
+		}
+		hash, err := api._blockReader.CanonicalHash(ctx, tx, blockNum)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &SupplyDelta{
+			BlockNumber: hexutil.Uint64(blockNum),
+			Hash:        hash,
+			Delta:       (*hexutil.Big)(delta.Delta()),
+			Issuance: SupplyIssuance{
+				GenesisAlloc: (*hexutil.Big)(delta.GenesisAlloc.ToBig()),
+				Reward:       (*hexutil.Big)(delta.Reward.ToBig()),
+				Withdrawals:  (*hexutil.Big)(delta.Withdrawals.ToBig()),
+			},
+			Burn: SupplyBurn{
+				EIP1559: (*hexutil.Big)(delta.EIP1559Burn.ToBig()),
+				Blob:    (*hexutil.Big)(delta.BlobBurn.ToBig()),
+				Misc:    (*hexutil.Big)(delta.MiscBurn.ToBig()),
+			},
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+	"github.com/ledgerwatch/erigon-lib/wrap"
+
+	"github.com/ledgerwatch/erigon/consensus/ethash"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/stages/mock"
+)
+
+func TestGetSupplyDelta(t *testing.T) {
+	var (
+		signer      = types.LatestSignerForChainID(nil)
+		bankKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		bankAddress = crypto.PubkeyToAddress(bankKey.PublicKey)
+		recipient   = libcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+		chainConfig = *params.TestChainConfig
+		gspec       = &types.Genesis{
+			Config: &chainConfig,
+			Alloc:  types.GenesisAlloc{bankAddress: {Balance: big.NewInt(1e18)}},
+		}
+	)
+	chainConfig.LondonBlock = big.NewInt(0)
+	m := mock.MockWithGenesis(t, gspec, bankKey, false)
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 3, func(i int, block *core.BlockGen) {
+		txn, err := types.SignTx(types.NewTransaction(block.TxNonce(bankAddress), recipient, uint256.NewInt(1000), params.TxGas, uint256.NewInt(1e9), nil), *signer, bankKey)
+		require.NoError(t, err)
+		block.AddTx(txn)
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.InsertChain(chain))
+
+	ctx := context.Background()
+	tx, err := m.DB.BeginRw(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	sync := stagedsync.New(ethconfig.Defaults.Sync, []*stagedsync.Stage{{ID: stages.Supply}}, nil, nil, log.New())
+	s, err := sync.StageState(stages.Supply, tx, m.DB, false, false)
+	require.NoError(t, err)
+	cfg := stagedsync.StageSupplyCfg(m.DB, m.ChainConfig, m.Engine, m.BlockReader, gspec)
+	require.NoError(t, stagedsync.SpawnSupplyStage(s, wrap.TxContainer{Tx: tx}, cfg, ctx, log.New()))
+	require.NoError(t, tx.Commit())
+
+	api := NewErigonAPI(newBaseApiForTest(m), m.DB, nil)
+	deltas, err := api.GetSupplyDelta(ctx, SupplyDeltaRange{FromBlock: 0, ToBlock: rpc.LatestBlockNumber})
+	require.NoError(t, err)
+	require.Len(t, deltas, len(chain.Blocks)+1)
+	require.Equal(t, big.NewInt(1e18), deltas[0].Issuance.GenesisAlloc.ToInt())
+	require.Equal(t, big.NewInt(1e18), deltas[0].Delta.ToInt())
+	for i, block := range chain.Blocks {
+		delta := deltas[i+1]
+		require.Equal(t, hexutil.Uint64(block.NumberU64()), delta.BlockNumber)
+		require.Equal(t, block.Hash(), delta.Hash)
+		require.Equal(t, ethash.ConstantinopleBlockReward.ToBig(), delta.Issuance.Reward.ToInt())
+		burn := new(big.Int).Mul(block.BaseFee(), new(big.Int).SetUint64(block.GasUsed()))
+		require.Equal(t, burn, delta.Burn.EIP1559.ToInt())
+		require.Equal(t, new(big.Int).Sub(ethash.ConstantinopleBlockReward.ToBig(), burn), delta.Delta.ToInt())
+	}
+
+	// unwinding drops the deltas of the unwound blocks
+	tx, err = m.DB.BeginRw(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	s, err = sync.StageState(stages.Supply, tx, m.DB, false, false)
+	require.NoError(t, err)
+	require.NoError(t, stagedsync.UnwindSupplyStage(&stagedsync.UnwindState{ID: stages.Supply, UnwindPoint: 1}, s, tx, cfg, ctx))
+	require.NoError(t, tx.Commit())
+
+	_, err = api.GetSupplyDelta(ctx, SupplyDeltaRange{FromBlock: 1, ToBlock: 2})
+	require.ErrorContains(t, err, "only tracked up to block 1")
+}
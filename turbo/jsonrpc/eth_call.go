@@ -17,6 +17,7 @@
 package jsonrpc
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -31,7 +32,9 @@ import (
 	"github.com/ledgerwatch/erigon-lib/gointerfaces"
 	txpool_proto "github.com/ledgerwatch/erigon-lib/gointerfaces/txpoolproto"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
 	"github.com/ledgerwatch/erigon-lib/log/v3"
+	libstate "github.com/ledgerwatch/erigon-lib/state"
 	types2 "github.com/ledgerwatch/erigon-lib/types"
 
 	"github.com/ledgerwatch/erigon/core"
@@ -320,100 +323,121 @@ func (api *APIImpl) EstimateGas(ctx context.Context, argsOrNil *ethapi2.CallArgs
 	return hexutil.Uint64(hi), nil
 }
 
-// maxGetProofRewindBlockCount limits the number of blocks into the past that
-// GetProof will allow computing proofs.  Because we must rewind the hash state
-// and re-compute the state trie, the further back in time the request, the more
-// computationally intensive the operation becomes.  The staged sync code
-// assumes that if more than 100_000 blocks are skipped, that the entire trie
-// should be re-computed. Re-computing the entire trie will currently take ~15
-// minutes on mainnet.  The current limit has been chosen arbitrarily as
-// 'useful' without likely being overly computationally intense.
-
-// GetProof is partially implemented; no Storage proofs, and proofs must be for
-// blocks within maxGetProofRewindBlockCount blocks of the head.
+// GetProof returns account and storage proofs for the state at given block. Trie branches
+// are read from commitment history as of the end of the block, and the resulting root is
+// verified against the header stateRoot. Only blocks within MaxGetProofRewindBlockCount
+// blocks of the head are served (100_000 by default), which also must be in commitment history:
+// executed by the node and not pruned by --prune.h.older.
 func (api *APIImpl) GetProof(ctx context.Context, address libcommon.Address, storageKeys []libcommon.Hash, blockNrOrHash rpc.BlockNumberOrHash) (*accounts.AccProofResult, error) {
-	return nil, fmt.Errorf("not supported by Erigon3")
-	/*
-		tx, err := api.db.BeginRo(ctx)
-		if err != nil {
-			return nil, err
-		}
-		defer tx.Rollback()
-
-		blockNr, _, _, err := rpchelper.GetBlockNumber(blockNrOrHash, tx, api.filters)
-		if err != nil {
-			return nil, err
-		}
-
-		header, err := api._blockReader.HeaderByNumber(ctx, tx, blockNr)
-		if err != nil {
-			return nil, err
-		}
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
-		latestBlock, err := rpchelper.GetLatestBlockNumber(tx)
-		if err != nil {
-			return nil, err
-		}
+	blockNr, _, _, err := rpchelper.GetBlockNumber(blockNrOrHash, tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
 
-		if latestBlock < blockNr {
-			// shouldn't happen, but check anyway
-			return nil, fmt.Errorf("block number is in the future latest=%d requested=%d", latestBlock, blockNr)
-		}
+	header, err := api._blockReader.HeaderByNumber(ctx, tx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("block %d not found", blockNr)
+	}
 
-		rl := trie.NewRetainList(0)
-		var loader *trie.FlatDBTrieLoader
-		if blockNr < latestBlock {
-			if latestBlock-blockNr > uint64(api.MaxGetProofRewindBlockCount) {
-				return nil, fmt.Errorf("requested block is too old, block must be within %d blocks of the head block number (currently %d)", uint64(api.MaxGetProofRewindBlockCount), latestBlock)
-			}
-			batch := membatchwithdb.NewMemoryBatch(tx, api.dirs.Tmp, api.logger)
-			defer batch.Rollback()
+	latestBlock, err := rpchelper.GetLatestBlockNumber(tx)
+	if err != nil {
+		return nil, err
+	}
 
-			unwindState := &stagedsync.UnwindState{UnwindPoint: blockNr}
-			stageState := &stagedsync.StageState{BlockNumber: latestBlock}
+	if latestBlock < blockNr {
+		// shouldn't happen, but check anyway
+		return nil, fmt.Errorf("block number is in the future latest=%d requested=%d", latestBlock, blockNr)
+	}
+	if api.MaxGetProofRewindBlockCount > 0 && latestBlock-blockNr > uint64(api.MaxGetProofRewindBlockCount) {
+		return nil, fmt.Errorf("requested block is too old, block must be within %d blocks of the head block number (currently %d)", uint64(api.MaxGetProofRewindBlockCount), latestBlock)
+	}
 
-			hashStageCfg := stagedsync.StageHashStateCfg(nil, api.dirs, api.historyV3(batch))
-			if err := stagedsync.UnwindHashStateStage(unwindState, stageState, batch, hashStageCfg, ctx, api.logger); err != nil {
-				return nil, err
-			}
+	// commitment of the block is computed after its last txNum
+	lastTxNum, err := rawdbv3.TxNums.Max(tx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([][]byte, len(storageKeys))
+	for i := range storageKeys {
+		keys[i] = storageKeys[i].Bytes()
+	}
+	aggTx, err := aggregatorRoTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := aggTx.ProofAsOf(tx, lastTxNum+1, address.Bytes(), keys)
+	if errors.Is(err, libstate.ErrCommitmentHistoryDisabled) {
+		return nil, fmt.Errorf("%w: node must be started with --prune.include-commitment-history to serve proofs", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("commitment history not available for block %d: %w", blockNr, err)
+	}
+	if !bytes.Equal(proof.RootHash, header.Root.Bytes()) {
+		return nil, fmt.Errorf("commitment history not available for block %d: computed root %x, expected %x", blockNr, proof.RootHash, header.Root)
+	}
 
-			interHashStageCfg := stagedsync.StageTrieCfg(nil, false, false, false, api.dirs.Tmp, api._blockReader, nil, api.historyV3(batch), api._agg)
-			loader, err = stagedsync.UnwindIntermediateHashesForTrieLoader("eth_getProof", rl, unwindState, stageState, batch, interHashStageCfg, nil, nil, ctx.Done(), api.logger)
-			if err != nil {
-				return nil, err
-			}
-			tx = batch
-		} else {
-			loader = trie.NewFlatDBTrieLoader("eth_getProof", rl, nil, nil, false)
-		}
+	reader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, "")
+	if err != nil {
+		return nil, err
+	}
+	a, err := reader.ReadAccountData(address)
+	if err != nil {
+		return nil, err
+	}
+	storageHash := libcommon.BytesToHash(proof.StorageRoot)
+	if a == nil {
+		// absent account is reported with zero fields, as other clients do
+		a = &accounts.Account{}
+		storageHash = libcommon.Hash{}
+	}
 
-		reader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, "")
-		if err != nil {
-			return nil, err
-		}
-		a, err := reader.ReadAccountData(address)
+	result := &accounts.AccProofResult{
+		Address:      address,
+		AccountProof: make([]hexutility.Bytes, len(proof.AccountProof)),
+		Balance:      (*hexutil.Big)(a.Balance.ToBig()),
+		CodeHash:     a.CodeHash,
+		Nonce:        hexutil.Uint64(a.Nonce),
+		StorageHash:  storageHash,
+		StorageProof: make([]accounts.StorProofResult, len(storageKeys)),
+	}
+	for i, node := range proof.AccountProof {
+		result.AccountProof[i] = node
+	}
+	for i := range storageKeys {
+		v, err := reader.ReadAccountStorage(address, a.Incarnation, &storageKeys[i])
 		if err != nil {
 			return nil, err
 		}
-		if a == nil {
-			a = &accounts.Account{}
+		sp := accounts.StorProofResult{
+			Key:   storageKeys[i],
+			Value: (*hexutil.Big)(new(big.Int).SetBytes(v)),
+			Proof: make([]hexutility.Bytes, len(proof.StorageProofs[i])),
 		}
-		pr, err := trie.NewProofRetainer(address, a, storageKeys, rl)
-		if err != nil {
-			return nil, err
-		}
-
-		loader.SetProofRetainer(pr)
-		root, err := loader.CalcTrieRoot(tx, nil)
-		if err != nil {
-			return nil, err
+		for j, node := range proof.StorageProofs[i] {
+			sp.Proof[j] = node
 		}
+		result.StorageProof[i] = sp
+	}
+	return result, nil
+}
 
-		if root != header.Root {
-			return nil, fmt.Errorf("mismatch in expected state root computed %v vs %v indicates bug in proof implementation", root, header.Root)
+// aggregatorRoTx returns the state files of tx. Commitment is read from them, so a remote DB can't serve proofs.
+func aggregatorRoTx(tx kv.Tx) (*libstate.AggregatorRoTx, error) {
+	if withAggTx, ok := tx.(libstate.HasAggTx); ok {
+		if aggTx, ok := withAggTx.AggTx().(*libstate.AggregatorRoTx); ok {
+			return aggTx, nil
 		}
-		return pr.ProofResult()
-	*/
+	}
+	return nil, errors.New("commitment history requires local datadir: run rpcdaemon with --datadir")
 }
 
 func (api *APIImpl) tryBlockFromLru(hash libcommon.Hash) *types.Block {
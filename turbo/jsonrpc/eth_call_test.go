@@ -34,6 +34,7 @@ import (
 	txpool "github.com/ledgerwatch/erigon-lib/gointerfaces/txpoolproto"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
 
 	"github.com/ledgerwatch/erigon-lib/log/v3"
 
@@ -44,6 +45,7 @@ import (
 	"github.com/ledgerwatch/erigon/core/state"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/ethdb/prune"
 	"github.com/ledgerwatch/erigon/params"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/rpc/rpccfg"
@@ -108,13 +110,16 @@ func TestEthCallToPrunedBlock(t *testing.T) {
 	}
 }
 
+func TestGetProofRequiresStateFiles(t *testing.T) {
+	_, tx := memdb.NewTestTx(t) // not a temporal tx, as with remote DB
+	_, err := aggregatorRoTx(tx)
+	require.ErrorContains(t, err, "requires local datadir")
+}
+
 func TestGetProof(t *testing.T) {
 	var maxGetProofRewindBlockCount = 1 // Note, this is unsafe for parallel tests, but, this test is the only consumer for now
 
 	m, bankAddr, contractAddr := chainWithDeployedContract(t)
-	if m.HistoryV3 {
-		t.Skip("not supported by Erigon3")
-	}
 	api := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, 1e18, 100_000, false, maxGetProofRewindBlockCount, 128, log.New())
 
 	key := func(b byte) libcommon.Hash {
@@ -515,7 +520,9 @@ func chainWithDeployedContract(t *testing.T) (*mock.MockSentry, libcommon.Addres
 			Alloc:  types.GenesisAlloc{bankAddress: {Balance: bankFunds}},
 		}
 	)
-	m := mock.MockWithGenesis(t, gspec, bankKey, false)
+	pm := prune.DefaultMode
+	pm.CommitmentHistory = true // needed by eth_getProof
+	m := mock.MockWithGenesisPruneMode(t, gspec, bankKey, 128, pm, false)
 	db := m.DB
 
 	var contractAddr libcommon.Address
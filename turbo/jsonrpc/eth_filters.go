@@ -20,6 +20,9 @@ import (
 	"context"
 	"strings"
 
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	txpoolproto "github.com/ledgerwatch/erigon-lib/gointerfaces/txpoolproto"
 	"github.com/ledgerwatch/erigon-lib/log/v3"
 
 	"github.com/ledgerwatch/erigon/common/debug"
@@ -265,6 +268,93 @@ func (api *APIImpl) NewPendingTransactionsWithBody(ctx context.Context) (*rpc.Su
 	return rpcSub, nil
 }
 
+// TxpoolEvent is the notification of the txpoolEvents subscription.
+type TxpoolEvent struct {
+	Type       string            `json:"type"` // add, promote, replace or discard
+	Hash       libcommon.Hash    `json:"hash"`
+	Sender     libcommon.Address `json:"sender"`
+	From       string            `json:"from,omitempty"`       // promote
+	To         string            `json:"to,omitempty"`         // add, promote
+	ReplacedBy *libcommon.Hash   `json:"replacedBy,omitempty"` // replace
+	Reason     string            `json:"reason,omitempty"`     // discard
+}
+
+func txpoolSubPoolName(t txpoolproto.AllReply_TxnType) string {
+	switch t {
+	case txpoolproto.AllReply_PENDING:
+		return "pending"
+	case txpoolproto.AllReply_BASE_FEE:
+		return "baseFee"
+	case txpoolproto.AllReply_QUEUED:
+		return "queued"
+	default:
+		return ""
+	}
+}
+
+func newTxpoolEvent(ev *txpoolproto.TxpoolEvent) *TxpoolEvent {
+	res := &TxpoolEvent{
+		Hash:   gointerfaces.ConvertH256ToHash(ev.Hash),
+		Sender: gointerfaces.ConvertH160toAddress(ev.Sender),
+	}
+	switch ev.Type {
+	case txpoolproto.TxpoolEventType_ADD:
+		res.Type = "add"
+		res.To = txpoolSubPoolName(ev.To)
+	case txpoolproto.TxpoolEventType_PROMOTE:
+		res.Type = "promote"
+		res.From = txpoolSubPoolName(ev.From)
+		res.To = txpoolSubPoolName(ev.To)
+	case txpoolproto.TxpoolEventType_REPLACE:
+		res.Type = "replace"
+		replacedBy := libcommon.Hash(gointerfaces.ConvertH256ToHash(ev.ReplacedBy))
+		res.ReplacedBy = &replacedBy
+	case txpoolproto.TxpoolEventType_DISCARD:
+		res.Type = "discard"
+		res.Reason = ev.DiscardMessage
+	}
+	return res
+}
+
+// TxpoolEvents send a notification each time a transaction is added to the mempool, moves between its
+// pending, baseFee and queued sub-pools, is replaced or discarded.
+func (api *APIImpl) TxpoolEvents(ctx context.Context) (*rpc.Subscription, error) {
+	if api.filters == nil {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		defer debug.LogPanic()
+		eventsCh, id := api.filters.SubscribeTxpoolEvents(256)
+		defer api.filters.UnsubscribeTxpoolEvents(id)
+
+		for {
+			select {
+			case events, ok := <-eventsCh:
+				for _, ev := range events {
+					if err := notifier.Notify(rpcSub.ID, newTxpoolEvent(ev)); err != nil {
+						log.Warn("[rpc] error while notifying subscription", "err", err)
+					}
+				}
+				if !ok {
+					log.Warn("[rpc] txpool events channel was closed")
+					return
+				}
+			case <-rpcSub.Err():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // Logs send a notification each time a new log appears.
 func (api *APIImpl) Logs(ctx context.Context, crit filters.FilterCriteria) (*rpc.Subscription, error) {
 	if api.filters == nil {
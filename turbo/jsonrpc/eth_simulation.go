@@ -0,0 +1,619 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+
+	"github.com/ledgerwatch/erigon/consensus/misc"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+	"github.com/ledgerwatch/erigon/turbo/transactions"
+)
+
+const (
+	// maxSimulateBlocks is the maximum number of blocks (including gap-filling empty blocks) a single
+	// eth_simulateV1 request may produce
+	maxSimulateBlocks = 256
+	// simulateBlockTimeIncrement is the default distance in seconds between consecutive simulated blocks
+	simulateBlockTimeIncrement = 12
+)
+
+// error codes defined by the eth_simulateV1 specification
+const (
+	simulateErrCodeNonceTooLow           = -38010
+	simulateErrCodeNonceTooHigh          = -38011
+	simulateErrCodeBaseFeeTooLow         = -38012
+	simulateErrCodeIntrinsicGas          = -38013
+	simulateErrCodeInsufficientFunds     = -38014
+	simulateErrCodeBlockGasLimitReached  = -38015
+	simulateErrCodeBlockNumberInvalid    = -38020
+	simulateErrCodeBlockTimestampInvalid = -38021
+	simulateErrCodeSenderIsNotEOA        = -38024
+	simulateErrCodeMaxInitCodeSize       = -38025
+	simulateErrCodeClientLimitExceeded   = -38026
+	simulateErrCodeInvalidParams         = -32602
+	simulateErrCodeVMError               = -32015
+	simulateErrCodeReverted              = 3
+)
+
+// transferLogAddress is the pseudo-contract emitting ETH transfer logs when traceTransfers is enabled
+var transferLogAddress = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+
+// transferTopic is keccak256("Transfer(address,address,uint256)"), identical to the ERC-20 event
+var transferTopic = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+// SimulationRequest is the first parameter of eth_simulateV1
+type SimulationRequest struct {
+	BlockStateCalls        []SimulatedBlock `json:"blockStateCalls"`
+	TraceTransfers         bool             `json:"traceTransfers"`
+	Validation             bool             `json:"validation"`
+	ReturnFullTransactions bool             `json:"returnFullTransactions"`
+}
+
+// SimulatedBlock describes the calls of one simulated block and the overrides applied before executing them
+type SimulatedBlock struct {
+	BlockOverrides *SimulatedBlockOverrides `json:"blockOverrides"`
+	StateOverrides *ethapi.StateOverrides   `json:"stateOverrides"`
+	Calls          []ethapi.CallArgs        `json:"calls"`
+}
+
+// SimulatedBlockOverrides are the header fields a simulated block may override
+type SimulatedBlockOverrides struct {
+	Number        *hexutil.Big    `json:"number"`
+	Time          *hexutil.Uint64 `json:"time"`
+	GasLimit      *hexutil.Uint64 `json:"gasLimit"`
+	FeeRecipient  *common.Address `json:"feeRecipient"`
+	PrevRandao    *common.Hash    `json:"prevRandao"`
+	BaseFeePerGas *hexutil.Big    `json:"baseFeePerGas"`
+	BlobBaseFee   *hexutil.Big    `json:"blobBaseFee"`
+}
+
+// SimulatedCallResult is the outcome of a single simulated call
+type SimulatedCallResult struct {
+	ReturnData hexutility.Bytes    `json:"returnData"`
+	Logs       []*types.Log        `json:"logs"`
+	GasUsed    hexutil.Uint64      `json:"gasUsed"`
+	Status     hexutil.Uint64      `json:"status"`
+	Error      *SimulatedCallError `json:"error,omitempty"`
+}
+
+// SimulatedCallError describes a reverted or failed call
+type SimulatedCallError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+// SimulateV1 implements eth_simulateV1. Executes the calls of several consecutive simulated blocks on top of the
+// given block, applying per-block header and state overrides, and returns the resulting blocks with call results.
+func (api *APIImpl) SimulateV1(ctx context.Context, req SimulationRequest, blockNrOrHash *rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	if len(req.BlockStateCalls) == 0 {
+		return nil, &rpc.InvalidParamsError{Message: "empty input"}
+	}
+	if blockNrOrHash == nil {
+		blockNrOrHash = &latestNumOrHash
+	}
+
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	blockNum, hash, _, err := rpchelper.GetCanonicalBlockNumber(*blockNrOrHash, tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	block, err := api.blockWithSenders(ctx, tx, hash, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %d(%x) not found", blockNum, hash)
+	}
+	parent := block.Header()
+
+	slots, err := planSimulatedBlocks(parent, req)
+	if err != nil {
+		return nil, err
+	}
+
+	stateReader, err := rpchelper.CreateStateReader(ctx, tx, *blockNrOrHash, 0, api.filters, api.stateCache, chainConfig.ChainName)
+	if err != nil {
+		return nil, err
+	}
+	ibs := state.New(stateReader)
+
+	// Setup context so it may be cancelled the call has completed
+	// or, in case of unmetered gas, setup a context with a timeout.
+	var cancel context.CancelFunc
+	if api.evmCallTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, api.evmCallTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	defer func(start time.Time) { log.Trace("Executing EVM simulateV1 finished", "runtime", time.Since(start)) }(time.Now())
+
+	// hashes of already simulated blocks are served to BLOCKHASH before falling back to the canonical chain
+	simulatedHashes := make(map[uint64]common.Hash, len(slots))
+	canonicalHash := transactions.MakeHeaderGetter(true, tx, api._blockReader)
+	getHash := func(n uint64) common.Hash {
+		if h, ok := simulatedHashes[n]; ok {
+			return h
+		}
+		return canonicalHash(n)
+	}
+
+	results := make([]map[string]interface{}, 0, len(slots))
+	for _, slot := range slots {
+		overrides := slot.block.BlockOverrides
+		if overrides == nil {
+			overrides = &SimulatedBlockOverrides{}
+		}
+		header := simulatedHeader(chainConfig, parent, slot.number, slot.time, overrides, req.Validation)
+		res, err := api.simulateBlock(ctx, chainConfig, ibs, header, slot.block, req, getHash)
+		if err != nil {
+			return nil, err
+		}
+		simulatedHashes[header.Number.Uint64()] = header.Hash()
+		results = append(results, res)
+		parent = header
+	}
+	return results, nil
+}
+
+// simulatedBlockSlot is a planned simulated block: its number, timestamp and the calls to execute in it
+type simulatedBlockSlot struct {
+	number uint64
+	time   uint64
+	block  *SimulatedBlock
+}
+
+// planSimulatedBlocks assigns numbers and timestamps to every simulated block, inserting empty blocks
+// into the gaps between explicitly numbered ones, and validates that both strictly increase.
+func planSimulatedBlocks(parent *types.Header, req SimulationRequest) ([]simulatedBlockSlot, error) {
+	var (
+		slots    []simulatedBlockSlot
+		prevNum  = parent.Number.Uint64()
+		prevTime = parent.Time
+	)
+	for i := range req.BlockStateCalls {
+		sb := &req.BlockStateCalls[i]
+		overrides := sb.BlockOverrides
+		if overrides == nil {
+			overrides = &SimulatedBlockOverrides{}
+		}
+		num := prevNum + 1
+		if overrides.Number != nil {
+			if !overrides.Number.ToInt().IsUint64() || overrides.Number.ToInt().Uint64() <= prevNum {
+				return nil, &rpc.CustomError{Code: simulateErrCodeBlockNumberInvalid,
+					Message: fmt.Sprintf("block numbers must be in order: %s <= %d", overrides.Number.ToInt(), prevNum)}
+			}
+			num = overrides.Number.ToInt().Uint64()
+		}
+		if num-parent.Number.Uint64() > maxSimulateBlocks {
+			return nil, &rpc.CustomError{Code: simulateErrCodeClientLimitExceeded, Message: "too many blocks"}
+		}
+		// fill the gap with empty blocks
+		for n := prevNum + 1; n < num; n++ {
+			prevTime += simulateBlockTimeIncrement
+			slots = append(slots, simulatedBlockSlot{number: n, time: prevTime, block: &SimulatedBlock{}})
+		}
+		t := prevTime + simulateBlockTimeIncrement
+		if overrides.Time != nil {
+			if uint64(*overrides.Time) <= prevTime {
+				return nil, &rpc.CustomError{Code: simulateErrCodeBlockTimestampInvalid,
+					Message: fmt.Sprintf("block timestamps must be in order: %d <= %d", uint64(*overrides.Time), prevTime)}
+			}
+			t = uint64(*overrides.Time)
+		}
+		slots = append(slots, simulatedBlockSlot{number: num, time: t, block: sb})
+		prevNum, prevTime = num, t
+	}
+	return slots, nil
+}
+
+// simulatedHeader derives the header of a simulated block from its parent and the block overrides.
+// The gas used, bloom and roots are filled in once the block has been executed.
+func simulatedHeader(chainConfig *chain.Config, parent *types.Header, num, time uint64, overrides *SimulatedBlockOverrides, validation bool) *types.Header {
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		UncleHash:  types.EmptyUncleHash,
+		Coinbase:   parent.Coinbase,
+		Difficulty: new(big.Int),
+		Number:     new(big.Int).SetUint64(num),
+		GasLimit:   parent.GasLimit,
+		Time:       time,
+		MixDigest:  parent.MixDigest,
+	}
+	if parent.Difficulty != nil && parent.Difficulty.Sign() > 0 {
+		header.Difficulty.Set(parent.Difficulty)
+	}
+	if overrides.FeeRecipient != nil {
+		header.Coinbase = *overrides.FeeRecipient
+	}
+	if overrides.GasLimit != nil {
+		header.GasLimit = uint64(*overrides.GasLimit)
+	}
+	if overrides.PrevRandao != nil {
+		header.MixDigest = *overrides.PrevRandao
+	}
+	if chainConfig.IsLondon(num) {
+		switch {
+		case overrides.BaseFeePerGas != nil:
+			header.BaseFee = new(big.Int).Set(overrides.BaseFeePerGas.ToInt())
+		case validation:
+			header.BaseFee = misc.CalcBaseFee(chainConfig, parent)
+		default:
+			header.BaseFee = new(big.Int)
+		}
+	}
+	if chainConfig.IsShanghai(time) {
+		header.WithdrawalsHash = &types.EmptyRootHash
+	}
+	if chainConfig.IsCancun(time) {
+		excessBlobGas := misc.CalcExcessBlobGas(chainConfig, parent)
+		header.ExcessBlobGas = &excessBlobGas
+		header.BlobGasUsed = new(uint64)
+		header.ParentBeaconBlockRoot = &common.Hash{}
+	}
+	return header
+}
+
+// simulateBlock executes the calls of one simulated block on top of ibs and returns the marshalled block.
+// header is completed in place so that it can serve as the parent of the next simulated block.
+func (api *APIImpl) simulateBlock(ctx context.Context, chainConfig *chain.Config, ibs *state.IntraBlockState, header *types.Header,
+	sb *SimulatedBlock, req SimulationRequest, getHash func(uint64) common.Hash) (map[string]interface{}, error) {
+	rules := chainConfig.Rules(header.Number.Uint64(), header.Time)
+
+	var precompiles map[common.Address]vm.PrecompiledContract
+	if sb.StateOverrides != nil {
+		if err := sb.StateOverrides.Override(ibs); err != nil {
+			return nil, err
+		}
+		var err error
+		if precompiles, err = sb.StateOverrides.OverridePrecompiles(rules); err != nil {
+			return nil, err
+		}
+	}
+
+	blockCtx := core.NewEVMBlockContext(header, getHash, api.engine(), &header.Coinbase, chainConfig)
+	switch {
+	case sb.BlockOverrides != nil && sb.BlockOverrides.BlobBaseFee != nil:
+		blobBaseFee, overflow := uint256.FromBig(sb.BlockOverrides.BlobBaseFee.ToInt())
+		if overflow {
+			return nil, fmt.Errorf("blobBaseFee higher than 2^256-1")
+		}
+		blockCtx.BlobBaseFee = blobBaseFee
+	case !req.Validation && blockCtx.BlobBaseFee != nil:
+		// same as the base fee: blob gas is free unless the calls are validated
+		blockCtx.BlobBaseFee = new(uint256.Int)
+	}
+	var baseFee *uint256.Int
+	if header.BaseFee != nil {
+		baseFee, _ = uint256.FromBig(header.BaseFee)
+	}
+
+	var (
+		txs         = make(types.Transactions, 0, len(sb.Calls))
+		receipts    = make(types.Receipts, 0, len(sb.Calls))
+		callResults = make([]SimulatedCallResult, 0, len(sb.Calls))
+		gp          = new(core.GasPool).AddGas(header.GasLimit).AddBlobGas(chainConfig.GetMaxBlobGasPerBlock())
+		gasUsed     uint64
+		blobGasUsed uint64
+	)
+	for i := range sb.Calls {
+		args := sb.Calls[i]
+		if args.From == nil {
+			args.From = &common.Address{}
+		}
+		if args.Nonce == nil {
+			nonce := hexutil.Uint64(ibs.GetNonce(*args.From))
+			args.Nonce = &nonce
+		}
+		remaining := header.GasLimit - gasUsed
+		if args.Gas == nil {
+			args.Gas = (*hexutil.Uint64)(&remaining)
+		}
+		if uint64(*args.Gas) > remaining {
+			return nil, &rpc.CustomError{Code: simulateErrCodeBlockGasLimitReached,
+				Message: fmt.Sprintf("block gas limit reached: %d >= %d", gasUsed, header.GasLimit)}
+		}
+		if args.ChainID == nil {
+			args.ChainID = (*hexutil.Big)(chainConfig.ChainID)
+		}
+		msg, err := args.ToMessage(api.GasCap, baseFee)
+		if err != nil {
+			return nil, err
+		}
+		// with validation the call is checked as a transaction of the block: the nonce, the sender being an EOA,
+		// the fee caps against the base fee and the blob base fee; the sender balance, the intrinsic gas
+		// and the gas left in the block are checked in any case
+		msg.SetCheckNonce(req.Validation)
+
+		txn := simulatedTransaction(&args, &msg, chainConfig, baseFee != nil)
+		ibs.SetTxContext(txn.Hash(), common.Hash{}, i)
+
+		vmConfig := vm.Config{NoBaseFee: !req.Validation}
+		var transfers *transferTracer
+		if req.TraceTransfers {
+			transfers = newTransferTracer()
+			vmConfig.Debug, vmConfig.Tracer = true, transfers
+		}
+		evm := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), ibs, chainConfig, vmConfig)
+		if precompiles != nil {
+			evm.SetPrecompiles(precompiles)
+		}
+		result, err := transactions.ApplyCallMessage(ctx, evm, msg, gp, api.evmCallTimeout)
+		if err != nil {
+			return nil, simulateTxError(err)
+		}
+		if err = ibs.FinalizeTx(rules, state.NewNoopWriter()); err != nil {
+			return nil, err
+		}
+		gasUsed += result.UsedGas
+		blobGasUsed += msg.BlobGas()
+
+		logs := ibs.GetLogs(txn.Hash())
+		if transfers != nil {
+			logs = transfers.mergeLogs(logs)
+		}
+		receipt := &types.Receipt{
+			Type:              txn.Type(),
+			CumulativeGasUsed: gasUsed,
+			TxHash:            txn.Hash(),
+			GasUsed:           result.UsedGas,
+			TransactionIndex:  uint(i),
+			Logs:              logs,
+		}
+		if result.Failed() {
+			receipt.Status = types.ReceiptStatusFailed
+		} else {
+			receipt.Status = types.ReceiptStatusSuccessful
+		}
+		if msg.To() == nil {
+			receipt.ContractAddress = crypto.CreateAddress(msg.From(), msg.Nonce())
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+
+		callResult := SimulatedCallResult{
+			ReturnData: result.Return(),
+			GasUsed:    hexutil.Uint64(result.UsedGas),
+			Status:     hexutil.Uint64(receipt.Status),
+		}
+		if result.Failed() {
+			callResult.ReturnData = result.Revert()
+			if errors.Is(result.Err, vm.ErrExecutionReverted) {
+				revertErr := ethapi.NewRevertError(result)
+				callResult.Error = &SimulatedCallError{Code: simulateErrCodeReverted, Message: revertErr.Error(), Data: hexutility.Encode(result.Revert())}
+			} else {
+				callResult.Error = &SimulatedCallError{Code: simulateErrCodeVMError, Message: result.Err.Error()}
+			}
+		}
+		if len(callResult.ReturnData) > api.ReturnDataLimit {
+			return nil, fmt.Errorf("call returned result on length %d exceeding --rpc.returndata.limit %d", len(callResult.ReturnData), api.ReturnDataLimit)
+		}
+
+		txs = append(txs, txn)
+		receipts = append(receipts, receipt)
+		callResults = append(callResults, callResult)
+	}
+
+	header.GasUsed = gasUsed
+	if header.BlobGasUsed != nil {
+		header.BlobGasUsed = &blobGasUsed
+	}
+	var withdrawals types.Withdrawals
+	if header.WithdrawalsHash != nil {
+		withdrawals = types.Withdrawals{}
+	}
+	block := types.NewBlock(header, txs, nil, receipts, withdrawals, nil)
+	*header = *block.Header()
+
+	// logs were emitted before the block hash was known
+	blockHash := block.Hash()
+	logIndex := uint(0)
+	for i := range callResults {
+		for _, l := range receipts[i].Logs {
+			l.BlockHash = blockHash
+			l.BlockNumber = header.Number.Uint64()
+			l.TxHash = txs[i].Hash()
+			l.TxIndex = uint(i)
+			l.Index = logIndex
+			logIndex++
+		}
+		callResults[i].Logs = receipts[i].Logs
+		if callResults[i].Logs == nil {
+			callResults[i].Logs = []*types.Log{}
+		}
+	}
+
+	fields, err := ethapi.RPCMarshalBlock(block, true, req.ReturnFullTransactions, map[string]interface{}{"calls": callResults})
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// simulatedTransaction builds the unsigned transaction representing a simulated call in the resulting block.
+func simulatedTransaction(args *ethapi.CallArgs, msg *types.Message, chainConfig *chain.Config, london bool) types.Transaction {
+	var txn types.Transaction
+	chainID, _ := uint256.FromBig(chainConfig.ChainID)
+	switch {
+	case !london || args.GasPrice != nil:
+		txn = &types.LegacyTx{
+			CommonTx: types.CommonTx{Nonce: msg.Nonce(), Gas: msg.Gas(), To: msg.To(), Value: msg.Value(), Data: msg.Data()},
+			GasPrice: msg.GasPrice(),
+		}
+	case len(msg.BlobHashes()) > 0:
+		txn = &types.BlobTx{
+			DynamicFeeTransaction: types.DynamicFeeTransaction{
+				CommonTx:   types.CommonTx{Nonce: msg.Nonce(), Gas: msg.Gas(), To: msg.To(), Value: msg.Value(), Data: msg.Data()},
+				ChainID:    chainID,
+				Tip:        msg.Tip(),
+				FeeCap:     msg.FeeCap(),
+				AccessList: msg.AccessList(),
+			},
+			MaxFeePerBlobGas:    msg.MaxFeePerBlobGas(),
+			BlobVersionedHashes: msg.BlobHashes(),
+		}
+	default:
+		txn = &types.DynamicFeeTransaction{
+			CommonTx:   types.CommonTx{Nonce: msg.Nonce(), Gas: msg.Gas(), To: msg.To(), Value: msg.Value(), Data: msg.Data()},
+			ChainID:    chainID,
+			Tip:        msg.Tip(),
+			FeeCap:     msg.FeeCap(),
+			AccessList: msg.AccessList(),
+		}
+	}
+	txn.SetSender(msg.From())
+	return txn
+}
+
+// simulateTxError maps consensus errors of an invalid simulated transaction to eth_simulateV1 error codes.
+func simulateTxError(err error) error {
+	code := -32000
+	switch {
+	case errors.Is(err, core.ErrNonceTooLow):
+		code = simulateErrCodeNonceTooLow
+	case errors.Is(err, core.ErrNonceTooHigh):
+		code = simulateErrCodeNonceTooHigh
+	case errors.Is(err, core.ErrFeeCapTooLow):
+		code = simulateErrCodeBaseFeeTooLow
+	case errors.Is(err, core.ErrIntrinsicGas):
+		code = simulateErrCodeIntrinsicGas
+	case errors.Is(err, core.ErrInsufficientFunds):
+		code = simulateErrCodeInsufficientFunds
+	case errors.Is(err, core.ErrGasLimitReached):
+		code = simulateErrCodeBlockGasLimitReached
+	case errors.Is(err, core.ErrSenderNoEOA):
+		code = simulateErrCodeSenderIsNotEOA
+	case errors.Is(err, core.ErrMaxInitCodeSizeExceeded):
+		code = simulateErrCodeMaxInitCodeSize
+	case errors.Is(err, core.ErrTipAboveFeeCap):
+		code = simulateErrCodeInvalidParams
+	}
+	return &rpc.CustomError{Code: code, Message: err.Error()}
+}
+
+// transferTracer records ETH value transfers of a simulated call as synthetic ERC-20 Transfer logs.
+// Real LOG opcodes are recorded as placeholders, so that transfers and logs keep their execution order.
+// Entries of reverted frames are dropped, mirroring what happens to logs in the IntraBlockState.
+type transferTracer struct {
+	frames [][]*types.Log // per call-depth entries; nil entries stand for a real log
+}
+
+func newTransferTracer() *transferTracer {
+	return &transferTracer{}
+}
+
+func (t *transferTracer) pushFrame(from, to common.Address, value *uint256.Int, typ vm.OpCode) {
+	var entries []*types.Log
+	if value != nil && !value.IsZero() && typ != vm.DELEGATECALL && typ != vm.STATICCALL {
+		amount := value.Bytes32()
+		entries = append(entries, &types.Log{
+			Address: transferLogAddress,
+			Topics:  []common.Hash{transferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+			Data:    amount[:],
+		})
+	}
+	t.frames = append(t.frames, entries)
+}
+
+func (t *transferTracer) popFrame(err error) {
+	if err != nil {
+		t.frames[len(t.frames)-1] = nil
+	}
+	if len(t.frames) == 1 {
+		// keep the outermost frame for mergeLogs
+		return
+	}
+	last := t.frames[len(t.frames)-1]
+	t.frames = t.frames[:len(t.frames)-1]
+	t.frames[len(t.frames)-1] = append(t.frames[len(t.frames)-1], last...)
+}
+
+// mergeLogs interleaves the recorded transfers with the logs emitted by the call.
+func (t *transferTracer) mergeLogs(logs []*types.Log) []*types.Log {
+	if len(t.frames) == 0 {
+		return logs
+	}
+	merged := make([]*types.Log, 0, len(logs)+len(t.frames[0]))
+	next := 0
+	for _, entry := range t.frames[0] {
+		if entry != nil {
+			merged = append(merged, entry)
+			continue
+		}
+		if next < len(logs) {
+			merged = append(merged, logs[next])
+			next++
+		}
+	}
+	return append(merged, logs[next:]...)
+}
+
+func (t *transferTracer) CaptureTxStart(gasLimit uint64) {}
+func (t *transferTracer) CaptureTxEnd(restGas uint64)    {}
+func (t *transferTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	t.frames = t.frames[:0]
+	t.pushFrame(from, to, value, vm.CALL)
+}
+func (t *transferTracer) CaptureEnd(output []byte, usedGas uint64, err error) {
+	t.popFrame(err)
+}
+func (t *transferTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	t.pushFrame(from, to, value, typ)
+}
+func (t *transferTracer) CaptureExit(output []byte, usedGas uint64, err error) {
+	t.popFrame(err)
+}
+func (t *transferTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if err == nil && op >= vm.LOG0 && op <= vm.LOG4 && len(t.frames) > 0 {
+		t.frames[len(t.frames)-1] = append(t.frames[len(t.frames)-1], nil)
+	}
+}
+func (t *transferTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+var _ vm.EVMLogger = (*transferTracer)(nil)
@@ -0,0 +1,218 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
+	"github.com/ledgerwatch/erigon/turbo/stages/mock"
+)
+
+func TestSimulateV1(t *testing.T) {
+	m, bankAddress, _ := chainWithDeployedContract(t)
+	api := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, 1e18, 100_000, false, 100_000, 128, log.New())
+	ctx := context.Background()
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	var (
+		sender    = libcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+		recipient = libcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+		funds     = (*hexutil.Big)(big.NewInt(1e18))
+		value     = (*hexutil.Big)(big.NewInt(1000))
+		fundsPtr  = &funds
+	)
+
+	head, err := api.BlockNumber(ctx)
+	require.NoError(t, err)
+
+	t.Run("state overrides and transfers", func(t *testing.T) {
+		gapNumber := (*hexutil.Big)(new(big.Int).SetUint64(uint64(head) + 3))
+		res, err := api.SimulateV1(ctx, SimulationRequest{
+			TraceTransfers: true,
+			BlockStateCalls: []SimulatedBlock{
+				{
+					StateOverrides: &ethapi.StateOverrides{sender: ethapi.Account{Balance: fundsPtr}},
+					Calls:          []ethapi.CallArgs{{From: &sender, To: &recipient, Value: value}},
+				},
+				{
+					BlockOverrides: &SimulatedBlockOverrides{Number: gapNumber},
+					Calls:          []ethapi.CallArgs{{From: &sender, To: &recipient, Value: value}},
+				},
+			},
+		}, &latest)
+		require.NoError(t, err)
+		// the second block is moved forward, so one empty block fills the gap
+		require.Len(t, res, 3)
+		require.Equal(t, (*hexutil.Big)(new(big.Int).SetUint64(uint64(head)+1)), res[0]["number"])
+		require.Equal(t, gapNumber, res[2]["number"])
+		require.Empty(t, res[1]["calls"])
+		require.Equal(t, res[0]["hash"], res[1]["parentHash"])
+
+		calls := res[0]["calls"].([]SimulatedCallResult)
+		require.Len(t, calls, 1)
+		require.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), calls[0].Status)
+		require.Len(t, calls[0].Logs, 1)
+		require.Equal(t, transferLogAddress, calls[0].Logs[0].Address)
+		require.Equal(t, transferTopic, calls[0].Logs[0].Topics[0])
+		require.Equal(t, libcommon.BytesToHash(recipient.Bytes()), calls[0].Logs[0].Topics[2])
+
+		// state carries over from one simulated block to the next
+		calls = res[2]["calls"].([]SimulatedCallResult)
+		require.Len(t, calls, 1)
+		require.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), calls[0].Status)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		nonce := hexutil.Uint64(1000)
+		_, err := api.SimulateV1(ctx, SimulationRequest{
+			Validation: true,
+			BlockStateCalls: []SimulatedBlock{{
+				Calls: []ethapi.CallArgs{{From: &bankAddress, To: &recipient, Value: value, Nonce: &nonce}},
+			}},
+		}, &latest)
+		require.Error(t, err)
+		require.Equal(t, simulateErrCodeNonceTooHigh, err.(rpc.Error).ErrorCode())
+	})
+
+	t.Run("block numbers must increase", func(t *testing.T) {
+		_, err := api.SimulateV1(ctx, SimulationRequest{
+			BlockStateCalls: []SimulatedBlock{
+				{BlockOverrides: &SimulatedBlockOverrides{Number: (*hexutil.Big)(new(big.Int).SetUint64(uint64(head) + 2))}},
+				{BlockOverrides: &SimulatedBlockOverrides{Number: (*hexutil.Big)(new(big.Int).SetUint64(uint64(head) + 1))}},
+			},
+		}, &latest)
+		require.Error(t, err)
+		require.Equal(t, simulateErrCodeBlockNumberInvalid, err.(rpc.Error).ErrorCode())
+	})
+
+	t.Run("move precompile", func(t *testing.T) {
+		identity := libcommon.BytesToAddress([]byte{0x04})
+		moved := libcommon.HexToAddress("0x0000000000000000000000000000000000123456")
+		input := hexutility.Bytes{0xde, 0xad, 0xbe, 0xef}
+		res, err := api.SimulateV1(ctx, SimulationRequest{
+			BlockStateCalls: []SimulatedBlock{{
+				StateOverrides: &ethapi.StateOverrides{identity: ethapi.Account{MovePrecompileToAddress: &moved}},
+				Calls: []ethapi.CallArgs{
+					{From: &sender, To: &moved, Input: &input},
+					{From: &sender, To: &identity, Input: &input},
+				},
+			}},
+		}, &latest)
+		require.NoError(t, err)
+		calls := res[0]["calls"].([]SimulatedCallResult)
+		require.Equal(t, input, calls[0].ReturnData)
+		require.Empty(t, calls[1].ReturnData)
+	})
+}
+
+func TestSimulateV1ValidationFailures(t *testing.T) {
+	m := mock.MockWithZeroTTD(t, false) // London and Cancun are active
+	api := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, 1e18, 100_000, false, 100_000, 128, log.New())
+	ctx := context.Background()
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	var (
+		sender    = m.Address
+		recipient = libcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+		feeCap    = (*hexutil.Big)(big.NewInt(2 * params.GWei))
+		code      = hexutility.Bytes{0x00}
+		nonce     = func(n uint64) *hexutil.Uint64 { return (*hexutil.Uint64)(&n) }
+		gas       = func(n uint64) *hexutil.Uint64 { return (*hexutil.Uint64)(&n) }
+		wei       = func(n int64) *hexutil.Big { return (*hexutil.Big)(big.NewInt(n)) }
+		initCode  = make(hexutility.Bytes, params.MaxInitCodeSize+1)
+		blobHash  = libcommon.Hash{0x01}
+	)
+
+	for _, tt := range []struct {
+		name      string
+		call      ethapi.CallArgs
+		overrides *ethapi.StateOverrides
+		code      int
+		message   string
+	}{
+		{name: "nonce too low", code: simulateErrCodeNonceTooLow,
+			call:      ethapi.CallArgs{From: &sender, To: &recipient, MaxFeePerGas: feeCap, Nonce: nonce(4)},
+			overrides: &ethapi.StateOverrides{sender: ethapi.Account{Nonce: nonce(5)}}},
+		{name: "nonce too high", code: simulateErrCodeNonceTooHigh,
+			call: ethapi.CallArgs{From: &sender, To: &recipient, MaxFeePerGas: feeCap, Nonce: nonce(1)}},
+		{name: "sender is not EOA", code: simulateErrCodeSenderIsNotEOA,
+			call:      ethapi.CallArgs{From: &sender, To: &recipient, MaxFeePerGas: feeCap},
+			overrides: &ethapi.StateOverrides{sender: ethapi.Account{Code: &code}}},
+		{name: "fee cap below base fee", code: simulateErrCodeBaseFeeTooLow,
+			call: ethapi.CallArgs{From: &sender, To: &recipient, MaxFeePerGas: wei(1)}},
+		{name: "tip above fee cap", code: simulateErrCodeInvalidParams,
+			call: ethapi.CallArgs{From: &sender, To: &recipient, MaxFeePerGas: feeCap, MaxPriorityFeePerGas: wei(3 * params.GWei)}},
+		{name: "insufficient funds", code: simulateErrCodeInsufficientFunds,
+			call: ethapi.CallArgs{From: &sender, To: &recipient, MaxFeePerGas: feeCap, Value: wei(params.Ether)}},
+		{name: "intrinsic gas", code: simulateErrCodeIntrinsicGas,
+			call: ethapi.CallArgs{From: &sender, To: &recipient, MaxFeePerGas: feeCap, Gas: gas(20_000)}},
+		{name: "block gas limit", code: simulateErrCodeBlockGasLimitReached,
+			call: ethapi.CallArgs{From: &sender, To: &recipient, MaxFeePerGas: feeCap, Gas: gas(m.Genesis.GasLimit() + 1)}},
+		{name: "max initcode size", code: simulateErrCodeMaxInitCodeSize,
+			call: ethapi.CallArgs{From: &sender, MaxFeePerGas: feeCap, Input: &initCode}},
+		{name: "blob fee cap below blob base fee", code: -32000, message: core.ErrMaxFeePerBlobGas.Error(),
+			call: ethapi.CallArgs{From: &sender, To: &recipient, MaxFeePerGas: feeCap, MaxFeePerBlobGas: wei(0), BlobVersionedHashes: []libcommon.Hash{blobHash}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := api.SimulateV1(ctx, SimulationRequest{
+				Validation:      true,
+				BlockStateCalls: []SimulatedBlock{{StateOverrides: tt.overrides, Calls: []ethapi.CallArgs{tt.call}}},
+			}, &latest)
+			require.Error(t, err)
+			require.Equal(t, tt.code, err.(rpc.Error).ErrorCode(), err.Error())
+			require.Contains(t, err.Error(), tt.message)
+		})
+	}
+
+	t.Run("valid blob call", func(t *testing.T) {
+		res, err := api.SimulateV1(ctx, SimulationRequest{
+			Validation: true,
+			BlockStateCalls: []SimulatedBlock{{Calls: []ethapi.CallArgs{
+				{From: &sender, To: &recipient, MaxFeePerGas: feeCap, MaxFeePerBlobGas: wei(params.GWei), BlobVersionedHashes: []libcommon.Hash{blobHash}},
+			}}},
+		}, &latest)
+		require.NoError(t, err)
+		calls := res[0]["calls"].([]SimulatedCallResult)
+		require.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), calls[0].Status)
+		require.NotZero(t, *res[0]["blobGasUsed"].(*hexutil.Uint64))
+	})
+
+	t.Run("fees are not checked without validation", func(t *testing.T) {
+		res, err := api.SimulateV1(ctx, SimulationRequest{
+			BlockStateCalls: []SimulatedBlock{{Calls: []ethapi.CallArgs{
+				{From: &sender, To: &recipient, MaxFeePerGas: wei(0), Nonce: nonce(7)},
+				{From: &sender, To: &recipient, MaxFeePerBlobGas: wei(0), BlobVersionedHashes: []libcommon.Hash{blobHash}},
+			}}},
+		}, &latest)
+		require.NoError(t, err)
+		require.Len(t, res[0]["calls"], 2)
+	})
+}
@@ -17,17 +17,24 @@
 package jsonrpc
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
 
+	jsoniter "github.com/json-iterator/go"
+
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/common/debug"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/eth/ethutils"
+	"github.com/ledgerwatch/erigon/eth/filters"
+	tracersConfig "github.com/ledgerwatch/erigon/eth/tracers/config"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
 	"github.com/ledgerwatch/erigon/turbo/rpchelper"
@@ -36,17 +43,31 @@ import (
 type GraphQLAPI interface {
 	GetBlockDetails(ctx context.Context, number rpc.BlockNumber) (map[string]interface{}, error)
 	GetChainID(ctx context.Context) (*big.Int, error)
+	GetTransactionTrace(ctx context.Context, hash common.Hash) (json.RawMessage, error)
+	GetAccountTransactions(ctx context.Context, addr common.Address, blockNum uint64, pageSize uint16) (map[string]interface{}, error)
+	GetBalanceChangesInBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (map[common.Address]*hexutil.Big, error)
+
+	// Subscriptions: returned channels are closed when ctx is done
+	SubscribeNewHeads(ctx context.Context) (<-chan *types.Header, error)
+	SubscribeLogs(ctx context.Context, crit filters.FilterCriteria) (<-chan *types.Log, error)
+	SubscribePendingTxs(ctx context.Context) (<-chan []types.Transaction, error)
 }
 
 type GraphQLAPIImpl struct {
 	*BaseAPI
-	db kv.RoDB
+	db     kv.RoDB
+	ots    *OtterscanAPIImpl
+	erigon *ErigonImpl
+	debug  *PrivateDebugAPIImpl
 }
 
-func NewGraphQLAPI(base *BaseAPI, db kv.RoDB) *GraphQLAPIImpl {
+func NewGraphQLAPI(base *BaseAPI, db kv.RoDB, gascap uint64, otsMaxPageSize uint64) *GraphQLAPIImpl {
 	return &GraphQLAPIImpl{
 		BaseAPI: base,
 		db:      db,
+		ots:     NewOtterscanAPI(base, db, otsMaxPageSize),
+		erigon:  NewErigonAPI(base, db, nil),
+		debug:   NewPrivateDebugAPI(base, db, gascap),
 	}
 }
 
@@ -170,3 +191,132 @@ func (api *GraphQLAPIImpl) delegateGetBlockByNumber(tx kv.Tx, b *types.Block, nu
 
 	return response, err
 }
+
+// GetTransactionTrace - result of the native callTracer for the txn, null if txn is not found
+func (api *GraphQLAPIImpl) GetTransactionTrace(ctx context.Context, hash common.Hash) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
+	tracer := "callTracer"
+	if err := api.debug.TraceTransaction(ctx, hash, &tracersConfig.TraceConfig{Tracer: &tracer}, stream); err != nil {
+		return nil, err
+	}
+	if err := stream.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GetAccountTransactions - page of txs touching addr before blockNum (0 - latest) found by otterscan search indexes, newest first.
+// Response has "transactions" - txn fields merged with the fields of its receipt, and "lastPage"
+func (api *GraphQLAPIImpl) GetAccountTransactions(ctx context.Context, addr common.Address, blockNum uint64, pageSize uint16) (map[string]interface{}, error) {
+	res, err := api.ots.SearchTransactionsBefore(ctx, addr, blockNum, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0, len(res.Txs))
+	for i, txn := range res.Txs {
+		transaction := map[string]interface{}{
+			"hash":  txn.Hash,
+			"nonce": txn.Nonce,
+			"from":  txn.From,
+			"gas":   txn.Gas,
+			"input": txn.Input,
+			"type":  txn.Type,
+		}
+		if txn.To != nil {
+			transaction["to"] = *txn.To
+		}
+		for field, value := range map[string]*hexutil.Big{
+			"value":                txn.Value,
+			"gasPrice":             txn.GasPrice,
+			"maxFeePerGas":         txn.FeeCap,
+			"maxPriorityFeePerGas": txn.Tip,
+			"blockNumber":          txn.BlockNumber,
+		} {
+			if value != nil {
+				transaction[field] = value
+			}
+		}
+		if txn.V != nil && txn.R != nil && txn.S != nil {
+			transaction["v"], transaction["r"], transaction["s"] = txn.V, txn.R, txn.S
+		}
+		if txn.TransactionIndex != nil {
+			transaction["transactionIndex"] = *txn.TransactionIndex
+		}
+		if txn.BlockHash != nil {
+			transaction["blockHash"] = *txn.BlockHash
+		}
+		if i < len(res.Receipts) {
+			for _, field := range []string{"status", "gasUsed", "cumulativeGasUsed", "effectiveGasPrice"} {
+				if value, ok := res.Receipts[i][field]; ok {
+					transaction[field] = value
+				}
+			}
+		}
+		result = append(result, transaction)
+	}
+
+	response := map[string]interface{}{}
+	response["transactions"] = result
+	response["lastPage"] = res.LastPage
+	return response, nil
+}
+
+// GetBalanceChangesInBlock - same as erigon_getBalanceChangesInBlock
+func (api *GraphQLAPIImpl) GetBalanceChangesInBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (map[common.Address]*hexutil.Big, error) {
+	return api.erigon.GetBalanceChangesInBlock(ctx, blockNrOrHash)
+}
+
+// SubscribeNewHeads - headers of new canonical blocks, same source as eth_subscribe("newHeads")
+func (api *GraphQLAPIImpl) SubscribeNewHeads(ctx context.Context) (<-chan *types.Header, error) {
+	if api.filters == nil {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	headers, id := api.filters.SubscribeNewHeads(32)
+	return forwardUntilDone(ctx, headers, func() { api.filters.UnsubscribeHeads(id) }), nil
+}
+
+// SubscribeLogs - logs of new canonical blocks matching crit, same source as eth_subscribe("logs")
+func (api *GraphQLAPIImpl) SubscribeLogs(ctx context.Context, crit filters.FilterCriteria) (<-chan *types.Log, error) {
+	if api.filters == nil {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	logs, id := api.filters.SubscribeLogs(256, crit)
+	return forwardUntilDone(ctx, logs, func() { api.filters.UnsubscribeLogs(id) }), nil
+}
+
+// SubscribePendingTxs - txs added to the txpool, same source as eth_subscribe("newPendingTransactions")
+func (api *GraphQLAPIImpl) SubscribePendingTxs(ctx context.Context) (<-chan []types.Transaction, error) {
+	if api.filters == nil {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	txs, id := api.filters.SubscribePendingTxs(256)
+	return forwardUntilDone(ctx, txs, func() { api.filters.UnsubscribePendingTxs(id) }), nil
+}
+
+// forwardUntilDone - copies subscription events to the returned channel, unsubscribes and closes it when ctx is done
+func forwardUntilDone[T any](ctx context.Context, in <-chan T, unsubscribe func()) <-chan T {
+	out := make(chan T, cap(in))
+	go func() {
+		defer debug.LogPanic()
+		defer close(out)
+		defer unsubscribe()
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	txpool "github.com/ledgerwatch/erigon-lib/gointerfaces/txpoolproto"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+func TestGraphQLSubscribePendingTxs(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+
+	noFilters := NewGraphQLAPI(NewBaseApi(nil, stateCache, m.BlockReader, m.HistoryV3Components(), false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs), m.DB, 0, 0)
+	_, err := noFilters.SubscribePendingTxs(context.Background())
+	require.ErrorIs(t, err, rpc.ErrNotificationsUnsupported)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ff := rpchelper.New(ctx, rpchelper.DefaultFiltersConfig, nil, nil, nil, func() {}, m.Log)
+	api := NewGraphQLAPI(NewBaseApi(ff, stateCache, m.BlockReader, m.HistoryV3Components(), false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs), m.DB, 0, 0)
+
+	txsCh, err := api.SubscribePendingTxs(ctx)
+	require.NoError(t, err)
+
+	txn, err := types.SignTx(types.NewTransaction(0, libcommon.Address{1}, uint256.NewInt(1), 21000, uint256.NewInt(1), nil), *types.LatestSignerForChainID(m.ChainConfig.ChainID), m.Key)
+	require.NoError(t, err)
+	var txnRlp bytes.Buffer
+	require.NoError(t, txn.MarshalBinary(&txnRlp))
+	ff.OnNewTx(&txpool.OnAddReply{RplTxs: [][]byte{txnRlp.Bytes()}})
+
+	select {
+	case txs := <-txsCh:
+		require.Len(t, txs, 1)
+		require.Equal(t, txn.Hash(), txs[0].Hash())
+	case <-time.After(5 * time.Second):
+		t.Fatal("pending txn is not delivered")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-txsCh:
+		require.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel is not closed after ctx is done")
+	}
+}
+
+func TestGraphQLGetTransactionTrace(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	baseApi := NewBaseApi(nil, stateCache, m.BlockReader, m.HistoryV3Components(), false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs)
+	api := NewGraphQLAPI(baseApi, m.DB, 0, 0)
+
+	for _, tt := range debugTraceTransactionTests {
+		res, err := api.GetTransactionTrace(m.Ctx, libcommon.HexToHash(tt.txHash))
+		require.NoError(t, err)
+		var frame struct {
+			Type    string         `json:"type"`
+			GasUsed hexutil.Uint64 `json:"gasUsed"`
+			Output  string         `json:"output"`
+		}
+		require.NoError(t, json.Unmarshal(res, &frame), tt.txHash)
+		require.Equal(t, "CALL", frame.Type, tt.txHash)
+		require.Equal(t, tt.gas, uint64(frame.GasUsed), tt.txHash)
+	}
+
+	res, err := api.GetTransactionTrace(m.Ctx, libcommon.Hash{1})
+	require.NoError(t, err)
+	require.Equal(t, "null", string(res))
+}
@@ -16,6 +16,17 @@ import (
 
 // SendRawTransaction implements eth_sendRawTransaction. Creates new message call transaction or a contract creation for previously-signed transactions.
 func (api *APIImpl) SendRawTransaction(ctx context.Context, encodedTx hexutility.Bytes) (common.Hash, error) {
+	return api.sendRawTransaction(ctx, encodedTx, false)
+}
+
+// SendPrivateRawTransaction implements eth_sendPrivateRawTransaction. Same as eth_sendRawTransaction, but the transaction
+// is never sent to peers: it can only be included into blocks built by this node and is dropped from the pool if it's
+// not mined within --txpool.private.lifetime blocks.
+func (api *APIImpl) SendPrivateRawTransaction(ctx context.Context, encodedTx hexutility.Bytes) (common.Hash, error) {
+	return api.sendRawTransaction(ctx, encodedTx, true)
+}
+
+func (api *APIImpl) sendRawTransaction(ctx context.Context, encodedTx hexutility.Bytes, private bool) (common.Hash, error) {
 	txn, err := types.DecodeWrappedTransaction(encodedTx)
 	if err != nil {
 		return common.Hash{}, err
@@ -53,7 +64,7 @@ func (api *APIImpl) SendRawTransaction(ctx context.Context, encodedTx hexutility
 	}
 
 	hash := txn.Hash()
-	res, err := api.txPool.Add(ctx, &txPoolProto.AddRequest{RlpTxs: [][]byte{encodedTx}})
+	res, err := api.txPool.Add(ctx, &txPoolProto.AddRequest{RlpTxs: [][]byte{encodedTx}, Private: private})
 	if err != nil {
 		return common.Hash{}, err
 	}
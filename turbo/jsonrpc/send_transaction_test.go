@@ -142,6 +142,43 @@ func TestSendRawTransaction(t *testing.T) {
 	//require.Equal(eth.ToProto[m.MultiClient.Protocol()][eth.NewPooledTransactionHashesMsg], sent.Id)
 }
 
+func TestSendPrivateRawTransaction(t *testing.T) {
+	mockSentry, require := mock.MockWithTxPool(t), require.New(t)
+	logger := log.New()
+
+	oneBlockStep(mockSentry, require, t)
+
+	expectedValue := uint64(1234)
+	txn, err := types.SignTx(types.NewTransaction(0, common.Address{1}, uint256.NewInt(expectedValue), params.TxGas, uint256.NewInt(10*params.GWei), nil), *types.LatestSignerForChainID(mockSentry.ChainConfig.ChainID), mockSentry.Key)
+	require.NoError(err)
+
+	ctx, conn := rpcdaemontest.CreateTestGrpcConn(t, mockSentry)
+	txPool := txpool.NewTxpoolClient(conn)
+	ff := rpchelper.New(ctx, rpchelper.DefaultFiltersConfig, nil, txPool, txpool.NewMiningClient(conn), func() {}, mockSentry.Log)
+	api := jsonrpc.NewEthAPI(newBaseApiForTest(mockSentry), mockSentry.DB, nil, txPool, nil, 5000000, 1e18, 100_000, false, 100_000, 128, logger)
+
+	buf := bytes.NewBuffer(nil)
+	err = txn.MarshalBinary(buf)
+	require.NoError(err)
+
+	txsCh, id := ff.SubscribePendingTxs(1)
+	defer ff.UnsubscribePendingTxs(id)
+
+	txHash, err := api.SendPrivateRawTransaction(ctx, buf.Bytes())
+	require.NoError(err)
+	require.Equal(txn.Hash(), txHash)
+
+	// in the pool, but not announced
+	jsonTx, err := api.GetTransactionByHash(ctx, txHash)
+	require.NoError(err)
+	require.Equal(expectedValue, jsonTx.Value.Uint64())
+	select {
+	case got := <-txsCh:
+		t.Fatalf("private txn announced: %x", got[0].Hash())
+	case <-time.After(time.Second):
+	}
+}
+
 func TestSendRawTransactionUnprotected(t *testing.T) {
 	mockSentry, require := mock.MockWithTxPool(t), require.New(t)
 	logger := log.New()
@@ -111,6 +111,16 @@ func (api *TraceAPIImpl) Transaction(ctx context.Context, txHash common.Hash, ga
 		}
 	}
 
+	if api.canUseTraceResults(*gasBailOut, traceConfig) {
+		blockTraces, ok, err := api.readTraceResults(ctx, tx, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return blockTraces.ofTransaction(uint64(txIndex)), nil
+		}
+	}
+
 	bn := hexutil.Uint64(blockNumber)
 	hash := block.Hash()
 	signer := types.MakeSigner(chainConfig, blockNumber, block.Time())
@@ -194,6 +204,16 @@ func (api *TraceAPIImpl) Block(ctx context.Context, blockNr rpc.BlockNumber, gas
 	}
 	bn := hexutil.Uint64(blockNum)
 
+	if api.canUseTraceResults(*gasBailOut, traceConfig) {
+		traces, ok, err := api.readTraceResults(ctx, tx, blockNum)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return traces, nil
+		}
+	}
+
 	// Extract transactions from block
 	block, bErr := api.blockWithSenders(ctx, tx, hash, blockNum)
 	if bErr != nil {
@@ -202,13 +222,18 @@ func (api *TraceAPIImpl) Block(ctx context.Context, blockNr rpc.BlockNumber, gas
 	if block == nil {
 		return nil, fmt.Errorf("could not find block %d", uint64(bn))
 	}
+	return api.traceBlock(ctx, tx, block, *gasBailOut, traceConfig)
+}
 
+// traceBlock re-executes the block, producing the trace_block result
+func (api *TraceAPIImpl) traceBlock(ctx context.Context, tx kv.Tx, block *types.Block, gasBailOut bool, traceConfig *config.TraceConfig) (ParityTraces, error) {
+	blockNum, hash := block.NumberU64(), block.Hash()
 	cfg, err := api.chainConfig(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
 	signer := types.MakeSigner(cfg, blockNum, block.Time())
-	traces, syscall, err := api.callManyTransactions(ctx, tx, block, []string{TraceTypeTrace}, -1 /* all txn indices */, *gasBailOut /* gasBailOut */, signer, cfg, traceConfig)
+	traces, syscall, err := api.callManyTransactions(ctx, tx, block, []string{TraceTypeTrace}, -1 /* all txn indices */, gasBailOut /* gasBailOut */, signer, cfg, traceConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -380,6 +405,8 @@ func (api *TraceAPIImpl) filterV3(ctx context.Context, dbtx kv.TemporalTx, fromB
 	var lastHeader *types.Header
 	var lastSigner *types.Signer
 	var lastRules *chain.Rules
+	var lastBlockTraces ParityTraces // persisted by the TraceResults stage, nil if the block must be re-executed
+	useTraceResults := api.canUseTraceResults(gasBailOut, traceConfig)
 
 	stateReader := state.NewHistoryReaderV3()
 	stateReader.SetTx(dbtx)
@@ -431,6 +458,21 @@ func (api *TraceAPIImpl) filterV3(ctx context.Context, dbtx kv.TemporalTx, fromB
 			lastBlockHash = lastHeader.Hash()
 			lastSigner = types.MakeSigner(chainConfig, blockNum, lastHeader.Time)
 			lastRules = chainConfig.Rules(blockNum, lastHeader.Time)
+
+			lastBlockTraces = nil
+			if useTraceResults {
+				if lastBlockTraces, _, err = api.readTraceResults(ctx, dbtx, blockNum); err != nil {
+					if first {
+						first = false
+					} else {
+						stream.WriteMore()
+					}
+					stream.WriteObjectStart()
+					rpc.HandleError(err, stream)
+					stream.WriteObjectEnd()
+					continue
+				}
+			}
 		}
 		if isFnalTxn {
 			// TODO(yperbasis) proper rewards for Gnosis
@@ -541,6 +583,38 @@ func (api *TraceAPIImpl) filterV3(ctx context.Context, dbtx kv.TemporalTx, fromB
 			continue
 		}
 		txIndexU64 := uint64(txIndex)
+		isIntersectionMode := req.Mode == TraceFilterModeIntersection
+		if lastBlockTraces != nil {
+			for _, pt := range lastBlockTraces.ofTransaction(txIndexU64) {
+				if includeAll || filterTrace(&pt, fromAddresses, toAddresses, isIntersectionMode) {
+					nSeen++
+					b, err := json.Marshal(pt)
+					if err != nil {
+						if first {
+							first = false
+						} else {
+							stream.WriteMore()
+						}
+						stream.WriteObjectStart()
+						rpc.HandleError(err, stream)
+						stream.WriteObjectEnd()
+						continue
+					}
+					if nSeen > after && nExported < count {
+						if first {
+							first = false
+						} else {
+							stream.WriteMore()
+						}
+						if _, err := stream.Write(b); err != nil {
+							return err
+						}
+						nExported++
+					}
+				}
+			}
+			continue
+		}
 		//fmt.Printf("txNum=%d, blockNum=%d, txIndex=%d\n", txNum, blockNum, txIndex)
 		txn, err := api._txnReader.TxnByIdxInBlock(ctx, dbtx, blockNum, txIndex)
 		if err != nil {
@@ -635,7 +709,6 @@ func (api *TraceAPIImpl) filterV3(ctx context.Context, dbtx kv.TemporalTx, fromB
 			stream.WriteObjectEnd()
 			continue
 		}
-		isIntersectionMode := req.Mode == TraceFilterModeIntersection
 		for _, pt := range traceResult.Trace {
 			if includeAll || filterTrace(pt, fromAddresses, toAddresses, isIntersectionMode) {
 				nSeen++
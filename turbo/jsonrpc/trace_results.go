@@ -0,0 +1,143 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang/snappy"
+
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/cli/httpcfg"
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/eth/tracers/config"
+	"github.com/ledgerwatch/erigon/turbo/services"
+)
+
+// NewTraceResultsProducer returns the function used by the TraceResults stage to produce the persisted
+// trace_block result of a canonical block. It traces with the defaults of trace_block: no gas bailout,
+// no tracer config and no OpenEthereum compatibility.
+func NewTraceResultsProducer(blockReader services.FullBlockReader, engine consensus.EngineReader, dirs datadir.Dirs) func(ctx context.Context, tx kv.TemporalTx, blockNum uint64) ([]byte, error) {
+	api := NewTraceAPI(NewBaseApi(nil, kvcache.NewDummy(), blockReader, nil, true, 0, engine, dirs), nil, &httpcfg.HttpCfg{})
+	return func(ctx context.Context, tx kv.TemporalTx, blockNum uint64) ([]byte, error) {
+		if blockNum == 0 {
+			return encodeTraceResults(ParityTraces{})
+		}
+		block, err := api.blockByNumberWithSenders(ctx, tx, blockNum)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			return nil, fmt.Errorf("could not find block %d", blockNum)
+		}
+		traces, err := api.traceBlock(ctx, tx, block, false /* gasBailOut */, nil /* traceConfig */)
+		if err != nil {
+			return nil, err
+		}
+		return encodeTraceResults(traces)
+	}
+}
+
+// canUseTraceResults - persisted trace results are produced with the defaults, other requests are re-executed
+func (api *TraceAPIImpl) canUseTraceResults(gasBailOut bool, traceConfig *config.TraceConfig) bool {
+	return !gasBailOut && traceConfig == nil && !api.compatibility
+}
+
+// readTraceResults returns the trace_block result persisted by the TraceResults stage, if any
+func (api *TraceAPIImpl) readTraceResults(ctx context.Context, tx kv.Tx, blockNum uint64) (ParityTraces, bool, error) {
+	data, ok, err := api._blockReader.TraceResults(ctx, tx, blockNum)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	traces, err := decodeTraceResults(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("trace results of block %d: %w", blockNum, err)
+	}
+	return traces, true, nil
+}
+
+// ofTransaction returns the traces of the transaction at the given position of the block
+func (traces ParityTraces) ofTransaction(txIndex uint64) ParityTraces {
+	out := make(ParityTraces, 0)
+	for _, pt := range traces {
+		if pt.TransactionPosition != nil && *pt.TransactionPosition == txIndex {
+			out = append(out, pt)
+		}
+	}
+	return out
+}
+
+func encodeTraceResults(traces ParityTraces) ([]byte, error) {
+	data, err := json.Marshal(traces)
+	if err != nil {
+		return nil, err
+	}
+	return snappy.Encode(nil, data), nil
+}
+
+// storedParityTrace - ParityTrace with action and result left undecoded until the trace type is known
+type storedParityTrace struct {
+	ParityTrace
+	Action json.RawMessage `json:"action"`
+	Result json.RawMessage `json:"result"`
+}
+
+func decodeTraceResults(data []byte) (ParityTraces, error) {
+	data, err := snappy.Decode(nil, data)
+	if err != nil {
+		return nil, err
+	}
+	var stored []storedParityTrace
+	if err = json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	traces := make(ParityTraces, len(stored))
+	for i := range stored {
+		pt := stored[i].ParityTrace
+		switch pt.Type {
+		case CALL:
+			pt.Action = &CallTraceAction{}
+			pt.Result = &TraceResult{}
+		case CREATE:
+			pt.Action = &CreateTraceAction{}
+			pt.Result = &CreateTraceResult{}
+		case SUICIDE:
+			pt.Action = &SuicideTraceAction{}
+		case REWARD:
+			pt.Action = &RewardTraceAction{}
+		default:
+			return nil, fmt.Errorf("unknown trace type: %q", pt.Type)
+		}
+		if err = json.Unmarshal(stored[i].Action, pt.Action); err != nil {
+			return nil, err
+		}
+		if pt.Result != nil {
+			if len(stored[i].Result) == 0 || bytes.Equal(stored[i].Result, []byte("null")) { // failed calls have no result
+				pt.Result = nil
+			} else if err = json.Unmarshal(stored[i].Result, pt.Result); err != nil {
+				return nil, err
+			}
+		}
+		traces[i] = pt
+	}
+	return traces, nil
+}
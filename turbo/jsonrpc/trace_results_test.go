@@ -0,0 +1,110 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/cli/httpcfg"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+// traceResultsOutput - trace_block of each block, trace_transaction of each transaction and trace_filter of the whole chain
+func traceResultsOutput(t *testing.T, api *TraceAPIImpl, txHashes []libcommon.Hash, head uint64) []string {
+	t.Helper()
+	ctx := context.Background()
+	var out []string
+	for blockNum := uint64(1); blockNum <= head; blockNum++ {
+		traces, err := api.Block(ctx, rpc.BlockNumber(blockNum), new(bool), nil)
+		require.NoError(t, err)
+		js, err := json.Marshal(traces)
+		require.NoError(t, err)
+		out = append(out, string(js))
+	}
+	for _, txHash := range txHashes {
+		traces, err := api.Transaction(ctx, txHash, new(bool), nil)
+		require.NoError(t, err)
+		js, err := json.Marshal(traces)
+		require.NoError(t, err)
+		out = append(out, string(js))
+	}
+
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	defer jsoniter.ConfigDefault.ReturnStream(stream)
+	fromBlock, toBlock := uint64(1), head
+	req := TraceFilterRequest{FromBlock: (*hexutil.Uint64)(&fromBlock), ToBlock: (*hexutil.Uint64)(&toBlock)}
+	require.NoError(t, api.Filter(ctx, req, new(bool), nil, stream))
+	return append(out, string(stream.Buffer()))
+}
+
+func TestTraceResultsMatchReExecution(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	api := NewTraceAPI(newBaseApiForTest(m), m.DB, &httpcfg.HttpCfg{})
+	ctx := context.Background()
+
+	var head uint64
+	var txHashes []libcommon.Hash
+	err := m.DB.View(ctx, func(tx kv.Tx) error {
+		head = *rawdb.ReadCurrentBlockNumber(tx)
+		for blockNum := uint64(1); blockNum <= head; blockNum++ {
+			block, err := m.BlockReader.BlockByNumber(ctx, tx, blockNum)
+			if err != nil {
+				return err
+			}
+			for _, txn := range block.Transactions() {
+				txHashes = append(txHashes, txn.Hash())
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, txHashes)
+	reExecuted := traceResultsOutput(t, api, txHashes, head)
+
+	produce := NewTraceResultsProducer(m.BlockReader, m.Engine, m.Dirs)
+	err = m.DB.Update(ctx, func(tx kv.RwTx) error {
+		for blockNum := uint64(0); blockNum <= head; blockNum++ {
+			data, err := produce(ctx, tx.(kv.TemporalTx), blockNum)
+			if err != nil {
+				return err
+			}
+			if err = rawdb.WriteTraceResults(tx, blockNum, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = m.DB.View(ctx, func(tx kv.Tx) error {
+		_, ok, err := api.readTraceResults(ctx, tx, head)
+		require.True(t, ok)
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, reExecuted, traceResultsOutput(t, api, txHashes, head))
+}
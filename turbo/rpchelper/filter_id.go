@@ -30,6 +30,7 @@ type (
 	PendingLogsSubID  SubscriptionID
 	PendingBlockSubID SubscriptionID
 	PendingTxsSubID   SubscriptionID
+	TxpoolEventsSubID SubscriptionID
 	LogsSubID         SubscriptionID
 )
 
@@ -58,10 +58,18 @@ type Filters struct {
 	pendingLogsSubs  *concurrent.SyncMap[PendingLogsSubID, Sub[types.Logs]]
 	pendingBlockSubs *concurrent.SyncMap[PendingBlockSubID, Sub[*types.Block]]
 	pendingTxsSubs   *concurrent.SyncMap[PendingTxsSubID, Sub[[]types.Transaction]]
+	txpoolEventsSubs *concurrent.SyncMap[TxpoolEventsSubID, Sub[[]*txpool.TxpoolEvent]]
 	logsSubs         *LogsFilterAggregator
 	logsRequestor    atomic.Value
 	onNewSnapshot    func()
 
+	// txpool events are streamed only while somebody is subscribed to them, as producing them costs the pool
+	ctx                  context.Context
+	txPool               txpool.TxpoolClient
+	txpoolEventsMu       sync.Mutex
+	txpoolEventsSubCount int
+	txpoolEventsCancel   context.CancelFunc
+
 	logsStores         *concurrent.SyncMap[LogsSubID, []*types.Log]
 	pendingHeadsStores *concurrent.SyncMap[HeadsSubID, []*types.Header]
 	pendingTxsStores   *concurrent.SyncMap[PendingTxsSubID, [][]types.Transaction]
@@ -79,6 +87,7 @@ func New(ctx context.Context, config FiltersConfig, ethBackend ApiBackend, txPoo
 	ff := &Filters{
 		headsSubs:          concurrent.NewSyncMap[HeadsSubID, Sub[*types.Header]](),
 		pendingTxsSubs:     concurrent.NewSyncMap[PendingTxsSubID, Sub[[]types.Transaction]](),
+		txpoolEventsSubs:   concurrent.NewSyncMap[TxpoolEventsSubID, Sub[[]*txpool.TxpoolEvent]](),
 		pendingLogsSubs:    concurrent.NewSyncMap[PendingLogsSubID, Sub[types.Logs]](),
 		pendingBlockSubs:   concurrent.NewSyncMap[PendingBlockSubID, Sub[*types.Block]](),
 		logsSubs:           NewLogsFilterAggregator(),
@@ -88,6 +97,8 @@ func New(ctx context.Context, config FiltersConfig, ethBackend ApiBackend, txPoo
 		pendingTxsStores:   concurrent.NewSyncMap[PendingTxsSubID, [][]types.Transaction](),
 		logger:             logger,
 		config:             config,
+		ctx:                ctx,
+		txPool:             txPool,
 	}
 
 	go func() {
@@ -257,6 +268,56 @@ func (ff *Filters) subscribeToPendingTransactions(ctx context.Context, txPool tx
 	return nil
 }
 
+// streamTxpoolEvents receives the events of the transaction pool until ctx is cancelled, re-subscribing on errors.
+func (ff *Filters) streamTxpoolEvents(ctx context.Context) {
+	activeSubscriptionsLogsClientGauge.With(prometheus.Labels{clientLabelName: "txPool_Events"}).Inc()
+	defer activeSubscriptionsLogsClientGauge.With(prometheus.Labels{clientLabelName: "txPool_Events"}).Dec()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		if err := ff.subscribeToTxpoolEvents(ctx, ff.txPool); err != nil {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+			if grpcutil.ErrIs(err, txpool2.ErrEventsSubscriberTooSlow) {
+				ff.logger.Debug("rpc filters: txpool events were dropped, resubscribing")
+				continue
+			}
+			if grpcutil.IsEndOfStream(err) || grpcutil.IsRetryLater(err) || grpcutil.ErrIs(err, txpool2.ErrPoolDisabled) {
+				time.Sleep(3 * time.Second)
+				continue
+			}
+			ff.logger.Warn("rpc filters: error subscribing to txpool events", "err", err)
+		}
+	}
+}
+
+// subscribeToTxpoolEvents subscribes to add, promote, replace and discard events of the transaction pool.
+func (ff *Filters) subscribeToTxpoolEvents(ctx context.Context, txPool txpool.TxpoolClient) error {
+	subscription, err := txPool.OnEvent(ctx, &txpool.OnEventRequest{}, grpc.WaitForReady(true))
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := subscription.Recv()
+		if errors.Is(err, io.EOF) {
+			ff.logger.Debug("rpcdaemon: the subscription to txpool events channel was closed")
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		ff.OnTxpoolEvents(event)
+	}
+	return nil
+}
+
 // subscribeToPendingBlocks subscribes to pending blocks using the given mining client.
 // It listens for new pending blocks and processes them as they arrive.
 func (ff *Filters) subscribeToPendingBlocks(ctx context.Context, mining txpool.MiningClient) error {
@@ -435,6 +496,47 @@ func (ff *Filters) UnsubscribePendingTxs(id PendingTxsSubID) bool {
 	return true
 }
 
+// SubscribeTxpoolEvents subscribes to the transaction pool events and returns a channel to receive them
+// and a subscription ID to manage the subscription. The stream of events from the pool is opened by the first subscriber.
+func (ff *Filters) SubscribeTxpoolEvents(size int) (<-chan []*txpool.TxpoolEvent, TxpoolEventsSubID) {
+	id := TxpoolEventsSubID(generateSubscriptionID())
+	sub := newChanSub[[]*txpool.TxpoolEvent](size)
+	ff.txpoolEventsSubs.Put(id, sub)
+
+	ff.txpoolEventsMu.Lock()
+	defer ff.txpoolEventsMu.Unlock()
+	ff.txpoolEventsSubCount++
+	if ff.txpoolEventsCancel == nil && ff.txPool != nil {
+		var ctx context.Context
+		ctx, ff.txpoolEventsCancel = context.WithCancel(ff.ctx)
+		go ff.streamTxpoolEvents(ctx)
+	}
+	return sub.ch, id
+}
+
+// UnsubscribeTxpoolEvents unsubscribes from the transaction pool events using the given subscription ID.
+// It returns true if the unsubscription was successful, otherwise false.
+func (ff *Filters) UnsubscribeTxpoolEvents(id TxpoolEventsSubID) bool {
+	ch, ok := ff.txpoolEventsSubs.Get(id)
+	if !ok {
+		return false
+	}
+	ch.Close()
+	if _, ok = ff.txpoolEventsSubs.Delete(id); !ok {
+		return false
+	}
+
+	// the pool stops producing events once the last subscriber is gone
+	ff.txpoolEventsMu.Lock()
+	defer ff.txpoolEventsMu.Unlock()
+	ff.txpoolEventsSubCount--
+	if ff.txpoolEventsSubCount == 0 && ff.txpoolEventsCancel != nil {
+		ff.txpoolEventsCancel()
+		ff.txpoolEventsCancel = nil
+	}
+	return true
+}
+
 // SubscribeLogs subscribes to logs using the specified filter criteria and returns a channel to receive the logs
 // and a subscription ID to manage the subscription.
 func (ff *Filters) SubscribeLogs(size int, criteria filters.FilterCriteria) (<-chan *types.Log, LogsSubID) {
@@ -650,6 +752,14 @@ func (ff *Filters) OnNewTx(reply *txpool.OnAddReply) {
 	})
 }
 
+// OnTxpoolEvents handles a batch of events from the transaction pool and sends it to the subscribers.
+func (ff *Filters) OnTxpoolEvents(reply *txpool.OnEventReply) {
+	ff.txpoolEventsSubs.Range(func(k TxpoolEventsSubID, v Sub[[]*txpool.TxpoolEvent]) error {
+		v.Send(reply.Events)
+		return nil
+	})
+}
+
 // OnNewLogs handles a new log event from the remote and processes it.
 func (ff *Filters) OnNewLogs(reply *remote.SubscribeLogsReply) {
 	ff.logsSubs.distributeLog(reply)
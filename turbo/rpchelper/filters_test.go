@@ -18,15 +18,20 @@ package rpchelper
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 
 	"github.com/ledgerwatch/erigon/core/types"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces"
 	remote "github.com/ledgerwatch/erigon-lib/gointerfaces/remoteproto"
+	txpool "github.com/ledgerwatch/erigon-lib/gointerfaces/txpoolproto"
 
 	types2 "github.com/ledgerwatch/erigon-lib/gointerfaces/typesproto"
 
@@ -507,3 +512,90 @@ func TestFilters_AddPendingTxs(t *testing.T) {
 		})
 	}
 }
+
+func TestFilters_TxpoolEvents(t *testing.T) {
+	f := New(context.TODO(), DefaultFiltersConfig, nil, nil, nil, func() {}, log.New())
+	events, id := f.SubscribeTxpoolEvents(8)
+
+	reply := &txpool.OnEventReply{Events: []*txpool.TxpoolEvent{
+		{Type: txpool.TxpoolEventType_ADD, Hash: topic1H256, Sender: address1H160, To: txpool.AllReply_QUEUED},
+		{Type: txpool.TxpoolEventType_PROMOTE, Hash: topic1H256, Sender: address1H160, From: txpool.AllReply_QUEUED, To: txpool.AllReply_PENDING},
+	}}
+	f.OnTxpoolEvents(reply)
+	got := <-events
+	if len(got) != 2 || got[1].Type != txpool.TxpoolEventType_PROMOTE {
+		t.Fatalf("unexpected events %v", got)
+	}
+
+	if !f.UnsubscribeTxpoolEvents(id) {
+		t.Fatal("expected to unsubscribe")
+	}
+	if _, ok := <-events; ok {
+		t.Fatal("expected the channel to be closed")
+	}
+	if f.UnsubscribeTxpoolEvents(id) {
+		t.Fatal("expected the second unsubscribe to fail")
+	}
+	f.OnTxpoolEvents(reply) // no subscribers
+}
+
+type txpoolEventsStub struct {
+	txpool.TxpoolClient
+	mu      sync.Mutex
+	streams []context.Context
+}
+
+func (s *txpoolEventsStub) OnAdd(ctx context.Context, _ *txpool.OnAddRequest, _ ...grpc.CallOption) (txpool.Txpool_OnAddClient, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *txpoolEventsStub) OnEvent(ctx context.Context, _ *txpool.OnEventRequest, _ ...grpc.CallOption) (txpool.Txpool_OnEventClient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.streams = append(s.streams, ctx)
+	return &txpoolEventsStreamStub{ctx: ctx}, nil
+}
+
+func (s *txpoolEventsStub) openStreams() []context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]context.Context{}, s.streams...)
+}
+
+type txpoolEventsStreamStub struct {
+	grpc.ClientStream
+	ctx context.Context
+}
+
+func (s *txpoolEventsStreamStub) Recv() (*txpool.OnEventReply, error) {
+	<-s.ctx.Done()
+	return nil, s.ctx.Err()
+}
+
+type noMiningStub struct {
+	txpool.MiningClient
+}
+
+func TestFilters_TxpoolEventsStreamedOnlyWhenSubscribed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool := &txpoolEventsStub{}
+	f := New(ctx, DefaultFiltersConfig, nil, pool, (*noMiningStub)(nil), func() {}, log.New())
+	require.Empty(t, pool.openStreams())
+
+	_, id1 := f.SubscribeTxpoolEvents(8)
+	_, id2 := f.SubscribeTxpoolEvents(8)
+	require.Eventually(t, func() bool { return len(pool.openStreams()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	stream := pool.openStreams()[0]
+
+	require.True(t, f.UnsubscribeTxpoolEvents(id1))
+	require.NoError(t, stream.Err())
+	require.True(t, f.UnsubscribeTxpoolEvents(id2))
+	require.Error(t, stream.Err())
+
+	_, id3 := f.SubscribeTxpoolEvents(8)
+	require.Eventually(t, func() bool { return len(pool.openStreams()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	require.True(t, f.UnsubscribeTxpoolEvents(id3))
+	require.Error(t, pool.openStreams()[1].Err())
+}
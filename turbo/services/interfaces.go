@@ -77,6 +77,12 @@ type BorCheckpointReader interface {
 	Checkpoint(ctx context.Context, tx kv.Getter, checkpointId uint64) ([]byte, error)
 }
 
+// TraceResultsReader reads the trace_block results persisted by the optional TraceResults stage
+type TraceResultsReader interface {
+	TraceResults(ctx context.Context, tx kv.Getter, blockNum uint64) ([]byte, bool, error)
+	FrozenTraceResults() uint64
+}
+
 type CanonicalReader interface {
 	CanonicalHash(ctx context.Context, tx kv.Getter, blockNum uint64) (common.Hash, error)
 	BadHeaderNumber(ctx context.Context, tx kv.Getter, hash common.Hash) (blockHeight *uint64, err error)
@@ -116,6 +122,7 @@ type FullBlockReader interface {
 	BorCheckpointReader
 	TxnReader
 	CanonicalReader
+	TraceResultsReader
 
 	FrozenBlocks() uint64
 	FrozenBorBlocks() uint64
@@ -125,6 +132,7 @@ type FullBlockReader interface {
 
 	Snapshots() BlockSnapshots
 	BorSnapshots() BlockSnapshots
+	TraceSnapshots() BlockSnapshots
 
 	AllTypes() []snaptype.Type
 }
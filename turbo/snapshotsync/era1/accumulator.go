@@ -0,0 +1,91 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package era1
+
+import (
+	"fmt"
+	"math/big"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+// ComputeAccumulator - hash_tree_root(List[HeaderRecord, MaxEra1Size]), where
+// HeaderRecord is container {block_hash: Bytes32, total_difficulty: Uint256}
+func ComputeAccumulator(hashes []libcommon.Hash, tds []*big.Int) (libcommon.Hash, error) {
+	if len(hashes) != len(tds) {
+		return libcommon.Hash{}, fmt.Errorf("era1: %d hashes, but %d total difficulties", len(hashes), len(tds))
+	}
+	if len(hashes) > MaxEra1Size {
+		return libcommon.Hash{}, fmt.Errorf("era1: too many blocks for accumulator: %d", len(hashes))
+	}
+	leaves := make([][32]byte, len(hashes))
+	for i := range hashes {
+		leaves[i] = utils.Sha256(hashes[i][:], tdBytes(tds[i]))
+	}
+	root, err := merkle_tree.MerkleizeVector(leaves, MaxEra1Size)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	lengthRoot := merkle_tree.Uint64Root(uint64(len(hashes)))
+	return utils.Sha256(root[:], lengthRoot[:]), nil
+}
+
+// VerifyAccumulator - recomputes accumulator from headers and total difficulties and compares it with the stored one
+func (r *Reader) VerifyAccumulator() (libcommon.Hash, error) {
+	expected, err := r.Accumulator()
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	hashes, tds := make([]libcommon.Hash, r.Count()), make([]*big.Int, r.Count())
+	for i := range hashes {
+		header, td, err := r.HeaderAndTD(r.start + uint64(i))
+		if err != nil {
+			return libcommon.Hash{}, err
+		}
+		hashes[i], tds[i] = header.Hash(), td
+	}
+	root, err := ComputeAccumulator(hashes, tds)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	if root != expected {
+		return libcommon.Hash{}, fmt.Errorf("era1: accumulator mismatch: computed %x, stored %x", root, expected)
+	}
+	return root, nil
+}
+
+// Verify - checks that transactions, uncles and receipts belong to the header
+func (e *Entry) Verify() error {
+	num := e.Header.Number.Uint64()
+	if root := types.DeriveSha(types.Transactions(e.Body.Transactions)); root != e.Header.TxHash {
+		return fmt.Errorf("era1: block %d: transactions root %x, header has %x", num, root, e.Header.TxHash)
+	}
+	if hash := types.CalcUncleHash(e.Body.Uncles); hash != e.Header.UncleHash {
+		return fmt.Errorf("era1: block %d: uncles hash %x, header has %x", num, hash, e.Header.UncleHash)
+	}
+	if len(e.Receipts) != len(e.Body.Transactions) {
+		return fmt.Errorf("era1: block %d: %d receipts for %d transactions", num, len(e.Receipts), len(e.Body.Transactions))
+	}
+	if root := types.DeriveSha(e.Receipts); root != e.Header.ReceiptHash {
+		return fmt.Errorf("era1: block %d: receipts root %x, header has %x", num, root, e.Header.ReceiptHash)
+	}
+	return nil
+}
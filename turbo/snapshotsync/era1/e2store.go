@@ -0,0 +1,94 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package era1
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// e2store - type-length-value container used by era and era1 files.
+// Record: type_2bytes_LE + length_4bytes_LE + reserved_2bytes_zero + data
+const headerSize = 8
+
+var ErrReservedNotZero = errors.New("e2store: reserved bytes of record header are not zero")
+
+type Record struct {
+	Type uint16
+	Data []byte
+}
+
+type e2storeWriter struct {
+	w   io.Writer
+	buf [headerSize]byte
+}
+
+func newE2storeWriter(w io.Writer) *e2storeWriter { return &e2storeWriter{w: w} }
+
+// Write - writes record, returns amount of written bytes including header
+func (e *e2storeWriter) Write(typ uint16, data []byte) (int, error) {
+	if uint64(len(data)) > uint64(^uint32(0)) {
+		return 0, fmt.Errorf("e2store: record of type %#x is too big: %d", typ, len(data))
+	}
+	binary.LittleEndian.PutUint16(e.buf[0:], typ)
+	binary.LittleEndian.PutUint32(e.buf[2:], uint32(len(data)))
+	e.buf[6], e.buf[7] = 0, 0
+	n, err := e.w.Write(e.buf[:])
+	if err != nil {
+		return n, err
+	}
+	m, err := e.w.Write(data)
+	return n + m, err
+}
+
+type e2storeReader struct {
+	r io.ReaderAt
+}
+
+func newE2storeReader(r io.ReaderAt) *e2storeReader { return &e2storeReader{r: r} }
+
+// readHeader - type and length of data of record at offset
+func (e *e2storeReader) readHeader(off int64) (typ uint16, length uint32, err error) {
+	var buf [headerSize]byte
+	if _, err := e.r.ReadAt(buf[:], off); err != nil {
+		return 0, 0, err
+	}
+	if buf[6] != 0 || buf[7] != 0 {
+		return 0, 0, fmt.Errorf("%w: offset %d", ErrReservedNotZero, off)
+	}
+	return binary.LittleEndian.Uint16(buf[0:]), binary.LittleEndian.Uint32(buf[2:]), nil
+}
+
+// ReadAt - record at offset, and amount of bytes it takes in the file
+func (e *e2storeReader) ReadAt(off int64) (*Record, int64, error) {
+	typ, length, err := e.readHeader(off)
+	if err != nil {
+		return nil, 0, err
+	}
+	data := make([]byte, length)
+	if length > 0 {
+		if _, err := e.r.ReadAt(data, off+headerSize); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, 0, fmt.Errorf("e2store: data of record at offset %d: %w", off, err)
+		}
+	}
+	return &Record{Type: typ, Data: data}, headerSize + int64(length), nil
+}
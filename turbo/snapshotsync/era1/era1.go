@@ -0,0 +1,398 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+// Package era1 - reader and writer of era1 archives: pre-merge history (blocks, receipts,
+// total difficulty) in epochs of 8192 blocks, in the format shared by execution clients.
+//
+//	era1 := Version | block-tuple* | other-entries* | Accumulator | BlockIndex
+//	block-tuple := CompressedHeader | CompressedBody | CompressedReceipts | TotalDifficulty
+//	BlockIndex := starting-number | index | index | index ... | count
+//
+// Compressed records are snappy framed RLP, total difficulty is 32 bytes little-endian,
+// index entries are offsets of CompressedHeader records relative to the start of BlockIndex record.
+package era1
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang/snappy"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+const (
+	TypeVersion            uint16 = 0x3265
+	TypeCompressedHeader   uint16 = 0x03
+	TypeCompressedBody     uint16 = 0x04
+	TypeCompressedReceipts uint16 = 0x05
+	TypeTotalDifficulty    uint16 = 0x06
+	TypeAccumulator        uint16 = 0x07
+	TypeBlockIndex         uint16 = 0x3266
+
+	// MaxEra1Size - amount of blocks in one epoch
+	MaxEra1Size = 8192
+)
+
+// Entry - block with everything era1 stores about it
+type Entry struct {
+	Header          *types.Header
+	Body            *types.Body
+	Receipts        types.Receipts
+	TotalDifficulty *big.Int
+}
+
+// Filename - <network>-<epoch>-<short-root>.era1, where short-root is first 4 bytes of accumulator root
+func Filename(network string, epoch uint64, root libcommon.Hash) string {
+	return fmt.Sprintf("%s-%05d-%x.era1", network, epoch, root[:4])
+}
+
+// ParseFilename - reverse of Filename
+func ParseFilename(name string) (network string, epoch uint64, shortRoot string, err error) {
+	base := strings.TrimSuffix(filepath.Base(name), ".era1")
+	if base == filepath.Base(name) {
+		return "", 0, "", fmt.Errorf("era1: not an era1 file: %s", name)
+	}
+	parts := strings.Split(base, "-")
+	if len(parts) < 3 {
+		return "", 0, "", fmt.Errorf("era1: unexpected file name: %s", name)
+	}
+	shortRoot = parts[len(parts)-1]
+	if len(shortRoot) != 8 {
+		return "", 0, "", fmt.Errorf("era1: unexpected root in file name: %s", name)
+	}
+	if epoch, err = strconv.ParseUint(parts[len(parts)-2], 10, 64); err != nil {
+		return "", 0, "", fmt.Errorf("era1: unexpected epoch in file name: %s", name)
+	}
+	return strings.Join(parts[:len(parts)-2], "-"), epoch, shortRoot, nil
+}
+
+// Builder - writes blocks of one epoch. Call Add for every block in order, then Finalize.
+type Builder struct {
+	w       *e2storeWriter
+	written int64
+
+	startNum *uint64
+	offsets  []int64
+	hashes   []libcommon.Hash
+	tds      []*big.Int
+
+	buf *bytes.Buffer
+	sw  *snappy.Writer
+}
+
+func NewBuilder(w io.Writer) *Builder {
+	buf := bytes.NewBuffer(nil)
+	return &Builder{w: newE2storeWriter(w), buf: buf, sw: snappy.NewBufferedWriter(buf)}
+}
+
+func (b *Builder) Add(block *types.Block, receipts types.Receipts, td *big.Int) error {
+	header, err := rlp.EncodeToBytes(block.Header())
+	if err != nil {
+		return err
+	}
+	body, err := rlp.EncodeToBytes(types.Body{Transactions: block.Transactions(), Uncles: block.Uncles()})
+	if err != nil {
+		return err
+	}
+	receiptsRLP, err := rlp.EncodeToBytes(receipts)
+	if err != nil {
+		return err
+	}
+	return b.AddRLP(header, body, receiptsRLP, block.NumberU64(), block.Hash(), td)
+}
+
+// AddRLP - like Add, but with already encoded header, body (list of transactions and uncles) and receipts
+func (b *Builder) AddRLP(header, body, receipts []byte, number uint64, hash libcommon.Hash, td *big.Int) error {
+	if len(b.offsets) >= MaxEra1Size {
+		return fmt.Errorf("era1: exceeds max size %d", MaxEra1Size)
+	}
+	if b.startNum == nil {
+		if err := b.write(TypeVersion, nil); err != nil {
+			return err
+		}
+		b.startNum = &number
+	} else if expected := *b.startNum + uint64(len(b.offsets)); number != expected {
+		return fmt.Errorf("era1: block %d added out of order, expected %d", number, expected)
+	}
+	if td.Sign() < 0 || td.BitLen() > 256 {
+		return fmt.Errorf("era1: invalid total difficulty of block %d: %s", number, td)
+	}
+
+	b.offsets = append(b.offsets, b.written)
+	b.hashes = append(b.hashes, hash)
+	b.tds = append(b.tds, new(big.Int).Set(td))
+
+	for _, r := range []struct {
+		typ  uint16
+		data []byte
+	}{{TypeCompressedHeader, header}, {TypeCompressedBody, body}, {TypeCompressedReceipts, receipts}} {
+		if err := b.writeCompressed(r.typ, r.data); err != nil {
+			return err
+		}
+	}
+	return b.write(TypeTotalDifficulty, tdBytes(td))
+}
+
+// Finalize - writes accumulator and block index, returns accumulator root
+func (b *Builder) Finalize() (libcommon.Hash, error) {
+	if b.startNum == nil {
+		return libcommon.Hash{}, errors.New("era1: finalize called on empty builder")
+	}
+	root, err := ComputeAccumulator(b.hashes, b.tds)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	if err := b.write(TypeAccumulator, root[:]); err != nil {
+		return libcommon.Hash{}, err
+	}
+
+	base := b.written
+	index := make([]byte, 8+8*len(b.offsets)+8)
+	binary.LittleEndian.PutUint64(index, *b.startNum)
+	for i, offset := range b.offsets {
+		binary.LittleEndian.PutUint64(index[8+i*8:], uint64(offset-base))
+	}
+	binary.LittleEndian.PutUint64(index[8+8*len(b.offsets):], uint64(len(b.offsets)))
+	if err := b.write(TypeBlockIndex, index); err != nil {
+		return libcommon.Hash{}, err
+	}
+	return root, nil
+}
+
+func (b *Builder) write(typ uint16, data []byte) error {
+	n, err := b.w.Write(typ, data)
+	b.written += int64(n)
+	return err
+}
+
+func (b *Builder) writeCompressed(typ uint16, data []byte) error {
+	b.buf.Reset()
+	b.sw.Reset(b.buf)
+	if _, err := b.sw.Write(data); err != nil {
+		return err
+	}
+	if err := b.sw.Flush(); err != nil {
+		return err
+	}
+	return b.write(typ, b.buf.Bytes())
+}
+
+func tdBytes(td *big.Int) []byte {
+	res := make([]byte, 32)
+	td.FillBytes(res)
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res
+}
+
+func tdFromBytes(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+// Reader - random access to blocks of era1 file
+type Reader struct {
+	e       *e2storeReader
+	start   uint64
+	offsets []int64 // absolute offsets of CompressedHeader records
+	indexAt int64
+}
+
+// File - Reader over opened file, Close it after use
+type File struct {
+	*Reader
+	f *os.File
+}
+
+func Open(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	r, err := NewReader(f, st.Size())
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%w: %s", err, path)
+	}
+	return &File{Reader: r, f: f}, nil
+}
+
+func (f *File) Close() error { return f.f.Close() }
+
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	e := newE2storeReader(r)
+	version, _, err := e.ReadAt(0)
+	if err != nil {
+		return nil, fmt.Errorf("era1: read version: %w", err)
+	}
+	if version.Type != TypeVersion {
+		return nil, fmt.Errorf("era1: first record has type %#x, expected version", version.Type)
+	}
+
+	if size < headerSize+16 {
+		return nil, errors.New("era1: file too small")
+	}
+	var buf [8]byte
+	if _, err := r.ReadAt(buf[:], size-8); err != nil {
+		return nil, err
+	}
+	count := binary.LittleEndian.Uint64(buf[:])
+	if count == 0 || count > MaxEra1Size {
+		return nil, fmt.Errorf("era1: invalid blocks count %d", count)
+	}
+	indexAt := size - headerSize - 16 - 8*int64(count)
+	index, _, err := e.ReadAt(indexAt)
+	if err != nil {
+		return nil, fmt.Errorf("era1: read block index: %w", err)
+	}
+	if index.Type != TypeBlockIndex {
+		return nil, fmt.Errorf("era1: last record has type %#x, expected block index", index.Type)
+	}
+	rd := &Reader{e: e, start: binary.LittleEndian.Uint64(index.Data), offsets: make([]int64, count), indexAt: indexAt}
+	for i := range rd.offsets {
+		rd.offsets[i] = indexAt + int64(binary.LittleEndian.Uint64(index.Data[8+8*i:]))
+		if rd.offsets[i] < 0 || rd.offsets[i] >= indexAt {
+			return nil, fmt.Errorf("era1: offset of block %d is out of file", rd.start+uint64(i))
+		}
+	}
+	return rd, nil
+}
+
+// Start - number of first block
+func (r *Reader) Start() uint64 { return r.start }
+
+// Count - amount of blocks
+func (r *Reader) Count() uint64 { return uint64(len(r.offsets)) }
+
+// Accumulator - root stored in the file, it's not verified against the blocks
+func (r *Reader) Accumulator() (libcommon.Hash, error) {
+	rec, _, err := r.e.ReadAt(r.indexAt - headerSize - 32)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	if rec.Type != TypeAccumulator || len(rec.Data) != 32 {
+		return libcommon.Hash{}, fmt.Errorf("era1: no accumulator before block index, record type %#x", rec.Type)
+	}
+	return libcommon.BytesToHash(rec.Data), nil
+}
+
+// HeaderAndTD - cheaper than Entry, enough to verify the accumulator
+func (r *Reader) HeaderAndTD(num uint64) (*types.Header, *big.Int, error) {
+	off, err := r.offset(num)
+	if err != nil {
+		return nil, nil, err
+	}
+	var header types.Header
+	if off, err = r.readCompressed(off, TypeCompressedHeader, &header); err != nil {
+		return nil, nil, err
+	}
+	for _, typ := range []uint16{TypeCompressedBody, TypeCompressedReceipts} {
+		rec, n, err := r.e.ReadAt(off)
+		if err != nil {
+			return nil, nil, err
+		}
+		if rec.Type != typ {
+			return nil, nil, fmt.Errorf("era1: block %d: record has type %#x, expected %#x", num, rec.Type, typ)
+		}
+		off += n
+	}
+	td, err := r.readTD(off, num)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &header, td, nil
+}
+
+func (r *Reader) Entry(num uint64) (*Entry, error) {
+	off, err := r.offset(num)
+	if err != nil {
+		return nil, err
+	}
+	e := &Entry{Header: &types.Header{}, Body: &types.Body{}}
+	if off, err = r.readCompressed(off, TypeCompressedHeader, e.Header); err != nil {
+		return nil, err
+	}
+	if off, err = r.readCompressed(off, TypeCompressedBody, e.Body); err != nil {
+		return nil, err
+	}
+	if off, err = r.readCompressed(off, TypeCompressedReceipts, &e.Receipts); err != nil {
+		return nil, err
+	}
+	if e.TotalDifficulty, err = r.readTD(off, num); err != nil {
+		return nil, err
+	}
+	if e.Header.Number.Uint64() != num {
+		return nil, fmt.Errorf("era1: header at index of block %d has number %d", num, e.Header.Number.Uint64())
+	}
+	return e, nil
+}
+
+func (r *Reader) offset(num uint64) (int64, error) {
+	if num < r.start || num >= r.start+r.Count() {
+		return 0, fmt.Errorf("era1: block %d is out of range [%d, %d)", num, r.start, r.start+r.Count())
+	}
+	return r.offsets[num-r.start], nil
+}
+
+func (r *Reader) readCompressed(off int64, typ uint16, to any) (int64, error) {
+	rec, n, err := r.e.ReadAt(off)
+	if err != nil {
+		return 0, err
+	}
+	if rec.Type != typ {
+		return 0, fmt.Errorf("era1: record at offset %d has type %#x, expected %#x", off, rec.Type, typ)
+	}
+	data, err := io.ReadAll(snappy.NewReader(bytes.NewReader(rec.Data)))
+	if err != nil {
+		return 0, fmt.Errorf("era1: decompress record at offset %d: %w", off, err)
+	}
+	if err := rlp.DecodeBytes(data, to); err != nil {
+		return 0, fmt.Errorf("era1: decode record at offset %d: %w", off, err)
+	}
+	return off + n, nil
+}
+
+func (r *Reader) readTD(off int64, num uint64) (*big.Int, error) {
+	rec, _, err := r.e.ReadAt(off)
+	if err != nil {
+		return nil, err
+	}
+	if rec.Type != TypeTotalDifficulty || len(rec.Data) != 32 {
+		return nil, fmt.Errorf("era1: block %d: no total difficulty record, got type %#x", num, rec.Type)
+	}
+	return tdFromBytes(rec.Data), nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package era1
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/chain/networkname"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+)
+
+type testBlock struct {
+	block    *types.Block
+	receipts types.Receipts
+	td       *big.Int
+}
+
+// testChain - pre-merge blocks [from, from+n) with a few transactions and uncles
+func testChain(t *testing.T, from uint64, n int) []testBlock {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+
+	var res []testBlock
+	parent, td, nonce := libcommon.Hash{}, big.NewInt(1000), uint64(0)
+	for i := 0; i < n; i++ {
+		num := from + uint64(i)
+		header := &types.Header{ParentHash: parent, Number: new(big.Int).SetUint64(num), Difficulty: big.NewInt(int64(100 + i)), GasLimit: 1_000_000, Time: num}
+		var txs types.Transactions
+		var receipts types.Receipts
+		for j := 0; j < i%3; j++ {
+			txn, err := types.SignTx(types.NewTransaction(nonce, libcommon.Address{1}, uint256.NewInt(1), 21_000, uint256.NewInt(1), nil), *signer, key)
+			require.NoError(t, err)
+			nonce++
+			txs = append(txs, txn)
+			receipts = append(receipts, &types.Receipt{Type: types.LegacyTxType, Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21_000 * uint64(j+1),
+				Logs: []*types.Log{{Address: libcommon.Address{2}, Topics: []libcommon.Hash{{3}}, Data: []byte{4}}}})
+		}
+		var uncles []*types.Header
+		if i%4 == 1 {
+			uncles = append(uncles, &types.Header{Number: new(big.Int).SetUint64(num - 1), Difficulty: big.NewInt(1)})
+		}
+		for _, r := range receipts {
+			r.Bloom = types.CreateBloom(types.Receipts{r})
+		}
+		block := types.NewBlock(header, txs, uncles, receipts, nil, nil)
+		td = new(big.Int).Add(td, header.Difficulty)
+		res = append(res, testBlock{block: block, receipts: receipts, td: td})
+		parent = block.Hash()
+	}
+	return res
+}
+
+func writeEra1(t *testing.T, blocks []testBlock) ([]byte, libcommon.Hash) {
+	t.Helper()
+	var buf bytes.Buffer
+	b := NewBuilder(&buf)
+	for _, tb := range blocks {
+		require.NoError(t, b.Add(tb.block, tb.receipts, tb.td))
+	}
+	root, err := b.Finalize()
+	require.NoError(t, err)
+	return buf.Bytes(), root
+}
+
+func TestRoundTrip(t *testing.T) {
+	blocks := testChain(t, 8192, 20)
+	data, root := writeEra1(t, blocks)
+
+	r, err := NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	require.Equal(t, uint64(8192), r.Start())
+	require.Equal(t, uint64(20), r.Count())
+
+	stored, err := r.Accumulator()
+	require.NoError(t, err)
+	require.Equal(t, root, stored)
+	verified, err := r.VerifyAccumulator()
+	require.NoError(t, err)
+	require.Equal(t, root, verified)
+
+	for _, tb := range blocks {
+		e, err := r.Entry(tb.block.NumberU64())
+		require.NoError(t, err)
+		require.NoError(t, e.Verify())
+		require.Equal(t, tb.block.Hash(), e.Header.Hash())
+		require.Equal(t, tb.td, e.TotalDifficulty)
+		require.Equal(t, len(tb.block.Transactions()), len(e.Body.Transactions))
+		for i, txn := range tb.block.Transactions() {
+			require.Equal(t, txn.Hash(), e.Body.Transactions[i].Hash())
+		}
+		require.Equal(t, len(tb.block.Uncles()), len(e.Body.Uncles))
+	}
+
+	_, err = r.Entry(8192 + 20)
+	require.Error(t, err)
+}
+
+func TestAccumulatorMismatch(t *testing.T) {
+	blocks := testChain(t, 0, 5)
+	blocks[3].td = new(big.Int).Add(blocks[3].td, big.NewInt(1))
+	data, root := writeEra1(t, blocks)
+
+	// accumulator of other total difficulties
+	blocks[3].td = new(big.Int).Sub(blocks[3].td, big.NewInt(1))
+	_, otherRoot := writeEra1(t, blocks)
+	require.NotEqual(t, root, otherRoot)
+	copy(data[bytes.Index(data, root[:]):], otherRoot[:])
+
+	r, err := NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	_, err = r.VerifyAccumulator()
+	require.ErrorContains(t, err, "accumulator mismatch")
+}
+
+func TestEntryVerify(t *testing.T) {
+	blocks := testChain(t, 0, 3)
+	blocks[2].receipts[0].Status = types.ReceiptStatusFailed
+	data, _ := writeEra1(t, blocks)
+
+	r, err := NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	e, err := r.Entry(1)
+	require.NoError(t, err)
+	require.NoError(t, e.Verify())
+	e, err = r.Entry(2)
+	require.NoError(t, err)
+	require.ErrorContains(t, e.Verify(), "receipts root")
+}
+
+func TestFilename(t *testing.T) {
+	root := libcommon.HexToHash("0x5ec1ffb8c3b146f42606c74ced973dc16ec5a107c0345858c343fc94780b4218")
+	name := Filename(networkname.MainnetChainName, 0, root)
+	require.Equal(t, "mainnet-00000-5ec1ffb8.era1", name)
+
+	network, epoch, shortRoot, err := ParseFilename("/data/era1/" + name)
+	require.NoError(t, err)
+	require.Equal(t, networkname.MainnetChainName, network)
+	require.Equal(t, uint64(0), epoch)
+	require.Equal(t, "5ec1ffb8", shortRoot)
+
+	_, _, _, err = ParseFilename("mainnet-00000-5ec1ffb8.era")
+	require.Error(t, err)
+}
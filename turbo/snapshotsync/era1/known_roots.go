@@ -0,0 +1,43 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package era1
+
+import (
+	"fmt"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+// KnownRoots - accumulator roots of all pre-merge epochs of a network, index is epoch number.
+// The accumulator in a file and the file name are produced by whoever built the file, so for networks
+// listed here the root is also checked against the list (as geth does), to reject self-consistent
+// files of a different history. Lists must have the roots published along with the reference era1 files
+// of the network. Files of networks not listed are imported only if the user explicitly trusts their roots.
+var KnownRoots = map[string][]libcommon.Hash{}
+
+// KnownRoot - accumulator root of the epoch, ok=false if there is no list for the network.
+// Networks with a list have all of their pre-merge epochs in it, other epochs are an error.
+func KnownRoot(network string, epoch uint64) (root libcommon.Hash, ok bool, err error) {
+	roots, ok := KnownRoots[network]
+	if !ok {
+		return libcommon.Hash{}, false, nil
+	}
+	if epoch >= uint64(len(roots)) {
+		return libcommon.Hash{}, true, fmt.Errorf("era1: epoch %d is not a pre-merge epoch of %s", epoch, network)
+	}
+	return roots[epoch], true, nil
+}
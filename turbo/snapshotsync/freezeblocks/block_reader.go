@@ -120,13 +120,15 @@ func (r *RemoteBlockReader) HeaderByNumber(ctx context.Context, tx kv.Getter, bl
 	}
 	return block.Header(), nil
 }
-func (r *RemoteBlockReader) Snapshots() services.BlockSnapshots    { panic("not implemented") }
-func (r *RemoteBlockReader) BorSnapshots() services.BlockSnapshots { panic("not implemented") }
-func (r *RemoteBlockReader) AllTypes() []snaptype.Type             { panic("not implemented") }
-func (r *RemoteBlockReader) FrozenBlocks() uint64                  { panic("not supported") }
-func (r *RemoteBlockReader) FrozenBorBlocks() uint64               { panic("not supported") }
-func (r *RemoteBlockReader) FrozenFiles() (list []string)          { panic("not supported") }
-func (r *RemoteBlockReader) FreezingCfg() ethconfig.BlocksFreezing { panic("not supported") }
+func (r *RemoteBlockReader) Snapshots() services.BlockSnapshots      { panic("not implemented") }
+func (r *RemoteBlockReader) BorSnapshots() services.BlockSnapshots   { panic("not implemented") }
+func (r *RemoteBlockReader) TraceSnapshots() services.BlockSnapshots { panic("not implemented") }
+func (r *RemoteBlockReader) AllTypes() []snaptype.Type               { panic("not implemented") }
+func (r *RemoteBlockReader) FrozenBlocks() uint64                    { panic("not supported") }
+func (r *RemoteBlockReader) FrozenBorBlocks() uint64                 { panic("not supported") }
+func (r *RemoteBlockReader) FrozenTraceResults() uint64              { return 0 }
+func (r *RemoteBlockReader) FrozenFiles() (list []string)            { panic("not supported") }
+func (r *RemoteBlockReader) FreezingCfg() ethconfig.BlocksFreezing   { panic("not supported") }
 
 func (r *RemoteBlockReader) HeaderByHash(ctx context.Context, tx kv.Getter, hash common.Hash) (*types.Header, error) {
 	blockNum := rawdb.ReadHeaderNumber(tx, hash)
@@ -150,6 +152,15 @@ func NewRemoteBlockReader(client remote.ETHBACKENDClient) *RemoteBlockReader {
 	return &RemoteBlockReader{client}
 }
 
+// TraceResults - remote reader sees only trace results which are not moved to files yet
+func (r *RemoteBlockReader) TraceResults(ctx context.Context, tx kv.Getter, blockNum uint64) ([]byte, bool, error) {
+	v, err := rawdb.ReadTraceResults(tx, blockNum)
+	if err != nil {
+		return nil, false, err
+	}
+	return v, v != nil, nil
+}
+
 func (r *RemoteBlockReader) TxnLookup(ctx context.Context, tx kv.Getter, txnHash common.Hash) (uint64, bool, error) {
 	reply, err := r.client.TxnLookup(ctx, &remote.TxnLookupRequest{TxnHash: gointerfaces.ConvertHashToH256(txnHash)})
 	if err != nil {
@@ -319,8 +330,9 @@ func (r *RemoteBlockReader) Checkpoint(ctx context.Context, tx kv.Getter, spanId
 
 // BlockReader can read blocks from db and snapshots
 type BlockReader struct {
-	sn    *RoSnapshots
-	borSn *BorRoSnapshots
+	sn      *RoSnapshots
+	borSn   *BorRoSnapshots
+	traceSn *TraceRoSnapshots
 }
 
 func NewBlockReader(snapshots services.BlockSnapshots, borSnapshots services.BlockSnapshots) *BlockReader {
@@ -329,6 +341,12 @@ func NewBlockReader(snapshots services.BlockSnapshots, borSnapshots services.Blo
 	return &BlockReader{sn: sn, borSn: borSn}
 }
 
+// WithTraceSnapshots - makes files of the optional TraceResults stage readable by TraceResults
+func (r *BlockReader) WithTraceSnapshots(traceSnapshots *TraceRoSnapshots) *BlockReader {
+	r.traceSn = traceSnapshots
+	return r
+}
+
 func (r *BlockReader) CanPruneTo(currentBlockInDB uint64) uint64 {
 	return CanDeleteTo(currentBlockInDB, r.sn.BlocksAvailable())
 }
@@ -357,6 +375,18 @@ func (r *BlockReader) FrozenBorBlocks() uint64 {
 	}
 	return 0
 }
+func (r *BlockReader) FrozenTraceResults() uint64 {
+	if r.traceSn != nil {
+		return r.traceSn.BlocksAvailable()
+	}
+	return 0
+}
+func (r *BlockReader) TraceSnapshots() services.BlockSnapshots {
+	if r.traceSn != nil {
+		return r.traceSn
+	}
+	return nil
+}
 func (r *BlockReader) FrozenFiles() []string {
 	files := r.sn.Files()
 	if r.borSn != nil {
@@ -541,6 +571,9 @@ func (r *BlockReader) BodyWithTransactions(ctx context.Context, tx kv.Getter, ha
 	}
 	txnSeg, ok := view.TxsSegment(blockHeight)
 	if !ok {
+		if err := r.historyPrunedErr(blockHeight); err != nil {
+			return nil, err
+		}
 		if dbgLogs {
 			log.Info(dbgPrefix+"no transactions file for this block num", "r.sn.BlocksAvailable()", r.sn.BlocksAvailable(), "r.sn.idxMax", r.sn.idxMax.Load(), "r.sn.segmetntsMax", r.sn.segmentsMax.Load())
 		}
@@ -711,6 +744,9 @@ func (r *BlockReader) blockWithSenders(ctx context.Context, tx kv.Getter, hash c
 
 	txnSeg, ok := view.TxsSegment(blockHeight)
 	if !ok {
+		if err = r.historyPrunedErr(blockHeight); err != nil {
+			return nil, nil, err
+		}
 		if dbgLogs {
 			log.Info(dbgPrefix+"no transactions file for this block num", "r.sn.BlocksAvailable()", r.sn.BlocksAvailable(), "r.sn.indicesReady", r.sn.indicesReady.Load())
 		}
@@ -732,6 +768,26 @@ func (r *BlockReader) blockWithSenders(ctx context.Context, tx kv.Getter, hash c
 	return block, senders, nil
 }
 
+// HistoryPrunedError - transactions of requested block are out of `--snap.history.window`: their files were deleted
+type HistoryPrunedError struct {
+	BlockNum uint64
+	PrunedTo uint64 // first block which still has transactions
+}
+
+func (e *HistoryPrunedError) Error() string {
+	return fmt.Sprintf("history pruned: transactions of block %d are not available, node keeps them from block %d", e.BlockNum, e.PrunedTo)
+}
+
+// ErrorCode - JSON-RPC error code. Same as other clients return for pruned history (EIP-4444)
+func (e *HistoryPrunedError) ErrorCode() int { return 4444 }
+
+func (r *BlockReader) historyPrunedErr(blockNum uint64) error {
+	if prunedTo := r.sn.HistoryPrunedTo(); blockNum < prunedTo {
+		return &HistoryPrunedError{BlockNum: blockNum, PrunedTo: prunedTo}
+	}
+	return nil
+}
+
 func (r *BlockReader) headerFromSnapshot(blockHeight uint64, sn *Segment, buf []byte) (*types.Header, []byte, error) {
 	index := sn.Index()
 
@@ -998,7 +1054,7 @@ func (r *BlockReader) TxnByIdxInBlock(ctx context.Context, tx kv.Getter, blockNu
 
 	txnSeg, ok := view.TxsSegment(blockNum)
 	if !ok {
-		return
+		return nil, r.historyPrunedErr(blockNum)
 	}
 	// +1 because block has system-txn in the beginning of block
 	return r.txnByID(b.BaseTxnID.At(txIdxInBlock), txnSeg, nil)
@@ -1070,14 +1126,19 @@ func (r *BlockReader) IntegrityTxnID(failFast bool) error {
 	defer view.Close()
 
 	var expectedFirstTxnID uint64
+	checkFirstTxnID := true
 	for _, snb := range view.Bodies() {
 		firstBlockNum := snb.Index().BaseDataID()
-		sn, _ := view.TxsSegment(firstBlockNum)
+		sn, ok := view.TxsSegment(firstBlockNum)
+		if !ok && firstBlockNum < r.sn.HistoryPrunedTo() {
+			checkFirstTxnID = false // nothing to compare with: transactions of previous blocks were pruned
+			continue
+		}
 		b, _, err := r.bodyForStorageFromSnapshot(firstBlockNum, snb, nil)
 		if err != nil {
 			return err
 		}
-		if b.BaseTxnID.U64() != expectedFirstTxnID {
+		if checkFirstTxnID && b.BaseTxnID.U64() != expectedFirstTxnID {
 			err := fmt.Errorf("[integrity] IntegrityTxnID: bn=%d, baseID=%d, cnt=%d, expectedFirstTxnID=%d", firstBlockNum, b.BaseTxnID, sn.Count(), expectedFirstTxnID)
 			if failFast {
 				return err
@@ -1086,6 +1147,7 @@ func (r *BlockReader) IntegrityTxnID(failFast bool) error {
 			}
 		}
 		expectedFirstTxnID = b.BaseTxnID.LastSystemTx(uint32(sn.Count())) + 1 // +1 to move to first baseTxId of next block aka its first system tx
+		checkFirstTxnID = true
 	}
 	return nil
 }
@@ -23,6 +23,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -287,6 +288,8 @@ type RoSnapshots struct {
 
 	// allows for pruning segments - this is the min availible segment
 	segmentsMin atomic.Uint64
+	// transactions files of blocks below this number were pruned - see historyPrunedTo
+	historyPrunedTo atomic.Uint64
 }
 
 // NewRoSnapshots - opens all snapshots. But to simplify everything:
@@ -318,6 +321,7 @@ func (s *RoSnapshots) IndicesMax() uint64            { return s.idxMax.Load() }
 func (s *RoSnapshots) SegmentsMax() uint64           { return s.segmentsMax.Load() }
 func (s *RoSnapshots) SegmentsMin() uint64           { return s.segmentsMin.Load() }
 func (s *RoSnapshots) SetSegmentsMin(min uint64)     { s.segmentsMin.Store(min) }
+func (s *RoSnapshots) HistoryPrunedTo() uint64       { return s.historyPrunedTo.Load() }
 func (s *RoSnapshots) BlocksAvailable() uint64 {
 	if s == nil {
 		return 0
@@ -533,6 +537,7 @@ func (s *RoSnapshots) rebuildSegments(fileNames []string, open bool, optimistic
 	s.closeWhatNotInList(fileNames)
 	var segmentsMax uint64
 	var segmentsMaxSet bool
+	var opened []snaptype.FileInfo
 
 	for _, fName := range fileNames {
 		f, isState, ok := snaptype.ParseFileName(s.dir, fName)
@@ -603,11 +608,13 @@ func (s *RoSnapshots) rebuildSegments(fileNames []string, open bool, optimistic
 			segmentsMax = 0
 		}
 		segmentsMaxSet = true
+		opened = append(opened, f)
 	}
 
 	if segmentsMaxSet {
 		s.segmentsMax.Store(segmentsMax)
 	}
+	s.historyPrunedTo.Store(historyPrunedTo(opened))
 	s.segmentsReady.Store(true)
 	s.idxMax.Store(s.idxAvailability())
 	s.indicesReady.Store(true)
@@ -1016,13 +1023,18 @@ func noGaps(in []snaptype.FileInfo) (out []snaptype.FileInfo, missingSnapshots [
 	return out, missingSnapshots
 }
 
-func typeOfSegmentsMustExist(dir string, in []snaptype.FileInfo, types []snaptype.Type) (res []snaptype.FileInfo) {
+// typeOfSegmentsMustExist - keeps only ranges which have files of all types.
+// Exception: ranges below historyPrunedTo have no transactions files (see historyPrunedTo).
+func typeOfSegmentsMustExist(dir string, in []snaptype.FileInfo, types []snaptype.Type, prunedTo uint64) (res []snaptype.FileInfo) {
 MainLoop:
 	for _, f := range in {
 		if f.From == f.To {
 			continue
 		}
 		for _, t := range types {
+			if t.Enum() == coresnaptype.Enums.Transactions && f.To <= prunedTo {
+				continue
+			}
 			p := filepath.Join(dir, snaptype.SegmentFileName(f.Version, f.From, f.To, t.Enum()))
 			exists, err := dir2.FileExist(p)
 			if err != nil {
@@ -1143,16 +1155,17 @@ func Segments(dir string, minBlock uint64) (res []snaptype.FileInfo, missingSnap
 }
 
 func typedSegments(dir string, minBlock uint64, types []snaptype.Type, allowGaps bool) (res []snaptype.FileInfo, missingSnapshots []Range, err error) {
-	segmentsTypeCheck := func(dir string, in []snaptype.FileInfo) (res []snaptype.FileInfo) {
-		return typeOfSegmentsMustExist(dir, in, types)
-	}
-
 	list, err := snaptype.Segments(dir)
 
 	if err != nil {
 		return nil, missingSnapshots, err
 	}
 
+	prunedTo := historyPrunedTo(list)
+	segmentsTypeCheck := func(dir string, in []snaptype.FileInfo) (res []snaptype.FileInfo) {
+		return typeOfSegmentsMustExist(dir, in, types, prunedTo)
+	}
+
 	for _, segType := range types {
 		{
 			var l []snaptype.FileInfo
@@ -1185,6 +1198,27 @@ func typedSegments(dir string, minBlock uint64, types []snaptype.Type, allowGaps
 	return res, missingSnapshots, nil
 }
 
+// historyPrunedTo - transactions of blocks below returned number were pruned (by `--snap.history.window` or `--prune`):
+// headers and bodies files start from block 0, but transactions files start from returned block.
+func historyPrunedTo(list []snaptype.FileInfo) uint64 {
+	var headersFrom, txsFrom uint64 = math.MaxUint64, math.MaxUint64
+	for _, f := range list {
+		if f.From == f.To {
+			continue
+		}
+		switch f.Type.Enum() {
+		case coresnaptype.Enums.Headers:
+			headersFrom = min(headersFrom, f.From)
+		case coresnaptype.Enums.Transactions:
+			txsFrom = min(txsFrom, f.From)
+		}
+	}
+	if headersFrom == math.MaxUint64 || txsFrom == math.MaxUint64 || txsFrom <= headersFrom {
+		return 0
+	}
+	return txsFrom
+}
+
 func chooseSegmentEnd(from, to uint64, snapType snaptype.Enum, chainConfig *chain.Config) uint64 {
 	var chainName string
 
@@ -1514,7 +1548,7 @@ func (br *BlockRetire) RetireBlocks(ctx context.Context, requestedMinBlockNum ui
 
 	var err error
 	for {
-		var ok, okBor bool
+		var ok, okBor, okTrace bool
 		minBlockNum := max(br.blockReader.FrozenBlocks(), requestedMinBlockNum)
 		maxBlockNum := br.maxScheduledBlock.Load()
 		ok, err = br.retireBlocks(ctx, minBlockNum, maxBlockNum, lvl, seedNewSnapshots, onDeleteSnapshots)
@@ -1529,13 +1563,19 @@ func (br *BlockRetire) RetireBlocks(ctx context.Context, requestedMinBlockNum ui
 				return err
 			}
 		}
+
+		// trace results of the optional TraceResults stage don't depend on block files: they start from genesis
+		okTrace, err = br.retireTraceResults(ctx, br.blockReader.FrozenTraceResults(), maxBlockNum, lvl, seedNewSnapshots, onDeleteSnapshots)
+		if err != nil {
+			return err
+		}
 		if onFinish != nil {
 			if err := onFinish(); err != nil {
 				return err
 			}
 		}
 
-		if !(ok || okBor) {
+		if !(ok || okBor || okTrace) {
 			break
 		}
 	}
@@ -1553,6 +1593,12 @@ func (br *BlockRetire) BuildMissedIndicesIfNeed(ctx context.Context, logPrefix s
 		}
 	}
 
+	if traceSnapshots := br.traceSnapshots(); traceSnapshots != nil {
+		if err := traceSnapshots.RoSnapshots.buildMissedIndicesIfNeed(ctx, logPrefix, notifier, br.dirs, cc, br.logger); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -1602,6 +1648,7 @@ func dumpRange(ctx context.Context, f snaptype.FileInfo, dumper dumpFunc, firstK
 		return lastKeyValue, err
 	}
 	defer sn.Close()
+	sn.SetCodec(f.Type.Codec())
 
 	lastKeyValue, err = dumper(ctx, chainDB, chainConfig, f.From, f.To, firstKey, func(v []byte) error {
 		return sn.AddWord(v)
@@ -2075,7 +2122,7 @@ func (m *Merger) mergeSubSegment(ctx context.Context, sn snaptype.FileInfo, toMe
 	if len(toMerge) == 0 {
 		return
 	}
-	if err = m.merge(ctx, toMerge, sn.Path, nil); err != nil {
+	if err = m.merge(ctx, toMerge, sn.Path, sn.Type.Codec(), nil); err != nil {
 		err = fmt.Errorf("mergeByAppendSegments: %w", err)
 		return
 	}
@@ -2136,7 +2183,7 @@ func (m *Merger) Merge(ctx context.Context, snapshots *RoSnapshots, snapTypes []
 	return nil
 }
 
-func (m *Merger) merge(ctx context.Context, toMerge []string, targetFile string, logEvery *time.Ticker) error {
+func (m *Merger) merge(ctx context.Context, toMerge []string, targetFile string, codec seg.Codec, logEvery *time.Ticker) error {
 	var word = make([]byte, 0, 4096)
 	var expectedTotal int
 	cList := make([]*seg.Decompressor, len(toMerge))
@@ -2155,6 +2202,7 @@ func (m *Merger) merge(ctx context.Context, toMerge []string, targetFile string,
 		return err
 	}
 	defer f.Close()
+	f.SetCodec(codec)
 	if m.noFsync {
 		f.DisableFsync()
 	}
@@ -259,6 +259,59 @@ func TestDeleteSnapshots(t *testing.T) {
 	}
 }
 
+func TestHistoryPrunedSnapshots(t *testing.T) {
+	logger := log.New()
+	dir, require := t.TempDir(), require.New(t)
+	createFile := func(from, to uint64, types []snaptype.Type) {
+		for _, snT := range types {
+			createTestSegmentFile(t, from, to, snT.Enum(), dir, 1, logger)
+		}
+	}
+	createFile(0, 500_000, []snaptype.Type{coresnaptype.Headers, coresnaptype.Bodies})
+	createFile(500_000, 1_000_000, []snaptype.Type{coresnaptype.Headers, coresnaptype.Bodies})
+	createFile(1_000_000, 1_100_000, coresnaptype.BlockSnapshotTypes)
+
+	s := NewRoSnapshots(ethconfig.BlocksFreezing{Enabled: true}, dir, 0, logger)
+	defer s.Close()
+	require.NoError(s.ReopenFolder())
+	require.Equal(1_000_000, int(s.HistoryPrunedTo()))
+	require.Equal(1_100_000-1, int(s.SegmentsMax()))
+
+	view := s.View()
+	_, ok := view.HeadersSegment(10)
+	require.True(ok)
+	_, ok = view.BodiesSegment(600_000)
+	require.True(ok)
+	_, ok = view.TxsSegment(600_000)
+	require.False(ok)
+	view.Close()
+
+	r := NewBlockReader(s, nil)
+	var prunedErr *HistoryPrunedError
+	require.ErrorAs(r.historyPrunedErr(600_000), &prunedErr)
+	require.Equal(4444, prunedErr.ErrorCode())
+	require.Equal(1_000_000, int(prunedErr.PrunedTo))
+	require.NoError(r.historyPrunedErr(1_000_000))
+
+	// gap in transactions files above pruned history is not a pruned history
+	createFile(1_100_000, 1_200_000, []snaptype.Type{coresnaptype.Headers, coresnaptype.Bodies})
+	createFile(1_200_000, 1_300_000, coresnaptype.BlockSnapshotTypes)
+	require.NoError(s.ReopenFolder())
+	require.Equal(1_000_000, int(s.HistoryPrunedTo()))
+	require.Equal(1_100_000-1, int(s.SegmentsMax()))
+}
+
+func TestHistoryPrunedTo(t *testing.T) {
+	require := require.New(t)
+	cfg := ethconfig.BlocksFreezing{Enabled: true}
+	require.Zero(cfg.HistoryPrunedTo(20_000_000))
+	cfg.HistoryWindow = 1_000_000
+	require.Equal(19_000_000, int(cfg.HistoryPrunedTo(20_000_000)))
+	require.Zero(cfg.HistoryPrunedTo(500_000))
+	cfg.HistoryWindow = 1
+	require.Equal(20_000_000-snaptype.Erigon2MergeLimit, int(cfg.HistoryPrunedTo(20_000_000)))
+}
+
 func TestRemoveOverlaps(t *testing.T) {
 	logger := log.New()
 	dir, require := t.TempDir(), require.New(t)
@@ -419,6 +419,7 @@ func dumpBeaconBlocksRange(ctx context.Context, db kv.RoDB, fromSlot uint64, toS
 		return err
 	}
 	defer sn.Close()
+	sn.SetCodec(snaptype.BeaconBlocks.Codec())
 
 	tx, err := db.BeginRo(ctx)
 	if err != nil {
@@ -465,6 +466,7 @@ func dumpBlobSidecarsRange(ctx context.Context, db kv.RoDB, storage blob_storage
 		return err
 	}
 	defer sn.Close()
+	sn.SetCodec(snaptype.BlobSidecars.Codec())
 
 	tx, err := db.BeginRo(ctx)
 	if err != nil {
@@ -0,0 +1,438 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package freezeblocks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	common2 "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/background"
+	"github.com/ledgerwatch/erigon-lib/downloader/snaptype"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+	"github.com/ledgerwatch/erigon-lib/seg"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	coresnaptype "github.com/ledgerwatch/erigon/core/snaptype"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/turbo/services"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/era1"
+)
+
+// ImportEra1 - builds headers, bodies and transactions segments from era1 files, continuing the chain
+// of parent (from genesis if parent is nil). Every file is verified against its accumulator root, and the root
+// against the list of known roots of the network (see era1.KnownRoots), before any of its blocks is used, and every block against its header, so only verified blocks get into segments.
+// Files of a network without the list are refused, unless trustFileRoots: then they are verified only against their own accumulator.
+// Only whole segments are built, blocks after the last one are left for the regular sync.
+// Returns the end of imported blocks range (exclusive) and the first txNum after it.
+func ImportEra1(ctx context.Context, files []string, parent *types.Header, parentTD *big.Int, firstTxNum uint64, chainConfig *chain.Config, trustFileRoots bool, snapDir, tmpDir string, workers int, lvl log.Lvl, logger log.Logger) (blockTo, lastTxNum uint64, err error) {
+	blocks, err := openEra1Blocks(files, parent, parentTD, chainConfig, trustFileRoots, logger)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer blocks.Close()
+
+	blockFrom, end := blocks.next, blocks.end()
+	lastTxNum = firstTxNum
+	for ; ; blockFrom = blockTo {
+		blockTo = chooseSegmentEnd(blockFrom, end, coresnaptype.Enums.Headers, chainConfig)
+		if blockTo <= blockFrom {
+			blockTo = blockFrom
+			break
+		}
+		if lastTxNum, err = importEra1Range(ctx, blocks, blockFrom, blockTo, lastTxNum, chainConfig, snapDir, tmpDir, workers, lvl, logger); err != nil {
+			return blockFrom, lastTxNum, err
+		}
+	}
+	if blockTo < end {
+		logger.Info("[era1] Blocks not filling whole segment are left for sync", "from", blockTo, "to", end)
+	}
+	return blockTo, lastTxNum, nil
+}
+
+func importEra1Range(ctx context.Context, blocks *era1Blocks, blockFrom, blockTo, firstTxNum uint64, chainConfig *chain.Config, snapDir, tmpDir string, workers int, lvl log.Lvl, logger log.Logger) (lastTxNum uint64, err error) {
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+
+	segments := []snaptype.FileInfo{
+		coresnaptype.Headers.FileInfo(snapDir, blockFrom, blockTo),
+		coresnaptype.Bodies.FileInfo(snapDir, blockFrom, blockTo),
+		coresnaptype.Transactions.FileInfo(snapDir, blockFrom, blockTo),
+	}
+	compressors := make([]*seg.Compressor, len(segments))
+	for i, f := range segments {
+		if compressors[i], err = seg.NewCompressor(ctx, "Snapshot "+f.Type.Name(), f.Path, tmpDir, seg.MinPatternScore, workers, log.LvlTrace, logger); err != nil {
+			return firstTxNum, err
+		}
+		defer compressors[i].Close()
+		compressors[i].SetCodec(f.Type.Codec())
+	}
+	headers, bodies, txs := compressors[0], compressors[1], compressors[2]
+
+	lastTxNum = firstTxNum
+	var txBuf bytes.Buffer
+	for blockNum := blockFrom; blockNum < blockTo; blockNum++ {
+		e, err := blocks.Entry(blockNum)
+		if err != nil {
+			return lastTxNum, err
+		}
+
+		headerRLP, err := rlp.EncodeToBytes(e.Header)
+		if err != nil {
+			return lastTxNum, err
+		}
+		if err := headers.AddWord(append([]byte{e.Header.Hash()[0]}, headerRLP...)); err != nil {
+			return lastTxNum, err
+		}
+
+		body := types.BodyForStorage{BaseTxnID: types.BaseTxnID(lastTxNum), TxCount: types.TxCountToTxAmount(len(e.Body.Transactions)), Uncles: e.Body.Uncles}
+		lastTxNum = body.BaseTxnID.LastSystemTx(body.TxCount) + 1 // +1 to set it on first systemTxn of next block
+		bodyRLP, err := rlp.EncodeToBytes(body)
+		if err != nil {
+			return lastTxNum, err
+		}
+		if err := bodies.AddWord(bodyRLP); err != nil {
+			return lastTxNum, err
+		}
+
+		// first tx byte => sender address => tx rlp, surrounded by empty system txs
+		if err := txs.AddWord(nil); err != nil {
+			return lastTxNum, err
+		}
+		signer := types.MakeSigner(chainConfig, blockNum, e.Header.Time)
+		for _, txn := range e.Body.Transactions {
+			sender, err := signer.Sender(txn)
+			if err != nil {
+				return lastTxNum, fmt.Errorf("era1: block %d: recover sender of %x: %w", blockNum, txn.Hash(), err)
+			}
+			txBuf.Reset()
+			txBuf.WriteByte(txn.Hash()[0])
+			txBuf.Write(sender[:])
+			if err := txn.MarshalBinary(&txBuf); err != nil {
+				return lastTxNum, err
+			}
+			if err := txs.AddWord(txBuf.Bytes()); err != nil {
+				return lastTxNum, err
+			}
+		}
+		if err := txs.AddWord(nil); err != nil {
+			return lastTxNum, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return lastTxNum, ctx.Err()
+		case <-logEvery.C:
+			logger.Log(lvl, "[era1] Importing blocks", "block num", blockNum, "to", blockTo)
+		default:
+		}
+	}
+
+	for i, f := range segments {
+		logger.Log(lvl, "[snapshots] Compression start", "file", f.Name(), "workers", compressors[i].Workers())
+		if err := compressors[i].Compress(); err != nil {
+			return lastTxNum, fmt.Errorf("compress: %w", err)
+		}
+	}
+	for _, f := range segments {
+		if err := f.Type.BuildIndexes(ctx, f, chainConfig, tmpDir, &background.Progress{}, lvl, logger); err != nil {
+			return lastTxNum, err
+		}
+	}
+	return lastTxNum, nil
+}
+
+// era1Blocks - blocks of contiguous era1 files in order, verifying accumulator of file on first access to it
+type era1Blocks struct {
+	files       []*era1.File
+	names       []string
+	verified    int // files[:verified] have verified accumulator
+	chainConfig *chain.Config
+	trustRoots  bool // accept files of networks without known roots
+
+	next     uint64
+	parent   *types.Header
+	parentTD *big.Int
+}
+
+func openEra1Blocks(paths []string, parent *types.Header, parentTD *big.Int, chainConfig *chain.Config, trustRoots bool, logger log.Logger) (_ *era1Blocks, err error) {
+	blocks := &era1Blocks{chainConfig: chainConfig, trustRoots: trustRoots, parent: parent, parentTD: parentTD}
+	defer func() {
+		if err != nil {
+			blocks.Close()
+		}
+	}()
+	if parent != nil {
+		blocks.next = parent.Number.Uint64() + 1
+	}
+
+	type opened struct {
+		f    *era1.File
+		name string
+	}
+	all := make([]opened, 0, len(paths))
+	for _, path := range paths {
+		f, err := era1.Open(path)
+		if err != nil {
+			for _, o := range all {
+				o.f.Close()
+			}
+			return nil, err
+		}
+		all = append(all, opened{f, path})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].f.Start() < all[j].f.Start() })
+	for _, o := range all {
+		if o.f.Start()+o.f.Count() <= blocks.next {
+			logger.Info("[era1] Skip file with blocks already in snapshots", "file", filepath.Base(o.name))
+			o.f.Close()
+			continue
+		}
+		if len(blocks.files) == 0 && o.f.Start() > blocks.next {
+			o.f.Close()
+			return nil, fmt.Errorf("era1: files start at block %d, but next block to import is %d", o.f.Start(), blocks.next)
+		}
+		if n := len(blocks.files); n > 0 && blocks.files[n-1].Start()+blocks.files[n-1].Count() != o.f.Start() {
+			o.f.Close()
+			return nil, fmt.Errorf("era1: gap or overlap before %s", o.name)
+		}
+		blocks.files, blocks.names = append(blocks.files, o.f), append(blocks.names, o.name)
+	}
+	if len(blocks.files) == 0 {
+		return nil, fmt.Errorf("era1: no files with blocks after %d", blocks.next)
+	}
+	return blocks, nil
+}
+
+func (b *era1Blocks) end() uint64 {
+	last := b.files[len(b.files)-1]
+	return last.Start() + last.Count()
+}
+
+// Entry - verified block, must be called for every block in order
+func (b *era1Blocks) Entry(blockNum uint64) (*era1.Entry, error) {
+	if blockNum != b.next {
+		return nil, fmt.Errorf("era1: block %d requested, next is %d", blockNum, b.next)
+	}
+	i := sort.Search(len(b.files), func(i int) bool { return b.files[i].Start()+b.files[i].Count() > blockNum })
+	if i == len(b.files) {
+		return nil, fmt.Errorf("era1: no file with block %d", blockNum)
+	}
+	for ; b.verified <= i; b.verified++ {
+		if err := b.verify(b.verified); err != nil {
+			return nil, err
+		}
+	}
+
+	e, err := b.files[i].Entry(blockNum)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.Verify(); err != nil {
+		return nil, err
+	}
+	if e.Header.Difficulty.Sign() == 0 {
+		return nil, fmt.Errorf("era1: block %d is post-merge", blockNum)
+	}
+	if b.parent == nil {
+		if genesis := params.GenesisHashByChainName(b.chainConfig.ChainName); genesis != nil && *genesis != e.Header.Hash() {
+			return nil, fmt.Errorf("era1: genesis %x, expected %x", e.Header.Hash(), *genesis)
+		}
+	} else if e.Header.ParentHash != b.parent.Hash() {
+		return nil, fmt.Errorf("era1: block %d has parent %x, expected %x", blockNum, e.Header.ParentHash, b.parent.Hash())
+	}
+	expectedTD := new(big.Int).Set(e.Header.Difficulty)
+	if b.parentTD != nil {
+		expectedTD.Add(expectedTD, b.parentTD)
+	}
+	if (b.parent == nil || b.parentTD != nil) && expectedTD.Cmp(e.TotalDifficulty) != 0 {
+		return nil, fmt.Errorf("era1: block %d has total difficulty %s, expected %s", blockNum, e.TotalDifficulty, expectedTD)
+	}
+	b.next, b.parent, b.parentTD = blockNum+1, e.Header, e.TotalDifficulty
+	return e, nil
+}
+
+func (b *era1Blocks) verify(i int) error {
+	f, name := b.files[i], b.names[i]
+	network, epoch, shortRoot, err := era1.ParseFilename(name)
+	if err != nil {
+		return err
+	}
+	if network != b.chainConfig.ChainName {
+		return fmt.Errorf("era1: %s is of network %s, expected %s", name, network, b.chainConfig.ChainName)
+	}
+	if epoch != f.Start()/era1.MaxEra1Size || f.Start()%era1.MaxEra1Size != 0 {
+		return fmt.Errorf("era1: %s starts at block %d, which is not start of epoch %d", name, f.Start(), epoch)
+	}
+	root, err := f.VerifyAccumulator()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, name)
+	}
+	if fmt.Sprintf("%x", root[:4]) != shortRoot {
+		return fmt.Errorf("era1: %s has accumulator %x", name, root)
+	}
+	known, ok, err := era1.KnownRoot(network, epoch)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, name)
+	}
+	if !ok && !b.trustRoots {
+		return fmt.Errorf("era1: no known accumulator roots of network %s to verify %s against", network, name)
+	}
+	if ok && known != root {
+		return fmt.Errorf("era1: %s has accumulator %x, known accumulator of epoch %d is %x", name, root, epoch, known)
+	}
+	return nil
+}
+
+func (b *era1Blocks) Close() {
+	for _, f := range b.files {
+		f.Close()
+	}
+}
+
+// Era1ReceiptsGetter - consensus receipts of canonical block
+type Era1ReceiptsGetter func(ctx context.Context, tx kv.Tx, block *types.Block, senders []common2.Address) (types.Receipts, error)
+
+// ExportEra1 - writes era1 files of epochs [fromEpoch, toEpoch) into dir, stops at the merge or at the
+// end of available blocks (incomplete epoch is not written). Receipts of every block are checked against
+// its header, it means blocks before Byzantium with transactions can't be exported: their receipts have
+// intermediate state roots, which are not kept.
+func ExportEra1(ctx context.Context, db kv.RoDB, blockReader services.FullBlockReader, getReceipts Era1ReceiptsGetter, chainConfig *chain.Config, dir string, fromEpoch, toEpoch uint64, lvl log.Lvl, logger log.Logger) (written []string, err error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	for epoch := fromEpoch; epoch < toEpoch; epoch++ {
+		var path string
+		var done bool
+		if err := db.View(ctx, func(tx kv.Tx) error {
+			path, done, err = exportEra1Epoch(ctx, tx, blockReader, getReceipts, chainConfig, dir, epoch, lvl, logger)
+			return err
+		}); err != nil {
+			return written, err
+		}
+		if path != "" {
+			written = append(written, path)
+			logger.Log(lvl, "[era1] Exported", "file", filepath.Base(path))
+		}
+		if done {
+			break
+		}
+	}
+	return written, nil
+}
+
+// exportEra1Epoch - returns path of written file, and done=true if there are no blocks for next epochs
+func exportEra1Epoch(ctx context.Context, tx kv.Tx, blockReader services.FullBlockReader, getReceipts Era1ReceiptsGetter, chainConfig *chain.Config, dir string, epoch uint64, lvl log.Lvl, logger log.Logger) (path string, done bool, err error) {
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+
+	tmp, err := os.CreateTemp(dir, "era1-export-*.tmp")
+	if err != nil {
+		return "", false, err
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	builder := era1.NewBuilder(tmp)
+	from := epoch * era1.MaxEra1Size
+	blockNum, merged := from, false
+	for ; blockNum < from+era1.MaxEra1Size; blockNum++ {
+		hash, err := blockReader.CanonicalHash(ctx, tx, blockNum)
+		if err != nil {
+			return "", false, err
+		}
+		if hash == (common2.Hash{}) {
+			done = true
+			break
+		}
+		block, senders, err := blockReader.BlockWithSenders(ctx, tx, hash, blockNum)
+		if err != nil {
+			return "", false, err
+		}
+		if block == nil {
+			return "", false, fmt.Errorf("era1: block %d not found", blockNum)
+		}
+		if block.Difficulty().Sign() == 0 { // first PoS block
+			done, merged = true, true
+			break
+		}
+		td, err := rawdb.ReadTd(tx, hash, blockNum)
+		if err != nil {
+			return "", false, err
+		}
+		if td == nil {
+			return "", false, fmt.Errorf("era1: total difficulty of block %d not found", blockNum)
+		}
+		receipts, err := getReceipts(ctx, tx, block, senders)
+		if err != nil {
+			return "", false, fmt.Errorf("era1: receipts of block %d: %w", blockNum, err)
+		}
+		if root := types.DeriveSha(receipts); root != block.ReceiptHash() {
+			if !chainConfig.IsByzantium(blockNum) {
+				return "", false, fmt.Errorf("era1: receipts of block %d before Byzantium can't be restored (no intermediate state roots), export epochs from %d",
+					blockNum, (chainConfig.ByzantiumBlock.Uint64()+era1.MaxEra1Size-1)/era1.MaxEra1Size)
+			}
+			return "", false, fmt.Errorf("era1: block %d: receipts root %x, header has %x", blockNum, root, block.ReceiptHash())
+		}
+		if err := builder.Add(block, receipts, td); err != nil {
+			return "", false, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", false, ctx.Err()
+		case <-logEvery.C:
+			logger.Log(lvl, "[era1] Exporting blocks", "block num", blockNum, "epoch", epoch)
+		default:
+		}
+	}
+	switch {
+	case blockNum == from:
+		return "", true, nil
+	case blockNum < from+era1.MaxEra1Size && !merged:
+		logger.Info("[era1] Skip incomplete epoch", "epoch", epoch, "blocks", blockNum-from)
+		return "", true, nil
+	}
+
+	root, err := builder.Finalize()
+	if err != nil {
+		return "", false, err
+	}
+	if err := tmp.Sync(); err != nil {
+		return "", false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", false, err
+	}
+	path = filepath.Join(dir, era1.Filename(chainConfig.ChainName, epoch, root))
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", false, err
+	}
+	return path, done, nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package freezeblocks
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/era1"
+	"github.com/ledgerwatch/erigon/turbo/testlog"
+)
+
+// writeTestEra1 - era1 file with chain of n blocks from genesis, every 100th block has a transaction
+func writeTestEra1(t *testing.T, dir string, n int) (string, []*types.Block) {
+	t.Helper()
+	cfg := *params.TestChainConfig
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := types.LatestSignerForChainID(cfg.ChainID)
+
+	f, err := os.CreateTemp(dir, "*.tmp")
+	require.NoError(t, err)
+	defer f.Close()
+	builder := era1.NewBuilder(f)
+
+	var blocks []*types.Block
+	parent, td := libcommon.Hash{}, new(big.Int)
+	for i := 0; i < n; i++ {
+		header := &types.Header{ParentHash: parent, Number: big.NewInt(int64(i)), Difficulty: big.NewInt(131072), GasLimit: 1_000_000, Time: uint64(i)}
+		var txs types.Transactions
+		var receipts types.Receipts
+		if i%100 == 99 {
+			txn, err := types.SignTx(types.NewTransaction(uint64(i/100), libcommon.Address{1}, uint256.NewInt(1), 21_000, uint256.NewInt(1), nil), *signer, key)
+			require.NoError(t, err)
+			txs = append(txs, txn)
+			receipts = append(receipts, &types.Receipt{Type: types.LegacyTxType, Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21_000})
+		}
+		block := types.NewBlock(header, txs, nil, receipts, nil, nil)
+		td.Add(td, header.Difficulty)
+		require.NoError(t, builder.Add(block, receipts, td))
+		blocks = append(blocks, block)
+		parent = block.Hash()
+	}
+	root, err := builder.Finalize()
+	require.NoError(t, err)
+	path := filepath.Join(dir, era1.Filename("era1test", 0, root))
+	require.NoError(t, os.Rename(f.Name(), path))
+	return path, blocks
+}
+
+func TestImportEra1(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	dir, snapDir := t.TempDir(), t.TempDir()
+	path, blocks := writeTestEra1(t, dir, 1200)
+	cfg := *params.TestChainConfig
+	cfg.ChainName = "era1test"
+
+	f, err := era1.Open(path)
+	require.NoError(t, err)
+	root, err := f.Accumulator()
+	require.NoError(t, err)
+	f.Close()
+	era1.KnownRoots[cfg.ChainName] = []libcommon.Hash{root}
+	defer delete(era1.KnownRoots, cfg.ChainName)
+
+	blockTo, lastTxNum, err := ImportEra1(context.Background(), []string{path}, nil, nil, 0, &cfg, false, snapDir, t.TempDir(), 1, log.LvlInfo, logger)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000), blockTo) // remainder doesn't fill segment
+	require.Equal(t, uint64(1000*2+10), lastTxNum)
+
+	s := NewRoSnapshots(ethconfig.BlocksFreezing{Enabled: true}, snapDir, 0, logger)
+	defer s.Close()
+	require.NoError(t, s.ReopenFolder())
+	require.Equal(t, uint64(999), s.BlocksAvailable())
+
+	blockReader := NewBlockReader(s, nil)
+	for _, num := range []uint64{0, 99, 500, 999} {
+		block, senders, err := blockReader.BlockWithSenders(context.Background(), nil, blocks[num].Hash(), num)
+		require.NoError(t, err)
+		require.NotNil(t, block)
+		require.Equal(t, blocks[num].Hash(), block.Hash())
+		require.Equal(t, len(blocks[num].Transactions()), len(block.Transactions()))
+		for i, txn := range block.Transactions() {
+			require.Equal(t, blocks[num].Transactions()[i].Hash(), txn.Hash())
+			sender, err := txn.Sender(*types.LatestSignerForChainID(cfg.ChainID))
+			require.NoError(t, err)
+			require.Equal(t, sender, senders[i])
+		}
+	}
+}
+
+func TestImportEra1Validation(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	dir := t.TempDir()
+	path, blocks := writeTestEra1(t, dir, 1000)
+	cfg := *params.TestChainConfig
+	cfg.ChainName = "era1test"
+	importEra1 := func(path string, parent *types.Header, parentTD *big.Int) error {
+		_, _, err := ImportEra1(context.Background(), []string{path}, parent, parentTD, 0, &cfg, true, t.TempDir(), t.TempDir(), 1, log.LvlInfo, logger)
+		return err
+	}
+
+	t.Run("wrong network", func(t *testing.T) {
+		other := filepath.Join(dir, "other"+filepath.Base(path)[len("era1test"):])
+		require.NoError(t, os.Link(path, other))
+		require.ErrorContains(t, importEra1(other, nil, nil), "is of network")
+	})
+	t.Run("wrong root in file name", func(t *testing.T) {
+		other := filepath.Join(dir, "era1test-00000-00000000.era1")
+		require.NoError(t, os.Link(path, other))
+		require.ErrorContains(t, importEra1(other, nil, nil), "has accumulator")
+	})
+	t.Run("unknown accumulator", func(t *testing.T) {
+		era1.KnownRoots[cfg.ChainName] = []libcommon.Hash{{1}}
+		defer delete(era1.KnownRoots, cfg.ChainName)
+		require.ErrorContains(t, importEra1(path, nil, nil), "known accumulator of epoch 0 is")
+
+		era1.KnownRoots[cfg.ChainName] = nil
+		require.ErrorContains(t, importEra1(path, nil, nil), "epoch 0 is not a pre-merge epoch")
+	})
+	t.Run("no known roots", func(t *testing.T) {
+		_, _, err := ImportEra1(context.Background(), []string{path}, nil, nil, 0, &cfg, false, t.TempDir(), t.TempDir(), 1, log.LvlInfo, logger)
+		require.ErrorContains(t, err, "no known accumulator roots of network era1test")
+	})
+	t.Run("corrupted accumulator", func(t *testing.T) {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		other := filepath.Join(t.TempDir(), filepath.Base(path))
+		data[len(data)-8*1000-16-8-1] ^= 1 // last byte of accumulator root
+		require.NoError(t, os.WriteFile(other, data, 0644))
+		require.ErrorContains(t, importEra1(other, nil, nil), "accumulator mismatch")
+	})
+	t.Run("not continuing snapshots", func(t *testing.T) {
+		require.ErrorContains(t, importEra1(path, &types.Header{Number: big.NewInt(10)}, nil), "has parent")
+		require.ErrorContains(t, importEra1(path, blocks[10].Header(), big.NewInt(1)), "block 11 has total difficulty")
+		require.ErrorContains(t, importEra1(path, blocks[999].Header(), nil), "no files with blocks after 1000")
+	})
+}
@@ -0,0 +1,189 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package freezeblocks
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/log/v3"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	coresnaptype "github.com/ledgerwatch/erigon/core/snaptype"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/turbo/services"
+)
+
+// Trace Results
+// value: snappy(json(trace_block result)) - same as in kv.TraceResults
+// block_num -> trace_results_segment_offset
+
+type TraceRoSnapshots struct {
+	RoSnapshots
+}
+
+// NewTraceRoSnapshots - opens files of the optional TraceResults stage. Same rules as for block snapshots:
+//   - all files of given blocks range must exist - to make this blocks range available
+//   - gaps are not allowed
+//   - segment have [from:to) semantic
+func NewTraceRoSnapshots(cfg ethconfig.BlocksFreezing, snapDir string, segmentsMin uint64, logger log.Logger) *TraceRoSnapshots {
+	return &TraceRoSnapshots{*newRoSnapshots(cfg, snapDir, coresnaptype.TraceSnapshotTypes(), segmentsMin, logger)}
+}
+
+func (s *TraceRoSnapshots) Ranges() []Range {
+	view := s.View()
+	defer view.Close()
+	return view.base.Ranges()
+}
+
+type TraceView struct {
+	base *View
+}
+
+func (s *TraceRoSnapshots) View() *TraceView {
+	v := &TraceView{base: s.RoSnapshots.View()}
+	v.base.baseSegType = coresnaptype.TraceResults
+	return v
+}
+
+func (v *TraceView) Close() {
+	v.base.Close()
+}
+
+func (v *TraceView) TraceResults() []*Segment { return v.base.Segments(coresnaptype.TraceResults) }
+
+func (v *TraceView) TraceResultsSegment(blockNum uint64) (*Segment, bool) {
+	return v.base.Segment(coresnaptype.TraceResults, blockNum)
+}
+
+// TraceResults - returns compressed trace_block result of given block. From db if it's not moved to files yet.
+func (r *BlockReader) TraceResults(ctx context.Context, tx kv.Getter, blockNum uint64) ([]byte, bool, error) {
+	if tx != nil {
+		v, err := rawdb.ReadTraceResults(tx, blockNum)
+		if err != nil {
+			return nil, false, err
+		}
+		if v != nil {
+			return v, true, nil
+		}
+	}
+	if r.traceSn == nil {
+		return nil, false, nil
+	}
+
+	view := r.traceSn.View()
+	defer view.Close()
+	seg, ok := view.TraceResultsSegment(blockNum)
+	if !ok {
+		return nil, false, nil
+	}
+	index := seg.Index()
+	if index == nil {
+		return nil, false, nil
+	}
+	gg := seg.MakeGetter()
+	gg.Reset(index.OrdinalLookup(blockNum - index.BaseDataID()))
+	if !gg.HasNext() {
+		return nil, false, nil
+	}
+	v, _ := gg.Next(nil)
+	return common.Copy(v), true, nil
+}
+
+func (br *BlockRetire) traceSnapshots() *TraceRoSnapshots {
+	if sn := br.blockReader.TraceSnapshots(); sn != nil {
+		return sn.(*TraceRoSnapshots)
+	}
+	return nil
+}
+
+// retireTraceResults - moves trace results of the TraceResults stage to files and merges them. Trace results
+// are produced after blocks execution, so they're retired only up to the progress of that stage.
+func (br *BlockRetire) retireTraceResults(ctx context.Context, minBlockNum uint64, maxBlockNum uint64, lvl log.Lvl, seedNewSnapshots func(downloadRequest []services.DownloadRequest) error, onDelete func(l []string) error) (bool, error) {
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	default:
+	}
+
+	snapshots := br.traceSnapshots()
+	if snapshots == nil {
+		return false, nil
+	}
+	notifier, logger, tmpDir, db, workers := br.notifier, br.logger, br.tmpDir, br.db, br.workers
+
+	var traceProgress uint64
+	if err := db.View(ctx, func(tx kv.Tx) (err error) {
+		traceProgress, err = stages.GetStageProgress(tx, stages.TraceResults)
+		return err
+	}); err != nil {
+		return false, err
+	}
+	maxBlockNum = min(maxBlockNum, traceProgress)
+
+	blocksRetired := false
+	blockFrom, blockTo, ok := CanRetire(maxBlockNum, minBlockNum, coresnaptype.TraceResults.Enum(), br.chainConfig)
+	if ok {
+		blocksRetired = true
+		logger.Log(lvl, "[snapshots] Retire Trace Results", "range", fmt.Sprintf("%dk-%dk", blockFrom/1000, blockTo/1000))
+		for i := blockFrom; i < blockTo; i = chooseSegmentEnd(i, blockTo, coresnaptype.TraceResults.Enum(), br.chainConfig) {
+			end := chooseSegmentEnd(i, blockTo, coresnaptype.TraceResults.Enum(), br.chainConfig)
+			if _, err := coresnaptype.TraceResults.ExtractRange(ctx, coresnaptype.TraceResults.FileInfo(snapshots.Dir(), i, end), nil, db, br.chainConfig, tmpDir, workers, lvl, logger); err != nil {
+				return ok, fmt.Errorf("ExtractRange: %d-%d: %w", i, end, err)
+			}
+		}
+		if err := snapshots.ReopenFolder(); err != nil {
+			return blocksRetired, fmt.Errorf("reopen: %w", err)
+		}
+		snapshots.LogStat("traces:retire")
+		if notifier != nil && !reflect.ValueOf(notifier).IsNil() { // notify about new snapshots of any size
+			notifier.OnNewSnapshot()
+		}
+	}
+
+	merger := NewMerger(tmpDir, workers, lvl, db, br.chainConfig, logger)
+	rangesToMerge := merger.FindMergeRanges(snapshots.Ranges(), snapshots.BlocksAvailable())
+	if len(rangesToMerge) == 0 {
+		return blocksRetired, nil
+	}
+	blocksRetired = true // have something to merge
+	onMerge := func(r Range) error {
+		if notifier != nil && !reflect.ValueOf(notifier).IsNil() { // notify about new snapshots of any size
+			notifier.OnNewSnapshot()
+		}
+
+		if seedNewSnapshots != nil {
+			downloadRequest := []services.DownloadRequest{
+				services.NewDownloadRequest("", ""),
+			}
+			if err := seedNewSnapshots(downloadRequest); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := merger.Merge(ctx, &snapshots.RoSnapshots, snapshots.Types(), rangesToMerge, snapshots.Dir(), true /* doIndex */, onMerge, onDelete); err != nil {
+		return blocksRetired, err
+	}
+	if err := snapshots.removeOverlapsAfterMerge(); err != nil {
+		return blocksRetired, err
+	}
+	return blocksRetired, nil
+}
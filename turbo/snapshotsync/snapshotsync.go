@@ -44,6 +44,7 @@ import (
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	coresnaptype "github.com/ledgerwatch/erigon/core/snaptype"
 	snaptype2 "github.com/ledgerwatch/erigon/core/snaptype"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
 	"github.com/ledgerwatch/erigon/ethdb/prune"
 	"github.com/ledgerwatch/erigon/turbo/services"
 )
@@ -272,6 +273,54 @@ func computeBlocksToPrune(blockReader services.FullBlockReader, p prune.Mode) (b
 	return blocksToPrune, historyToPrune
 }
 
+// getMaxBlockInPreverified - end of the last headers segment of the preverified list: the amount of blocks
+// the node will have in snapshots once they are downloaded, also on a fresh node.
+func getMaxBlockInPreverified(preverified snapcfg.Preverified) uint64 {
+	maxTo := uint64(0)
+	for _, p := range preverified {
+		if !strings.Contains(p.Name, "headers") {
+			continue
+		}
+		if f, _, ok := snaptype.ParseFileName("", p.Name); ok && f.To > maxTo {
+			maxTo = f.To
+		}
+	}
+	return maxTo
+}
+
+// historyWindowPrunedTo - transactions files of blocks below returned number are out of `--snap.history.window`.
+// The cutoff is computed from the preverified list, as local snapshots may not be there yet. If the preverified
+// list has no state files, execution starts from genesis and needs all transactions: nothing is pruned.
+// Execution starts from the block of the first txNum not covered by the state files, so the cutoff is capped
+// at this block; blockOfTxNum returns ok=false if it's unknown yet, then nothing is pruned either.
+func historyWindowPrunedTo(cfg ethconfig.BlocksFreezing, preverified snapcfg.Preverified, blockOfTxNum func(txNum uint64) (uint64, bool, error)) (uint64, error) {
+	maxStep, err := getMaxStepRangeInSnapshots(preverified)
+	if err != nil {
+		return 0, err
+	}
+	if maxStep == 0 {
+		return 0, nil
+	}
+	stateBlock, ok, err := blockOfTxNum(maxStep * config3.HistoryV3AggregationStep)
+	if err != nil || !ok {
+		return 0, err
+	}
+	return min(cfg.HistoryPrunedTo(getMaxBlockInPreverified(preverified)), stateBlock), nil
+}
+
+// frozenBlockOfTxNum - block of txNum by bodies snapshots, which are there after the header-chain download
+func frozenBlockOfTxNum(blockReader services.FullBlockReader) func(txNum uint64) (uint64, bool, error) {
+	return func(txNum uint64) (blockNum uint64, ok bool, err error) {
+		err = blockReader.IterateFrozenBodies(func(num, baseTxNum, txAmount uint64) error {
+			if !ok && baseTxNum <= txNum && txNum < baseTxNum+txAmount {
+				blockNum, ok = num, true
+			}
+			return nil
+		})
+		return blockNum, ok, err
+	}
+}
+
 // WaitForDownloader - wait for Downloader service to download all expected snapshots
 // for MVP we sync with Downloader only once, in future will send new snapshots also
 func WaitForDownloader(ctx context.Context, logPrefix string, headerchain, blobs bool, prune prune.Mode, caplin CaplinMode, agg *state.Aggregator, tx kv.RwTx, blockReader services.FullBlockReader, cc *chain.Config, snapshotDownloader proto_downloader.DownloaderClient, stagesIdsList []string) error {
@@ -326,6 +375,15 @@ func WaitForDownloader(ctx context.Context, logPrefix string, headerchain, blobs
 		}
 	}
 
+	var historyPrunedTo uint64
+	if !headerchain && blockReader.FreezingCfg().HistoryWindow > 0 {
+		var err error
+		if historyPrunedTo, err = historyWindowPrunedTo(blockReader.FreezingCfg(), preverifiedBlockSnapshots, frozenBlockOfTxNum(blockReader)); err != nil {
+			return err
+		}
+		log.Info(fmt.Sprintf("[%s] History window: skip transactions files", logPrefix), "below_block", historyPrunedTo)
+	}
+
 	// build all download requests
 	for _, p := range preverifiedBlockSnapshots {
 		if caplin == NoCaplin && (strings.Contains(p.Name, "beaconblocks") || strings.Contains(p.Name, "blobsidecars")) {
@@ -343,6 +401,11 @@ func WaitForDownloader(ctx context.Context, logPrefix string, headerchain, blobs
 		if _, ok := blackListForPruning[p.Name]; ok {
 			continue
 		}
+		if historyPrunedTo > 0 && strings.Contains(p.Name, "transactions") {
+			if f, _, ok := snaptype.ParseFileName("", p.Name); ok && f.To <= historyPrunedTo {
+				continue
+			}
+		}
 
 		downloadRequest = append(downloadRequest, services.NewDownloadRequest(p.Name, p.Hash))
 	}
@@ -21,7 +21,10 @@ import (
 	"testing"
 
 	"github.com/ledgerwatch/erigon-lib/chain/snapcfg"
+	"github.com/ledgerwatch/erigon-lib/config3"
 	"github.com/ledgerwatch/erigon-lib/downloader/snaptype"
+
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
 )
 
 func TestBlackListForPruning(t *testing.T) {
@@ -57,3 +60,51 @@ func TestBlackListForPruning(t *testing.T) {
 	}
 
 }
+
+func TestHistoryWindowPrunedTo(t *testing.T) {
+	preverified := snapcfg.Mainnet
+	maxBlock := getMaxBlockInPreverified(preverified)
+	if maxBlock == 0 {
+		t.Fatal("expected headers in preverified list")
+	}
+	maxStep, err := getMaxStepRangeInSnapshots(preverified)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateBlock := func(blockNum uint64, ok bool) func(txNum uint64) (uint64, bool, error) {
+		return func(txNum uint64) (uint64, bool, error) {
+			if txNum != maxStep*config3.HistoryV3AggregationStep {
+				t.Errorf("expected end of state files, got txNum %d", txNum)
+			}
+			return blockNum, ok, nil
+		}
+	}
+
+	cfg := ethconfig.BlocksFreezing{HistoryWindow: 1_000_000}
+	prunedTo, err := historyWindowPrunedTo(cfg, preverified, stateBlock(maxBlock, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prunedTo != maxBlock-1_000_000 {
+		t.Errorf("expected cutoff %d, got %d", maxBlock-1_000_000, prunedTo)
+	}
+
+	// transactions not covered by state files are needed by execution
+	if prunedTo, err = historyWindowPrunedTo(cfg, preverified, stateBlock(maxBlock-2_000_000, true)); err != nil || prunedTo != maxBlock-2_000_000 {
+		t.Errorf("expected cutoff at the end of state files %d, got %d (err %v)", maxBlock-2_000_000, prunedTo, err)
+	}
+	if prunedTo, err = historyWindowPrunedTo(cfg, preverified, stateBlock(0, false)); err != nil || prunedTo != 0 {
+		t.Errorf("expected no cutoff with unknown end of state files, got %d (err %v)", prunedTo, err)
+	}
+
+	// without state files, execution needs all transactions
+	var noState snapcfg.Preverified
+	for _, p := range preverified {
+		if !strings.HasPrefix(p.Name, "domain") {
+			noState = append(noState, p)
+		}
+	}
+	if prunedTo, err = historyWindowPrunedTo(cfg, noState, stateBlock(maxBlock, true)); err != nil || prunedTo != 0 {
+		t.Errorf("expected no cutoff without state files, got %d (err %v)", prunedTo, err)
+	}
+}
@@ -101,6 +101,14 @@ func DoCall(
 
 	evm := vm.NewEVM(blockCtx, txCtx, state, chainConfig, vm.Config{NoBaseFee: true})
 
+	gp := new(core.GasPool).AddGas(msg.Gas()).AddBlobGas(msg.BlobGas())
+	return ApplyCallMessage(ctx, evm, msg, gp, callTimeout)
+}
+
+// ApplyCallMessage applies msg in evm the way DoCall does: the execution is aborted when ctx is done,
+// the gas and blob gas are taken from gp, and the sender has to be able to pay for them.
+// The nonce, fee cap and blob fee checks are made if msg.CheckNonce() and if evm isn't configured with NoBaseFee.
+func ApplyCallMessage(ctx context.Context, evm *vm.EVM, msg types.Message, gp *core.GasPool, callTimeout time.Duration) (*evmtypes.ExecutionResult, error) {
 	// Wait for the context to be done and cancel the evm. Even if the
 	// EVM has finished, cancelling may be done (repeatedly)
 	go func() {
@@ -108,7 +116,6 @@ func DoCall(
 		evm.Cancel()
 	}()
 
-	gp := new(core.GasPool).AddGas(msg.Gas()).AddBlobGas(msg.BlobGas())
 	result, err := core.ApplyMessage(evm, msg, gp, true /* refunds */, false /* gasBailout */)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,180 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"bytes"
+	"fmt"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+// NewFromWitness builds the trie with the given root out of RLP encoded trie nodes keyed by their hashes,
+// as they come in execution witnesses. Nodes missing in the witness stay hash nodes: the root hash is
+// still correct, but TryGet, TryUpdate and TryDelete of keys under them return MissingNodeError.
+// That applies to the root node as well.
+// Values are leaf payloads as they are stored in trie nodes, i.e. already RLP encoded.
+func NewFromWitness(root libcommon.Hash, nodes map[libcommon.Hash][]byte) (*Trie, error) {
+	t := NewTestRLPTrie(root)
+	if t.root == nil {
+		return t, nil
+	}
+	var err error
+	if t.root, err = expandWitnessNode(t.root, nodes); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func expandWitnessNode(n node, nodes map[libcommon.Hash][]byte) (node, error) {
+	var err error
+	switch n := n.(type) {
+	case hashNode:
+		enc, ok := nodes[libcommon.BytesToHash(n.hash)]
+		if !ok {
+			return n, nil
+		}
+		decoded, err := decodeNode(enc)
+		if err != nil {
+			return nil, fmt.Errorf("trie node %x: %w", n.hash, err)
+		}
+		return expandWitnessNode(decoded, nodes)
+	case *shortNode:
+		if n.Val, err = expandWitnessNode(n.Val, nodes); err != nil {
+			return nil, err
+		}
+	case *fullNode:
+		for i, child := range n.Children {
+			if n.Children[i], err = expandWitnessNode(child, nodes); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// TryGet returns the value for key stored in the trie, or MissingNodeError
+// if the path to the key goes through a node which is not loaded.
+func (t *Trie) TryGet(key []byte) ([]byte, error) {
+	if err := t.checkPath(keybytesToHex(key), false); err != nil {
+		return nil, err
+	}
+	value, _ := t.Get(key)
+	return value, nil
+}
+
+// TryUpdate is Update which returns MissingNodeError instead of
+// panicking if the path to the key goes through a node which is not loaded.
+func (t *Trie) TryUpdate(key, value []byte) error {
+	if err := t.checkPath(keybytesToHex(key), false); err != nil {
+		return err
+	}
+	t.Update(key, value)
+	return nil
+}
+
+// TryDelete is Delete which returns MissingNodeError instead of panicking if the path to the key
+// goes through a node which is not loaded, or if the deletion collapses a branch into its only
+// remaining child which is not loaded (it may be a short node which has to be merged).
+func (t *Trie) TryDelete(key []byte) error {
+	if err := t.checkPath(keybytesToHex(key), true); err != nil {
+		return err
+	}
+	t.Delete(key)
+	return nil
+}
+
+func (t *Trie) checkPath(hex []byte, deletion bool) error {
+	n, pos := t.root, 0
+	for {
+		switch nd := n.(type) {
+		case nil, valueNode:
+			return nil
+		case hashNode:
+			return &MissingNodeError{NodeHash: libcommon.BytesToHash(nd.hash), Path: libcommon.Copy(hex[:pos])}
+		case *shortNode:
+			matchlen := prefixLen(hex[pos:], nd.Key)
+			if matchlen < len(nd.Key) {
+				return nil // key is not in the trie
+			}
+			n, pos = nd.Val, pos+matchlen
+		case *duoNode:
+			i1, i2 := nd.childrenIdx()
+			switch hex[pos] {
+			case i1:
+				n = nd.child1
+				if deletion && isLeafOf(n, hex[pos+1:]) {
+					if err := loadedSibling(nd.child2, hex[:pos], i2); err != nil {
+						return err
+					}
+				}
+			case i2:
+				n = nd.child2
+				if deletion && isLeafOf(n, hex[pos+1:]) {
+					if err := loadedSibling(nd.child1, hex[:pos], i1); err != nil {
+						return err
+					}
+				}
+			default:
+				return nil
+			}
+			pos++
+		case *fullNode:
+			n = nd.Children[hex[pos]]
+			if deletion && isLeafOf(n, hex[pos+1:]) {
+				var sibling node
+				var siblingIdx byte
+				count := 0
+				for i, child := range nd.Children {
+					if child != nil && i != int(hex[pos]) {
+						sibling, siblingIdx = child, byte(i)
+						count++
+					}
+				}
+				if count == 1 {
+					if err := loadedSibling(sibling, hex[:pos], siblingIdx); err != nil {
+						return err
+					}
+				}
+			}
+			pos++
+		case *accountNode:
+			n = nd.storage
+		default:
+			panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+		}
+	}
+}
+
+// isLeafOf tells whether deleting the key with the remaining path hex removes n entirely
+func isLeafOf(n node, hex []byte) bool {
+	switch n := n.(type) {
+	case valueNode:
+		return len(hex) == 1 // only the terminator is left
+	case *shortNode:
+		_, ok := n.Val.(valueNode)
+		return ok && bytes.Equal(n.Key, hex)
+	}
+	return false
+}
+
+func loadedSibling(sibling node, path []byte, idx byte) error {
+	if hn, ok := sibling.(hashNode); ok {
+		return &MissingNodeError{NodeHash: libcommon.BytesToHash(hn.hash), Path: append(libcommon.Copy(path), idx)}
+	}
+	return nil
+}
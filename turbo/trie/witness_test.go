@@ -0,0 +1,147 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+// witnessNodes returns the nodes on the paths to the keys, as the witness would have them
+func witnessNodes(t *testing.T, tr *Trie, keys ...[]byte) map[libcommon.Hash][]byte {
+	nodes := make(map[libcommon.Hash][]byte)
+	for _, key := range keys {
+		proof, err := tr.Prove(key, 0, false)
+		require.NoError(t, err)
+		for _, n := range proof {
+			nodes[crypto.Keccak256Hash(n)] = n
+		}
+	}
+	return nodes
+}
+
+func TestNewFromWitness(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	full := NewTestRLPTrie(libcommon.Hash{})
+
+	keys := make([][]byte, 500)
+	for i := range keys {
+		keys[i] = crypto.Keccak256([]byte{byte(i), byte(i >> 8)})
+		value := make([]byte, 1+rnd.Intn(40))
+		rnd.Read(value)
+		enc, err := rlp.EncodeToBytes(value)
+		require.NoError(t, err)
+		full.Update(keys[i], enc)
+	}
+	root := full.Hash()
+
+	partial, err := NewFromWitness(root, witnessNodes(t, full, keys...))
+	require.NoError(t, err)
+	require.Equal(t, root, partial.Hash())
+	for i, key := range keys {
+		v, err := partial.TryGet(key)
+		require.NoError(t, err)
+		fv, _ := full.Get(key)
+		require.Equal(t, fv, v, "key %d", i)
+	}
+	v, err := partial.TryGet(crypto.Keccak256([]byte("absent")))
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	// deletions collapse branches, updates split leaves
+	for i := 0; i < len(keys); i += 3 {
+		require.NoError(t, partial.TryDelete(keys[i]))
+		full.Delete(keys[i])
+	}
+	for i := 1; i < len(keys); i += 7 {
+		require.NoError(t, partial.TryUpdate(keys[i], []byte{byte(i)}))
+		full.Update(keys[i], []byte{byte(i)})
+	}
+	require.Equal(t, full.Hash(), partial.Hash())
+}
+
+func TestNewFromWitnessMissingNodes(t *testing.T) {
+	full := NewTestRLPTrie(libcommon.Hash{})
+	keys := make([][]byte, 50)
+	for i := range keys {
+		keys[i] = crypto.Keccak256([]byte{byte(i)})
+		full.Update(keys[i], []byte{byte(i + 1)})
+	}
+	root := full.Hash()
+
+	partial, err := NewFromWitness(root, nil)
+	require.NoError(t, err)
+	require.Equal(t, root, partial.Hash())
+	var missing *MissingNodeError
+	_, err = partial.TryGet(keys[0])
+	require.ErrorAs(t, err, &missing)
+	require.Equal(t, root, missing.NodeHash)
+
+	// only the path to the first key is in the witness
+	partial, err = NewFromWitness(root, witnessNodes(t, full, keys[0]))
+	require.NoError(t, err)
+	require.Equal(t, root, partial.Hash())
+
+	v, err := partial.TryGet(keys[0])
+	require.NoError(t, err)
+	fv, _ := full.Get(keys[0])
+	require.Equal(t, fv, v)
+
+	_, err = partial.TryGet(keys[1])
+	require.ErrorAs(t, err, &missing)
+	require.ErrorAs(t, partial.TryUpdate(keys[1], []byte{0x01}), &missing)
+	require.ErrorAs(t, partial.TryDelete(keys[1]), &missing)
+	require.Equal(t, root, partial.Hash())
+}
+
+func TestTryDeleteCollapsesIntoMissingNode(t *testing.T) {
+	full := NewTestRLPTrie(libcommon.Hash{})
+	// two subtries under different first nibbles: a single leaf and a branch,
+	// values are long enough for nodes not to be embedded into their parents
+	value := append([]byte{0x80 + 32}, make([]byte, 32)...)
+	leaf := []byte{0x10, 0x00}
+	full.Update(leaf, value)
+	full.Update([]byte{0x20, 0x00}, value)
+	full.Update([]byte{0x21, 0x00}, value)
+	root := full.Hash()
+
+	nodes := witnessNodes(t, full, leaf)
+	partial, err := NewFromWitness(root, nodes)
+	require.NoError(t, err)
+
+	// the remaining sibling would have to be merged with the branch nibble, but it is not in the witness
+	var missing *MissingNodeError
+	require.ErrorAs(t, partial.TryDelete(leaf), &missing)
+	require.Equal(t, []byte{0x2}, missing.Path)
+
+	// with the sibling the deletion goes through
+	for h, n := range witnessNodes(t, full, []byte{0x20, 0x00}) {
+		nodes[h] = n
+	}
+	partial, err = NewFromWitness(root, nodes)
+	require.NoError(t, err)
+	require.NoError(t, partial.TryDelete(leaf))
+	full.Delete(leaf)
+	require.Equal(t, full.Hash(), partial.Hash())
+}